@@ -2,98 +2,746 @@ package main
 
 import (
 	"context"
-	"log"
-	"net/http"
+	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/shubh-37/linkedin-ghostwriter/config"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/api"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/backlog"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/cache"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/checkin"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/dedup"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/digest"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/email"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/experiments"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/gcal"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/images"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/importer"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/janitor"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/journal"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/leader"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/linear"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/linkedin"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/logging"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/notion"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/outbound"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/prompts"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/queue"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/recategorize"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/retry"
 	slackpkg "github.com/shubh-37/linkedin-ghostwriter/internal/slack"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/sources"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/telegram"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/telemetry"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/twitter"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/webhook"
 )
 
-func main() {
-	log.Println("Starting LinkedIn Ghostwriter Bot")
+// cacheEntries caps the in-process query cache.
+const cacheEntries = 10000
 
+func main() {
 	cfg := config.LoadConfig()
+	if err := logging.Setup(cfg.LogFormat, cfg.LogLevel); err != nil {
+		fatal("Configuration error", err)
+	}
+	slog.Info("Starting LinkedIn Ghostwriter Bot")
+
 	if err := cfg.Validate(); err != nil {
-		log.Fatalf("Configuration error: %v", err)
+		fatal("Configuration error", err)
 	}
 
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shutdownTracing, err := telemetry.Setup(ctx, cfg.ServiceName, cfg.OTLPEndpoint)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+	if cfg.OTLPEndpoint != "" {
+		slog.Info("Tracing enabled", "endpoint", cfg.OTLPEndpoint, "service", cfg.ServiceName)
+	}
 
 	db, err := database.NewDB(cfg.DatabaseURL)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("Failed to connect to database", err)
+	}
+
+	if err := db.Migrate(ctx); err != nil {
+		fatal("Failed to migrate database", err)
 	}
-	defer db.Close()
 
-	if err := db.CreateTables(ctx); err != nil {
-		log.Fatalf("Failed to create tables: %v", err)
+	if cfg.CacheTTL > 0 {
+		var store cache.Store = cache.NewMemory(cacheEntries)
+		if cfg.RedisURL != "" {
+			redisStore, err := cache.NewRedis(cfg.RedisURL)
+			if err != nil {
+				fatal("Failed to connect to Redis", err)
+			}
+			defer redisStore.Close()
+			store = redisStore
+		}
+		db.Cache = cache.New(store, cfg.CacheTTL)
+		slog.Info("Query cache enabled", "ttl", cfg.CacheTTL, "shared", cfg.RedisURL != "")
 	}
 
 	thoughtRepo := database.NewThoughtRepository(db)
 	postRepo := database.NewPostRepository(db)
 	brainstormRepo := database.NewBrainstormRepository(db)
+	linkedinTokenRepo := database.NewLinkedInTokenRepository(db)
+	draftMessageRepo := database.NewDraftMessageRepository(db)
+	styleRepo := database.NewStyleRepository(db)
+	workspaceRepo := database.NewWorkspaceRepository(db)
+	revisionRepo := database.NewRevisionRepository(db)
+	eventRepo := database.NewEventRepository(db)
+	categoryRepo := database.NewCategoryRepository(db)
+	importJobRepo := database.NewImportJobRepository(db)
+	auditRepo := database.NewAuditRepository(db)
 
-	categorizer := agents.NewCategorizerAgent(cfg.AnthropicKey)
-	contentGenerator := agents.NewContentGeneratorAgent(cfg.AnthropicKey)
-	scheduler := agents.NewSchedulerAgent(postRepo)
+	// Every client of a provider shares its request budget and circuit
+	// breaker.
+	guards := outbound.NewGuards(cfg.RateLimits, cfg.BreakerFailures, cfg.BreakerCooldown)
 
-	slackClient := slackpkg.NewClient(cfg.SlackToken)
+	providerConfig := agents.ProviderConfig{
+		Provider:      cfg.LLMProvider,
+		Model:         cfg.LLMModel,
+		AnthropicKey:  cfg.AnthropicKey,
+		OpenAIKey:     cfg.OpenAIKey,
+		OpenAIBaseURL: cfg.OpenAIBaseURL,
+		OllamaURL:     cfg.OllamaURL,
+		Temperature:   cfg.LLMTemperature,
+		Guards:        guards,
+	}
 
-	approvalHandler := slackpkg.NewApprovalHandler(slackClient, postRepo)
+	generationModel, err := agents.NewSwitchableProvider(providerConfig)
+	if err != nil {
+		fatal("Failed to configure LLM provider", err)
+	}
+
+	providerConfig.Provider = cfg.CategorizerProvider
+	providerConfig.Model = cfg.CategorizerModel
+	providerConfig.Temperature = cfg.CategorizerTemp
+
+	categorizerModel, err := agents.NewSwitchableProvider(providerConfig)
+	if err != nil {
+		fatal("Failed to configure categorizer LLM provider", err)
+	}
 
+	// Models switched from Slack with `model` stay switched across restarts.
+	modelRegistry := agents.NewModelRegistry(database.NewSettingsRepository(db))
+	modelRegistry.Register(ctx, agents.RoleGeneration, generationModel)
+	modelRegistry.Register(ctx, agents.RoleCategorizer, categorizerModel)
+
+	slackClient := slackpkg.NewClient(cfg.SlackToken, guards.For("slack"))
+
+	// Every call is recorded in llm_usage; only generation stops when the
+	// monthly budget is spent, so thoughts are still captured.
+	usageRepo := database.NewUsageRepository(db)
+	usageTracker := agents.NewUsageTracker(usageRepo, database.NewSettingsRepository(db), cfg.LLMMonthlyBudget, cfg.LLMBudgetWarn, slackClient, cfg.SlackNotifyChannel)
+
+	retryConfig := agents.RetryConfig{MaxAttempts: cfg.LLMMaxAttempts}
+	generationLLM := usageTracker.Track(agents.WithTracing(agents.WithRetry(agents.WithTimeout(generationModel, cfg.LLMTimeout), retryConfig)), agents.RoleGeneration, true)
+	categorizerLLM := usageTracker.Track(agents.WithTracing(agents.WithRetry(agents.WithTimeout(categorizerModel, cfg.CategorizerTimeout), retryConfig)), agents.RoleCategorizer, false)
+
+	slog.Info("LLM providers configured",
+		"generation_provider", generationLLM.Name(),
+		"generation_model", generationLLM.Model(),
+		"categorizer_provider", categorizerLLM.Name(),
+		"categorizer_model", categorizerLLM.Model(),
+	)
+
+	embedder, err := agents.NewEmbedder(agents.ProviderConfig{
+		Provider:      cfg.EmbeddingProvider,
+		Model:         cfg.EmbeddingModel,
+		OpenAIKey:     cfg.OpenAIKey,
+		OpenAIBaseURL: cfg.OpenAIBaseURL,
+		OllamaURL:     cfg.OllamaURL,
+		Guards:        guards,
+	})
+	if err != nil {
+		fatal("Failed to configure embedding provider", err)
+	}
+
+	var embeddingAgent *agents.EmbeddingAgent
+	if embedder != nil {
+		if err := db.EnableEmbeddings(ctx); err != nil {
+			fatal("Failed to enable embeddings (is pgvector installed?)", err)
+		}
+		embeddingAgent = agents.NewEmbeddingAgent(embedder, thoughtRepo)
+		slog.Info("Embeddings configured", "provider", embedder.Name(), "model", embedder.Model())
+	}
+
+	promptStore, err := prompts.NewStore(database.NewPromptRepository(db), cfg.PromptsDir)
+	if err != nil {
+		fatal("Failed to load prompt templates", err)
+	}
+
+	categorizer := agents.NewCategorizerAgent(categorizerLLM, cfg.CategorizerTokens, promptStore, categoryRepo, cfg.CategorizerBatch, cfg.CategorizerBatchMax)
+	contentGenerator := agents.NewContentGeneratorAgent(generationLLM, cfg.LLMMaxTokens, promptStore)
+	styleAnalyzer := agents.NewStyleAnalyzerAgent(generationLLM)
+	jobQueue := queue.NewQueue(database.NewJobRepository(db))
+	thoughtImporter := importer.NewImporter(thoughtRepo, importJobRepo, categoryRepo, categorizer, embeddingAgent, jobQueue, cfg.ImportRateLimit)
+	recategorizer := recategorize.NewRecategorizer(thoughtRepo, categorizer, cfg.ImportRateLimit)
+	var calendar *gcal.Client
+	if cfg.GoogleRefreshToken != "" {
+		calendar = gcal.NewClient(cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.GoogleRefreshToken, cfg.GoogleCalendarID, cfg.CalendarAway, cfg.CalendarTalks)
+		slog.Info("Google Calendar scheduling enabled", "calendar_id", cfg.GoogleCalendarID)
+	}
+	blackoutRepo := database.NewBlackoutRepository(db)
+	scheduler := agents.NewSchedulerAgent(postRepo, blackoutRepo, cfg.PostingDays, calendar)
+
+	// Drafts offer a Targets menu once there is somewhere besides LinkedIn
+	// to publish to.
+	var crossPoster linkedin.CrossPoster
+	publishTargets := []string{models.TargetLinkedIn}
+	if cfg.LinkedInOrgURN != "" && (cfg.LinkedInOrgToken != "" || cfg.LinkedInClientID != "") {
+		publishTargets = append(publishTargets, models.TargetCompany)
+		slog.Info("Company page publishing enabled", "organization_urn", cfg.LinkedInOrgURN)
+	}
+	if cfg.XAPIKey != "" {
+		crossPoster = twitter.NewClient(cfg.XAPIKey, cfg.XAPISecret, cfg.XAccessToken, cfg.XAccessSecret)
+		publishTargets = append(publishTargets, models.TargetX)
+		slog.Info("X cross-posting enabled")
+	}
+
+	approvalRepo := database.NewApprovalRepository(db)
+	if cfg.ReviewerSlackID != "" {
+		slog.Info("Review mode enabled", "reviewer", cfg.ReviewerSlackID)
+	}
+	contactRepo := database.NewContactRepository(db)
+	checkInRepo := database.NewCheckInRepository(db)
+	failedJobRepo := database.NewFailedJobRepository(db)
+	checkInTime, err := checkin.ParseTime(cfg.CheckInTime)
+	if err != nil {
+		fatal("Configuration error: invalid CHECKIN_TIME", err)
+	}
+	postLanguage, err := models.ParseLanguage(cfg.PostLanguage)
+	if err != nil {
+		fatal("Configuration error: invalid POST_LANGUAGE", err)
+	}
+	emojiPolicy, err := models.ParseEmojiPolicy(cfg.EmojiPolicy)
+	if err != nil {
+		fatal("Configuration error: invalid EMOJI_POLICY", err)
+	}
+	postLength, err := models.ParsePostLength(cfg.PostLength)
+	if err != nil {
+		fatal("Configuration error: invalid POST_LENGTH", err)
+	}
+
+	// Owners from the config are (re)granted on every start, so they can't
+	// lock themselves out from Slack.
+	userRepo := database.NewUserRepository(db)
+	for _, ownerID := range cfg.OwnerSlackIDs {
+		if err := userRepo.Save(ctx, models.NewUser(ownerID, models.RoleOwner, "")); err != nil {
+			fatal("Failed to save owner", err)
+		}
+	}
+	if len(cfg.OwnerSlackIDs) > 0 {
+		slog.Info("Roles enabled", "owners", len(cfg.OwnerSlackIDs))
+	}
+	roles := slackpkg.NewRoles(slackClient, userRepo, cfg.ReviewerSlackID)
+
+	var policy *agents.PolicyAgent
+	if len(cfg.BannedTerms)+len(cfg.CustomerNames)+len(cfg.Codenames) > 0 {
+		policy = agents.NewPolicyAgent(cfg.BannedTerms, cfg.CustomerNames, cfg.Codenames)
+		slog.Info("Confidential content guard enabled", "banned_terms", len(cfg.BannedTerms), "customers", len(cfg.CustomerNames), "codenames", len(cfg.Codenames))
+	}
+
+	approvalHandler := slackpkg.NewApprovalHandler(slackClient, postRepo, thoughtRepo, draftMessageRepo, revisionRepo, approvalRepo, contactRepo, auditRepo, publishTargets, cfg.ReviewerSlackID, roles, policy)
+
+	processedEvents := dedup.NewPersistent(dedup.NewCache(cfg.DedupCacheSize, cfg.DedupTTL), eventRepo, cfg.DedupTTL)
+
+	var linkedinAuth *linkedin.OAuthHandler
+	if cfg.LinkedInClientID != "" {
+		linkedinAuth = linkedin.NewOAuthHandler(
+			cfg.LinkedInClientID,
+			cfg.LinkedInSecret,
+			cfg.LinkedInRedirectURL,
+			cfg.LinkedInOrgURN,
+			cfg.LinkedInAuthorID,
+			linkedinTokenRepo,
+			eventRepo,
+		)
+	}
+
+	var linkedinTokens linkedin.TokenSource
+	if cfg.LinkedInAccessToken != "" {
+		linkedinTokens = linkedin.NewStaticTokenSource(cfg.LinkedInAccessToken, cfg.LinkedInAuthorURN)
+	} else if linkedinAuth != nil {
+		linkedinTokens = linkedinAuth
+	}
+
+	var companyTokens linkedin.TokenSource
+	if cfg.LinkedInOrgToken != "" {
+		companyTokens = linkedin.NewStaticTokenSource(cfg.LinkedInOrgToken, cfg.LinkedInOrgURN)
+	} else if linkedinAuth != nil && linkedinAuth.HasOrganization() {
+		companyTokens = linkedinAuth.Organization()
+	}
+
+	var linkedinClient *linkedin.Client
+	if linkedinTokens != nil {
+		linkedinClient = linkedin.NewClient(linkedinTokens, guards.For("linkedin"))
+	}
+	commentRepo := database.NewCommentRepository(db)
+
+	var linearSyncer *linear.Syncer
+	var linearWebhookHandler *linear.WebhookHandler
+	var backlinker linkedin.Backlinker
+	if cfg.LinearToken != "" {
+		linearClient := linear.NewClient(cfg.LinearToken, guards.For("linear"))
+		linearFilter := linear.Filter{
+			Teams:       cfg.LinearTeams,
+			Projects:    cfg.LinearProjects,
+			Labels:      cfg.LinearLabels,
+			MinEstimate: float64(cfg.LinearMinEstimate),
+		}
+		linearSyncer = linear.NewSyncer(linearClient, thoughtRepo, categorizer, embeddingAgent, database.NewSettingsRepository(db), linearFilter)
+		linearSyncer.LoadFilter(ctx)
+
+		backlinkMode, err := linear.ParseBacklinkMode(cfg.LinearBacklinks)
+		if err != nil {
+			fatal("Configuration error: invalid LINEAR_BACKLINKS", err)
+		}
+		if backlinkMode != linear.BacklinkOff {
+			backlinker = linear.NewBacklinker(linearClient, thoughtRepo, backlinkMode)
+			slog.Info("Linear back-links enabled", "mode", backlinkMode)
+		}
+
+		if cfg.LinearWebhookSecret != "" {
+			linearWebhookHandler = linear.NewWebhookHandler(
+				linearSyncer,
+				processedEvents,
+				cfg.LinearWebhookSecret,
+				cfg.LinearMilestones != "off",
+				cfg.LinearIdeaLabel,
+			)
+			slog.Info("Linear webhook handler initialized")
+		} else {
+			slog.Info("LINEAR_WEBHOOK_SECRET not configured, Linear webhook endpoint disabled")
+		}
+	} else {
+		slog.Info("Linear API key not configured, add LINEAR_API_KEY to .env to enable Linear integration")
+	}
+
+	var notionSyncer *notion.Syncer
+	var journalSinks []journal.Sink
+	if cfg.NotionToken != "" {
+		notionClient := notion.NewClient(cfg.NotionToken)
+		if cfg.JournalNotionDBID != "" {
+			journalSinks = append(journalSinks, notion.NewDailyNotes(notionClient, cfg.JournalNotionDBID))
+		}
+		notionSyncer = notion.NewSyncer(
+			notionClient,
+			cfg.NotionDatabaseID,
+			cfg.NotionIdeaStatus,
+			thoughtRepo,
+			postRepo,
+			database.NewNotionPageRepository(db),
+			categorizer,
+			embeddingAgent,
+			cfg.NotionSyncInterval,
+		)
+		slog.Info("Notion sync initialized", "database_id", cfg.NotionDatabaseID)
+	} else {
+		slog.Info("Notion not configured, add NOTION_TOKEN to .env to enable Notion sync")
+	}
+
+	carouselRenderer, err := images.NewCarouselRenderer(cfg.CarouselDir)
+	if err != nil {
+		fatal("Failed to set up carousel rendering", err)
+	}
+
+	var factChecker *agents.FactCheckerAgent
+	if cfg.FactCheck {
+		factChecker = agents.NewFactCheckerAgent(generationLLM)
+		slog.Info("Fact check enabled for generated drafts")
+	}
+
+	experimentRepo := database.NewExperimentRepository(db)
 	commandHandler := slackpkg.NewCommandHandler(
 		slackClient,
 		thoughtRepo,
 		postRepo,
 		brainstormRepo,
+		styleRepo,
+		workspaceRepo,
+		revisionRepo,
 		contentGenerator,
+		styleAnalyzer,
 		scheduler,
+		linkedinAuth,
+		linearSyncer,
+		embeddingAgent,
+		carouselRenderer,
+		notionSyncer,
+		publishTargets,
+		modelRegistry,
+		promptStore,
+		categoryRepo,
+		thoughtImporter,
+		agents.NewLinterAgent(generationLLM, cfg.LLMMaxTokens),
+		factChecker,
+		experimentRepo,
+		contactRepo,
+		recategorizer,
+		checkInRepo,
+		checkInTime,
+		auditRepo,
+		roles,
+		database.NewSettingsRepository(db),
+		agents.ClampVariations(cfg.Variations),
+		postLanguage,
+		emojiPolicy,
+		postLength,
+		blackoutRepo,
+		linkedinClient,
+		commentRepo,
+		database.NewTemplateRepository(db),
+		usageRepo,
+		usageTracker,
 	)
 
+	var summarizer *agents.SummarizerAgent
+	if cfg.SummarizeSources {
+		summarizer = agents.NewSummarizerAgent(categorizerLLM)
+	}
+
 	messageHandler := slackpkg.NewMessageHandler(
 		slackClient,
 		thoughtRepo,
 		categorizer,
 		commandHandler,
 		approvalHandler,
+		embeddingAgent,
+		summarizer,
+		sources.NewFetcher(),
+		checkInRepo,
+		failedJobRepo,
+		jobQueue,
+		roles,
 	)
 
-	var linearWebhookHandler *linear.WebhookHandler
-	if cfg.LinearToken != "" {
-		linearClient := linear.NewClient(cfg.LinearToken)
-		linearWebhookHandler = linear.NewWebhookHandler(
-			linearClient,
-			thoughtRepo,
-			categorizer,
-		)
-		log.Println("Linear webhook handler initialized")
+	var workers sync.WaitGroup
+
+	// Scheduled work runs on one replica at a time, the one holding the
+	// leader lock, so nothing is published or sent twice. Work driven by
+	// requests or claimed from the database runs on every replica.
+	elector := leader.NewElector(db.Pool)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		elector.Start(ctx)
+	}()
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		processedEvents.Start(ctx)
+	}()
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		recategorizer.Start(ctx)
+	}()
+
+	if embeddingAgent != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "embedding backfill", func(ctx context.Context) {
+				count, err := embeddingAgent.Backfill(ctx, 50)
+				if err != nil && ctx.Err() == nil {
+					slog.ErrorContext(ctx, "Embedding backfill stopped", "embedded", count, "error", err)
+				} else if count > 0 {
+					slog.InfoContext(ctx, "Embedded existing thoughts", "count", count)
+				}
+			})
+		}()
+	}
+
+	imageGenerator, err := images.NewGenerator(cfg.ImageProvider, cfg.OpenAIKey, cfg.OpenAIBaseURL, cfg.ImageModel)
+	if err != nil {
+		fatal("Failed to configure image provider", err)
+	}
+	if notionSyncer != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "notion sync", notionSyncer.Start)
+		}()
+	}
+
+	if cfg.JournalVaultPath != "" {
+		vault, err := journal.NewVault(cfg.JournalVaultPath)
+		if err != nil {
+			fatal("Configuration error: invalid JOURNAL_VAULT_PATH", err)
+		}
+		journalSinks = append(journalSinks, vault)
+	}
+	if len(journalSinks) > 0 {
+		location, err := time.LoadLocation(cfg.DigestTimezone)
+		if err != nil {
+			fatal("Configuration error: invalid DIGEST_TIMEZONE", err)
+		}
+		exporter := journal.NewExporter(thoughtRepo, database.NewSettingsRepository(db), journalSinks, location, cfg.JournalInterval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "journal export", exporter.Start)
+		}()
+		slog.Info("Daily note export enabled", "sinks", len(journalSinks))
+	}
+
+	if imageGenerator != nil {
+		imagePipeline, err := images.NewPipeline(agents.NewImageAgent(generationLLM), imageGenerator, postRepo, cfg.ImageDir, time.Minute)
+		if err != nil {
+			fatal("Failed to set up image pipeline", err)
+		}
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "image pipeline", imagePipeline.Start)
+		}()
+		slog.Info("Image pipeline enabled", "provider", imageGenerator.Name(), "dir", cfg.ImageDir)
+	}
+
+	if linkedinClient != nil {
+		// Held posts are asked about in the notify channel, so without one
+		// posts are published unchecked.
+		var duplicateGuard linkedin.DuplicateChecker
+		if cfg.DuplicateThreshold > 0 && cfg.SlackNotifyChannel != "" {
+			duplicateGuard = agents.NewDuplicateGuard(embedder, postRepo, cfg.DuplicateThreshold)
+			slog.Info("Duplicate guard enabled", "threshold", cfg.DuplicateThreshold, "by_meaning", embedder != nil)
+		}
+		var companyClient *linkedin.Client
+		if companyTokens != nil {
+			companyClient = linkedin.NewClient(companyTokens, guards.For("linkedin"))
+		}
+		publishLocation, err := time.LoadLocation(cfg.DigestTimezone)
+		if err != nil {
+			fatal("Configuration error: invalid DIGEST_TIMEZONE", err)
+		}
+		publisher := linkedin.NewPublisher(linkedinClient, companyClient, crossPoster, backlinker, duplicateGuard, commandHandler, policy, commandHandler, cfg.ConfirmBefore, postRepo, contactRepo, auditRepo, blackoutRepo, jobQueue, slackClient, cfg.SlackNotifyChannel, publishLocation, time.Minute)
+		jobQueue.Register(models.JobPublish, publisher.RunJob, 1, 1)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "publisher", publisher.Start)
+		}()
+
+		metricsSyncer := linkedin.NewMetricsSyncer(linkedinClient, postRepo, commentRepo, cfg.MetricsSyncInterval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "metrics sync", metricsSyncer.Start)
+		}()
+
+		// Experiments are decided on synced metrics, so they are only
+		// tracked when posts are published.
+		experimentTracker := experiments.NewTracker(experimentRepo, slackClient, cfg.SlackNotifyChannel, time.Hour)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "experiment tracker", experimentTracker.Start)
+		}()
+		slog.Info("LinkedIn publisher initialized")
 	} else {
-		log.Println("Linear API key not configured")
-		log.Println("Add LINEAR_API_KEY to .env to enable Linear integration")
+		slog.Info("LinkedIn not configured, add LINKEDIN_CLIENT_ID or LINKEDIN_ACCESS_TOKEN to .env to enable auto-publishing")
+	}
+
+	if cfg.SlackNotifyChannel != "" && cfg.DigestSchedule != "off" {
+		schedule, err := digest.ParseSchedule(cfg.DigestSchedule)
+		if err != nil {
+			fatal("Configuration error", err)
+		}
+		location, err := time.LoadLocation(cfg.DigestTimezone)
+		if err != nil {
+			fatal("Configuration error: invalid DIGEST_TIMEZONE", err)
+		}
+
+		digester := digest.NewDigester(thoughtRepo, postRepo, scheduler, slackClient, cfg.SlackNotifyChannel, schedule, location, cfg.DigestNudgeDays)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "digest", digester.Start)
+		}()
+	}
+
+	if cfg.SlackNotifyChannel != "" && cfg.BacklogSchedule != "off" {
+		schedule, err := digest.ParseSchedule(cfg.BacklogSchedule)
+		if err != nil {
+			fatal("Configuration error: invalid BACKLOG_REVIEW_SCHEDULE", err)
+		}
+		location, err := time.LoadLocation(cfg.DigestTimezone)
+		if err != nil {
+			fatal("Configuration error: invalid DIGEST_TIMEZONE", err)
+		}
+
+		reviewer := backlog.NewReviewer(thoughtRepo, postRepo, categoryRepo, agents.NewBacklogAnalystAgent(categorizerLLM), slackClient, cfg.SlackNotifyChannel, schedule, location, cfg.BacklogGapDays, cfg.BacklogStaleDays)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "backlog review", reviewer.Start)
+		}()
 	}
 
+	checkInLocation, err := time.LoadLocation(cfg.DigestTimezone)
+	if err != nil {
+		fatal("Configuration error: invalid DIGEST_TIMEZONE", err)
+	}
+	reminder := checkin.NewReminder(checkInRepo, commandHandler, checkInLocation)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		elector.Run(ctx, "check-in reminder", reminder.Start)
+	}()
+
+	// Slow work runs from the jobs table, so any replica can take it and it
+	// outlasts a restart. Kinds are registered before the queue starts.
+	jobQueue.Register(models.JobGenerate, messageHandler.RunGenerateJob, cfg.JobWorkers, 2)
+	jobQueue.Register(models.JobBrainstorm, messageHandler.RunBrainstormJob, cfg.JobWorkers, 2)
+	jobQueue.Register(models.JobImport, thoughtImporter.Handler(commandHandler.ReportImport), 1, 3)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		jobQueue.Start(ctx)
+	}()
+
+	retrier := retry.NewWorker(failedJobRepo, messageHandler, slackClient, cfg.RetryMaxAttempts)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		retrier.Start(ctx)
+	}()
+
+	if linearSyncer != nil && cfg.LinearMilestones != "off" {
+		schedule, err := digest.ParseSchedule(cfg.LinearMilestones)
+		if err != nil {
+			fatal("Configuration error: invalid LINEAR_MILESTONE_SCHEDULE", err)
+		}
+		location, err := time.LoadLocation(cfg.DigestTimezone)
+		if err != nil {
+			fatal("Configuration error: invalid DIGEST_TIMEZONE", err)
+		}
+
+		milestones := linear.NewMilestoneJob(linearSyncer, agents.NewSummarizerAgent(categorizerLLM), schedule, location)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "linear milestones", milestones.Start)
+		}()
+	}
+
+	if cfg.JanitorSchedule != "off" {
+		schedule, err := digest.ParseSchedule(cfg.JanitorSchedule)
+		if err != nil {
+			fatal("Configuration error: invalid JANITOR_SCHEDULE", err)
+		}
+		location, err := time.LoadLocation(cfg.DigestTimezone)
+		if err != nil {
+			fatal("Configuration error: invalid DIGEST_TIMEZONE", err)
+		}
+
+		cleaner := janitor.NewJanitor(thoughtRepo, postRepo, database.NewArchiveRepository(db), approvalHandler, cfg.SlackNotifyChannel, schedule, location, cfg.JanitorStaleDays, cfg.JanitorArchiveDays, cfg.JanitorTrashDays)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			elector.Run(ctx, "janitor", cleaner.Start)
+		}()
+	}
+
+	dispatcher := slackpkg.NewDispatcher(cfg.EventWorkers, cfg.EventQueueSize, cfg.EventTimeout)
+	dispatcher.Start()
+
+	slackServer := slackpkg.NewServer(slackClient, messageHandler, approvalHandler, commandHandler, dispatcher, cfg.SlackSigningSecret, "3000")
+
+	// Every integration's webhook is verified, size-limited, deduplicated
+	// and retried the same way.
+	webhooks := webhook.NewRouter(slackServer, processedEvents, database.NewDeadLetterRepository(db))
+	webhooks.Register(slackServer.Webhooks()...)
+
 	if linearWebhookHandler != nil {
-		http.HandleFunc("/linear/webhook", linearWebhookHandler.HandleWebhook)
-		log.Println("Linear webhook endpoint: http://localhost:3000/linear/webhook")
+		webhooks.Register(linearWebhookHandler.Webhook())
+		slog.Info("Linear webhook endpoint enabled", "url", "http://localhost:3000/linear/webhook")
+	}
+
+	if cfg.EmailMailgunKey != "" || cfg.EmailSESTopicARN != "" {
+		emailCapturer := email.NewCapturer(thoughtRepo, categorizer, agents.NewSummarizerAgent(categorizerLLM), embeddingAgent, cfg.EmailAllowedSenders)
+		if cfg.EmailMailgunKey != "" {
+			webhooks.Register(emailCapturer.Mailgun(cfg.EmailMailgunKey))
+			slog.Info("Mailgun email capture enabled", "url", "http://localhost:3000/email/mailgun")
+		}
+		if cfg.EmailSESTopicARN != "" {
+			webhooks.Register(emailCapturer.SES(cfg.EmailSESTopicARN))
+			slog.Info("SES email capture enabled", "url", "http://localhost:3000/email/ses")
+		}
+	}
+
+	if cfg.TelegramToken != "" {
+		telegramBot := telegram.NewBot(telegram.NewClient(cfg.TelegramToken), messageHandler, thoughtRepo, cfg.TelegramSecret, cfg.TelegramUsers)
+		webhooks.Register(telegramBot.Webhook())
+		if cfg.TelegramWebhookURL != "" {
+			if err := telegramBot.Register(ctx, cfg.TelegramWebhookURL); err != nil {
+				slog.Error("Failed to register Telegram webhook", "error", err)
+			}
+		}
+		slog.Info("Telegram capture enabled", "url", "http://localhost:3000/telegram/webhook")
+	}
+
+	if linkedinAuth != nil {
+		slackServer.HandleFunc("/auth/linkedin/callback", linkedinAuth.HandleCallback)
+		slog.Info("LinkedIn OAuth callback enabled", "url", cfg.LinkedInRedirectURL)
 	}
 
-	slackServer := slackpkg.NewServer(slackClient, messageHandler, approvalHandler, cfg.SlackSigningSecret)
+	if cfg.APIToken != "" {
+		apiHandler := api.NewHandler(thoughtRepo, postRepo, revisionRepo, approvalRepo, scheduler, thoughtImporter, importJobRepo, cfg.APIToken, cfg.ReviewerSlackID != "", policy)
+		slackServer.HandleFunc("/api/v1/", apiHandler.ServeHTTP)
+		slog.Info("Admin API enabled", "url", "http://localhost:3000/api/v1/")
+	} else {
+		slog.Info("API_TOKEN not configured, admin API disabled")
+	}
 
 	go func() {
-		if err := slackServer.Start("3000"); err != nil {
-			log.Fatalf("Failed to start Slack server: %v", err)
+		if err := slackServer.Start(); err != nil {
+			fatal("Failed to start Slack server", err)
 		}
 	}()
 
-	log.Println("Bot is running. Press Ctrl+C to stop...")
+	slog.Info("Bot is running. Press Ctrl+C to stop...")
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-}
\ No newline at end of file
+
+	slog.Info("Shutting down...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := slackServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server shutdown error", "error", err)
+	}
+
+	cancel()
+	workers.Wait()
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+
+	db.Close()
+	slog.Info("Shutdown complete")
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// thoughtsPerDraft matches how many thoughts the Slack generate command
+// combines into one set of drafts.
+const thoughtsPerDraft = 3
+
+func (a *app) add(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("add", flag.ExitOnError)
+	channel := flags.String("channel", os.Getenv("GHOSTCTL_CHANNEL"), "Slack channel whose workspace the thought belongs to")
+	flags.Parse(args)
+
+	content := strings.Join(flags.Args(), " ")
+	if content == "" {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read thought from stdin: %w", err)
+		}
+		content = string(input)
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return fmt.Errorf("nothing to add: pass the thought as arguments or on stdin")
+	}
+
+	thought := models.NewThought(content, "cli")
+	thought.SlackChannelID = *channel
+
+	categorizer, err := a.llm(ctx, agents.RoleCategorizer)
+	if err == nil {
+		err = agents.NewCategorizerAgent(categorizer, a.cfg.CategorizerTokens, a.prompts, a.categoryRepo, 0, 0).CategorizeThought(ctx, thought)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not categorize thought, saving it uncategorized: %v\n", err)
+		thought.Category = "uncategorized"
+		thought.TopicTags = []string{"general"}
+	}
+
+	if err := a.thoughtRepo.Create(ctx, thought); err != nil {
+		return err
+	}
+
+	fmt.Printf("Captured %s | Category: %s | Tags: %s\n", shortID(thought.ID), thought.Category, strings.Join(thought.TopicTags, ", "))
+	return nil
+}
+
+func (a *app) drafts(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("drafts", flag.ExitOnError)
+	full := flags.Bool("full", false, "print the full text of each draft")
+	flags.Parse(args)
+
+	drafts, err := a.postRepo.GetByStatus(ctx, "draft")
+	if err != nil {
+		return err
+	}
+
+	if len(drafts) == 0 {
+		fmt.Println("No pending drafts.")
+		return nil
+	}
+
+	for _, draft := range drafts {
+		printPost(draft, *full)
+	}
+	return nil
+}
+
+func (a *app) approve(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: ghostctl approve <draft id>")
+	}
+
+	drafts, err := a.postRepo.GetByStatus(ctx, "draft")
+	if err != nil {
+		return err
+	}
+
+	requireReview := a.cfg.ReviewerSlackID != ""
+	if requireReview {
+		// Drafts the reviewer approved first still need the author.
+		inReview, err := a.postRepo.GetByStatus(ctx, models.StatusInReview)
+		if err != nil {
+			return err
+		}
+		drafts = append(drafts, inReview...)
+	}
+
+	var matches []*models.Post
+	for _, draft := range drafts {
+		if strings.HasPrefix(draft.ID, args[0]) {
+			matches = append(matches, draft)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no pending draft matches %q", args[0])
+	case 1:
+	default:
+		return fmt.Errorf("%q matches %d drafts, use a longer id", args[0], len(matches))
+	}
+
+	post := matches[0]
+	policy := agents.NewPolicyAgent(a.cfg.BannedTerms, a.cfg.CustomerNames, a.cfg.Codenames)
+	if err := policy.Check(post); err != nil {
+		return fmt.Errorf("can't approve %s, edit it first: %w", shortID(post.ID), err)
+	}
+
+	status, err := a.approvalRepo.Decide(ctx, models.NewApproval(post.ID, "cli", models.RoleAuthor, models.DecisionApproved), requireReview)
+	if err != nil {
+		return err
+	}
+
+	if status == models.StatusInReview {
+		fmt.Printf("Approved %s. It can be scheduled once the reviewer approves it in Slack.\n", shortID(post.ID))
+		return nil
+	}
+
+	if len(post.SourceThoughtIDs) > 0 {
+		if err := a.thoughtRepo.MarkUsed(ctx, post.SourceThoughtIDs, post.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to mark thoughts used: %v\n", err)
+		}
+	}
+
+	fmt.Printf("Approved %s. Schedule it from Slack with `schedule`.\n", shortID(post.ID))
+	return nil
+}
+
+func (a *app) generate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	channel := flags.String("channel", os.Getenv("GHOSTCTL_CHANNEL"), "Slack channel whose workspace to generate from")
+	user := flags.String("user", os.Getenv("GHOSTCTL_USER"), "Slack user ID whose learned style to use")
+	count := flags.Int("variations", a.cfg.Variations, "number of variations to write, 1-5")
+	language := flags.String("language", a.cfg.PostLanguage, "language to write in, such as en or de")
+	emoji := flags.String("emoji", a.cfg.EmojiPolicy, "emojis to use: none, minimal or expressive")
+	length := flags.String("length", a.cfg.PostLength, "post length: short, medium, long or a number of words")
+	flags.Parse(args)
+
+	code, err := models.ParseLanguage(*language)
+	if err != nil {
+		return err
+	}
+	policy, err := models.ParseEmojiPolicy(*emoji)
+	if err != nil {
+		return err
+	}
+	postLength, err := models.ParsePostLength(*length)
+	if err != nil {
+		return err
+	}
+	ctx = agents.WithEmojiPolicy(agents.WithLanguage(ctx, code), policy)
+	ctx = agents.WithPostLength(ctx, postLength)
+
+	topic := strings.Join(flags.Args(), " ")
+
+	var thoughts []*models.Thought
+	if topic != "" {
+		thoughts, err = a.thoughtRepo.SearchByTagOrKeyword(ctx, *channel, topic, thoughtsPerDraft)
+	} else {
+		thoughts, err = a.thoughtRepo.GetUnused(ctx, *channel, thoughtsPerDraft)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(thoughts) == 0 {
+		return fmt.Errorf("no thoughts to generate from")
+	}
+
+	llm, err := a.llm(ctx, agents.RoleGeneration)
+	if err != nil {
+		return err
+	}
+
+	var userStyle string
+	if *user != "" {
+		profile, err := a.styleRepo.GetByUserID(ctx, *user)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load style profile: %v\n", err)
+		}
+		userStyle = agents.FormatStyleGuide(profile)
+	}
+
+	examples, err := a.postRepo.GetTopPerforming(ctx, 3)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load top performing posts: %v\n", err)
+	}
+
+	fmt.Printf("Generating drafts from %d thought(s)...\n\n", len(thoughts))
+
+	variations, err := agents.NewContentGeneratorAgent(llm, a.cfg.LLMMaxTokens, a.prompts).GeneratePost(ctx, thoughts, userStyle, examples, *count)
+	if err != nil {
+		return err
+	}
+
+	thoughtIDs := make([]string, len(thoughts))
+	for i, t := range thoughts {
+		thoughtIDs[i] = t.ID
+	}
+
+	var factChecker *agents.FactCheckerAgent
+	if a.cfg.FactCheck {
+		factChecker = agents.NewFactCheckerAgent(llm)
+	}
+
+	for _, variation := range variations {
+		post := models.NewPost(variation.Content, thoughtIDs, "insight", "professional")
+		post.FirstComment = variation.FirstComment
+		post.Language = code
+		post.Provenance = models.ProvenanceOf(thoughts)
+		if factChecker != nil {
+			if post.FactCheck, err = factChecker.Check(ctx, post.Content, thoughts); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to fact-check draft: %v\n", err)
+			}
+		}
+		if err := a.postRepo.Create(ctx, post); err != nil {
+			return err
+		}
+		if err := a.revisionRepo.Record(ctx, post.ID, post.Content, models.RevisionGeneration, "cli", ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to record revision: %v\n", err)
+		}
+		printPost(post, true)
+	}
+
+	fmt.Println("Approve one with `ghostctl approve <id>`.")
+	return nil
+}
+
+func printPost(post *models.Post, full bool) {
+	content := post.Content
+	if !full {
+		content = strings.Join(strings.Fields(content), " ")
+		if runes := []rune(content); len(runes) > 100 {
+			content = string(runes[:100]) + "..."
+		}
+	}
+	if full && post.FirstComment != "" {
+		content += "\n\nFirst comment: " + post.FirstComment
+	}
+	if full && post.FactCheck != nil && len(post.FactCheck.Flags) > 0 {
+		content += fmt.Sprintf("\n\nFact check: %d of %d claim(s) not found in the source thoughts:", len(post.FactCheck.Flags), post.FactCheck.Checked)
+		for _, flag := range post.FactCheck.Flags {
+			content += fmt.Sprintf("\n- %q %s", flag.Claim, flag.Verdict)
+			if flag.Note != "" {
+				content += ": " + flag.Note
+			}
+		}
+	}
+
+	fmt.Printf("%s  %s\n%s\n\n", shortID(post.ID), post.CreatedAt.Format("Jan 2 15:04"), content)
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+func migrate(ctx context.Context, db *database.DB, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		return db.Migrate(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations to revert: %s", args[1])
+			}
+			steps = n
+		}
+		return db.MigrateDown(ctx, steps)
+	case "status":
+		states, err := db.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			applied := "pending"
+			if state.AppliedAt != nil {
+				applied = "applied " + state.AppliedAt.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%04d_%s  %s\n", state.Version, state.Name, applied)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown migrate action %q, expected up, down or status", action)
+}
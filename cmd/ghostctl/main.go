@@ -0,0 +1,179 @@
+// Command ghostctl captures thoughts and reviews drafts from the terminal,
+// working directly against the bot's database.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/shubh-37/linkedin-ghostwriter/config"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/cache"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/logging"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/outbound"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/prompts"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/telemetry"
+	"go.opentelemetry.io/otel"
+)
+
+const usage = `Usage: ghostctl <command> [flags] [args]
+
+Commands:
+  add [-channel C0123] <thought>      Capture a thought (reads stdin when no text is given)
+  drafts [-full]                      List pending drafts
+  approve <draft id>                  Approve a draft (an id prefix from drafts is enough)
+  generate [-channel C0123] [-user U0123] [topic]
+                                      Generate drafts from unused thoughts, or thoughts about topic
+  migrate [up | down [n] | status]    Apply pending migrations, revert the last n (default 1), or list them
+
+Thoughts added without -channel (or GHOSTCTL_CHANNEL) go to the shared pool.
+`
+
+type app struct {
+	cfg          *config.Config
+	thoughtRepo  *database.ThoughtRepository
+	postRepo     *database.PostRepository
+	styleRepo    *database.StyleRepository
+	revisionRepo *database.RevisionRepository
+	approvalRepo *database.ApprovalRepository
+	categoryRepo *database.CategoryRepository
+	guards       *outbound.Guards
+	models       *agents.ModelRegistry
+	prompts      *prompts.Store
+	usage        *agents.UsageTracker
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := run(os.Args[1], os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "ghostctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(command string, args []string) error {
+	cfg := config.LoadConfig()
+
+	// Diagnostics stay on stderr and out of the way of command output
+	// unless something goes wrong.
+	if err := logging.Setup("text", "warn"); err != nil {
+		return err
+	}
+
+	if cfg.DatabaseURL == "" {
+		return fmt.Errorf("DATABASE_URL is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	shutdownTracing, err := telemetry.Setup(ctx, cfg.ServiceName, cfg.OTLPEndpoint)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.WithoutCancel(ctx))
+
+	ctx, span := otel.Tracer("github.com/shubh-37/linkedin-ghostwriter/cmd/ghostctl").Start(ctx, "ghostctl "+command)
+	defer span.End()
+
+	// Posts changed from the command line are audited as "cli", and model
+	// calls are attributed to the command.
+	ctx = database.WithActor(ctx, "cli")
+	ctx = agents.WithCommand(ctx, command)
+
+	db, err := database.NewDB(cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	// The bot's shared cache is invalidated by changes made here too; its
+	// in-process cache catches up within CACHE_TTL_SECONDS.
+	if cfg.CacheTTL > 0 && cfg.RedisURL != "" {
+		store, err := cache.NewRedis(cfg.RedisURL)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		db.Cache = cache.New(store, cfg.CacheTTL)
+	}
+
+	if command == "migrate" {
+		return migrate(ctx, db, args)
+	}
+
+	if err := db.Migrate(ctx); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	promptStore, err := prompts.NewStore(database.NewPromptRepository(db), cfg.PromptsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load prompt templates: %w", err)
+	}
+
+	a := &app{
+		cfg:          cfg,
+		thoughtRepo:  database.NewThoughtRepository(db),
+		postRepo:     database.NewPostRepository(db),
+		styleRepo:    database.NewStyleRepository(db),
+		revisionRepo: database.NewRevisionRepository(db),
+		approvalRepo: database.NewApprovalRepository(db),
+		categoryRepo: database.NewCategoryRepository(db),
+		guards:       outbound.NewGuards(cfg.RateLimits, cfg.BreakerFailures, cfg.BreakerCooldown),
+		models:       agents.NewModelRegistry(database.NewSettingsRepository(db)),
+		prompts:      promptStore,
+		usage:        agents.NewUsageTracker(database.NewUsageRepository(db), database.NewSettingsRepository(db), cfg.LLMMonthlyBudget, cfg.LLMBudgetWarn, nil, ""),
+	}
+
+	switch command {
+	case "add":
+		return a.add(ctx, args)
+	case "drafts":
+		return a.drafts(ctx, args)
+	case "approve":
+		return a.approve(ctx, args)
+	case "generate":
+		return a.generate(ctx, args)
+	}
+
+	return fmt.Errorf("unknown command %q, run ghostctl help", command)
+}
+
+// llm builds the provider for role, either "generation" or "categorizer",
+// with the same model, retry policy, timeouts, provider guards and budget as
+// the bot.
+func (a *app) llm(ctx context.Context, role string) (agents.LLMProvider, error) {
+	providerConfig := agents.ProviderConfig{
+		Provider:      a.cfg.LLMProvider,
+		Model:         a.cfg.LLMModel,
+		AnthropicKey:  a.cfg.AnthropicKey,
+		OpenAIKey:     a.cfg.OpenAIKey,
+		OpenAIBaseURL: a.cfg.OpenAIBaseURL,
+		OllamaURL:     a.cfg.OllamaURL,
+		Temperature:   a.cfg.LLMTemperature,
+		Guards:        a.guards,
+	}
+	timeout := a.cfg.LLMTimeout
+	if role == agents.RoleCategorizer {
+		providerConfig.Provider = a.cfg.CategorizerProvider
+		providerConfig.Model = a.cfg.CategorizerModel
+		providerConfig.Temperature = a.cfg.CategorizerTemp
+		timeout = a.cfg.CategorizerTimeout
+	}
+
+	provider, err := agents.NewSwitchableProvider(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	a.models.Register(ctx, role, provider)
+
+	llm := agents.WithTracing(agents.WithRetry(agents.WithTimeout(provider, timeout), agents.RetryConfig{MaxAttempts: a.cfg.LLMMaxAttempts}))
+	return a.usage.Track(llm, role, role == agents.RoleGeneration), nil
+}
@@ -2,32 +2,276 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DatabaseURL    string
-	SlackToken     string
-	SlackSigningSecret string
-	LinearToken    string
-	AnthropicKey   string
+	DatabaseURL         string
+	SlackToken          string
+	SlackSigningSecret  string
+	SlackNotifyChannel  string
+	ReviewerSlackID     string
+	OwnerSlackIDs       []string
+	EventWorkers        int
+	JobWorkers          int
+	EventQueueSize      int
+	EventTimeout        time.Duration
+	DedupCacheSize      int
+	DedupTTL            time.Duration
+	CacheTTL            time.Duration
+	RedisURL            string
+	LinearToken         string
+	LinearWebhookSecret string
+	LinearTeams         []string
+	LinearProjects      []string
+	LinearLabels        []string
+	LinearMinEstimate   int
+	LinearMilestones    string
+	LinearBacklinks     string
+	LinearIdeaLabel     string
+	NotionToken         string
+	NotionDatabaseID    string
+	NotionIdeaStatus    string
+	NotionSyncInterval  time.Duration
+	JournalVaultPath    string
+	JournalNotionDBID   string
+	JournalInterval     time.Duration
+	EmailMailgunKey     string
+	EmailSESTopicARN    string
+	EmailAllowedSenders []string
+	TelegramToken       string
+	TelegramSecret      string
+	TelegramWebhookURL  string
+	TelegramUsers       []string
+	AnthropicKey        string
+	OpenAIKey           string
+	OpenAIBaseURL       string
+	OllamaURL           string
+	LLMProvider         string
+	LLMModel            string
+	CategorizerProvider string
+	CategorizerModel    string
+	LLMMaxAttempts      int
+	LLMTimeout          time.Duration
+	LLMMaxTokens        int
+	LLMTemperature      *float64
+	LLMMonthlyBudget    float64
+	LLMBudgetWarn       float64
+	Variations          int
+	PostLanguage        string
+	EmojiPolicy         string
+	PostLength          string
+	FactCheck           bool
+	BannedTerms         []string
+	CustomerNames       []string
+	Codenames           []string
+	CategorizerTimeout  time.Duration
+	CategorizerTokens   int
+	CategorizerTemp     *float64
+	// CategorizerBatch is how long thoughts captured in Slack wait to be
+	// categorized together, up to CategorizerBatchMax of them.
+	CategorizerBatch    time.Duration
+	CategorizerBatchMax int
+	PromptsDir          string
+	ImportRateLimit     int
+	RateLimits          map[string]int
+	BreakerFailures     int
+	BreakerCooldown     time.Duration
+	EmbeddingProvider   string
+	EmbeddingModel      string
+	ImageProvider       string
+	ImageModel          string
+	ImageDir            string
+	CarouselDir         string
+	SummarizeSources    bool
+	LinkedInAccessToken string
+	LinkedInAuthorURN   string
+	LinkedInAuthorID    string
+	LinkedInClientID    string
+	LinkedInSecret      string
+	LinkedInRedirectURL string
+	LinkedInOrgURN      string
+	LinkedInOrgToken    string
+	MetricsSyncInterval time.Duration
+	DuplicateThreshold  float64
+	ConfirmBefore       time.Duration
+	XAPIKey             string
+	XAPISecret          string
+	XAccessToken        string
+	XAccessSecret       string
+	PostingDays         []time.Weekday
+	GoogleClientID      string
+	GoogleClientSecret  string
+	GoogleRefreshToken  string
+	GoogleCalendarID    string
+	CalendarAway        []string
+	CalendarTalks       []string
+	APIToken            string
+	DigestSchedule      string
+	DigestTimezone      string
+	DigestNudgeDays     int
+	BacklogSchedule     string
+	BacklogGapDays      int
+	BacklogStaleDays    int
+	CheckInTime         string
+	JanitorSchedule     string
+	JanitorStaleDays    int
+	JanitorArchiveDays  int
+	JanitorTrashDays    int
+	RetryMaxAttempts    int
+	LogFormat           string
+	LogLevel            string
+	OTLPEndpoint        string
+	ServiceName         string
 }
 
 func LoadConfig() *Config {
 	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found or couldn't be loaded: %v", err)
+		slog.Warn(".env file not found or couldn't be loaded", "error", err)
 	}
-	
-	return &Config{
-		DatabaseURL:        getEnv("DATABASE_URL", ""),
-		SlackToken:         getEnv("SLACK_BOT_TOKEN", ""),
-		SlackSigningSecret: getEnv("SLACK_SIGNING_SECRET", ""),
-		LinearToken:        getEnv("LINEAR_API_KEY", ""),
-		AnthropicKey:       getEnv("ANTHROPIC_API_KEY", ""),
+
+	cfg := &Config{
+		DatabaseURL:         getEnv("DATABASE_URL", ""),
+		SlackToken:          getEnv("SLACK_BOT_TOKEN", ""),
+		SlackSigningSecret:  getEnv("SLACK_SIGNING_SECRET", ""),
+		SlackNotifyChannel:  getEnv("SLACK_NOTIFY_CHANNEL", ""),
+		ReviewerSlackID:     getEnv("REVIEWER_SLACK_ID", ""),
+		OwnerSlackIDs:       getEnvList("OWNER_SLACK_IDS", ""),
+		EventWorkers:        getEnvInt("EVENT_WORKERS", 4),
+		JobWorkers:          getEnvInt("JOB_WORKERS", 2),
+		EventQueueSize:      getEnvInt("EVENT_QUEUE_SIZE", 100),
+		EventTimeout:        time.Duration(getEnvInt("EVENT_TIMEOUT_SECONDS", 120)) * time.Second,
+		DedupCacheSize:      getEnvInt("DEDUP_CACHE_SIZE", 10000),
+		DedupTTL:            time.Duration(getEnvInt("DEDUP_TTL_MINUTES", 1440)) * time.Minute,
+		CacheTTL:            time.Duration(getEnvInt("CACHE_TTL_SECONDS", 60)) * time.Second,
+		RedisURL:            getEnv("REDIS_URL", ""),
+		LinearToken:         getEnv("LINEAR_API_KEY", ""),
+		LinearWebhookSecret: getEnv("LINEAR_WEBHOOK_SECRET", ""),
+		LinearTeams:         getEnvList("LINEAR_TEAMS", ""),
+		LinearProjects:      getEnvList("LINEAR_PROJECTS", ""),
+		LinearLabels:        getEnvList("LINEAR_LABELS", ""),
+		LinearMinEstimate:   getEnvInt("LINEAR_MIN_ESTIMATE", 0),
+		LinearMilestones:    getEnv("LINEAR_MILESTONE_SCHEDULE", "off"),
+		LinearBacklinks:     getEnv("LINEAR_BACKLINKS", "attachment"),
+		LinearIdeaLabel:     getEnv("LINEAR_IDEA_LABEL", ""),
+		NotionToken:         getEnv("NOTION_TOKEN", ""),
+		NotionDatabaseID:    getEnv("NOTION_DATABASE_ID", ""),
+		NotionIdeaStatus:    getEnv("NOTION_IDEA_STATUS", "Idea"),
+		NotionSyncInterval:  time.Duration(getEnvInt("NOTION_SYNC_INTERVAL_MINUTES", 10)) * time.Minute,
+		JournalVaultPath:    getEnv("JOURNAL_VAULT_PATH", ""),
+		JournalNotionDBID:   getEnv("JOURNAL_NOTION_DATABASE_ID", ""),
+		JournalInterval:     time.Duration(getEnvInt("JOURNAL_INTERVAL_MINUTES", 5)) * time.Minute,
+		EmailMailgunKey:     getEnv("EMAIL_MAILGUN_SIGNING_KEY", ""),
+		EmailSESTopicARN:    getEnv("EMAIL_SES_TOPIC_ARN", ""),
+		EmailAllowedSenders: getEnvList("EMAIL_ALLOWED_SENDERS", ""),
+		TelegramToken:       getEnv("TELEGRAM_BOT_TOKEN", ""),
+		TelegramSecret:      getEnv("TELEGRAM_WEBHOOK_SECRET", ""),
+		TelegramWebhookURL:  getEnv("TELEGRAM_WEBHOOK_URL", ""),
+		TelegramUsers:       getEnvList("TELEGRAM_ALLOWED_USERS", ""),
+		AnthropicKey:        getEnv("ANTHROPIC_API_KEY", ""),
+		OpenAIKey:           getEnv("OPENAI_API_KEY", ""),
+		OpenAIBaseURL:       getEnv("OPENAI_BASE_URL", ""),
+		OllamaURL:           getEnv("OLLAMA_URL", ""),
+		LLMProvider:         getEnv("LLM_PROVIDER", "anthropic"),
+		LLMModel:            getEnv("LLM_MODEL", ""),
+		CategorizerProvider: getEnv("CATEGORIZER_LLM_PROVIDER", ""),
+		CategorizerModel:    getEnv("CATEGORIZER_LLM_MODEL", ""),
+		LLMMaxAttempts:      getEnvInt("LLM_MAX_ATTEMPTS", 4),
+		LLMTimeout:          time.Duration(getEnvInt("LLM_TIMEOUT_SECONDS", 90)) * time.Second,
+		CategorizerTimeout:  time.Duration(getEnvInt("CATEGORIZER_TIMEOUT_SECONDS", 30)) * time.Second,
+		LLMMaxTokens:        getEnvInt("LLM_MAX_TOKENS", 2000),
+		LLMTemperature:      getEnvFloat("LLM_TEMPERATURE"),
+		LLMMonthlyBudget:    getEnvAmount("LLM_MONTHLY_BUDGET"),
+		LLMBudgetWarn:       getEnvRatio("LLM_BUDGET_WARN_RATIO", 0.8),
+		Variations:          getEnvInt("VARIATIONS", 3),
+		PostLanguage:        getEnv("POST_LANGUAGE", "en"),
+		EmojiPolicy:         getEnv("EMOJI_POLICY", "minimal"),
+		PostLength:          getEnv("POST_LENGTH", "medium"),
+		FactCheck:           getEnv("FACT_CHECK", "off") == "on",
+		BannedTerms:         getEnvList("BANNED_TERMS", ""),
+		CustomerNames:       getEnvList("CONFIDENTIAL_CUSTOMERS", ""),
+		Codenames:           getEnvList("UNRELEASED_CODENAMES", ""),
+		CategorizerTokens:   getEnvInt("CATEGORIZER_MAX_TOKENS", 500),
+		CategorizerTemp:     getEnvFloat("CATEGORIZER_TEMPERATURE"),
+		CategorizerBatch:    time.Duration(getEnvInt("CATEGORIZER_BATCH_SECONDS", 2)) * time.Second,
+		CategorizerBatchMax: getEnvInt("CATEGORIZER_BATCH_SIZE", 10),
+		PromptsDir:          getEnv("PROMPTS_DIR", ""),
+		ImportRateLimit:     getEnvInt("IMPORT_RATE_LIMIT", 20),
+		RateLimits: map[string]int{
+			"anthropic": getEnvInt("ANTHROPIC_RATE_LIMIT", 50),
+			"slack":     getEnvInt("SLACK_RATE_LIMIT", 50),
+			"linear":    getEnvInt("LINEAR_RATE_LIMIT", 25),
+			"linkedin":  getEnvInt("LINKEDIN_RATE_LIMIT", 20),
+		},
+		BreakerFailures:     getEnvInt("CIRCUIT_BREAKER_FAILURES", 5),
+		BreakerCooldown:     time.Duration(getEnvInt("CIRCUIT_BREAKER_COOLDOWN_SECONDS", 60)) * time.Second,
+		EmbeddingProvider:   getEnv("EMBEDDING_PROVIDER", ""),
+		EmbeddingModel:      getEnv("EMBEDDING_MODEL", ""),
+		ImageProvider:       getEnv("IMAGE_PROVIDER", ""),
+		ImageModel:          getEnv("IMAGE_MODEL", ""),
+		ImageDir:            getEnv("IMAGE_DIR", "data/images"),
+		CarouselDir:         getEnv("CAROUSEL_DIR", "data/carousels"),
+		SummarizeSources:    getEnv("SUMMARIZE_SOURCES", "on") != "off",
+		LinkedInAccessToken: getEnv("LINKEDIN_ACCESS_TOKEN", ""),
+		LinkedInAuthorURN:   getEnv("LINKEDIN_AUTHOR_URN", ""),
+		LinkedInAuthorID:    getEnv("LINKEDIN_AUTHOR_SLACK_ID", ""),
+		LinkedInClientID:    getEnv("LINKEDIN_CLIENT_ID", ""),
+		LinkedInSecret:      getEnv("LINKEDIN_CLIENT_SECRET", ""),
+		LinkedInRedirectURL: getEnv("LINKEDIN_REDIRECT_URL", "http://localhost:3000/auth/linkedin/callback"),
+		LinkedInOrgURN:      getEnv("LINKEDIN_ORGANIZATION_URN", ""),
+		LinkedInOrgToken:    getEnv("LINKEDIN_ORGANIZATION_TOKEN", ""),
+		MetricsSyncInterval: time.Duration(getEnvInt("METRICS_SYNC_INTERVAL_MINUTES", 360)) * time.Minute,
+		DuplicateThreshold:  getEnvRatio("DUPLICATE_THRESHOLD", 0.9),
+		ConfirmBefore:       time.Duration(getEnvInt("PUBLISH_CONFIRM_MINUTES", 30)) * time.Minute,
+		XAPIKey:             getEnv("X_API_KEY", ""),
+		XAPISecret:          getEnv("X_API_SECRET", ""),
+		XAccessToken:        getEnv("X_ACCESS_TOKEN", ""),
+		XAccessSecret:       getEnv("X_ACCESS_TOKEN_SECRET", ""),
+		PostingDays:         getEnvWeekdays("POSTING_DAYS", "mon,tue,wed,thu,fri"),
+		GoogleClientID:      getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret:  getEnv("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRefreshToken:  getEnv("GOOGLE_REFRESH_TOKEN", ""),
+		GoogleCalendarID:    getEnv("GOOGLE_CALENDAR_ID", "primary"),
+		CalendarAway:        getEnvList("CALENDAR_AWAY_KEYWORDS", "ooo,out of office,vacation,holiday,pto,leave,travel,flight,conference"),
+		CalendarTalks:       getEnvList("CALENDAR_TALK_KEYWORDS", "talk,keynote,panel,meetup,webinar,workshop,presentation,speaking,podcast,demo day"),
+		APIToken:            getEnv("API_TOKEN", ""),
+		DigestSchedule:      getEnv("DIGEST_SCHEDULE", "mon 09:00"),
+		DigestTimezone:      getEnv("DIGEST_TIMEZONE", "Asia/Kolkata"),
+		DigestNudgeDays:     getEnvInt("DIGEST_NUDGE_DAYS", 3),
+		BacklogSchedule:     getEnv("BACKLOG_REVIEW_SCHEDULE", "mon 09:30"),
+		BacklogGapDays:      getEnvInt("BACKLOG_GAP_DAYS", 21),
+		BacklogStaleDays:    getEnvInt("BACKLOG_STALE_DAYS", 14),
+		CheckInTime:         getEnv("CHECKIN_TIME", "18:00"),
+		JanitorSchedule:     getEnv("JANITOR_SCHEDULE", "fri 16:00"),
+		JanitorStaleDays:    getEnvInt("JANITOR_STALE_DAYS", 14),
+		JanitorArchiveDays:  getEnvInt("JANITOR_ARCHIVE_DAYS", 30),
+		JanitorTrashDays:    getEnvInt("JANITOR_TRASH_DAYS", 30),
+		RetryMaxAttempts:    getEnvInt("RETRY_MAX_ATTEMPTS", 5),
+		LogFormat:           getEnv("LOG_FORMAT", "json"),
+		LogLevel:            getEnv("LOG_LEVEL", "info"),
+		OTLPEndpoint:        getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		ServiceName:         getEnv("OTEL_SERVICE_NAME", "linkedin-ghostwriter"),
+	}
+
+	// Without an author set, posts go to the first owner's profile.
+	if cfg.LinkedInAuthorID == "" && len(cfg.OwnerSlackIDs) > 0 {
+		cfg.LinkedInAuthorID = cfg.OwnerSlackIDs[0]
 	}
+
+	if cfg.CategorizerProvider == "" {
+		cfg.CategorizerProvider = cfg.LLMProvider
+		if cfg.CategorizerModel == "" {
+			cfg.CategorizerModel = cfg.LLMModel
+		}
+	}
+
+	return cfg
 }
 
 func getEnv(key, defaultValue string) string {
@@ -37,6 +281,118 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("Invalid environment value, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+
+	return parsed
+}
+
+// getEnvFloat returns nil when key is unset or invalid, leaving the choice
+// to whoever reads it.
+func getEnvFloat(key string) *float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		slog.Warn("Invalid environment value, ignoring it", "key", key, "value", value)
+		return nil
+	}
+
+	return &parsed
+}
+
+// getEnvAmount reads an amount of money, returning 0 when key is unset or
+// invalid.
+func getEnvAmount(key string) float64 {
+	amount := getEnvFloat(key)
+	if amount == nil || *amount < 0 {
+		return 0
+	}
+	return *amount
+}
+
+// getEnvRatio reads a value above 0 and at most 1, returning 0 when key is
+// "off".
+func getEnvRatio(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	switch value {
+	case "":
+		return defaultValue
+	case "off":
+		return 0
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed <= 0 || parsed > 1 {
+		slog.Warn("Invalid environment value, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+
+	return parsed
+}
+
+// getEnvList reads a comma-separated list, dropping empty entries.
+func getEnvList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func getEnvWeekdays(key, defaultValue string) []time.Weekday {
+	value := getEnv(key, defaultValue)
+
+	days, err := parseWeekdays(value)
+	if err != nil {
+		slog.Warn("Invalid environment value, using default", "key", key, "value", value, "default", defaultValue)
+		days, _ = parseWeekdays(defaultValue)
+	}
+
+	return days
+}
+
+func parseWeekdays(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) > 3 {
+			name = name[:3]
+		}
+		day, ok := weekdays[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
 func (c *Config) Validate() error {
 	if c.DatabaseURL == "" {
 		return fmt.Errorf("DATABASE_URL is required")
@@ -47,8 +403,56 @@ func (c *Config) Validate() error {
 	if c.SlackSigningSecret == "" {
 		return fmt.Errorf("SLACK_SIGNING_SECRET is required")
 	}
-	if c.AnthropicKey == "" {
-		return fmt.Errorf("ANTHROPIC_API_KEY is required")
+	for _, provider := range []string{c.LLMProvider, c.CategorizerProvider} {
+		switch provider {
+		case "anthropic":
+			if c.AnthropicKey == "" {
+				return fmt.Errorf("ANTHROPIC_API_KEY is required")
+			}
+		case "openai":
+			if c.OpenAIKey == "" {
+				return fmt.Errorf("OPENAI_API_KEY is required when using the openai provider")
+			}
+		}
+	}
+	if c.EmbeddingProvider == "openai" && c.OpenAIKey == "" {
+		return fmt.Errorf("OPENAI_API_KEY is required when EMBEDDING_PROVIDER is openai")
+	}
+	if c.ImageProvider == "openai" && c.OpenAIKey == "" {
+		return fmt.Errorf("OPENAI_API_KEY is required when IMAGE_PROVIDER is openai")
+	}
+	if c.NotionToken != "" && c.NotionDatabaseID == "" {
+		return fmt.Errorf("NOTION_DATABASE_ID is required when NOTION_TOKEN is set")
+	}
+	if c.JournalNotionDBID != "" && c.NotionToken == "" {
+		return fmt.Errorf("NOTION_TOKEN is required when JOURNAL_NOTION_DATABASE_ID is set")
+	}
+	if (c.EmailMailgunKey != "" || c.EmailSESTopicARN != "") && len(c.EmailAllowedSenders) == 0 {
+		return fmt.Errorf("EMAIL_ALLOWED_SENDERS is required when email capture is enabled")
+	}
+	if c.TelegramToken != "" && c.TelegramSecret == "" {
+		return fmt.Errorf("TELEGRAM_WEBHOOK_SECRET is required when TELEGRAM_BOT_TOKEN is set")
+	}
+	if c.TelegramToken != "" && len(c.TelegramUsers) == 0 {
+		return fmt.Errorf("TELEGRAM_ALLOWED_USERS is required when TELEGRAM_BOT_TOKEN is set")
+	}
+	if c.LinkedInAccessToken != "" && c.LinkedInAuthorURN == "" {
+		return fmt.Errorf("LINKEDIN_AUTHOR_URN is required when LINKEDIN_ACCESS_TOKEN is set")
+	}
+	if c.LinkedInOrgToken != "" && c.LinkedInOrgURN == "" {
+		return fmt.Errorf("LINKEDIN_ORGANIZATION_URN is required when LINKEDIN_ORGANIZATION_TOKEN is set")
+	}
+	if c.LinkedInClientID != "" && c.LinkedInSecret == "" {
+		return fmt.Errorf("LINKEDIN_CLIENT_SECRET is required when LINKEDIN_CLIENT_ID is set")
+	}
+	if c.LinkedInClientID != "" && c.LinkedInAccessToken == "" && c.LinkedInAuthorID == "" {
+		return fmt.Errorf("LINKEDIN_AUTHOR_SLACK_ID or OWNER_SLACK_IDS is required when LINKEDIN_CLIENT_ID is set")
+	}
+	if c.GoogleRefreshToken != "" && (c.GoogleClientID == "" || c.GoogleClientSecret == "") {
+		return fmt.Errorf("GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET are required when GOOGLE_REFRESH_TOKEN is set")
+	}
+	if c.XAPIKey != "" && (c.XAPISecret == "" || c.XAccessToken == "" || c.XAccessSecret == "") {
+		return fmt.Errorf("X_API_SECRET, X_ACCESS_TOKEN and X_ACCESS_TOKEN_SECRET are required when X_API_KEY is set")
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,135 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// CategoryGap is a category nothing has been published about for a while.
+// LastPublished is zero when nothing ever was, and Waiting counts its
+// thoughts not used in a post yet.
+type CategoryGap struct {
+	Category      string
+	LastPublished time.Time
+	Waiting       int
+}
+
+// Suggestion is an aging thought worth turning into a post next.
+type Suggestion struct {
+	Thought *models.Thought
+	Reason  string
+}
+
+// BacklogAnalystAgent reviews the thought backlog and picks what to post
+// about next.
+type BacklogAnalystAgent struct {
+	llm LLMProvider
+}
+
+func NewBacklogAnalystAgent(llm LLMProvider) *BacklogAnalystAgent {
+	if llm == nil {
+		slog.Error("LLM provider is required")
+		os.Exit(1)
+	}
+
+	return &BacklogAnalystAgent{
+		llm: llm,
+	}
+}
+
+// maxSuggestionContent is how much of each thought the analyst reads.
+const maxSuggestionContent = 300
+
+type suggestionsResponse struct {
+	Picks []struct {
+		Thought int    `json:"thought"`
+		Reason  string `json:"reason"`
+	} `json:"picks"`
+}
+
+var suggestionsSchema = objectSchema(map[string]Schema{
+	"picks": arraySchema("The picks, best first", objectSchema(map[string]Schema{
+		"thought": integerSchema("The number of the thought"),
+		"reason":  stringSchema("A short reason it should be posted next"),
+	}, "thought", "reason")),
+}, "picks")
+
+func (r *suggestionsResponse) validate() error {
+	if len(r.Picks) == 0 {
+		return fmt.Errorf("no thought was picked")
+	}
+	return nil
+}
+
+// SuggestNext picks up to n of thoughts that would make good posts next,
+// favouring the categories in gaps, each with a one-line reason.
+func (a *BacklogAnalystAgent) SuggestNext(ctx context.Context, gaps []CategoryGap, thoughts []*models.Thought, n int) ([]Suggestion, error) {
+	if len(thoughts) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	var gapList strings.Builder
+	for _, gap := range gaps {
+		if gap.LastPublished.IsZero() {
+			fmt.Fprintf(&gapList, "- %s: never posted about\n", gap.Category)
+			continue
+		}
+		fmt.Fprintf(&gapList, "- %s: last posted about %s\n", gap.Category, gap.LastPublished.Format("Jan 2"))
+	}
+	if gapList.Len() == 0 {
+		gapList.WriteString("- none\n")
+	}
+
+	var thoughtList strings.Builder
+	for i, thought := range thoughts {
+		content := []rune(strings.Join(strings.Fields(thought.Content), " "))
+		if len(content) > maxSuggestionContent {
+			content = append(content[:maxSuggestionContent], '…')
+		}
+		fmt.Fprintf(&thoughtList, "%d. [%s, captured %s] %s\n", i+1, thought.Category, thought.Timestamp.Format("Jan 2"), string(content))
+	}
+
+	prompt := fmt.Sprintf(`You are helping a LinkedIn author decide what to post about next.
+
+Categories they haven't posted about lately:
+%s
+Thoughts they captured a while ago and haven't used yet:
+"""
+%s"""
+
+Pick up to %d of these thoughts that would make the strongest posts next.
+Prefer thoughts that fill the gaps above, that still feel timely and that
+have a concrete story, lesson or number in them. Skip thoughts too thin to
+carry a post on their own. Give each pick a short reason, such as "Fills the
+technical gap with a concrete debugging story".`, gapList.String(), thoughtList.String(), n)
+
+	var response suggestionsResponse
+	if err := completeJSON(ctx, a.llm, prompt, 400, suggestionsSchema, &response); err != nil {
+		return nil, err
+	}
+
+	var suggestions []Suggestion
+	picked := make(map[int]bool)
+	for _, pick := range response.Picks {
+		if pick.Thought < 1 || pick.Thought > len(thoughts) || picked[pick.Thought] {
+			continue
+		}
+		picked[pick.Thought] = true
+		suggestions = append(suggestions, Suggestion{Thought: thoughts[pick.Thought-1], Reason: strings.TrimSpace(pick.Reason)})
+		if len(suggestions) == n {
+			break
+		}
+	}
+
+	if len(suggestions) == 0 {
+		return nil, fmt.Errorf("backlog suggestions pick no listed thought")
+	}
+
+	return suggestions, nil
+}
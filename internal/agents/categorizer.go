@@ -1,191 +1,324 @@
 package agents
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
+	"log/slog"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/prompts"
 )
 
-type CategorizerAgent struct {
-	apiKey     string
-	httpClient *http.Client
-}
+const defaultCategorizerMaxTokens = 500
 
-type anthropicRequest struct {
-	Model     string              `json:"model"`
-	MaxTokens int                 `json:"max_tokens"`
-	Messages  []anthropicMessage  `json:"messages"`
-}
+// ErrNoCategory is returned when the categorizer's response names no
+// category, so the thought would only be filed as uncategorized.
+var ErrNoCategory = errors.New("categorizer response has no category")
 
-type anthropicMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+// categorization is the categorizer's answer for one thought. Thought
+// numbers it within a batch.
+type categorization struct {
+	Thought   int      `json:"thought,omitempty"`
+	Category  string   `json:"category"`
+	Tags      []string `json:"tags"`
+	Readiness string   `json:"readiness"`
+	Reason    string   `json:"reason"`
 }
 
-type anthropicResponse struct {
-	Content []anthropicContent `json:"content"`
-	Error   *anthropicError    `json:"error,omitempty"`
+var categorizationProperties = map[string]Schema{
+	"category":  stringSchema("The one category the thought belongs to"),
+	"tags":      arraySchema("2-4 relevant topic keywords", stringSchema("")),
+	"readiness": enumSchema("Whether the thought is ready to draft", "draft_ready", "needs_brainstorm"),
+	"reason":    stringSchema("A brief explanation why"),
 }
 
-type anthropicContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+var categorizationSchema = objectSchema(categorizationProperties, "category", "tags", "readiness", "reason")
+
+func (c *categorization) validate() error {
+	if strings.TrimSpace(c.Category) == "" {
+		return ErrNoCategory
+	}
+	return nil
 }
 
-type anthropicError struct {
-	Type    string `json:"type"`
-	Message string `json:"message"`
+type batchCategorization struct {
+	Thoughts []categorization `json:"thoughts"`
 }
 
-func NewCategorizerAgent(apiKey string) *CategorizerAgent {
-	if apiKey == "" {
-		log.Fatal("ANTHROPIC_API_KEY is required")
+var batchCategorizationSchema = func() Schema {
+	properties := map[string]Schema{"thought": integerSchema("The number of the thought")}
+	for name, property := range categorizationProperties {
+		properties[name] = property
 	}
-
-	return &CategorizerAgent{
-		apiKey:     apiKey,
-		httpClient: &http.Client{},
+	return objectSchema(map[string]Schema{
+		"thoughts": arraySchema("One answer per thought, in the same order", objectSchema(properties, "thought", "category", "tags", "readiness", "reason")),
+	}, "thoughts")
+}()
+
+func (b *batchCategorization) validate() error {
+	if len(b.Thoughts) == 0 {
+		return fmt.Errorf("no thought was categorized")
 	}
+	return nil
 }
 
-func (a *CategorizerAgent) CategorizeThought(ctx context.Context, thought *models.Thought) error {
-
-	prompt := fmt.Sprintf(`You are an AI assistant helping to categorize LinkedIn content ideas.
+type CategorizerAgent struct {
+	llm          LLMProvider
+	maxTokens    int
+	prompts      *prompts.Store
+	categoryRepo *database.CategoryRepository
+	batchWindow  time.Duration
+	batchSize    int
+
+	mu      sync.Mutex
+	pending []*batchRequest
+	timer   *time.Timer
+}
 
-Analyze this thought and provide:
-1. Category (choose ONE): technical, business, learning, product_update, personal, industry_insight, milestone
-2. Topic tags (2-4 relevant keywords)
-3. Content readiness (choose ONE): draft_ready, needs_brainstorm
+// batchRequest is a thought waiting for the next batch, and where its
+// categorization is sent.
+type batchRequest struct {
+	ctx     context.Context
+	thought models.Thought
+	done    chan batchResult
+}
 
-Thought: "%s"
+type batchResult struct {
+	thought models.Thought
+	err     error
+}
 
-Respond in this exact format:
-CATEGORY: [category]
-TAGS: [tag1, tag2, tag3]
-READINESS: [draft_ready or needs_brainstorm]
-REASON: [brief explanation why]`, thought.Content)
+// NewCategorizerAgent caps each categorization at maxTokens, or 500 when
+// maxTokens is zero. The prompt comes from store, and thoughts are only
+// filed under the categories in categoryRepo. CategorizeBatched collects
+// thoughts for batchWindow, or until batchSize of them wait, and
+// categorizes them in one call; a zero batchWindow categorizes each on its
+// own.
+func NewCategorizerAgent(llm LLMProvider, maxTokens int, store *prompts.Store, categoryRepo *database.CategoryRepository, batchWindow time.Duration, batchSize int) *CategorizerAgent {
+	if llm == nil {
+		slog.Error("LLM provider is required")
+		os.Exit(1)
+	}
 
-	reqBody := anthropicRequest{
-		Model:     "claude-sonnet-4-5-20250929",
-		MaxTokens: 500,
-		Messages: []anthropicMessage{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+	if maxTokens <= 0 {
+		maxTokens = defaultCategorizerMaxTokens
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+	return &CategorizerAgent{
+		llm:          llm,
+		maxTokens:    maxTokens,
+		prompts:      store,
+		categoryRepo: categoryRepo,
+		batchWindow:  batchWindow,
+		batchSize:    batchSize,
 	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
+// CategorizeThought files thought by the categories' rules when exactly one
+// category's rules match it, and asks the categorizer otherwise.
+func (a *CategorizerAgent) CategorizeThought(ctx context.Context, thought *models.Thought) error {
+	categories, err := a.categoryRepo.List(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", a.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
-
-	resp, err := a.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to call Anthropic API: %w", err)
+	if matchRules(thought, categories) {
+		slog.InfoContext(ctx, "Categorized thought by rule", "category", thought.Category)
+		return nil
 	}
-	defer resp.Body.Close()
+	choices, known := describeCategories(categories)
 
-	body, err := io.ReadAll(resp.Body)
+	prompt, err := a.prompts.Render(ctx, prompts.Categorize, prompts.CategorizeData{
+		Thought:    thought.Content,
+		Categories: choices,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Anthropic API error (status %d): %s", resp.StatusCode, string(body))
-		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	var response categorization
+	if err := completeJSON(ctx, a.llm, prompt, a.maxTokens, categorizationSchema, &response); err != nil {
+		return err
 	}
 
-	var apiResp anthropicResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
+	a.apply(ctx, thought, response, known)
+	return nil
+}
+
+// describeCategories lists categories for the prompt, and the names a
+// response may use.
+func describeCategories(categories []*models.Category) (string, map[string]bool) {
+	var choices []string
+	known := make(map[string]bool)
+	for _, category := range categories {
+		known[category.Name] = true
+		if category.Description != "" {
+			choices = append(choices, fmt.Sprintf("%s (%s)", category.Name, category.Description))
+		} else {
+			choices = append(choices, category.Name)
+		}
 	}
 
-	if apiResp.Error != nil {
-		return fmt.Errorf("API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
+	return strings.Join(choices, ", "), known
+}
+
+// apply files thought as response says.
+func (a *CategorizerAgent) apply(ctx context.Context, thought *models.Thought, response categorization, known map[string]bool) {
+	category := models.NormalizeCategory(response.Category)
+	if category == "" {
+		category = "uncategorized"
 	}
 
-	var responseText string
-	if len(apiResp.Content) > 0 && apiResp.Content[0].Type == "text" {
-		responseText = apiResp.Content[0].Text
-	} else {
-		return fmt.Errorf("unexpected response format")
+	var tags []string
+	for _, tag := range response.Tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
 	}
+	if len(tags) == 0 {
+		tags = []string{"general"}
+	}
+
+	readiness := strings.ToLower(strings.TrimSpace(response.Readiness))
 
-	category, tags, readiness := a.parseResponse(responseText)
+	if category != "uncategorized" && !known[category] {
+		slog.WarnContext(ctx, "Categorizer chose an unknown category", "category", category)
+		category = "uncategorized"
+	}
 
 	thought.Category = category
 	thought.TopicTags = tags
-	
+
 	if readiness == "draft_ready" {
 		thought.Status = "raw"
 	} else {
 		thought.Status = "raw"
 	}
+}
 
-	return nil
+// CategorizeBatched categorizes thought together with the other thoughts
+// that arrive within the batch window, to save calls when several are
+// captured in a row. It waits for the batch, so it suits callers that
+// categorize concurrently; a caller going through thoughts one by one
+// should use CategorizeThought.
+func (a *CategorizerAgent) CategorizeBatched(ctx context.Context, thought *models.Thought) error {
+	if a.batchWindow <= 0 || a.batchSize < 2 {
+		return a.CategorizeThought(ctx, thought)
+	}
+
+	// A thought the rules file right away doesn't wait for a batch.
+	if categories, err := a.categoryRepo.List(ctx); err == nil && matchRules(thought, categories) {
+		slog.InfoContext(ctx, "Categorized thought by rule", "category", thought.Category)
+		return nil
+	}
+
+	request := &batchRequest{ctx: ctx, thought: *thought, done: make(chan batchResult, 1)}
+
+	a.mu.Lock()
+	a.pending = append(a.pending, request)
+	if len(a.pending) >= a.batchSize {
+		batch := a.pending
+		a.pending = nil
+		a.timer.Stop()
+		go a.runBatch(batch)
+	} else if len(a.pending) == 1 {
+		a.timer = time.AfterFunc(a.batchWindow, a.flush)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case result := <-request.done:
+		if result.err != nil {
+			return result.err
+		}
+		thought.Category = result.thought.Category
+		thought.TopicTags = result.thought.TopicTags
+		thought.Status = result.thought.Status
+		return nil
+	}
 }
 
-func (a *CategorizerAgent) parseResponse(response string) (string, []string, string) {
-	var category string
-	var tags []string
-	var readiness string
-
-	lines := strings.Split(response, "\n")
-	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		
-		if strings.HasPrefix(line, "CATEGORY:") {
-			category = strings.TrimSpace(strings.TrimPrefix(line, "CATEGORY:"))
-			category = strings.ToLower(category)
+func (a *CategorizerAgent) flush() {
+	a.mu.Lock()
+	batch := a.pending
+	a.pending = nil
+	a.mu.Unlock()
+
+	if len(batch) > 0 {
+		a.runBatch(batch)
+	}
+}
+
+// runBatch categorizes a batch in one call. A thought the response leaves
+// out is categorized on its own.
+func (a *CategorizerAgent) runBatch(batch []*batchRequest) {
+	// The batch outlives any one of its callers, but keeps the first
+	// one's trace.
+	ctx := context.WithoutCancel(batch[0].ctx)
+
+	if len(batch) == 1 {
+		a.finish(ctx, batch[0], nil, nil)
+		return
+	}
+
+	categories, err := a.categoryRepo.List(ctx)
+	if err != nil {
+		for _, request := range batch {
+			request.done <- batchResult{err: err}
 		}
-		
-		if strings.HasPrefix(line, "TAGS:") {
-			tagsStr := strings.TrimSpace(strings.TrimPrefix(line, "TAGS:"))
-			tagsStr = strings.Trim(tagsStr, "[]")
-			tagList := strings.Split(tagsStr, ",")
-			for _, tag := range tagList {
-				tag = strings.TrimSpace(tag)
-				tag = strings.ToLower(tag)
-				if tag != "" {
-					tags = append(tags, tag)
-				}
+		return
+	}
+	choices, known := describeCategories(categories)
+
+	var thoughts strings.Builder
+	for i, request := range batch {
+		fmt.Fprintf(&thoughts, "Thought %d: %q\n", i+1, request.thought.Content)
+	}
+
+	prompt, err := a.prompts.Render(ctx, prompts.CategorizeBatch, prompts.CategorizeBatchData{
+		Thoughts:   thoughts.String(),
+		Categories: choices,
+	})
+	if err == nil {
+		var response batchCategorization
+		err = completeJSON(ctx, a.llm, prompt, a.maxTokens*len(batch), batchCategorizationSchema, &response)
+		if err == nil {
+			answers := make(map[int]*categorization)
+			for i := range response.Thoughts {
+				answers[response.Thoughts[i].Thought] = &response.Thoughts[i]
 			}
-		}
-		
-		if strings.HasPrefix(line, "READINESS:") {
-			readiness = strings.TrimSpace(strings.TrimPrefix(line, "READINESS:"))
-			readiness = strings.ToLower(readiness)
+			slog.InfoContext(ctx, "Categorized thoughts in a batch", "thoughts", len(batch), "answered", len(answers))
+			for i, request := range batch {
+				a.finish(ctx, request, answers[i+1], known)
+			}
+			return
 		}
 	}
 
-	if category == "" {
-		category = "uncategorized"
+	for _, request := range batch {
+		request.done <- batchResult{err: err}
 	}
-	if len(tags) == 0 {
-		tags = []string{"general"}
-	}
-	if readiness == "" {
-		readiness = "needs_brainstorm"
+}
+
+// finish files request's thought as its answer in the batch response
+// says, or on its own when the answer is missing or names no category.
+func (a *CategorizerAgent) finish(ctx context.Context, request *batchRequest, response *categorization, known map[string]bool) {
+	thought := request.thought
+
+	var err error
+	if response != nil && response.validate() == nil {
+		a.apply(ctx, &thought, *response, known)
+	} else {
+		err = a.CategorizeThought(request.ctx, &thought)
 	}
 
-	return category, tags, readiness
-}
\ No newline at end of file
+	request.done <- batchResult{thought: thought, err: err}
+}
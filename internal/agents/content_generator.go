@@ -1,35 +1,105 @@
 package agents
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
+	"log/slog"
+	"os"
 	"strings"
 
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/prompts"
 )
 
+const defaultGeneratorMaxTokens = 2000
+
+// Bounds and default of how many variations a generation writes.
+const (
+	MinVariations     = 1
+	MaxVariations     = 5
+	DefaultVariations = 3
+)
+
+// variationAngles are the angles variations take, in the order they are
+// handed out.
+var variationAngles = []string{
+	"Story-driven approach",
+	"Insight/lesson-focused",
+	"Data/results-focused",
+	"Contrarian take on a common belief",
+	"Practical how-to with concrete steps",
+}
+
+// Variation is a generated post. FirstComment is posted under it right
+// after publishing, and is empty when the model proposed none.
+type Variation struct {
+	Content      string `json:"content"`
+	FirstComment string `json:"first_comment"`
+}
+
+type variationsResponse struct {
+	Variations []Variation `json:"variations"`
+}
+
+var variationsSchema = objectSchema(map[string]Schema{
+	"variations": arraySchema("The variations, in the order asked for", objectSchema(map[string]Schema{
+		"content":       stringSchema("The post"),
+		"first_comment": stringSchema("The first comment to post under it"),
+	}, "content", "first_comment")),
+}, "variations")
+
+func (r *variationsResponse) validate() error {
+	var variations []Variation
+	for _, variation := range r.Variations {
+		variation.Content = strings.TrimSpace(variation.Content)
+		variation.FirstComment = strings.TrimSpace(variation.FirstComment)
+		if variation.Content != "" {
+			variations = append(variations, variation)
+		}
+	}
+	if len(variations) == 0 {
+		return fmt.Errorf("no variation has any content")
+	}
+	r.Variations = variations
+	return nil
+}
+
+// PartialVariations reads the posts of a generation still being streamed,
+// the last of them possibly cut short.
+func PartialVariations(text string) []string {
+	return partialStrings(text, "content")
+}
+
 type ContentGeneratorAgent struct {
-	apiKey     string
-	httpClient *http.Client
+	llm       LLMProvider
+	maxTokens int
+	prompts   *prompts.Store
 }
 
-func NewContentGeneratorAgent(apiKey string) *ContentGeneratorAgent {
-	if apiKey == "" {
-		log.Fatal("ANTHROPIC_API_KEY is required")
+// NewContentGeneratorAgent caps each generation at maxTokens, or 2000 when
+// maxTokens is zero. The post and brainstorm prompts come from store.
+func NewContentGeneratorAgent(llm LLMProvider, maxTokens int, store *prompts.Store) *ContentGeneratorAgent {
+	if llm == nil {
+		slog.Error("LLM provider is required")
+		os.Exit(1)
+	}
+
+	if maxTokens <= 0 {
+		maxTokens = defaultGeneratorMaxTokens
 	}
 
 	return &ContentGeneratorAgent{
-		apiKey:     apiKey,
-		httpClient: &http.Client{},
+		llm:       llm,
+		maxTokens: maxTokens,
+		prompts:   store,
 	}
 }
 
-func (a *ContentGeneratorAgent) GeneratePost(ctx context.Context, thoughts []*models.Thought, userStyle string) ([]string, error) {
+// GeneratePost writes count variations from thoughts. Top-performing
+// published posts, if any, are shown to the model as examples of what
+// resonates.
+func (a *ContentGeneratorAgent) GeneratePost(ctx context.Context, thoughts []*models.Thought, userStyle string, examples []*models.Post, count int) ([]Variation, error) {
 	if len(thoughts) == 0 {
 		return nil, fmt.Errorf("no thoughts provided")
 	}
@@ -38,206 +108,335 @@ func (a *ContentGeneratorAgent) GeneratePost(ctx context.Context, thoughts []*mo
 	for i, thought := range thoughts {
 		thoughtsText += fmt.Sprintf("\nThought %d: %s", i+1, thought.Content)
 	}
-	prompt := fmt.Sprintf(`You are a LinkedIn ghostwriter helping create authentic, engaging posts.
 
-Input thoughts:%s
+	return a.writeVariations(ctx, "Input thoughts:"+thoughtsText, userStyle, examples, count)
+}
 
-Create a LinkedIn post that:
-1. Sounds natural and conversational (not corporate or salesy)
-2. Starts with a strong hook that grabs attention
-3. Uses short paragraphs and line breaks for readability
-4. Includes a clear insight or takeaway
-5. Ends with engagement (question, call to action, or thought-provoking statement)
-6. Is between 150-300 words
-7. Uses emojis sparingly (1-2 max)
-
-Writing style guidelines:
-- Be authentic and personal
-- Use "I" and "we" pronouns
-- Share specific details and numbers when available
-- Avoid buzzwords and jargon
-- Keep it concise and punchy
-
-Generate 3 different variations with different angles:
-- Variation 1: Story-driven approach
-- Variation 2: Insight/lesson-focused
-- Variation 3: Data/results-focused
-
-Format your response as:
-===VARIATION 1===
-[post content]
-
-===VARIATION 2===
-[post content]
-
-===VARIATION 3===
-[post content]`, thoughtsText)
-
-	responseText, err := a.callClaude(ctx, prompt)
-	if err != nil {
-		return nil, err
+// DevelopAngle writes count variations from one of the angles a
+// brainstorm session suggested for topic, using the session's exploration
+// as background.
+func (a *ContentGeneratorAgent) DevelopAngle(ctx context.Context, topic, angle, exploration, userStyle string, examples []*models.Post, count int) ([]Variation, error) {
+	if strings.TrimSpace(angle) == "" {
+		return nil, fmt.Errorf("no angle provided")
 	}
 
-	variations := a.parseVariations(responseText)
-
-	if len(variations) == 0 {
-		return nil, fmt.Errorf("failed to generate variations")
+	input := fmt.Sprintf("Topic: %s\n\nChosen angle (build the post around this):\n%s", topic, angle)
+	if exploration != "" {
+		input += fmt.Sprintf("\n\nBackground from an earlier brainstorm:\n%s", exploration)
 	}
 
-	return variations, nil
+	return a.writeVariations(ctx, input, userStyle, examples, count)
 }
 
-func (a *ContentGeneratorAgent) GenerateBrainstorm(ctx context.Context, thought *models.Thought) (string, []string, error) {
+// GenerateRecap writes count "what we shipped" variations from recap, a
+// description of the work completed in a sprint or release called title.
+func (a *ContentGeneratorAgent) GenerateRecap(ctx context.Context, title, recap, userStyle string, examples []*models.Post, count int) ([]Variation, error) {
+	if strings.TrimSpace(recap) == "" {
+		return nil, fmt.Errorf("no recap provided")
+	}
 
-	prompt := fmt.Sprintf(`You are helping brainstorm LinkedIn content ideas.
+	input := fmt.Sprintf(`Write a "what we shipped this sprint" recap of %s. Lead with the most
+meaningful highlight for users rather than listing everything, mention the
+overall scope, and credit the team.
 
-The user shared this incomplete thought:
-"%s"
+What was completed:
+%s`, title, recap)
 
-Help develop this into a complete LinkedIn post idea by:
-1. Exploring different angles to approach this topic
-2. Identifying what additional context or examples would strengthen it
-3. Suggesting 3-4 specific directions this could go
+	return a.writeVariations(ctx, input, userStyle, examples, count)
+}
 
-Respond in this format:
-EXPLORATION:
-[2-3 paragraphs exploring the topic and why it matters]
+// ClampVariations brings count within MinVariations and MaxVariations, or
+// to DefaultVariations when it is zero.
+func ClampVariations(count int) int {
+	switch {
+	case count == 0:
+		return DefaultVariations
+	case count < MinVariations:
+		return MinVariations
+	case count > MaxVariations:
+		return MaxVariations
+	}
+	return count
+}
 
-KEY ANGLES:
-1. [Angle 1 description]
-2. [Angle 2 description]
-3. [Angle 3 description]
-4. [Angle 4 description]
+func (a *ContentGeneratorAgent) writeVariations(ctx context.Context, input, userStyle string, examples []*models.Post, count int) ([]Variation, error) {
+	count = ClampVariations(count)
 
-QUESTIONS TO CONSIDER:
-- [Question 1]
-- [Question 2]
-- [Question 3]`, thought.Content)
+	var angles strings.Builder
+	for i := range count {
+		fmt.Fprintf(&angles, "- Variation %d: %s\n", i+1, variationAngles[i])
+	}
+	if count == 1 {
+		angles.Reset()
+		for _, angle := range variationAngles {
+			fmt.Fprintf(&angles, "- %s\n", angle)
+		}
+	}
 
-	responseText, err := a.callClaude(ctx, prompt)
+	prompt, err := a.prompts.Render(ctx, prompts.Generate, prompts.GenerateData{
+		Input:    input,
+		Style:    userStyle,
+		Examples: formatPerformanceExamples(examples),
+		Count:    count,
+		Angles:   angles.String(),
+		Emoji:    emojiRule(ctx),
+		Length:   postLength(ctx).String(),
+	})
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
+	prompt += templateSection(ctx) + languageSection(ctx)
 
-	brainstormContent, angles := a.parseBrainstorm(responseText)
+	var response variationsResponse
+	if err := a.completeJSON(ctx, prompt, variationsSchema, &response); err != nil {
+		return nil, fmt.Errorf("failed to generate variations: %w", err)
+	}
 
-	return brainstormContent, angles, nil
+	variations := response.Variations
+	if len(variations) > count {
+		variations = variations[:count]
+	}
+	for i := range variations {
+		variations[i].Content = cleanPost(ctx, variations[i].Content)
+		variations[i].FirstComment = cleanPost(ctx, variations[i].FirstComment)
+	}
+	a.fitLength(ctx, variations)
+
+	return variations, nil
 }
 
-func (a *ContentGeneratorAgent) callClaude(ctx context.Context, prompt string) (string, error) {
-	reqBody := anthropicRequest{
-		Model:     "claude-sonnet-4-5-20250929",
-		MaxTokens: 2000,
-		Messages: []anthropicMessage{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+// Carousel is a multi-slide post: Caption is the commentary published above
+// the document, Slides the text of each page in order.
+type Carousel struct {
+	Caption string   `json:"caption"`
+	Slides  []string `json:"slides"`
+}
+
+var carouselSchema = objectSchema(map[string]Schema{
+	"caption": stringSchema("The caption published above the carousel"),
+	"slides":  arraySchema("The text of each slide, in order", stringSchema("")),
+}, "caption", "slides")
+
+func (c *Carousel) validate() error {
+	c.Caption = strings.TrimSpace(c.Caption)
+	if c.Caption == "" {
+		return fmt.Errorf("caption is empty")
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+	c.Slides = trimList(c.Slides)
+	if len(c.Slides) < minCarouselSlides {
+		return fmt.Errorf("carousel has %d slides, at least %d are needed", len(c.Slides), minCarouselSlides)
 	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+const (
+	minCarouselSlides = 4
+	maxCarouselSlides = 10
+)
+
+// GenerateCarousel writes a 6-8 slide carousel from thoughts.
+func (a *ContentGeneratorAgent) GenerateCarousel(ctx context.Context, thoughts []*models.Thought, userStyle string) (*Carousel, error) {
+	if len(thoughts) == 0 {
+		return nil, fmt.Errorf("no thoughts provided")
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", a.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
+	var thoughtsText string
+	for i, thought := range thoughts {
+		thoughtsText += fmt.Sprintf("\nThought %d: %s", i+1, thought.Content)
+	}
 
-	resp, err := a.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to call Anthropic API: %w", err)
+	var styleSection string
+	if userStyle != "" {
+		styleSection = fmt.Sprintf("\nThe author's own writing style (match its tone and vocabulary):\n%s\n", userStyle)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+	prompt := fmt.Sprintf(`You are a LinkedIn ghostwriter turning the author's thoughts into a carousel post.
+
+Input thoughts:%s
+%s
+A carousel is a swipeable document, one slide per page. Write 6-8 slides:
+- Slide 1 is the hook: a bold promise or question, at most 12 words
+- The middle slides each make exactly one point, as a short title line followed by 1-3 short sentences
+- The last slide sums up and asks readers to comment or follow
+- Each slide is at most 40 words
+- No emojis, hashtags or markdown on the slides
+
+Also write the caption that is published above the carousel: 2-4 short lines that
+tease the content and invite people to swipe. %s.`, thoughtsText, styleSection, captionEmojiRule(ctx))
+	prompt += languageSection(ctx)
+
+	carousel := &Carousel{}
+	if err := a.completeJSON(ctx, prompt, carouselSchema, carousel); err != nil {
+		return nil, fmt.Errorf("failed to generate carousel: %w", err)
+	}
+	if len(carousel.Slides) > maxCarouselSlides {
+		carousel.Slides = carousel.Slides[:maxCarouselSlides]
 	}
+	carousel.Caption = cleanPost(ctx, carousel.Caption)
+
+	return carousel, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Anthropic API error (status %d): %s", resp.StatusCode, string(body))
-		return "", fmt.Errorf("API request failed with status %d", resp.StatusCode)
+// GeneratePoll writes a LinkedIn poll from thoughts. The returned caption
+// is the commentary published above the poll.
+func (a *ContentGeneratorAgent) GeneratePoll(ctx context.Context, thoughts []*models.Thought, userStyle string) (string, *models.Poll, error) {
+	if len(thoughts) == 0 {
+		return "", nil, fmt.Errorf("no thoughts provided")
 	}
 
-	var apiResp anthropicResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	var thoughtsText string
+	for i, thought := range thoughts {
+		thoughtsText += fmt.Sprintf("\nThought %d: %s", i+1, thought.Content)
 	}
 
-	if apiResp.Error != nil {
-		return "", fmt.Errorf("API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
+	var styleSection string
+	if userStyle != "" {
+		styleSection = fmt.Sprintf("\nThe author's own writing style (match its tone and vocabulary):\n%s\n", userStyle)
 	}
 
-	if len(apiResp.Content) > 0 && apiResp.Content[0].Type == "text" {
-		return apiResp.Content[0].Text, nil
+	prompt := fmt.Sprintf(`You are a LinkedIn ghostwriter turning the author's thoughts into a poll.
+
+Input thoughts:%s
+%s
+Write a poll that invites the author's network to share where they stand:
+- The question is at most %d characters and has no obvious right answer
+- Give %d-%d answer options, each at most %d characters, distinct and covering the realistic positions
+- The caption above the poll is 2-5 short lines: the author's own take or story, then a nudge to vote and explain in the comments
+- %s`, thoughtsText, styleSection, models.PollMaxQuestionChars, models.PollMinOptions, models.PollMaxOptions, models.PollMaxOptionChars, captionEmojiRule(ctx))
+	prompt += languageSection(ctx)
+
+	var response pollResponse
+	if err := a.completeJSON(ctx, prompt, pollSchema, &response); err != nil {
+		return "", nil, fmt.Errorf("failed to generate poll: %w", err)
 	}
 
-	return "", fmt.Errorf("unexpected response format")
+	return cleanPost(ctx, response.Caption), &models.Poll{Question: response.Question, Options: response.Options, Duration: models.DefaultPollDuration}, nil
 }
 
-func (a *ContentGeneratorAgent) parseVariations(response string) []string {
-	var variations []string
+type pollResponse struct {
+	Caption  string   `json:"caption"`
+	Question string   `json:"question"`
+	Options  []string `json:"options"`
+}
 
-	parts := strings.Split(response, "===VARIATION")
+var pollSchema = objectSchema(map[string]Schema{
+	"caption":  stringSchema("The commentary published above the poll"),
+	"question": stringSchema("The poll question"),
+	"options":  arraySchema("The answer options", stringSchema("")),
+}, "caption", "question", "options")
 
-	for _, part := range parts {
-		if strings.TrimSpace(part) == "" {
-			continue
-		}
+func (r *pollResponse) validate() error {
+	r.Caption = strings.TrimSpace(r.Caption)
+	if r.Caption == "" {
+		return fmt.Errorf("caption is empty")
+	}
 
-		lines := strings.Split(part, "\n")
-		if len(lines) < 2 {
-			continue
-		}
+	r.Question = strings.TrimSpace(r.Question)
+	r.Options = trimList(r.Options)
 
-		content := strings.Join(lines[1:], "\n")
-		content = strings.TrimSpace(content)
+	poll := models.Poll{Question: r.Question, Options: r.Options, Duration: models.DefaultPollDuration}
+	return poll.Validate()
+}
+
+// maxExampleChars keeps few-shot examples from crowding out the thoughts.
+const maxExampleChars = 1500
 
-		if content != "" {
-			variations = append(variations, content)
+func formatPerformanceExamples(examples []*models.Post) string {
+	if len(examples) == 0 {
+		return ""
+	}
+
+	section := "\nThe author's best performing past posts on LinkedIn. Learn from their format, hook and length, but do not copy their content:\n"
+	for i, example := range examples {
+		content := example.Content
+		if runes := []rune(content); len(runes) > maxExampleChars {
+			content = string(runes[:maxExampleChars]) + "..."
 		}
+		section += fmt.Sprintf("\n--- Example %d (%d likes, %d comments) ---\n%s\n", i+1, example.Metrics["likes"], example.Metrics["comments"], content)
 	}
 
-	return variations
+	return section
 }
 
-func (a *ContentGeneratorAgent) parseBrainstorm(response string) (string, []string) {
-	var brainstormContent string
-	var angles []string
+func (a *ContentGeneratorAgent) GenerateBrainstorm(ctx context.Context, thought *models.Thought) (string, []string, error) {
+	prompt, err := a.prompts.Render(ctx, prompts.Brainstorm, prompts.ThoughtData{Thought: thought.Content})
+	if err != nil {
+		return "", nil, err
+	}
 
-	if idx := strings.Index(response, "EXPLORATION:"); idx != -1 {
-		endIdx := strings.Index(response, "KEY ANGLES:")
-		if endIdx == -1 {
-			endIdx = len(response)
-		}
-		brainstormContent = strings.TrimSpace(response[idx+len("EXPLORATION:"):endIdx])
+	var response brainstormResponse
+	if err := a.completeJSON(ctx, prompt, brainstormSchema, &response); err != nil {
+		return "", nil, fmt.Errorf("failed to brainstorm: %w", err)
 	}
 
-	if idx := strings.Index(response, "KEY ANGLES:"); idx != -1 {
-		endIdx := strings.Index(response, "QUESTIONS TO CONSIDER:")
-		if endIdx == -1 {
-			endIdx = len(response)
-		}
-		anglesSection := response[idx+len("KEY ANGLES:"):endIdx]
-		lines := strings.Split(anglesSection, "\n")
-
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if len(line) > 3 && line[0] >= '1' && line[0] <= '9' && line[1] == '.' {
-				angle := strings.TrimSpace(line[2:])
-				if angle != "" {
-					angles = append(angles, angle)
-				}
-			}
-		}
+	return response.Exploration, response.Angles, nil
+}
+
+type brainstormResponse struct {
+	Exploration string   `json:"exploration"`
+	Angles      []string `json:"angles"`
+	Questions   []string `json:"questions"`
+}
+
+var brainstormSchema = objectSchema(map[string]Schema{
+	"exploration": stringSchema("2-3 paragraphs exploring the topic and why it matters"),
+	"angles":      arraySchema("3-4 specific directions the post could take, one sentence or two each", stringSchema("")),
+	"questions":   arraySchema("Questions the author should consider", stringSchema("")),
+}, "exploration", "angles")
+
+func (r *brainstormResponse) validate() error {
+	r.Exploration = strings.TrimSpace(r.Exploration)
+	r.Angles = trimList(r.Angles)
+	if len(r.Angles) == 0 {
+		return fmt.Errorf("no angles")
+	}
+	return nil
+}
+
+func (a *ContentGeneratorAgent) RevisePost(ctx context.Context, post *models.Post, feedback string) (string, error) {
+	if strings.TrimSpace(feedback) == "" {
+		return "", fmt.Errorf("no feedback provided")
 	}
 
-	return brainstormContent, angles
-}
\ No newline at end of file
+	prompt := fmt.Sprintf(`You are a LinkedIn ghostwriter revising a draft based on the author's feedback.
+
+Current draft:
+"""
+%s
+"""
+
+Author's feedback:
+"%s"
+
+Rewrite the draft so it addresses the feedback while keeping everything the feedback doesn't mention.
+Keep it natural and conversational, with short paragraphs and a strong hook.%s
+
+Respond with ONLY the revised post content, no preamble or explanation.`, post.Content, feedback, languageInstruction(post.Language))
+
+	responseText, err := a.complete(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	revised := cleanPost(ctx, strings.TrimSpace(responseText))
+	if revised == "" {
+		return "", fmt.Errorf("failed to generate revision")
+	}
+
+	return revised, nil
+}
+
+// cleanPost turns text the model wrote for a post into canonical text,
+// following the emoji policy ctx asks for.
+func cleanPost(ctx context.Context, text string) string {
+	return applyEmojiPolicy(ctx, format.FromMarkdown(text))
+}
+
+func (a *ContentGeneratorAgent) complete(ctx context.Context, prompt string) (string, error) {
+	return a.llm.Complete(ctx, prompt, a.maxTokens)
+}
+
+func (a *ContentGeneratorAgent) completeJSON(ctx context.Context, prompt string, schema Schema, out structured) error {
+	return completeJSON(ctx, a.llm, prompt, a.maxTokens, schema, out)
+}
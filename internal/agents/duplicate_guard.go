@@ -0,0 +1,144 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// embedBatchSize is how many published posts are embedded per query while
+// catching up on ones published before the guard ran.
+const embedBatchSize = 50
+
+// DuplicateGuard finds published posts that a post about to go out repeats.
+// With an embedder it compares meaning; without one it compares wording.
+type DuplicateGuard struct {
+	embedder  Embedder
+	postRepo  *database.PostRepository
+	threshold float64
+}
+
+// NewDuplicateGuard flags posts at least threshold similar, from 0 to 1, to
+// a published post. embedder may be nil.
+func NewDuplicateGuard(embedder Embedder, postRepo *database.PostRepository, threshold float64) *DuplicateGuard {
+	return &DuplicateGuard{
+		embedder:  embedder,
+		postRepo:  postRepo,
+		threshold: threshold,
+	}
+}
+
+// FindDuplicate returns the published post most similar to post when it is
+// over the threshold, or nil.
+func (g *DuplicateGuard) FindDuplicate(ctx context.Context, post *models.Post) (*database.SimilarPost, error) {
+	var match *database.SimilarPost
+	var err error
+	if g.embedder != nil {
+		match, err = g.closestByMeaning(ctx, post)
+	} else {
+		match, err = g.closestByWording(ctx, post)
+	}
+	if err != nil || match == nil || match.Similarity < g.threshold {
+		return nil, err
+	}
+
+	return match, nil
+}
+
+func (g *DuplicateGuard) closestByMeaning(ctx context.Context, post *models.Post) (*database.SimilarPost, error) {
+	if err := g.embedPublished(ctx); err != nil {
+		return nil, err
+	}
+
+	embedding, err := g.embedder.Embed(ctx, post.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed post: %w", err)
+	}
+
+	similar, err := g.postRepo.FindSimilarPublished(ctx, embedding, post.ID, 1)
+	if err != nil || len(similar) == 0 {
+		return nil, err
+	}
+
+	return similar[0], nil
+}
+
+// embedPublished embeds the published posts that don't have an embedding
+// yet, which after the first check is only the ones published since.
+func (g *DuplicateGuard) embedPublished(ctx context.Context) error {
+	for {
+		posts, err := g.postRepo.GetPublishedWithoutEmbedding(ctx, embedBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, published := range posts {
+			embedding, err := g.embedder.Embed(ctx, published.Content)
+			if err != nil {
+				return fmt.Errorf("failed to embed published post: %w", err)
+			}
+			if err := g.postRepo.UpdateEmbedding(ctx, published.ID, embedding); err != nil {
+				return err
+			}
+		}
+
+		if len(posts) < embedBatchSize {
+			return nil
+		}
+	}
+}
+
+func (g *DuplicateGuard) closestByWording(ctx context.Context, post *models.Post) (*database.SimilarPost, error) {
+	published, err := g.postRepo.GetByStatus(ctx, "published")
+	if err != nil {
+		return nil, err
+	}
+
+	words := wordCounts(post.Content)
+
+	var closest *database.SimilarPost
+	for _, candidate := range published {
+		if candidate.ID == post.ID {
+			continue
+		}
+		similarity := cosine(words, wordCounts(candidate.Content))
+		if closest == nil || similarity > closest.Similarity {
+			closest = &database.SimilarPost{Post: candidate, Similarity: similarity}
+		}
+	}
+
+	return closest, nil
+}
+
+// wordCounts counts the lowercased words of text, ignoring punctuation,
+// hashtag signs and emoji.
+func wordCounts(text string) map[string]float64 {
+	counts := make(map[string]float64)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		counts[word]++
+	}
+	return counts
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for word, count := range a {
+		dot += count * b[word]
+		normA += count * count
+	}
+	for _, count := range b {
+		normB += count * count
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
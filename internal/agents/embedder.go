@@ -0,0 +1,172 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOpenAIEmbeddingModel = "text-embedding-3-small"
+	defaultOllamaEmbeddingModel = "nomic-embed-text"
+)
+
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	Name() string
+	Model() string
+}
+
+// NewEmbedder returns nil without an error when no embedding provider is
+// configured, which disables similarity features.
+func NewEmbedder(cfg ProviderConfig) (Embedder, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case "":
+		return nil, nil
+
+	case "openai":
+		if cfg.OpenAIKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is required for openai embeddings")
+		}
+		baseURL := cfg.OpenAIBaseURL
+		if baseURL == "" {
+			baseURL = defaultOpenAIBaseURL
+		}
+		model := cfg.Model
+		if model == "" {
+			model = defaultOpenAIEmbeddingModel
+		}
+		return &openAIEmbedder{apiKey: cfg.OpenAIKey, baseURL: strings.TrimRight(baseURL, "/"), model: model, httpClient: cfg.httpClient("openai")}, nil
+
+	case "ollama":
+		baseURL := cfg.OllamaURL
+		if baseURL == "" {
+			baseURL = defaultOllamaURL
+		}
+		model := cfg.Model
+		if model == "" {
+			model = defaultOllamaEmbeddingModel
+		}
+		return &ollamaEmbedder{baseURL: strings.TrimRight(baseURL, "/"), model: model, httpClient: cfg.httpClient("ollama")}, nil
+	}
+
+	return nil, fmt.Errorf("unknown embedding provider: %s", cfg.Provider)
+}
+
+type openAIEmbedder struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+func (e *openAIEmbedder) Name() string {
+	return "openai"
+}
+
+func (e *openAIEmbedder) Model() string {
+	return e.model
+}
+
+func (e *openAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	reqBody := map[string]string{
+		"model": e.model,
+		"input": text,
+	}
+
+	var apiResp struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+
+	headers := map[string]string{"Authorization": "Bearer " + e.apiKey}
+	if err := postEmbedding(ctx, e.httpClient, e.Name(), e.baseURL+"/embeddings", headers, reqBody, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if len(apiResp.Data) == 0 || len(apiResp.Data[0].Embedding) == 0 {
+		return nil, fmt.Errorf("unexpected response format")
+	}
+
+	return apiResp.Data[0].Embedding, nil
+}
+
+type ollamaEmbedder struct {
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+func (e *ollamaEmbedder) Name() string {
+	return "ollama"
+}
+
+func (e *ollamaEmbedder) Model() string {
+	return e.model
+}
+
+func (e *ollamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	reqBody := map[string]string{
+		"model": e.model,
+		"input": text,
+	}
+
+	var apiResp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+
+	if err := postEmbedding(ctx, e.httpClient, e.Name(), e.baseURL+"/api/embed", nil, reqBody, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if len(apiResp.Embeddings) == 0 || len(apiResp.Embeddings[0]) == 0 {
+		return nil, fmt.Errorf("unexpected response format")
+	}
+
+	return apiResp.Embeddings[0], nil
+}
+
+func postEmbedding(ctx context.Context, httpClient *http.Client, provider, url string, headers map[string]string, reqBody, result any) error {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s embeddings API: %w: %w", provider, errTransport, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		slog.ErrorContext(ctx, "Embeddings API error", "provider", provider, "status", resp.StatusCode, "body", string(body))
+		return newAPIError(provider, resp)
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	duplicateSimilarity = 0.95
+	relatedSimilarity   = 0.80
+	maxRelatedThoughts  = 5
+)
+
+type EmbeddingAgent struct {
+	embedder    Embedder
+	thoughtRepo *database.ThoughtRepository
+}
+
+func NewEmbeddingAgent(embedder Embedder, thoughtRepo *database.ThoughtRepository) *EmbeddingAgent {
+	return &EmbeddingAgent{
+		embedder:    embedder,
+		thoughtRepo: thoughtRepo,
+	}
+}
+
+// Match embeds a new thought and fills in its related thoughts from the
+// workspace of its channel. When an existing thought is close enough to be a
+// near-duplicate it is also returned so the caller can skip saving.
+func (a *EmbeddingAgent) Match(ctx context.Context, thought *models.Thought) (*database.SimilarThought, error) {
+	embedding, err := a.embedder.Embed(ctx, thought.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed thought: %w", err)
+	}
+	thought.Embedding = embedding
+
+	similar, err := a.thoughtRepo.FindSimilar(ctx, embedding, thought.SlackChannelID, thought.ID, maxRelatedThoughts)
+	if err != nil {
+		return nil, err
+	}
+
+	thought.RelatedThoughts = []string{}
+	for _, match := range similar {
+		if match.Similarity >= relatedSimilarity {
+			thought.RelatedThoughts = append(thought.RelatedThoughts, match.Thought.ID)
+		}
+	}
+
+	if len(similar) > 0 && similar[0].Similarity >= duplicateSimilarity {
+		return similar[0], nil
+	}
+
+	return nil, nil
+}
+
+// SimilarTo returns the thoughts in the workspace of channelID closest in
+// meaning to free text.
+func (a *EmbeddingAgent) SimilarTo(ctx context.Context, channelID, text string, limit int) ([]*database.SimilarThought, error) {
+	embedding, err := a.embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	return a.thoughtRepo.FindSimilar(ctx, embedding, channelID, "", limit)
+}
+
+// Link stores the embedding of a saved thought and links its related
+// thoughts back to it.
+func (a *EmbeddingAgent) Link(ctx context.Context, thought *models.Thought) error {
+	if len(thought.Embedding) == 0 {
+		return nil
+	}
+
+	if err := a.thoughtRepo.UpdateEmbedding(ctx, thought.ID, thought.Embedding); err != nil {
+		return err
+	}
+
+	for _, relatedID := range thought.RelatedThoughts {
+		if err := a.thoughtRepo.AddRelated(ctx, relatedID, thought.ID); err != nil {
+			slog.ErrorContext(ctx, "Failed to link related thought", "related_id", relatedID, "thought_id", thought.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Backfill embeds and links thoughts captured before embeddings were
+// enabled, oldest first.
+func (a *EmbeddingAgent) Backfill(ctx context.Context, batchSize int) (int, error) {
+	count := 0
+
+	for {
+		thoughts, err := a.thoughtRepo.GetWithoutEmbedding(ctx, batchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(thoughts) == 0 {
+			return count, nil
+		}
+
+		for _, thought := range thoughts {
+			if ctx.Err() != nil {
+				return count, ctx.Err()
+			}
+
+			if _, err := a.Match(ctx, thought); err != nil {
+				return count, err
+			}
+			if err := a.thoughtRepo.SetRelated(ctx, thought.ID, thought.RelatedThoughts); err != nil {
+				return count, err
+			}
+			if err := a.Link(ctx, thought); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type emojiPolicyKey struct{}
+
+// WithEmojiPolicy returns ctx in which posts are written, and cleaned up
+// after, to follow policy.
+func WithEmojiPolicy(ctx context.Context, policy string) context.Context {
+	return context.WithValue(ctx, emojiPolicyKey{}, policy)
+}
+
+// EmojiPolicyFrom returns the emoji policy ctx asks for, or an empty
+// string when it doesn't ask for one.
+func EmojiPolicyFrom(ctx context.Context) string {
+	policy, _ := ctx.Value(emojiPolicyKey{}).(string)
+	return policy
+}
+
+// emojiRule tells the model how many emojis the policy ctx asks for
+// allows. Without one it allows a few, as the prompts always have.
+func emojiRule(ctx context.Context) string {
+	switch EmojiPolicyFrom(ctx) {
+	case models.EmojiNone:
+		return "Uses no emojis at all, whatever the author's style says"
+	case models.EmojiExpressive:
+		return fmt.Sprintf("Uses emojis freely where they add energy, such as to mark key lines or list items, up to %d, never the same one twice in a row", models.MaxExpressiveEmoji)
+	}
+	return "Uses emojis sparingly (1-2 max)"
+}
+
+// captionEmojiRule is emojiRule for the caption of a carousel or poll.
+func captionEmojiRule(ctx context.Context) string {
+	rule := emojiRule(ctx)
+	return "The caption " + strings.ToLower(rule[:1]) + rule[1:]
+}
+
+// applyEmojiPolicy strips the emojis text has beyond what the policy ctx
+// asks for allows, since the model doesn't always keep to it.
+func applyEmojiPolicy(ctx context.Context, text string) string {
+	return models.ApplyEmojiPolicy(text, EmojiPolicyFrom(ctx))
+}
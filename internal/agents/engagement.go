@@ -0,0 +1,139 @@
+package agents
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// minEngagementSamples is how many published posts with metrics it takes
+	// before posting times are picked from engagement instead of defaults.
+	minEngagementSamples = 8
+	// engagementPrior is how many average posts each weekday and hour is
+	// assumed to have on top of its own, so one lucky post can't make a slot
+	// look best.
+	engagementPrior = 3
+	// Posting times are picked between these hours, at least
+	// minPostingGapHours apart.
+	earliestPostingHour = 7
+	latestPostingHour   = 21
+	minPostingGapHours  = 2
+)
+
+type engagementBucket struct {
+	sum   float64
+	count int
+}
+
+// Engagement holds how well published posts did by the weekday and hour
+// they went out. Scores are log-scaled so a single viral post doesn't
+// outweigh a steady pattern.
+type Engagement struct {
+	Samples int
+	mean    float64
+	byDay   [7]engagementBucket
+	byHour  [24]engagementBucket
+}
+
+// NewEngagement learns from the published posts that have synced metrics,
+// bucketing their publish times in location.
+func NewEngagement(posts []*models.Post, location *time.Location) *Engagement {
+	e := &Engagement{}
+
+	var total float64
+	for _, post := range posts {
+		if post.PublishedAt == nil || post.MetricsSyncedAt == nil {
+			continue
+		}
+
+		score := math.Log1p(math.Max(post.PerformanceScore, 0))
+		at := post.PublishedAt.In(location)
+
+		e.byDay[at.Weekday()].sum += score
+		e.byDay[at.Weekday()].count++
+		e.byHour[at.Hour()].sum += score
+		e.byHour[at.Hour()].count++
+		total += score
+		e.Samples++
+	}
+
+	if e.Samples > 0 {
+		e.mean = total / float64(e.Samples)
+	}
+
+	return e
+}
+
+// Ready reports whether there is enough history to prefer some slots over
+// others.
+func (e *Engagement) Ready() bool {
+	return e != nil && e.Samples >= minEngagementSamples
+}
+
+// effect is how much better than average a bucket does, shrunk towards
+// zero when it has few posts.
+func (e *Engagement) effect(b engagementBucket) float64 {
+	shrunk := (b.sum + engagementPrior*e.mean) / float64(b.count+engagementPrior)
+	return shrunk - e.mean
+}
+
+// Score rates a posting slot by its weekday and hour; higher is better.
+func (e *Engagement) Score(t time.Time) float64 {
+	return e.mean + e.effect(e.byDay[t.Weekday()]) + e.effect(e.byHour[t.Hour()])
+}
+
+// BestTimes picks n posting times, as 15:04, from the hours that did best,
+// spaced apart. Hours with no posts yet are only used, from fallback, when
+// too few hours have any.
+func (e *Engagement) BestTimes(n int, fallback []string) []string {
+	var hours []int
+	for hour := earliestPostingHour; hour <= latestPostingHour; hour++ {
+		if e.byHour[hour].count > 0 {
+			hours = append(hours, hour)
+		}
+	}
+	sort.SliceStable(hours, func(i, j int) bool {
+		return e.effect(e.byHour[hours[i]]) > e.effect(e.byHour[hours[j]])
+	})
+
+	for _, t := range fallback {
+		if parsed, err := time.Parse("15:04", t); err == nil {
+			hours = append(hours, parsed.Hour())
+		}
+	}
+
+	var picked []int
+	for _, hour := range hours {
+		if len(picked) == n {
+			break
+		}
+		if !farEnough(picked, hour) {
+			continue
+		}
+		picked = append(picked, hour)
+	}
+
+	if len(picked) < n {
+		return fallback
+	}
+
+	sort.Ints(picked)
+	times := make([]string, len(picked))
+	for i, hour := range picked {
+		times[i] = fmt.Sprintf("%02d:00", hour)
+	}
+	return times
+}
+
+func farEnough(picked []int, hour int) bool {
+	for _, p := range picked {
+		if gap := p - hour; gap < minPostingGapHours && gap > -minPostingGapHours {
+			return false
+		}
+	}
+	return true
+}
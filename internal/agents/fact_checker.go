@@ -0,0 +1,116 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// FactCheckerAgent checks the claims a draft makes against the thoughts it
+// was written from, to catch numbers, names and events the model invented.
+type FactCheckerAgent struct {
+	llm LLMProvider
+}
+
+func NewFactCheckerAgent(llm LLMProvider) *FactCheckerAgent {
+	if llm == nil {
+		slog.Error("LLM provider is required")
+		os.Exit(1)
+	}
+
+	return &FactCheckerAgent{
+		llm: llm,
+	}
+}
+
+type factCheckResponse struct {
+	Claims []struct {
+		Claim   string `json:"claim"`
+		Verdict string `json:"verdict"`
+		Note    string `json:"note"`
+	} `json:"claims"`
+}
+
+var factCheckSchema = objectSchema(map[string]Schema{
+	"claims": arraySchema("Every factual claim in the draft, in order", objectSchema(map[string]Schema{
+		"claim":   stringSchema("The claim, quoted or closely paraphrased from the draft"),
+		"verdict": enumSchema("Whether the source notes back the claim up", models.ClaimSupported, models.ClaimUnsupported, models.ClaimContradicted),
+		"note":    stringSchema("For a claim that isn't supported, what the notes say instead or that they don't mention it"),
+	}, "claim", "verdict")),
+}, "claims")
+
+func (r *factCheckResponse) validate() error {
+	for _, claim := range r.Claims {
+		if strings.TrimSpace(claim.Claim) == "" {
+			return fmt.Errorf("a claim is empty")
+		}
+		switch claim.Verdict {
+		case models.ClaimSupported, models.ClaimUnsupported, models.ClaimContradicted:
+		default:
+			return fmt.Errorf("claim %q has unknown verdict %q", claim.Claim, claim.Verdict)
+		}
+	}
+	return nil
+}
+
+// Check lists the factual claims in content and flags the ones thoughts
+// don't support. Opinions, advice and rhetorical framing aren't claims.
+func (a *FactCheckerAgent) Check(ctx context.Context, content string, thoughts []*models.Thought) (*models.FactCheck, error) {
+	if len(thoughts) == 0 {
+		return nil, fmt.Errorf("no source thoughts to check against")
+	}
+
+	var notes strings.Builder
+	for i, thought := range thoughts {
+		fmt.Fprintf(&notes, "Note %d: %s\n", i+1, thought.Content)
+	}
+
+	prompt := fmt.Sprintf(`You are fact-checking a LinkedIn post a ghostwriter drafted from the author's notes.
+The notes are the only source of truth: the author will publish the post under
+their name, so anything the notes don't say may have been made up.
+
+Notes:
+"""
+%s"""
+
+Draft:
+"""
+%s
+"""
+
+List every factual claim in the draft: numbers, percentages, amounts, dates
+and durations, names of people, companies and products, quotes, and events
+or results ("we shipped", "customers asked for"). Skip opinions, advice,
+lessons and rhetorical framing.
+
+For each claim give a verdict:
+- supported: the notes state it, or it follows directly from them
+- unsupported: the notes don't mention it, including rounded or more precise
+  numbers than the notes give
+- contradicted: the notes say something different
+
+Be strict about numbers and names and lenient about wording.`, notes.String(), content)
+
+	var response factCheckResponse
+	if err := completeJSON(ctx, a.llm, prompt, 1000, factCheckSchema, &response); err != nil {
+		return nil, err
+	}
+
+	check := &models.FactCheck{Checked: len(response.Claims)}
+	for _, claim := range response.Claims {
+		if claim.Verdict == models.ClaimSupported {
+			continue
+		}
+		check.Flags = append(check.Flags, models.ClaimFlag{
+			Claim:   strings.TrimSpace(claim.Claim),
+			Verdict: claim.Verdict,
+			Note:    strings.TrimSpace(claim.Note),
+		})
+	}
+
+	return check, nil
+}
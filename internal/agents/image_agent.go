@@ -0,0 +1,76 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// ImageConcept describes the visual proposed for a post. Hook is the short
+// line rendered onto a quote card when no image model is configured.
+type ImageConcept struct {
+	Concept string `json:"concept"`
+	Hook    string `json:"hook"`
+	AltText string `json:"alt_text"`
+}
+
+var imageConceptSchema = objectSchema(map[string]Schema{
+	"concept":  stringSchema("One paragraph describing the image, written as a prompt for an image model"),
+	"hook":     stringSchema("The hook sentence"),
+	"alt_text": stringSchema("One sentence of alt text describing the image for screen readers"),
+}, "concept", "hook", "alt_text")
+
+func (c *ImageConcept) validate() error {
+	c.Concept = strings.TrimSpace(c.Concept)
+	c.Hook = strings.Trim(c.Hook, `"“” `)
+	c.AltText = strings.TrimSpace(c.AltText)
+	if c.Concept == "" || c.Hook == "" {
+		return fmt.Errorf("concept and hook are required")
+	}
+	return nil
+}
+
+type ImageAgent struct {
+	llm LLMProvider
+}
+
+func NewImageAgent(llm LLMProvider) *ImageAgent {
+	if llm == nil {
+		slog.Error("LLM provider is required")
+		os.Exit(1)
+	}
+
+	return &ImageAgent{
+		llm: llm,
+	}
+}
+
+// ProposeConcept suggests one visual to accompany a LinkedIn post.
+func (a *ImageAgent) ProposeConcept(ctx context.Context, content string) (*ImageConcept, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("no post content provided")
+	}
+
+	prompt := fmt.Sprintf(`You are an art director choosing a single image to accompany a LinkedIn post.
+
+Post:
+"""
+%s
+"""
+
+Propose one visual that reinforces the post's main idea. Prefer simple, uncluttered
+compositions that read well at thumbnail size. No text, logos or real people's faces
+in the image itself.
+
+Also pick the post's hook: the single most striking sentence, at most 20 words,
+quoted from the post or tightened from it.`, content)
+
+	concept := &ImageConcept{}
+	if err := completeJSON(ctx, a.llm, prompt, 500, imageConceptSchema, concept); err != nil {
+		return nil, fmt.Errorf("failed to propose image concept: %w", err)
+	}
+
+	return concept, nil
+}
@@ -0,0 +1,145 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type languageKey struct{}
+
+// WithLanguage returns ctx in which posts are written in the language with
+// code, such as "de".
+func WithLanguage(ctx context.Context, code string) context.Context {
+	return context.WithValue(ctx, languageKey{}, code)
+}
+
+// LanguageFrom returns the language code ctx asks for, or an empty string
+// when it doesn't ask for one.
+func LanguageFrom(ctx context.Context) string {
+	code, _ := ctx.Value(languageKey{}).(string)
+	return code
+}
+
+// languageSection tells the model to write in the language ctx asks for.
+// English is what the prompts are written in, so it needs no instruction.
+func languageSection(ctx context.Context) string {
+	return languageInstruction(LanguageFrom(ctx))
+}
+
+func languageInstruction(code string) string {
+	if code == "" || code == models.DefaultLanguage {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+
+Write everything in %s, the way a native speaker posts on LinkedIn, whatever language the input is in. Keep names, product names and technical terms people usually leave in English as they are.`, models.LanguageName(code))
+}
+
+// Translation is a post rewritten in another language. Slides and Poll are
+// only set for carousels and polls.
+type Translation struct {
+	Content      string
+	FirstComment string
+	Slides       []string
+	Poll         *models.Poll
+}
+
+type translationResponse struct {
+	Content      string   `json:"content"`
+	FirstComment string   `json:"first_comment"`
+	Slides       []string `json:"slides"`
+	PollQuestion string   `json:"poll_question"`
+	PollOptions  []string `json:"poll_options"`
+
+	// What the original had, to check nothing was dropped.
+	slides, options int
+	comment         bool
+}
+
+var translationSchema = objectSchema(map[string]Schema{
+	"content":       stringSchema("The translated post"),
+	"first_comment": stringSchema("The translated first comment, or an empty string when there is none"),
+	"slides":        arraySchema("The translated slides, in order, or an empty list when there are none", stringSchema("")),
+	"poll_question": stringSchema("The translated poll question, or an empty string when there is no poll"),
+	"poll_options":  arraySchema("The translated poll options, in order, or an empty list when there is no poll", stringSchema("")),
+}, "content", "first_comment", "slides", "poll_question", "poll_options")
+
+func (r *translationResponse) validate() error {
+	r.Content = strings.TrimSpace(r.Content)
+	if r.Content == "" {
+		return fmt.Errorf("translation is empty")
+	}
+
+	r.FirstComment = strings.TrimSpace(r.FirstComment)
+	if r.comment && r.FirstComment == "" {
+		return fmt.Errorf("first comment is missing")
+	}
+
+	r.Slides = trimList(r.Slides)
+	if len(r.Slides) != r.slides {
+		return fmt.Errorf("got %d slides, the original has %d", len(r.Slides), r.slides)
+	}
+
+	if r.options == 0 {
+		return nil
+	}
+	r.PollQuestion = strings.TrimSpace(r.PollQuestion)
+	r.PollOptions = trimList(r.PollOptions)
+	if len(r.PollOptions) != r.options {
+		return fmt.Errorf("got %d poll options, the original has %d", len(r.PollOptions), r.options)
+	}
+	poll := models.Poll{Question: r.PollQuestion, Options: r.PollOptions, Duration: models.DefaultPollDuration}
+	return poll.Validate()
+}
+
+// TranslatePost rewrites post, with its first comment, slides and poll, in
+// the language with code.
+func (a *ContentGeneratorAgent) TranslatePost(ctx context.Context, post *models.Post, code string) (*Translation, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Post:\n\"\"\"\n%s\n\"\"\"\n", post.Content)
+	if post.FirstComment != "" {
+		fmt.Fprintf(&b, "\nFirst comment:\n\"\"\"\n%s\n\"\"\"\n", post.FirstComment)
+	}
+	for i, slide := range post.Slides {
+		fmt.Fprintf(&b, "\nSlide %d:\n\"\"\"\n%s\n\"\"\"\n", i+1, slide)
+	}
+	if post.Poll != nil {
+		fmt.Fprintf(&b, "\nPoll question: %s\nPoll options:\n", post.Poll.Question)
+		for _, option := range post.Poll.Options {
+			fmt.Fprintf(&b, "- %s\n", option)
+		}
+	}
+
+	prompt := fmt.Sprintf(`You are a LinkedIn ghostwriter translating the author's post into %s for their audience there.
+
+%s
+Translate it so it reads as if the author had written it in %s:
+- Keep the meaning, the hook, the line breaks and the length
+- Adapt idioms and phrasing instead of translating word for word
+- Keep names, product names, hashtags and technical terms people usually leave in English as they are
+- Translate the first comment, every slide and the poll too when there are any
+- Poll questions stay under %d characters and options under %d`, models.LanguageName(code), b.String(), models.LanguageName(code), models.PollMaxQuestionChars, models.PollMaxOptionChars)
+
+	response := translationResponse{slides: len(post.Slides), comment: post.FirstComment != ""}
+	if post.Poll != nil {
+		response.options = len(post.Poll.Options)
+	}
+	if err := a.completeJSON(ctx, prompt, translationSchema, &response); err != nil {
+		return nil, fmt.Errorf("failed to translate post: %w", err)
+	}
+
+	translation := &Translation{
+		Content:      cleanPost(ctx, response.Content),
+		FirstComment: cleanPost(ctx, response.FirstComment),
+		Slides:       response.Slides,
+	}
+	if post.Poll != nil {
+		translation.Poll = &models.Poll{Question: response.PollQuestion, Options: response.PollOptions, Duration: post.Poll.Duration}
+	}
+
+	return translation, nil
+}
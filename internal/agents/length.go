@@ -0,0 +1,86 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type postLengthKey struct{}
+
+// WithPostLength returns ctx in which posts are written at length.
+func WithPostLength(ctx context.Context, length models.PostLength) context.Context {
+	return context.WithValue(ctx, postLengthKey{}, length)
+}
+
+// PostLengthFrom returns the length ctx asks for, and whether it asks for
+// one.
+func PostLengthFrom(ctx context.Context) (models.PostLength, bool) {
+	length, ok := ctx.Value(postLengthKey{}).(models.PostLength)
+	return length, ok
+}
+
+// postLength returns the length ctx asks for, or the medium preset.
+func postLength(ctx context.Context) models.PostLength {
+	if length, ok := PostLengthFrom(ctx); ok {
+		return length
+	}
+	return models.DefaultPostLength
+}
+
+// fitLength asks the model to trim each variation that overshoots the
+// length ctx asks for. A variation the trim fails for is kept as it is, a
+// long draft being more use than none.
+func (a *ContentGeneratorAgent) fitLength(ctx context.Context, variations []Variation) {
+	length := postLength(ctx)
+	for i, variation := range variations {
+		if !length.Overshoots(variation.Content) {
+			continue
+		}
+
+		words := models.CountWords(variation.Content)
+		trimmed, err := a.trimPost(ctx, variation.Content, length)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to trim variation", "variation", i+1, "words", words, "error", err)
+			continue
+		}
+
+		slog.InfoContext(ctx, "Trimmed variation", "variation", i+1, "words", words, "trimmed_words", models.CountWords(trimmed), "target", length.String())
+		variations[i].Content = trimmed
+	}
+}
+
+// trimPost shortens content to fit length, keeping its hook, voice and
+// ending.
+func (a *ContentGeneratorAgent) trimPost(ctx context.Context, content string, length models.PostLength) (string, error) {
+	prompt := fmt.Sprintf(`You are a LinkedIn ghostwriter. This post has %d words, too many for the %s it should have.
+
+Post:
+"""
+%s
+"""
+
+Shorten it to %s:
+- Keep the hook, the key insight and the closing line
+- Cut repetition, filler and the weakest examples first
+- Keep the author's voice, language, line breaks and emphasis
+
+Respond with ONLY the shortened post, no preamble or explanation.`, models.CountWords(content), length, content, length)
+
+	responseText, err := a.complete(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to trim post: %w", err)
+	}
+
+	trimmed := cleanPost(ctx, strings.TrimSpace(responseText))
+	if trimmed == "" {
+		return "", fmt.Errorf("trimmed post is empty")
+	}
+	if models.CountWords(trimmed) >= models.CountWords(content) {
+		return "", fmt.Errorf("trimmed post is no shorter")
+	}
+	return trimmed, nil
+}
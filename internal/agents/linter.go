@@ -0,0 +1,165 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+)
+
+// Lint rules.
+const (
+	LintCliche    = "cliche"
+	LintAIPattern = "ai_pattern"
+	LintEmDashes  = "em_dashes"
+)
+
+// maxEmDashes is how many em-dashes a post can have before it reads as
+// machine-written.
+const maxEmDashes = 2
+
+// LintIssue is one thing a post would be better without. Sentence is the
+// flagged sentence, or empty when the issue is with the post as a whole.
+type LintIssue struct {
+	Rule     string
+	Message  string
+	Sentence string
+}
+
+type lintPattern struct {
+	rule    string
+	pattern *regexp.Regexp
+	message string
+}
+
+var lintPatterns = []lintPattern{
+	{LintCliche, regexp.MustCompile(`(?i)\b(humbled|honou?red|thrilled|excited|proud) to (announce|share)\b`), "LinkedIn cliché"},
+	{LintCliche, regexp.MustCompile(`(?i)\bgame[- ]chang(er|ing)\b`), "LinkedIn cliché"},
+	{LintCliche, regexp.MustCompile(`(?i)\bthought leader(ship)?\b`), "LinkedIn cliché"},
+	{LintCliche, regexp.MustCompile(`(?i)\bsynerg(y|ies)\b`), "corporate jargon"},
+	{LintCliche, regexp.MustCompile(`(?i)\bmove the needle\b`), "corporate jargon"},
+	{LintCliche, regexp.MustCompile(`(?i)\bparadigm shift\b`), "corporate jargon"},
+	{LintCliche, regexp.MustCompile(`(?i)\blet that sink in\b`), "LinkedIn cliché"},
+	{LintCliche, regexp.MustCompile(`(?i)\bat the end of the day\b`), "cliché"},
+	{LintCliche, regexp.MustCompile(`(?i)\bi'?m (so )?grateful for this journey\b`), "LinkedIn cliché"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\bdelv(e|es|ed|ing)\b`), "reads as AI-written"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\bin today'?s (fast[- ]paced|ever[- ]evolving|ever[- ]changing|digital) (world|landscape|age)\b`), "reads as AI-written"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\bever[- ](evolving|changing) landscape\b`), "reads as AI-written"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\b(rich )?tapestry\b`), "reads as AI-written"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\b(unlock|unleash|harness)(es|ed|ing)? the (full )?(power|potential)\b`), "reads as AI-written"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\bnavigat(e|es|ed|ing) the complexities\b`), "reads as AI-written"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\bembark(s|ed|ing)? on (a|this|my|our) journey\b`), "reads as AI-written"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\b(a|is a) testament to\b`), "reads as AI-written"},
+	{LintAIPattern, regexp.MustCompile(`(?i)\bit'?s not (just )?(about )?[^.!?]{1,60}[,;—-]+ it'?s (about )?`), "contrast framing typical of AI-written text"},
+	{LintAIPattern, regexp.MustCompile(`(?i)^in conclusion\b`), "reads as AI-written"},
+}
+
+var sentenceEnd = regexp.MustCompile(`[.!?]+["')\]]*\s+|\n+`)
+
+// Lint flags clichés, overused em-dashes and phrasing typical of AI-written
+// text in a post.
+func Lint(content string) []LintIssue {
+	var issues []LintIssue
+
+	for _, sentence := range splitSentences(content) {
+		for _, p := range lintPatterns {
+			if match := p.pattern.FindString(sentence); match != "" {
+				issues = append(issues, LintIssue{
+					Rule:     p.rule,
+					Message:  fmt.Sprintf("%q: %s", strings.TrimSpace(match), p.message),
+					Sentence: sentence,
+				})
+			}
+		}
+	}
+
+	if count := strings.Count(content, "—"); count > maxEmDashes {
+		issues = append(issues, LintIssue{
+			Rule:    LintEmDashes,
+			Message: fmt.Sprintf("%d em-dashes, more than %d reads as AI-written", count, maxEmDashes),
+		})
+	}
+
+	return issues
+}
+
+func splitSentences(content string) []string {
+	var sentences []string
+	for _, sentence := range sentenceEnd.Split(content, -1) {
+		if sentence = strings.TrimSpace(sentence); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+	return sentences
+}
+
+type LinterAgent struct {
+	llm       LLMProvider
+	maxTokens int
+}
+
+// NewLinterAgent caps each rewrite at maxTokens, or 2000 when maxTokens is
+// zero.
+func NewLinterAgent(llm LLMProvider, maxTokens int) *LinterAgent {
+	if llm == nil {
+		slog.Error("LLM provider is required")
+		os.Exit(1)
+	}
+
+	if maxTokens <= 0 {
+		maxTokens = defaultGeneratorMaxTokens
+	}
+
+	return &LinterAgent{
+		llm:       llm,
+		maxTokens: maxTokens,
+	}
+}
+
+// Rewrite fixes the issues Lint found in content, rewriting only the
+// flagged sentences and keeping the rest word for word.
+func (a *LinterAgent) Rewrite(ctx context.Context, content string, issues []LintIssue) (string, error) {
+	if len(issues) == 0 {
+		return "", fmt.Errorf("nothing to fix")
+	}
+
+	var b strings.Builder
+	for _, issue := range issues {
+		if issue.Sentence == "" {
+			fmt.Fprintf(&b, "- The whole post: %s\n", issue.Message)
+			continue
+		}
+		fmt.Fprintf(&b, "- \"%s\": %s\n", issue.Sentence, issue.Message)
+	}
+
+	prompt := fmt.Sprintf(`You are editing a LinkedIn post so it sounds like a real person wrote it.
+
+Post:
+"""
+%s
+"""
+
+These sentences were flagged:
+%s
+Rewrite only the flagged sentences in plain, specific language the author would actually say.
+Drop clichés, jargon and stock AI phrasing rather than swapping in synonyms, and use at most %d em-dashes in the whole post.
+Keep every other sentence, the line breaks, hashtags and emoji exactly as they are, and write in the post's own language.
+
+Respond with ONLY the full edited post, no preamble or explanation.`, content, b.String(), maxEmDashes)
+
+	responseText, err := a.llm.Complete(ctx, prompt, a.maxTokens)
+	if err != nil {
+		return "", err
+	}
+
+	rewritten := format.FromMarkdown(responseText)
+	if rewritten == "" {
+		return "", fmt.Errorf("failed to generate rewrite")
+	}
+
+	return rewritten, nil
+}
@@ -0,0 +1,82 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/outbound"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type LLMProvider interface {
+	Complete(ctx context.Context, prompt string, maxTokens int) (string, error)
+	Name() string
+	Model() string
+}
+
+// logCompletion records the metadata of a model call in the log and on the
+// current span, and counts its tokens towards usage. Prompts and responses
+// are never logged, only their size.
+func logCompletion(ctx context.Context, provider LLMProvider, prompt string, inputTokens, outputTokens int, started time.Time) {
+	countTokens(ctx, inputTokens, outputTokens)
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("gen_ai.usage.input_tokens", inputTokens),
+		attribute.Int("gen_ai.usage.output_tokens", outputTokens),
+	)
+
+	slog.InfoContext(ctx, "LLM completion",
+		"provider", provider.Name(),
+		"model", provider.Model(),
+		"prompt_chars", len(prompt),
+		"input_tokens", inputTokens,
+		"output_tokens", outputTokens,
+		"latency_ms", time.Since(started).Milliseconds(),
+	)
+}
+
+type ProviderConfig struct {
+	Provider      string
+	Model         string
+	AnthropicKey  string
+	OpenAIKey     string
+	OpenAIBaseURL string
+	OllamaURL     string
+	// Temperature overrides the provider's default sampling temperature.
+	Temperature *float64
+	// Guards rate limits and circuit-breaks calls per provider; nil leaves
+	// them unguarded.
+	Guards *outbound.Guards
+}
+
+// httpClient returns a client whose calls go through provider's guard.
+func (cfg ProviderConfig) httpClient(provider string) *http.Client {
+	return &http.Client{Transport: cfg.Guards.For(provider).Transport(otelhttp.NewTransport(http.DefaultTransport))}
+}
+
+func NewLLMProvider(cfg ProviderConfig) (LLMProvider, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case "", "anthropic":
+		if cfg.AnthropicKey == "" {
+			return nil, fmt.Errorf("ANTHROPIC_API_KEY is required for the anthropic provider")
+		}
+		return NewAnthropicProvider(cfg.AnthropicKey, cfg.Model, cfg.Temperature, cfg.httpClient("anthropic")), nil
+
+	case "openai":
+		if cfg.OpenAIKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is required for the openai provider")
+		}
+		return NewOpenAIProvider(cfg.OpenAIKey, cfg.OpenAIBaseURL, cfg.Model, cfg.Temperature, cfg.httpClient("openai")), nil
+
+	case "ollama":
+		return NewOllamaProvider(cfg.OllamaURL, cfg.Model, cfg.Temperature, cfg.httpClient("ollama")), nil
+	}
+
+	return nil, fmt.Errorf("unknown LLM provider: %s", cfg.Provider)
+}
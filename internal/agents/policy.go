@@ -0,0 +1,147 @@
+package agents
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// Kinds of confidential information.
+const (
+	PolicyBannedTerm = "banned term"
+	PolicyCustomer   = "customer"
+	PolicyCodename   = "codename"
+)
+
+// PolicyViolation is a confidential term a post mentions. Excerpt is the
+// text around the mention, to show where it is.
+type PolicyViolation struct {
+	Kind    string
+	Term    string
+	Excerpt string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("%s %q in %q", v.Kind, v.Term, v.Excerpt)
+}
+
+// PolicyError is returned for a post that can't be approved because it
+// mentions confidential information.
+type PolicyError struct {
+	Violations []PolicyViolation
+}
+
+func (e *PolicyError) Error() string {
+	mentions := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		mentions[i] = violation.String()
+	}
+	return "post mentions confidential information: " + strings.Join(mentions, "; ")
+}
+
+type policyRule struct {
+	kind    string
+	term    string
+	pattern *regexp.Regexp
+}
+
+// PolicyAgent keeps confidential information out of posts: terms that must
+// never be published, customers who haven't agreed to be named and
+// codenames of unreleased features.
+type PolicyAgent struct {
+	rules []policyRule
+}
+
+// NewPolicyAgent flags posts mentioning any of bannedTerms, customers or
+// codenames. Matches ignore case and whole words only, and words may be
+// joined or hyphenated, so "Project Falcon" also catches #ProjectFalcon.
+func NewPolicyAgent(bannedTerms, customers, codenames []string) *PolicyAgent {
+	agent := &PolicyAgent{}
+	agent.add(PolicyBannedTerm, bannedTerms)
+	agent.add(PolicyCustomer, customers)
+	agent.add(PolicyCodename, codenames)
+	return agent
+}
+
+func (a *PolicyAgent) add(kind string, terms []string) {
+	for _, term := range terms {
+		words := strings.Fields(term)
+		if len(words) == 0 {
+			continue
+		}
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		pattern := regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(` + strings.Join(words, `[\s_-]*`) + `)(?:[^\p{L}\p{N}]|$)`)
+		a.rules = append(a.rules, policyRule{kind: kind, term: strings.Join(strings.Fields(term), " "), pattern: pattern})
+	}
+}
+
+// Check returns a *PolicyError when post mentions anything confidential.
+// A nil agent, for when no terms are configured, passes every post.
+func (a *PolicyAgent) Check(post *models.Post) error {
+	if a == nil {
+		return nil
+	}
+	if violations := a.Scan(post); len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// Scan returns the confidential terms post mentions anywhere it would be
+// published: its text, first comment, carousel slides and poll. Each term
+// is reported once.
+func (a *PolicyAgent) Scan(post *models.Post) []PolicyViolation {
+	parts := []string{post.Content, post.FirstComment}
+	parts = append(parts, post.Slides...)
+	if post.Poll != nil {
+		parts = append(parts, post.Poll.Question)
+		parts = append(parts, post.Poll.Options...)
+	}
+	text := strings.Join(parts, "\n")
+
+	var violations []PolicyViolation
+	for _, rule := range a.rules {
+		match := rule.pattern.FindStringSubmatchIndex(text)
+		if match == nil {
+			continue
+		}
+		violations = append(violations, PolicyViolation{
+			Kind:    rule.kind,
+			Term:    rule.term,
+			Excerpt: excerpt(text, match[2], match[3]),
+		})
+	}
+	return violations
+}
+
+// excerptContext is how many characters are shown on each side of a
+// mention.
+const excerptContext = 40
+
+// excerpt returns the line around text[start:end], cut to a few words on
+// each side.
+func excerpt(text string, start, end int) string {
+	lineStart := strings.LastIndex(text[:start], "\n") + 1
+	lineEnd := len(text)
+	if i := strings.Index(text[end:], "\n"); i >= 0 {
+		lineEnd = end + i
+	}
+
+	before := []rune(text[lineStart:start])
+	after := []rune(text[end:lineEnd])
+
+	prefix, suffix := "", ""
+	if len(before) > excerptContext {
+		before = before[len(before)-excerptContext:]
+		prefix = "…"
+	}
+	if len(after) > excerptContext {
+		after = after[:excerptContext]
+		suffix = "…"
+	}
+	return strings.TrimSpace(prefix + string(before) + text[start:end] + string(after) + suffix)
+}
@@ -0,0 +1,255 @@
+package agents
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultAnthropicModel = "claude-sonnet-4-5-20250929"
+
+type AnthropicProvider struct {
+	apiKey      string
+	model       string
+	temperature *float64
+	httpClient  *http.Client
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Stream      bool               `json:"stream,omitempty"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+	ToolChoice  *anthropicChoice   `json:"tool_choice,omitempty"`
+}
+
+// anthropicTool is the tool a structured response is returned through.
+type anthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema Schema `json:"input_schema"`
+}
+
+type anthropicChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// respondTool is the name of the tool the model is made to call with a
+// structured response.
+const respondTool = "respond"
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content []anthropicContent `json:"content"`
+	Usage   struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *anthropicError `json:"error,omitempty"`
+}
+
+type anthropicContent struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	Input json.RawMessage `json:"input"`
+}
+
+// anthropicStreamEvent is one server-sent event of a streamed response.
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta"`
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *anthropicError `json:"error,omitempty"`
+}
+
+type anthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// NewAnthropicProvider uses the API's default temperature when temperature
+// is nil.
+func NewAnthropicProvider(apiKey, model string, temperature *float64, httpClient *http.Client) *AnthropicProvider {
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+
+	return &AnthropicProvider{
+		apiKey:      apiKey,
+		model:       model,
+		temperature: temperature,
+		httpClient:  httpClient,
+	}
+}
+
+func (p *AnthropicProvider) Name() string {
+	return "anthropic"
+}
+
+func (p *AnthropicProvider) Model() string {
+	return p.model
+}
+
+// Complete streams the response when ctx was set up with WithStream. A
+// structured response is returned as the JSON input of a forced tool call.
+func (p *AnthropicProvider) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	stream := streamFrom(ctx)
+
+	reqBody := anthropicRequest{
+		Model:       p.model,
+		MaxTokens:   maxTokens,
+		Temperature: p.temperature,
+		Messages: []anthropicMessage{
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+		Stream: stream != nil,
+	}
+	if schema := schemaFrom(ctx); schema != nil {
+		reqBody.Tools = []anthropicTool{{Name: respondTool, Description: "Return the response.", InputSchema: schema}}
+		reqBody.ToolChoice = &anthropicChoice{Type: "tool", Name: respondTool}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	started := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Anthropic API: %w: %w", errTransport, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK && stream != nil {
+		return p.readStream(ctx, resp.Body, prompt, started, stream)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		slog.ErrorContext(ctx, "Anthropic API error", "status", resp.StatusCode, "body", string(body), "latency_ms", time.Since(started).Milliseconds())
+		return "", newAPIError(p.Name(), resp)
+	}
+
+	var apiResp anthropicResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if apiResp.Error != nil {
+		return "", fmt.Errorf("API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
+	}
+
+	logCompletion(ctx, p, prompt, apiResp.Usage.InputTokens, apiResp.Usage.OutputTokens, started)
+
+	for _, content := range apiResp.Content {
+		switch {
+		case content.Type == "text" && content.Text != "":
+			return content.Text, nil
+		case content.Type == "tool_use" && len(content.Input) > 0:
+			return string(content.Input), nil
+		}
+	}
+
+	return "", fmt.Errorf("unexpected response format")
+}
+
+// readStream collects the text of a streamed response, passing it to stream
+// as it grows.
+func (p *AnthropicProvider) readStream(ctx context.Context, body io.Reader, prompt string, started time.Time, stream StreamFunc) (string, error) {
+	var text strings.Builder
+	var inputTokens, outputTokens int
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return "", fmt.Errorf("failed to parse stream event: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			inputTokens = event.Message.Usage.InputTokens
+		case "content_block_delta":
+			switch event.Delta.Type {
+			case "text_delta":
+				text.WriteString(event.Delta.Text)
+				stream(text.String())
+			case "input_json_delta":
+				text.WriteString(event.Delta.PartialJSON)
+				stream(text.String())
+			}
+		case "message_delta":
+			outputTokens = event.Usage.OutputTokens
+		case "error":
+			if event.Error == nil {
+				return "", fmt.Errorf("API error in stream")
+			}
+			err := fmt.Errorf("API error: %s - %s", event.Error.Type, event.Error.Message)
+			if event.Error.Type == "overloaded_error" || event.Error.Type == "api_error" {
+				// Like a 529 or 500 before the stream started, worth
+				// another try.
+				err = fmt.Errorf("%w: %w", err, errTransport)
+			}
+			return "", err
+		case "message_stop":
+			logCompletion(ctx, p, prompt, inputTokens, outputTokens, started)
+			if text.Len() == 0 {
+				return "", fmt.Errorf("unexpected response format")
+			}
+			return text.String(), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read stream: %w: %w", errTransport, err)
+	}
+	return "", fmt.Errorf("stream ended before the message was complete: %w", errTransport)
+}
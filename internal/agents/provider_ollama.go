@@ -0,0 +1,131 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOllamaModel = "llama3.1"
+	defaultOllamaURL   = "http://localhost:11434"
+)
+
+type OllamaProvider struct {
+	baseURL     string
+	model       string
+	temperature *float64
+	httpClient  *http.Client
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   Schema          `json:"format,omitempty"`
+	Options  map[string]any  `json:"options,omitempty"`
+}
+
+type ollamaResponse struct {
+	Message         openAIMessage `json:"message"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error,omitempty"`
+}
+
+func NewOllamaProvider(baseURL, model string, temperature *float64, httpClient *http.Client) *OllamaProvider {
+	if baseURL == "" {
+		baseURL = defaultOllamaURL
+	}
+	if model == "" {
+		model = defaultOllamaModel
+	}
+
+	return &OllamaProvider{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		model:       model,
+		temperature: temperature,
+		httpClient:  httpClient,
+	}
+}
+
+func (p *OllamaProvider) Name() string {
+	return "ollama"
+}
+
+func (p *OllamaProvider) Model() string {
+	return p.model
+}
+
+func (p *OllamaProvider) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	reqBody := ollamaRequest{
+		Model: p.model,
+		Messages: []openAIMessage{
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+		Stream: false,
+		Options: map[string]any{
+			"num_predict": maxTokens,
+		},
+	}
+	if p.temperature != nil {
+		reqBody.Options["temperature"] = *p.temperature
+	}
+	// Ollama constrains the output to the schema itself.
+	reqBody.Format = schemaFrom(ctx)
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	started := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Ollama: %w: %w", errTransport, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		slog.ErrorContext(ctx, "Ollama error", "status", resp.StatusCode, "body", string(body), "latency_ms", time.Since(started).Milliseconds())
+		return "", newAPIError(p.Name(), resp)
+	}
+
+	var apiResp ollamaResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if apiResp.Error != "" {
+		return "", fmt.Errorf("Ollama error: %s", apiResp.Error)
+	}
+
+	logCompletion(ctx, p, prompt, apiResp.PromptEvalCount, apiResp.EvalCount, started)
+
+	if apiResp.Message.Content != "" {
+		return apiResp.Message.Content, nil
+	}
+
+	return "", fmt.Errorf("unexpected response format")
+}
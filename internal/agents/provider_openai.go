@@ -0,0 +1,148 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIModel   = "gpt-4o-mini"
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+)
+
+type OpenAIProvider struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature *float64
+	httpClient  *http.Client
+}
+
+type openAIRequest struct {
+	Model          string          `json:"model"`
+	MaxTokens      int             `json:"max_tokens"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	Messages       []openAIMessage `json:"messages"`
+	ResponseFormat *openAIFormat   `json:"response_format,omitempty"`
+}
+
+// openAIFormat turns on JSON mode. The schema itself is only in the
+// prompt, since not every OpenAI-compatible server takes one.
+type openAIFormat struct {
+	Type string `json:"type"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func NewOpenAIProvider(apiKey, baseURL, model string, temperature *float64, httpClient *http.Client) *OpenAIProvider {
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+
+	return &OpenAIProvider{
+		apiKey:      apiKey,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		model:       model,
+		temperature: temperature,
+		httpClient:  httpClient,
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+func (p *OpenAIProvider) Model() string {
+	return p.model
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	reqBody := openAIRequest{
+		Model:       p.model,
+		MaxTokens:   maxTokens,
+		Temperature: p.temperature,
+		Messages: []openAIMessage{
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+	}
+	if schemaFrom(ctx) != nil {
+		reqBody.ResponseFormat = &openAIFormat{Type: "json_object"}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	started := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call OpenAI API: %w: %w", errTransport, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		slog.ErrorContext(ctx, "OpenAI API error", "status", resp.StatusCode, "body", string(body), "latency_ms", time.Since(started).Milliseconds())
+		return "", newAPIError(p.Name(), resp)
+	}
+
+	var apiResp openAIResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if apiResp.Error != nil {
+		return "", fmt.Errorf("API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
+	}
+
+	logCompletion(ctx, p, prompt, apiResp.Usage.PromptTokens, apiResp.Usage.CompletionTokens, started)
+
+	if len(apiResp.Choices) > 0 && apiResp.Choices[0].Message.Content != "" {
+		return apiResp.Choices[0].Message.Content, nil
+	}
+
+	return "", fmt.Errorf("unexpected response format")
+}
@@ -0,0 +1,85 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxReplyChars keeps replies to a few sentences, as replies to comments
+// on LinkedIn are.
+const maxReplyChars = 600
+
+type repliesResponse struct {
+	Replies []struct {
+		Comment int    `json:"comment"`
+		Reply   string `json:"reply"`
+	} `json:"replies"`
+}
+
+var repliesSchema = objectSchema(map[string]Schema{
+	"replies": arraySchema("One reply per comment", objectSchema(map[string]Schema{
+		"comment": integerSchema("The number of the comment"),
+		"reply":   stringSchema("The reply, as the author would write it"),
+	}, "comment", "reply")),
+}, "replies")
+
+func (r *repliesResponse) validate() error {
+	if len(r.Replies) == 0 {
+		return fmt.Errorf("no replies")
+	}
+	return nil
+}
+
+// DraftReplies suggests a reply in the author's voice to each of comments
+// on post. Replies line up with comments; a comment the model skipped gets
+// an empty one.
+func (a *ContentGeneratorAgent) DraftReplies(ctx context.Context, post string, comments []string, userStyle string) ([]string, error) {
+	if len(comments) == 0 {
+		return nil, nil
+	}
+
+	var commentList strings.Builder
+	for i, comment := range comments {
+		fmt.Fprintf(&commentList, "%d. %s\n", i+1, strings.Join(strings.Fields(comment), " "))
+	}
+
+	var styleSection string
+	if userStyle != "" {
+		styleSection = fmt.Sprintf("\nThe author's own writing style (match its tone and vocabulary):\n%s\n", userStyle)
+	}
+
+	prompt := fmt.Sprintf(`You are a LinkedIn ghostwriter replying to comments on the author's post, as the author.
+
+The post:
+"""
+%s
+"""
+
+Comments people left on it:
+"""
+%s"""
+%s
+Write one reply to each comment, as the author would in the comments:
+- 1-3 short sentences, warm and specific to what the commenter said
+- Answer questions directly, and add a detail from the post or the author's experience where it helps
+- Thank people sparingly and never with a generic "Thanks for sharing!" on its own
+- Reply in the language the comment is written in
+- No hashtags, no links and no sales pitch
+- Keep each reply under %d characters`, post, commentList.String(), styleSection, maxReplyChars)
+
+	var response repliesResponse
+	if err := a.completeJSON(ctx, prompt, repliesSchema, &response); err != nil {
+		return nil, fmt.Errorf("failed to draft replies: %w", err)
+	}
+
+	replies := make([]string, len(comments))
+	for _, reply := range response.Replies {
+		if reply.Comment < 1 || reply.Comment > len(comments) {
+			continue
+		}
+		replies[reply.Comment-1] = strings.TrimSpace(reply.Reply)
+	}
+
+	return replies, nil
+}
@@ -0,0 +1,163 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/outbound"
+)
+
+var (
+	ErrLLMUnavailable = errors.New("LLM provider unavailable")
+	errTransport      = errors.New("transport error")
+)
+
+type APIError struct {
+	Provider   string
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API request failed with status %d", e.Provider, e.StatusCode)
+}
+
+func newAPIError(provider string, resp *http.Response) *APIError {
+	return &APIError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("retry-after")),
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+type retryProvider struct {
+	LLMProvider
+	config RetryConfig
+}
+
+func WithRetry(provider LLMProvider, config RetryConfig) LLMProvider {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = time.Second
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = 30 * time.Second
+	}
+
+	return &retryProvider{
+		LLMProvider: provider,
+		config:      config,
+	}
+}
+
+func (p *retryProvider) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	var lastErr error
+
+	for attempt := 1; attempt <= p.config.MaxAttempts; attempt++ {
+		text, err := p.LLMProvider.Complete(ctx, prompt, maxTokens)
+		if err == nil {
+			return text, nil
+		}
+		lastErr = err
+
+		if !isRetryable(ctx, err) {
+			return "", err
+		}
+
+		if attempt == p.config.MaxAttempts {
+			break
+		}
+
+		delay := p.backoff(attempt, err)
+		slog.WarnContext(ctx, "LLM call failed, retrying",
+			"provider", p.Name(),
+			"attempt", attempt,
+			"max_attempts", p.config.MaxAttempts,
+			"delay_ms", delay.Milliseconds(),
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return "", fmt.Errorf("%w after %d attempts: %v", ErrLLMUnavailable, p.config.MaxAttempts, lastErr)
+}
+
+// backoff uses full jitter over an exponential window, but never waits less
+// than the provider asked for in retry-after.
+func (p *retryProvider) backoff(attempt int, err error) time.Duration {
+	window := p.config.BaseDelay << (attempt - 1)
+	if window > p.config.MaxDelay || window <= 0 {
+		window = p.config.MaxDelay
+	}
+
+	delay := time.Duration(rand.Int63n(int64(window) + 1))
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+
+	return delay
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	// An open circuit stays open for its cooldown; retrying only spins.
+	if errors.Is(err, outbound.ErrCircuitOpen) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout, 529:
+			return true
+		}
+		return false
+	}
+
+	// Transport failures (connection resets, timeouts) are worth another try;
+	// parse and format errors are not.
+	return errors.Is(err, errTransport)
+}
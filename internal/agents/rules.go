@@ -0,0 +1,76 @@
+package agents
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// maxRuleTags caps how many matched words become a thought's tags.
+const maxRuleTags = 4
+
+// CompileRule turns a category rule into the pattern it matches: a
+// /regular expression/, or else a keyword or phrase matched as whole
+// words. Both ignore case.
+func CompileRule(rule string) (*regexp.Regexp, error) {
+	rule = strings.TrimSpace(rule)
+	if rule == "" {
+		return nil, fmt.Errorf("rule is empty")
+	}
+
+	if len(rule) > 2 && strings.HasPrefix(rule, "/") && strings.HasSuffix(rule, "/") {
+		pattern, err := regexp.Compile("(?i)" + rule[1:len(rule)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", rule, err)
+		}
+		return pattern, nil
+	}
+
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(rule) + `\b`), nil
+}
+
+// matchRules files thought under the one category whose rules match it,
+// tagging it with the words they matched. A thought that no rule matches,
+// or that rules of several categories match, is left for the categorizer.
+func matchRules(thought *models.Thought, categories []*models.Category) bool {
+	var category string
+	var tags []string
+
+	for _, c := range categories {
+		var matched []string
+		for _, rule := range c.Rules {
+			pattern, err := CompileRule(rule)
+			if err != nil {
+				continue
+			}
+			if match := pattern.FindString(thought.Content); match != "" {
+				matched = append(matched, strings.ToLower(match))
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		if category != "" {
+			return false
+		}
+		category, tags = c.Name, matched
+	}
+	if category == "" {
+		return false
+	}
+
+	seen := make(map[string]bool)
+	thought.TopicTags = nil
+	for _, tag := range tags {
+		if !seen[tag] && len(thought.TopicTags) < maxRuleTags {
+			seen[tag] = true
+			thought.TopicTags = append(thought.TopicTags, tag)
+		}
+	}
+	thought.Category = category
+	thought.Status = "raw"
+
+	return true
+}
@@ -3,14 +3,20 @@ package agents
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/gcal"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
 )
 
 type SchedulerAgent struct {
-	postRepo *database.PostRepository
+	postRepo     *database.PostRepository
+	blackoutRepo *database.BlackoutRepository
+	postingDays  []time.Weekday
+	calendar     *gcal.Client
 }
 
 type ScheduleConfig struct {
@@ -18,11 +24,47 @@ type ScheduleConfig struct {
 	PreferredTimes []string
 	StartDate      time.Time
 	Timezone       string
+	// PostingDays overrides the scheduler's default posting days.
+	PostingDays []time.Weekday
 }
 
-func NewSchedulerAgent(postRepo *database.PostRepository) *SchedulerAgent {
+// EventSlot suggests posting about a talk or event right after it ends.
+type EventSlot struct {
+	Event gcal.Event
+	At    time.Time
+}
+
+// slotSearchDays bounds how far ahead the scheduler looks for a free slot.
+const slotSearchDays = 366
+
+// experimentGapDays is how far apart the two variations of an experiment
+// are published, so they meet the same weekday and time of day.
+const experimentGapDays = 7
+
+// calendarLookaheadDays bounds how far ahead away days are read from the
+// calendar; slots past it are scheduled as if the calendar were empty.
+const calendarLookaheadDays = 90
+
+// Posts suggested after an event go out eventFollowUp after it ends, or
+// the next morning when that would be later than eventLatestHour.
+const (
+	eventFollowUp      = 30 * time.Minute
+	eventLatestHour    = 20
+	eventNextDayPostAt = 9
+)
+
+// NewSchedulerAgent creates a scheduler. calendar may be nil, in which case
+// every posting day is treated as available.
+func NewSchedulerAgent(postRepo *database.PostRepository, blackoutRepo *database.BlackoutRepository, postingDays []time.Weekday, calendar *gcal.Client) *SchedulerAgent {
+	if len(postingDays) == 0 {
+		postingDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+
 	return &SchedulerAgent{
-		postRepo: postRepo,
+		postRepo:     postRepo,
+		blackoutRepo: blackoutRepo,
+		postingDays:  postingDays,
+		calendar:     calendar,
 	}
 }
 
@@ -36,55 +78,383 @@ func (s *SchedulerAgent) ScheduleApprovedPosts(ctx context.Context, config Sched
 		return 0, nil
 	}
 
+	search, err := s.newSlotSearch(ctx, config)
+	if err != nil {
+		return 0, err
+	}
+	engagement := search.engagement
+
+	// Slots are filled a week at a time. Without engagement history that is
+	// simply the earliest free slots; with it, the best scoring ones in the
+	// week, so posts never wait more than a week for a better slot.
+	var slots []time.Time
+	pending, ok := search.next()
+	for ok && len(slots) < len(approvedPosts) {
+		windowEnd := pending.AddDate(0, 0, 7)
+		var window []time.Time
+		for ok && pending.Before(windowEnd) {
+			window = append(window, pending)
+			pending, ok = search.next()
+		}
+
+		need := len(approvedPosts) - len(slots)
+		if engagement.Ready() && len(window) > need {
+			sort.SliceStable(window, func(i, j int) bool {
+				return engagement.Score(window[i]) > engagement.Score(window[j])
+			})
+			window = window[:need]
+			sort.Slice(window, func(i, j int) bool { return window[i].Before(window[j]) })
+		}
+		if len(window) > need {
+			window = window[:need]
+		}
+		slots = append(slots, window...)
+	}
+
+	scheduledCount := 0
+	for i, post := range approvedPosts {
+		if i >= len(slots) {
+			return scheduledCount, fmt.Errorf("no free posting slot in the next %d days", slotSearchDays)
+		}
+
+		scheduledTime := slots[i]
+		post.ScheduledAt = &scheduledTime
+		post.Status = "scheduled"
+
+		if err := s.postRepo.Update(ctx, post); err != nil {
+			continue
+		}
+
+		search.occupied[slotKey(scheduledTime)] = true
+		scheduledCount++
+	}
+
+	return scheduledCount, nil
+}
+
+// ScheduleExperiment schedules two approved variations of the same post a
+// week apart: the first in the next free slot, the second in the same
+// weekday and time a week later, or the first free slot after that.
+func (s *SchedulerAgent) ScheduleExperiment(ctx context.Context, config ScheduleConfig, first, second *models.Post) error {
+	search, err := s.newSlotSearch(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	firstAt, ok := search.next()
+	if !ok {
+		return fmt.Errorf("no free posting slot in the next %d days", slotSearchDays)
+	}
+
+	secondAt := firstAt.AddDate(0, 0, experimentGapDays)
+	if !search.free(secondAt) {
+		search.restart(secondAt)
+		if secondAt, ok = search.next(); !ok {
+			return fmt.Errorf("no free posting slot in the next %d days", slotSearchDays)
+		}
+	}
+
+	// Both go on the schedule or neither does, so a variation is never
+	// published without the other.
+	if err := s.postRepo.ScheduleAll(ctx, map[string]time.Time{first.ID: firstAt, second.ID: secondAt}); err != nil {
+		return err
+	}
+	first.Status, first.ScheduledAt = "scheduled", &firstAt
+	second.Status, second.ScheduledAt = "scheduled", &secondAt
+
+	return nil
+}
+
+// slotSearch walks the posting slots in time order, skipping ones that are
+// taken, past, on days that aren't posting days, on away days or in a
+// blackout.
+type slotSearch struct {
+	scheduler  *SchedulerAgent
+	times      []string
+	location   *time.Location
+	allowed    map[time.Weekday]bool
+	occupied   map[string]bool
+	away       map[string]bool
+	blackouts  []*models.Blackout
+	engagement *Engagement
+	now        time.Time
+
+	date     time.Time
+	index    int
+	searched int
+}
+
+func (s *SchedulerAgent) newSlotSearch(ctx context.Context, config ScheduleConfig) (*slotSearch, error) {
 	location, err := time.LoadLocation(config.Timezone)
 	if err != nil {
 		location = time.UTC
 	}
 
+	engagement := s.engagement(ctx, location)
+
 	if len(config.PreferredTimes) == 0 {
 		config.PreferredTimes = s.getDefaultTimes(config.PostsPerDay)
+		if engagement.Ready() {
+			config.PreferredTimes = engagement.BestTimes(len(config.PreferredTimes), config.PreferredTimes)
+		}
 	}
 
-	scheduledCount := 0
-	currentDate := config.StartDate
-	timeSlotIndex := 0
+	postingDays := config.PostingDays
+	if len(postingDays) == 0 {
+		postingDays = s.postingDays
+	}
+	allowedDays := make(map[time.Weekday]bool)
+	for _, day := range postingDays {
+		allowedDays[day] = true
+	}
+
+	occupied, err := s.occupiedSlots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slotSearch{
+		scheduler:  s,
+		times:      config.PreferredTimes,
+		location:   location,
+		allowed:    allowedDays,
+		occupied:   occupied,
+		away:       s.awayDays(ctx, config.StartDate, calendarLookaheadDays, location),
+		blackouts:  s.blackouts(ctx, config.StartDate.In(location)),
+		engagement: engagement,
+		now:        time.Now(),
+		date:       config.StartDate.In(location),
+	}, nil
+}
+
+// next returns the next free posting slot.
+func (search *slotSearch) next() (time.Time, bool) {
+	for ; search.searched < slotSearchDays*len(search.times); search.searched++ {
+		candidate, err := search.scheduler.calculateScheduledTime(search.date, search.times[search.index], search.location)
+
+		search.index++
+		if search.index >= len(search.times) {
+			search.index = 0
+			search.date = search.date.AddDate(0, 0, 1)
+		}
 
-	for _, post := range approvedPosts {
-		scheduledTime, err := s.calculateScheduledTime(currentDate, config.PreferredTimes[timeSlotIndex], location)
-		if err != nil {
+		if err != nil || candidate.Before(search.now) || !search.free(candidate) {
 			continue
 		}
 
-		post.ScheduledAt = &scheduledTime
-		post.Status = "scheduled"
+		search.searched++
+		return candidate, true
+	}
+	return time.Time{}, false
+}
 
-		if err := s.postRepo.Update(ctx, post); err != nil {
+// free reports whether a post could go out at t.
+func (search *slotSearch) free(t time.Time) bool {
+	day := t.In(search.location).Format(models.DateLayout)
+	return search.allowed[t.Weekday()] && !search.occupied[slotKey(t)] && !search.away[day] && models.Covering(search.blackouts, day) == nil
+}
+
+// restart continues the search from the first slot at or after t.
+func (search *slotSearch) restart(t time.Time) {
+	search.date = t.In(search.location)
+	search.index = 0
+	search.searched = 0
+	search.now = t
+}
+
+// engagement learns from published posts which slots do best. It returns
+// nil when they can't be read, which schedules as if there were no history.
+func (s *SchedulerAgent) engagement(ctx context.Context, location *time.Location) *Engagement {
+	published, err := s.postRepo.GetByStatus(ctx, "published")
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read published posts, scheduling at default times", "error", err)
+		return nil
+	}
+
+	return NewEngagement(published, location)
+}
+
+// PostingTimes returns the times ScheduleApprovedPosts uses for postsPerDay
+// posts a day, and whether they were picked from past engagement rather
+// than being the defaults.
+func (s *SchedulerAgent) PostingTimes(ctx context.Context, postsPerDay int, location *time.Location) ([]string, bool) {
+	defaults := s.getDefaultTimes(postsPerDay)
+
+	engagement := s.engagement(ctx, location)
+	if !engagement.Ready() {
+		return defaults, false
+	}
+
+	return engagement.BestTimes(len(defaults), defaults), true
+}
+
+func (s *SchedulerAgent) occupiedSlots(ctx context.Context) (map[string]bool, error) {
+	scheduledPosts, err := s.postRepo.GetByStatus(ctx, "scheduled")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled posts: %w", err)
+	}
+
+	occupied := make(map[string]bool)
+	for _, post := range scheduledPosts {
+		if post.ScheduledAt != nil {
+			occupied[slotKey(*post.ScheduledAt)] = true
+		}
+	}
+
+	return occupied, nil
+}
+
+func slotKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04")
+}
+
+// awayDays returns the dates, as 2006-01-02 in location, that the calendar
+// marks as away in the days days from start. A calendar that can't be read
+// is logged and treated as empty so scheduling still works.
+func (s *SchedulerAgent) awayDays(ctx context.Context, start time.Time, days int, location *time.Location) map[string]bool {
+	away := make(map[string]bool)
+	if s.calendar == nil {
+		return away
+	}
+
+	events, err := s.calendar.Events(ctx, start, start.AddDate(0, 0, days))
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read calendar, scheduling without it", "error", err)
+		return away
+	}
+
+	for _, event := range events {
+		if event.Kind != gcal.KindAway {
 			continue
 		}
+		for _, day := range event.Days(location) {
+			away[day] = true
+		}
+	}
 
-		scheduledCount++
+	return away
+}
 
-		timeSlotIndex++
-		if timeSlotIndex >= len(config.PreferredTimes) {
-			timeSlotIndex = 0
-			currentDate = currentDate.AddDate(0, 0, 1)
+// blackouts returns the blackouts and pauses with an end date not over by
+// from. A pause until resumed only holds the publisher back, so posts still
+// get slots for when it lifts. Blackouts that can't be read are logged and
+// treated as none so scheduling still works.
+func (s *SchedulerAgent) blackouts(ctx context.Context, from time.Time) []*models.Blackout {
+	if s.blackoutRepo == nil {
+		return nil
+	}
+
+	blackouts, err := s.blackoutRepo.ListActive(ctx, from)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read blackouts, scheduling without them", "error", err)
+		return nil
+	}
+
+	var dated []*models.Blackout
+	for _, blackout := range blackouts {
+		if blackout.EndDate != nil {
+			dated = append(dated, blackout)
 		}
 	}
+	return dated
+}
 
-	return scheduledCount, nil
+// EventSlots suggests when to post about the talks and events on the
+// calendar in the next days days: shortly after each one ends, or the next
+// morning for events that end late. Suggestions falling on away days or in
+// a blackout are left out.
+func (s *SchedulerAgent) EventSlots(ctx context.Context, days int, location *time.Location) ([]EventSlot, error) {
+	if s.calendar == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	events, err := s.calendar.Events(ctx, now, now.AddDate(0, 0, days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	away := make(map[string]bool)
+	for _, event := range events {
+		if event.Kind == gcal.KindAway {
+			for _, day := range event.Days(location) {
+				away[day] = true
+			}
+		}
+	}
+
+	blackouts := s.blackouts(ctx, now.In(location))
+
+	var slots []EventSlot
+	for _, event := range events {
+		if event.Kind != gcal.KindTalk {
+			continue
+		}
+
+		at := followUpTime(event, location)
+		day := at.Format(models.DateLayout)
+		if at.Before(now) || away[day] || models.Covering(blackouts, day) != nil {
+			continue
+		}
+
+		slots = append(slots, EventSlot{Event: event, At: at})
+	}
+
+	return slots, nil
 }
 
-func (s *SchedulerAgent) GetSchedule(ctx context.Context, days int) ([]*models.Post, error) {
+func followUpTime(event gcal.Event, location *time.Location) time.Time {
+	if event.AllDay {
+		end := event.End
+		return time.Date(end.Year(), end.Month(), end.Day(), eventNextDayPostAt, 0, 0, 0, location)
+	}
+
+	// Round up to the next quarter hour.
+	at := event.End.In(location).Add(eventFollowUp)
+	if rounded := at.Truncate(15 * time.Minute); rounded.Before(at) {
+		at = rounded.Add(15 * time.Minute)
+	}
+	if at.Hour() >= eventLatestHour {
+		next := at.AddDate(0, 0, 1)
+		at = time.Date(next.Year(), next.Month(), next.Day(), eventNextDayPostAt, 0, 0, 0, location)
+	}
+
+	return at
+}
+
+// GetUpcoming returns every scheduled post in posting order. Post numbers
+// shown in Slack are positions in this list.
+func (s *SchedulerAgent) GetUpcoming(ctx context.Context) ([]*models.Post, error) {
 	scheduledPosts, err := s.postRepo.GetByStatus(ctx, "scheduled")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get scheduled posts: %w", err)
 	}
 
+	var upcoming []*models.Post
+	for _, post := range scheduledPosts {
+		if post.ScheduledAt != nil {
+			upcoming = append(upcoming, post)
+		}
+	}
+
+	sort.Slice(upcoming, func(i, j int) bool {
+		return upcoming[i].ScheduledAt.Before(*upcoming[j].ScheduledAt)
+	})
+
+	return upcoming, nil
+}
+
+func (s *SchedulerAgent) GetSchedule(ctx context.Context, days int) ([]*models.Post, error) {
+	upcoming, err := s.GetUpcoming(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	cutoffDate := time.Now().AddDate(0, 0, days)
 	var filteredPosts []*models.Post
 
-	for _, post := range scheduledPosts {
-		if post.ScheduledAt != nil && post.ScheduledAt.Before(cutoffDate) {
+	for _, post := range upcoming {
+		if post.ScheduledAt.Before(cutoffDate) {
 			filteredPosts = append(filteredPosts, post)
 		}
 	}
@@ -92,6 +462,42 @@ func (s *SchedulerAgent) GetSchedule(ctx context.Context, days int) ([]*models.P
 	return filteredPosts, nil
 }
 
+// OpenPostingDays returns the posting days in the next days days, starting
+// today in location, that have nothing scheduled and aren't away days or in
+// a blackout.
+func (s *SchedulerAgent) OpenPostingDays(ctx context.Context, days int, location *time.Location) ([]time.Time, error) {
+	upcoming, err := s.GetUpcoming(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool)
+	for _, post := range upcoming {
+		taken[post.ScheduledAt.In(location).Format("2006-01-02")] = true
+	}
+
+	allowedDays := make(map[time.Weekday]bool)
+	for _, day := range s.postingDays {
+		allowedDays[day] = true
+	}
+
+	now := time.Now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	away := s.awayDays(ctx, today, days, location)
+	blackouts := s.blackouts(ctx, today)
+
+	var open []time.Time
+	for i := 0; i < days; i++ {
+		day := today.AddDate(0, 0, i)
+		key := day.Format(models.DateLayout)
+		if allowedDays[day.Weekday()] && !taken[key] && !away[key] && models.Covering(blackouts, key) == nil {
+			open = append(open, day)
+		}
+	}
+
+	return open, nil
+}
+
 func (s *SchedulerAgent) ReschedulePost(ctx context.Context, postID string, newTime time.Time) error {
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
@@ -171,4 +577,4 @@ func (s *SchedulerAgent) GetNextScheduledPost(ctx context.Context) (*models.Post
 	}
 
 	return scheduledPosts[0], nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,21 @@
+package agents
+
+import "context"
+
+// StreamFunc is called with the text a model has written so far, each time
+// more of it arrives.
+type StreamFunc func(text string)
+
+type streamKey struct{}
+
+// WithStream returns ctx in which completions call fn as the model writes.
+// Providers that can't stream ignore it and return the whole text at the
+// end. A retried call starts the text over.
+func WithStream(ctx context.Context, fn StreamFunc) context.Context {
+	return context.WithValue(ctx, streamKey{}, fn)
+}
+
+func streamFrom(ctx context.Context) StreamFunc {
+	fn, _ := ctx.Value(streamKey{}).(StreamFunc)
+	return fn
+}
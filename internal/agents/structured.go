@@ -0,0 +1,175 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Schema is a JSON Schema describing the object a structured response is.
+type Schema map[string]any
+
+type schemaKey struct{}
+
+// withSchema returns ctx in which completions ask the model for a JSON
+// object matching schema: Anthropic through a forced tool call, OpenAI and
+// Ollama through their JSON modes.
+func withSchema(ctx context.Context, schema Schema) context.Context {
+	return context.WithValue(ctx, schemaKey{}, schema)
+}
+
+func schemaFrom(ctx context.Context) Schema {
+	schema, _ := ctx.Value(schemaKey{}).(Schema)
+	return schema
+}
+
+func objectSchema(properties map[string]Schema, required ...string) Schema {
+	return Schema{"type": "object", "properties": properties, "required": required}
+}
+
+func stringSchema(description string) Schema {
+	return Schema{"type": "string", "description": description}
+}
+
+func integerSchema(description string) Schema {
+	return Schema{"type": "integer", "description": description}
+}
+
+func enumSchema(description string, values ...string) Schema {
+	return Schema{"type": "string", "description": description, "enum": values}
+}
+
+func arraySchema(description string, items Schema) Schema {
+	return Schema{"type": "array", "description": description, "items": items}
+}
+
+// structured is a response decoded from JSON, which checks what the
+// schema can't once decoded.
+type structured interface {
+	validate() error
+}
+
+// completeJSON asks llm for a JSON object matching schema and decodes it
+// into out. A response that doesn't decode or validate is sent back once,
+// with what was wrong with it, for the model to repair.
+func completeJSON(ctx context.Context, llm LLMProvider, prompt string, maxTokens int, schema Schema, out structured) error {
+	schemaText, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	prompt += "\n\nRespond with ONLY a JSON object matching this JSON Schema:\n" + string(schemaText)
+
+	ctx = withSchema(ctx, schema)
+	responseText, err := llm.Complete(ctx, prompt, maxTokens)
+	if err != nil {
+		return err
+	}
+
+	invalid := decodeJSON(responseText, out)
+	if invalid == nil {
+		return nil
+	}
+	slog.WarnContext(ctx, "Structured response was invalid, asking for a repair", "error", invalid)
+
+	repair := fmt.Sprintf(`%s
+
+Your previous response could not be used: %v
+
+It was:
+"""
+%s
+"""
+
+Respond again with ONLY the corrected JSON object.`, prompt, invalid, responseText)
+
+	// The repair isn't streamed, so it can't garble what was shown so far.
+	responseText, err = llm.Complete(WithStream(ctx, nil), repair, maxTokens)
+	if err != nil {
+		return err
+	}
+
+	if err := decodeJSON(responseText, out); err != nil {
+		return fmt.Errorf("invalid structured response: %w", err)
+	}
+
+	return nil
+}
+
+// decodeJSON reads the JSON object in response into out and validates it.
+// Text around the object, such as a code fence, is ignored.
+func decodeJSON(response string, out structured) error {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		return fmt.Errorf("response is not a JSON object")
+	}
+
+	if err := json.Unmarshal([]byte(response[start:end+1]), out); err != nil {
+		return fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	return out.validate()
+}
+
+// partialStrings returns the values of the string fields called key in a
+// JSON object still being streamed, the last of them possibly cut short.
+func partialStrings(text, key string) []string {
+	var values []string
+
+	marker := `"` + key + `"`
+	for {
+		idx := strings.Index(text, marker)
+		if idx == -1 {
+			return values
+		}
+		text = strings.TrimLeft(text[idx+len(marker):], " \t\r\n")
+
+		rest, ok := strings.CutPrefix(text, ":")
+		if !ok {
+			continue
+		}
+		rest, ok = strings.CutPrefix(strings.TrimLeft(rest, " \t\r\n"), `"`)
+		if !ok {
+			continue
+		}
+
+		raw, closed := scanString(rest)
+		var value string
+		if err := json.Unmarshal([]byte(`"`+raw+`"`), &value); err != nil {
+			value = raw
+		}
+		values = append(values, value)
+
+		if !closed {
+			return values
+		}
+		text = rest[len(raw)+1:]
+	}
+}
+
+// scanString returns the escaped contents of a JSON string up to its
+// closing quote, and whether the quote was reached. An escape cut short
+// at the end is dropped.
+func scanString(text string) (string, bool) {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			return text[:i], true
+		case '\\':
+			if i+1 >= len(text) {
+				return text[:i], false
+			}
+			if text[i+1] == 'u' {
+				if i+6 > len(text) {
+					return text[:i], false
+				}
+				i += 5
+				continue
+			}
+			i++
+		}
+	}
+	return text, false
+}
@@ -0,0 +1,141 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type StyleAnalyzerAgent struct {
+	llm LLMProvider
+}
+
+var stylePatternKeys = []string{"tone", "sentence_length", "emoji_usage", "hook_style", "formatting", "vocabulary"}
+
+// styleResponse has a field per style pattern key, and the author's
+// preferred tones and signature elements.
+type styleResponse struct {
+	Patterns          map[string]string `json:"-"`
+	TonePreferences   []string          `json:"tone_preferences"`
+	SignatureElements []string          `json:"signature_elements"`
+}
+
+var styleSchema = func() Schema {
+	properties := map[string]Schema{
+		"tone":               stringSchema("Overall tone, e.g. candid and playful"),
+		"sentence_length":    stringSchema("Typical sentence length and rhythm"),
+		"emoji_usage":        stringSchema("How often and which kinds of emojis they use, or none"),
+		"hook_style":         stringSchema("How their opening lines grab attention"),
+		"formatting":         stringSchema("Paragraph length, line breaks, lists, hashtags"),
+		"vocabulary":         stringSchema("Characteristic words, phrases or jargon level"),
+		"tone_preferences":   arraySchema("2-4 tones they write in", stringSchema("")),
+		"signature_elements": arraySchema("2-4 elements that make their posts recognizable", stringSchema("")),
+	}
+	return objectSchema(properties, append(append([]string{}, stylePatternKeys...), "tone_preferences", "signature_elements")...)
+}()
+
+func (r *styleResponse) UnmarshalJSON(data []byte) error {
+	type lists styleResponse
+	if err := json.Unmarshal(data, (*lists)(r)); err != nil {
+		return err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	r.Patterns = make(map[string]string)
+	for _, key := range stylePatternKeys {
+		if value, ok := fields[key].(string); ok && strings.TrimSpace(value) != "" {
+			r.Patterns[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return nil
+}
+
+func (r *styleResponse) validate() error {
+	if len(r.Patterns) == 0 {
+		return fmt.Errorf("no style pattern was described")
+	}
+	r.TonePreferences = trimList(r.TonePreferences)
+	r.SignatureElements = trimList(r.SignatureElements)
+	return nil
+}
+
+func NewStyleAnalyzerAgent(llm LLMProvider) *StyleAnalyzerAgent {
+	if llm == nil {
+		slog.Error("LLM provider is required")
+		os.Exit(1)
+	}
+
+	return &StyleAnalyzerAgent{
+		llm: llm,
+	}
+}
+
+func (a *StyleAnalyzerAgent) AnalyzeStyle(ctx context.Context, profile *models.StyleProfile) error {
+	if len(profile.SamplePosts) == 0 {
+		return fmt.Errorf("no sample posts provided")
+	}
+
+	var postsText string
+	for i, post := range profile.SamplePosts {
+		postsText += fmt.Sprintf("\n===POST %d===\n%s\n", i+1, post)
+	}
+
+	prompt := fmt.Sprintf(`You are analyzing the writing style of a LinkedIn author so a ghostwriter can imitate it.
+
+Here are posts they wrote:
+%s
+Describe their style, one short phrase or sentence per aspect.`, postsText)
+
+	var response styleResponse
+	if err := completeJSON(ctx, a.llm, prompt, 1000, styleSchema, &response); err != nil {
+		return fmt.Errorf("failed to extract style patterns: %w", err)
+	}
+
+	profile.StylePatterns = response.Patterns
+	profile.TonePreferences = response.TonePreferences
+	profile.HighPerformingElements = response.SignatureElements
+
+	return nil
+}
+
+func trimList(items []string) []string {
+	var trimmed []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			trimmed = append(trimmed, item)
+		}
+	}
+	return trimmed
+}
+
+func FormatStyleGuide(profile *models.StyleProfile) string {
+	if profile == nil || len(profile.StylePatterns) == 0 {
+		return ""
+	}
+
+	var guide string
+	for _, key := range stylePatternKeys {
+		if value, ok := profile.StylePatterns[key]; ok {
+			guide += fmt.Sprintf("- %s: %s\n", strings.ReplaceAll(key, "_", " "), value)
+		}
+	}
+
+	if len(profile.TonePreferences) > 0 {
+		guide += fmt.Sprintf("- preferred tones: %s\n", strings.Join(profile.TonePreferences, ", "))
+	}
+
+	if len(profile.HighPerformingElements) > 0 {
+		guide += fmt.Sprintf("- signature elements: %s\n", strings.Join(profile.HighPerformingElements, ", "))
+	}
+
+	return strings.TrimSpace(guide)
+}
@@ -0,0 +1,99 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type SummarizerAgent struct {
+	llm LLMProvider
+}
+
+func NewSummarizerAgent(llm LLMProvider) *SummarizerAgent {
+	if llm == nil {
+		slog.Error("LLM provider is required")
+		os.Exit(1)
+	}
+
+	return &SummarizerAgent{
+		llm: llm,
+	}
+}
+
+// Summarize condenses an article or document the author shared into notes
+// a ghostwriter can build a post from. note is whatever the author wrote
+// alongside it and steers what the summary focuses on.
+func (a *SummarizerAgent) Summarize(ctx context.Context, note, title, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("no text to summarize")
+	}
+
+	var noteSection string
+	if strings.TrimSpace(note) != "" {
+		noteSection = fmt.Sprintf("\nThe author shared it with this note, focus on what they found interesting:\n\"%s\"\n", note)
+	}
+
+	prompt := fmt.Sprintf(`You are helping a LinkedIn author keep notes on things they read.
+
+Title: %s
+%s
+Content:
+"""
+%s
+"""
+
+Summarize the content in 3-5 bullet points covering the key ideas, claims and any
+specific numbers or examples worth quoting. Stay factual and don't add opinions.
+
+Respond with ONLY the bullet points, no preamble.`, title, noteSection, text)
+
+	responseText, err := a.llm.Complete(ctx, prompt, 600)
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(responseText)
+	if summary == "" {
+		return "", fmt.Errorf("failed to generate summary")
+	}
+
+	return summary, nil
+}
+
+// SummarizeMilestone writes up a week of progress on project from the
+// issues completed in it, each given as a title followed by any details.
+func (a *SummarizerAgent) SummarizeMilestone(ctx context.Context, project string, issues []string) (string, error) {
+	if len(issues) == 0 {
+		return "", fmt.Errorf("no issues to summarize")
+	}
+
+	prompt := fmt.Sprintf(`You are helping a LinkedIn author keep notes on what their team shipped.
+
+These issues were completed on %s this week:
+"""
+- %s
+"""
+
+Summarize the week's progress in 2-4 sentences, as a milestone the author could
+post about: what moved forward, why it matters to users, and any specific
+numbers or details worth quoting. Group related issues rather than listing
+them one by one, and leave out internal chores. Stay factual and don't add
+opinions.
+
+Respond with ONLY the summary, no preamble.`, project, strings.Join(issues, "\n- "))
+
+	responseText, err := a.llm.Complete(ctx, prompt, 600)
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(responseText)
+	if summary == "" {
+		return "", fmt.Errorf("failed to generate milestone summary")
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,199 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+)
+
+// modelCheckTimeout bounds the test call made before switching models.
+const modelCheckTimeout = 30 * time.Second
+
+// LLM roles whose model can be switched at runtime.
+const (
+	RoleGeneration  = "generation"
+	RoleCategorizer = "categorizer"
+)
+
+// SwitchableProvider is an LLMProvider whose provider and model can be
+// changed while the bot runs. Calls already in flight finish on the model
+// they started with.
+type SwitchableProvider struct {
+	config ProviderConfig
+
+	mu      sync.RWMutex
+	current LLMProvider
+}
+
+func NewSwitchableProvider(cfg ProviderConfig) (*SwitchableProvider, error) {
+	provider, err := NewLLMProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SwitchableProvider{config: cfg, current: provider}, nil
+}
+
+func (p *SwitchableProvider) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	return p.get().Complete(ctx, prompt, maxTokens)
+}
+
+func (p *SwitchableProvider) Name() string {
+	return p.get().Name()
+}
+
+func (p *SwitchableProvider) Model() string {
+	return p.get().Model()
+}
+
+// Switch moves to model on provider. An empty provider keeps the configured
+// one. An empty model keeps the configured model, or takes the provider's
+// default when the provider changes. Switch("", "") goes back to the
+// configuration the bot started with.
+func (p *SwitchableProvider) Switch(provider, model string) error {
+	next, err := p.build(provider, model)
+	if err != nil {
+		return err
+	}
+
+	p.set(next)
+	return nil
+}
+
+// SwitchChecked is Switch, but first makes one small call to the new model
+// and stays on the current one if it fails, so a mistyped model name is
+// caught before anything is generated with it.
+func (p *SwitchableProvider) SwitchChecked(ctx context.Context, provider, model string) error {
+	next, err := p.build(provider, model)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, modelCheckTimeout)
+	defer cancel()
+
+	if _, err := next.Complete(ctx, "Reply with OK.", 5); err != nil {
+		return fmt.Errorf("%s model %s didn't answer a test call: %w", next.Name(), next.Model(), err)
+	}
+
+	p.set(next)
+	return nil
+}
+
+func (p *SwitchableProvider) build(provider, model string) (LLMProvider, error) {
+	cfg := p.config
+	if provider != "" && !strings.EqualFold(provider, p.config.Provider) {
+		cfg.Provider = provider
+		cfg.Model = ""
+	}
+	if model != "" {
+		cfg.Model = model
+	}
+
+	return NewLLMProvider(cfg)
+}
+
+func (p *SwitchableProvider) set(next LLMProvider) {
+	p.mu.Lock()
+	p.current = next
+	p.mu.Unlock()
+}
+
+func (p *SwitchableProvider) get() LLMProvider {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.current
+}
+
+// ModelRegistry tracks the switchable provider of each role and stores
+// switches in settings, so they survive restarts.
+type ModelRegistry struct {
+	settings *database.SettingsRepository
+	roles    map[string]*SwitchableProvider
+}
+
+type modelSetting struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+}
+
+func NewModelRegistry(settings *database.SettingsRepository) *ModelRegistry {
+	return &ModelRegistry{settings: settings, roles: make(map[string]*SwitchableProvider)}
+}
+
+// Register adds the provider for role and moves it to the model last
+// switched to, if any. A stored model that no longer works is logged and
+// the configured one kept.
+func (r *ModelRegistry) Register(ctx context.Context, role string, provider *SwitchableProvider) {
+	r.roles[role] = provider
+
+	value, err := r.settings.Get(ctx, settingKey(role))
+	if err != nil || value == "" {
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to load model setting", "role", role, "error", err)
+		}
+		return
+	}
+
+	var setting modelSetting
+	if err := json.Unmarshal([]byte(value), &setting); err != nil {
+		slog.WarnContext(ctx, "Ignoring invalid model setting", "role", role, "value", value)
+		return
+	}
+
+	if err := provider.Switch(setting.Provider, setting.Model); err != nil {
+		slog.WarnContext(ctx, "Ignoring stored model setting", "role", role, "error", err)
+		return
+	}
+
+	slog.InfoContext(ctx, "Using stored model setting", "role", role, "provider", provider.Name(), "model", provider.Model())
+}
+
+// Get returns the provider for role, or nil for an unknown role.
+func (r *ModelRegistry) Get(role string) *SwitchableProvider {
+	return r.roles[role]
+}
+
+// Switch changes the model of role and remembers it. The new model must
+// answer a test call first. userID is recorded as who changed it.
+func (r *ModelRegistry) Switch(ctx context.Context, role, provider, model, userID string) error {
+	switchable := r.roles[role]
+	if switchable == nil {
+		return fmt.Errorf("unknown model role %q", role)
+	}
+
+	if err := switchable.SwitchChecked(ctx, provider, model); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(modelSetting{Provider: switchable.Name(), Model: switchable.Model()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal model setting: %w", err)
+	}
+
+	return r.settings.Set(ctx, settingKey(role), string(value), userID)
+}
+
+// Reset puts role back on the configured model.
+func (r *ModelRegistry) Reset(ctx context.Context, role string) error {
+	switchable := r.roles[role]
+	if switchable == nil {
+		return fmt.Errorf("unknown model role %q", role)
+	}
+
+	if err := switchable.Switch("", ""); err != nil {
+		return err
+	}
+
+	return r.settings.Delete(ctx, settingKey(role))
+}
+
+func settingKey(role string) string {
+	return "model." + role
+}
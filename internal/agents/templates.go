@@ -0,0 +1,37 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type templateKey struct{}
+
+// WithTemplate returns ctx in which every variation written follows the
+// skeleton of template.
+func WithTemplate(ctx context.Context, template *models.PostTemplate) context.Context {
+	return context.WithValue(ctx, templateKey{}, template)
+}
+
+func templateFrom(ctx context.Context) *models.PostTemplate {
+	template, _ := ctx.Value(templateKey{}).(*models.PostTemplate)
+	return template
+}
+
+// templateSection tells the model to follow the template ctx asks for. It
+// goes after the rendered prompt, so custom prompts follow templates too.
+func templateSection(ctx context.Context) string {
+	template := templateFrom(ctx)
+	if template == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+
+Every variation MUST follow the %q structure below, part by part and in this order. Vary the angle, hook and details between variations, never the structure. Don't label the parts in the post.
+"""
+%s
+"""`, template.Name, template.Skeleton)
+}
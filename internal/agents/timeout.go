@@ -0,0 +1,37 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type timeoutProvider struct {
+	LLMProvider
+	timeout time.Duration
+}
+
+// WithTimeout gives every completion its own deadline, so a hung connection
+// fails the call instead of blocking the caller until its context ends.
+// Wrapped in WithRetry, each attempt gets the full timeout and a timed out
+// attempt is retried like any other transport failure. A zero timeout leaves calls bounded only by ctx.
+func WithTimeout(provider LLMProvider, timeout time.Duration) LLMProvider {
+	if timeout <= 0 {
+		return provider
+	}
+
+	return &timeoutProvider{LLMProvider: provider, timeout: timeout}
+}
+
+func (p *timeoutProvider) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	text, err := p.LLMProvider.Complete(callCtx, prompt, maxTokens)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("%s call timed out after %s: %w: %w", p.Name(), p.timeout, errTransport, err)
+	}
+
+	return text, err
+}
@@ -0,0 +1,43 @@
+package agents
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/shubh-37/linkedin-ghostwriter/internal/agents")
+
+type tracingProvider struct {
+	LLMProvider
+}
+
+// WithTracing records every completion, including any retries inside it, as
+// one span. Token counts are added by the provider that made the call.
+func WithTracing(provider LLMProvider) LLMProvider {
+	return &tracingProvider{LLMProvider: provider}
+}
+
+func (p *tracingProvider) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	ctx, span := tracer.Start(ctx, "llm "+p.Name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("gen_ai.system", p.Name()),
+			attribute.String("gen_ai.request.model", p.Model()),
+			attribute.Int("gen_ai.request.max_tokens", maxTokens),
+			attribute.Int("llm.prompt_chars", len(prompt)),
+		),
+	)
+	defer span.End()
+
+	text, err := p.LLMProvider.Complete(ctx, prompt, maxTokens)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return text, err
+}
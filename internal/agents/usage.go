@@ -0,0 +1,247 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// ErrBudgetExceeded is returned instead of calling the model once this
+// month's budget is spent.
+var ErrBudgetExceeded = errors.New("monthly LLM budget exceeded")
+
+// budgetWarnedSetting remembers the last budget warning sent, as
+// "2006-01:warn" or "2006-01:exceeded", so each is sent once a month.
+const budgetWarnedSetting = "usage.budget_warned"
+
+type commandKey struct{}
+
+// WithCommand returns ctx in which model calls are attributed to command,
+// such as "generate".
+func WithCommand(ctx context.Context, command string) context.Context {
+	return context.WithValue(ctx, commandKey{}, command)
+}
+
+func commandFrom(ctx context.Context) string {
+	command, _ := ctx.Value(commandKey{}).(string)
+	return command
+}
+
+type tokensKey struct{}
+
+// tokenCount adds up the tokens of every attempt at one call.
+type tokenCount struct {
+	mu     sync.Mutex
+	input  int
+	output int
+}
+
+func countTokens(ctx context.Context, inputTokens, outputTokens int) {
+	count, ok := ctx.Value(tokensKey{}).(*tokenCount)
+	if !ok {
+		return
+	}
+	count.mu.Lock()
+	count.input += inputTokens
+	count.output += outputTokens
+	count.mu.Unlock()
+}
+
+// modelPrice is what a model costs, in US dollars per million tokens.
+type modelPrice struct {
+	prefix string
+	input  float64
+	output float64
+}
+
+// modelPrices are matched by prefix in order, so more specific models come
+// first. Models not listed, such as those run on Ollama, cost nothing.
+var modelPrices = []modelPrice{
+	{"claude-opus-4-5", 5, 25},
+	{"claude-opus-4", 15, 75},
+	{"claude-3-opus", 15, 75},
+	{"claude-sonnet-4", 3, 15},
+	{"claude-3-7-sonnet", 3, 15},
+	{"claude-3-5-sonnet", 3, 15},
+	{"claude-haiku-4", 1, 5},
+	{"claude-3-5-haiku", 0.8, 4},
+	{"claude-3-haiku", 0.25, 1.25},
+	{"gpt-4o-mini", 0.15, 0.6},
+	{"gpt-4o", 2.5, 10},
+	{"gpt-4.1-nano", 0.1, 0.4},
+	{"gpt-4.1-mini", 0.4, 1.6},
+	{"gpt-4.1", 2, 8},
+}
+
+// EstimateCost returns what a call to model costs in US dollars.
+func EstimateCost(model string, inputTokens, outputTokens int) float64 {
+	for _, price := range modelPrices {
+		if strings.HasPrefix(model, price.prefix) {
+			return (float64(inputTokens)*price.input + float64(outputTokens)*price.output) / 1e6
+		}
+	}
+	return 0
+}
+
+// UsageNotifier is told when the month's budget is nearly or fully spent.
+type UsageNotifier interface {
+	SendMessage(channelID, message string) error
+}
+
+// UsageTracker records the tokens and cost of model calls in llm_usage and
+// keeps generation within a monthly budget, in US dollars. A zero budget
+// records usage without limiting it.
+type UsageTracker struct {
+	repo     *database.UsageRepository
+	settings *database.SettingsRepository
+	budget   float64
+	warnAt   float64
+	notifier UsageNotifier
+	channel  string
+}
+
+// NewUsageTracker warns in channel once warnAt of budget is spent, and again
+// when all of it is. notifier may be nil.
+func NewUsageTracker(repo *database.UsageRepository, settings *database.SettingsRepository, budget, warnAt float64, notifier UsageNotifier, channel string) *UsageTracker {
+	return &UsageTracker{
+		repo:     repo,
+		settings: settings,
+		budget:   budget,
+		warnAt:   warnAt,
+		notifier: notifier,
+		channel:  channel,
+	}
+}
+
+// Budget returns the monthly budget, or 0 when there is none.
+func (t *UsageTracker) Budget() float64 {
+	return t.budget
+}
+
+// MonthStart returns the start of the budget month at falls in. Months
+// follow UTC, as providers bill them.
+func MonthStart(at time.Time) time.Time {
+	at = at.UTC()
+	return time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Track wraps provider so its calls are recorded under role. When enforce
+// is set, calls fail with ErrBudgetExceeded once the budget is spent.
+func (t *UsageTracker) Track(provider LLMProvider, role string, enforce bool) LLMProvider {
+	return &usageProvider{LLMProvider: provider, tracker: t, role: role, enforce: enforce}
+}
+
+type usageProvider struct {
+	LLMProvider
+	tracker *UsageTracker
+	role    string
+	enforce bool
+}
+
+func (p *usageProvider) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	if p.enforce {
+		if spent, over := p.tracker.overBudget(ctx); over {
+			return "", fmt.Errorf("%w: $%.2f of $%.2f spent this month", ErrBudgetExceeded, spent, p.tracker.budget)
+		}
+	}
+
+	count := &tokenCount{}
+	text, err := p.LLMProvider.Complete(context.WithValue(ctx, tokensKey{}, count), prompt, maxTokens)
+
+	count.mu.Lock()
+	inputTokens, outputTokens := count.input, count.output
+	count.mu.Unlock()
+	if inputTokens+outputTokens > 0 {
+		p.tracker.record(ctx, &models.LLMUsage{
+			Provider:     p.Name(),
+			Model:        p.Model(),
+			Role:         p.role,
+			Command:      commandFrom(ctx),
+			UserID:       database.ActorFrom(ctx),
+			InputTokens:  inputTokens,
+			OutputTokens: outputTokens,
+			Cost:         EstimateCost(p.Model(), inputTokens, outputTokens),
+		})
+	}
+
+	return text, err
+}
+
+// overBudget reports whether this month's budget is spent, and how much
+// was. Usage that can't be read doesn't block anything.
+func (t *UsageTracker) overBudget(ctx context.Context) (float64, bool) {
+	if t.budget <= 0 {
+		return 0, false
+	}
+
+	from := MonthStart(time.Now())
+	total, err := t.repo.Total(ctx, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to check LLM budget, allowing the call", "error", err)
+		return 0, false
+	}
+
+	return total.Cost, total.Cost >= t.budget
+}
+
+func (t *UsageTracker) record(ctx context.Context, usage *models.LLMUsage) {
+	// The call already happened, so it is recorded even when it was
+	// cancelled on the way back.
+	ctx = context.WithoutCancel(ctx)
+	if err := t.repo.Record(ctx, usage); err != nil {
+		slog.ErrorContext(ctx, "Failed to record LLM usage", "error", err)
+		return
+	}
+
+	t.warn(ctx)
+}
+
+// warn tells the notify channel, once a month each, when warnAt of the
+// budget is spent and when all of it is.
+func (t *UsageTracker) warn(ctx context.Context) {
+	if t.budget <= 0 || t.notifier == nil || t.channel == "" {
+		return
+	}
+
+	spent, over := t.overBudget(ctx)
+	month := MonthStart(time.Now()).Format("2006-01")
+	level := "exceeded"
+	if !over {
+		if t.warnAt <= 0 || spent < t.budget*t.warnAt {
+			return
+		}
+		level = "warn"
+	}
+
+	warned, err := t.settings.Get(ctx, budgetWarnedSetting)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load budget warning", "error", err)
+		return
+	}
+	if warned == month+":"+level || (level == "warn" && warned == month+":exceeded") {
+		return
+	}
+	if err := t.settings.Set(ctx, budgetWarnedSetting, month+":"+level, ""); err != nil {
+		slog.WarnContext(ctx, "Failed to save budget warning", "error", err)
+		return
+	}
+
+	message := fmt.Sprintf("⚠️ *LLM budget:* $%.2f of this month's $%.2f is spent (%.0f%%). Generating stops once it's all spent. Use `@LinkedIn Ghostwriter usage` to see where it went.",
+		spent, t.budget, 100*spent/t.budget)
+	if over {
+		message = fmt.Sprintf("🛑 *LLM budget spent:* $%.2f of this month's $%.2f. Generating and revising drafts is blocked until next month or until `LLM_MONTHLY_BUDGET` is raised; capturing thoughts keeps working. Use `@LinkedIn Ghostwriter usage` to see where it went.",
+			spent, t.budget)
+	}
+
+	slog.WarnContext(ctx, "LLM budget warning", "spent", spent, "budget", t.budget, "level", level)
+	if err := t.notifier.SendMessage(t.channel, message); err != nil {
+		slog.ErrorContext(ctx, "Failed to send budget warning", "error", err)
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/importer"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/logging"
+)
+
+// maxBodySize caps request bodies; posts are at most a few thousand
+// characters.
+const maxBodySize = 1 << 20
+
+// Handler serves the admin REST API under /api/v1. Every request must carry
+// the configured token as "Authorization: Bearer <token>".
+type Handler struct {
+	thoughtRepo  *database.ThoughtRepository
+	postRepo     *database.PostRepository
+	revisionRepo *database.RevisionRepository
+	approvalRepo *database.ApprovalRepository
+	scheduler    *agents.SchedulerAgent
+	importer     *importer.Importer
+	importRepo   *database.ImportJobRepository
+	token        string
+	review       bool
+	policy       *agents.PolicyAgent
+	mux          *http.ServeMux
+}
+
+// NewHandler builds the API. With requireReview, approvals through the API
+// count as the author's and leave posts in review until the reviewer
+// approves them in Slack. Drafts that policy flags as confidential can't
+// be approved; policy may be nil.
+func NewHandler(thoughtRepo *database.ThoughtRepository, postRepo *database.PostRepository, revisionRepo *database.RevisionRepository, approvalRepo *database.ApprovalRepository, scheduler *agents.SchedulerAgent, thoughtImporter *importer.Importer, importRepo *database.ImportJobRepository, token string, requireReview bool, policy *agents.PolicyAgent) *Handler {
+	h := &Handler{
+		thoughtRepo:  thoughtRepo,
+		postRepo:     postRepo,
+		revisionRepo: revisionRepo,
+		approvalRepo: approvalRepo,
+		scheduler:    scheduler,
+		importer:     thoughtImporter,
+		importRepo:   importRepo,
+		token:        token,
+		review:       requireReview,
+		policy:       policy,
+		mux:          http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /api/v1/thoughts", h.listThoughts)
+	h.mux.HandleFunc("GET /api/v1/thoughts/{id}", h.getThought)
+	h.mux.HandleFunc("PATCH /api/v1/thoughts/{id}", h.updateThought)
+	h.mux.HandleFunc("DELETE /api/v1/thoughts/{id}", h.deleteThought)
+
+	h.mux.HandleFunc("GET /api/v1/posts", h.listPosts)
+	h.mux.HandleFunc("GET /api/v1/posts/{id}", h.getPost)
+	h.mux.HandleFunc("PATCH /api/v1/posts/{id}", h.updatePost)
+	h.mux.HandleFunc("DELETE /api/v1/posts/{id}", h.deletePost)
+	h.mux.HandleFunc("GET /api/v1/posts/{id}/revisions", h.listRevisions)
+	h.mux.HandleFunc("POST /api/v1/posts/{id}/approve", h.approvePost)
+	h.mux.HandleFunc("POST /api/v1/posts/{id}/reject", h.rejectPost)
+	h.mux.HandleFunc("PUT /api/v1/posts/{id}/schedule", h.schedulePost)
+	h.mux.HandleFunc("DELETE /api/v1/posts/{id}/schedule", h.unschedulePost)
+
+	h.mux.HandleFunc("GET /api/v1/schedule", h.getSchedule)
+
+	h.mux.HandleFunc("POST /api/v1/imports", h.createImport)
+	h.mux.HandleFunc("GET /api/v1/imports/{id}", h.getImport)
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = logging.NewRequestID()
+	}
+	w.Header().Set("X-Request-ID", requestID)
+	r = r.WithContext(database.WithActor(logging.WithRequestID(r.Context(), requestID), reviewerID))
+
+	if !h.authorized(r.Header.Get("Authorization")) {
+		writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+		return
+	}
+
+	limit := int64(maxBodySize)
+	if r.URL.Path == "/api/v1/imports" {
+		limit = importer.MaxFileSize
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) authorized(header string) bool {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to write api response", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeLookupError answers 404 for a missing record and 500 otherwise.
+func writeLookupError(w http.ResponseWriter, r *http.Request, err error, kind string) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeError(w, http.StatusNotFound, kind+" not found")
+		return
+	}
+	slog.ErrorContext(r.Context(), "api: failed to load record", "kind", kind, "error", err)
+	writeError(w, http.StatusInternalServerError, "failed to load "+kind)
+}
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// pageParams reads ?limit= (default 50, at most 200) and ?offset=.
+func pageParams(r *http.Request) (limit, offset int, err error) {
+	limit = defaultPageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+	}
+
+	if value := r.URL.Query().Get("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be zero or positive")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+func decodeJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
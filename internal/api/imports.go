@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/importer"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// createImport queues the CSV or JSON export in the request body for
+// import and answers 202 with the job, which GET /imports/{id} reports on.
+// ?channel= files the thoughts under that channel's workspace, and
+// ?filename= names the job.
+func (h *Handler) createImport(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, "import file is too large")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+
+	filename := r.URL.Query().Get("filename")
+	if filename == "" {
+		filename = "upload"
+		switch mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType {
+		case "text/csv":
+			filename += ".csv"
+		case "application/json":
+			filename += ".json"
+		}
+	}
+
+	records, err := importer.Parse(data, filename)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job := models.NewImportJob(filename, r.URL.Query().Get("channel"), "api", len(records))
+	if err := h.importer.Submit(r.Context(), job, records); err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to queue import", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to queue import")
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/imports/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (h *Handler) getImport(w http.ResponseWriter, r *http.Request) {
+	job, err := h.importRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "import")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
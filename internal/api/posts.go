@@ -0,0 +1,310 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// reviewerID is recorded as reviewed_by, as the editor of revisions and as
+// the actor in the audit log, for changes made through the API.
+const reviewerID = "api"
+
+type postUpdate struct {
+	Content      *string `json:"content"`
+	PostType     *string `json:"post_type"`
+	Tone         *string `json:"tone"`
+	FirstComment *string `json:"first_comment"`
+}
+
+type scheduleRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+func (h *Handler) listPosts(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	posts, err := h.postRepo.List(r.Context(), r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to list posts", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list posts")
+		return
+	}
+
+	if posts == nil {
+		posts = []*models.Post{}
+	}
+	writeJSON(w, http.StatusOK, posts)
+}
+
+func (h *Handler) getPost(w http.ResponseWriter, r *http.Request) {
+	post, err := h.postRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "post")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, post)
+}
+
+// updatePost edits a post that has not been published yet.
+func (h *Handler) updatePost(w http.ResponseWriter, r *http.Request) {
+	var update postUpdate
+	if err := decodeJSON(r, &update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "post")
+		return
+	}
+
+	if post.Status == "published" {
+		writeError(w, http.StatusConflict, "published posts cannot be edited")
+		return
+	}
+
+	contentChanged := false
+	if update.Content != nil {
+		if strings.TrimSpace(*update.Content) == "" {
+			writeError(w, http.StatusBadRequest, "content cannot be empty")
+			return
+		}
+		contentChanged = *update.Content != post.Content
+		post.Content = *update.Content
+	}
+	if contentChanged {
+		post.FactCheck = nil
+	}
+	if update.PostType != nil {
+		post.PostType = *update.PostType
+	}
+	if update.Tone != nil {
+		post.Tone = *update.Tone
+	}
+	commentChanged := false
+	if update.FirstComment != nil {
+		comment := strings.TrimSpace(*update.FirstComment)
+		commentChanged = comment != post.FirstComment
+		post.FirstComment = comment
+	}
+
+	// Approved posts aren't approved again, so an edit that would make one
+	// leak confidential information is refused here.
+	if (contentChanged || commentChanged) && post.Status != "draft" && post.Status != "stale" && post.Status != models.StatusInReview {
+		if err := h.policy.Check(post); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+	}
+
+	if err := h.postRepo.Update(r.Context(), post); err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to update post", "post_id", post.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to update post")
+		return
+	}
+
+	if contentChanged {
+		if err := h.revisionRepo.Record(r.Context(), post.ID, post.Content, models.RevisionEdit, reviewerID, ""); err != nil {
+			slog.ErrorContext(r.Context(), "api: failed to record revision of post", "post_id", post.ID, "error", err)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, post)
+}
+
+// listRevisions returns every version of a post, including the drafts it
+// was revised from, oldest first.
+func (h *Handler) listRevisions(w http.ResponseWriter, r *http.Request) {
+	post, err := h.postRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "post")
+		return
+	}
+
+	revisions, err := h.revisionRepo.GetHistory(r.Context(), post.ID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to load revisions of post", "post_id", post.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to load revisions")
+		return
+	}
+
+	if revisions == nil {
+		revisions = []*models.PostRevision{}
+	}
+	writeJSON(w, http.StatusOK, revisions)
+}
+
+func (h *Handler) deletePost(w http.ResponseWriter, r *http.Request) {
+	post, err := h.postRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "post")
+		return
+	}
+
+	if err := h.postRepo.Delete(r.Context(), post.ID); err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to delete post", "post_id", post.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to delete post")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// approvePost approves a draft and, like approving in Slack, retires the
+// thoughts behind it.
+func (h *Handler) approvePost(w http.ResponseWriter, r *http.Request) {
+	post, ok := h.reviewPost(w, r, models.DecisionApproved)
+	if !ok {
+		return
+	}
+
+	if post.Status == models.DecisionApproved && len(post.SourceThoughtIDs) > 0 {
+		if err := h.thoughtRepo.MarkUsed(r.Context(), post.SourceThoughtIDs, post.ID); err != nil {
+			slog.ErrorContext(r.Context(), "api: failed to mark thoughts used by post", "post_id", post.ID, "error", err)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, post)
+}
+
+func (h *Handler) rejectPost(w http.ResponseWriter, r *http.Request) {
+	post, ok := h.reviewPost(w, r, models.DecisionRejected)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, post)
+}
+
+// reviewPost records a decision on a draft. It writes the error response
+// itself and returns false when the decision could not be made.
+func (h *Handler) reviewPost(w http.ResponseWriter, r *http.Request, decision string) (*models.Post, bool) {
+	post, err := h.postRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "post")
+		return nil, false
+	}
+
+	if post.Status != "draft" && post.Status != models.StatusInReview {
+		writeError(w, http.StatusConflict, "only drafts can be reviewed (status: "+post.Status+")")
+		return nil, false
+	}
+
+	if decision == models.DecisionApproved {
+		if err := h.policy.Check(post); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return nil, false
+		}
+	}
+
+	approval := models.NewApproval(post.ID, reviewerID, models.RoleAuthor, decision)
+	status, err := h.approvalRepo.Decide(r.Context(), approval, h.review)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to review post", "post_id", post.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to update post")
+		return nil, false
+	}
+
+	now := approval.DecidedAt
+	reviewer := reviewerID
+	post.Status = status
+	post.ReviewedBy = &reviewer
+	post.ReviewedAt = &now
+
+	return post, true
+}
+
+// schedulePost puts an approved post on the schedule, or moves one that is
+// already scheduled.
+func (h *Handler) schedulePost(w http.ResponseWriter, r *http.Request) {
+	var request scheduleRequest
+	if err := decodeJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if !request.ScheduledAt.After(time.Now()) {
+		writeError(w, http.StatusBadRequest, "scheduled_at must be in the future")
+		return
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "post")
+		return
+	}
+
+	if post.Status != "approved" && post.Status != "scheduled" {
+		writeError(w, http.StatusConflict, "only approved or scheduled posts can be scheduled (status: "+post.Status+")")
+		return
+	}
+
+	post.Status = "scheduled"
+	post.ScheduledAt = &request.ScheduledAt
+	if err := h.postRepo.Update(r.Context(), post); err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to schedule post", "post_id", post.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to schedule post")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, post)
+}
+
+func (h *Handler) unschedulePost(w http.ResponseWriter, r *http.Request) {
+	post, err := h.postRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "post")
+		return
+	}
+
+	if post.Status != "scheduled" {
+		writeError(w, http.StatusConflict, "post is not scheduled (status: "+post.Status+")")
+		return
+	}
+
+	if err := h.scheduler.CancelSchedule(r.Context(), post.ID); err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to unschedule post", "post_id", post.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to unschedule post")
+		return
+	}
+
+	post.Status = "approved"
+	post.ScheduledAt = nil
+	writeJSON(w, http.StatusOK, post)
+}
+
+// getSchedule lists scheduled posts for the next ?days= days (default 7).
+func (h *Handler) getSchedule(w http.ResponseWriter, r *http.Request) {
+	days := 7
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 365 {
+			writeError(w, http.StatusBadRequest, "days must be between 1 and 365")
+			return
+		}
+		days = parsed
+	}
+
+	posts, err := h.scheduler.GetSchedule(r.Context(), days)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to load schedule", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to load schedule")
+		return
+	}
+
+	if posts == nil {
+		posts = []*models.Post{}
+	}
+	writeJSON(w, http.StatusOK, posts)
+}
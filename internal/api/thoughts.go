@@ -0,0 +1,118 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var thoughtStatuses = map[string]bool{"raw": true, "used": true}
+
+type thoughtUpdate struct {
+	Content   *string   `json:"content"`
+	Category  *string   `json:"category"`
+	TopicTags *[]string `json:"topic_tags"`
+	Status    *string   `json:"status"`
+}
+
+// listThoughts pages through thoughts, filtered by ?status= or, with
+// ?channel=, limited to what that channel's workspace sees.
+func (h *Handler) listThoughts(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var thoughts []*models.Thought
+	switch query := r.URL.Query(); {
+	case query.Get("status") != "":
+		thoughts, err = h.thoughtRepo.GetByStatus(r.Context(), query.Get("status"), limit, offset)
+	case query.Has("channel"):
+		thoughts, err = h.thoughtRepo.GetByWorkspace(r.Context(), query.Get("channel"), limit, offset)
+	default:
+		thoughts, err = h.thoughtRepo.GetAll(r.Context(), limit, offset)
+	}
+
+	if err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to list thoughts", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list thoughts")
+		return
+	}
+
+	if thoughts == nil {
+		thoughts = []*models.Thought{}
+	}
+	writeJSON(w, http.StatusOK, thoughts)
+}
+
+func (h *Handler) getThought(w http.ResponseWriter, r *http.Request) {
+	thought, err := h.thoughtRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "thought")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, thought)
+}
+
+func (h *Handler) updateThought(w http.ResponseWriter, r *http.Request) {
+	var update thoughtUpdate
+	if err := decodeJSON(r, &update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	thought, err := h.thoughtRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "thought")
+		return
+	}
+
+	if update.Content != nil {
+		if strings.TrimSpace(*update.Content) == "" {
+			writeError(w, http.StatusBadRequest, "content cannot be empty")
+			return
+		}
+		thought.Content = *update.Content
+	}
+	if update.Category != nil {
+		thought.Category = *update.Category
+	}
+	if update.TopicTags != nil {
+		thought.TopicTags = *update.TopicTags
+	}
+	if update.Status != nil {
+		if !thoughtStatuses[*update.Status] {
+			writeError(w, http.StatusBadRequest, "status must be raw or used")
+			return
+		}
+		thought.Status = *update.Status
+	}
+
+	if err := h.thoughtRepo.Update(r.Context(), thought); err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to update thought", "thought_id", thought.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to update thought")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, thought)
+}
+
+func (h *Handler) deleteThought(w http.ResponseWriter, r *http.Request) {
+	thought, err := h.thoughtRepo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, r, err, "thought")
+		return
+	}
+
+	if err := h.thoughtRepo.Delete(r.Context(), thought.ID); err != nil {
+		slog.ErrorContext(r.Context(), "api: failed to delete thought", "thought_id", thought.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to delete thought")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
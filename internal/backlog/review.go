@@ -0,0 +1,264 @@
+package backlog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/digest"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type Notifier interface {
+	SendMessage(channelID, message string) error
+}
+
+const (
+	// agingLimit is how many of the oldest unused thoughts the analyst
+	// chooses from.
+	agingLimit = 20
+
+	// suggestionCount is how many thoughts the review suggests.
+	suggestionCount = 3
+)
+
+// Reviewer looks over the thought backlog once a week: it points out
+// categories nothing has been published about in gapDays and suggests
+// which thoughts left unused for staleDays to turn into posts next. A zero
+// gapDays or staleDays skips that part.
+type Reviewer struct {
+	thoughtRepo  *database.ThoughtRepository
+	postRepo     *database.PostRepository
+	categoryRepo *database.CategoryRepository
+	analyst      *agents.BacklogAnalystAgent
+	notifier     Notifier
+	channel      string
+	schedule     digest.Schedule
+	location     *time.Location
+	gapDays      int
+	staleDays    int
+}
+
+func NewReviewer(
+	thoughtRepo *database.ThoughtRepository,
+	postRepo *database.PostRepository,
+	categoryRepo *database.CategoryRepository,
+	analyst *agents.BacklogAnalystAgent,
+	notifier Notifier,
+	channel string,
+	schedule digest.Schedule,
+	location *time.Location,
+	gapDays int,
+	staleDays int,
+) *Reviewer {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &Reviewer{
+		thoughtRepo:  thoughtRepo,
+		postRepo:     postRepo,
+		categoryRepo: categoryRepo,
+		analyst:      analyst,
+		notifier:     notifier,
+		channel:      channel,
+		schedule:     schedule,
+		location:     location,
+		gapDays:      gapDays,
+		staleDays:    staleDays,
+	}
+}
+
+func (r *Reviewer) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "backlog review started", "schedule", r.schedule, "gap_days", r.gapDays, "stale_days", r.staleDays)
+
+	for {
+		next := r.schedule.Next(time.Now(), r.location)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.InfoContext(ctx, "backlog review stopped")
+			return
+		case <-timer.C:
+		}
+
+		if err := r.Send(ctx); err != nil {
+			slog.ErrorContext(ctx, "failed to send backlog review", "error", err)
+		}
+	}
+}
+
+// Send posts the review, unless there is nothing to report.
+func (r *Reviewer) Send(ctx context.Context) error {
+	message, err := r.Build(ctx)
+	if err != nil || message == "" {
+		return err
+	}
+
+	return r.notifier.SendMessage(r.channel, message)
+}
+
+// Build writes the review, or returns "" when no category has a gap and
+// no thought is aging.
+func (r *Reviewer) Build(ctx context.Context) (string, error) {
+	now := time.Now()
+
+	gaps, err := r.Gaps(ctx, now)
+	if err != nil {
+		return "", err
+	}
+
+	var aging []*models.Thought
+	if r.staleDays > 0 {
+		aging, err = r.thoughtRepo.GetAging(ctx, now.AddDate(0, 0, -r.staleDays), agingLimit)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(gaps) == 0 && len(aging) == 0 {
+		return "", nil
+	}
+
+	message := "*Backlog Review*\n\n"
+
+	if len(gaps) > 0 {
+		message += "🕳️ *Content gaps*\n"
+		for _, gap := range gaps {
+			message += "• " + describeGap(gap, now) + "\n"
+		}
+		message += "\n"
+	}
+
+	if len(aging) > 0 {
+		message += fmt.Sprintf("🧊 *%s unused thought(s) older than %d days.*", countLabel(len(aging)), r.staleDays)
+
+		suggestions, err := r.analyst.SuggestNext(ctx, gaps, aging, suggestionCount)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to suggest thoughts from backlog", "error", err)
+			suggestions = fallbackSuggestions(gaps, aging)
+		}
+
+		message += " Worth turning into posts next:\n"
+		for i, suggestion := range suggestions {
+			thought := suggestion.Thought
+			message += fmt.Sprintf("%d. _%s_ (%s, captured %s)", i+1,
+				truncate(strings.Join(strings.Fields(thought.Content), " "), 100),
+				thought.Category, thought.Timestamp.In(r.location).Format("Jan 2"))
+			if suggestion.Reason != "" {
+				message += " - " + suggestion.Reason
+			}
+			message += "\n"
+		}
+		message += "\nUse `@LinkedIn Ghostwriter generate [topic]` to write from them."
+	}
+
+	return message, nil
+}
+
+// Gaps returns the categories nothing has been published about in gapDays,
+// those never posted about first and then the longest quiet.
+func (r *Reviewer) Gaps(ctx context.Context, now time.Time) ([]agents.CategoryGap, error) {
+	if r.gapDays <= 0 {
+		return nil, nil
+	}
+
+	categories, err := r.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lastPublished, err := r.postRepo.LastPublishedByCategory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	waiting, err := r.thoughtRepo.CountUnusedByCategory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := now.AddDate(0, 0, -r.gapDays)
+	var gaps []agents.CategoryGap
+	for _, category := range categories {
+		if category.Name == "uncategorized" {
+			continue
+		}
+		last := lastPublished[category.Name]
+		if last.After(cutoff) {
+			continue
+		}
+		gaps = append(gaps, agents.CategoryGap{Category: category.Name, LastPublished: last, Waiting: waiting[category.Name]})
+	}
+
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return gaps[i].LastPublished.Before(gaps[j].LastPublished)
+	})
+
+	return gaps, nil
+}
+
+func describeGap(gap agents.CategoryGap, now time.Time) string {
+	line := fmt.Sprintf("Nothing about *%s* has ever been posted", gap.Category)
+	if !gap.LastPublished.IsZero() {
+		line = fmt.Sprintf("You haven't posted anything about *%s* in %s", gap.Category, sinceLabel(now.Sub(gap.LastPublished)))
+	}
+
+	if gap.Waiting > 0 {
+		return line + fmt.Sprintf(", %d thought(s) waiting", gap.Waiting)
+	}
+	return line + ", and no thoughts are waiting in it"
+}
+
+// sinceLabel reads a duration of at least a day as days, or weeks from two
+// weeks on.
+func sinceLabel(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days >= 14 {
+		return fmt.Sprintf("%d weeks", days/7)
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+func countLabel(n int) string {
+	if n >= agingLimit {
+		return fmt.Sprintf("%d+", n)
+	}
+	return fmt.Sprint(n)
+}
+
+// fallbackSuggestions picks the oldest aging thoughts, those in a gap
+// category first, when the analyst can't.
+func fallbackSuggestions(gaps []agents.CategoryGap, aging []*models.Thought) []agents.Suggestion {
+	inGap := make(map[string]bool)
+	for _, gap := range gaps {
+		inGap[gap.Category] = true
+	}
+
+	thoughts := append([]*models.Thought(nil), aging...)
+	sort.SliceStable(thoughts, func(i, j int) bool {
+		return inGap[thoughts[i].Category] && !inGap[thoughts[j].Category]
+	})
+	if len(thoughts) > suggestionCount {
+		thoughts = thoughts[:suggestionCount]
+	}
+
+	suggestions := make([]agents.Suggestion, len(thoughts))
+	for i, thought := range thoughts {
+		suggestions[i] = agents.Suggestion{Thought: thought}
+	}
+	return suggestions
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
+}
@@ -0,0 +1,106 @@
+// Package cache keeps the results of hot queries, such as the stats
+// aggregates, the schedule and the draft message lookups, for a short
+// while. Entries belong to the tables they were read from, and a write to
+// a table invalidates every entry read from it. The store is in-process by
+// default, or Redis, which replicas share so a write on one invalidates
+// the others' entries too.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Store holds entries and the version of each table. Versions only grow
+// and don't expire.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Versions returns the current version of each table, 0 for a table
+	// never written.
+	Versions(ctx context.Context, tables []string) ([]int64, error)
+	// Bump moves each table to a new version.
+	Bump(ctx context.Context, tables []string) error
+}
+
+type Cache struct {
+	store Store
+	ttl   time.Duration
+}
+
+// New keeps entries in store for ttl. Entries are also invalidated by
+// writes, so ttl only bounds how stale a write made outside the bot, or
+// one that failed to invalidate, can leave them.
+func New(store Store, ttl time.Duration) *Cache {
+	return &Cache{store: store, ttl: ttl}
+}
+
+// entry wraps a cached value, so a nil pointer can be cached too.
+type entry[T any] struct {
+	Value T
+}
+
+// Load returns the value cached under key, or calls load and caches what it
+// returns. The entry is invalidated by writes to tables. A nil Cache, or
+// one whose store fails, calls load every time.
+func Load[T any](ctx context.Context, c *Cache, key string, load func() (T, error), tables ...string) (T, error) {
+	if c == nil {
+		return load()
+	}
+
+	versions, err := c.store.Versions(ctx, tables)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read cache versions", "error", err)
+		return load()
+	}
+
+	var b strings.Builder
+	for i, table := range tables {
+		fmt.Fprintf(&b, "%s.%d:", table, versions[i])
+	}
+	b.WriteString(key)
+	versionedKey := b.String()
+
+	if data, ok, err := c.store.Get(ctx, versionedKey); err != nil {
+		slog.WarnContext(ctx, "failed to read cache", "key", key, "error", err)
+	} else if ok {
+		var cached entry[T]
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cached); err == nil {
+			return cached.Value, nil
+		}
+		slog.WarnContext(ctx, "failed to decode cache entry", "key", key, "error", err)
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(entry[T]{Value: value}); err != nil {
+		slog.WarnContext(ctx, "failed to encode cache entry", "key", key, "error", err)
+		return value, nil
+	}
+	if err := c.store.Set(ctx, versionedKey, data.Bytes(), c.ttl); err != nil {
+		slog.WarnContext(ctx, "failed to write cache", "key", key, "error", err)
+	}
+
+	return value, nil
+}
+
+// Invalidate drops every entry read from tables. It runs even when ctx is
+// cancelled, since the write it follows has already happened.
+func (c *Cache) Invalidate(ctx context.Context, tables ...string) {
+	if c == nil {
+		return
+	}
+
+	if err := c.store.Bump(context.WithoutCancel(ctx), tables); err != nil {
+		slog.ErrorContext(ctx, "failed to invalidate cache", "tables", tables, "error", err)
+	}
+}
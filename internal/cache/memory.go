@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is a Store in the bot's own memory.
+type Memory struct {
+	maxEntries int
+
+	mu       sync.Mutex
+	entries  map[string]memoryEntry
+	versions map[string]int64
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemory holds at most maxEntries entries. When full, expired entries
+// are dropped, and if none have expired, all of them.
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{
+		maxEntries: maxEntries,
+		entries:    make(map[string]memoryEntry),
+		versions:   make(map[string]int64),
+	}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if len(m.entries) >= m.maxEntries {
+		for k, e := range m.entries {
+			if now.After(e.expires) {
+				delete(m.entries, k)
+			}
+		}
+		if len(m.entries) >= m.maxEntries {
+			clear(m.entries)
+		}
+	}
+
+	m.entries[key] = memoryEntry{value: value, expires: now.Add(ttl)}
+	return nil
+}
+
+func (m *Memory) Versions(_ context.Context, tables []string) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	versions := make([]int64, len(tables))
+	for i, table := range tables {
+		versions[i] = m.versions[table]
+	}
+	return versions, nil
+}
+
+// Bump leaves the entries of old versions to expire, since nothing reads
+// them any more.
+func (m *Memory) Bump(_ context.Context, tables []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, table := range tables {
+		m.versions[table]++
+	}
+	return nil
+}
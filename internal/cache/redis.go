@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix keeps the bot's keys apart from anything else in the Redis
+// database.
+const keyPrefix = "ghostwriter:cache:"
+
+// Redis is a Store that replicas share.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis connects to the Redis server at url, such as
+// redis://localhost:6379/0.
+func NewRedis(url string) (*Redis, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(options)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &Redis{client: client}, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, keyPrefix+key, value, ttl).Err()
+}
+
+func (r *Redis) Versions(ctx context.Context, tables []string) ([]int64, error) {
+	if len(tables) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(tables))
+	for i, table := range tables {
+		keys[i] = keyPrefix + "version:" + table
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int64, len(tables))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			fmt.Sscan(s, &versions[i])
+		}
+	}
+	return versions, nil
+}
+
+func (r *Redis) Bump(ctx context.Context, tables []string) error {
+	pipe := r.client.Pipeline()
+	for _, table := range tables {
+		pipe.Incr(ctx, keyPrefix+"version:"+table)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,111 @@
+// Package checkin sends a daily DM to everyone who opted in, asking what
+// they worked on. Their replies are captured as thoughts by the Slack
+// handler.
+package checkin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// checkInterval is how often the reminder looks for prompts that are due.
+	checkInterval = time.Minute
+	// maxDelay is how late a prompt can still go out, such as after a
+	// restart. Later than that, the day is skipped.
+	maxDelay = 2 * time.Hour
+)
+
+// Prompter sends the check-in DM.
+type Prompter interface {
+	PromptCheckIn(ctx context.Context, checkIn *models.CheckIn) error
+}
+
+type Reminder struct {
+	repo     *database.CheckInRepository
+	prompter Prompter
+	location *time.Location
+}
+
+func NewReminder(repo *database.CheckInRepository, prompter Prompter, location *time.Location) *Reminder {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &Reminder{
+		repo:     repo,
+		prompter: prompter,
+		location: location,
+	}
+}
+
+// ParseTime reads an "HH:MM" time of day and returns it in that form.
+func ParseTime(spec string) (string, error) {
+	clock, err := time.Parse("15:04", spec)
+	if err != nil {
+		return "", fmt.Errorf("invalid check-in time %q, expected e.g. \"18:00\"", spec)
+	}
+	return clock.Format("15:04"), nil
+}
+
+func (r *Reminder) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "check-in reminder started", "location", r.location)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "check-in reminder stopped")
+			return
+		case <-ticker.C:
+			r.check(ctx, time.Now())
+		}
+	}
+}
+
+func (r *Reminder) check(ctx context.Context, now time.Time) {
+	checkIns, err := r.repo.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list check-ins", "error", err)
+		return
+	}
+
+	for _, checkIn := range checkIns {
+		if !Due(checkIn, now, r.location) {
+			continue
+		}
+
+		if err := r.prompter.PromptCheckIn(ctx, checkIn); err != nil {
+			slog.ErrorContext(ctx, "failed to send check-in", "user", checkIn.UserID, "error", err)
+			continue
+		}
+
+		if err := r.repo.MarkPrompted(ctx, checkIn.UserID, now); err != nil {
+			slog.ErrorContext(ctx, "failed to mark check-in prompted", "user", checkIn.UserID, "error", err)
+		}
+	}
+}
+
+// Due reports whether checkIn's prompt for today should go out at now: its
+// time has passed, less than maxDelay ago, and it hasn't been sent since.
+func Due(checkIn *models.CheckIn, now time.Time, location *time.Location) bool {
+	clock, err := time.Parse("15:04", checkIn.Time)
+	if err != nil {
+		return false
+	}
+
+	now = now.In(location)
+	at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+	if now.Before(at) || now.Sub(at) > maxDelay {
+		return false
+	}
+
+	return checkIn.LastPromptedAt == nil || checkIn.LastPromptedAt.Before(at)
+}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var (
+	// ErrNoDecision is returned by Withdraw when the user hasn't made the
+	// decision on the post.
+	ErrNoDecision = errors.New("no such decision")
+	// ErrDecisionTooOld is returned by Withdraw when the decision was made
+	// before the time it may be withdrawn from.
+	ErrDecisionTooOld = errors.New("decision is too old to withdraw")
+	// ErrDecisionFinal is returned by Withdraw when the post has moved on
+	// from review, such as to the schedule.
+	ErrDecisionFinal = errors.New("post has moved on from review")
+)
+
+type ApprovalRepository struct {
+	db *DB
+}
+
+func NewApprovalRepository(db *DB) *ApprovalRepository {
+	return &ApprovalRepository{db: db}
+}
+
+// Decide records a decision on a post and moves the post to the status it
+// leads to, in one transaction. Without requireReview that is the decision
+// itself; with it the post is only approved once both the author and the
+// reviewer have approved it. A user deciding again replaces their earlier
+// decision.
+func (r *ApprovalRepository) Decide(ctx context.Context, approval *models.Approval, requireReview bool) (string, error) {
+	defer r.db.changed(ctx, tablePosts)
+
+	if ActorFrom(ctx) == "" {
+		ctx = WithActor(ctx, approval.UserID)
+	}
+
+	tx, err := r.db.begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO approvals (post_id, user_id, role, decision, decided_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (post_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role, decision = EXCLUDED.decision, decided_at = EXCLUDED.decided_at
+	`
+
+	if _, err := tx.Exec(ctx, query, approval.PostID, approval.UserID, approval.Role, approval.Decision, approval.DecidedAt); err != nil {
+		return "", fmt.Errorf("failed to record approval: %w", err)
+	}
+
+	status := approval.Decision
+	if requireReview {
+		approvals, err := decisions(ctx, tx, approval.PostID)
+		if err != nil {
+			return "", err
+		}
+
+		status = models.ReviewStatus(approvals)
+	}
+
+	result, err := tx.Exec(ctx, `UPDATE posts SET status = $2, reviewed_by = $3, reviewed_at = $4 WHERE id = $1`,
+		approval.PostID, status, approval.UserID, approval.DecidedAt)
+	if err != nil {
+		return "", fmt.Errorf("failed to update post review: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return "", fmt.Errorf("post not found")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit approval: %w", err)
+	}
+
+	return status, nil
+}
+
+// Withdraw takes back the decision a user made on a post, if made since
+// since, and moves the post back to previous. With requireReview a post
+// someone else has also decided on goes to what their decisions add up to
+// instead. Only posts still in review, approved or rejected can be
+// withdrawn from. It returns the post's new status.
+func (r *ApprovalRepository) Withdraw(ctx context.Context, approval *models.Approval, since time.Time, requireReview bool, previous string) (string, error) {
+	defer r.db.changed(ctx, tablePosts)
+
+	if ActorFrom(ctx) == "" {
+		ctx = WithActor(ctx, approval.UserID)
+	}
+
+	tx, err := r.db.begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var decidedAt time.Time
+	err = tx.QueryRow(ctx, `SELECT decided_at FROM approvals WHERE post_id = $1 AND user_id = $2 AND decision = $3 FOR UPDATE`,
+		approval.PostID, approval.UserID, approval.Decision).Scan(&decidedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNoDecision
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query approval: %w", err)
+	}
+	if decidedAt.Before(since) {
+		return "", ErrDecisionTooOld
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM approvals WHERE post_id = $1 AND user_id = $2`, approval.PostID, approval.UserID); err != nil {
+		return "", fmt.Errorf("failed to withdraw approval: %w", err)
+	}
+
+	status := previous
+	if requireReview {
+		approvals, err := decisions(ctx, tx, approval.PostID)
+		if err != nil {
+			return "", err
+		}
+
+		if len(approvals) > 0 {
+			status = models.ReviewStatus(approvals)
+		}
+	}
+
+	result, err := tx.Exec(ctx, `UPDATE posts SET status = $2 WHERE id = $1 AND status IN ($3, $4, $5)`,
+		approval.PostID, status, models.DecisionApproved, models.DecisionRejected, models.StatusInReview)
+	if err != nil {
+		return "", fmt.Errorf("failed to update post review: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return "", ErrDecisionFinal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit withdrawal: %w", err)
+	}
+
+	return status, nil
+}
+
+// decisions returns the decisions made on a post so far.
+func decisions(ctx context.Context, tx pgx.Tx, postID string) ([]*models.Approval, error) {
+	rows, err := tx.Query(ctx, `SELECT role, decision FROM approvals WHERE post_id = $1`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query approvals: %w", err)
+	}
+	defer rows.Close()
+
+	var approvals []*models.Approval
+	for rows.Next() {
+		decided := &models.Approval{PostID: postID}
+		if err := rows.Scan(&decided.Role, &decided.Decision); err != nil {
+			return nil, fmt.Errorf("failed to scan approval: %w", err)
+		}
+		approvals = append(approvals, decided)
+	}
+
+	return approvals, rows.Err()
+}
+
+// Reset clears the decisions on a post, for when its content changes after
+// someone already approved it.
+func (r *ApprovalRepository) Reset(ctx context.Context, postID string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM approvals WHERE post_id = $1`, postID); err != nil {
+		return fmt.Errorf("failed to reset approvals: %w", err)
+	}
+
+	return nil
+}
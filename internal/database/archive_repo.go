@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ArchiveRepository moves rows nobody needs any more into archived_records.
+type ArchiveRepository struct {
+	db *DB
+}
+
+func NewArchiveRepository(db *DB) *ArchiveRepository {
+	return &ArchiveRepository{db: db}
+}
+
+// ArchiveRejectedPosts moves posts rejected before olderThan, along with
+// their revisions, into the archive. Both happen in one statement, so a
+// post is never deleted without its copy being stored.
+func (r *ArchiveRepository) ArchiveRejectedPosts(ctx context.Context, olderThan time.Time) (int, error) {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `
+		WITH archived AS (
+			DELETE FROM posts
+			WHERE status = 'rejected' AND COALESCE(reviewed_at, created_at) < $1
+			RETURNING *
+		)
+		INSERT INTO archived_records (record_type, record_id, data)
+		SELECT 'post', a.id, to_jsonb(a) || jsonb_build_object('revisions', COALESCE(
+			(SELECT jsonb_agg(to_jsonb(rev) ORDER BY rev.created_at) FROM post_revisions rev WHERE rev.post_id = a.id),
+			'[]'::jsonb))
+		FROM archived a
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive rejected posts: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// ArchiveOrphanedBrainstorms moves brainstorm sessions last touched before
+// olderThan that no post was developed from into the archive.
+func (r *ArchiveRepository) ArchiveOrphanedBrainstorms(ctx context.Context, olderThan time.Time) (int, error) {
+	query := `
+		WITH archived AS (
+			DELETE FROM brainstorm_sessions b
+			WHERE COALESCE(b.updated_at, b.created_at) < $1
+			  AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.brainstorm_session_id = b.id)
+			RETURNING *
+		)
+		INSERT INTO archived_records (record_type, record_id, data)
+		SELECT 'brainstorm_session', a.id, to_jsonb(a)
+		FROM archived a
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive brainstorm sessions: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type actorKey struct{}
+
+// WithActor marks ctx as acting for actor: a Slack user ID, "api" or "cli".
+// Posts changed under ctx are attributed to actor in the audit log.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor ctx acts for, or "" for the bot itself.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// begin starts a transaction that the audit trigger on posts attributes to
+// the actor in ctx.
+func (db *DB) begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if actor := ActorFrom(ctx); actor != "" {
+		if _, err := tx.Exec(ctx, `SELECT set_config('ghostwriter.actor', $1, true)`, actor); err != nil {
+			tx.Rollback(ctx)
+			return nil, fmt.Errorf("failed to set audit actor: %w", err)
+		}
+	}
+
+	return tx, nil
+}
+
+// exec runs a statement that may change a post's status, inside a
+// transaction carrying the actor when ctx has one.
+func (db *DB) exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	if ActorFrom(ctx) == "" {
+		return db.Pool.Exec(ctx, query, args...)
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return result, err
+	}
+
+	return result, tx.Commit(ctx)
+}
+
+type AuditRepository struct {
+	db *DB
+}
+
+func NewAuditRepository(db *DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Record stores an entry. An empty actor is taken from ctx.
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.Actor == "" {
+		entry.Actor = ActorFrom(ctx)
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO audit_log (id, post_id, actor, action, from_status, to_status, detail, created_at)
+		VALUES ($1, NULLIF($2, '')::uuid, NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, entry.ID, entry.PostID, entry.Actor, entry.Action,
+		entry.FromStatus, entry.ToStatus, entry.Detail, entry.CreatedAt); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetLifecycle returns the entries of a post and of the drafts it was
+// revised from, oldest first.
+func (r *AuditRepository) GetLifecycle(ctx context.Context, postID string) ([]*models.AuditEntry, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_post_id FROM posts WHERE id = $1
+			UNION ALL
+			SELECT p.id, p.parent_post_id FROM posts p JOIN chain c ON p.id = c.parent_post_id
+		)
+		SELECT id, post_id::text, COALESCE(actor, ''), action, COALESCE(from_status, ''),
+		       COALESCE(to_status, ''), COALESCE(detail, ''), created_at
+		FROM audit_log
+		WHERE post_id IN (SELECT id FROM chain)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+// GetRecent returns the latest entries with action, newest first.
+func (r *AuditRepository) GetRecent(ctx context.Context, action string, limit int) ([]*models.AuditEntry, error) {
+	query := `
+		SELECT id, COALESCE(post_id::text, ''), COALESCE(actor, ''), action, COALESCE(from_status, ''),
+		       COALESCE(to_status, ''), COALESCE(detail, ''), created_at
+		FROM audit_log
+		WHERE action = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, action, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+// GetLatestTransitions returns the status changes actor made last, newest
+// first: the latest one and those made up to within before it, so the
+// posts one reaction or click decided come back together.
+func (r *AuditRepository) GetLatestTransitions(ctx context.Context, actor string, within time.Duration) ([]*models.AuditEntry, error) {
+	query := `
+		WITH latest AS (
+			SELECT MAX(created_at) AS at FROM audit_log WHERE action = $1 AND actor = $2
+		)
+		SELECT id, COALESCE(post_id::text, ''), COALESCE(actor, ''), action, COALESCE(from_status, ''),
+		       COALESCE(to_status, ''), COALESCE(detail, ''), created_at
+		FROM audit_log, latest
+		WHERE action = $1 AND actor = $2 AND created_at >= latest.at - make_interval(secs => $3)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, models.AuditTransition, actor, within.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+func scanAuditEntries(rows pgx.Rows) ([]*models.AuditEntry, error) {
+	var entries []*models.AuditEntry
+	for rows.Next() {
+		entry := &models.AuditEntry{}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.PostID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.FromStatus,
+			&entry.ToStatus,
+			&entry.Detail,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
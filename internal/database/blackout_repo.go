@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var ErrBlackoutNotFound = errors.New("blackout not found")
+
+type BlackoutRepository struct {
+	db *DB
+}
+
+func NewBlackoutRepository(db *DB) *BlackoutRepository {
+	return &BlackoutRepository{db: db}
+}
+
+func (r *BlackoutRepository) Create(ctx context.Context, blackout *models.Blackout) error {
+	if blackout.ID == "" {
+		blackout.ID = uuid.New().String()
+	}
+	if blackout.CreatedAt.IsZero() {
+		blackout.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO blackouts (id, kind, start_date, end_date, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, blackout.ID, blackout.Kind, blackout.StartDate, blackout.EndDate,
+		blackout.Reason, blackout.CreatedBy, blackout.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create blackout: %w", err)
+	}
+
+	return nil
+}
+
+// ListActive returns the blackouts and pauses not over by from, a date,
+// soonest first.
+func (r *BlackoutRepository) ListActive(ctx context.Context, from time.Time) ([]*models.Blackout, error) {
+	query := `
+		SELECT id, kind, start_date, end_date, COALESCE(reason, ''), COALESCE(created_by, ''), created_at
+		FROM blackouts
+		WHERE end_date IS NULL OR end_date >= $1
+		ORDER BY start_date, created_at
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blackouts: %w", err)
+	}
+	defer rows.Close()
+
+	var blackouts []*models.Blackout
+	for rows.Next() {
+		blackout := &models.Blackout{}
+		if err := rows.Scan(&blackout.ID, &blackout.Kind, &blackout.StartDate, &blackout.EndDate,
+			&blackout.Reason, &blackout.CreatedBy, &blackout.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan blackout: %w", err)
+		}
+		blackouts = append(blackouts, blackout)
+	}
+
+	return blackouts, rows.Err()
+}
+
+func (r *BlackoutRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM blackouts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete blackout: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrBlackoutNotFound
+	}
+
+	return nil
+}
+
+// DeletePauses resumes publishing, returning how many pauses there were.
+// Blackout dates stay.
+func (r *BlackoutRepository) DeletePauses(ctx context.Context) (int, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM blackouts WHERE kind = $1`, models.BlackoutPause)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resume publishing: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
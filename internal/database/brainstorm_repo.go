@@ -2,10 +2,12 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
 )
 
@@ -74,6 +76,47 @@ func (r *BrainstormRepository) GetByID(ctx context.Context, id string) (*models.
 	return session, nil
 }
 
+// SetSlackMessage remembers the message a session was posted as, so replies
+// in its thread can find it.
+func (r *BrainstormRepository) SetSlackMessage(ctx context.Context, id, channelID, messageTS string) error {
+	query := `UPDATE brainstorm_sessions SET slack_channel_id = $2, slack_message_ts = $3 WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, channelID, messageTS); err != nil {
+		return fmt.Errorf("failed to link brainstorm session to message: %w", err)
+	}
+
+	return nil
+}
+
+// GetBySlackThread returns nil without an error when the thread does not
+// belong to a brainstorm session.
+func (r *BrainstormRepository) GetBySlackThread(ctx context.Context, channelID, threadTS string) (*models.BrainstormSession, error) {
+	query := `
+		SELECT id, topic, thought_ids, brainstorm_content, key_angles, status, created_at
+		FROM brainstorm_sessions
+		WHERE slack_channel_id = $1 AND slack_message_ts = $2
+	`
+
+	session := &models.BrainstormSession{}
+	err := r.db.Pool.QueryRow(ctx, query, channelID, threadTS).Scan(
+		&session.ID,
+		&session.Topic,
+		&session.ThoughtIDs,
+		&session.BrainstormContent,
+		&session.KeyAngles,
+		&session.Status,
+		&session.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brainstorm session by thread: %w", err)
+	}
+
+	return session, nil
+}
+
 func (r *BrainstormRepository) GetByStatus(ctx context.Context, status string) ([]*models.BrainstormSession, error) {
 	query := `
 		SELECT id, topic, thought_ids, brainstorm_content, key_angles, status, created_at
@@ -150,4 +193,4 @@ func (r *BrainstormRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
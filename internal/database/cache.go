@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/cache"
+)
+
+// Tables whose cached reads are invalidated by writes.
+const (
+	tableThoughts      = "thoughts"
+	tablePosts         = "posts"
+	tableDraftMessages = "draft_messages"
+)
+
+// changed invalidates what was cached from tables after a write to them.
+func (db *DB) changed(ctx context.Context, tables ...string) {
+	db.Cache.Invalidate(ctx, tables...)
+}
+
+// cached returns the result of load, cached under key until a write to
+// tables.
+func cached[T any](ctx context.Context, db *DB, key string, load func() (T, error), tables ...string) (T, error) {
+	return cache.Load(ctx, db.Cache, key, load, tables...)
+}
+
+// minuteKey formats t for a cache key to the minute, so reads of "the last
+// four weeks" made within the same minute share an entry.
+func minuteKey(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return fmt.Sprint(t.Unix() / 60)
+}
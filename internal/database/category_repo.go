@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var (
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+type CategoryRepository struct {
+	db *DB
+}
+
+func NewCategoryRepository(db *DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+func (r *CategoryRepository) List(ctx context.Context) ([]*models.Category, error) {
+	query := `
+		SELECT name, description, rules, COALESCE(created_by, ''), created_at
+		FROM categories
+		ORDER BY created_at, name
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*models.Category
+	for rows.Next() {
+		category := &models.Category{}
+		if err := rows.Scan(&category.Name, &category.Description, &category.Rules, &category.CreatedBy, &category.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	query := `
+		INSERT INTO categories (name, description, created_by, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, category.Name, category.Description, category.CreatedBy, category.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCategoryExists
+	}
+
+	return nil
+}
+
+// SetRules replaces the rules of a category.
+func (r *CategoryRepository) SetRules(ctx context.Context, name string, rules []string) error {
+	if rules == nil {
+		rules = []string{}
+	}
+
+	result, err := r.db.Pool.Exec(ctx, `UPDATE categories SET rules = $2 WHERE name = $1`, name, rules)
+	if err != nil {
+		return fmt.Errorf("failed to set category rules: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// Rename renames a category and moves its thoughts along with it, returning
+// how many thoughts moved.
+func (r *CategoryRepository) Rename(ctx context.Context, oldName, newName string) (int, error) {
+	defer r.db.changed(ctx, tableThoughts)
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM categories WHERE name = $1)`, newName).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("failed to check category: %w", err)
+	}
+	if exists {
+		return 0, ErrCategoryExists
+	}
+
+	result, err := tx.Exec(ctx, `UPDATE categories SET name = $2 WHERE name = $1`, oldName, newName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rename category: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return 0, ErrCategoryNotFound
+	}
+
+	moved, err := tx.Exec(ctx, `UPDATE thoughts SET category = $2 WHERE category = $1`, oldName, newName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move thoughts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(moved.RowsAffected()), nil
+}
+
+// Delete removes a category. Its thoughts become uncategorized, so the
+// weekly digest points them out; the count of those is returned.
+func (r *CategoryRepository) Delete(ctx context.Context, name string) (int, error) {
+	defer r.db.changed(ctx, tableThoughts)
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `DELETE FROM categories WHERE name = $1`, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete category: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return 0, ErrCategoryNotFound
+	}
+
+	moved, err := tx.Exec(ctx, `UPDATE thoughts SET category = 'uncategorized' WHERE category = $1`, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to uncategorize thoughts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(moved.RowsAffected()), nil
+}
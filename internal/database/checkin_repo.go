@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var ErrCheckInNotFound = errors.New("check-in not found")
+
+type CheckInRepository struct {
+	db *DB
+}
+
+func NewCheckInRepository(db *DB) *CheckInRepository {
+	return &CheckInRepository{db: db}
+}
+
+const checkInColumns = `user_id, channel_id, prompt_time, COALESCE(pending_category, ''), last_prompted_at, created_at`
+
+func scanCheckIn(row pgx.Row) (*models.CheckIn, error) {
+	checkIn := &models.CheckIn{}
+	err := row.Scan(&checkIn.UserID, &checkIn.ChannelID, &checkIn.Time, &checkIn.PendingCategory,
+		&checkIn.LastPromptedAt, &checkIn.CreatedAt)
+	return checkIn, err
+}
+
+func (r *CheckInRepository) List(ctx context.Context) ([]*models.CheckIn, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT `+checkInColumns+` FROM check_ins ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check-ins: %w", err)
+	}
+	defer rows.Close()
+
+	var checkIns []*models.CheckIn
+	for rows.Next() {
+		checkIn, err := scanCheckIn(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan check-in: %w", err)
+		}
+		checkIns = append(checkIns, checkIn)
+	}
+
+	return checkIns, rows.Err()
+}
+
+// GetByUser returns nil without an error when userID hasn't opted in.
+func (r *CheckInRepository) GetByUser(ctx context.Context, userID string) (*models.CheckIn, error) {
+	checkIn, err := scanCheckIn(r.db.Pool.QueryRow(ctx, `SELECT `+checkInColumns+` FROM check_ins WHERE user_id = $1`, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check-in: %w", err)
+	}
+
+	return checkIn, nil
+}
+
+// Save opts a user in, or moves their check-in to another channel or time.
+func (r *CheckInRepository) Save(ctx context.Context, checkIn *models.CheckIn) error {
+	query := `
+		INSERT INTO check_ins (user_id, channel_id, prompt_time, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET channel_id = EXCLUDED.channel_id, prompt_time = EXCLUDED.prompt_time
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, checkIn.UserID, checkIn.ChannelID, checkIn.Time, checkIn.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save check-in: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CheckInRepository) Delete(ctx context.Context, userID string) error {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM check_ins WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete check-in: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCheckInNotFound
+	}
+
+	return nil
+}
+
+// MarkPrompted records that userID was sent today's prompt and forgets any
+// category picked from an earlier one.
+func (r *CheckInRepository) MarkPrompted(ctx context.Context, userID string, at time.Time) error {
+	query := `UPDATE check_ins SET last_prompted_at = $2, pending_category = NULL WHERE user_id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, userID, at.UTC()); err != nil {
+		return fmt.Errorf("failed to mark check-in prompted: %w", err)
+	}
+
+	return nil
+}
+
+// SetPendingCategory files userID's next reply under category, or lets the
+// categorizer choose when category is empty.
+func (r *CheckInRepository) SetPendingCategory(ctx context.Context, userID, category string) error {
+	query := `UPDATE check_ins SET pending_category = NULLIF($2, '') WHERE user_id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, userID, category)
+	if err != nil {
+		return fmt.Errorf("failed to update check-in: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCheckInNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var ErrCommentNotFound = errors.New("comment not found")
+
+const commentColumns = `id, post_id, comment_urn, COALESCE(author_urn, ''), content, commented_at,
+	COALESCE(reply_draft, ''), replied_at, COALESCE(replied_by, ''), created_at`
+
+type CommentRepository struct {
+	db *DB
+}
+
+func NewCommentRepository(db *DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// Save stores a comment synced from LinkedIn, or updates its text when it
+// was synced before and has been edited since.
+func (r *CommentRepository) Save(ctx context.Context, comment *models.Comment) error {
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO post_comments (id, post_id, comment_urn, author_urn, content, commented_at, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+		ON CONFLICT (comment_urn) DO UPDATE SET content = EXCLUDED.content
+		RETURNING id
+	`
+
+	if err := r.db.Pool.QueryRow(ctx, query, comment.ID, comment.PostID, comment.CommentURN, comment.AuthorURN,
+		comment.Content, comment.CommentedAt, comment.CreatedAt).Scan(&comment.ID); err != nil {
+		return fmt.Errorf("failed to save comment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CommentRepository) GetByID(ctx context.Context, id string) (*models.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM post_comments WHERE id = $1`
+
+	comment, err := scanComment(r.db.Pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// ListUnanswered returns the comments on a post not replied to yet, oldest
+// first.
+func (r *CommentRepository) ListUnanswered(ctx context.Context, postID string) ([]*models.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM post_comments
+		WHERE post_id = $1 AND replied_at IS NULL
+		ORDER BY commented_at
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+func (r *CommentRepository) SaveDraft(ctx context.Context, id, draft string) error {
+	if _, err := r.db.Pool.Exec(ctx, `UPDATE post_comments SET reply_draft = $2 WHERE id = $1`, id, draft); err != nil {
+		return fmt.Errorf("failed to save reply draft: %w", err)
+	}
+
+	return nil
+}
+
+// MarkReplied records that userID answered a comment, reporting false when
+// someone already had, so a reply is only ever posted once.
+func (r *CommentRepository) MarkReplied(ctx context.Context, id, userID string) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `
+		UPDATE post_comments SET replied_at = NOW(), replied_by = NULLIF($2, '')
+		WHERE id = $1 AND replied_at IS NULL
+	`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark comment replied: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// ClearReplied takes back MarkReplied when posting the reply failed.
+func (r *CommentRepository) ClearReplied(ctx context.Context, id string) error {
+	if _, err := r.db.Pool.Exec(ctx, `UPDATE post_comments SET replied_at = NULL, replied_by = NULL WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to clear comment reply: %w", err)
+	}
+
+	return nil
+}
+
+func scanComment(row pgx.Row) (*models.Comment, error) {
+	comment := &models.Comment{}
+	err := row.Scan(&comment.ID, &comment.PostID, &comment.CommentURN, &comment.AuthorURN, &comment.Content,
+		&comment.CommentedAt, &comment.ReplyDraft, &comment.RepliedAt, &comment.RepliedBy, &comment.CreatedAt)
+	return comment, err
+}
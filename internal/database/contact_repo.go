@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var ErrContactNotFound = errors.New("contact not found")
+
+type ContactRepository struct {
+	db *DB
+}
+
+func NewContactRepository(db *DB) *ContactRepository {
+	return &ContactRepository{db: db}
+}
+
+func (r *ContactRepository) List(ctx context.Context) ([]*models.Contact, error) {
+	query := `
+		SELECT id, name, aliases, COALESCE(linkedin_urn, ''), COALESCE(profile_url, ''), COALESCE(created_by, ''), created_at
+		FROM contacts
+		ORDER BY LOWER(name)
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []*models.Contact
+	for rows.Next() {
+		contact := &models.Contact{}
+		if err := rows.Scan(&contact.ID, &contact.Name, &contact.Aliases, &contact.LinkedInURN, &contact.ProfileURL,
+			&contact.CreatedBy, &contact.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan contact: %w", err)
+		}
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, rows.Err()
+}
+
+// Save adds a contact, or replaces the aliases, URN and profile URL of the
+// contact with the same name.
+func (r *ContactRepository) Save(ctx context.Context, contact *models.Contact) error {
+	if contact.ID == "" {
+		contact.ID = uuid.New().String()
+	}
+	if contact.Aliases == nil {
+		contact.Aliases = []string{}
+	}
+
+	query := `
+		INSERT INTO contacts (id, name, aliases, linkedin_urn, profile_url, created_by, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7)
+		ON CONFLICT ((LOWER(name))) DO UPDATE
+		SET name = EXCLUDED.name, aliases = EXCLUDED.aliases,
+		    linkedin_urn = EXCLUDED.linkedin_urn, profile_url = EXCLUDED.profile_url
+		RETURNING id
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query, contact.ID, contact.Name, contact.Aliases, contact.LinkedInURN,
+		contact.ProfileURL, contact.CreatedBy, contact.CreatedAt).Scan(&contact.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save contact: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the contact called name, ignoring case.
+func (r *ContactRepository) Delete(ctx context.Context, name string) error {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM contacts WHERE LOWER(name) = LOWER($1)`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrContactNotFound
+	}
+
+	return nil
+}
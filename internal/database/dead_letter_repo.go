@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type DeadLetterRepository struct {
+	db *DB
+}
+
+func NewDeadLetterRepository(db *DB) *DeadLetterRepository {
+	return &DeadLetterRepository{db: db}
+}
+
+func (r *DeadLetterRepository) Save(ctx context.Context, letter *models.DeadLetter) error {
+	if letter.ID == "" {
+		letter.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO webhook_dead_letters (id, source, delivery_id, payload, error, attempts, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, letter.ID, letter.Source, letter.DeliveryID, letter.Payload,
+		letter.Error, letter.Attempts, letter.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+
+	return nil
+}
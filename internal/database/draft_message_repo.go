@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type DraftMessageRepository struct {
+	db *DB
+}
+
+func NewDraftMessageRepository(db *DB) *DraftMessageRepository {
+	return &DraftMessageRepository{db: db}
+}
+
+func (r *DraftMessageRepository) Create(ctx context.Context, message *models.DraftMessage) error {
+	defer r.db.changed(ctx, tableDraftMessages)
+
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO draft_messages (message_ts, channel_id, post_ids, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (message_ts) DO UPDATE
+		SET channel_id = EXCLUDED.channel_id, post_ids = EXCLUDED.post_ids
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		message.MessageTS,
+		message.ChannelID,
+		message.PostIDs,
+		message.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create draft message: %w", err)
+	}
+
+	return nil
+}
+
+// GetByMessageTS returns nil without an error when the message is not a
+// tracked draft message, since most reactions land on unrelated messages.
+func (r *DraftMessageRepository) GetByMessageTS(ctx context.Context, messageTS string) (*models.DraftMessage, error) {
+	return cached(ctx, r.db, "draft_messages.by_ts:"+messageTS, func() (*models.DraftMessage, error) {
+		query := `
+			SELECT message_ts, channel_id, post_ids, created_at
+			FROM draft_messages
+			WHERE message_ts = $1
+		`
+
+		message := &models.DraftMessage{}
+		err := r.db.Pool.QueryRow(ctx, query, messageTS).Scan(
+			&message.MessageTS,
+			&message.ChannelID,
+			&message.PostIDs,
+			&message.CreatedAt,
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get draft message: %w", err)
+		}
+
+		return message, nil
+	}, tableDraftMessages)
+}
+
+// GetLatestByPostID returns the most recent draft message showing a post,
+// or nil when none does.
+func (r *DraftMessageRepository) GetLatestByPostID(ctx context.Context, postID string) (*models.DraftMessage, error) {
+	return cached(ctx, r.db, "draft_messages.latest_by_post:"+postID, func() (*models.DraftMessage, error) {
+		query := `
+			SELECT message_ts, channel_id, post_ids, created_at
+			FROM draft_messages
+			WHERE $1 = ANY(post_ids)
+			ORDER BY created_at DESC
+			LIMIT 1
+		`
+
+		message := &models.DraftMessage{}
+		err := r.db.Pool.QueryRow(ctx, query, postID).Scan(
+			&message.MessageTS,
+			&message.ChannelID,
+			&message.PostIDs,
+			&message.CreatedAt,
+		)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get draft message: %w", err)
+		}
+
+		return message, nil
+	}, tableDraftMessages)
+}
+
+func (r *DraftMessageRepository) Delete(ctx context.Context, messageTS string) error {
+	defer r.db.changed(ctx, tableDraftMessages)
+
+	query := `DELETE FROM draft_messages WHERE message_ts = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, messageTS)
+	if err != nil {
+		return fmt.Errorf("failed to delete draft message: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("draft message not found")
+	}
+
+	return nil
+}
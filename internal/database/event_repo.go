@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EventRepository records which webhook events have been processed, so every
+// replica of the bot agrees on which deliveries are retries.
+type EventRepository struct {
+	db *DB
+}
+
+func NewEventRepository(db *DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Claim records key for ttl. It returns false when key was already claimed
+// and has not expired yet.
+func (r *EventRepository) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO processed_events (event_key, expires_at)
+		VALUES ($1, LOCALTIMESTAMP + $2 * INTERVAL '1 second')
+		ON CONFLICT (event_key) DO UPDATE SET expires_at = EXCLUDED.expires_at
+		WHERE processed_events.expires_at < LOCALTIMESTAMP
+		RETURNING event_key
+	`
+
+	var claimed string
+	err := r.db.Pool.QueryRow(ctx, query, key, ttl.Seconds()).Scan(&claimed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim event: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *EventRepository) Release(ctx context.Context, key string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM processed_events WHERE event_key = $1`, key); err != nil {
+		return fmt.Errorf("failed to release event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EventRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM processed_events WHERE expires_at < LOCALTIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired events: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
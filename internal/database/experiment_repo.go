@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// ErrExperimentPosts is returned when a variation is no longer approved or
+// is already part of another experiment.
+var ErrExperimentPosts = errors.New("both variations must be approved and not in another experiment")
+
+type ExperimentRepository struct {
+	db *DB
+}
+
+func NewExperimentRepository(db *DB) *ExperimentRepository {
+	return &ExperimentRepository{db: db}
+}
+
+// Create saves an experiment and links postIDs to it.
+func (r *ExperimentRepository) Create(ctx context.Context, experiment *models.Experiment, postIDs []string) error {
+	defer r.db.changed(ctx, tablePosts)
+
+	if experiment.ID == "" {
+		experiment.ID = uuid.New().String()
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO experiments (id, slack_channel_id, created_by, created_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4)
+	`
+	if _, err := tx.Exec(ctx, query, experiment.ID, experiment.SlackChannelID, experiment.CreatedBy, experiment.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create experiment: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE posts SET experiment_id = $1
+		WHERE id = ANY($2) AND status = 'approved' AND experiment_id IS NULL
+	`, experiment.ID, postIDs)
+	if err != nil {
+		return fmt.Errorf("failed to link experiment posts: %w", err)
+	}
+	if result.RowsAffected() != int64(len(postIDs)) {
+		return ErrExperimentPosts
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit experiment: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the most recent experiments, newest first, with their
+// variations.
+func (r *ExperimentRepository) List(ctx context.Context, limit int) ([]*models.Experiment, error) {
+	return r.query(ctx, `
+		SELECT id, COALESCE(slack_channel_id, ''), COALESCE(created_by, ''), created_at, winner_post_id, concluded_at
+		FROM experiments
+		ORDER BY created_at DESC
+		LIMIT $1
+	`, limit)
+}
+
+// GetRunning returns the experiments that haven't been concluded, oldest
+// first, with their variations.
+func (r *ExperimentRepository) GetRunning(ctx context.Context) ([]*models.Experiment, error) {
+	return r.query(ctx, `
+		SELECT id, COALESCE(slack_channel_id, ''), COALESCE(created_by, ''), created_at, winner_post_id, concluded_at
+		FROM experiments
+		WHERE concluded_at IS NULL
+		ORDER BY created_at ASC
+	`)
+}
+
+// Conclude records the result of an experiment. winnerID is nil when
+// neither variation clearly won.
+func (r *ExperimentRepository) Conclude(ctx context.Context, id string, winnerID *string) error {
+	query := `UPDATE experiments SET winner_post_id = $2, concluded_at = $3 WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, winnerID, time.Now()); err != nil {
+		return fmt.Errorf("failed to conclude experiment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExperimentRepository) query(ctx context.Context, query string, args ...any) ([]*models.Experiment, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query experiments: %w", err)
+	}
+	defer rows.Close()
+
+	var experiments []*models.Experiment
+	byID := make(map[string]*models.Experiment)
+	var ids []string
+	for rows.Next() {
+		experiment := &models.Experiment{}
+		if err := rows.Scan(&experiment.ID, &experiment.SlackChannelID, &experiment.CreatedBy, &experiment.CreatedAt,
+			&experiment.WinnerPostID, &experiment.ConcludedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan experiment: %w", err)
+		}
+		experiments = append(experiments, experiment)
+		byID[experiment.ID] = experiment
+		ids = append(ids, experiment.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query experiments: %w", err)
+	}
+	if len(ids) == 0 {
+		return experiments, nil
+	}
+
+	postRows, err := r.db.Pool.Query(ctx, `
+		SELECT `+postColumns+`
+		FROM posts
+		WHERE experiment_id = ANY($1::uuid[]) AND deleted_at IS NULL
+		ORDER BY scheduled_at ASC NULLS LAST, created_at ASC
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query experiment posts: %w", err)
+	}
+	defer postRows.Close()
+
+	posts, err := scanPosts(postRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		if experiment := byID[*post.ExperimentID]; experiment != nil {
+			experiment.Posts = append(experiment.Posts, post)
+		}
+	}
+
+	return experiments, nil
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const failedJobColumns = `id, kind, COALESCE(thought_id, ''), COALESCE(slack_channel_id, ''), COALESCE(user_id, ''),
+	request, error, attempts, next_attempt_at, resolved_at, created_at`
+
+type FailedJobRepository struct {
+	db *DB
+}
+
+func NewFailedJobRepository(db *DB) *FailedJobRepository {
+	return &FailedJobRepository{db: db}
+}
+
+func (r *FailedJobRepository) Create(ctx context.Context, job *models.FailedJob) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO failed_jobs (id, kind, thought_id, slack_channel_id, user_id, request, error, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9, $10)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, job.ID, job.Kind, job.ThoughtID, job.ChannelID, job.UserID,
+		job.Request, job.Error, job.Attempts, job.NextAttemptAt, job.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create failed job: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue returns unresolved jobs whose next attempt is due, oldest first,
+// and pushes their next attempt back by lease so another replica doesn't
+// run them at the same time.
+func (r *FailedJobRepository) ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*models.FailedJob, error) {
+	query := `
+		UPDATE failed_jobs
+		SET next_attempt_at = NOW() + make_interval(secs => $1)
+		WHERE id IN (
+			SELECT id FROM failed_jobs
+			WHERE resolved_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + failedJobColumns
+
+	rows, err := r.db.Pool.Query(ctx, query, lease.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due jobs: %w", err)
+	}
+
+	return scanFailedJobs(rows)
+}
+
+// GetOpen returns every unresolved job, including those that ran out of
+// attempts, oldest first.
+func (r *FailedJobRepository) GetOpen(ctx context.Context) ([]*models.FailedJob, error) {
+	query := `
+		SELECT ` + failedJobColumns + `
+		FROM failed_jobs
+		WHERE resolved_at IS NULL
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open jobs: %w", err)
+	}
+
+	return scanFailedJobs(rows)
+}
+
+func (r *FailedJobRepository) Resolve(ctx context.Context, id string) error {
+	query := `UPDATE failed_jobs SET resolved_at = NOW(), next_attempt_at = NULL WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to resolve job: %w", err)
+	}
+
+	return nil
+}
+
+// RecordAttempt counts another failed attempt of a job. A nil nextAttempt
+// gives up on it.
+func (r *FailedJobRepository) RecordAttempt(ctx context.Context, id, errMessage string, nextAttempt *time.Time) error {
+	query := `
+		UPDATE failed_jobs
+		SET attempts = attempts + 1, error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, errMessage, nextAttempt); err != nil {
+		return fmt.Errorf("failed to record job attempt: %w", err)
+	}
+
+	return nil
+}
+
+// RetryAll makes every unresolved job due now with its attempts reset,
+// returning how many there were.
+func (r *FailedJobRepository) RetryAll(ctx context.Context) (int, error) {
+	query := `
+		UPDATE failed_jobs
+		SET attempts = 0, next_attempt_at = NOW()
+		WHERE resolved_at IS NULL
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retry jobs: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+func scanFailedJobs(rows pgx.Rows) ([]*models.FailedJob, error) {
+	defer rows.Close()
+
+	var jobs []*models.FailedJob
+	for rows.Next() {
+		job := &models.FailedJob{}
+		if err := rows.Scan(&job.ID, &job.Kind, &job.ThoughtID, &job.ChannelID, &job.UserID,
+			&job.Request, &job.Error, &job.Attempts, &job.NextAttemptAt, &job.ResolvedAt, &job.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan failed job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type ImportJobRepository struct {
+	db *DB
+}
+
+func NewImportJobRepository(db *DB) *ImportJobRepository {
+	return &ImportJobRepository{db: db}
+}
+
+func (r *ImportJobRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO import_jobs (id, filename, slack_channel_id, requested_by, progress_ts, status, total, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, job.ID, job.Filename, job.SlackChannelID, job.RequestedBy, job.ProgressTS, job.Status, job.Total, job.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateProgress saves a job's status, counts, error and finish time.
+func (r *ImportJobRepository) UpdateProgress(ctx context.Context, job *models.ImportJob) error {
+	query := `
+		UPDATE import_jobs
+		SET status = $2, imported = $3, skipped = $4, failed = $5, error = NULLIF($6, ''), finished_at = $7
+		WHERE id = $1
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, job.ID, job.Status, job.Imported, job.Skipped, job.Failed, job.Error, job.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("import job not found")
+	}
+
+	return nil
+}
+
+// GetByID returns pgx.ErrNoRows when there is no such job.
+func (r *ImportJobRepository) GetByID(ctx context.Context, id string) (*models.ImportJob, error) {
+	query := `
+		SELECT id, filename, COALESCE(slack_channel_id, ''), COALESCE(requested_by, ''), COALESCE(progress_ts, ''), status,
+		       total, imported, skipped, failed, COALESCE(error, ''), created_at, finished_at
+		FROM import_jobs
+		WHERE id = $1
+	`
+
+	job := &models.ImportJob{}
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+		&job.ID, &job.Filename, &job.SlackChannelID, &job.RequestedBy, &job.ProgressTS, &job.Status,
+		&job.Total, &job.Imported, &job.Skipped, &job.Failed, &job.Error, &job.CreatedAt, &job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const jobColumns = `id, kind, COALESCE(key, ''), payload, COALESCE(actor, ''), state, attempts, max_attempts,
+	run_at, COALESCE(locked_by, ''), lease_until, COALESCE(last_error, ''), created_at, finished_at`
+
+// ErrLeaseLost is returned when a worker updates a job it no longer holds,
+// because its lease ran out and another worker took the job.
+var ErrLeaseLost = errors.New("job lease lost")
+
+type JobRepository struct {
+	db *DB
+}
+
+func NewJobRepository(db *DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Enqueue saves a queued job. It reports false, without saving, when a job
+// with the same key is already queued or running.
+func (r *JobRepository) Enqueue(ctx context.Context, job *models.Job) (bool, error) {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO jobs (id, kind, key, payload, actor, state, max_attempts, run_at, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), $6, $7, $8, $9)
+		ON CONFLICT (key) WHERE state IN ('queued', 'running') DO NOTHING
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, job.ID, job.Kind, job.Key, job.Payload, job.Actor,
+		job.State, job.MaxAttempts, job.RunAt, job.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// Claim leases the next due job of kind to worker for lease, counting an
+// attempt. Jobs whose lease ran out are claimed again. It returns nil when
+// nothing is due.
+func (r *JobRepository) Claim(ctx context.Context, kind, worker string, lease time.Duration) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET state = 'running', attempts = attempts + 1, locked_by = $2,
+		    lease_until = NOW() + make_interval(secs => $3)
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE kind = $1
+			  AND ((state = 'queued' AND run_at <= NOW()) OR (state = 'running' AND lease_until < NOW()))
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.Pool.QueryRow(ctx, query, kind, worker, lease.Seconds()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Extend renews worker's lease on a running job.
+func (r *JobRepository) Extend(ctx context.Context, id, worker string, lease time.Duration) error {
+	query := `
+		UPDATE jobs
+		SET lease_until = NOW() + make_interval(secs => $3)
+		WHERE id = $1 AND locked_by = $2 AND state = 'running'
+	`
+
+	return r.finish(ctx, "extend job lease", query, id, worker, lease.Seconds())
+}
+
+func (r *JobRepository) Complete(ctx context.Context, id, worker string) error {
+	query := `
+		UPDATE jobs
+		SET state = 'done', lease_until = NULL, finished_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND state = 'running'
+	`
+
+	return r.finish(ctx, "complete job", query, id, worker)
+}
+
+// Retry queues a failed job again at runAt.
+func (r *JobRepository) Retry(ctx context.Context, id, worker, errMessage string, runAt time.Time) error {
+	query := `
+		UPDATE jobs
+		SET state = 'queued', run_at = $3, last_error = $4, locked_by = NULL, lease_until = NULL
+		WHERE id = $1 AND locked_by = $2 AND state = 'running'
+	`
+
+	return r.finish(ctx, "retry job", query, id, worker, runAt, errMessage)
+}
+
+func (r *JobRepository) Fail(ctx context.Context, id, worker, errMessage string) error {
+	query := `
+		UPDATE jobs
+		SET state = 'failed', last_error = $3, lease_until = NULL, finished_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND state = 'running'
+	`
+
+	return r.finish(ctx, "fail job", query, id, worker, errMessage)
+}
+
+// Release puts a job worker stopped before it finished back in the queue,
+// without counting the attempt.
+func (r *JobRepository) Release(ctx context.Context, id, worker string) error {
+	query := `
+		UPDATE jobs
+		SET state = 'queued', attempts = GREATEST(attempts - 1, 0), locked_by = NULL, lease_until = NULL
+		WHERE id = $1 AND locked_by = $2 AND state = 'running'
+	`
+
+	return r.finish(ctx, "release job", query, id, worker)
+}
+
+// Prune deletes jobs that finished before cutoff, returning how many.
+func (r *JobRepository) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	query := `DELETE FROM jobs WHERE state IN ('done', 'failed') AND finished_at < $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// finish runs an update of a job held by worker, returning ErrLeaseLost
+// when the worker no longer holds it.
+func (r *JobRepository) finish(ctx context.Context, what, query string, args ...any) error {
+	result, err := r.db.Pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", what, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+func scanJob(row pgx.Row) (*models.Job, error) {
+	job := &models.Job{}
+	err := row.Scan(&job.ID, &job.Kind, &job.Key, &job.Payload, &job.Actor, &job.State, &job.Attempts, &job.MaxAttempts,
+		&job.RunAt, &job.LockedBy, &job.LeaseUntil, &job.LastError, &job.CreatedAt, &job.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
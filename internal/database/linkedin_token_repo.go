@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type LinkedInTokenRepository struct {
+	db *DB
+}
+
+func NewLinkedInTokenRepository(db *DB) *LinkedInTokenRepository {
+	return &LinkedInTokenRepository{db: db}
+}
+
+func (r *LinkedInTokenRepository) Save(ctx context.Context, token *models.LinkedInToken) error {
+	if token.ID == "" {
+		token.ID = uuid.New().String()
+	}
+	if token.Kind == "" {
+		token.Kind = models.TokenMember
+	}
+
+	now := time.Now()
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = now
+	}
+	token.UpdatedAt = now
+
+	query := `
+		INSERT INTO linkedin_tokens (id, user_id, author_urn, access_token, refresh_token,
+		                             expires_at, refresh_token_expires_at, created_at, updated_at, kind)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id, kind) DO UPDATE
+		SET author_urn = EXCLUDED.author_urn,
+		    access_token = EXCLUDED.access_token,
+		    refresh_token = EXCLUDED.refresh_token,
+		    expires_at = EXCLUDED.expires_at,
+		    refresh_token_expires_at = EXCLUDED.refresh_token_expires_at,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		token.ID,
+		token.UserID,
+		token.AuthorURN,
+		token.AccessToken,
+		token.RefreshToken,
+		token.ExpiresAt,
+		token.RefreshTokenExpiresAt,
+		token.CreatedAt,
+		token.UpdatedAt,
+		token.Kind,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save linkedin token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LinkedInTokenRepository) GetByUserID(ctx context.Context, userID, kind string) (*models.LinkedInToken, error) {
+	query := `
+		SELECT id, user_id, kind, author_urn, access_token, COALESCE(refresh_token, ''),
+		       expires_at, refresh_token_expires_at, created_at, updated_at
+		FROM linkedin_tokens
+		WHERE user_id = $1 AND kind = $2
+	`
+
+	return r.scanOne(ctx, query, userID, kind)
+}
+
+// GetLatest returns the token of kind connected or refreshed last.
+func (r *LinkedInTokenRepository) GetLatest(ctx context.Context, kind string) (*models.LinkedInToken, error) {
+	query := `
+		SELECT id, user_id, kind, author_urn, access_token, COALESCE(refresh_token, ''),
+		       expires_at, refresh_token_expires_at, created_at, updated_at
+		FROM linkedin_tokens
+		WHERE kind = $1
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`
+
+	return r.scanOne(ctx, query, kind)
+}
+
+func (r *LinkedInTokenRepository) scanOne(ctx context.Context, query string, args ...interface{}) (*models.LinkedInToken, error) {
+	token := &models.LinkedInToken{}
+	err := r.db.Pool.QueryRow(ctx, query, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Kind,
+		&token.AuthorURN,
+		&token.AccessToken,
+		&token.RefreshToken,
+		&token.ExpiresAt,
+		&token.RefreshTokenExpiresAt,
+		&token.CreatedAt,
+		&token.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("linkedin token not found: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *LinkedInTokenRepository) Delete(ctx context.Context, userID, kind string) error {
+	query := `DELETE FROM linkedin_tokens WHERE user_id = $1 AND kind = $2`
+
+	result, err := r.db.Pool.Exec(ctx, query, userID, kind)
+	if err != nil {
+		return fmt.Errorf("failed to delete linkedin token: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("linkedin token not found")
+	}
+
+	return nil
+}
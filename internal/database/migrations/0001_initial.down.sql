@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS channel_workspaces;
+DROP TABLE IF EXISTS draft_messages;
+DROP TABLE IF EXISTS linkedin_tokens;
+DROP TABLE IF EXISTS writing_style_profile;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS brainstorm_sessions;
+DROP TABLE IF EXISTS thoughts;
@@ -0,0 +1,96 @@
+-- Baseline schema. Every statement is idempotent so databases created before
+-- versioned migrations existed adopt this version unchanged.
+
+CREATE TABLE IF NOT EXISTS thoughts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source VARCHAR(50) NOT NULL,
+    content TEXT NOT NULL,
+    category VARCHAR(100),
+    topic_tags TEXT[],
+    status VARCHAR(50) DEFAULT 'raw',
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    related_thoughts UUID[],
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_thoughts_status ON thoughts(status);
+CREATE INDEX IF NOT EXISTS idx_thoughts_category ON thoughts(category);
+CREATE INDEX IF NOT EXISTS idx_thoughts_timestamp ON thoughts(timestamp DESC);
+ALTER TABLE thoughts ADD COLUMN IF NOT EXISTS slack_channel_id VARCHAR(50);
+ALTER TABLE thoughts ADD COLUMN IF NOT EXISTS slack_thread_ts VARCHAR(50);
+CREATE INDEX IF NOT EXISTS idx_thoughts_slack_thread ON thoughts(slack_channel_id, slack_thread_ts);
+ALTER TABLE thoughts ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_thoughts_external_id ON thoughts(source, external_id) WHERE external_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_thoughts_content_fts ON thoughts USING GIN (to_tsvector('english', content));
+ALTER TABLE thoughts ADD COLUMN IF NOT EXISTS used_by_post_id UUID;
+
+CREATE TABLE IF NOT EXISTS brainstorm_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    topic VARCHAR(255) NOT NULL,
+    thought_ids UUID[],
+    brainstorm_content TEXT,
+    key_angles TEXT[],
+    status VARCHAR(50) DEFAULT 'in_progress',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_brainstorm_status ON brainstorm_sessions(status);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    content TEXT NOT NULL,
+    status VARCHAR(50) DEFAULT 'draft',
+    source_thought_ids UUID[],
+    brainstorm_session_id UUID,
+    post_type VARCHAR(50),
+    tone VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    scheduled_at TIMESTAMP,
+    published_at TIMESTAMP,
+    metrics JSONB DEFAULT '{"likes": 0, "comments": 0, "shares": 0, "views": 0}',
+    performance_score DECIMAL(5,2) DEFAULT 0.0,
+    FOREIGN KEY (brainstorm_session_id) REFERENCES brainstorm_sessions(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_posts_status ON posts(status);
+CREATE INDEX IF NOT EXISTS idx_posts_scheduled ON posts(scheduled_at);
+CREATE INDEX IF NOT EXISTS idx_posts_published ON posts(published_at DESC);
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(50);
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS parent_post_id UUID REFERENCES posts(id) ON DELETE SET NULL;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS linkedin_urn VARCHAR(255);
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS metrics_synced_at TIMESTAMP;
+ALTER TABLE posts ALTER COLUMN performance_score TYPE DECIMAL(10,2);
+
+CREATE TABLE IF NOT EXISTS writing_style_profile (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(100) NOT NULL UNIQUE,
+    style_patterns JSONB,
+    tone_preferences JSONB,
+    high_performing_elements JSONB,
+    sample_posts JSONB,
+    last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS linkedin_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(100) NOT NULL UNIQUE,
+    author_urn VARCHAR(255) NOT NULL,
+    access_token TEXT NOT NULL,
+    refresh_token TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    refresh_token_expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS draft_messages (
+    message_ts VARCHAR(50) PRIMARY KEY,
+    channel_id VARCHAR(50) NOT NULL,
+    post_ids UUID[] NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS channel_workspaces (
+    channel_id VARCHAR(50) PRIMARY KEY,
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
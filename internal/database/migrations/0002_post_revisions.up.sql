@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    editor VARCHAR(100),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_post_revisions_post ON post_revisions(post_id, created_at);
+
+-- Posts created before revisions were tracked start with their current text.
+INSERT INTO post_revisions (post_id, content, source, created_at)
+SELECT id, content, 'import', created_at FROM posts;
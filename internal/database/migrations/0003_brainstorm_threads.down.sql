@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_brainstorm_slack_message;
+ALTER TABLE brainstorm_sessions DROP COLUMN IF EXISTS slack_message_ts;
+ALTER TABLE brainstorm_sessions DROP COLUMN IF EXISTS slack_channel_id;
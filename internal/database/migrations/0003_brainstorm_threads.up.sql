@@ -0,0 +1,3 @@
+ALTER TABLE brainstorm_sessions ADD COLUMN IF NOT EXISTS slack_channel_id VARCHAR(50);
+ALTER TABLE brainstorm_sessions ADD COLUMN IF NOT EXISTS slack_message_ts VARCHAR(50);
+CREATE INDEX IF NOT EXISTS idx_brainstorm_slack_message ON brainstorm_sessions(slack_channel_id, slack_message_ts);
@@ -0,0 +1,3 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS image_alt_text;
+ALTER TABLE posts DROP COLUMN IF EXISTS image_path;
+ALTER TABLE posts DROP COLUMN IF EXISTS image_concept;
@@ -0,0 +1,3 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS image_concept TEXT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS image_path TEXT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS image_alt_text TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS slides TEXT[];
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS document_path TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE thoughts DROP COLUMN IF EXISTS source_title;
+ALTER TABLE thoughts DROP COLUMN IF EXISTS source_url;
@@ -0,0 +1,2 @@
+ALTER TABLE thoughts ADD COLUMN IF NOT EXISTS source_url TEXT;
+ALTER TABLE thoughts ADD COLUMN IF NOT EXISTS source_title TEXT;
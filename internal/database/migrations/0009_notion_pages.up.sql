@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS notion_pages (
+    post_id UUID PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    page_id TEXT NOT NULL,
+    synced_state TEXT NOT NULL,
+    synced_at TIMESTAMP NOT NULL
+);
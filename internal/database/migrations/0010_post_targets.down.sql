@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS x_post_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS targets;
@@ -0,0 +1,2 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS targets TEXT[];
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS x_post_id TEXT;
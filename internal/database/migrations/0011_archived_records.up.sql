@@ -0,0 +1,10 @@
+-- Rows moved out of their tables by the cleanup job, kept as JSON so the
+-- archive survives later schema changes.
+CREATE TABLE IF NOT EXISTS archived_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    record_type VARCHAR(50) NOT NULL,
+    record_id UUID NOT NULL,
+    data JSONB NOT NULL,
+    archived_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_archived_records_record ON archived_records(record_type, record_id);
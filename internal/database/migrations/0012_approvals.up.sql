@@ -0,0 +1,10 @@
+-- Per-user review decisions. In review mode a post is only approved once
+-- both its author and the reviewer have approved it here.
+CREATE TABLE IF NOT EXISTS approvals (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id VARCHAR(50) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    decided_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id)
+);
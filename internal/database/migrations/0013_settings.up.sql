@@ -0,0 +1,8 @@
+-- Settings changed at runtime from Slack, such as the model each LLM role
+-- uses, so they outlast restarts.
+CREATE TABLE IF NOT EXISTS settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by VARCHAR(50),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,8 @@
+-- Prompt templates edited from Slack. A row replaces the built-in template
+-- of the same name until it is reset.
+CREATE TABLE IF NOT EXISTS prompts (
+    name VARCHAR(50) PRIMARY KEY,
+    template TEXT NOT NULL,
+    updated_by VARCHAR(50),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,18 @@
+-- Categories the categorizer may file thoughts under, managed from Slack.
+-- Seeded with the categories that used to be built into the prompt.
+CREATE TABLE IF NOT EXISTS categories (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO categories (name) VALUES
+    ('technical'),
+    ('business'),
+    ('learning'),
+    ('product_update'),
+    ('personal'),
+    ('industry_insight'),
+    ('milestone')
+ON CONFLICT (name) DO NOTHING;
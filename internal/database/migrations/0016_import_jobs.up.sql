@@ -0,0 +1,16 @@
+-- Bulk imports of old notes, with their progress, so Slack and the admin
+-- API can report on a job while it runs.
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id VARCHAR(50) PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL DEFAULT '',
+    slack_channel_id VARCHAR(50),
+    requested_by VARCHAR(50),
+    status VARCHAR(20) NOT NULL,
+    total INTEGER NOT NULL DEFAULT 0,
+    imported INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
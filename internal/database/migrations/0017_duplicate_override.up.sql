@@ -0,0 +1 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS allow_duplicate BOOLEAN NOT NULL DEFAULT FALSE;
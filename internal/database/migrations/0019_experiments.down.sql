@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_experiment_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS experiment_id;
+DROP TABLE IF EXISTS experiments;
@@ -0,0 +1,13 @@
+-- A/B experiments: two variations of the same post published a week apart
+-- to see which framing does better.
+CREATE TABLE IF NOT EXISTS experiments (
+    id UUID PRIMARY KEY,
+    slack_channel_id VARCHAR(50),
+    created_by VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    winner_post_id UUID REFERENCES posts(id) ON DELETE SET NULL,
+    concluded_at TIMESTAMP
+);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS experiment_id UUID REFERENCES experiments(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_experiment_id ON posts(experiment_id);
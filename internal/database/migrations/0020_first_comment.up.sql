@@ -0,0 +1 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS first_comment TEXT;
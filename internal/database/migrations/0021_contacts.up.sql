@@ -0,0 +1,12 @@
+-- People and companies the bot tags when a post names them.
+CREATE TABLE IF NOT EXISTS contacts (
+    id UUID PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    linkedin_urn VARCHAR(100),
+    profile_url TEXT,
+    created_by VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_name ON contacts(LOWER(name));
@@ -0,0 +1,10 @@
+-- People who asked for a daily DM asking what they worked on. Replies are
+-- saved as thoughts in channel_id, the channel they opted in from.
+CREATE TABLE IF NOT EXISTS check_ins (
+    user_id VARCHAR(50) PRIMARY KEY,
+    channel_id VARCHAR(50) NOT NULL,
+    prompt_time VARCHAR(5) NOT NULL,
+    pending_category VARCHAR(100),
+    last_prompted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
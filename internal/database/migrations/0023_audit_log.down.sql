@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS posts_audit_status ON posts;
+DROP FUNCTION IF EXISTS audit_post_status();
+DROP TABLE IF EXISTS audit_log;
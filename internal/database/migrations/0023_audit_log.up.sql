@@ -0,0 +1,41 @@
+-- Who did what: commands run in Slack, every change of a post's status and
+-- every publish attempt. post_id has no foreign key so a post's trail
+-- outlives it. actor is a Slack user ID, "api" or "cli", and NULL for the
+-- bot's own workers.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID,
+    actor VARCHAR(50),
+    action VARCHAR(20) NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20),
+    detail TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_post ON audit_log(post_id, created_at);
+
+-- Status changes are recorded by the database so none are missed. The
+-- actor comes from the ghostwriter.actor setting of the transaction.
+CREATE OR REPLACE FUNCTION audit_post_status() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.status IS NOT DISTINCT FROM OLD.status THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO audit_log (post_id, actor, action, from_status, to_status)
+    VALUES (
+        NEW.id,
+        NULLIF(current_setting('ghostwriter.actor', true), ''),
+        'transition',
+        CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END,
+        NEW.status
+    );
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_audit_status
+    AFTER INSERT OR UPDATE OF status ON posts
+    FOR EACH ROW EXECUTE FUNCTION audit_post_status();
@@ -0,0 +1,9 @@
+-- Who may do what in Slack. Owners run everything, editors write and
+-- approve drafts, and viewers (and anyone not listed) only read. With no
+-- rows every user is treated as an owner.
+CREATE TABLE IF NOT EXISTS users (
+    user_id VARCHAR(50) PRIMARY KEY,
+    role VARCHAR(20) NOT NULL,
+    added_by VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,19 @@
+-- Categorizations and generations that failed, kept so a worker can retry
+-- them with a backoff instead of the thought staying mis-tagged or the
+-- request being lost. A job that ran out of attempts has no next attempt
+-- and waits for `retry failed`.
+CREATE TABLE IF NOT EXISTS failed_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL,
+    thought_id VARCHAR(50),
+    slack_channel_id VARCHAR(50),
+    user_id VARCHAR(50),
+    request TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    next_attempt_at TIMESTAMP,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_jobs_next_attempt_at ON failed_jobs(next_attempt_at) WHERE resolved_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS progress_ts;
+
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,27 @@
+-- Long-running work such as generating drafts, imports and publishing,
+-- queued so any replica of the bot can run it. A worker leases a job while
+-- it runs it; a job whose lease ran out is picked up again. Only one job
+-- with a key is queued or running at a time.
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(50) NOT NULL,
+    key VARCHAR(255),
+    payload JSONB NOT NULL DEFAULT '{}',
+    actor VARCHAR(100),
+    state VARCHAR(20) NOT NULL DEFAULT 'queued',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_by VARCHAR(255),
+    lease_until TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_active_key ON jobs(key) WHERE state IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_jobs_runnable ON jobs(kind, run_at) WHERE state IN ('queued', 'running');
+
+-- Imports run as jobs and report on a Slack message that any replica
+-- updates, so the message is kept with the import.
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS progress_ts VARCHAR(50);
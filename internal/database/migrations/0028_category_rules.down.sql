@@ -0,0 +1 @@
+ALTER TABLE categories DROP COLUMN IF EXISTS rules;
@@ -0,0 +1,3 @@
+-- Keywords and /regex/ patterns that file a thought under a category
+-- without asking the categorizer.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS rules TEXT[] NOT NULL DEFAULT '{}';
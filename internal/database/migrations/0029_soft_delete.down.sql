@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_deleted_at;
+DROP INDEX IF EXISTS idx_thoughts_deleted_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE thoughts DROP COLUMN IF EXISTS deleted_at;
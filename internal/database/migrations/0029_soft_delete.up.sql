@@ -0,0 +1,6 @@
+-- Deleted thoughts and posts stay in the trash until the purge job removes
+-- them, so a delete can be undone with restore.
+ALTER TABLE thoughts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_thoughts_deleted_at ON thoughts(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts(deleted_at) WHERE deleted_at IS NOT NULL;
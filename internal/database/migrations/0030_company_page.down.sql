@@ -0,0 +1,5 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS company_post_urn;
+DELETE FROM linkedin_tokens WHERE kind <> 'member';
+ALTER TABLE linkedin_tokens DROP CONSTRAINT IF EXISTS linkedin_tokens_user_id_kind_key;
+ALTER TABLE linkedin_tokens ADD CONSTRAINT linkedin_tokens_user_id_key UNIQUE (user_id);
+ALTER TABLE linkedin_tokens DROP COLUMN IF EXISTS kind;
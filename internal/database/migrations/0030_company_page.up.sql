@@ -0,0 +1,6 @@
+-- A user can connect a company page besides their own profile, so tokens
+-- are kept per user and kind.
+ALTER TABLE linkedin_tokens ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'member';
+ALTER TABLE linkedin_tokens DROP CONSTRAINT IF EXISTS linkedin_tokens_user_id_key;
+ALTER TABLE linkedin_tokens ADD CONSTRAINT linkedin_tokens_user_id_kind_key UNIQUE (user_id, kind);
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS company_post_urn TEXT;
@@ -0,0 +1,12 @@
+-- Dates nothing is scheduled or published on, and pauses of publishing.
+-- A pause without an end_date lasts until it is resumed.
+CREATE TABLE IF NOT EXISTS blackouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL DEFAULT 'blackout',
+    start_date DATE NOT NULL,
+    end_date DATE,
+    reason TEXT,
+    created_by VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_blackouts_end_date ON blackouts(end_date);
@@ -0,0 +1 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS confirmation_sent_for;
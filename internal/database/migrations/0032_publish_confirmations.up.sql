@@ -0,0 +1,3 @@
+-- The scheduled time a post was last sent for confirmation, so moving it
+-- asks again before the new time.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS confirmation_sent_for TIMESTAMP;
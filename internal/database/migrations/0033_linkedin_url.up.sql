@@ -0,0 +1,7 @@
+-- The canonical URL of a post published to LinkedIn, shared in Slack so
+-- teammates can engage early.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS linkedin_url TEXT;
+
+UPDATE posts
+SET linkedin_url = 'https://www.linkedin.com/feed/update/' || linkedin_urn || '/'
+WHERE linkedin_urn IS NOT NULL AND linkedin_url IS NULL;
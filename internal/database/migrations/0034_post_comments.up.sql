@@ -0,0 +1,15 @@
+-- Comments on published posts, synced from LinkedIn, with the reply drafted
+-- for each. replied_at is set once a reply was posted or marked done.
+CREATE TABLE IF NOT EXISTS post_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    comment_urn VARCHAR(255) NOT NULL UNIQUE,
+    author_urn VARCHAR(255),
+    content TEXT NOT NULL,
+    commented_at TIMESTAMP NOT NULL,
+    reply_draft TEXT,
+    replied_at TIMESTAMP,
+    replied_by VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_post_comments_post_id ON post_comments(post_id, commented_at);
@@ -0,0 +1,38 @@
+-- Reusable post structures `generate --template` makes drafts follow,
+-- seeded with a few that tend to do well on LinkedIn.
+CREATE TABLE IF NOT EXISTS post_templates (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    skeleton TEXT NOT NULL,
+    created_by VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO post_templates (name, description, skeleton) VALUES
+('contrarian', 'Push back on a popular belief',
+'1. Hook: state the popular belief in one line, then say plainly that you disagree
+2. Why people believe it: one or two lines, fairly put
+3. What you saw instead: a specific story, number or example from your own work
+4. The better rule: what you do now, in one or two lines
+5. Close: ask readers where they stand'),
+('before-after', 'Show a change and what caused it',
+'1. Hook: the result, with a number if there is one
+2. Before: how things were and what it cost, in concrete terms
+3. The turning point: the one decision or change that made the difference
+4. After: how things are now, with specifics
+5. Takeaway: what readers can try themselves
+6. Close: a question about their own before/after'),
+('listicle', 'A numbered list of lessons or tips',
+'1. Hook: promise the list, such as "5 things I wish I knew about X"
+2. One line of context on why you are qualified to say it
+3. The list: 3-7 numbered items, each a bold-worthy first line and one sentence of explanation or example
+4. Close: ask readers what they would add'),
+('failure-story', 'Own a mistake and what it taught you',
+'1. Hook: admit the failure in one line, without softening it
+2. The setup: what you were trying to do and why it seemed right
+3. What went wrong: the moment it fell apart, with specifics
+4. What it cost and how you handled it
+5. The lesson: what you do differently now
+6. Close: invite readers to share a failure that taught them something')
+ON CONFLICT (name) DO NOTHING;
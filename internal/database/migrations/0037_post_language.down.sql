@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS translation_of;
+ALTER TABLE posts DROP COLUMN IF EXISTS language;
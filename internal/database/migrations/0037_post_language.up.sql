@@ -0,0 +1,4 @@
+-- The language a post is written in, and for translations the post they
+-- were translated from, so both versions can be published side by side.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS language VARCHAR(10) NOT NULL DEFAULT 'en';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS translation_of UUID REFERENCES posts(id) ON DELETE SET NULL;
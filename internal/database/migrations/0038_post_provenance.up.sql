@@ -0,0 +1,4 @@
+-- Where the thoughts a post was written from came from, such as Linear
+-- issues and GitHub pull requests, so its claims can be checked before it
+-- is approved.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS provenance JSONB;
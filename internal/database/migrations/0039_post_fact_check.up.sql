@@ -0,0 +1,3 @@
+-- What the optional fact check found in a generated draft: the claims its
+-- source thoughts don't back up.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS fact_check JSONB;
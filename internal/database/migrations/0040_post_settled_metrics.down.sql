@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS settled_score;
+ALTER TABLE posts DROP COLUMN IF EXISTS settled_metrics;
@@ -0,0 +1,5 @@
+-- The metrics of a post as first synced once it had been out for the
+-- settle period, so experiment variations published a week apart are
+-- compared at the same age.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS settled_metrics JSONB;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS settled_score DECIMAL(10,2);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_posts_job_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS job_id;
@@ -0,0 +1,4 @@
+-- The queued job a draft was generated by, so a job that runs again after
+-- a crash or restart reuses its drafts instead of writing more.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS job_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_posts_job_id ON posts(job_id) WHERE job_id IS NOT NULL;
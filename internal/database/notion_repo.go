@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NotionPage links a post to the Notion page it was written to. State is
+// the post state last written, so unchanged posts aren't rewritten.
+type NotionPage struct {
+	PostID string
+	PageID string
+	State  string
+}
+
+type NotionPageRepository struct {
+	db *DB
+}
+
+func NewNotionPageRepository(db *DB) *NotionPageRepository {
+	return &NotionPageRepository{db: db}
+}
+
+// GetAll returns every synced page keyed by post ID.
+func (r *NotionPageRepository) GetAll(ctx context.Context) (map[string]*NotionPage, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT post_id, page_id, synced_state FROM notion_pages`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notion pages: %w", err)
+	}
+	defer rows.Close()
+
+	pages := map[string]*NotionPage{}
+	for rows.Next() {
+		page := &NotionPage{}
+		if err := rows.Scan(&page.PostID, &page.PageID, &page.State); err != nil {
+			return nil, fmt.Errorf("failed to scan notion page: %w", err)
+		}
+		pages[page.PostID] = page
+	}
+
+	return pages, rows.Err()
+}
+
+func (r *NotionPageRepository) Save(ctx context.Context, page *NotionPage) error {
+	query := `
+		INSERT INTO notion_pages (post_id, page_id, synced_state, synced_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (post_id) DO UPDATE
+		SET page_id = EXCLUDED.page_id, synced_state = EXCLUDED.synced_state, synced_at = EXCLUDED.synced_at
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, page.PostID, page.PageID, page.State, time.Now()); err != nil {
+		return fmt.Errorf("failed to save notion page: %w", err)
+	}
+
+	return nil
+}
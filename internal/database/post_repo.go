@@ -7,9 +7,31 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
 )
 
+const postColumns = `id, content, status, source_thought_ids, brainstorm_session_id,
+		       parent_post_id, post_type, tone, created_at, scheduled_at, published_at,
+		       metrics, performance_score, reviewed_by, reviewed_at,
+		       COALESCE(linkedin_urn, ''), metrics_synced_at,
+		       COALESCE(image_concept, ''), COALESCE(image_path, ''), COALESCE(image_alt_text, ''),
+		       slides, COALESCE(document_path, ''), poll, targets, COALESCE(x_post_id, ''),
+		       allow_duplicate, scores, experiment_id, COALESCE(first_comment, ''),
+		       COALESCE(company_post_urn, ''), COALESCE(linkedin_url, ''), language, translation_of,
+		       provenance, fact_check, settled_metrics, settled_score, COALESCE(job_id, '')`
+
+type SimilarPost struct {
+	Post       *models.Post
+	Similarity float64
+}
+
+// DeletedPost is a post in the trash.
+type DeletedPost struct {
+	Post      *models.Post
+	DeletedAt time.Time
+}
+
 type PostRepository struct {
 	db *DB
 }
@@ -19,6 +41,8 @@ func NewPostRepository(db *DB) *PostRepository {
 }
 
 func (r *PostRepository) Create(ctx context.Context, post *models.Post) error {
+	defer r.db.changed(ctx, tablePosts)
+
 	if post.ID == "" {
 		post.ID = uuid.New().String()
 	}
@@ -32,19 +56,46 @@ func (r *PostRepository) Create(ctx context.Context, post *models.Post) error {
 		return fmt.Errorf("failed to marshal metrics: %w", err)
 	}
 
+	var pollJSON []byte
+	if post.Poll != nil {
+		if pollJSON, err = json.Marshal(post.Poll); err != nil {
+			return fmt.Errorf("failed to marshal poll: %w", err)
+		}
+	}
+
+	scoresJSON, err := scorePost(post)
+	if err != nil {
+		return err
+	}
+
+	var provenanceJSON []byte
+	if len(post.Provenance) > 0 {
+		if provenanceJSON, err = json.Marshal(post.Provenance); err != nil {
+			return fmt.Errorf("failed to marshal provenance: %w", err)
+		}
+	}
+
+	factCheckJSON, err := marshalFactCheck(post)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO posts (id, content, status, source_thought_ids, brainstorm_session_id, 
-		                   post_type, tone, created_at, scheduled_at, published_at, 
-		                   metrics, performance_score)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO posts (id, content, status, source_thought_ids, brainstorm_session_id,
+		                   parent_post_id, post_type, tone, created_at, scheduled_at, published_at,
+		                   metrics, performance_score, slides, document_path, poll, targets, scores, first_comment,
+		                   language, translation_of, provenance, fact_check, job_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), $16, $17, $18, NULLIF($19, ''),
+		        COALESCE(NULLIF($20, ''), 'en'), $21, $22, $23, NULLIF($24, ''))
 	`
 
-	_, err = r.db.Pool.Exec(ctx, query,
+	_, err = r.db.exec(ctx, query,
 		post.ID,
 		post.Content,
 		post.Status,
 		post.SourceThoughtIDs,
 		post.BrainstormSessionID,
+		post.ParentPostID,
 		post.PostType,
 		post.Tone,
 		post.CreatedAt,
@@ -52,6 +103,17 @@ func (r *PostRepository) Create(ctx context.Context, post *models.Post) error {
 		post.PublishedAt,
 		metricsJSON,
 		post.PerformanceScore,
+		post.Slides,
+		post.DocumentPath,
+		pollJSON,
+		post.Targets,
+		scoresJSON,
+		post.FirstComment,
+		post.Language,
+		post.TranslationOf,
+		provenanceJSON,
+		factCheckJSON,
+		post.JobID,
 	)
 
 	if err != nil {
@@ -63,98 +125,81 @@ func (r *PostRepository) Create(ctx context.Context, post *models.Post) error {
 
 func (r *PostRepository) GetByID(ctx context.Context, id string) (*models.Post, error) {
 	query := `
-		SELECT id, content, status, source_thought_ids, brainstorm_session_id, 
-		       post_type, tone, created_at, scheduled_at, published_at, 
-		       metrics, performance_score
+		SELECT ` + postColumns + `
 		FROM posts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	post := &models.Post{}
-	var metricsJSON []byte
-
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&post.ID,
-		&post.Content,
-		&post.Status,
-		&post.SourceThoughtIDs,
-		&post.BrainstormSessionID,
-		&post.PostType,
-		&post.Tone,
-		&post.CreatedAt,
-		&post.ScheduledAt,
-		&post.PublishedAt,
-		&metricsJSON,
-		&post.PerformanceScore,
-	)
-
+	post, err := scanPost(r.db.Pool.QueryRow(ctx, query, id))
 	if err != nil {
 		return nil, fmt.Errorf("post not found: %w", err)
 	}
 
-	if err := json.Unmarshal(metricsJSON, &post.Metrics); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal metrics: %w", err)
+	return post, nil
+}
+
+// GetByJobID returns the posts generated by the queued job with jobID,
+// oldest first.
+func (r *PostRepository) GetByJobID(ctx context.Context, jobID string) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE job_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query posts by job: %w", err)
 	}
+	defer rows.Close()
 
-	return post, nil
+	return scanPosts(rows)
 }
 
-func (r *PostRepository) GetByStatus(ctx context.Context, status string) ([]*models.Post, error) {
+// List returns a page of posts, newest first, optionally filtered by status.
+func (r *PostRepository) List(ctx context.Context, status string, limit, offset int) ([]*models.Post, error) {
 	query := `
-		SELECT id, content, status, source_thought_ids, brainstorm_session_id, 
-		       post_type, tone, created_at, scheduled_at, published_at, 
-		       metrics, performance_score
+		SELECT ` + postColumns + `
 		FROM posts
-		WHERE status = $1
+		WHERE deleted_at IS NULL AND ($1 = '' OR status = $1)
 		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, status)
+	rows, err := r.db.Pool.Query(ctx, query, status, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query posts: %w", err)
 	}
 	defer rows.Close()
 
-	var posts []*models.Post
-	for rows.Next() {
-		post := &models.Post{}
-		var metricsJSON []byte
-
-		err := rows.Scan(
-			&post.ID,
-			&post.Content,
-			&post.Status,
-			&post.SourceThoughtIDs,
-			&post.BrainstormSessionID,
-			&post.PostType,
-			&post.Tone,
-			&post.CreatedAt,
-			&post.ScheduledAt,
-			&post.PublishedAt,
-			&metricsJSON,
-			&post.PerformanceScore,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan post: %w", err)
-		}
+	return scanPosts(rows)
+}
 
-		if err := json.Unmarshal(metricsJSON, &post.Metrics); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metrics: %w", err)
+func (r *PostRepository) GetByStatus(ctx context.Context, status string) ([]*models.Post, error) {
+	return cached(ctx, r.db, "posts.by_status:"+status, func() ([]*models.Post, error) {
+		query := `
+			SELECT ` + postColumns + `
+			FROM posts
+			WHERE status = $1 AND deleted_at IS NULL
+			ORDER BY created_at DESC
+		`
+
+		rows, err := r.db.Pool.Query(ctx, query, status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query posts: %w", err)
 		}
+		defer rows.Close()
 
-		posts = append(posts, post)
-	}
-
-	return posts, nil
+		return scanPosts(rows)
+	}, tablePosts)
 }
 
 func (r *PostRepository) GetScheduledPosts(ctx context.Context) ([]*models.Post, error) {
 	query := `
-		SELECT id, content, status, source_thought_ids, brainstorm_session_id, 
-		       post_type, tone, created_at, scheduled_at, published_at, 
-		       metrics, performance_score
+		SELECT ` + postColumns + `
 		FROM posts
-		WHERE status = 'scheduled' AND scheduled_at <= $1
+		WHERE status = 'scheduled' AND scheduled_at <= $1 AND deleted_at IS NULL
 		ORDER BY scheduled_at ASC
 	`
 
@@ -164,54 +209,118 @@ func (r *PostRepository) GetScheduledPosts(ctx context.Context) ([]*models.Post,
 	}
 	defer rows.Close()
 
-	var posts []*models.Post
-	for rows.Next() {
-		post := &models.Post{}
-		var metricsJSON []byte
-
-		err := rows.Scan(
-			&post.ID,
-			&post.Content,
-			&post.Status,
-			&post.SourceThoughtIDs,
-			&post.BrainstormSessionID,
-			&post.PostType,
-			&post.Tone,
-			&post.CreatedAt,
-			&post.ScheduledAt,
-			&post.PublishedAt,
-			&metricsJSON,
-			&post.PerformanceScore,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan post: %w", err)
-		}
+	return scanPosts(rows)
+}
 
-		if err := json.Unmarshal(metricsJSON, &post.Metrics); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metrics: %w", err)
-		}
+// GetAwaitingConfirmation returns the scheduled posts due by before, but
+// not yet, that haven't been sent for confirmation at their current time.
+func (r *PostRepository) GetAwaitingConfirmation(ctx context.Context, before time.Time) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status = 'scheduled' AND scheduled_at > $1 AND scheduled_at <= $2 AND deleted_at IS NULL
+		  AND confirmation_sent_for IS DISTINCT FROM scheduled_at
+		ORDER BY scheduled_at ASC
+	`
 
-		posts = append(posts, post)
+	rows, err := r.db.Pool.Query(ctx, query, time.Now(), before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query posts awaiting confirmation: %w", err)
 	}
+	defer rows.Close()
 
-	return posts, nil
+	return scanPosts(rows)
+}
+
+// ClaimConfirmation records that a post scheduled at scheduledAt is being
+// sent for confirmation. It reports false when it already was, or has
+// moved since.
+func (r *PostRepository) ClaimConfirmation(ctx context.Context, id string, scheduledAt time.Time) (bool, error) {
+	query := `
+		UPDATE posts SET confirmation_sent_for = scheduled_at
+		WHERE id = $1 AND status = 'scheduled' AND scheduled_at = $2
+		  AND confirmation_sent_for IS DISTINCT FROM scheduled_at
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, scheduledAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim confirmation: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// ReleaseConfirmation undoes ClaimConfirmation when the post couldn't be
+// sent, so it is sent again on the next round.
+func (r *PostRepository) ReleaseConfirmation(ctx context.Context, id string, scheduledAt time.Time) error {
+	query := `UPDATE posts SET confirmation_sent_for = NULL WHERE id = $1 AND confirmation_sent_for = $2`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, scheduledAt); err != nil {
+		return fmt.Errorf("failed to release confirmation: %w", err)
+	}
+
+	return nil
+}
+
+// MoveSchedule moves a post scheduled at from to to. It reports false when
+// the post is no longer scheduled at from.
+func (r *PostRepository) MoveSchedule(ctx context.Context, id string, from, to time.Time) (bool, error) {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET scheduled_at = $3 WHERE id = $1 AND status = 'scheduled' AND scheduled_at = $2`
+
+	result, err := r.db.exec(ctx, query, id, from, to)
+	if err != nil {
+		return false, fmt.Errorf("failed to move post: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// Unschedule takes a post scheduled at scheduledAt back to approved. It
+// reports false when the post is no longer scheduled then.
+func (r *PostRepository) Unschedule(ctx context.Context, id string, scheduledAt time.Time) (bool, error) {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET status = 'approved', scheduled_at = NULL WHERE id = $1 AND status = 'scheduled' AND scheduled_at = $2`
+
+	result, err := r.db.exec(ctx, query, id, scheduledAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to unschedule post: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
 }
 
 func (r *PostRepository) Update(ctx context.Context, post *models.Post) error {
+	defer r.db.changed(ctx, tablePosts)
+
 	metricsJSON, err := json.Marshal(post.Metrics)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metrics: %w", err)
 	}
 
+	scoresJSON, err := scorePost(post)
+	if err != nil {
+		return err
+	}
+
+	factCheckJSON, err := marshalFactCheck(post)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE posts
 		SET content = $2, status = $3, source_thought_ids = $4, brainstorm_session_id = $5,
 		    post_type = $6, tone = $7, scheduled_at = $8, published_at = $9,
-		    metrics = $10, performance_score = $11
+		    metrics = $10, performance_score = $11, linkedin_urn = NULLIF($12, ''),
+		    x_post_id = NULLIF($13, ''), scores = $14, first_comment = NULLIF($15, ''),
+		    company_post_urn = NULLIF($16, ''), linkedin_url = NULLIF($17, ''), fact_check = $18
 		WHERE id = $1
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query,
+	result, err := r.db.exec(ctx, query,
 		post.ID,
 		post.Content,
 		post.Status,
@@ -223,6 +332,13 @@ func (r *PostRepository) Update(ctx context.Context, post *models.Post) error {
 		post.PublishedAt,
 		metricsJSON,
 		post.PerformanceScore,
+		post.LinkedInURN,
+		post.XPostID,
+		scoresJSON,
+		post.FirstComment,
+		post.CompanyPostURN,
+		post.LinkedInURL,
+		factCheckJSON,
 	)
 
 	if err != nil {
@@ -237,9 +353,11 @@ func (r *PostRepository) Update(ctx context.Context, post *models.Post) error {
 }
 
 func (r *PostRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	defer r.db.changed(ctx, tablePosts)
+
 	query := `UPDATE posts SET status = $2 WHERE id = $1`
 
-	result, err := r.db.Pool.Exec(ctx, query, id, status)
+	result, err := r.db.exec(ctx, query, id, status)
 	if err != nil {
 		return fmt.Errorf("failed to update post status: %w", err)
 	}
@@ -251,10 +369,439 @@ func (r *PostRepository) UpdateStatus(ctx context.Context, id, status string) er
 	return nil
 }
 
+// ScheduleAll schedules each post at its time in one transaction, so
+// either all of them are scheduled or none is.
+func (r *PostRepository) ScheduleAll(ctx context.Context, times map[string]time.Time) error {
+	defer r.db.changed(ctx, tablePosts)
+
+	tx, err := r.db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for id, at := range times {
+		result, err := tx.Exec(ctx, `UPDATE posts SET status = 'scheduled', scheduled_at = $2 WHERE id = $1`, id, at)
+		if err != nil {
+			return fmt.Errorf("failed to schedule post: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("post not found")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit schedule: %w", err)
+	}
+	return nil
+}
+
+// TransitionStatus moves a post from one status to another and reports
+// whether it was still in from, so two people acting on the same post at
+// once can't both succeed.
+func (r *PostRepository) TransitionStatus(ctx context.Context, id, from, to string) (bool, error) {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET status = $3 WHERE id = $1 AND status = $2`
+
+	result, err := r.db.exec(ctx, query, id, from, to)
+	if err != nil {
+		return false, fmt.Errorf("failed to update post status: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// ReleaseHold puts a post held as a duplicate back on the schedule, allowed
+// to publish despite the match. It reports whether the post was on hold.
+func (r *PostRepository) ReleaseHold(ctx context.Context, id string) (bool, error) {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET status = 'scheduled', allow_duplicate = TRUE WHERE id = $1 AND status = $2`
+
+	result, err := r.db.exec(ctx, query, id, models.StatusOnHold)
+	if err != nil {
+		return false, fmt.Errorf("failed to release held post: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *PostRepository) UpdateReview(ctx context.Context, id, status, reviewerID string) error {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET status = $2, reviewed_by = $3, reviewed_at = $4 WHERE id = $1`
+
+	result, err := r.db.exec(ctx, query, id, status, reviewerID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update post review: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found")
+	}
+
+	return nil
+}
+
+// UpdateMetrics stores the latest metrics of a published post. The first
+// metrics synced once it has been out models.SettleDays are also kept as
+// its settled metrics.
+func (r *PostRepository) UpdateMetrics(ctx context.Context, id string, metrics map[string]int, score float64) error {
+	defer r.db.changed(ctx, tablePosts)
+
+	metricsJSON, err := json.Marshal(metrics)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+
+	query := `
+		UPDATE posts
+		SET metrics = $2, performance_score = $3, metrics_synced_at = $4,
+		    settled_metrics = CASE WHEN settled_score IS NULL AND published_at <= $5 THEN $2 ELSE settled_metrics END,
+		    settled_score = CASE WHEN settled_score IS NULL AND published_at <= $5 THEN $3 ELSE settled_score END
+		WHERE id = $1
+	`
+
+	now := time.Now()
+	if _, err := r.db.Pool.Exec(ctx, query, id, metricsJSON, score, now, now.AddDate(0, 0, -models.SettleDays)); err != nil {
+		return fmt.Errorf("failed to update post metrics: %w", err)
+	}
+
+	return nil
+}
+
+// SetImage attaches a generated image to a post. path may be empty when
+// only a concept was produced.
+func (r *PostRepository) SetImage(ctx context.Context, id, concept, path, altText string) error {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET image_concept = $2, image_path = NULLIF($3, ''), image_alt_text = NULLIF($4, '') WHERE id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, concept, path, altText)
+	if err != nil {
+		return fmt.Errorf("failed to update post image: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found")
+	}
+
+	return nil
+}
+
+// SetDocument records where a carousel post's rendered PDF is stored.
+func (r *PostRepository) SetDocument(ctx context.Context, id, path string) error {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET document_path = NULLIF($2, '') WHERE id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, path)
+	if err != nil {
+		return fmt.Errorf("failed to update post document: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found")
+	}
+
+	return nil
+}
+
+// MarkStale moves drafts nobody has touched since olderThan to the stale
+// status. Keeping a stale draft records a review, which restarts its clock.
+func (r *PostRepository) MarkStale(ctx context.Context, olderThan time.Time) (int, error) {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET status = 'stale' WHERE status = 'draft' AND deleted_at IS NULL AND COALESCE(reviewed_at, created_at) < $1`
+
+	result, err := r.db.exec(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark stale drafts: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// SetTargets records which networks a post is published to.
+func (r *PostRepository) SetTargets(ctx context.Context, id string, targets []string) error {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET targets = $2 WHERE id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, targets)
+	if err != nil {
+		return fmt.Errorf("failed to update post targets: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found")
+	}
+
+	return nil
+}
+
+// GetAwaitingImage returns approved or scheduled posts that have not been
+// through the image pipeline yet, oldest first. Carousels carry their own
+// document and polls can't have media, so both are skipped.
+func (r *PostRepository) GetAwaitingImage(ctx context.Context, limit int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status IN ('approved', 'scheduled') AND image_concept IS NULL AND post_type NOT IN ('carousel', 'poll') AND deleted_at IS NULL
+		ORDER BY created_at ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query posts awaiting image: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPosts(rows)
+}
+
+// GetPublishedSince returns posts published after since, best performing
+// first.
+func (r *PostRepository) GetPublishedSince(ctx context.Context, since time.Time) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status = 'published' AND published_at >= $1 AND deleted_at IS NULL
+		ORDER BY performance_score DESC, published_at DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query published posts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPosts(rows)
+}
+
+// GetPublishedBetween returns a page of the posts published in [from, to),
+// newest first. A zero from or to leaves that end open.
+func (r *PostRepository) GetPublishedBetween(ctx context.Context, from, to time.Time, limit, offset int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status = 'published' AND deleted_at IS NULL
+		  AND ($1::timestamp IS NULL OR published_at >= $1)
+		  AND ($2::timestamp IS NULL OR published_at < $2)
+		ORDER BY published_at DESC NULLS LAST, created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, nullTime(from), nullTime(to), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query published posts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPosts(rows)
+}
+
+// LastPublishedByCategory returns when a post drawn from a thought in each
+// category was last published. Categories nothing was published from are
+// left out.
+func (r *PostRepository) LastPublishedByCategory(ctx context.Context) (map[string]time.Time, error) {
+	query := `
+		SELECT t.category, MAX(p.published_at)
+		FROM posts p
+		JOIN thoughts t ON t.id = ANY(p.source_thought_ids)
+		WHERE p.status = 'published' AND p.published_at IS NOT NULL AND p.deleted_at IS NULL
+		GROUP BY t.category
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last published by category: %w", err)
+	}
+	defer rows.Close()
+
+	last := make(map[string]time.Time)
+	for rows.Next() {
+		var category string
+		var publishedAt time.Time
+		if err := rows.Scan(&category, &publishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan last published: %w", err)
+		}
+		last[category] = publishedAt
+	}
+
+	return last, rows.Err()
+}
+
+// GetTopPerforming returns the n published posts with the highest
+// performance score, ignoring posts whose metrics were never synced.
+func (r *PostRepository) GetTopPerforming(ctx context.Context, n int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status = 'published' AND metrics_synced_at IS NOT NULL AND performance_score > 0 AND deleted_at IS NULL
+		ORDER BY performance_score DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top posts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPosts(rows)
+}
+
+// CountByStatus counts all posts per status.
+func (r *PostRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	return cached(ctx, r.db, "posts.count_by_status", func() (map[string]int, error) {
+		rows, err := r.db.Pool.Query(ctx, `SELECT status, COUNT(*) FROM posts WHERE deleted_at IS NULL GROUP BY status`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count posts: %w", err)
+		}
+		defer rows.Close()
+
+		counts := make(map[string]int)
+		for rows.Next() {
+			var status string
+			var count int
+			if err := rows.Scan(&status, &count); err != nil {
+				return nil, fmt.Errorf("failed to scan post count: %w", err)
+			}
+			counts[status] = count
+		}
+
+		return counts, rows.Err()
+	}, tablePosts)
+}
+
+// CountByWeek counts posts published in each of the last weeks weeks,
+// oldest first, including empty weeks.
+func (r *PostRepository) CountByWeek(ctx context.Context, weeks int) ([]WeekCount, error) {
+	return cached(ctx, r.db, fmt.Sprintf("posts.count_by_week:%d", weeks), func() ([]WeekCount, error) {
+		query := `
+			SELECT w.week, COUNT(p.id)
+			FROM generate_series(
+				date_trunc('week', LOCALTIMESTAMP) - ($1 - 1) * INTERVAL '1 week',
+				date_trunc('week', LOCALTIMESTAMP),
+				INTERVAL '1 week'
+			) AS w(week)
+			LEFT JOIN posts p ON p.status = 'published' AND p.deleted_at IS NULL AND date_trunc('week', p.published_at) = w.week
+			GROUP BY w.week
+			ORDER BY w.week
+		`
+
+		rows, err := r.db.Pool.Query(ctx, query, weeks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count posts by week: %w", err)
+		}
+		defer rows.Close()
+
+		return scanWeekCounts(rows)
+	}, tablePosts)
+}
+
+// AverageTimeToPublish is the mean time between the earliest source thought
+// of a published post and its publication. It returns zero when no post
+// qualifies.
+func (r *PostRepository) AverageTimeToPublish(ctx context.Context) (time.Duration, error) {
+	return cached(ctx, r.db, "posts.average_time_to_publish", func() (time.Duration, error) {
+		query := `
+			SELECT AVG(EXTRACT(EPOCH FROM p.published_at - t.first_captured))
+			FROM posts p
+			JOIN LATERAL (
+				SELECT MIN(timestamp) AS first_captured FROM thoughts WHERE id = ANY(p.source_thought_ids)
+			) t ON t.first_captured IS NOT NULL
+			WHERE p.status = 'published' AND p.published_at IS NOT NULL AND p.deleted_at IS NULL
+		`
+
+		var seconds *float64
+		if err := r.db.Pool.QueryRow(ctx, query).Scan(&seconds); err != nil {
+			return 0, fmt.Errorf("failed to compute time to publish: %w", err)
+		}
+		if seconds == nil {
+			return 0, nil
+		}
+
+		return time.Duration(*seconds * float64(time.Second)), nil
+	}, tablePosts, tableThoughts)
+}
+
+// The embedding queries below require EnableEmbeddings to have run.
+
+func (r *PostRepository) UpdateEmbedding(ctx context.Context, id string, embedding []float32) error {
+	query := `UPDATE posts SET embedding = $2::vector WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, vectorLiteral(embedding)); err != nil {
+		return fmt.Errorf("failed to update post embedding: %w", err)
+	}
+
+	return nil
+}
+
+// GetPublishedWithoutEmbedding returns published posts that haven't been
+// embedded yet, oldest first.
+func (r *PostRepository) GetPublishedWithoutEmbedding(ctx context.Context, limit int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status = 'published' AND embedding IS NULL AND deleted_at IS NULL
+		ORDER BY published_at ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query published posts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPosts(rows)
+}
+
+// FindSimilarPublished returns the published posts closest to embedding by
+// cosine similarity, most similar first, skipping excludeID.
+func (r *PostRepository) FindSimilarPublished(ctx context.Context, embedding []float32, excludeID string, limit int) ([]*SimilarPost, error) {
+	query := `
+		SELECT ` + postColumns + `, 1 - (embedding <=> $1::vector) AS similarity
+		FROM posts
+		WHERE status = 'published' AND deleted_at IS NULL
+		  AND embedding IS NOT NULL
+		  AND vector_dims(embedding) = vector_dims($1::vector)
+		  AND id::text <> $2
+		ORDER BY embedding <=> $1::vector
+		LIMIT $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, vectorLiteral(embedding), excludeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query similar posts: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []*SimilarPost
+	for rows.Next() {
+		var similarity float64
+		post, err := scanPost(rows, &similarity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		matches = append(matches, &SimilarPost{Post: post, Similarity: similarity})
+	}
+
+	return matches, rows.Err()
+}
+
+// Delete moves a post to the trash, where every other query stops seeing
+// it until Restore brings it back or PurgeDeleted removes it.
 func (r *PostRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM posts WHERE id = $1`
+	defer r.db.changed(ctx, tablePosts)
 
-	result, err := r.db.Pool.Exec(ctx, query, id)
+	query := `UPDATE posts SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to delete post: %w", err)
 	}
@@ -264,4 +811,188 @@ func (r *PostRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ListDeleted returns the posts in the trash, most recently deleted first.
+func (r *PostRepository) ListDeleted(ctx context.Context) ([]*DeletedPost, error) {
+	query := `
+		SELECT ` + postColumns + `, deleted_at
+		FROM posts
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted posts: %w", err)
+	}
+	defer rows.Close()
+
+	var deleted []*DeletedPost
+	for rows.Next() {
+		var deletedAt time.Time
+		post, err := scanPost(rows, &deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		deleted = append(deleted, &DeletedPost{Post: post, DeletedAt: deletedAt})
+	}
+
+	return deleted, rows.Err()
+}
+
+// Restore takes a post out of the trash with the status it was deleted in.
+func (r *PostRepository) Restore(ctx context.Context, id string) error {
+	defer r.db.changed(ctx, tablePosts)
+
+	query := `UPDATE posts SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore post: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found in trash")
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently deletes posts moved to the trash before
+// olderThan.
+func (r *PostRepository) PurgeDeleted(ctx context.Context, olderThan time.Time) (int, error) {
+	query := `DELETE FROM posts WHERE deleted_at < $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted posts: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// scorePost scores the current content of post, so the stored scores
+// always describe the text that was approved and published.
+func scorePost(post *models.Post) ([]byte, error) {
+	post.Scores = models.ScoreContent(post.Content)
+
+	scoresJSON, err := json.Marshal(post.Scores)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scores: %w", err)
+	}
+	return scoresJSON, nil
+}
+
+// marshalFactCheck returns the fact check of post as JSON, or nil when the
+// post wasn't checked.
+func marshalFactCheck(post *models.Post) ([]byte, error) {
+	if post.FactCheck == nil {
+		return nil, nil
+	}
+
+	factCheckJSON, err := json.Marshal(post.FactCheck)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal fact check: %w", err)
+	}
+	return factCheckJSON, nil
+}
+
+func scanPost(row pgx.Row, extra ...any) (*models.Post, error) {
+	post := &models.Post{}
+	var metricsJSON, pollJSON, scoresJSON, provenanceJSON, factCheckJSON, settledJSON []byte
+
+	dest := []any{
+		&post.ID,
+		&post.Content,
+		&post.Status,
+		&post.SourceThoughtIDs,
+		&post.BrainstormSessionID,
+		&post.ParentPostID,
+		&post.PostType,
+		&post.Tone,
+		&post.CreatedAt,
+		&post.ScheduledAt,
+		&post.PublishedAt,
+		&metricsJSON,
+		&post.PerformanceScore,
+		&post.ReviewedBy,
+		&post.ReviewedAt,
+		&post.LinkedInURN,
+		&post.MetricsSyncedAt,
+		&post.ImageConcept,
+		&post.ImagePath,
+		&post.ImageAltText,
+		&post.Slides,
+		&post.DocumentPath,
+		&pollJSON,
+		&post.Targets,
+		&post.XPostID,
+		&post.AllowDuplicate,
+		&scoresJSON,
+		&post.ExperimentID,
+		&post.FirstComment,
+		&post.CompanyPostURN,
+		&post.LinkedInURL,
+		&post.Language,
+		&post.TranslationOf,
+		&provenanceJSON,
+		&factCheckJSON,
+		&settledJSON,
+		&post.SettledScore,
+		&post.JobID,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(metricsJSON, &post.Metrics); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metrics: %w", err)
+	}
+
+	if pollJSON != nil {
+		if err := json.Unmarshal(pollJSON, &post.Poll); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal poll: %w", err)
+		}
+	}
+
+	if scoresJSON != nil {
+		if err := json.Unmarshal(scoresJSON, &post.Scores); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal scores: %w", err)
+		}
+	}
+
+	if provenanceJSON != nil {
+		if err := json.Unmarshal(provenanceJSON, &post.Provenance); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal provenance: %w", err)
+		}
+	}
+
+	if factCheckJSON != nil {
+		if err := json.Unmarshal(factCheckJSON, &post.FactCheck); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal fact check: %w", err)
+		}
+	}
+
+	if settledJSON != nil {
+		if err := json.Unmarshal(settledJSON, &post.SettledMetrics); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal settled metrics: %w", err)
+		}
+	}
+
+	return post, nil
+}
+
+func scanPosts(rows pgx.Rows) ([]*models.Post, error) {
+	var posts []*models.Post
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/cache"
 )
 
 type DB struct {
 	Pool *pgxpool.Pool
+	// Cache keeps hot query results; nil disables it.
+	Cache *cache.Cache
 }
 
 func NewDB(databaseURL string) (*DB, error) {
@@ -19,6 +24,7 @@ func NewDB(databaseURL string) (*DB, error) {
 
 	config.MaxConns = 25
 	config.MinConns = 5
+	config.ConnConfig.Tracer = multitracer.New(newQueryLogger(), otelpgx.NewTracer())
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
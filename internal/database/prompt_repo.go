@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PromptRepository struct {
+	db *DB
+}
+
+func NewPromptRepository(db *DB) *PromptRepository {
+	return &PromptRepository{db: db}
+}
+
+// Get returns the override for a prompt, or an empty string without an
+// error when there is none.
+func (r *PromptRepository) Get(ctx context.Context, name string) (string, error) {
+	var template string
+	err := r.db.Pool.QueryRow(ctx, `SELECT template FROM prompts WHERE name = $1`, name).Scan(&template)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	return template, nil
+}
+
+func (r *PromptRepository) Set(ctx context.Context, name, template, updatedBy string) error {
+	query := `
+		INSERT INTO prompts (name, template, updated_by, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		ON CONFLICT (name) DO UPDATE
+		SET template = EXCLUDED.template, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, name, template, updatedBy, time.Now()); err != nil {
+		return fmt.Errorf("failed to save prompt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PromptRepository) Delete(ctx context.Context, name string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM prompts WHERE name = $1`, name); err != nil {
+		return fmt.Errorf("failed to delete prompt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/tracelog"
+)
+
+// newQueryLogger reports failed queries through slog, tagged with the
+// request ID of the context they ran under. Query arguments are left out
+// because they carry thought and post content.
+func newQueryLogger() *tracelog.TraceLog {
+	return &tracelog.TraceLog{
+		Logger:   tracelog.LoggerFunc(logQuery),
+		LogLevel: tracelog.LogLevelError,
+	}
+}
+
+func logQuery(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]any) {
+	attrs := []any{"operation", msg}
+	if sql, ok := data["sql"].(string); ok {
+		attrs = append(attrs, "sql", sql)
+	}
+	if err, ok := data["err"].(error); ok {
+		attrs = append(attrs, "error", err)
+	}
+	if duration, ok := data["time"].(time.Duration); ok {
+		attrs = append(attrs, "duration_ms", duration.Milliseconds())
+	}
+
+	slog.Log(ctx, slogLevel(level), "Database query failed", attrs...)
+}
+
+func slogLevel(level tracelog.LogLevel) slog.Level {
+	switch level {
+	case tracelog.LogLevelError:
+		return slog.LevelError
+	case tracelog.LogLevelWarn:
+		return slog.LevelWarn
+	case tracelog.LogLevelInfo:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type RevisionRepository struct {
+	db *DB
+}
+
+func NewRevisionRepository(db *DB) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+// Record stores the content a post now has, who changed it and why.
+func (r *RevisionRepository) Record(ctx context.Context, postID, content, source, editor, note string) error {
+	query := `
+		INSERT INTO post_revisions (id, post_id, content, source, editor, note, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, uuid.New().String(), postID, content, source, editor, note, time.Now()); err != nil {
+		return fmt.Errorf("failed to record post revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetHistory returns the revisions of a post and of the drafts it was
+// revised from, oldest first.
+func (r *RevisionRepository) GetHistory(ctx context.Context, postID string) ([]*models.PostRevision, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_post_id FROM posts WHERE id = $1
+			UNION ALL
+			SELECT p.id, p.parent_post_id FROM posts p JOIN chain c ON p.id = c.parent_post_id
+		)
+		SELECT id, post_id, content, source, COALESCE(editor, ''), COALESCE(note, ''), created_at
+		FROM post_revisions
+		WHERE post_id IN (SELECT id FROM chain)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query post revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []*models.PostRevision
+	for rows.Next() {
+		revision := &models.PostRevision{}
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.PostID,
+			&revision.Content,
+			&revision.Source,
+			&revision.Editor,
+			&revision.Note,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan post revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
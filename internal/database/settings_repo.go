@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SettingsRepository struct {
+	db *DB
+}
+
+func NewSettingsRepository(db *DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// Get returns an empty value without an error when the setting is unset.
+func (r *SettingsRepository) Get(ctx context.Context, key string) (string, error) {
+	var value string
+	err := r.db.Pool.QueryRow(ctx, `SELECT value FROM settings WHERE key = $1`, key).Scan(&value)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get setting: %w", err)
+	}
+
+	return value, nil
+}
+
+func (r *SettingsRepository) Set(ctx context.Context, key, value, updatedBy string) error {
+	query := `
+		INSERT INTO settings (key, value, updated_by, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		ON CONFLICT (key) DO UPDATE
+		SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, key, value, updatedBy, time.Now()); err != nil {
+		return fmt.Errorf("failed to save setting: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SettingsRepository) Delete(ctx context.Context, key string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM settings WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to delete setting: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+type StyleRepository struct {
+	db *DB
+}
+
+func NewStyleRepository(db *DB) *StyleRepository {
+	return &StyleRepository{db: db}
+}
+
+func (r *StyleRepository) Save(ctx context.Context, profile *models.StyleProfile) error {
+	if profile.ID == "" {
+		profile.ID = uuid.New().String()
+	}
+	profile.LastUpdated = time.Now()
+
+	patternsJSON, err := json.Marshal(profile.StylePatterns)
+	if err != nil {
+		return fmt.Errorf("failed to marshal style patterns: %w", err)
+	}
+
+	toneJSON, err := json.Marshal(profile.TonePreferences)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tone preferences: %w", err)
+	}
+
+	elementsJSON, err := json.Marshal(profile.HighPerformingElements)
+	if err != nil {
+		return fmt.Errorf("failed to marshal high performing elements: %w", err)
+	}
+
+	samplesJSON, err := json.Marshal(profile.SamplePosts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sample posts: %w", err)
+	}
+
+	query := `
+		INSERT INTO writing_style_profile (id, user_id, style_patterns, tone_preferences,
+		                                   high_performing_elements, sample_posts, last_updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE
+		SET style_patterns = EXCLUDED.style_patterns,
+		    tone_preferences = EXCLUDED.tone_preferences,
+		    high_performing_elements = EXCLUDED.high_performing_elements,
+		    sample_posts = EXCLUDED.sample_posts,
+		    last_updated = EXCLUDED.last_updated
+	`
+
+	_, err = r.db.Pool.Exec(ctx, query,
+		profile.ID,
+		profile.UserID,
+		patternsJSON,
+		toneJSON,
+		elementsJSON,
+		samplesJSON,
+		profile.LastUpdated,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save style profile: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID returns nil without an error when the user has no profile yet.
+func (r *StyleRepository) GetByUserID(ctx context.Context, userID string) (*models.StyleProfile, error) {
+	query := `
+		SELECT id, user_id, style_patterns, tone_preferences,
+		       high_performing_elements, sample_posts, last_updated
+		FROM writing_style_profile
+		WHERE user_id = $1
+	`
+
+	profile := &models.StyleProfile{}
+	var patternsJSON, toneJSON, elementsJSON, samplesJSON []byte
+
+	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(
+		&profile.ID,
+		&profile.UserID,
+		&patternsJSON,
+		&toneJSON,
+		&elementsJSON,
+		&samplesJSON,
+		&profile.LastUpdated,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get style profile: %w", err)
+	}
+
+	fields := []struct {
+		data []byte
+		dest interface{}
+	}{
+		{patternsJSON, &profile.StylePatterns},
+		{toneJSON, &profile.TonePreferences},
+		{elementsJSON, &profile.HighPerformingElements},
+		{samplesJSON, &profile.SamplePosts},
+	}
+
+	for _, field := range fields {
+		if len(field.data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(field.data, field.dest); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal style profile: %w", err)
+		}
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var ErrTemplateNotFound = errors.New("post template not found")
+
+const templateColumns = `name, COALESCE(description, ''), skeleton, COALESCE(created_by, ''), created_at, updated_at`
+
+type TemplateRepository struct {
+	db *DB
+}
+
+func NewTemplateRepository(db *DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+func (r *TemplateRepository) List(ctx context.Context) ([]*models.PostTemplate, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT `+templateColumns+` FROM post_templates ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list post templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*models.PostTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, rows.Err()
+}
+
+func (r *TemplateRepository) Get(ctx context.Context, name string) (*models.PostTemplate, error) {
+	template, err := scanTemplate(r.db.Pool.QueryRow(ctx, `SELECT `+templateColumns+` FROM post_templates WHERE name = $1`, name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post template: %w", err)
+	}
+
+	return template, nil
+}
+
+// Save adds a template, or replaces the description and skeleton of the
+// one with its name, reporting whether it was new.
+func (r *TemplateRepository) Save(ctx context.Context, template *models.PostTemplate) (bool, error) {
+	query := `
+		INSERT INTO post_templates (name, description, skeleton, created_by, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, ''), $5, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			description = EXCLUDED.description,
+			skeleton = EXCLUDED.skeleton,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at = updated_at
+	`
+
+	var created bool
+	if err := r.db.Pool.QueryRow(ctx, query, template.Name, template.Description, template.Skeleton,
+		template.CreatedBy, template.CreatedAt, template.UpdatedAt).Scan(&created); err != nil {
+		return false, fmt.Errorf("failed to save post template: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *TemplateRepository) Delete(ctx context.Context, name string) error {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM post_templates WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete post template: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+func scanTemplate(row pgx.Row) (*models.PostTemplate, error) {
+	template := &models.PostTemplate{}
+	err := row.Scan(&template.Name, &template.Description, &template.Skeleton, &template.CreatedBy,
+		&template.CreatedAt, &template.UpdatedAt)
+	return template, err
+}
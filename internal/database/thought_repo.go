@@ -2,13 +2,57 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
 )
 
+const thoughtColumns = `id, source, content, category, topic_tags, status, timestamp, related_thoughts,
+		       COALESCE(slack_channel_id, ''), COALESCE(slack_thread_ts, ''), COALESCE(external_id, ''), used_by_post_id,
+		       COALESCE(source_url, ''), COALESCE(source_title, '')`
+
+// workspaceFilter limits thoughts to the workspace of the channel bound to
+// parameter $arg. A channel always sees its own thoughts. Shared channels
+// also see every other shared channel's thoughts and thoughts without a
+// channel, such as Linear webhook issues; an empty channel ID selects just
+// that shared pool.
+func workspaceFilter(arg int) string {
+	p := fmt.Sprintf("$%d::text", arg)
+	return `(slack_channel_id = ` + p + ` OR (
+		(` + p + ` = '' OR EXISTS (SELECT 1 FROM channel_workspaces WHERE channel_id = ` + p + ` AND shared))
+		AND (slack_channel_id IS NULL OR slack_channel_id IN (SELECT channel_id FROM channel_workspaces WHERE shared))))`
+}
+
+// WeekCount is the number of records in the week starting at Week.
+type WeekCount struct {
+	Week  time.Time
+	Count int
+}
+
+type SimilarThought struct {
+	Thought    *models.Thought
+	Similarity float64
+}
+
+// DeletedThought is a thought in the trash.
+type DeletedThought struct {
+	Thought   *models.Thought
+	DeletedAt time.Time
+}
+
+// CapturedThought is a thought with the time it was saved, which for
+// imported notes differs from its timestamp.
+type CapturedThought struct {
+	Thought    *models.Thought
+	CapturedAt time.Time
+}
+
 type ThoughtRepository struct {
 	db *DB
 }
@@ -18,6 +62,8 @@ func NewThoughtRepository(db *DB) *ThoughtRepository {
 }
 
 func (r *ThoughtRepository) Create(ctx context.Context, thought *models.Thought) error {
+	defer r.db.changed(ctx, tableThoughts)
+
 	if thought.ID == "" {
 		thought.ID = uuid.New().String()
 	}
@@ -27,8 +73,10 @@ func (r *ThoughtRepository) Create(ctx context.Context, thought *models.Thought)
 	}
 
 	query := `
-		INSERT INTO thoughts (id, source, content, category, topic_tags, status, timestamp, related_thoughts)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO thoughts (id, source, content, category, topic_tags, status, timestamp, related_thoughts,
+		                      slack_channel_id, slack_thread_ts, external_id, source_url, source_title)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), NULLIF($10, ''), NULLIF($11, ''),
+		        NULLIF($12, ''), NULLIF($13, ''))
 	`
 
 	_, err := r.db.Pool.Exec(ctx, query,
@@ -40,6 +88,11 @@ func (r *ThoughtRepository) Create(ctx context.Context, thought *models.Thought)
 		thought.Status,
 		thought.Timestamp,
 		thought.RelatedThoughts,
+		thought.SlackChannelID,
+		thought.SlackThreadTS,
+		thought.ExternalID,
+		thought.SourceURL,
+		thought.SourceTitle,
 	)
 
 	if err != nil {
@@ -51,23 +104,12 @@ func (r *ThoughtRepository) Create(ctx context.Context, thought *models.Thought)
 
 func (r *ThoughtRepository) GetByID(ctx context.Context, id string) (*models.Thought, error) {
 	query := `
-		SELECT id, source, content, category, topic_tags, status, timestamp, related_thoughts
+		SELECT ` + thoughtColumns + `
 		FROM thoughts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	thought := &models.Thought{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&thought.ID,
-		&thought.Source,
-		&thought.Content,
-		&thought.Category,
-		&thought.TopicTags,
-		&thought.Status,
-		&thought.Timestamp,
-		&thought.RelatedThoughts,
-	)
-
+	thought, err := scanThought(r.db.Pool.QueryRow(ctx, query, id))
 	if err != nil {
 		return nil, fmt.Errorf("thought not found: %w", err)
 	}
@@ -75,114 +117,138 @@ func (r *ThoughtRepository) GetByID(ctx context.Context, id string) (*models.Tho
 	return thought, nil
 }
 
-func (r *ThoughtRepository) GetAll(ctx context.Context) ([]*models.Thought, error) {
+// GetBySlackThread returns nil without an error when no thought was captured
+// from the thread's root message.
+func (r *ThoughtRepository) GetBySlackThread(ctx context.Context, channelID, threadTS string) (*models.Thought, error) {
 	query := `
-		SELECT id, source, content, category, topic_tags, status, timestamp, related_thoughts
+		SELECT ` + thoughtColumns + `
 		FROM thoughts
-		ORDER BY timestamp DESC
+		WHERE slack_channel_id = $1 AND slack_thread_ts = $2 AND deleted_at IS NULL
+		ORDER BY timestamp ASC
+		LIMIT 1
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	thought, err := scanThought(r.db.Pool.QueryRow(ctx, query, channelID, threadTS))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to query thoughts: %w", err)
+		return nil, fmt.Errorf("failed to get thought by thread: %w", err)
 	}
-	defer rows.Close()
 
-	var thoughts []*models.Thought
-	for rows.Next() {
-		thought := &models.Thought{}
-		err := rows.Scan(
-			&thought.ID,
-			&thought.Source,
-			&thought.Content,
-			&thought.Category,
-			&thought.TopicTags,
-			&thought.Status,
-			&thought.Timestamp,
-			&thought.RelatedThoughts,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan thought: %w", err)
-		}
-		thoughts = append(thoughts, thought)
+	return thought, nil
+}
+
+func (r *ThoughtRepository) ExistsByExternalID(ctx context.Context, source, externalID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM thoughts WHERE source = $1 AND external_id = $2)`
+
+	var exists bool
+	if err := r.db.Pool.QueryRow(ctx, query, source, externalID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check thought: %w", err)
 	}
 
-	return thoughts, nil
+	return exists, nil
+}
+
+// GetByExternalID returns nil without an error when no thought was imported
+// with externalID from source. Like ExistsByExternalID it also finds
+// thoughts in the trash, so a re-import never brings a deleted one back.
+func (r *ThoughtRepository) GetByExternalID(ctx context.Context, source, externalID string) (*models.Thought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts
+		WHERE source = $1 AND external_id = $2
+	`
+
+	thought, err := scanThought(r.db.Pool.QueryRow(ctx, query, source, externalID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thought by external ID: %w", err)
+	}
+
+	return thought, nil
 }
 
-func (r *ThoughtRepository) GetByStatus(ctx context.Context, status string) ([]*models.Thought, error) {
+// GetAll returns a page of thoughts, newest first.
+func (r *ThoughtRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.Thought, error) {
 	query := `
-		SELECT id, source, content, category, topic_tags, status, timestamp, related_thoughts
+		SELECT ` + thoughtColumns + `
 		FROM thoughts
-		WHERE status = $1
+		WHERE deleted_at IS NULL
 		ORDER BY timestamp DESC
+		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, status)
+	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query thoughts: %w", err)
 	}
 	defer rows.Close()
 
-	var thoughts []*models.Thought
-	for rows.Next() {
-		thought := &models.Thought{}
-		err := rows.Scan(
-			&thought.ID,
-			&thought.Source,
-			&thought.Content,
-			&thought.Category,
-			&thought.TopicTags,
-			&thought.Status,
-			&thought.Timestamp,
-			&thought.RelatedThoughts,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan thought: %w", err)
-		}
-		thoughts = append(thoughts, thought)
+	return scanThoughts(rows)
+}
+
+// GetByWorkspace returns a page of the thoughts visible from channelID,
+// newest first.
+func (r *ThoughtRepository) GetByWorkspace(ctx context.Context, channelID string, limit, offset int) ([]*models.Thought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts
+		WHERE deleted_at IS NULL AND ` + workspaceFilter(1) + `
+		ORDER BY timestamp DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, channelID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query thoughts: %w", err)
 	}
+	defer rows.Close()
 
-	return thoughts, nil
+	return scanThoughts(rows)
 }
 
-func (r *ThoughtRepository) GetByCategory(ctx context.Context, category string) ([]*models.Thought, error) {
+func (r *ThoughtRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.Thought, error) {
 	query := `
-		SELECT id, source, content, category, topic_tags, status, timestamp, related_thoughts
+		SELECT ` + thoughtColumns + `
 		FROM thoughts
-		WHERE category = $1
+		WHERE status = $1 AND deleted_at IS NULL
 		ORDER BY timestamp DESC
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, category)
+	rows, err := r.db.Pool.Query(ctx, query, status, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query thoughts: %w", err)
 	}
 	defer rows.Close()
 
-	var thoughts []*models.Thought
-	for rows.Next() {
-		thought := &models.Thought{}
-		err := rows.Scan(
-			&thought.ID,
-			&thought.Source,
-			&thought.Content,
-			&thought.Category,
-			&thought.TopicTags,
-			&thought.Status,
-			&thought.Timestamp,
-			&thought.RelatedThoughts,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan thought: %w", err)
-		}
-		thoughts = append(thoughts, thought)
+	return scanThoughts(rows)
+}
+
+func (r *ThoughtRepository) GetByCategory(ctx context.Context, category string, limit, offset int) ([]*models.Thought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts
+		WHERE category = $1 AND deleted_at IS NULL
+		ORDER BY timestamp DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, category, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query thoughts: %w", err)
 	}
+	defer rows.Close()
 
-	return thoughts, nil
+	return scanThoughts(rows)
 }
 
 func (r *ThoughtRepository) Update(ctx context.Context, thought *models.Thought) error {
+	defer r.db.changed(ctx, tableThoughts)
+
 	query := `
 		UPDATE thoughts
 		SET source = $2, content = $3, category = $4, topic_tags = $5, 
@@ -211,7 +277,271 @@ func (r *ThoughtRepository) Update(ctx context.Context, thought *models.Thought)
 	return nil
 }
 
+func (r *ThoughtRepository) AppendContext(ctx context.Context, id, additionalContext string) error {
+	query := `UPDATE thoughts SET content = content || $2 WHERE id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, "\n\n"+additionalContext)
+	if err != nil {
+		return fmt.Errorf("failed to append thought context: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("thought not found")
+	}
+
+	return nil
+}
+
+// GetByIDs returns the given thoughts that belong to the workspace of
+// channelID.
+func (r *ThoughtRepository) GetByIDs(ctx context.Context, channelID string, ids []string) ([]*models.Thought, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts
+		WHERE id = ANY($1) AND deleted_at IS NULL AND ` + workspaceFilter(2) + `
+		ORDER BY timestamp DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, ids, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanThoughts(rows)
+}
+
+func (r *ThoughtRepository) SetRelated(ctx context.Context, id string, relatedIDs []string) error {
+	query := `UPDATE thoughts SET related_thoughts = $2 WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, relatedIDs); err != nil {
+		return fmt.Errorf("failed to update related thoughts: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ThoughtRepository) AddRelated(ctx context.Context, id, relatedID string) error {
+	query := `
+		UPDATE thoughts
+		SET related_thoughts = array_append(COALESCE(related_thoughts, '{}'), $2::uuid)
+		WHERE id = $1 AND NOT ($2::uuid = ANY(COALESCE(related_thoughts, '{}')))
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, relatedID); err != nil {
+		return fmt.Errorf("failed to add related thought: %w", err)
+	}
+
+	return nil
+}
+
+// SearchByTagOrKeyword matches a topic against category, topic tags and
+// content, case-insensitively. Unused thoughts come first, then category and
+// tag hits. Only thoughts in the workspace of channelID are searched, and at
+// most limit are returned.
+func (r *ThoughtRepository) SearchByTagOrKeyword(ctx context.Context, channelID, topic string, limit int) ([]*models.Thought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts
+		WHERE (LOWER(category) = LOWER($1)
+		   OR EXISTS (SELECT 1 FROM unnest(topic_tags) AS tag WHERE LOWER(tag) = LOWER($1))
+		   OR content ILIKE '%' || $1 || '%')
+		  AND deleted_at IS NULL AND ` + workspaceFilter(2) + `
+		ORDER BY
+			status = 'used',
+			CASE
+				WHEN LOWER(category) = LOWER($1) THEN 0
+				WHEN EXISTS (SELECT 1 FROM unnest(topic_tags) AS tag WHERE LOWER(tag) = LOWER($1)) THEN 1
+				ELSE 2
+			END,
+			timestamp DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, topic, channelID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanThoughts(rows)
+}
+
+// SearchText ranks thoughts by full-text relevance to query, also matching
+// plain substrings so short words and names missed by stemming still hit.
+func (r *ThoughtRepository) SearchText(ctx context.Context, channelID, query string, limit int) ([]*models.Thought, error) {
+	sqlQuery := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts
+		WHERE (to_tsvector('english', content) @@ plainto_tsquery('english', $1)
+		   OR content ILIKE '%' || $1 || '%')
+		  AND deleted_at IS NULL AND ` + workspaceFilter(3) + `
+		ORDER BY ts_rank(to_tsvector('english', content), plainto_tsquery('english', $1)) DESC, timestamp DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, query, limit, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanThoughts(rows)
+}
+
+// The embedding queries below require EnableEmbeddings to have run.
+
+func (r *ThoughtRepository) UpdateEmbedding(ctx context.Context, id string, embedding []float32) error {
+	query := `UPDATE thoughts SET embedding = $2::vector WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, vectorLiteral(embedding)); err != nil {
+		return fmt.Errorf("failed to update thought embedding: %w", err)
+	}
+
+	return nil
+}
+
+// FindSimilar returns the thoughts closest to embedding by cosine
+// similarity within the workspace of channelID, most similar first,
+// skipping excludeID.
+func (r *ThoughtRepository) FindSimilar(ctx context.Context, embedding []float32, channelID, excludeID string, limit int) ([]*SimilarThought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `, 1 - (embedding <=> $1::vector) AS similarity
+		FROM thoughts
+		WHERE embedding IS NOT NULL
+		  AND vector_dims(embedding) = vector_dims($1::vector)
+		  AND id::text <> $2
+		  AND deleted_at IS NULL AND ` + workspaceFilter(4) + `
+		ORDER BY embedding <=> $1::vector
+		LIMIT $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, vectorLiteral(embedding), excludeID, limit, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query similar thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []*SimilarThought
+	for rows.Next() {
+		var similarity float64
+		thought, err := scanThought(rows, &similarity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan thought: %w", err)
+		}
+		matches = append(matches, &SimilarThought{Thought: thought, Similarity: similarity})
+	}
+
+	return matches, rows.Err()
+}
+
+func (r *ThoughtRepository) GetWithoutEmbedding(ctx context.Context, limit int) ([]*models.Thought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts
+		WHERE embedding IS NULL AND deleted_at IS NULL
+		ORDER BY timestamp ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanThoughts(rows)
+}
+
+// GetCapturedAfter returns up to limit thoughts saved after since, oldest
+// first, whatever their workspace.
+func (r *ThoughtRepository) GetCapturedAfter(ctx context.Context, since time.Time, limit int) ([]*CapturedThought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `, created_at
+		FROM thoughts
+		WHERE created_at > $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query captured thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	var captured []*CapturedThought
+	for rows.Next() {
+		var capturedAt time.Time
+		thought, err := scanThought(rows, &capturedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan thought: %w", err)
+		}
+		captured = append(captured, &CapturedThought{Thought: thought, CapturedAt: capturedAt})
+	}
+
+	return captured, rows.Err()
+}
+
+// MarkUsed moves thoughts consumed by an approved post to the used status
+// and records which post used them.
+func (r *ThoughtRepository) MarkUsed(ctx context.Context, ids []string, postID string) error {
+	defer r.db.changed(ctx, tableThoughts)
+
+	query := `UPDATE thoughts SET status = 'used', used_by_post_id = $2 WHERE id = ANY($1)`
+
+	if _, err := r.db.Pool.Exec(ctx, query, ids, postID); err != nil {
+		return fmt.Errorf("failed to mark thoughts used: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseUsed puts the thoughts a post used back to raw, for when its
+// approval is undone.
+func (r *ThoughtRepository) ReleaseUsed(ctx context.Context, postID string) error {
+	defer r.db.changed(ctx, tableThoughts)
+
+	query := `UPDATE thoughts SET status = 'raw', used_by_post_id = NULL WHERE used_by_post_id = $1 AND status = 'used'`
+
+	if _, err := r.db.Pool.Exec(ctx, query, postID); err != nil {
+		return fmt.Errorf("failed to release thoughts: %w", err)
+	}
+
+	return nil
+}
+
+// GetUnused returns raw thoughts, newest first, with thoughts that already
+// back a pending draft pushed to the end. At most limit thoughts from the
+// workspace of channelID are returned.
+func (r *ThoughtRepository) GetUnused(ctx context.Context, channelID string, limit int) ([]*models.Thought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts t
+		WHERE status = 'raw' AND deleted_at IS NULL AND ` + workspaceFilter(1) + `
+		ORDER BY EXISTS (
+			SELECT 1 FROM posts p
+			WHERE p.status = 'draft' AND p.deleted_at IS NULL AND t.id = ANY(p.source_thought_ids)
+		), timestamp DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, channelID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanThoughts(rows)
+}
+
 func (r *ThoughtRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	defer r.db.changed(ctx, tableThoughts)
+
 	query := `UPDATE thoughts SET status = $2 WHERE id = $1`
 
 	result, err := r.db.Pool.Exec(ctx, query, id, status)
@@ -226,10 +556,14 @@ func (r *ThoughtRepository) UpdateStatus(ctx context.Context, id, status string)
 	return nil
 }
 
+// Delete moves a thought to the trash, where every other query stops
+// seeing it until Restore brings it back or PurgeDeleted removes it.
 func (r *ThoughtRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM thoughts WHERE id = $1`
+	defer r.db.changed(ctx, tableThoughts)
 
-	result, err := r.db.Pool.Exec(ctx, query, id)
+	query := `UPDATE thoughts SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to delete thought: %w", err)
 	}
@@ -241,9 +575,215 @@ func (r *ThoughtRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// ListDeleted returns the thoughts of the workspace of channelID in the
+// trash, most recently deleted first.
+func (r *ThoughtRepository) ListDeleted(ctx context.Context, channelID string) ([]*DeletedThought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `, deleted_at
+		FROM thoughts
+		WHERE deleted_at IS NOT NULL AND ` + workspaceFilter(1) + `
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	var deleted []*DeletedThought
+	for rows.Next() {
+		var deletedAt time.Time
+		thought, err := scanThought(rows, &deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan thought: %w", err)
+		}
+		deleted = append(deleted, &DeletedThought{Thought: thought, DeletedAt: deletedAt})
+	}
+
+	return deleted, rows.Err()
+}
+
+// Restore takes a thought out of the trash.
+func (r *ThoughtRepository) Restore(ctx context.Context, id string) error {
+	defer r.db.changed(ctx, tableThoughts)
+
+	query := `UPDATE thoughts SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore thought: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("thought not found in trash")
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently deletes thoughts moved to the trash before
+// olderThan.
+func (r *ThoughtRepository) PurgeDeleted(ctx context.Context, olderThan time.Time) (int, error) {
+	query := `DELETE FROM thoughts WHERE deleted_at < $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted thoughts: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// CountByCategory counts the thoughts visible from channelID per category,
+// limited to thoughts captured at or after since when it is non-zero and
+// before until when it is non-zero.
+func (r *ThoughtRepository) CountByCategory(ctx context.Context, channelID string, since, until time.Time) (map[string]int, error) {
+	return cached(ctx, r.db, fmt.Sprintf("thoughts.count_by_category:%s:%s:%s", channelID, minuteKey(since), minuteKey(until)), func() (map[string]int, error) {
+		query := `
+			SELECT COALESCE(category, 'uncategorized'), COUNT(*)
+			FROM thoughts
+			WHERE deleted_at IS NULL AND ` + workspaceFilter(1) + `
+			  AND ($2::timestamp IS NULL OR timestamp >= $2)
+			  AND ($3::timestamp IS NULL OR timestamp < $3)
+			GROUP BY 1
+		`
+
+		rows, err := r.db.Pool.Query(ctx, query, channelID, nullTime(since), nullTime(until))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count thoughts: %w", err)
+		}
+		defer rows.Close()
+
+		counts := make(map[string]int)
+		for rows.Next() {
+			var category string
+			var count int
+			if err := rows.Scan(&category, &count); err != nil {
+				return nil, fmt.Errorf("failed to scan thought count: %w", err)
+			}
+			counts[category] = count
+		}
+
+		return counts, rows.Err()
+	}, tableThoughts)
+}
+
+// CountByWeek counts the thoughts visible from channelID captured in each of
+// the last weeks weeks, oldest first, including empty weeks.
+func (r *ThoughtRepository) CountByWeek(ctx context.Context, channelID string, weeks int) ([]WeekCount, error) {
+	return cached(ctx, r.db, fmt.Sprintf("thoughts.count_by_week:%s:%d", channelID, weeks), func() ([]WeekCount, error) {
+		query := `
+			SELECT w.week, COUNT(t.id)
+			FROM generate_series(
+				date_trunc('week', LOCALTIMESTAMP) - ($2 - 1) * INTERVAL '1 week',
+				date_trunc('week', LOCALTIMESTAMP),
+				INTERVAL '1 week'
+			) AS w(week)
+			LEFT JOIN thoughts t ON date_trunc('week', t.timestamp) = w.week AND t.deleted_at IS NULL AND ` + workspaceFilter(1) + `
+			GROUP BY w.week
+			ORDER BY w.week
+		`
+
+		rows, err := r.db.Pool.Query(ctx, query, channelID, weeks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count thoughts by week: %w", err)
+		}
+		defer rows.Close()
+
+		return scanWeekCounts(rows)
+	}, tableThoughts)
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func scanWeekCounts(rows pgx.Rows) ([]WeekCount, error) {
+	var counts []WeekCount
+	for rows.Next() {
+		var count WeekCount
+		if err := rows.Scan(&count.Week, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan week count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// GetAging returns up to limit unused thoughts captured before olderThan,
+// oldest first.
+func (r *ThoughtRepository) GetAging(ctx context.Context, olderThan time.Time, limit int) ([]*models.Thought, error) {
+	query := `
+		SELECT ` + thoughtColumns + `
+		FROM thoughts
+		WHERE status = 'raw' AND deleted_at IS NULL AND timestamp < $1
+		ORDER BY timestamp ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, olderThan, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query aging thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanThoughts(rows)
+}
+
+// CountUnusedByCategory returns how many thoughts in each category have
+// not been used in a post yet.
+func (r *ThoughtRepository) CountUnusedByCategory(ctx context.Context) (map[string]int, error) {
+	query := `SELECT category, COUNT(*) FROM thoughts WHERE status = 'raw' AND deleted_at IS NULL GROUP BY category`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unused thoughts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var category string
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan count: %w", err)
+		}
+		counts[category] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *ThoughtRepository) CountInCategory(ctx context.Context, category string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM thoughts WHERE category = $1 AND deleted_at IS NULL`
+
+	if err := r.db.Pool.QueryRow(ctx, query, category).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count thoughts: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *ThoughtRepository) CountSince(ctx context.Context, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM thoughts WHERE timestamp >= $1 AND deleted_at IS NULL`
+
+	if err := r.db.Pool.QueryRow(ctx, query, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count thoughts: %w", err)
+	}
+
+	return count, nil
+}
+
 func (r *ThoughtRepository) Count(ctx context.Context) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM thoughts`
+	query := `SELECT COUNT(*) FROM thoughts WHERE deleted_at IS NULL`
 
 	err := r.db.Pool.QueryRow(ctx, query).Scan(&count)
 	if err != nil {
@@ -251,4 +791,51 @@ func (r *ThoughtRepository) Count(ctx context.Context) (int, error) {
 	}
 
 	return count, nil
-}
\ No newline at end of file
+}
+
+func scanThought(row pgx.Row, extra ...any) (*models.Thought, error) {
+	thought := &models.Thought{}
+	dest := []any{
+		&thought.ID,
+		&thought.Source,
+		&thought.Content,
+		&thought.Category,
+		&thought.TopicTags,
+		&thought.Status,
+		&thought.Timestamp,
+		&thought.RelatedThoughts,
+		&thought.SlackChannelID,
+		&thought.SlackThreadTS,
+		&thought.ExternalID,
+		&thought.UsedByPostID,
+		&thought.SourceURL,
+		&thought.SourceTitle,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	return thought, nil
+}
+
+func scanThoughts(rows pgx.Rows) ([]*models.Thought, error) {
+	var thoughts []*models.Thought
+	for rows.Next() {
+		thought, err := scanThought(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan thought: %w", err)
+		}
+		thoughts = append(thoughts, thought)
+	}
+
+	return thoughts, nil
+}
+
+func vectorLiteral(embedding []float32) string {
+	parts := make([]string, len(embedding))
+	for i, value := range embedding {
+		parts[i] = strconv.FormatFloat(float64(value), 'f', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
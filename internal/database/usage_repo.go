@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// Usage breakdowns.
+const (
+	UsageByCommand = "command"
+	UsageByUser    = "user_id"
+	UsageByModel   = "model"
+)
+
+type UsageRepository struct {
+	db *DB
+}
+
+func NewUsageRepository(db *DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+func (r *UsageRepository) Record(ctx context.Context, usage *models.LLMUsage) error {
+	if usage.ID == "" {
+		usage.ID = uuid.New().String()
+	}
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO llm_usage (id, provider, model, role, command, user_id, input_tokens, output_tokens, cost, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, usage.ID, usage.Provider, usage.Model, usage.Role, usage.Command, usage.UserID,
+		usage.InputTokens, usage.OutputTokens, usage.Cost, usage.CreatedAt); err != nil {
+		return fmt.Errorf("failed to record llm usage: %w", err)
+	}
+
+	return nil
+}
+
+// Total adds up the calls made in [from, to).
+func (r *UsageRepository) Total(ctx context.Context, from, to time.Time) (*models.UsageTotal, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(input_tokens), 0), COALESCE(SUM(output_tokens), 0), COALESCE(SUM(cost), 0)
+		FROM llm_usage
+		WHERE created_at >= $1 AND created_at < $2
+	`
+
+	total := &models.UsageTotal{}
+	if err := r.db.Pool.QueryRow(ctx, query, from, to).Scan(&total.Calls, &total.InputTokens, &total.OutputTokens, &total.Cost); err != nil {
+		return nil, fmt.Errorf("failed to total llm usage: %w", err)
+	}
+
+	return total, nil
+}
+
+// TotalBy adds up the calls made in [from, to) by one of UsageByCommand,
+// UsageByUser or UsageByModel, costliest first. Calls without a command or
+// user have an empty key.
+func (r *UsageRepository) TotalBy(ctx context.Context, by string, from, to time.Time) ([]*models.UsageTotal, error) {
+	switch by {
+	case UsageByCommand, UsageByUser, UsageByModel:
+	default:
+		return nil, fmt.Errorf("unknown usage breakdown: %s", by)
+	}
+
+	query := `
+		SELECT COALESCE(` + by + `, ''), COUNT(*), SUM(input_tokens), SUM(output_tokens), SUM(cost)
+		FROM llm_usage
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY 1
+		ORDER BY 5 DESC, 1
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total llm usage: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []*models.UsageTotal
+	for rows.Next() {
+		total := &models.UsageTotal{}
+		if err := rows.Scan(&total.Key, &total.Calls, &total.InputTokens, &total.OutputTokens, &total.Cost); err != nil {
+			return nil, fmt.Errorf("failed to scan llm usage: %w", err)
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+type UserRepository struct {
+	db *DB
+}
+
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// List returns every user with a role, owners first.
+func (r *UserRepository) List(ctx context.Context) ([]*models.User, error) {
+	query := `
+		SELECT user_id, role, COALESCE(added_by, ''), created_at
+		FROM users
+		ORDER BY CASE role WHEN 'owner' THEN 0 WHEN 'editor' THEN 1 ELSE 2 END, created_at
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		if err := rows.Scan(&user.UserID, &user.Role, &user.AddedBy, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// GetRole returns the role of userID, or "" when they have none.
+func (r *UserRepository) GetRole(ctx context.Context, userID string) (string, error) {
+	var role string
+	err := r.db.Pool.QueryRow(ctx, `SELECT role FROM users WHERE user_id = $1`, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+
+	return role, nil
+}
+
+// Any reports whether anyone has been given a role.
+func (r *UserRepository) Any(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := r.db.Pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users)`).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check users: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Save gives a user a role, replacing the one they had.
+func (r *UserRepository) Save(ctx context.Context, user *models.User) error {
+	query := `
+		INSERT INTO users (user_id, role, added_by, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET role = EXCLUDED.role, added_by = EXCLUDED.added_by
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, user.UserID, user.Role, user.AddedBy, user.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, userID string) error {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM users WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// WorkspaceRepository stores whether a channel keeps its thoughts to itself
+// (the default) or pools them with the other shared channels.
+type WorkspaceRepository struct {
+	db *DB
+}
+
+func NewWorkspaceRepository(db *DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+func (r *WorkspaceRepository) IsShared(ctx context.Context, channelID string) (bool, error) {
+	query := `SELECT shared FROM channel_workspaces WHERE channel_id = $1`
+
+	var shared bool
+	err := r.db.Pool.QueryRow(ctx, query, channelID).Scan(&shared)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get channel workspace: %w", err)
+	}
+
+	return shared, nil
+}
+
+func (r *WorkspaceRepository) SetShared(ctx context.Context, channelID string, shared bool) error {
+	query := `
+		INSERT INTO channel_workspaces (channel_id, shared, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (channel_id) DO UPDATE
+		SET shared = EXCLUDED.shared,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, channelID, shared); err != nil {
+		return fmt.Errorf("failed to update channel workspace: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package dedup
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache remembers recently seen keys for a fixed TTL. Once it holds maxSize
+// keys the least recently seen one is evicted, so memory stays bounded no
+// matter how many events arrive.
+type Cache struct {
+	mu      sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type entry struct {
+	key       string
+	expiresAt time.Time
+}
+
+func NewCache(maxSize int, ttl time.Duration) *Cache {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+
+	return &Cache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Seen reports whether key was already recorded and not yet expired. If it
+// was not, the key is recorded, so concurrent callers see exactly one false.
+func (c *Cache) Seen(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry)
+		if now.Before(e.expiresAt) {
+			c.order.MoveToFront(el)
+			return true
+		}
+		c.remove(el)
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, expiresAt: now.Add(c.ttl)})
+
+	for c.order.Len() > c.maxSize {
+		c.remove(c.order.Back())
+	}
+
+	return false
+}
+
+// Forget drops key so the next Seen call reports it as new. Used when
+// processing could not start and the sender is expected to retry.
+func (c *Cache) Forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry).key)
+}
@@ -0,0 +1,94 @@
+package dedup
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Store decides whether an event key is new. Cache is a Store local to one
+// process; Persistent shares it between replicas.
+type Store interface {
+	Seen(key string) bool
+	Forget(key string)
+}
+
+// Backend is shared storage for processed keys, such as a database table.
+type Backend interface {
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// backendTimeout bounds how long a webhook waits on the backend; Slack
+// expects an answer within 3 seconds.
+const backendTimeout = time.Second
+
+// purgeInterval is how often expired keys are removed from the backend.
+const purgeInterval = time.Hour
+
+// Persistent checks the local cache first and then claims the key in the
+// backend, so a retry delivered to another replica is still recognized. If
+// the backend is unreachable it falls back to the local cache alone rather
+// than dropping events.
+type Persistent struct {
+	local   *Cache
+	backend Backend
+	ttl     time.Duration
+}
+
+func NewPersistent(local *Cache, backend Backend, ttl time.Duration) *Persistent {
+	return &Persistent{
+		local:   local,
+		backend: backend,
+		ttl:     ttl,
+	}
+}
+
+func (p *Persistent) Seen(key string) bool {
+	if p.local.Seen(key) {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backendTimeout)
+	defer cancel()
+
+	claimed, err := p.backend.Claim(ctx, key, p.ttl)
+	if err != nil {
+		slog.WarnContext(ctx, "dedup: falling back to local cache", "key", key, "error", err)
+		return false
+	}
+
+	return !claimed
+}
+
+func (p *Persistent) Forget(key string) {
+	p.local.Forget(key)
+
+	ctx, cancel := context.WithTimeout(context.Background(), backendTimeout)
+	defer cancel()
+
+	if err := p.backend.Release(ctx, key); err != nil {
+		slog.ErrorContext(ctx, "dedup: failed to release key", "key", key, "error", err)
+	}
+}
+
+// Start removes expired keys from the backend until ctx is cancelled.
+func (p *Persistent) Start(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := p.backend.DeleteExpired(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "dedup: failed to purge expired events", "error", err)
+			} else if count > 0 {
+				slog.InfoContext(ctx, "dedup: purged expired events", "count", count)
+			}
+		}
+	}
+}
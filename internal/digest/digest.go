@@ -0,0 +1,163 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+)
+
+type Notifier interface {
+	SendMessage(channelID, message string) error
+}
+
+// lookaheadDays is how far ahead the digest checks the schedule for gaps.
+const lookaheadDays = 7
+
+// Digester posts a weekly summary of what needs attention: pending drafts,
+// uncategorized thoughts, empty posting days and a nudge when no thoughts
+// have been captured for a while.
+type Digester struct {
+	thoughtRepo *database.ThoughtRepository
+	postRepo    *database.PostRepository
+	scheduler   *agents.SchedulerAgent
+	notifier    Notifier
+	channel     string
+	schedule    Schedule
+	location    *time.Location
+	nudgeDays   int
+}
+
+func NewDigester(
+	thoughtRepo *database.ThoughtRepository,
+	postRepo *database.PostRepository,
+	scheduler *agents.SchedulerAgent,
+	notifier Notifier,
+	channel string,
+	schedule Schedule,
+	location *time.Location,
+	nudgeDays int,
+) *Digester {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &Digester{
+		thoughtRepo: thoughtRepo,
+		postRepo:    postRepo,
+		scheduler:   scheduler,
+		notifier:    notifier,
+		channel:     channel,
+		schedule:    schedule,
+		location:    location,
+		nudgeDays:   nudgeDays,
+	}
+}
+
+func (d *Digester) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "weekly digest started", "schedule", d.schedule, "location", d.location)
+
+	for {
+		next := d.schedule.Next(time.Now(), d.location)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.InfoContext(ctx, "weekly digest stopped")
+			return
+		case <-timer.C:
+		}
+
+		if err := d.Send(ctx); err != nil {
+			slog.ErrorContext(ctx, "failed to send weekly digest", "error", err)
+		}
+	}
+}
+
+func (d *Digester) Send(ctx context.Context) error {
+	message, err := d.Build(ctx)
+	if err != nil {
+		return err
+	}
+
+	return d.notifier.SendMessage(d.channel, message)
+}
+
+func (d *Digester) Build(ctx context.Context) (string, error) {
+	drafts, err := d.postRepo.GetByStatus(ctx, "draft")
+	if err != nil {
+		return "", fmt.Errorf("failed to get drafts: %w", err)
+	}
+
+	uncategorized, err := d.thoughtRepo.CountInCategory(ctx, "uncategorized")
+	if err != nil {
+		return "", err
+	}
+
+	openDays, err := d.scheduler.OpenPostingDays(ctx, lookaheadDays, d.location)
+	if err != nil {
+		return "", fmt.Errorf("failed to check schedule: %w", err)
+	}
+
+	message := "*Weekly Ghostwriter Digest*\n\n"
+
+	if d.nudgeDays > 0 {
+		recent, err := d.thoughtRepo.CountSince(ctx, time.Now().AddDate(0, 0, -d.nudgeDays))
+		if err != nil {
+			return "", err
+		}
+		if recent == 0 {
+			message += fmt.Sprintf("💡 No thoughts captured in the last %d days. Drop a quick note about something you built, learned or noticed this week.\n\n", d.nudgeDays)
+		}
+	}
+
+	if len(drafts) > 0 {
+		message += fmt.Sprintf("📝 *%d draft(s) awaiting approval.* Review them with `@LinkedIn Ghostwriter drafts`.\n", len(drafts))
+		for i, draft := range drafts {
+			if i == 3 {
+				break
+			}
+			message += fmt.Sprintf("> %s\n", truncate(strings.Join(strings.Fields(draft.Content), " "), 80))
+		}
+		message += "\n"
+	} else {
+		message += "📝 No drafts waiting. Run `@LinkedIn Ghostwriter generate` to create some.\n\n"
+	}
+
+	if uncategorized > 0 {
+		message += fmt.Sprintf("🏷️ *%d uncategorized thought(s)* could not be categorized automatically.\n\n", uncategorized)
+	}
+
+	if len(openDays) > 0 {
+		names := make([]string, len(openDays))
+		for i, day := range openDays {
+			names[i] = day.Format("Mon Jan 2")
+		}
+		message += fmt.Sprintf("📅 *Nothing scheduled on:* %s. Approve drafts and run `@LinkedIn Ghostwriter schedule` to fill the gaps.\n", strings.Join(names, ", "))
+	} else {
+		message += "📅 Every posting day in the coming week has a post scheduled.\n"
+	}
+
+	slots, err := d.scheduler.EventSlots(ctx, lookaheadDays, d.location)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read calendar events for digest", "error", err)
+	}
+	for _, slot := range slots {
+		message += fmt.Sprintf("\n🎤 *%s* is coming up. Capture a thought about it afterwards and post around %s.", slot.Event.Summary, slot.At.Format("Mon Jan 2 3:04 PM"))
+	}
+
+	return message, nil
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
+}
@@ -0,0 +1,60 @@
+package digest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Schedule is a weekly point in time, such as Monday at 09:00.
+type Schedule struct {
+	Weekday time.Weekday
+	Hour    int
+	Minute  int
+}
+
+// ParseSchedule reads specs like "mon 09:00".
+func ParseSchedule(spec string) (Schedule, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) != 2 {
+		return Schedule{}, fmt.Errorf("invalid digest schedule %q, expected e.g. \"mon 09:00\"", spec)
+	}
+
+	weekday, ok := weekdays[fields[0]]
+	if !ok {
+		return Schedule{}, fmt.Errorf("invalid digest schedule day %q", fields[0])
+	}
+
+	clock, err := time.Parse("15:04", fields[1])
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid digest schedule time %q", fields[1])
+	}
+
+	return Schedule{Weekday: weekday, Hour: clock.Hour(), Minute: clock.Minute()}, nil
+}
+
+// Next returns the first occurrence of the schedule strictly after t, in
+// location.
+func (s Schedule) Next(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	next := time.Date(t.Year(), t.Month(), t.Day(), s.Hour, s.Minute, 0, 0, location)
+	next = next.AddDate(0, 0, (int(s.Weekday)-int(next.Weekday())+7)%7)
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+func (s Schedule) String() string {
+	return fmt.Sprintf("%s %02d:%02d", s.Weekday, s.Hour, s.Minute)
+}
@@ -0,0 +1,83 @@
+package email
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// Authentication is what the service that received an email found when
+// checking where it came from. A forged From header fails these checks,
+// or passes them only for the forger's own domain.
+type Authentication struct {
+	// SPF is whether the sending server may send for MailFrom, the
+	// envelope sender.
+	SPF      bool
+	MailFrom string
+	// DKIM is whether the email's signature checked out, for the domains
+	// in DKIMDomains.
+	DKIM        bool
+	DKIMDomains []string
+	// DMARC is the service's DMARC verdict for the From domain, "pass" or
+	// "fail", or "" when it has none.
+	DMARC string
+}
+
+// Verifies reports whether the checks vouch for the domain of address:
+// DMARC passed, or SPF or DKIM passed for that domain or one within it.
+func (a Authentication) Verifies(address string) bool {
+	switch a.DMARC {
+	case "pass":
+		return true
+	case "fail":
+		return false
+	}
+
+	domain := domainOf(address)
+	if domain == "" {
+		return false
+	}
+	if a.SPF && aligned(domainOf(a.MailFrom), domain) {
+		return true
+	}
+	if a.DKIM {
+		for _, signer := range a.DKIMDomains {
+			if aligned(signer, domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// aligned reports whether a and b are the same domain or one is a
+// subdomain of the other.
+func aligned(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || strings.HasSuffix(a, "."+b) || strings.HasSuffix(b, "."+a)
+}
+
+func domainOf(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+	_, domain, found := strings.Cut(strings.Trim(strings.TrimSpace(address), "<>"), "@")
+	if !found {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(domain, "."))
+}
+
+// dkimDomains returns the d= domains of DKIM-Signature header values.
+func dkimDomains(signatures []string) []string {
+	var domains []string
+	for _, signature := range signatures {
+		for _, tag := range strings.Split(signature, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(tag), "d="); ok {
+				domains = append(domains, strings.ToLower(strings.TrimSpace(value)))
+			}
+		}
+	}
+	return domains
+}
@@ -0,0 +1,259 @@
+// Package email turns emails forwarded to a capture address, such as
+// ideas@example.com, into thoughts. Mailgun and Amazon SES deliver the
+// emails they receive to the bot's webhooks; only senders on an allow list
+// are captured.
+package email
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/sources"
+)
+
+const thoughtSource = "email"
+
+// MaxBody caps a delivered email, attachments included.
+const MaxBody = 25 << 20
+
+// maxAttachments caps how many attachments of one email are summarized.
+const maxAttachments = 3
+
+// Message is an email delivered to the capture address.
+type Message struct {
+	// ID is the Message-ID header, which keeps an email from being
+	// captured twice.
+	ID          string
+	From        string
+	Subject     string
+	Text        string
+	Attachments []Attachment
+	// Auth is how the receiving service authenticated the sender. Only
+	// emails it vouches for are captured.
+	Auth Authentication
+}
+
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Capturer saves emails from allowed senders as thoughts in the shared
+// pool.
+type Capturer struct {
+	thoughtRepo *database.ThoughtRepository
+	categorizer *agents.CategorizerAgent
+	summarizer  *agents.SummarizerAgent
+	embeddings  *agents.EmbeddingAgent
+	allowed     []string
+}
+
+// NewCapturer captures emails from the addresses in allowed, or from any
+// address at a domain given as "@example.com", that pass SPF, DKIM or
+// DMARC for the sender's domain. Attachments are summarized
+// with summarizer, and embeddings may be nil.
+func NewCapturer(thoughtRepo *database.ThoughtRepository, categorizer *agents.CategorizerAgent, summarizer *agents.SummarizerAgent, embeddings *agents.EmbeddingAgent, allowed []string) *Capturer {
+	normalized := make([]string, 0, len(allowed))
+	for _, sender := range allowed {
+		if sender = strings.ToLower(strings.TrimSpace(sender)); sender != "" {
+			normalized = append(normalized, sender)
+		}
+	}
+
+	return &Capturer{
+		thoughtRepo: thoughtRepo,
+		categorizer: categorizer,
+		summarizer:  summarizer,
+		embeddings:  embeddings,
+		allowed:     normalized,
+	}
+}
+
+// Capture saves message as a categorized thought. It returns false without
+// an error when the sender isn't allowed or can't be authenticated, the
+// email was already captured or there is nothing in it.
+func (c *Capturer) Capture(ctx context.Context, message *Message) (bool, error) {
+	sender, err := mail.ParseAddress(message.From)
+	if err != nil || !c.isAllowed(sender.Address) {
+		slog.WarnContext(ctx, "ignoring email from unknown sender", "from", message.From)
+		return false, nil
+	}
+	// Anyone can put an allowed address in From, so it only counts when the
+	// receiving service could confirm it.
+	if !message.Auth.Verifies(sender.Address) {
+		slog.WarnContext(ctx, "ignoring email that failed sender authentication", "from", message.From, "spf", message.Auth.SPF, "dkim", message.Auth.DKIM, "dmarc", message.Auth.DMARC)
+		return false, nil
+	}
+
+	if message.ID != "" {
+		exists, err := c.thoughtRepo.ExistsByExternalID(ctx, thoughtSource, message.ID)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			slog.InfoContext(ctx, "email already captured", "message_id", message.ID)
+			return false, nil
+		}
+	}
+
+	content := c.content(ctx, message)
+	if content == "" {
+		slog.InfoContext(ctx, "ignoring empty email", "message_id", message.ID)
+		return false, nil
+	}
+
+	thought := models.NewThought(content, thoughtSource)
+	thought.ExternalID = message.ID
+
+	if c.embeddings != nil {
+		if _, err := c.embeddings.Match(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "failed to match thought embeddings", "error", err)
+		}
+	}
+
+	if err := c.categorizer.CategorizeThought(ctx, thought); err != nil {
+		slog.ErrorContext(ctx, "failed to categorize email thought", "error", err)
+		thought.Category = "uncategorized"
+		thought.TopicTags = []string{"general"}
+	}
+
+	if err := c.thoughtRepo.Create(ctx, thought); err != nil {
+		return false, fmt.Errorf("failed to save thought: %w", err)
+	}
+
+	if c.embeddings != nil {
+		if err := c.embeddings.Link(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "failed to store thought embedding", "error", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "created thought from email", "thought_id", thought.ID, "message_id", message.ID, "attachments", len(message.Attachments))
+	return true, nil
+}
+
+func (c *Capturer) isAllowed(address string) bool {
+	address = strings.ToLower(address)
+	for _, sender := range c.allowed {
+		if address == sender || (strings.HasPrefix(sender, "@") && strings.HasSuffix(address, sender)) {
+			return true
+		}
+	}
+	return false
+}
+
+// content is the subject, the email's own text without quoted replies or
+// signature, and a summary of each readable attachment.
+func (c *Capturer) content(ctx context.Context, message *Message) string {
+	var parts []string
+	if subject := cleanSubject(message.Subject); subject != "" {
+		parts = append(parts, subject)
+	}
+	text := StripQuoted(message.Text)
+	if text != "" {
+		parts = append(parts, text)
+	}
+
+	summarized := 0
+	for _, attachment := range message.Attachments {
+		if summarized == maxAttachments {
+			parts = append(parts, fmt.Sprintf("Attached: %s (not summarized)", attachment.Name))
+			continue
+		}
+
+		summary, err := c.summarize(ctx, text, attachment)
+		if err != nil {
+			slog.InfoContext(ctx, "skipping unreadable attachment", "file", attachment.Name, "error", err)
+			parts = append(parts, fmt.Sprintf("Attached: %s", attachment.Name))
+			continue
+		}
+		summarized++
+		parts = append(parts, fmt.Sprintf("Summary of %s:\n%s", attachment.Name, summary))
+	}
+
+	return strings.TrimSpace(strings.Join(parts, "\n\n"))
+}
+
+func (c *Capturer) summarize(ctx context.Context, note string, attachment Attachment) (string, error) {
+	doc, err := sources.Extract(attachment.Data, attachment.ContentType, attachment.Name)
+	if err != nil {
+		return "", err
+	}
+	return c.summarizer.Summarize(ctx, note, attachment.Name, doc.Text)
+}
+
+// trimMessageID drops the angle brackets around a Message-ID.
+func trimMessageID(id string) string {
+	return strings.Trim(strings.TrimSpace(id), "<>")
+}
+
+var subjectPrefix = regexp.MustCompile(`(?i)^\s*((re|fw|fwd|aw|wg)\s*:\s*)+`)
+
+// cleanSubject drops the "Fwd:" and "Re:" prefixes forwarding adds.
+func cleanSubject(subject string) string {
+	return strings.TrimSpace(subjectPrefix.ReplaceAllString(subject, ""))
+}
+
+var (
+	// replyHeader starts the quoted message of a reply, as in "On Mon, Mar
+	// 2, 2026 at 9:14 AM Ana <ana@example.com> wrote:".
+	replyHeader = regexp.MustCompile(`(?i)^(on\s.+\swrote:|am\s.+\sschrieb.*:|le\s.+\sa écrit\s?:|-+\s*original message\s*-+)$`)
+	// forwardHeader opens the message a forward carries, whose header
+	// lines follow until a blank line.
+	forwardHeader = regexp.MustCompile(`(?i)^-+\s*(forwarded message|weitergeleitete nachricht|message transféré)\s*-+$|^begin forwarded message:$`)
+	// mobileSignature is what mail apps add below the text.
+	mobileSignature = regexp.MustCompile(`(?i)^sent from my \w+|^get outlook for \w+`)
+)
+
+// StripQuoted returns the text an email adds of its own. Quoted lines, the
+// message a reply quotes and signatures are dropped. A forward keeps the
+// message it carries, which is usually the point of forwarding it, but not
+// the header lines in front of it.
+func StripQuoted(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var kept []string
+	// skipping drops a signature or quoted reply up to the next forwarded
+	// message, if any; inForwardHeader drops a forward's header lines up to
+	// the blank line that ends them.
+	skipping, inForwardHeader := false, false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case forwardHeader.MatchString(trimmed):
+			skipping, inForwardHeader = false, true
+			continue
+		case inForwardHeader:
+			inForwardHeader = trimmed != ""
+			continue
+		case skipping:
+			continue
+		case line == "-- " || trimmed == "--" || mobileSignature.MatchString(trimmed):
+			skipping = true
+			continue
+		case replyHeader.MatchString(trimmed) && len(kept) > 0:
+			skipping = true
+			continue
+		case strings.HasPrefix(trimmed, ">"):
+			continue
+		}
+
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+
+	return joinLines(kept)
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+func joinLines(lines []string) string {
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,139 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/webhook"
+)
+
+// deliveryAttempts is how many times an email is processed before it
+// becomes a dead letter.
+const deliveryAttempts = 3
+
+// maxFormMemory is how much of a posted email is parsed in memory; larger
+// attachments spill to temporary files.
+const maxFormMemory = 32 << 20
+
+// Mailgun returns the endpoint a Mailgun route forwards emails to, with
+// forward("https://<bot>/email/mailgun"), for a webhook.Router. signingKey
+// is the HTTP webhook signing key of the Mailgun account.
+func (c *Capturer) Mailgun(signingKey string) webhook.Source {
+	return webhook.Source{
+		Name:       "mailgun",
+		Path:       "/email/mailgun",
+		Verifier:   webhook.Mailgun(signingKey),
+		MaxBody:    MaxBody,
+		DeliveryID: webhook.MailgunToken,
+		Attempts:   deliveryAttempts,
+		Handle:     c.handleMailgun,
+	}
+}
+
+func (c *Capturer) handleMailgun(ctx context.Context, delivery *webhook.Delivery) ([]byte, error) {
+	message, err := parseMailgun(delivery)
+	if err != nil {
+		return nil, webhook.Fail(http.StatusBadRequest, err)
+	}
+
+	if _, err := c.Capture(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to capture email %s: %w", message.ID, err)
+	}
+	return nil, nil
+}
+
+// parseMailgun reads the form Mailgun posts for a stored or forwarded
+// email: its headers and plain text as fields, and its attachments as files.
+func parseMailgun(delivery *webhook.Delivery) (*Message, error) {
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(delivery.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = delivery.Header
+	if err := req.ParseMultipartForm(maxFormMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return nil, fmt.Errorf("failed to parse mailgun form: %w", err)
+	}
+	if req.MultipartForm != nil {
+		defer req.MultipartForm.RemoveAll()
+	}
+
+	from := req.PostFormValue("from")
+	if from == "" {
+		from = req.PostFormValue("sender")
+	}
+
+	headers := mailgunHeaders(req.PostFormValue("message-headers"))
+	header := func(name string) string {
+		if value := req.PostFormValue(name); value != "" {
+			return value
+		}
+		return headers.Get(name)
+	}
+
+	message := &Message{
+		ID:      req.PostFormValue("Message-Id"),
+		From:    from,
+		Subject: req.PostFormValue("subject"),
+		Text:    req.PostFormValue("body-plain"),
+		Auth: Authentication{
+			SPF:         strings.EqualFold(header("X-Mailgun-Spf"), "pass"),
+			MailFrom:    req.PostFormValue("sender"),
+			DKIM:        strings.EqualFold(header("X-Mailgun-Dkim-Check-Result"), "pass"),
+			DKIMDomains: dkimDomains(headers.Values("DKIM-Signature")),
+		},
+	}
+	if message.ID == "" {
+		message.ID = req.PostFormValue("message-id")
+	}
+	message.ID = trimMessageID(message.ID)
+
+	if req.MultipartForm == nil {
+		return message, nil
+	}
+	for i := 1; ; i++ {
+		files := req.MultipartForm.File[fmt.Sprintf("attachment-%d", i)]
+		if len(files) == 0 {
+			break
+		}
+
+		file, err := files[0].Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open attachment: %w", err)
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attachment: %w", err)
+		}
+
+		message.Attachments = append(message.Attachments, Attachment{
+			Name:        files[0].Filename,
+			ContentType: files[0].Header.Get("Content-Type"),
+			Data:        data,
+		})
+	}
+
+	return message, nil
+}
+
+// mailgunHeaders reads the message-headers field, the email's headers as
+// a JSON list of name and value pairs.
+func mailgunHeaders(field string) textproto.MIMEHeader {
+	var pairs [][2]string
+	if err := json.Unmarshal([]byte(field), &pairs); err != nil {
+		return textproto.MIMEHeader{}
+	}
+
+	headers := make(textproto.MIMEHeader, len(pairs))
+	for _, pair := range pairs {
+		headers.Add(pair[0], pair[1])
+	}
+	return headers
+}
@@ -0,0 +1,130 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/sources"
+)
+
+// maxParts caps how many parts of a message are read, so a crafted one
+// can't keep the parser busy.
+const maxParts = 50
+
+// ParseMIME reads a raw email, as SES delivers it. The text is its first
+// plain text part, or its first HTML part as text when it has none; parts
+// with a file name are attachments.
+func ParseMIME(raw []byte) (*Message, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email: %w", err)
+	}
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	message := &Message{
+		ID:      trimMessageID(msg.Header.Get("Message-Id")),
+		From:    msg.Header.Get("From"),
+		Subject: subject,
+		Auth:    Authentication{DKIMDomains: dkimDomains(msg.Header["Dkim-Signature"])},
+	}
+
+	var html string
+	parts := 0
+	var walk func(header textproto.MIMEHeader, body io.Reader) error
+	walk = func(header textproto.MIMEHeader, body io.Reader) error {
+		parts++
+		if parts > maxParts {
+			return nil
+		}
+
+		mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+		if err != nil {
+			mediaType = "text/plain"
+		}
+
+		if strings.HasPrefix(mediaType, "multipart/") {
+			reader := multipart.NewReader(body, params["boundary"])
+			for {
+				part, err := reader.NextRawPart()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return fmt.Errorf("failed to read email part: %w", err)
+				}
+				if err := walk(part.Header, part); err != nil {
+					return err
+				}
+			}
+		}
+
+		data, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+		if err != nil {
+			return fmt.Errorf("failed to decode email part: %w", err)
+		}
+
+		if name := fileName(header, params, decoder); name != "" {
+			message.Attachments = append(message.Attachments, Attachment{Name: name, ContentType: mediaType, Data: data})
+			return nil
+		}
+
+		switch {
+		case mediaType == "text/plain" && message.Text == "":
+			message.Text = string(data)
+		case mediaType == "text/html" && html == "":
+			if doc, err := sources.Extract(data, mediaType, ""); err == nil {
+				html = doc.Text
+			}
+		}
+		return nil
+	}
+
+	if err := walk(textproto.MIMEHeader(msg.Header), msg.Body); err != nil {
+		return nil, err
+	}
+	if message.Text == "" {
+		message.Text = html
+	}
+
+	return message, nil
+}
+
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
+
+// fileName returns the name a part is attached under, or "" for a part
+// that is shown inline as the message.
+func fileName(header textproto.MIMEHeader, params map[string]string, decoder *mime.WordDecoder) string {
+	name := params["name"]
+	if disposition, dispositionParams, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+		if filename := dispositionParams["filename"]; filename != "" {
+			name = filename
+		} else if disposition == "attachment" && name == "" {
+			name = "attachment"
+		}
+	}
+	if decoded, err := decoder.DecodeHeader(name); err == nil {
+		name = decoded
+	}
+	return name
+}
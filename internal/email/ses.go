@@ -0,0 +1,293 @@
+package email
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/webhook"
+)
+
+// snsHost matches the hosts SNS signing certificates and subscription
+// confirmations are served from.
+var snsHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// snsMessage is what SNS posts to an HTTPS subscription.
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	TopicARN         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	Token            string `json:"Token"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// sesNotification is what an SES receipt rule's SNS action publishes.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		// Source is the envelope sender.
+		Source string `json:"source"`
+	} `json:"mail"`
+	Receipt struct {
+		Action struct {
+			Encoding string `json:"encoding"`
+		} `json:"action"`
+		SPFVerdict   sesVerdict `json:"spfVerdict"`
+		DKIMVerdict  sesVerdict `json:"dkimVerdict"`
+		DMARCVerdict sesVerdict `json:"dmarcVerdict"`
+	} `json:"receipt"`
+	Content string `json:"content"`
+}
+
+// sesVerdict is the outcome of one of SES's checks: PASS, FAIL, GRAY or
+// PROCESSING_FAILED.
+type sesVerdict struct {
+	Status string `json:"status"`
+}
+
+// SES returns the endpoint an SNS topic that an SES receipt rule publishes
+// emails to delivers them to, for a webhook.Router. Only messages from
+// topicARN are accepted.
+func (c *Capturer) SES(topicARN string) webhook.Source {
+	return webhook.Source{
+		Name:     "ses",
+		Path:     "/email/ses",
+		Verifier: newSNSVerifier(topicARN),
+		MaxBody:  MaxBody,
+		DeliveryID: func(header http.Header, _ []byte) string {
+			return header.Get("X-Amz-Sns-Message-Id")
+		},
+		Attempts: deliveryAttempts,
+		Handle:   c.handleSES,
+	}
+}
+
+func (c *Capturer) handleSES(ctx context.Context, delivery *webhook.Delivery) ([]byte, error) {
+	var message snsMessage
+	if err := json.Unmarshal(delivery.Body, &message); err != nil {
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse sns message: %w", err))
+	}
+
+	switch message.Type {
+	case "SubscriptionConfirmation":
+		if err := confirmSubscription(ctx, message.SubscribeURL); err != nil {
+			return nil, err
+		}
+		slog.InfoContext(ctx, "confirmed ses email subscription", "topic_arn", message.TopicARN)
+		return nil, nil
+	case "Notification":
+	default:
+		return nil, nil
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(message.Message), &notification); err != nil {
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse ses notification: %w", err))
+	}
+	if notification.NotificationType != "Received" {
+		return nil, nil
+	}
+	if notification.Content == "" {
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("ses notification has no content, the receipt rule's SNS action must include the email"))
+	}
+
+	raw := []byte(notification.Content)
+	if strings.EqualFold(notification.Receipt.Action.Encoding, "BASE64") {
+		decoded, err := base64.StdEncoding.DecodeString(notification.Content)
+		if err != nil {
+			return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to decode ses email: %w", err))
+		}
+		raw = decoded
+	}
+
+	email, err := ParseMIME(raw)
+	if err != nil {
+		return nil, webhook.Fail(http.StatusBadRequest, err)
+	}
+
+	receipt := notification.Receipt
+	email.Auth.SPF = receipt.SPFVerdict.Status == "PASS"
+	email.Auth.MailFrom = notification.Mail.Source
+	email.Auth.DKIM = receipt.DKIMVerdict.Status == "PASS"
+	switch receipt.DMARCVerdict.Status {
+	case "PASS":
+		email.Auth.DMARC = "pass"
+	case "FAIL":
+		email.Auth.DMARC = "fail"
+	}
+
+	if _, err := c.Capture(ctx, email); err != nil {
+		return nil, fmt.Errorf("failed to capture email %s: %w", email.ID, err)
+	}
+	return nil, nil
+}
+
+// confirmSubscription visits the link SNS sends to confirm that the bot
+// wants the topic's messages.
+func confirmSubscription(ctx context.Context, subscribeURL string) error {
+	if err := checkSNSURL(subscribeURL); err != nil {
+		return webhook.Fail(http.StatusBadRequest, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subscribeURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := snsClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to confirm sns subscription: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to confirm sns subscription: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+var snsClient = &http.Client{Timeout: 10 * time.Second}
+
+func checkSNSURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || !snsHost.MatchString(parsed.Hostname()) {
+		return fmt.Errorf("%q is not an SNS URL", rawURL)
+	}
+	return nil
+}
+
+// snsVerifier checks the signature SNS puts on every message against its
+// signing certificate, which is fetched from SNS once and kept.
+type snsVerifier struct {
+	topicARN string
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+func newSNSVerifier(topicARN string) *snsVerifier {
+	return &snsVerifier{topicARN: topicARN, certs: map[string]*x509.Certificate{}}
+}
+
+func (v *snsVerifier) Verify(_ http.Header, body []byte) error {
+	var message snsMessage
+	if err := json.Unmarshal(body, &message); err != nil || message.Signature == "" {
+		return errors.New("missing signature")
+	}
+	if message.TopicARN != v.topicARN {
+		return fmt.Errorf("unexpected topic %s", message.TopicARN)
+	}
+
+	var hash crypto.Hash
+	switch message.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unknown signature version %q", message.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil {
+		return errors.New("invalid signature")
+	}
+
+	cert, err := v.cert(message.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("signing certificate has no RSA key")
+	}
+
+	digest := hash.New()
+	digest.Write([]byte(message.signedString()))
+	if err := rsa.VerifyPKCS1v15(key, hash, digest.Sum(nil), signature); err != nil {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// signedString is the text SNS signs: the message's fields, by type, each
+// as its name and value on lines of their own.
+func (m *snsMessage) signedString() string {
+	fields := [][2]string{{"Message", m.Message}, {"MessageId", m.MessageID}}
+	if m.Type == "Notification" {
+		if m.Subject != "" {
+			fields = append(fields, [2]string{"Subject", m.Subject})
+		}
+	} else {
+		fields = append(fields, [2]string{"SubscribeURL", m.SubscribeURL})
+	}
+	fields = append(fields, [2]string{"Timestamp", m.Timestamp})
+	if m.Type != "Notification" {
+		fields = append(fields, [2]string{"Token", m.Token})
+	}
+	fields = append(fields, [2]string{"TopicArn", m.TopicARN}, [2]string{"Type", m.Type})
+
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return b.String()
+}
+
+func (v *snsVerifier) cert(certURL string) (*x509.Certificate, error) {
+	if err := checkSNSURL(certURL); err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if cert, ok := v.certs[certURL]; ok {
+		return cert, nil
+	}
+
+	resp, err := snsClient.Get(certURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing certificate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch signing certificate: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing certificate is not PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing certificate: %w", err)
+	}
+
+	v.certs[certURL] = cert
+	return cert, nil
+}
@@ -0,0 +1,214 @@
+// Package experiments concludes A/B experiments between two variations of
+// a post once both have had a week to collect engagement, and reports which
+// framing did better.
+package experiments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// SettleDays is how long after publishing a variation's metrics are
+	// compared, so both get the same time to collect engagement.
+	SettleDays = models.SettleDays
+	// minLift is how much better, as a fraction, the winner has to do for
+	// the result to count rather than be too close to call.
+	minLift = 0.1
+)
+
+type Notifier interface {
+	SendMessage(channelID, message string) error
+}
+
+// Outcome is the result of an experiment.
+type Outcome struct {
+	// Done is false while a variation is still to be published or its
+	// metrics haven't settled.
+	Done bool
+	// Abandoned explains why an experiment ended without a comparison, such
+	// as a variation that was unscheduled or failed to publish.
+	Abandoned string
+	// Winner is nil when the variations did about as well.
+	Winner *models.Post
+	// Lift is how much better the winner did than the other variation.
+	Lift float64
+}
+
+// Evaluate works out where an experiment stands.
+func Evaluate(experiment *models.Experiment) Outcome {
+	if len(experiment.Posts) != 2 {
+		return Outcome{Done: true, Abandoned: fmt.Sprintf("it has %d variation(s) left instead of 2", len(experiment.Posts))}
+	}
+
+	for i, post := range experiment.Posts {
+		switch post.Status {
+		case "scheduled", models.StatusPublishing, models.StatusOnHold:
+			return Outcome{}
+		case "published":
+		case "approved":
+			return Outcome{Done: true, Abandoned: fmt.Sprintf("variation %s was unscheduled", Label(i))}
+		default:
+			return Outcome{Done: true, Abandoned: fmt.Sprintf("variation %s is %s", Label(i), strings.ReplaceAll(post.Status, "_", " "))}
+		}
+
+		// Each variation is compared at the same age, by the metrics it had
+		// SettleDays after publishing, however long ago that was.
+		if post.SettledScore == nil {
+			return Outcome{}
+		}
+	}
+
+	a, b := experiment.Posts[0], experiment.Posts[1]
+	winner, loser := a, b
+	if *b.SettledScore > *a.SettledScore {
+		winner, loser = b, a
+	}
+
+	outcome := Outcome{Done: true}
+	switch {
+	case *loser.SettledScore > 0:
+		outcome.Lift = *winner.SettledScore / *loser.SettledScore - 1
+	case *winner.SettledScore > 0:
+		outcome.Lift = 1
+	}
+	if outcome.Lift >= minLift {
+		outcome.Winner = winner
+	}
+
+	return outcome
+}
+
+// Label names the variation at index i: A is published first.
+func Label(i int) string {
+	return string(rune('A' + i))
+}
+
+// Tracker checks running experiments every interval, concludes the ones
+// that are done and reports them in the channel they were started from.
+type Tracker struct {
+	repo          *database.ExperimentRepository
+	notifier      Notifier
+	notifyChannel string
+	interval      time.Duration
+}
+
+// NewTracker reports experiments that have no channel of their own to
+// notifyChannel.
+func NewTracker(repo *database.ExperimentRepository, notifier Notifier, notifyChannel string, interval time.Duration) *Tracker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &Tracker{
+		repo:          repo,
+		notifier:      notifier,
+		notifyChannel: notifyChannel,
+		interval:      interval,
+	}
+}
+
+func (t *Tracker) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "experiment tracker started", "interval", t.interval)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		t.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "experiment tracker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) check(ctx context.Context) {
+	running, err := t.repo.GetRunning(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch running experiments", "error", err)
+		return
+	}
+
+	for _, experiment := range running {
+		outcome := Evaluate(experiment)
+		if !outcome.Done {
+			continue
+		}
+
+		var winnerID *string
+		if outcome.Winner != nil {
+			winnerID = &outcome.Winner.ID
+		}
+		if err := t.repo.Conclude(ctx, experiment.ID, winnerID); err != nil {
+			slog.ErrorContext(ctx, "failed to conclude experiment", "experiment_id", experiment.ID, "error", err)
+			continue
+		}
+
+		slog.InfoContext(ctx, "experiment concluded", "experiment_id", experiment.ID, "winner", winnerID, "lift", outcome.Lift)
+		t.report(ctx, experiment, outcome)
+	}
+}
+
+func (t *Tracker) report(ctx context.Context, experiment *models.Experiment, outcome Outcome) {
+	channel := experiment.SlackChannelID
+	if channel == "" {
+		channel = t.notifyChannel
+	}
+	if t.notifier == nil || channel == "" {
+		return
+	}
+
+	if err := t.notifier.SendMessage(channel, Report(experiment, outcome)); err != nil {
+		slog.ErrorContext(ctx, "failed to report experiment", "experiment_id", experiment.ID, "error", err)
+	}
+}
+
+// Report describes a concluded experiment for Slack.
+func Report(experiment *models.Experiment, outcome Outcome) string {
+	if outcome.Abandoned != "" {
+		return fmt.Sprintf("🧪 *Experiment ended without a result*: %s.", outcome.Abandoned)
+	}
+
+	var b strings.Builder
+	switch {
+	case outcome.Winner != nil:
+		label := Label(0)
+		if outcome.Winner.ID == experiment.Posts[1].ID {
+			label = Label(1)
+		}
+		fmt.Fprintf(&b, "🧪 *Experiment finished: variation %s won*, with a %.0f%% higher performance score after %d days.\n", label, outcome.Lift*100, SettleDays)
+	default:
+		fmt.Fprintf(&b, "🧪 *Experiment finished: too close to call.* The variations scored within %.0f%% of each other after %d days.\n", minLift*100, SettleDays)
+	}
+
+	for i, post := range experiment.Posts {
+		fmt.Fprintf(&b, "\n*%s* · published %s · score %.0f · %d likes · %d comments · %d shares",
+			Label(i), post.PublishedAt.Format("Jan 02"), *post.SettledScore,
+			post.SettledMetrics["likes"], post.SettledMetrics["comments"], post.SettledMetrics["shares"])
+		if post.Scores != nil {
+			fmt.Fprintf(&b, " · hook %d/100", post.Scores.HookStrength)
+		}
+		fmt.Fprintf(&b, "\n_%s_\n", firstLine(post.Content))
+	}
+
+	return b.String()
+}
+
+// firstLine is the opening of a post, where variations usually differ most.
+func firstLine(content string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	if runes := []rune(line); len(runes) > 120 {
+		line = string(runes[:120]) + "…"
+	}
+	return line
+}
@@ -0,0 +1,315 @@
+// Package export writes posts and thoughts out as CSV, JSON or Markdown
+// files, for backups and for sharing the content calendar outside Slack.
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// Formats.
+const (
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// What to export.
+const (
+	Posts    = "posts"
+	Thoughts = "thoughts"
+	All      = "all"
+)
+
+// pageSize is how many rows are read at a time.
+const pageSize = 200
+
+// postStatusOrder lists post statuses in the order the Markdown export
+// shows them: the calendar first, then the backlog.
+var postStatusOrder = []string{"published", "publishing", "scheduled", "on_hold", "approved", "in_review", "draft", "stale", "rejected", "failed"}
+
+// Data is what goes into an export.
+type Data struct {
+	Posts    []*models.Post
+	Thoughts []*models.Thought
+	// GeneratedAt dates the export and names its files.
+	GeneratedAt time.Time
+}
+
+// File is one generated export file.
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Load reads every post, and the thoughts channelID can see, for what:
+// Posts, Thoughts or All.
+func Load(ctx context.Context, postRepo *database.PostRepository, thoughtRepo *database.ThoughtRepository, channelID, what string) (*Data, error) {
+	data := &Data{GeneratedAt: time.Now()}
+
+	if what == Posts || what == All {
+		for offset := 0; ; offset += pageSize {
+			page, err := postRepo.List(ctx, "", pageSize, offset)
+			if err != nil {
+				return nil, err
+			}
+			data.Posts = append(data.Posts, page...)
+			if len(page) < pageSize {
+				break
+			}
+		}
+	}
+
+	if what == Thoughts || what == All {
+		for offset := 0; ; offset += pageSize {
+			page, err := thoughtRepo.GetByWorkspace(ctx, channelID, pageSize, offset)
+			if err != nil {
+				return nil, err
+			}
+			data.Thoughts = append(data.Thoughts, page...)
+			if len(page) < pageSize {
+				break
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// Render writes data in format. CSV gets a file per kind of record, since
+// posts and thoughts have different columns; JSON and Markdown get one
+// file holding both. what decides which kinds are written.
+func Render(data *Data, format, what string) ([]File, error) {
+	base := "ghostwriter-" + data.GeneratedAt.Format("2006-01-02")
+	withPosts, withThoughts := what == Posts || what == All, what == Thoughts || what == All
+
+	switch format {
+	case FormatCSV:
+		var files []File
+		if withPosts {
+			content, err := postsCSV(data.Posts)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, File{Name: base + "-posts.csv", Content: content})
+		}
+		if withThoughts {
+			content, err := thoughtsCSV(data.Thoughts)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, File{Name: base + "-thoughts.csv", Content: content})
+		}
+		return files, nil
+
+	case FormatJSON:
+		export := struct {
+			GeneratedAt time.Time         `json:"generated_at"`
+			Posts       []*models.Post    `json:"posts,omitempty"`
+			Thoughts    []*models.Thought `json:"thoughts,omitempty"`
+		}{GeneratedAt: data.GeneratedAt}
+		if withPosts {
+			export.Posts = data.Posts
+		}
+		if withThoughts {
+			export.Thoughts = data.Thoughts
+		}
+
+		content, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode export: %w", err)
+		}
+		return []File{{Name: base + "-" + what + ".json", Content: content}}, nil
+
+	case FormatMarkdown:
+		var b strings.Builder
+		fmt.Fprintf(&b, "# LinkedIn Ghostwriter export\n\n_Generated %s_\n", data.GeneratedAt.Format("January 2, 2006 15:04"))
+		if withPosts {
+			writePostsMarkdown(&b, data.Posts)
+		}
+		if withThoughts {
+			writeThoughtsMarkdown(&b, data.Thoughts)
+		}
+		return []File{{Name: base + "-" + what + ".md", Content: []byte(b.String())}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+func postsCSV(posts []*models.Post) ([]byte, error) {
+	rows := [][]string{{
+		"id", "status", "post_type", "content", "created_at", "scheduled_at", "published_at",
+		"targets", "likes", "comments", "shares", "views", "performance_score", "linkedin_urn", "linkedin_url",
+		"hook_strength", "reading_grade", "sentence_variance", "emoji_count", "first_comment", "language",
+	}}
+
+	for _, post := range posts {
+		scores := post.Scores
+		if scores == nil {
+			scores = models.ScoreContent(post.Content)
+		}
+
+		rows = append(rows, []string{
+			post.ID,
+			post.Status,
+			post.PostType,
+			post.Content,
+			post.CreatedAt.Format(time.RFC3339),
+			formatTime(post.ScheduledAt),
+			formatTime(post.PublishedAt),
+			strings.Join(post.Targets, ","),
+			strconv.Itoa(post.Metrics["likes"]),
+			strconv.Itoa(post.Metrics["comments"]),
+			strconv.Itoa(post.Metrics["shares"]),
+			strconv.Itoa(post.Metrics["views"]),
+			strconv.FormatFloat(post.PerformanceScore, 'f', -1, 64),
+			post.LinkedInURN,
+			post.LinkedInURL,
+			strconv.Itoa(scores.HookStrength),
+			strconv.FormatFloat(scores.ReadingGrade, 'f', -1, 64),
+			strconv.FormatFloat(scores.SentenceVariance, 'f', -1, 64),
+			strconv.Itoa(scores.EmojiCount),
+			post.FirstComment,
+			post.Language,
+		})
+	}
+
+	return writeCSV(rows)
+}
+
+// thoughtsCSV uses the columns the importer reads, so an export can be
+// imported into another bot.
+func thoughtsCSV(thoughts []*models.Thought) ([]byte, error) {
+	rows := [][]string{{"id", "date", "source", "status", "category", "tags", "content", "source_url"}}
+
+	for _, thought := range thoughts {
+		rows = append(rows, []string{
+			thought.ID,
+			thought.Timestamp.Format(time.RFC3339),
+			thought.Source,
+			thought.Status,
+			thought.Category,
+			strings.Join(thought.TopicTags, ", "),
+			thought.Content,
+			thought.SourceURL,
+		})
+	}
+
+	return writeCSV(rows)
+}
+
+func writeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writePostsMarkdown(b *strings.Builder, posts []*models.Post) {
+	byStatus := make(map[string][]*models.Post)
+	for _, post := range posts {
+		byStatus[post.Status] = append(byStatus[post.Status], post)
+	}
+
+	fmt.Fprintf(b, "\n## Posts (%d)\n", len(posts))
+
+	var others []string
+	for status := range byStatus {
+		if !slices.Contains(postStatusOrder, status) {
+			others = append(others, status)
+		}
+	}
+	slices.Sort(others)
+	statuses := slices.Concat(postStatusOrder, others)
+
+	for _, status := range statuses {
+		group := byStatus[status]
+		if len(group) == 0 {
+			continue
+		}
+		if status == "scheduled" {
+			// Upcoming posts read best in calendar order.
+			slices.SortStableFunc(group, func(a, b *models.Post) int {
+				return postTime(a).Compare(postTime(b))
+			})
+		}
+
+		fmt.Fprintf(b, "\n### %s (%d)\n", strings.ReplaceAll(status, "_", " "), len(group))
+		for _, post := range group {
+			fmt.Fprintf(b, "\n#### %s · %s\n\n", post.PostType, postDate(post))
+			switch {
+			case post.Status == "published":
+				fmt.Fprintf(b, "_%d likes · %d comments · %d shares · %d views_\n\n",
+					post.Metrics["likes"], post.Metrics["comments"], post.Metrics["shares"], post.Metrics["views"])
+			case len(post.Targets) > 0:
+				fmt.Fprintf(b, "_Targets: %s_\n\n", strings.Join(post.Targets, ", "))
+			}
+			b.WriteString(post.Content + "\n")
+			for i, slide := range post.Slides {
+				fmt.Fprintf(b, "\n> **Slide %d:** %s\n", i+1, strings.ReplaceAll(slide, "\n", " — "))
+			}
+			if post.Poll != nil {
+				fmt.Fprintf(b, "\n**Poll:** %s\n", post.Poll.Question)
+				for _, option := range post.Poll.Options {
+					fmt.Fprintf(b, "- %s\n", option)
+				}
+			}
+			if post.FirstComment != "" {
+				fmt.Fprintf(b, "\n**First comment:** %s\n", post.FirstComment)
+			}
+		}
+	}
+}
+
+func writeThoughtsMarkdown(b *strings.Builder, thoughts []*models.Thought) {
+	fmt.Fprintf(b, "\n## Thoughts (%d)\n\n", len(thoughts))
+
+	for _, thought := range thoughts {
+		fmt.Fprintf(b, "- **%s** · %s", thought.Timestamp.Format("Jan 02, 2006"), thought.Category)
+		if len(thought.TopicTags) > 0 {
+			fmt.Fprintf(b, " · %s", strings.Join(thought.TopicTags, ", "))
+		}
+		if thought.Status == "used" {
+			b.WriteString(" · _used_")
+		}
+		fmt.Fprintf(b, "\n  %s\n", strings.ReplaceAll(strings.TrimSpace(thought.Content), "\n", "\n  "))
+	}
+}
+
+// postDate is the most relevant date for a post: when it went out, when it
+// is due, or when it was written.
+func postDate(post *models.Post) string {
+	switch {
+	case post.PublishedAt != nil:
+		return "published " + post.PublishedAt.Format("Jan 02, 2006 15:04")
+	case post.ScheduledAt != nil:
+		return "scheduled for " + post.ScheduledAt.Format("Jan 02, 2006 15:04")
+	}
+	return "created " + post.CreatedAt.Format("Jan 02, 2006")
+}
+
+func postTime(post *models.Post) time.Time {
+	if post.ScheduledAt != nil {
+		return *post.ScheduledAt
+	}
+	return post.CreatedAt
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,130 @@
+// Package format converts post text between the forms it takes. Posts are
+// stored as canonical text: plain text in which **bold** and _italic_ mark
+// emphasis, as in Markdown. Slack shows it as mrkdwn, and LinkedIn and X,
+// which have no formatting, get the emphasis as Unicode bold and italic
+// letters.
+package format
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Emphasis opens after the start of a line, a space or an opening
+	// bracket or quote, and closes before the end of a line, a space or
+	// punctuation, so snake_case words and "5 * 3" are left alone.
+	mdBold     = regexp.MustCompile(`\*\*([^*\s](?:[^*\n]*[^*\s])?)\*\*`)
+	mdAltBold  = regexp.MustCompile(`(^|[\s(\["'])__([^_\s](?:[^_\n]*[^_\s])?)__([\s.,;:!?)\]"']|$)`)
+	singleStar = regexp.MustCompile(`(^|[\s(\["'_])\*([^*\s](?:[^*\n]*[^*\s])?)\*([\s.,;:!?)\]"'_]|$)`)
+	italic     = regexp.MustCompile(`(^|[\s(\["'*])_([^_\s](?:[^_\n]*[^_\s])?)_([\s.,;:!?)\]"'*]|$)`)
+	strike     = regexp.MustCompile(`(^|[\s(\["'])~([^~\s](?:[^~\n]*[^~\s])?)~([\s.,;:!?)\]"']|$)`)
+
+	mdHeading = regexp.MustCompile(`(?m)^#{1,6}[ \t]+`)
+	mdLink    = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^)\s]+)\)`)
+	slackLink = regexp.MustCompile(`<((?:https?|mailto):[^|>\s]+)(?:\|([^>\n]+))?>`)
+)
+
+var (
+	slackUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+	slackEscaper   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// FromMarkdown returns the canonical text of a post a model wrote, which
+// may use Markdown beyond the emphasis the canonical text keeps.
+func FromMarkdown(text string) string {
+	text = mdHeading.ReplaceAllString(text, "")
+	text = mdLink.ReplaceAllString(text, "$1 ($2)")
+	text = replaceAll(mdAltBold, text, "**")
+	text = replaceAll(singleStar, text, "_")
+	return strings.TrimSpace(text)
+}
+
+// FromSlack returns the canonical text of a post written in Slack, where
+// *bold* is a single asterisk and links and &, < and > are escaped.
+func FromSlack(text string) string {
+	text = slackLink.ReplaceAllStringFunc(text, func(link string) string {
+		match := slackLink.FindStringSubmatch(link)
+		url, label := match[1], match[2]
+		url = strings.TrimPrefix(url, "mailto:")
+		if label == "" || label == url {
+			return url
+		}
+		return label + " (" + url + ")"
+	})
+	text = slackUnescaper.Replace(text)
+	text = replaceAll(strike, text, "")
+	text = replaceAll(singleStar, text, "**")
+	return strings.TrimSpace(text)
+}
+
+// ToSlack returns canonical text as mrkdwn, to show in a Slack message.
+func ToSlack(text string) string {
+	return ToSlackInput(slackEscaper.Replace(text))
+}
+
+// ToSlackInput returns canonical text with Slack's emphasis, to edit in a
+// text input, which Slack doesn't escape. FromSlack reads it back.
+func ToSlackInput(text string) string {
+	return mdBold.ReplaceAllString(text, "*$1*")
+}
+
+// ToUnicode returns canonical text as plain text with bold and italic
+// spelled in Unicode letters, which LinkedIn and X show as formatted.
+func ToUnicode(text string) string {
+	text = mdBold.ReplaceAllStringFunc(text, func(match string) string {
+		return strings.Map(boldLetter, match[2:len(match)-2])
+	})
+	return replaceAllFunc(italic, text, func(inner string) string {
+		return strings.Map(italicLetter, inner)
+	})
+}
+
+// replaceAll swaps the markers of the emphasis pattern matches for marker,
+// keeping the characters around them.
+func replaceAll(pattern *regexp.Regexp, text, marker string) string {
+	return replaceAllFunc(pattern, text, func(inner string) string {
+		return marker + inner + marker
+	})
+}
+
+// replaceAllFunc runs until nothing matches, since emphasis sharing a
+// space with the one before it, as in "_a_ _b_", isn't matched at first.
+func replaceAllFunc(pattern *regexp.Regexp, text string, replace func(inner string) string) string {
+	for range 3 {
+		replaced := pattern.ReplaceAllStringFunc(text, func(match string) string {
+			parts := pattern.FindStringSubmatch(match)
+			return parts[1] + replace(parts[2]) + parts[3]
+		})
+		if replaced == text {
+			break
+		}
+		text = replaced
+	}
+	return text
+}
+
+// boldLetter maps ASCII letters and digits to Mathematical Sans-Serif Bold.
+func boldLetter(r rune) rune {
+	switch {
+	case r >= 'A' && r <= 'Z':
+		return 0x1D5D4 + r - 'A'
+	case r >= 'a' && r <= 'z':
+		return 0x1D5EE + r - 'a'
+	case r >= '0' && r <= '9':
+		return 0x1D7EC + r - '0'
+	}
+	return r
+}
+
+// italicLetter maps ASCII letters to Mathematical Sans-Serif Italic, which
+// has no digits.
+func italicLetter(r rune) rune {
+	switch {
+	case r >= 'A' && r <= 'Z':
+		return 0x1D608 + r - 'A'
+	case r >= 'a' && r <= 'z':
+		return 0x1D622 + r - 'a'
+	}
+	return r
+}
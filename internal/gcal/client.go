@@ -0,0 +1,211 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const tokenURL = "https://oauth2.googleapis.com/token"
+
+// Client reads one Google Calendar with an OAuth refresh token that has
+// the calendar.readonly scope.
+type Client struct {
+	clientID     string
+	clientSecret string
+	refreshToken string
+	calendarID   string
+	awayKeywords []string
+	talkKeywords []string
+	httpClient   *http.Client
+	baseURL      string
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+type eventsResponse struct {
+	Items         []apiEvent `json:"items"`
+	NextPageToken string     `json:"nextPageToken"`
+}
+
+type apiEvent struct {
+	Summary   string       `json:"summary"`
+	Status    string       `json:"status"`
+	EventType string       `json:"eventType"`
+	Start     apiEventTime `json:"start"`
+	End       apiEventTime `json:"end"`
+}
+
+type apiEventTime struct {
+	Date     string    `json:"date"`
+	DateTime time.Time `json:"dateTime"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type apiError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewClient(clientID, clientSecret, refreshToken, calendarID string, awayKeywords, talkKeywords []string) *Client {
+	if clientID == "" || clientSecret == "" || refreshToken == "" {
+		slog.Error("GOOGLE_CLIENT_ID, GOOGLE_CLIENT_SECRET and GOOGLE_REFRESH_TOKEN are required")
+		os.Exit(1)
+	}
+
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	return &Client{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		refreshToken: refreshToken,
+		calendarID:   calendarID,
+		awayKeywords: awayKeywords,
+		talkKeywords: talkKeywords,
+		httpClient:   &http.Client{Timeout: 30 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		baseURL:      "https://www.googleapis.com/calendar/v3",
+	}
+}
+
+// Events returns the events between from and to that mark the author as
+// away or speaking, in start order. Other events are left out.
+func (c *Client) Events(ctx context.Context, from, to time.Time) ([]Event, error) {
+	var events []Event
+	pageToken := ""
+
+	for {
+		params := url.Values{}
+		params.Set("timeMin", from.Format(time.RFC3339))
+		params.Set("timeMax", to.Format(time.RFC3339))
+		params.Set("singleEvents", "true")
+		params.Set("orderBy", "startTime")
+		params.Set("maxResults", "250")
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		var resp eventsResponse
+		if err := c.get(ctx, "/calendars/"+url.PathEscape(c.calendarID)+"/events?"+params.Encode(), &resp); err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Items {
+			if item.Status == "cancelled" {
+				continue
+			}
+			if event, ok := c.classify(item); ok {
+				events = append(events, event)
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			return events, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (c *Client) get(ctx context.Context, path string, result any) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Google Calendar API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("Google Calendar API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("Google Calendar API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// token returns a cached access token, exchanging the refresh token for a
+// new one shortly before it expires.
+func (c *Client) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Now().Before(c.expiresAt.Add(-time.Minute)) {
+		return c.accessToken, nil
+	}
+
+	params := url.Values{}
+	params.Set("grant_type", "refresh_token")
+	params.Set("refresh_token", c.refreshToken)
+	params.Set("client_id", c.clientID)
+	params.Set("client_secret", c.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Google token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Google token endpoint error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result tokenResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	c.accessToken = result.AccessToken
+	c.expiresAt = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+
+	return c.accessToken, nil
+}
@@ -0,0 +1,89 @@
+package gcal
+
+import (
+	"strings"
+	"time"
+)
+
+// Kinds of calendar events the scheduler cares about.
+const (
+	// KindAway events block posting on every day they touch: out of office,
+	// vacations, conference travel.
+	KindAway = "away"
+	// KindTalk events are talks and events worth posting about right after.
+	KindTalk = "talk"
+)
+
+type Event struct {
+	Summary string
+	Kind    string
+	Start   time.Time
+	End     time.Time
+	AllDay  bool
+}
+
+// Days returns the dates, formatted as 2006-01-02 in location, that the
+// event touches.
+func (e Event) Days(location *time.Location) []string {
+	start := e.Start.In(location)
+	end := e.End.In(location)
+	if e.AllDay {
+		// All-day events are plain dates, parsed as UTC midnight, and end at
+		// midnight after their last day.
+		start = time.Date(e.Start.Year(), e.Start.Month(), e.Start.Day(), 0, 0, 0, 0, location)
+		end = time.Date(e.End.Year(), e.End.Month(), e.End.Day(), 0, 0, 0, 0, location).Add(-time.Nanosecond)
+	}
+
+	var days []string
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+	for !day.After(end) {
+		days = append(days, day.Format("2006-01-02"))
+		day = day.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+func (c *Client) classify(item apiEvent) (Event, bool) {
+	event := Event{Summary: item.Summary}
+
+	if item.Start.Date != "" {
+		start, err := time.Parse("2006-01-02", item.Start.Date)
+		if err != nil {
+			return Event{}, false
+		}
+		end, err := time.Parse("2006-01-02", item.End.Date)
+		if err != nil {
+			end = start.AddDate(0, 0, 1)
+		}
+		event.Start, event.End, event.AllDay = start, end, true
+	} else {
+		event.Start, event.End = item.Start.DateTime, item.End.DateTime
+	}
+
+	switch {
+	case item.EventType == "outOfOffice" || matchesAny(item.Summary, c.awayKeywords):
+		event.Kind = KindAway
+	case matchesAny(item.Summary, c.talkKeywords):
+		event.Kind = KindTalk
+	default:
+		return Event{}, false
+	}
+
+	return event, true
+}
+
+// matchesAny reports whether summary contains one of keywords as whole
+// words, ignoring case, so "ooo" matches "OOO - dentist" but not "Zoooom".
+func matchesAny(summary string, keywords []string) bool {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(summary), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 0x7f)
+	}), " ") + " "
+
+	for _, keyword := range keywords {
+		keyword = strings.Join(strings.Fields(strings.ToLower(keyword)), " ")
+		if keyword != "" && strings.Contains(words, " "+keyword+" ") {
+			return true
+		}
+	}
+	return false
+}
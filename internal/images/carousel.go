@@ -0,0 +1,127 @@
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	// Carousel pages are square, which LinkedIn shows at full width in the
+	// feed. Sizes are in points.
+	slideSize   = 540.0
+	slideMargin = 54.0
+)
+
+// CarouselRenderer turns carousel slides into a PDF document, one page per
+// slide, and stores it in dir.
+type CarouselRenderer struct {
+	dir string
+}
+
+func NewCarouselRenderer(dir string) (*CarouselRenderer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create carousel directory: %w", err)
+	}
+
+	return &CarouselRenderer{dir: dir}, nil
+}
+
+// Render writes the slides for postID to <dir>/<postID>.pdf and returns
+// the path.
+func (r *CarouselRenderer) Render(postID string, slides []string) (string, error) {
+	if len(slides) == 0 {
+		return "", fmt.Errorf("no slides to render")
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "pt",
+		Size:    fpdf.SizeType{Wd: slideSize, Ht: slideSize},
+	})
+	pdf.SetMargins(slideMargin, slideMargin, slideMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddUTF8FontFromBytes("go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("go", "B", gobold.TTF)
+
+	for i, slide := range slides {
+		r.renderSlide(pdf, slide, i == 0, fmt.Sprintf("%d / %d", i+1, len(slides)))
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return "", fmt.Errorf("failed to render carousel: %w", err)
+	}
+
+	path := filepath.Join(r.dir, postID+".pdf")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("failed to save carousel: %w", err)
+	}
+
+	return path, nil
+}
+
+// renderSlide draws one page. The first line of a slide is its title; the
+// cover slide is a single large title.
+func (r *CarouselRenderer) renderSlide(pdf *fpdf.Fpdf, slide string, cover bool, pageLabel string) {
+	pdf.AddPage()
+
+	pdf.SetFillColor(int(cardBackground.R), int(cardBackground.G), int(cardBackground.B))
+	pdf.Rect(0, 0, slideSize, slideSize, "F")
+	pdf.SetFillColor(int(cardAccent.R), int(cardAccent.G), int(cardAccent.B))
+	pdf.Rect(slideMargin, slideMargin-18, 80, 6, "F")
+
+	pdf.SetTextColor(int(cardText.R), int(cardText.G), int(cardText.B))
+	width := slideSize - 2*slideMargin
+
+	title, body, _ := strings.Cut(strings.TrimSpace(slide), "\n")
+	if cover {
+		title, body = strings.Join(strings.Fields(slide), " "), ""
+	}
+
+	titleSize := 26.0
+	if cover {
+		titleSize = 38.0
+	}
+
+	pdf.SetFont("go", "B", titleSize)
+	titleLines := pdf.SplitText(title, width)
+	pdf.SetFont("go", "", 18)
+	bodyLines := pdf.SplitText(strings.TrimSpace(body), width)
+
+	titleHeight := float64(len(titleLines)) * titleSize * 1.25
+	bodyHeight := float64(len(bodyLines)) * 18 * 1.4
+	gap := 0.0
+	if len(bodyLines) > 0 {
+		gap = 18
+	}
+
+	y := (slideSize - titleHeight - gap - bodyHeight) / 2
+	if y < slideMargin {
+		y = slideMargin
+	}
+
+	pdf.SetFont("go", "B", titleSize)
+	for _, line := range titleLines {
+		pdf.SetXY(slideMargin, y)
+		pdf.CellFormat(width, titleSize*1.25, line, "", 0, "L", false, 0, "")
+		y += titleSize * 1.25
+	}
+
+	y += gap
+	pdf.SetFont("go", "", 18)
+	for _, line := range bodyLines {
+		pdf.SetXY(slideMargin, y)
+		pdf.CellFormat(width, 18*1.4, line, "", 0, "L", false, 0, "")
+		y += 18 * 1.4
+	}
+
+	pdf.SetFont("go", "", 12)
+	pdf.SetXY(slideMargin, slideSize-slideMargin)
+	pdf.CellFormat(width, 14, pageLabel, "", 0, "R", false, 0, "")
+}
@@ -0,0 +1,39 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+)
+
+// Generator turns an image concept into PNG bytes.
+type Generator interface {
+	Name() string
+	Generate(ctx context.Context, concept *agents.ImageConcept) ([]byte, error)
+}
+
+// NewGenerator returns the generator for provider, or nil when the image
+// pipeline is disabled.
+func NewGenerator(provider, openAIKey, openAIBaseURL, model string) (Generator, error) {
+	switch strings.ToLower(provider) {
+	case "", "off":
+		return nil, nil
+
+	case "quote-card":
+		return NewQuoteCard()
+
+	case "openai":
+		if openAIKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is required for the openai image provider")
+		}
+		httpClient := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+		return NewOpenAIGenerator(openAIKey, openAIBaseURL, model, httpClient), nil
+	}
+
+	return nil, fmt.Errorf("unknown image provider: %s", provider)
+}
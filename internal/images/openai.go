@@ -0,0 +1,153 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+)
+
+const (
+	defaultOpenAIImageModel = "gpt-image-1"
+	defaultOpenAIBaseURL    = "https://api.openai.com/v1"
+)
+
+// OpenAIGenerator calls the OpenAI images API, or any server that
+// implements the same endpoint.
+type OpenAIGenerator struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+type openAIImageRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	N      int    `json:"n"`
+	Size   string `json:"size"`
+}
+
+type openAIImageResponse struct {
+	Data []struct {
+		B64JSON string `json:"b64_json"`
+		URL     string `json:"url"`
+	} `json:"data"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func NewOpenAIGenerator(apiKey, baseURL, model string, httpClient *http.Client) *OpenAIGenerator {
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	if model == "" {
+		model = defaultOpenAIImageModel
+	}
+
+	return &OpenAIGenerator{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		model:      model,
+		httpClient: httpClient,
+	}
+}
+
+func (g *OpenAIGenerator) Name() string {
+	return "openai"
+}
+
+func (g *OpenAIGenerator) Generate(ctx context.Context, concept *agents.ImageConcept) ([]byte, error) {
+	reqBody := openAIImageRequest{
+		Model:  g.model,
+		Prompt: concept.Concept,
+		N:      1,
+		Size:   "1024x1024",
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", g.baseURL+"/images/generations", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+g.apiKey)
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call OpenAI images API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var apiResp openAIImageResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if apiResp.Error != nil {
+		return nil, fmt.Errorf("OpenAI images API error (status %d): %s", resp.StatusCode, apiResp.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenAI images API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if len(apiResp.Data) == 0 {
+		return nil, fmt.Errorf("no image returned")
+	}
+
+	if data := apiResp.Data[0].B64JSON; data != "" {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+		return decoded, nil
+	}
+
+	if apiResp.Data[0].URL != "" {
+		return g.download(ctx, apiResp.Data[0].URL)
+	}
+
+	return nil, fmt.Errorf("unexpected response format")
+}
+
+func (g *OpenAIGenerator) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,125 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// pipelineBatchSize bounds how many posts one tick works on, so a backlog
+// of approvals doesn't hold the image API for minutes.
+const pipelineBatchSize = 5
+
+// Pipeline proposes a visual for every approved post and stores the
+// rendered image next to the post record, where the LinkedIn publisher
+// picks it up.
+type Pipeline struct {
+	agent     *agents.ImageAgent
+	generator Generator
+	fallback  *QuoteCard
+	postRepo  *database.PostRepository
+	dir       string
+	interval  time.Duration
+}
+
+func NewPipeline(agent *agents.ImageAgent, generator Generator, postRepo *database.PostRepository, dir string, interval time.Duration) (*Pipeline, error) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create image directory: %w", err)
+	}
+
+	fallback, ok := generator.(*QuoteCard)
+	if !ok {
+		var err error
+		if fallback, err = NewQuoteCard(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Pipeline{
+		agent:     agent,
+		generator: generator,
+		fallback:  fallback,
+		postRepo:  postRepo,
+		dir:       dir,
+		interval:  interval,
+	}, nil
+}
+
+func (p *Pipeline) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "image pipeline started", "generator", p.generator.Name(), "interval", p.interval)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.processPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "image pipeline stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Pipeline) processPending(ctx context.Context) {
+	posts, err := p.postRepo.GetAwaitingImage(ctx, pipelineBatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch posts awaiting image", "error", err)
+		return
+	}
+
+	for _, post := range posts {
+		if ctx.Err() != nil {
+			return
+		}
+		p.process(ctx, post)
+	}
+}
+
+func (p *Pipeline) process(ctx context.Context, post *models.Post) {
+	concept, err := p.agent.ProposeConcept(ctx, post.Content)
+	if err != nil {
+		// Left without a concept, the post is retried on the next tick.
+		slog.ErrorContext(ctx, "failed to propose image concept", "post_id", post.ID, "error", err)
+		return
+	}
+
+	data, err := p.generator.Generate(ctx, concept)
+	if err != nil && p.generator != Generator(p.fallback) {
+		slog.WarnContext(ctx, "image generation failed, falling back to quote card", "post_id", post.ID, "generator", p.generator.Name(), "error", err)
+		data, err = p.fallback.Generate(ctx, concept)
+	}
+
+	var path string
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to render image", "post_id", post.ID, "error", err)
+	} else {
+		path = filepath.Join(p.dir, post.ID+".png")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			slog.ErrorContext(ctx, "failed to save image", "post_id", post.ID, "error", err)
+			path = ""
+		}
+	}
+
+	// The concept is stored even without an image so the post isn't
+	// retried forever; it still publishes as text.
+	if err := p.postRepo.SetImage(ctx, post.ID, concept.Concept, path, concept.AltText); err != nil {
+		slog.ErrorContext(ctx, "failed to attach image to post", "post_id", post.ID, "error", err)
+		return
+	}
+
+	slog.InfoContext(ctx, "attached image to post", "post_id", post.ID, "path", path)
+}
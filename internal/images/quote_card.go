@@ -0,0 +1,130 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+)
+
+const (
+	cardSize    = 1200
+	cardMargin  = 120
+	maxFontSize = 72
+	minFontSize = 36
+)
+
+var (
+	cardBackground = color.RGBA{R: 0x0a, G: 0x24, B: 0x3d, A: 0xff}
+	cardAccent     = color.RGBA{R: 0x0a, G: 0x66, B: 0xc2, A: 0xff}
+	cardText       = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+// QuoteCard renders the concept's hook as large text on a plain square
+// card. It needs no external service, so it is also the fallback when an
+// image model fails.
+type QuoteCard struct {
+	font *opentype.Font
+}
+
+func NewQuoteCard() (*QuoteCard, error) {
+	f, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+
+	return &QuoteCard{font: f}, nil
+}
+
+func (q *QuoteCard) Name() string {
+	return "quote-card"
+}
+
+func (q *QuoteCard) Generate(ctx context.Context, concept *agents.ImageConcept) ([]byte, error) {
+	hook := strings.TrimSpace(concept.Hook)
+	if hook == "" {
+		return nil, fmt.Errorf("no hook to render")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, cardSize, cardSize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: cardBackground}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(cardMargin, cardMargin-40, cardMargin+160, cardMargin-28), &image.Uniform{C: cardAccent}, image.Point{}, draw.Src)
+
+	face, lines, err := q.fit(hook)
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	lineHeight := face.Metrics().Height.Ceil() * 5 / 4
+	y := (cardSize-lineHeight*len(lines))/2 + face.Metrics().Ascent.Ceil()
+
+	drawer := &font.Drawer{Dst: img, Src: &image.Uniform{C: cardText}, Face: face}
+	for _, line := range lines {
+		drawer.Dot = fixed.P(cardMargin, y)
+		drawer.DrawString(line)
+		y += lineHeight
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// fit picks the largest font size at which the wrapped hook fits inside
+// the card's margins.
+func (q *QuoteCard) fit(text string) (font.Face, []string, error) {
+	width := cardSize - 2*cardMargin
+	height := cardSize - 2*cardMargin
+
+	for size := maxFontSize; ; size -= 4 {
+		face, err := opentype.NewFace(q.font, &opentype.FaceOptions{Size: float64(size), DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create font face: %w", err)
+		}
+
+		lines := wrap(face, text, width)
+		lineHeight := face.Metrics().Height.Ceil() * 5 / 4
+		if lineHeight*len(lines) <= height || size <= minFontSize {
+			return face, lines, nil
+		}
+		face.Close()
+	}
+}
+
+func wrap(face font.Face, text string, width int) []string {
+	var lines []string
+	var current string
+
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && font.MeasureString(face, candidate).Ceil() > width {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current = candidate
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
@@ -0,0 +1,286 @@
+// Package importer seeds the bot with notes exported from elsewhere. Imports
+// run as jobs in the job queue, categorizing each note at a limited rate so
+// a large export doesn't use up the LLM quota. An import stopped by a
+// restart carries on from where it was.
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/queue"
+)
+
+// ThoughtSource marks imported thoughts. Their external ID is a hash of the
+// content, so importing the same notes twice skips the ones already in.
+const ThoughtSource = "import"
+
+// progressEvery is how many notes pass between progress reports.
+const progressEvery = 25
+
+// Reporter is told about a job when it starts, every few notes and when
+// it ends.
+type Reporter func(ctx context.Context, job *models.ImportJob)
+
+// payload is an import waiting in the job queue.
+type payload struct {
+	ImportJobID string   `json:"import_job_id"`
+	Records     []Record `json:"records"`
+}
+
+type Importer struct {
+	thoughtRepo  *database.ThoughtRepository
+	jobRepo      *database.ImportJobRepository
+	categoryRepo *database.CategoryRepository
+	categorizer  *agents.CategorizerAgent
+	embeddings   *agents.EmbeddingAgent
+	jobs         *queue.Queue
+	interval     time.Duration
+}
+
+// NewImporter categorizes at most perMinute notes a minute, or without a
+// limit when perMinute is zero. embeddings may be nil.
+func NewImporter(
+	thoughtRepo *database.ThoughtRepository,
+	jobRepo *database.ImportJobRepository,
+	categoryRepo *database.CategoryRepository,
+	categorizer *agents.CategorizerAgent,
+	embeddings *agents.EmbeddingAgent,
+	jobs *queue.Queue,
+	perMinute int,
+) *Importer {
+	var interval time.Duration
+	if perMinute > 0 {
+		interval = time.Minute / time.Duration(perMinute)
+	}
+
+	return &Importer{
+		thoughtRepo:  thoughtRepo,
+		jobRepo:      jobRepo,
+		categoryRepo: categoryRepo,
+		categorizer:  categorizer,
+		embeddings:   embeddings,
+		jobs:         jobs,
+		interval:     interval,
+	}
+}
+
+// Submit saves a job for records and queues it.
+func (i *Importer) Submit(ctx context.Context, job *models.ImportJob, records []Record) error {
+	job.Total = len(records)
+
+	if err := i.jobRepo.Create(ctx, job); err != nil {
+		return err
+	}
+
+	if _, err := i.jobs.Enqueue(ctx, models.JobImport, "import:"+job.ID, payload{ImportJobID: job.ID, Records: records}); err != nil {
+		job.Status, job.Error = models.ImportFailed, "couldn't queue the job"
+		i.finish(ctx, job)
+		return err
+	}
+
+	return nil
+}
+
+// Handler runs queued imports, telling report about their progress. report
+// may be nil.
+func (i *Importer) Handler(report Reporter) queue.Handler {
+	return func(ctx context.Context, queued *models.Job) error {
+		var p payload
+		if err := queue.Decode(queued, &p); err != nil {
+			return err
+		}
+
+		job, err := i.jobRepo.GetByID(ctx, p.ImportJobID)
+		if err != nil {
+			return fmt.Errorf("failed to load import job %s: %w", p.ImportJobID, err)
+		}
+		if job.Done() {
+			return nil
+		}
+
+		i.run(ctx, job, p.Records, func() {
+			if report != nil {
+				report(context.WithoutCancel(ctx), job)
+			}
+		})
+
+		// An import the bot stopped goes back in the queue.
+		return ctx.Err()
+	}
+}
+
+// run imports records, starting after the ones an earlier run got through.
+func (i *Importer) run(ctx context.Context, job *models.ImportJob, records []Record, report func()) {
+	start := min(job.Processed(), len(records))
+	if start > 0 {
+		slog.InfoContext(ctx, "import resumed", "job_id", job.ID, "file", job.Filename, "from", start+1, "notes", job.Total)
+	} else {
+		slog.InfoContext(ctx, "import started", "job_id", job.ID, "file", job.Filename, "notes", job.Total)
+	}
+
+	job.Status = models.ImportRunning
+	if err := i.jobRepo.UpdateProgress(ctx, job); err != nil {
+		slog.ErrorContext(ctx, "failed to update import job", "job_id", job.ID, "error", err)
+	}
+	report()
+
+	categories, err := i.categoryRepo.List(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			i.interrupt(ctx, job, report)
+			return
+		}
+		job.Status, job.Error = models.ImportFailed, err.Error()
+		i.finish(ctx, job)
+		report()
+		return
+	}
+	known := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		known[category.Name] = true
+	}
+
+	var throttle <-chan time.Time
+	if i.interval > 0 {
+		ticker := time.NewTicker(i.interval)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	for n := start; n < len(records); n++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		imported, err := i.ingest(ctx, job, records[n], known, throttle)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// Stopped mid-note: it is done again when the import resumes.
+			continue
+		case err != nil:
+			slog.ErrorContext(ctx, "failed to import note", "job_id", job.ID, "note", n+1, "error", err)
+			job.Failed++
+		case imported:
+			job.Imported++
+		default:
+			job.Skipped++
+		}
+
+		if (n+1)%progressEvery == 0 && n+1 < len(records) {
+			if err := i.jobRepo.UpdateProgress(ctx, job); err != nil {
+				slog.ErrorContext(ctx, "failed to update import job", "job_id", job.ID, "error", err)
+			}
+			report()
+		}
+	}
+
+	if ctx.Err() != nil {
+		i.interrupt(ctx, job, report)
+		return
+	}
+
+	job.Status = models.ImportCompleted
+	i.finish(ctx, job)
+	report()
+
+	slog.InfoContext(ctx, "import finished", "job_id", job.ID, "status", job.Status,
+		"imported", job.Imported, "skipped", job.Skipped, "failed", job.Failed)
+}
+
+// interrupt saves how far a job the bot stopped got, so it can resume from
+// there.
+func (i *Importer) interrupt(ctx context.Context, job *models.ImportJob, report func()) {
+	job.Status = models.ImportInterrupted
+	if err := i.jobRepo.UpdateProgress(context.WithoutCancel(ctx), job); err != nil {
+		slog.ErrorContext(ctx, "failed to update import job", "job_id", job.ID, "error", err)
+	}
+	report()
+
+	slog.InfoContext(ctx, "import interrupted", "job_id", job.ID, "processed", job.Processed(), "notes", job.Total)
+}
+
+// ingest saves one note as a thought. It returns false without an error
+// when the note was imported before. Notes that name a known category keep
+// it; the others wait their turn for the categorizer.
+func (i *Importer) ingest(ctx context.Context, job *models.ImportJob, record Record, known map[string]bool, throttle <-chan time.Time) (bool, error) {
+	hash := sha256.Sum256([]byte(record.Content))
+	externalID := hex.EncodeToString(hash[:16])
+
+	exists, err := i.thoughtRepo.ExistsByExternalID(ctx, ThoughtSource, externalID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	thought := models.NewThought(record.Content, ThoughtSource)
+	thought.ExternalID = externalID
+	thought.SlackChannelID = job.SlackChannelID
+	if !record.Date.IsZero() {
+		thought.Timestamp = record.Date
+	}
+
+	if category := models.NormalizeCategory(record.Category); known[category] {
+		thought.Category = category
+		thought.TopicTags = record.Tags
+		if len(thought.TopicTags) == 0 {
+			thought.TopicTags = []string{"general"}
+		}
+	} else {
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case <-throttle:
+			}
+		}
+
+		if err := i.categorizer.CategorizeThought(ctx, thought); err != nil {
+			if ctx.Err() != nil {
+				return false, err
+			}
+			slog.WarnContext(ctx, "failed to categorize imported note", "job_id", job.ID, "error", err)
+			thought.Category = "uncategorized"
+			thought.TopicTags = []string{"general"}
+		}
+		if len(record.Tags) > 0 {
+			thought.TopicTags = record.Tags
+		}
+	}
+
+	if i.embeddings != nil {
+		if _, err := i.embeddings.Match(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "failed to match thought embeddings", "error", err)
+		}
+	}
+
+	if err := i.thoughtRepo.Create(ctx, thought); err != nil {
+		return false, fmt.Errorf("failed to save thought: %w", err)
+	}
+
+	if i.embeddings != nil {
+		if err := i.embeddings.Link(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "failed to store thought embedding", "error", err)
+		}
+	}
+
+	return true, nil
+}
+
+func (i *Importer) finish(ctx context.Context, job *models.ImportJob) {
+	now := time.Now()
+	job.FinishedAt = &now
+
+	if err := i.jobRepo.UpdateProgress(context.WithoutCancel(ctx), job); err != nil {
+		slog.ErrorContext(ctx, "failed to update import job", "job_id", job.ID, "error", err)
+	}
+}
@@ -0,0 +1,229 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Limits on a single import; bigger exports can be split up.
+const (
+	MaxFileSize = 5 << 20
+	MaxRecords  = 5000
+)
+
+// Record is one note from an export.
+type Record struct {
+	Content string `json:"content"`
+	// Date is when the note was written, or zero when the export has none.
+	Date     time.Time `json:"date,omitzero"`
+	Category string    `json:"category,omitempty"`
+	Tags     []string  `json:"tags,omitempty"`
+}
+
+// Columns (CSV) and keys (JSON) recognized in exports, matched ignoring
+// case. A title, such as a Notion page name, goes above the content.
+var (
+	contentKeys  = []string{"content", "text", "note", "notes", "thought", "body", "idea", "description"}
+	titleKeys    = []string{"title", "name"}
+	dateKeys     = []string{"date", "timestamp", "created", "created_at", "created time", "time"}
+	categoryKeys = []string{"category"}
+	tagKeys      = []string{"tags", "topic_tags", "labels"}
+)
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006 15:04",
+	"01/02/2006",
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006",
+}
+
+// Parse reads notes from a CSV file with a header row, or from a JSON array
+// of strings or objects. name picks the format by extension; without a
+// known one the content is sniffed. Notes with no text are left out.
+func Parse(data []byte, name string) ([]Record, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var records []Record
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(name)); {
+	case ext == ".json":
+		records, err = parseJSON(data)
+	case ext == ".csv":
+		records, err = parseCSV(data)
+	case bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")):
+		records, err = parseJSON(data)
+	default:
+		records, err = parseCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no notes found")
+	}
+	if len(records) > MaxRecords {
+		return nil, fmt.Errorf("%d notes is more than the %d an import can take, split the file up", len(records), MaxRecords)
+	}
+
+	return records, nil
+}
+
+func parseCSV(data []byte) ([]Record, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("CSV needs a header row and at least one note")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+
+	content, title := column(columns, contentKeys), column(columns, titleKeys)
+	if content < 0 && title < 0 {
+		return nil, fmt.Errorf("CSV has no content column, name one of: %s", strings.Join(slices.Concat(contentKeys, titleKeys), ", "))
+	}
+	date, category, tags := column(columns, dateKeys), column(columns, categoryKeys), column(columns, tagKeys)
+
+	var records []Record
+	for _, row := range rows[1:] {
+		field := func(i int) string {
+			if i < 0 || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		record := Record{
+			Content:  joinTitle(field(title), field(content)),
+			Date:     parseDate(field(date)),
+			Category: field(category),
+			Tags:     splitTags(field(tags)),
+		}
+		if record.Content != "" {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func parseJSON(data []byte) ([]Record, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("JSON must be an array of notes: %w", err)
+	}
+
+	var records []Record
+	for i, item := range items {
+		var text string
+		if err := json.Unmarshal(item, &text); err == nil {
+			if text = strings.TrimSpace(text); text != "" {
+				records = append(records, Record{Content: text})
+			}
+			continue
+		}
+
+		var raw map[string]any
+		if err := json.Unmarshal(item, &raw); err != nil {
+			return nil, fmt.Errorf("note %d is neither a string nor an object", i+1)
+		}
+
+		fields := make(map[string]any, len(raw))
+		for key, value := range raw {
+			fields[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+
+		record := Record{
+			Content:  joinTitle(stringField(fields, titleKeys), stringField(fields, contentKeys)),
+			Date:     parseDate(stringField(fields, dateKeys)),
+			Category: stringField(fields, categoryKeys),
+		}
+		for _, key := range tagKeys {
+			switch value := fields[key].(type) {
+			case string:
+				record.Tags = splitTags(value)
+			case []any:
+				for _, tag := range value {
+					if s, ok := tag.(string); ok {
+						record.Tags = append(record.Tags, splitTags(s)...)
+					}
+				}
+			}
+			if len(record.Tags) > 0 {
+				break
+			}
+		}
+
+		if record.Content != "" {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func column(columns map[string]int, keys []string) int {
+	for _, key := range keys {
+		if i, ok := columns[key]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+func stringField(fields map[string]any, keys []string) string {
+	for _, key := range keys {
+		if value, ok := fields[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func joinTitle(title, content string) string {
+	if title == "" || strings.HasPrefix(content, title) {
+		return content
+	}
+	return strings.TrimSpace(title + "\n\n" + content)
+}
+
+// parseDate reads the common export date formats, returning zero for an
+// empty or unrecognized value.
+func parseDate(value string) time.Time {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' || r == '|' }) {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,163 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/digest"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// Prompter asks in Slack whether stale drafts should be kept or discarded.
+type Prompter interface {
+	PromptStaleDrafts(ctx context.Context, channelID string, drafts []*models.Post, staleDays int) error
+}
+
+// Janitor runs once a week: it flags drafts that have sat untouched for
+// staleDays as stale, asks whether to keep or discard them, archives
+// rejected posts and brainstorm sessions nothing came of after archiveDays,
+// and empties thoughts and posts out of the trash after trashDays.
+type Janitor struct {
+	thoughtRepo *database.ThoughtRepository
+	postRepo    *database.PostRepository
+	archiveRepo *database.ArchiveRepository
+	prompter    Prompter
+	channel     string
+	schedule    digest.Schedule
+	location    *time.Location
+	staleDays   int
+	archiveDays int
+	trashDays   int
+}
+
+type Result struct {
+	Flagged          int
+	Stale            int
+	ArchivedPosts    int
+	ArchivedSessions int
+	PurgedThoughts   int
+	PurgedPosts      int
+}
+
+func NewJanitor(
+	thoughtRepo *database.ThoughtRepository,
+	postRepo *database.PostRepository,
+	archiveRepo *database.ArchiveRepository,
+	prompter Prompter,
+	channel string,
+	schedule digest.Schedule,
+	location *time.Location,
+	staleDays int,
+	archiveDays int,
+	trashDays int,
+) *Janitor {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &Janitor{
+		thoughtRepo: thoughtRepo,
+		postRepo:    postRepo,
+		archiveRepo: archiveRepo,
+		prompter:    prompter,
+		channel:     channel,
+		schedule:    schedule,
+		location:    location,
+		staleDays:   staleDays,
+		archiveDays: archiveDays,
+		trashDays:   trashDays,
+	}
+}
+
+func (j *Janitor) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "janitor started", "schedule", j.schedule, "stale_days", j.staleDays, "archive_days", j.archiveDays, "trash_days", j.trashDays)
+
+	for {
+		next := j.schedule.Next(time.Now(), j.location)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.InfoContext(ctx, "janitor stopped")
+			return
+		case <-timer.C:
+		}
+
+		result, err := j.Run(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "janitor run failed", "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "janitor run completed", "flagged", result.Flagged, "stale", result.Stale,
+			"archived_posts", result.ArchivedPosts, "archived_sessions", result.ArchivedSessions,
+			"purged_thoughts", result.PurgedThoughts, "purged_posts", result.PurgedPosts)
+	}
+}
+
+// Run does one cleanup pass. A zero staleDays, archiveDays or trashDays
+// skips that part.
+func (j *Janitor) Run(ctx context.Context) (*Result, error) {
+	result := &Result{}
+	now := time.Now()
+
+	if j.trashDays > 0 {
+		cutoff := now.AddDate(0, 0, -j.trashDays)
+
+		thoughts, err := j.thoughtRepo.PurgeDeleted(ctx, cutoff)
+		if err != nil {
+			return result, err
+		}
+		result.PurgedThoughts = thoughts
+
+		posts, err := j.postRepo.PurgeDeleted(ctx, cutoff)
+		if err != nil {
+			return result, err
+		}
+		result.PurgedPosts = posts
+	}
+
+	if j.archiveDays > 0 {
+		cutoff := now.AddDate(0, 0, -j.archiveDays)
+
+		posts, err := j.archiveRepo.ArchiveRejectedPosts(ctx, cutoff)
+		if err != nil {
+			return result, err
+		}
+		result.ArchivedPosts = posts
+
+		// Archiving posts first frees the sessions only they referenced.
+		sessions, err := j.archiveRepo.ArchiveOrphanedBrainstorms(ctx, cutoff)
+		if err != nil {
+			return result, err
+		}
+		result.ArchivedSessions = sessions
+	}
+
+	if j.staleDays <= 0 {
+		return result, nil
+	}
+
+	flagged, err := j.postRepo.MarkStale(ctx, now.AddDate(0, 0, -j.staleDays))
+	if err != nil {
+		return result, err
+	}
+	result.Flagged = flagged
+
+	stale, err := j.postRepo.GetByStatus(ctx, "stale")
+	if err != nil {
+		return result, fmt.Errorf("failed to get stale drafts: %w", err)
+	}
+	result.Stale = len(stale)
+
+	if len(stale) > 0 && j.prompter != nil && j.channel != "" {
+		if err := j.prompter.PromptStaleDrafts(ctx, j.channel, stale, j.staleDays); err != nil {
+			return result, fmt.Errorf("failed to prompt about stale drafts: %w", err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,177 @@
+// Package journal copies every captured thought into daily notes outside
+// the bot, such as an Obsidian vault or a Notion database, so a second
+// brain kept there stays in sync with what the bot has taken in.
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+)
+
+// exportedSettingPrefix followed by a sink's name stores when the last
+// thought appended to that sink was captured.
+const exportedSettingPrefix = "journal.exported."
+
+// batchSize caps how many thoughts one run reads at a time.
+const batchSize = 100
+
+// Sink appends entries to the daily note of day, creating the note when
+// it doesn't exist yet.
+type Sink interface {
+	Name() string
+	Append(ctx context.Context, day time.Time, entries []string) error
+}
+
+// Exporter appends each thought captured since its last run to the daily
+// note of the day it was captured, in every sink. Each sink keeps its own
+// place, so one that fails catches up on the next run without the others
+// getting entries twice.
+type Exporter struct {
+	thoughtRepo *database.ThoughtRepository
+	settings    *database.SettingsRepository
+	sinks       []Sink
+	location    *time.Location
+	interval    time.Duration
+}
+
+func NewExporter(thoughtRepo *database.ThoughtRepository, settings *database.SettingsRepository, sinks []Sink, location *time.Location, interval time.Duration) *Exporter {
+	if location == nil {
+		location = time.UTC
+	}
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	return &Exporter{
+		thoughtRepo: thoughtRepo,
+		settings:    settings,
+		sinks:       sinks,
+		location:    location,
+		interval:    interval,
+	}
+}
+
+func (e *Exporter) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "journal export started", "sinks", len(e.sinks), "interval", e.interval)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Run(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "journal export failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "journal export stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run brings every sink up to date.
+func (e *Exporter) Run(ctx context.Context) error {
+	var errs []error
+	for _, sink := range e.sinks {
+		if err := e.export(ctx, sink); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// export appends the thoughts sink hasn't had yet. A sink starts from the
+// first run it is part of, rather than copying the whole archive.
+func (e *Exporter) export(ctx context.Context, sink Sink) error {
+	key := exportedSettingPrefix + sink.Name()
+	value, err := e.settings.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return e.settings.Set(ctx, key, time.Now().UTC().Format(time.RFC3339Nano), "")
+	}
+
+	since, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+
+	exported := 0
+	for {
+		captured, err := e.thoughtRepo.GetCapturedAfter(ctx, since, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(captured) == 0 {
+			break
+		}
+
+		// Thoughts come oldest first, so each day's entries are appended
+		// together and the place only moves past days that made it.
+		for len(captured) > 0 {
+			day := e.day(captured[0].CapturedAt)
+			var entries []string
+			last := since
+			for len(captured) > 0 && e.day(captured[0].CapturedAt).Equal(day) {
+				entries = append(entries, Entry(captured[0], e.location))
+				last = captured[0].CapturedAt
+				captured = captured[1:]
+			}
+
+			if err := sink.Append(ctx, day, entries); err != nil {
+				return err
+			}
+			since = last
+			exported += len(entries)
+			if err := e.settings.Set(ctx, key, since.UTC().Format(time.RFC3339Nano), ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	if exported > 0 {
+		slog.InfoContext(ctx, "exported thoughts to daily notes", "sink", sink.Name(), "thoughts", exported)
+	}
+	return nil
+}
+
+// day returns the midnight that starts the day t falls on.
+func (e *Exporter) day(t time.Time) time.Time {
+	year, month, day := t.In(e.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, e.location)
+}
+
+// Entry formats a captured thought as one Markdown list item, such as
+// "- 14:05 Shipped offline mode #product_update #mobile (slack)". Line
+// breaks in the thought are folded, so the item stays on one line.
+func Entry(captured *database.CapturedThought, location *time.Location) string {
+	thought := captured.Thought
+
+	var b strings.Builder
+	b.WriteString("- ")
+	b.WriteString(captured.CapturedAt.In(location).Format("15:04"))
+	b.WriteString(" ")
+	b.WriteString(strings.Join(strings.Fields(thought.Content), " "))
+	if thought.Category != "" {
+		b.WriteString(" #" + thought.Category)
+	}
+	for _, tag := range thought.TopicTags {
+		if tag = strings.Join(strings.Fields(tag), "-"); tag != "" && tag != thought.Category {
+			b.WriteString(" #" + tag)
+		}
+	}
+	fmt.Fprintf(&b, " (%s)", thought.Source)
+	if thought.SourceURL != "" {
+		fmt.Fprintf(&b, " %s", thought.SourceURL)
+	}
+	return b.String()
+}
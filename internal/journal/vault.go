@@ -0,0 +1,63 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Vault appends to the Markdown daily notes in a folder of an Obsidian
+// vault, or any folder of Markdown files, named like 2026-03-14.md as
+// Obsidian names them by default.
+type Vault struct {
+	dir string
+}
+
+// NewVault checks that dir is a folder the bot can write to.
+func NewVault(dir string) (*Vault, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vault folder: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("vault path %s is not a folder", dir)
+	}
+	return &Vault{dir: dir}, nil
+}
+
+func (v *Vault) Name() string {
+	return "vault"
+}
+
+// Append adds entries to the end of the day's note. A note that doesn't
+// end in a line break gets one first, so the entries start a new line.
+func (v *Vault) Append(ctx context.Context, day time.Time, entries []string) error {
+	path := filepath.Join(v.dir, day.Format("2006-01-02")+".md")
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read daily note: %w", err)
+	}
+
+	text := strings.Join(entries, "\n") + "\n"
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		text = "\n" + text
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open daily note: %w", err)
+	}
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write daily note: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write daily note: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+// Package leader picks one replica of the bot to run the scheduled work,
+// such as queueing due posts, the digest and the cleanup job, so running
+// several replicas doesn't publish or send anything twice. The leader is
+// the replica holding a Postgres advisory lock; when it dies its session
+// ends, the lock is freed and another replica takes over.
+package leader
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// lockID keys the advisory lock held by the leader.
+	lockID = 7_253_119_005
+	// checkInterval is how often a follower tries to take the lock and the
+	// leader checks that its session is still alive.
+	checkInterval = 10 * time.Second
+)
+
+type Elector struct {
+	pool *pgxpool.Pool
+
+	mu sync.Mutex
+	// term is cancelled when this replica stops leading; nil while it
+	// follows.
+	term context.Context
+	// elected is closed when this replica next becomes the leader.
+	elected chan struct{}
+}
+
+func NewElector(pool *pgxpool.Pool) *Elector {
+	return &Elector{
+		pool:    pool,
+		elected: make(chan struct{}),
+	}
+}
+
+// Start campaigns for leadership until ctx is cancelled, then steps down.
+func (e *Elector) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "leader election started", "interval", checkInterval)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if conn := e.campaign(ctx); conn != nil {
+			e.lead(ctx, conn, ticker)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign tries to take the lock on a connection of its own, which holds
+// it for as long as this replica leads. It returns nil when another replica
+// leads.
+func (e *Elector) campaign(ctx context.Context) *pgxpool.Conn {
+	conn, err := e.pool.Acquire(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to acquire connection for leader election", "error", err)
+		}
+		return nil
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, lockID).Scan(&locked); err != nil || !locked {
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to try leader lock", "error", err)
+		}
+		conn.Release()
+		return nil
+	}
+
+	return conn
+}
+
+// lead runs a term: work waiting in Run starts, and this replica checks
+// that it still holds the lock until it loses its session or ctx is
+// cancelled.
+func (e *Elector) lead(ctx context.Context, conn *pgxpool.Conn, ticker *time.Ticker) {
+	term, end := context.WithCancel(ctx)
+
+	e.mu.Lock()
+	e.term = term
+	close(e.elected)
+	e.mu.Unlock()
+
+	slog.InfoContext(ctx, "became leader")
+
+	defer func() {
+		end()
+
+		e.mu.Lock()
+		e.term = nil
+		e.elected = make(chan struct{})
+		e.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID); err != nil {
+				slog.ErrorContext(ctx, "failed to release leader lock", "error", err)
+				conn.Conn().Close(context.Background())
+			}
+			conn.Release()
+			slog.InfoContext(ctx, "stepped down as leader")
+			return
+
+		case <-ticker.C:
+			if err := conn.Ping(ctx); err != nil && ctx.Err() == nil {
+				// The session, and with it the lock, may be gone. Closing
+				// the connection makes sure of it before another replica
+				// can take over.
+				slog.ErrorContext(ctx, "lost leader session", "error", err)
+				end()
+				conn.Conn().Close(context.Background())
+				conn.Release()
+				return
+			}
+		}
+	}
+}
+
+// await blocks until this replica leads and returns the term's context, or
+// returns nil when ctx is cancelled first.
+func (e *Elector) await(ctx context.Context) context.Context {
+	for {
+		e.mu.Lock()
+		term, elected := e.term, e.elected
+		e.mu.Unlock()
+
+		if term != nil && term.Err() == nil {
+			return term
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-elected:
+		}
+	}
+}
+
+// Run runs work whenever this replica leads, until ctx is cancelled. work
+// is stopped when the replica loses leadership and started again when it
+// regains it. Work that returns on its own, such as a one-off backfill, is
+// not started again.
+func (e *Elector) Run(ctx context.Context, name string, work func(ctx context.Context)) {
+	for {
+		term := e.await(ctx)
+		if term == nil {
+			return
+		}
+
+		slog.InfoContext(ctx, "leading scheduled work", "work", name)
+		work(term)
+
+		if ctx.Err() != nil || term.Err() == nil {
+			return
+		}
+		slog.InfoContext(ctx, "paused scheduled work until leading again", "work", name)
+	}
+}
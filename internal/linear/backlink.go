@@ -0,0 +1,182 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// Ways a published post is linked back to the issues it was written from.
+const (
+	BacklinkAttachment = "attachment"
+	BacklinkComment    = "comment"
+	BacklinkOff        = "off"
+)
+
+// backlinkTitleChars caps how much of a post's opening line titles the
+// link on the issue.
+const backlinkTitleChars = 80
+
+// ParseBacklinkMode returns the back-link mode named by s.
+func ParseBacklinkMode(s string) (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(s)); mode {
+	case BacklinkAttachment, BacklinkComment, BacklinkOff:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown back-link mode %q, use attachment, comment or off", s)
+}
+
+// Backlinker links each Linear issue a published post was written from to
+// the post, closing the loop for the team that did the work.
+type Backlinker struct {
+	client      *Client
+	thoughtRepo *database.ThoughtRepository
+	mode        string
+}
+
+// NewBacklinker adds the link as an attachment on the issue, or as a
+// comment when mode is BacklinkComment.
+func NewBacklinker(client *Client, thoughtRepo *database.ThoughtRepository, mode string) *Backlinker {
+	return &Backlinker{
+		client:      client,
+		thoughtRepo: thoughtRepo,
+		mode:        mode,
+	}
+}
+
+// Backlink links post to the issues behind the thoughts it used. Posts
+// only published to X, and thoughts that aren't single issues, such as
+// cycle recaps and milestones, are skipped. Linear keeps one attachment per
+// URL on an issue, so linking a post again updates the link in place.
+func (b *Backlinker) Backlink(ctx context.Context, post *models.Post) error {
+	url := post.LinkedInURL
+	if url == "" && post.CompanyPostURN != "" {
+		url = models.LinkedInPostURL(post.CompanyPostURN)
+	}
+	if url == "" {
+		return nil
+	}
+
+	title := backlinkTitle(post.Content)
+
+	var errs []error
+	for _, thoughtID := range post.SourceThoughtIDs {
+		thought, err := b.thoughtRepo.GetByID(ctx, thoughtID)
+		if err != nil {
+			// The thought may have been deleted since.
+			slog.WarnContext(ctx, "failed to load thought to link back", "post_id", post.ID, "thought_id", thoughtID, "error", err)
+			continue
+		}
+		if thought.Source != thoughtSource || thought.ExternalID == "" || strings.Contains(thought.ExternalID, ":") {
+			continue
+		}
+
+		issueID := thought.ExternalID
+		if b.mode == BacklinkComment {
+			err = b.client.CreateComment(issueID, fmt.Sprintf("Shared on LinkedIn: [%s](%s)", title, url))
+		} else {
+			err = b.client.CreateAttachment(issueID, url, "Shared on LinkedIn", title)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("issue %s: %w", issueID, err))
+			continue
+		}
+
+		slog.InfoContext(ctx, "linked published post to linear issue", "post_id", post.ID, "issue_id", issueID, "mode", b.mode)
+	}
+
+	return errors.Join(errs...)
+}
+
+// backlinkTitle returns the opening line of content, the hook, as plain
+// text.
+func backlinkTitle(content string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(format.ToUnicode(content)), "\n")
+	line = strings.TrimSpace(line)
+	if len([]rune(line)) > backlinkTitleChars {
+		line = strings.TrimSpace(truncateRunes(line, backlinkTitleChars-1)) + "…"
+	}
+	return line
+}
+
+// CreateAttachment links url to the issue with issueID, under title and
+// subtitle.
+func (c *Client) CreateAttachment(issueID, url, title, subtitle string) error {
+	query := `
+		mutation($input: AttachmentCreateInput!) {
+			attachmentCreate(input: $input) {
+				success
+			}
+		}
+	`
+
+	input := map[string]interface{}{
+		"issueId": issueID,
+		"url":     url,
+		"title":   title,
+	}
+	if subtitle != "" {
+		input["subtitle"] = subtitle
+	}
+
+	data, err := c.query(query, map[string]interface{}{"input": input})
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	var result struct {
+		AttachmentCreate struct {
+			Success bool `json:"success"`
+		} `json:"attachmentCreate"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to parse attachment: %w", err)
+	}
+	if !result.AttachmentCreate.Success {
+		return fmt.Errorf("linear did not create the attachment")
+	}
+
+	return nil
+}
+
+// CreateComment comments body, in Markdown, on the issue with issueID.
+func (c *Client) CreateComment(issueID, body string) error {
+	query := `
+		mutation($input: CommentCreateInput!) {
+			commentCreate(input: $input) {
+				success
+			}
+		}
+	`
+
+	data, err := c.query(query, map[string]interface{}{
+		"input": map[string]interface{}{
+			"issueId": issueID,
+			"body":    body,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	var result struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to parse comment: %w", err)
+	}
+	if !result.CommentCreate.Success {
+		return fmt.Errorf("linear did not create the comment")
+	}
+
+	return nil
+}
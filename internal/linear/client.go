@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/outbound"
 )
 
 type Client struct {
@@ -33,13 +36,20 @@ type GraphQLError struct {
 
 type Issue struct {
 	ID          string    `json:"id"`
+	Identifier  string    `json:"identifier"`
 	Title       string    `json:"title"`
+	URL         string    `json:"url"`
 	Description string    `json:"description"`
 	State       IssueState `json:"state"`
 	CompletedAt *time.Time `json:"completedAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 	Team        Team      `json:"team"`
 	Assignee    *User     `json:"assignee"`
+	Project     *Project  `json:"project"`
+	Labels      struct {
+		Nodes []Label `json:"nodes"`
+	} `json:"labels"`
+	Estimate *float64 `json:"estimate"`
 }
 
 type IssueState struct {
@@ -49,6 +59,15 @@ type IssueState struct {
 
 type Team struct {
 	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+type Project struct {
+	Name string `json:"name"`
+}
+
+type Label struct {
+	Name string `json:"name"`
 }
 
 type User struct {
@@ -56,16 +75,17 @@ type User struct {
 	Email string `json:"email"`
 }
 
-func NewClient(apiKey string) *Client {
+func NewClient(apiKey string, guard *outbound.Guard) *Client {
 	if apiKey == "" {
-		log.Fatal("LINEAR_API_KEY is required")
+		slog.Error("LINEAR_API_KEY is required")
+		os.Exit(1)
 	}
 
-	log.Println("linear client initialized")
+	slog.Info("linear client initialized")
 
 	return &Client{
 		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: guard.Transport(http.DefaultTransport)},
 		baseURL:    "https://api.linear.app/graphql",
 	}
 }
@@ -116,8 +136,10 @@ func (c *Client) query(query string, variables map[string]interface{}) (json.Raw
 	return gqlResp.Data, nil
 }
 
-func (c *Client) GetRecentlyCompletedIssues(days int) ([]Issue, error) {
-	log.Printf("fetching completed issues from the last %d days", days)
+// GetRecentlyCompletedIssues returns issues completed in the last days that
+// filter matches.
+func (c *Client) GetRecentlyCompletedIssues(days int, filter Filter) ([]Issue, error) {
+	slog.Info("fetching completed linear issues", "days", days, "filter", filter.String())
 
 	threshold := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
 
@@ -126,7 +148,9 @@ func (c *Client) GetRecentlyCompletedIssues(days int) ([]Issue, error) {
 			issues(filter: $filter, first: 50) {
 				nodes {
 					id
+					identifier
 					title
+					url
 					description
 					state {
 						name
@@ -136,23 +160,36 @@ func (c *Client) GetRecentlyCompletedIssues(days int) ([]Issue, error) {
 					updatedAt
 					team {
 						name
+						key
 					}
 					assignee {
 						name
 						email
 					}
+					project {
+						name
+					}
+					labels {
+						nodes {
+							name
+						}
+					}
+					estimate
 				}
 			}
 		}
 	`
 
-	variables := map[string]interface{}{
-		"filter": map[string]interface{}{
-			"completedAt": map[string]interface{}{
-				"gte": threshold,
-			},
+	issueFilter := map[string]interface{}{
+		"completedAt": map[string]interface{}{
+			"gte": threshold,
 		},
 	}
+	filter.issueFilter(issueFilter)
+
+	variables := map[string]interface{}{
+		"filter": issueFilter,
+	}
 
 	data, err := c.query(query, variables)
 	if err != nil {
@@ -169,9 +206,16 @@ func (c *Client) GetRecentlyCompletedIssues(days int) ([]Issue, error) {
 		return nil, fmt.Errorf("failed to parse issues: %w", err)
 	}
 
-	log.Printf("found %d completed issues", len(result.Issues.Nodes))
+	var issues []Issue
+	for _, issue := range result.Issues.Nodes {
+		if filter.Matches(&issue) {
+			issues = append(issues, issue)
+		}
+	}
+
+	slog.Info("found completed linear issues", "count", len(issues), "filtered_out", len(result.Issues.Nodes)-len(issues))
 
-	return result.Issues.Nodes, nil
+	return issues, nil
 }
 
 func (c *Client) GetIssue(issueID string) (*Issue, error) {
@@ -179,7 +223,9 @@ func (c *Client) GetIssue(issueID string) (*Issue, error) {
 		query($id: String!) {
 			issue(id: $id) {
 				id
+				identifier
 				title
+				url
 				description
 				state {
 					name
@@ -189,11 +235,21 @@ func (c *Client) GetIssue(issueID string) (*Issue, error) {
 				updatedAt
 				team {
 					name
+					key
 				}
 				assignee {
 					name
 					email
 				}
+				project {
+					name
+				}
+				labels {
+					nodes {
+						name
+					}
+				}
+				estimate
 			}
 		}
 	`
@@ -225,7 +281,9 @@ func (c *Client) GetMyIssues(filter string) ([]Issue, error) {
 				assignedIssues(first: 50) {
 					nodes {
 						id
+						identifier
 						title
+						url
 						description
 						state {
 							name
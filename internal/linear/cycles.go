@@ -0,0 +1,269 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// recentCycleDays is how long after a cycle ends it can still be
+	// recapped.
+	recentCycleDays = 30
+	// maxRecapIssues caps how many shipped issues a recap lists.
+	maxRecapIssues = 15
+)
+
+type Cycle struct {
+	ID       string    `json:"id"`
+	Number   int       `json:"number"`
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	Team     Team      `json:"team"`
+	Issues   []Issue   `json:"-"`
+}
+
+// Title names the cycle for people, such as "Mobile cycle 42".
+func (c *Cycle) Title() string {
+	title := fmt.Sprintf("%s cycle %d", c.Team.Name, c.Number)
+	if c.Name != "" {
+		title += " (" + c.Name + ")"
+	}
+	return title
+}
+
+// GetLastCompletedCycle returns the cycle that ended most recently, with its
+// issues, among the teams named (by name or key), or every team when none
+// are. It returns nil without an error when no cycle ended in the last
+// month.
+func (c *Client) GetLastCompletedCycle(teams []string) (*Cycle, error) {
+	query := `
+		query($filter: CycleFilter) {
+			cycles(filter: $filter, first: 50) {
+				nodes {
+					id
+					number
+					name
+					startsAt
+					endsAt
+					team {
+						name
+						key
+					}
+				}
+			}
+		}
+	`
+
+	cycleFilter := map[string]interface{}{
+		"isPast": map[string]interface{}{"eq": true},
+		"endsAt": map[string]interface{}{"gte": time.Now().AddDate(0, 0, -recentCycleDays).Format("2006-01-02")},
+	}
+	if len(teams) > 0 {
+		Filter{Teams: teams}.issueFilter(cycleFilter)
+	}
+
+	data, err := c.query(query, map[string]interface{}{"filter": cycleFilter})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Cycles struct {
+			Nodes []Cycle `json:"nodes"`
+		} `json:"cycles"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse cycles: %w", err)
+	}
+
+	var latest *Cycle
+	for i, cycle := range result.Cycles.Nodes {
+		if latest == nil || cycle.EndsAt.After(latest.EndsAt) {
+			latest = &result.Cycles.Nodes[i]
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	issues, err := c.getCycleIssues(latest.ID)
+	if err != nil {
+		return nil, err
+	}
+	latest.Issues = issues
+
+	slog.Info("found completed linear cycle", "cycle_id", latest.ID, "team", latest.Team.Name, "number", latest.Number, "issues", len(issues))
+
+	return latest, nil
+}
+
+func (c *Client) getCycleIssues(cycleID string) ([]Issue, error) {
+	query := `
+		query($id: String!) {
+			cycle(id: $id) {
+				issues(first: 100) {
+					nodes {
+						id
+						title
+						description
+						state {
+							name
+							type
+						}
+						completedAt
+						updatedAt
+						team {
+							name
+							key
+						}
+						assignee {
+							name
+							email
+						}
+						project {
+							name
+						}
+						labels {
+							nodes {
+								name
+							}
+						}
+						estimate
+					}
+				}
+			}
+		}
+	`
+
+	data, err := c.query(query, map[string]interface{}{"id": cycleID})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Cycle struct {
+			Issues struct {
+				Nodes []Issue `json:"nodes"`
+			} `json:"issues"`
+		} `json:"cycle"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse cycle issues: %w", err)
+	}
+
+	return result.Cycle.Issues.Nodes, nil
+}
+
+// Recap describes what the cycle shipped: its scope, the completed issues
+// with the largest first, and what was carried over. Cancelled issues are
+// left out.
+func (c *Cycle) Recap() string {
+	var shipped, open []Issue
+	var shippedPoints, totalPoints float64
+	for _, issue := range c.Issues {
+		if issue.State.Type == "canceled" {
+			continue
+		}
+		estimate := 0.0
+		if issue.Estimate != nil {
+			estimate = *issue.Estimate
+		}
+		totalPoints += estimate
+		if issue.State.Type == "completed" {
+			shipped = append(shipped, issue)
+			shippedPoints += estimate
+		} else {
+			open = append(open, issue)
+		}
+	}
+
+	sort.SliceStable(shipped, func(i, j int) bool {
+		return estimateOf(shipped[i]) > estimateOf(shipped[j])
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, %s to %s: completed %d of %d issues", c.Title(),
+		c.StartsAt.Format("Jan 2"), c.EndsAt.Format("Jan 2"), len(shipped), len(shipped)+len(open))
+	if totalPoints > 0 {
+		fmt.Fprintf(&b, " (%s of %s points)", formatPoints(shippedPoints), formatPoints(totalPoints))
+	}
+	b.WriteString(".\n")
+
+	if len(shipped) > 0 {
+		b.WriteString("\nShipped:\n")
+		for i, issue := range shipped {
+			if i == maxRecapIssues {
+				fmt.Fprintf(&b, "- ...and %d smaller issues\n", len(shipped)-maxRecapIssues)
+				break
+			}
+			b.WriteString("- " + issue.Title)
+			if issue.Project != nil && issue.Project.Name != "" {
+				b.WriteString(" [" + issue.Project.Name + "]")
+			}
+			if details := strings.TrimSpace(issue.Description); details != "" {
+				b.WriteString(": " + truncateRunes(strings.Join(strings.Fields(details), " "), 200))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(open) > 0 {
+		fmt.Fprintf(&b, "\nCarried over: %d issue(s)\n", len(open))
+	}
+
+	return b.String()
+}
+
+func estimateOf(issue Issue) float64 {
+	if issue.Estimate == nil {
+		return 0
+	}
+	return *issue.Estimate
+}
+
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+// CaptureCycle saves the cycle's recap as a milestone thought in the
+// workspace of channelID, or returns the one saved by an earlier recap.
+func (s *Syncer) CaptureCycle(ctx context.Context, channelID string, cycle *Cycle) (*models.Thought, error) {
+	externalID := "cycle:" + cycle.ID
+	existing, err := s.thoughtRepo.GetByExternalID(ctx, thoughtSource, externalID)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	thought := models.NewThought(cycle.Recap(), thoughtSource)
+	thought.ExternalID = externalID
+	thought.SlackChannelID = channelID
+	thought.Category = milestoneCategory
+	thought.TopicTags = []string{milestoneCategory, "sprint", strings.ToLower(cycle.Team.Name)}
+
+	if err := s.save(ctx, thought); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "created thought from linear cycle", "thought_id", thought.ID, "cycle_id", cycle.ID)
+
+	return thought, nil
+}
+
+// LastCompletedCycle returns the most recently completed cycle of team, or
+// of the teams in the filter when team is empty.
+func (s *Syncer) LastCompletedCycle(team string) (*Cycle, error) {
+	teams := s.Filter().Teams
+	if team != "" {
+		teams = []string{team}
+	}
+	return s.client.GetLastCompletedCycle(teams)
+}
@@ -0,0 +1,153 @@
+package linear
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter limits which completed issues become thoughts. Each non-empty list
+// must match: the issue's team (by name or key), its project, or any of its
+// labels, compared ignoring case. A positive MinEstimate also drops issues
+// estimated below it, or not estimated at all. The zero Filter lets every
+// issue through.
+type Filter struct {
+	Teams       []string `json:"teams,omitempty"`
+	Projects    []string `json:"projects,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	MinEstimate float64  `json:"min_estimate,omitempty"`
+}
+
+func (f Filter) IsZero() bool {
+	return len(f.Teams) == 0 && len(f.Projects) == 0 && len(f.Labels) == 0 && f.MinEstimate <= 0
+}
+
+func (f Filter) Matches(issue *Issue) bool {
+	if len(f.Teams) > 0 && !containsFold(f.Teams, issue.Team.Name) && !containsFold(f.Teams, issue.Team.Key) {
+		return false
+	}
+
+	if len(f.Projects) > 0 && (issue.Project == nil || !containsFold(f.Projects, issue.Project.Name)) {
+		return false
+	}
+
+	if len(f.Labels) > 0 {
+		labelled := false
+		for _, label := range issue.Labels.Nodes {
+			if containsFold(f.Labels, label.Name) {
+				labelled = true
+				break
+			}
+		}
+		if !labelled {
+			return false
+		}
+	}
+
+	if f.MinEstimate > 0 && (issue.Estimate == nil || *issue.Estimate < f.MinEstimate) {
+		return false
+	}
+
+	return true
+}
+
+// String describes the filter for Slack, such as "teams Mobile · estimate ≥ 2".
+func (f Filter) String() string {
+	var parts []string
+	if len(f.Teams) > 0 {
+		parts = append(parts, "teams "+strings.Join(f.Teams, ", "))
+	}
+	if len(f.Projects) > 0 {
+		parts = append(parts, "projects "+strings.Join(f.Projects, ", "))
+	}
+	if len(f.Labels) > 0 {
+		parts = append(parts, "labels "+strings.Join(f.Labels, ", "))
+	}
+	if f.MinEstimate > 0 {
+		parts = append(parts, "estimate ≥ "+strconv.FormatFloat(f.MinEstimate, 'f', -1, 64))
+	}
+
+	if len(parts) == 0 {
+		return "every issue"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// Set changes one part of the filter: "teams", "projects" or "labels" to a
+// comma-separated list, or "estimate" to a number. An empty value clears
+// that part.
+func (f *Filter) Set(field, value string) error {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	switch strings.ToLower(field) {
+	case "team", "teams":
+		f.Teams = list
+	case "project", "projects":
+		f.Projects = list
+	case "label", "labels":
+		f.Labels = list
+	case "estimate":
+		if value = strings.TrimSpace(value); value == "" {
+			f.MinEstimate = 0
+			return nil
+		}
+		estimate, err := strconv.ParseFloat(value, 64)
+		if err != nil || estimate < 0 {
+			return fmt.Errorf("invalid estimate %q", value)
+		}
+		f.MinEstimate = estimate
+	default:
+		return fmt.Errorf("unknown filter %q", field)
+	}
+
+	return nil
+}
+
+// issueFilter narrows a Linear IssueFilter to issues the filter matches.
+// Matches still has the last word.
+func (f Filter) issueFilter(filter map[string]interface{}) {
+	if len(f.Teams) > 0 {
+		var teams []interface{}
+		for _, team := range f.Teams {
+			teams = append(teams,
+				map[string]interface{}{"name": map[string]interface{}{"eqIgnoreCase": team}},
+				map[string]interface{}{"key": map[string]interface{}{"eqIgnoreCase": team}},
+			)
+		}
+		filter["team"] = map[string]interface{}{"or": teams}
+	}
+
+	if len(f.Projects) > 0 {
+		filter["project"] = map[string]interface{}{"or": nameFilters(f.Projects)}
+	}
+
+	if len(f.Labels) > 0 {
+		filter["labels"] = map[string]interface{}{"some": map[string]interface{}{"or": nameFilters(f.Labels)}}
+	}
+
+	if f.MinEstimate > 0 {
+		filter["estimate"] = map[string]interface{}{"gte": f.MinEstimate}
+	}
+}
+
+func nameFilters(names []string) []interface{} {
+	var filters []interface{}
+	for _, name := range names {
+		filters = append(filters, map[string]interface{}{"name": map[string]interface{}{"eqIgnoreCase": name}})
+	}
+	return filters
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
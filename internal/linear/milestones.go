@@ -0,0 +1,177 @@
+package linear
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/digest"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// milestoneDays is how far back each weekly run looks for completed
+	// issues.
+	milestoneDays = 7
+	// milestoneCategory is the category, and first tag, of milestone
+	// thoughts.
+	milestoneCategory = "milestone"
+	// maxMilestoneIssueDetails caps how much of each issue's description the
+	// summarizer sees.
+	maxMilestoneIssueDetails = 500
+)
+
+// MilestoneJob runs once a week: it groups the issues completed since the
+// last run by project, or by team for issues outside a project, and saves
+// one milestone thought per group summarizing the week's progress. The
+// thoughts go to the shared pool, like issues captured by the webhook.
+type MilestoneJob struct {
+	syncer     *Syncer
+	summarizer *agents.SummarizerAgent
+	schedule   digest.Schedule
+	location   *time.Location
+}
+
+func NewMilestoneJob(syncer *Syncer, summarizer *agents.SummarizerAgent, schedule digest.Schedule, location *time.Location) *MilestoneJob {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &MilestoneJob{
+		syncer:     syncer,
+		summarizer: summarizer,
+		schedule:   schedule,
+		location:   location,
+	}
+}
+
+func (j *MilestoneJob) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "linear milestones started", "schedule", j.schedule, "location", j.location)
+
+	for {
+		next := j.schedule.Next(time.Now(), j.location)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.InfoContext(ctx, "linear milestones stopped")
+			return
+		case <-timer.C:
+		}
+
+		result, err := j.Run(ctx, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to create linear milestones", "error", err)
+			continue
+		}
+
+		slog.InfoContext(ctx, "linear milestones created", "issues", result.Fetched, "created", result.Created, "skipped", result.Skipped, "failed", result.Failed)
+	}
+}
+
+// Run creates the milestone thoughts for the week ending at now. Fetched
+// counts issues and the other counts milestones; a group that already has a
+// milestone for that day is skipped.
+func (j *MilestoneJob) Run(ctx context.Context, now time.Time) (*SyncResult, error) {
+	issues, err := j.syncer.client.GetRecentlyCompletedIssues(milestoneDays, j.syncer.Filter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch completed issues: %w", err)
+	}
+
+	result := &SyncResult{Fetched: len(issues)}
+	week := now.In(j.location).Format("2006-01-02")
+
+	groups := groupIssues(issues)
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		created, err := j.createMilestone(ctx, name, week, groups[name])
+		switch {
+		case err != nil:
+			slog.ErrorContext(ctx, "failed to create linear milestone", "project", name, "error", err)
+			result.Failed++
+		case created:
+			result.Created++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result, nil
+}
+
+func (j *MilestoneJob) createMilestone(ctx context.Context, name, week string, issues []Issue) (bool, error) {
+	externalID := "milestone:" + week + ":" + strings.ToLower(truncateRunes(name, 200))
+	exists, err := j.syncer.thoughtRepo.ExistsByExternalID(ctx, thoughtSource, externalID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	var lines, titles []string
+	for _, issue := range issues {
+		line := issue.Title
+		if details := strings.TrimSpace(issue.Description); details != "" {
+			line += ": " + truncateRunes(details, maxMilestoneIssueDetails)
+		}
+		lines = append(lines, line)
+		titles = append(titles, issue.Title)
+	}
+
+	summary, err := j.summarizer.SummarizeMilestone(ctx, name, lines)
+	if err != nil {
+		return false, fmt.Errorf("failed to summarize milestone: %w", err)
+	}
+
+	content := fmt.Sprintf("Milestone: %s, week of %s\n\n%s\n\nCompleted: %s", name, week, summary, strings.Join(titles, "; "))
+
+	thought := models.NewThought(content, thoughtSource)
+	thought.ExternalID = externalID
+	thought.Category = milestoneCategory
+	thought.TopicTags = []string{milestoneCategory, strings.ToLower(name)}
+
+	if err := j.syncer.save(ctx, thought); err != nil {
+		return false, err
+	}
+
+	slog.InfoContext(ctx, "created milestone thought from linear issues", "thought_id", thought.ID, "project", name, "issues", len(issues))
+
+	return true, nil
+}
+
+// groupIssues groups issues by project name, or by team name for issues
+// outside a project.
+func groupIssues(issues []Issue) map[string][]Issue {
+	groups := make(map[string][]Issue)
+	for _, issue := range issues {
+		name := issue.Team.Name
+		if issue.Project != nil && issue.Project.Name != "" {
+			name = issue.Project.Name
+		}
+		groups[name] = append(groups[name], issue)
+	}
+	return groups
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
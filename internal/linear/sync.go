@@ -0,0 +1,242 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	thoughtSource = "linear"
+	// filterSetting stores the filter set from Slack, as JSON.
+	filterSetting = "linear.filter"
+)
+
+type Syncer struct {
+	client      *Client
+	thoughtRepo *database.ThoughtRepository
+	categorizer *agents.CategorizerAgent
+	embeddings  *agents.EmbeddingAgent
+	settings    *database.SettingsRepository
+
+	mu               sync.RWMutex
+	filter           Filter
+	configuredFilter Filter
+}
+
+type SyncResult struct {
+	Fetched int
+	Created int
+	Skipped int
+	Failed  int
+}
+
+// NewSyncer ingests the issues filter matches until a filter is set from
+// Slack.
+func NewSyncer(client *Client, thoughtRepo *database.ThoughtRepository, categorizer *agents.CategorizerAgent, embeddings *agents.EmbeddingAgent, settings *database.SettingsRepository, filter Filter) *Syncer {
+	return &Syncer{
+		client:           client,
+		thoughtRepo:      thoughtRepo,
+		categorizer:      categorizer,
+		embeddings:       embeddings,
+		settings:         settings,
+		filter:           filter,
+		configuredFilter: filter,
+	}
+}
+
+// LoadFilter switches to the filter last set from Slack, if any. A stored
+// filter that can't be read is logged and the configured one kept.
+func (s *Syncer) LoadFilter(ctx context.Context) {
+	value, err := s.settings.Get(ctx, filterSetting)
+	if err != nil || value == "" {
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load linear filter", "error", err)
+		}
+		return
+	}
+
+	var filter Filter
+	if err := json.Unmarshal([]byte(value), &filter); err != nil {
+		slog.WarnContext(ctx, "ignoring invalid linear filter", "value", value)
+		return
+	}
+
+	s.mu.Lock()
+	s.filter = filter
+	s.mu.Unlock()
+
+	slog.InfoContext(ctx, "using stored linear filter", "filter", filter.String())
+}
+
+func (s *Syncer) Filter() Filter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.filter
+}
+
+// SetFilter changes which issues are ingested and remembers it. userID is
+// recorded as who changed it.
+func (s *Syncer) SetFilter(ctx context.Context, filter Filter, userID string) error {
+	value, err := json.Marshal(filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal linear filter: %w", err)
+	}
+
+	if err := s.settings.Set(ctx, filterSetting, string(value), userID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.filter = filter
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ResetFilter goes back to the configured filter.
+func (s *Syncer) ResetFilter(ctx context.Context) error {
+	if err := s.settings.Delete(ctx, filterSetting); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.filter = s.configuredFilter
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Wanted reports whether the filter lets issueID through. The issue is only
+// fetched when the filter could reject it.
+func (s *Syncer) Wanted(ctx context.Context, issueID string) (bool, error) {
+	filter := s.Filter()
+	if filter.IsZero() {
+		return true, nil
+	}
+
+	issue, err := s.client.GetIssue(issueID)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch issue: %w", err)
+	}
+
+	return filter.Matches(issue), nil
+}
+
+// SyncCompleted ingests issues completed in the last days into the
+// workspace of channelID, skipping any issue that was already captured by an
+// earlier sync or the webhook.
+func (s *Syncer) SyncCompleted(ctx context.Context, channelID string, days int) (*SyncResult, error) {
+	issues, err := s.client.GetRecentlyCompletedIssues(days, s.Filter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch completed issues: %w", err)
+	}
+
+	result := &SyncResult{Fetched: len(issues)}
+
+	for _, issue := range issues {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		created, err := s.IngestIssue(ctx, channelID, issue.ID, issue.URL, issue.Title, issue.Description, issue.Team.Name)
+		switch {
+		case err != nil:
+			slog.ErrorContext(ctx, "failed to ingest linear issue", "issue_id", issue.ID, "error", err)
+			result.Failed++
+		case created:
+			result.Created++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result, nil
+}
+
+// IngestIssue creates a categorized thought for a completed issue in the
+// workspace of channelID, or in the shared pool when channelID is empty. It
+// returns false without an error when the issue was already ingested. The
+// issue's url is kept on the thought, so posts written from it can link
+// back.
+func (s *Syncer) IngestIssue(ctx context.Context, channelID, issueID, url, title, description, teamName string) (bool, error) {
+	return s.ingest(ctx, channelID, issueID, issueID, url, title, "Completed: "+title, description, teamName)
+}
+
+// IngestIdea creates a categorized thought in the shared pool for an issue
+// the team flagged as a content idea, before any work is done on it. It
+// returns false without an error when the idea was already captured. The
+// idea has its own external ID, so the issue is still ingested when it is
+// completed.
+func (s *Syncer) IngestIdea(ctx context.Context, issueID, url, title, description, teamName string) (bool, error) {
+	return s.ingest(ctx, "", ideaExternalID(issueID), issueID, url, title, "Content idea: "+title, description, teamName)
+}
+
+// ideaExternalID is the external ID of the thought captured for an issue
+// flagged as a content idea.
+func ideaExternalID(issueID string) string {
+	return "idea:" + issueID
+}
+
+func (s *Syncer) ingest(ctx context.Context, channelID, externalID, issueID, url, title, headline, description, teamName string) (bool, error) {
+	exists, err := s.thoughtRepo.ExistsByExternalID(ctx, thoughtSource, externalID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	content := headline
+	if description != "" {
+		content += fmt.Sprintf("\n\nDetails: %s", description)
+	}
+
+	thought := models.NewThought(content, thoughtSource)
+	thought.ExternalID = externalID
+	thought.SourceURL = url
+	thought.SourceTitle = title
+	thought.SlackChannelID = channelID
+
+	if err := s.categorizer.CategorizeThought(ctx, thought); err != nil {
+		slog.ErrorContext(ctx, "failed to categorize thought", "error", err)
+		thought.Category = "product_update"
+		thought.TopicTags = []string{"development", teamName}
+	}
+
+	if err := s.save(ctx, thought); err != nil {
+		return false, err
+	}
+
+	slog.InfoContext(ctx, "created thought from linear issue", "thought_id", thought.ID, "issue_id", issueID)
+
+	return true, nil
+}
+
+// save stores a thought made from Linear. Such thoughts are never dropped as
+// near-duplicates, but they are still linked to related thoughts.
+func (s *Syncer) save(ctx context.Context, thought *models.Thought) error {
+	if s.embeddings != nil {
+		if _, err := s.embeddings.Match(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "failed to match thought embeddings", "error", err)
+		}
+	}
+
+	if err := s.thoughtRepo.Create(ctx, thought); err != nil {
+		return fmt.Errorf("failed to save thought: %w", err)
+	}
+
+	if s.embeddings != nil {
+		if err := s.embeddings.Link(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "failed to store thought embedding", "error", err)
+		}
+	}
+
+	return nil
+}
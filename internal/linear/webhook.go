@@ -4,22 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"sync"
+	"strings"
 
-	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
-	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
-	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/dedup"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/webhook"
 )
 
+// webhookAttempts is how many times a delivery is processed before it
+// becomes a dead letter.
+const webhookAttempts = 3
+
 type WebhookHandler struct {
-	linearClient   *Client
-	thoughtRepo    *database.ThoughtRepository
-	categorizer    *agents.CategorizerAgent
-	processedIssues map[string]bool
-	mu             sync.Mutex
+	syncer          *Syncer
+	processedIssues dedup.Store
+	webhookSecret   string
+	// milestones leaves completed issues to the weekly MilestoneJob instead
+	// of capturing each one.
+	milestones bool
+	// ideaLabel, when set, captures issues with this label as soon as they
+	// are created or labeled.
+	ideaLabel string
 }
 
 type WebhookPayload struct {
@@ -31,7 +37,9 @@ type WebhookPayload struct {
 
 type WebhookIssueData struct {
 	ID          string `json:"id"`
+	Identifier  string `json:"identifier"`
 	Title       string `json:"title"`
+	URL         string `json:"url"`
 	Description string `json:"description"`
 	State       struct {
 		Name string `json:"name"`
@@ -40,99 +48,154 @@ type WebhookIssueData struct {
 	Team struct {
 		Name string `json:"name"`
 	} `json:"team"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// webhookUpdatedFrom holds the previous values of the fields an update
+// changed.
+type webhookUpdatedFrom struct {
+	LabelIDs []string `json:"labelIds"`
 }
 
 func NewWebhookHandler(
-	linearClient *Client,
-	thoughtRepo *database.ThoughtRepository,
-	categorizer *agents.CategorizerAgent,
+	syncer *Syncer,
+	processedIssues dedup.Store,
+	webhookSecret string,
+	milestones bool,
+	ideaLabel string,
 ) *WebhookHandler {
 	return &WebhookHandler{
-		linearClient:    linearClient,
-		thoughtRepo:     thoughtRepo,
-		categorizer:     categorizer,
-		processedIssues: make(map[string]bool),
+		syncer:          syncer,
+		processedIssues: processedIssues,
+		webhookSecret:   webhookSecret,
+		milestones:      milestones,
+		ideaLabel:       ideaLabel,
 	}
 }
 
-func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Printf("failed to read webhook body: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		return
+// Webhook returns the endpoint Linear delivers issue updates to, for a
+// webhook.Router.
+func (h *WebhookHandler) Webhook() webhook.Source {
+	return webhook.Source{
+		Name:     "linear",
+		Path:     "/linear/webhook",
+		Verifier: webhook.Linear(h.webhookSecret),
+		DeliveryID: func(header http.Header, _ []byte) string {
+			return header.Get("Linear-Delivery")
+		},
+		Attempts: webhookAttempts,
+		Handle:   h.handle,
 	}
+}
 
+func (h *WebhookHandler) handle(ctx context.Context, delivery *webhook.Delivery) ([]byte, error) {
 	var payload WebhookPayload
-	if err := json.Unmarshal(body, &payload); err != nil {
-		log.Printf("failed to parse webhook payload: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		return
+	if err := json.Unmarshal(delivery.Body, &payload); err != nil {
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse webhook payload: %w", err))
 	}
 
-	log.Printf("received linear webhook: %s %s", payload.Action, payload.Type)
-
-	if payload.Type != "Issue" {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
+	slog.InfoContext(ctx, "received linear webhook", "action", payload.Action, "type", payload.Type)
 
-	if payload.Action != "update" {
-		w.WriteHeader(http.StatusOK)
-		return
+	if payload.Type != "Issue" || (payload.Action != "create" && payload.Action != "update") {
+		return nil, nil
 	}
 
 	var issueData WebhookIssueData
 	if err := json.Unmarshal(payload.Data, &issueData); err != nil {
-		log.Printf("failed to parse issue data: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse issue data: %w", err))
 	}
 
-	if issueData.State.Type != "completed" {
-		w.WriteHeader(http.StatusOK)
-		return
+	if h.isNewIdea(&payload, &issueData) {
+		return nil, h.captureIdea(ctx, &issueData)
 	}
 
-	h.mu.Lock()
-	if h.processedIssues[issueData.ID] {
-		h.mu.Unlock()
-		log.Printf("skipping duplicate issue: %s", issueData.ID)
-		w.WriteHeader(http.StatusOK)
-		return
+	if payload.Action != "update" || issueData.State.Type != "completed" {
+		return nil, nil
 	}
-	h.processedIssues[issueData.ID] = true
-	h.mu.Unlock()
 
-	log.Printf("issue completed: %s - %s", issueData.ID, issueData.Title)
+	if h.milestones {
+		slog.InfoContext(ctx, "linear issue left for the weekly milestone", "issue_id", issueData.ID)
+		return nil, nil
+	}
 
-	ctx := context.Background()
-	if err := h.createThoughtFromIssue(ctx, &issueData); err != nil {
-		log.Printf("failed to create thought: %v", err)
+	key := "linear:" + issueData.ID
+	if h.processedIssues.Seen(key) {
+		slog.InfoContext(ctx, "skipping duplicate linear issue", "issue_id", issueData.ID)
+		return nil, nil
 	}
 
-	w.WriteHeader(http.StatusOK)
-}
+	slog.InfoContext(ctx, "linear issue completed", "issue_id", issueData.ID, "title", issueData.Title)
+
+	// On failure the issue is forgotten so a retry gets another chance.
+	wanted, err := h.syncer.Wanted(ctx, issueData.ID)
+	if err != nil {
+		h.processedIssues.Forget(key)
+		return nil, fmt.Errorf("failed to filter linear issue %s: %w", issueData.ID, err)
+	}
+	if !wanted {
+		slog.InfoContext(ctx, "skipping filtered linear issue", "issue_id", issueData.ID)
+		return nil, nil
+	}
 
-func (h *WebhookHandler) createThoughtFromIssue(ctx context.Context, issue *WebhookIssueData) error {
-	content := fmt.Sprintf("Completed: %s", issue.Title)
-	if issue.Description != "" {
-		content += fmt.Sprintf("\n\nDetails: %s", issue.Description)
+	created, err := h.syncer.IngestIssue(ctx, "", issueData.ID, issueData.URL, issueData.Title, issueData.Description, issueData.Team.Name)
+	if err != nil {
+		h.processedIssues.Forget(key)
+		return nil, fmt.Errorf("failed to create thought from linear issue %s: %w", issueData.ID, err)
+	}
+	if !created {
+		slog.InfoContext(ctx, "linear issue already captured", "issue_id", issueData.ID)
 	}
 
-	thought := models.NewThought(content, "linear")
+	return nil, nil
+}
 
-	if err := h.categorizer.CategorizeThought(ctx, thought); err != nil {
-		log.Printf("failed to categorize thought: %v", err)
-		thought.Category = "product_update"
-		thought.TopicTags = []string{"development", issue.Team.Name}
+// isNewIdea reports whether the issue was just created with the idea label,
+// or just given it.
+func (h *WebhookHandler) isNewIdea(payload *WebhookPayload, issueData *WebhookIssueData) bool {
+	if h.ideaLabel == "" || !issueData.hasLabel(h.ideaLabel) || issueData.State.Type == "completed" {
+		return false
+	}
+	if payload.Action == "create" {
+		return true
 	}
 
-	if err := h.thoughtRepo.Create(ctx, thought); err != nil {
-		return fmt.Errorf("failed to save thought: %w", err)
+	var updatedFrom webhookUpdatedFrom
+	if len(payload.UpdatedFrom) == 0 || json.Unmarshal(payload.UpdatedFrom, &updatedFrom) != nil {
+		return false
+	}
+	return updatedFrom.LabelIDs != nil
+}
+
+// captureIdea saves an issue flagged as a content idea as a thought. The
+// label is an explicit choice, so the filter doesn't apply.
+func (h *WebhookHandler) captureIdea(ctx context.Context, issueData *WebhookIssueData) error {
+	key := "linear-idea:" + issueData.ID
+	if h.processedIssues.Seen(key) {
+		slog.InfoContext(ctx, "skipping duplicate linear content idea", "issue_id", issueData.ID)
+		return nil
 	}
 
-	log.Printf("created thought from linear issue: %s", thought.ID)
+	slog.InfoContext(ctx, "linear content idea flagged", "issue_id", issueData.ID, "title", issueData.Title)
+
+	created, err := h.syncer.IngestIdea(ctx, issueData.ID, issueData.URL, issueData.Title, issueData.Description, issueData.Team.Name)
+	if err != nil {
+		h.processedIssues.Forget(key)
+		return fmt.Errorf("failed to create thought from linear content idea %s: %w", issueData.ID, err)
+	}
+	if !created {
+		slog.InfoContext(ctx, "linear content idea already captured", "issue_id", issueData.ID)
+	}
 
 	return nil
 }
+
+func (d *WebhookIssueData) hasLabel(name string) bool {
+	for _, label := range d.Labels {
+		if strings.EqualFold(label.Name, name) {
+			return true
+		}
+	}
+	return false
+}
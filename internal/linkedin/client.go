@@ -0,0 +1,255 @@
+package linkedin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/outbound"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+type Client struct {
+	tokens     TokenSource
+	httpClient *http.Client
+	baseURL    string
+}
+
+type ugcPostRequest struct {
+	Author          string                 `json:"author"`
+	LifecycleState  string                 `json:"lifecycleState"`
+	SpecificContent map[string]interface{} `json:"specificContent"`
+	Visibility      map[string]string      `json:"visibility"`
+}
+
+// MediaKind says how attached media is uploaded and shown on a post.
+type MediaKind int
+
+const (
+	MediaImage MediaKind = iota
+	MediaDocument
+)
+
+// Media is an image or document attached to a post. Data holds the encoded
+// file. Documents need a Title, which LinkedIn shows above the viewer.
+type Media struct {
+	Kind    MediaKind
+	Data    []byte
+	AltText string
+	Title   string
+}
+
+type registerUploadRequest struct {
+	RegisterUploadRequest struct {
+		Recipes              []string              `json:"recipes"`
+		Owner                string                `json:"owner"`
+		ServiceRelationships []serviceRelationship `json:"serviceRelationships"`
+	} `json:"registerUploadRequest"`
+}
+
+type serviceRelationship struct {
+	RelationshipType string `json:"relationshipType"`
+	Identifier       string `json:"identifier"`
+}
+
+type registerUploadResponse struct {
+	Value struct {
+		Asset           string `json:"asset"`
+		UploadMechanism map[string]struct {
+			UploadURL string `json:"uploadUrl"`
+		} `json:"uploadMechanism"`
+	} `json:"value"`
+}
+
+type apiError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func NewClient(tokens TokenSource, guard *outbound.Guard) *Client {
+	if tokens == nil {
+		slog.Error("LinkedIn token source is required")
+		os.Exit(1)
+	}
+
+	return &Client{
+		tokens:     tokens,
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: guard.Transport(otelhttp.NewTransport(http.DefaultTransport))},
+		baseURL:    "https://api.linkedin.com/v2",
+	}
+}
+
+// CreatePost publishes content as the token's author, tagging mentions.
+// media is optional; when set, the file is uploaded first and attached to
+// the post.
+func (c *Client) CreatePost(ctx context.Context, content string, mentions []Mention, media *Media) (string, error) {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if media != nil && media.Kind == MediaDocument {
+		return c.createDocumentPost(ctx, token.AccessToken, token.AuthorURN, content, mentions, media)
+	}
+
+	shareContent := map[string]interface{}{
+		"shareCommentary": map[string]string{
+			"text": content,
+		},
+		"shareMediaCategory": "NONE",
+	}
+	if len(mentions) > 0 {
+		shareContent["shareCommentary"] = map[string]interface{}{
+			"text":       content,
+			"attributes": ugcAttributes(content, mentions),
+		}
+	}
+
+	if media != nil {
+		asset, err := c.uploadImage(ctx, token.AccessToken, token.AuthorURN, media.Data)
+		if err != nil {
+			return "", err
+		}
+
+		shareContent["shareMediaCategory"] = "IMAGE"
+		shareContent["media"] = []map[string]interface{}{
+			{
+				"status":      "READY",
+				"media":       asset,
+				"description": map[string]string{"text": media.AltText},
+			},
+		}
+	}
+
+	reqBody := ugcPostRequest{
+		Author:         token.AuthorURN,
+		LifecycleState: "PUBLISHED",
+		SpecificContent: map[string]interface{}{
+			"com.linkedin.ugc.ShareContent": shareContent,
+		},
+		Visibility: map[string]string{
+			"com.linkedin.ugc.MemberNetworkVisibility": "PUBLIC",
+		},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/ugcPosts", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("X-Restli-Protocol-Version", "2.0.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call LinkedIn API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
+			return "", fmt.Errorf("LinkedIn API error (status %d): %s", resp.StatusCode, apiErr.Message)
+		}
+		return "", fmt.Errorf("LinkedIn API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	postURN := resp.Header.Get("X-RestLi-Id")
+	if postURN == "" {
+		var created struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(body, &created); err == nil {
+			postURN = created.ID
+		}
+	}
+
+	return postURN, nil
+}
+
+// uploadImage registers an image asset for the author and uploads data to
+// it, returning the asset URN to reference from a post.
+func (c *Client) uploadImage(ctx context.Context, accessToken, authorURN string, data []byte) (string, error) {
+	var reqBody registerUploadRequest
+	reqBody.RegisterUploadRequest.Recipes = []string{"urn:li:digitalmediaRecipe:feedshare-image"}
+	reqBody.RegisterUploadRequest.Owner = authorURN
+	reqBody.RegisterUploadRequest.ServiceRelationships = []serviceRelationship{
+		{RelationshipType: "OWNER", Identifier: "urn:li:userGeneratedContent"},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/assets?action=registerUpload", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("X-Restli-Protocol-Version", "2.0.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to register image upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("LinkedIn API error registering upload (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var registered registerUploadResponse
+	if err := json.Unmarshal(body, &registered); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	mechanism, ok := registered.Value.UploadMechanism["com.linkedin.digitalmedia.uploading.MediaUploadHttpRequest"]
+	if !ok || mechanism.UploadURL == "" || registered.Value.Asset == "" {
+		return "", fmt.Errorf("LinkedIn did not return an upload URL")
+	}
+
+	upload, err := http.NewRequestWithContext(ctx, "PUT", mechanism.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	upload.Header.Set("Authorization", "Bearer "+accessToken)
+	upload.Header.Set("Content-Type", "application/octet-stream")
+
+	uploadResp, err := c.httpClient.Do(upload)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload image: %w", err)
+	}
+	defer uploadResp.Body.Close()
+
+	if uploadResp.StatusCode != http.StatusOK && uploadResp.StatusCode != http.StatusCreated {
+		uploadBody, _ := io.ReadAll(uploadResp.Body)
+		return "", fmt.Errorf("LinkedIn image upload failed (status %d): %s", uploadResp.StatusCode, string(uploadBody))
+	}
+
+	return registered.Value.Asset, nil
+}
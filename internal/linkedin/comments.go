@@ -0,0 +1,157 @@
+package linkedin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	commentsPageSize = 100
+	// maxCommentPages caps how many comments are read off one post.
+	maxCommentPages = 5
+)
+
+type commentRequest struct {
+	Actor         string         `json:"actor"`
+	Object        string         `json:"object"`
+	Message       commentMessage `json:"message"`
+	ParentComment string         `json:"parentComment,omitempty"`
+}
+
+type commentMessage struct {
+	Text string `json:"text"`
+}
+
+// Comment is a top-level comment on a post. Own is set on comments the
+// token's author left.
+type Comment struct {
+	URN       string
+	AuthorURN string
+	Text      string
+	CreatedAt time.Time
+	Own       bool
+}
+
+// CreateComment comments text on the post postURN as the token's author.
+func (c *Client) CreateComment(ctx context.Context, postURN, text string) error {
+	return c.comment(ctx, postURN, postURN, "", text)
+}
+
+// ReplyToComment answers the comment commentURN on the post postURN with
+// text, as the token's author.
+func (c *Client) ReplyToComment(ctx context.Context, postURN, commentURN, text string) error {
+	return c.comment(ctx, commentURN, postURN, commentURN, text)
+}
+
+func (c *Client) comment(ctx context.Context, target, postURN, parentURN, text string) error {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	reqBody := commentRequest{
+		Actor:         token.AuthorURN,
+		Object:        postURN,
+		Message:       commentMessage{Text: text},
+		ParentComment: parentURN,
+	}
+
+	resp, body, err := c.restPost(ctx, token.AccessToken, restBaseURL+"/socialActions/"+url.PathEscape(target)+"/comments", reqBody)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return restError(resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// GetComments returns the top-level comments on the post postURN, oldest
+// first as LinkedIn lists them.
+func (c *Client) GetComments(ctx context.Context, postURN string) ([]Comment, error) {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{"LinkedIn-Version": linkedInVersion}
+
+	var comments []Comment
+	for page := 0; page < maxCommentPages; page++ {
+		endpoint := fmt.Sprintf("%s/socialActions/%s/comments?start=%d&count=%d",
+			restBaseURL, url.PathEscape(postURN), page*commentsPageSize, commentsPageSize)
+
+		var result struct {
+			Elements []struct {
+				CommentURN string `json:"commentUrn"`
+				Actor      string `json:"actor"`
+				Message    struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Created struct {
+					Time int64 `json:"time"`
+				} `json:"created"`
+				ParentComment string `json:"parentComment"`
+			} `json:"elements"`
+		}
+
+		if err := c.get(ctx, endpoint, headers, &result); err != nil {
+			return nil, err
+		}
+
+		for _, element := range result.Elements {
+			if element.CommentURN == "" || element.ParentComment != "" {
+				continue
+			}
+			comments = append(comments, Comment{
+				URN:       element.CommentURN,
+				AuthorURN: element.Actor,
+				Text:      element.Message.Text,
+				CreatedAt: time.UnixMilli(element.Created.Time),
+				Own:       element.Actor == token.AuthorURN,
+			})
+		}
+
+		if len(result.Elements) < commentsPageSize {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
+// SyncComments stores the comments others left on a published post,
+// returning how many it has.
+func SyncComments(ctx context.Context, client *Client, commentRepo *database.CommentRepository, post *models.Post) (int, error) {
+	comments, err := client.GetComments(ctx, post.LinkedInURN)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch comments: %w", err)
+	}
+
+	synced := 0
+	for _, comment := range comments {
+		if comment.Own || comment.Text == "" {
+			continue
+		}
+		if err := commentRepo.Save(ctx, &models.Comment{
+			PostID:      post.ID,
+			CommentURN:  comment.URN,
+			AuthorURN:   comment.AuthorURN,
+			Content:     comment.Text,
+			CommentedAt: comment.CreatedAt,
+		}); err != nil {
+			return synced, err
+		}
+		synced++
+	}
+
+	return synced, nil
+}
@@ -0,0 +1,72 @@
+package linkedin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func (c *Client) createDocumentPost(ctx context.Context, accessToken, authorURN, content string, mentions []Mention, media *Media) (string, error) {
+	documentURN, err := c.uploadDocument(ctx, accessToken, authorURN, media.Data)
+	if err != nil {
+		return "", err
+	}
+
+	return c.createRestPost(ctx, accessToken, authorURN, content, mentions, restPostContent{
+		Media: &restMedia{Title: media.Title, ID: documentURN},
+	})
+}
+
+// uploadDocument uploads a PDF for the author and returns its document
+// URN.
+func (c *Client) uploadDocument(ctx context.Context, accessToken, authorURN string, data []byte) (string, error) {
+	reqBody := map[string]any{
+		"initializeUploadRequest": map[string]string{"owner": authorURN},
+	}
+
+	resp, body, err := c.restPost(ctx, accessToken, restBaseURL+"/documents?action=initializeUpload", reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", restError(resp.StatusCode, body)
+	}
+
+	var initialized struct {
+		Value struct {
+			UploadURL string `json:"uploadUrl"`
+			Document  string `json:"document"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(body, &initialized); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if initialized.Value.UploadURL == "" || initialized.Value.Document == "" {
+		return "", fmt.Errorf("LinkedIn did not return a document upload URL")
+	}
+
+	upload, err := http.NewRequestWithContext(ctx, "PUT", initialized.Value.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	upload.Header.Set("Authorization", "Bearer "+accessToken)
+	upload.Header.Set("Content-Type", "application/octet-stream")
+
+	uploadResp, err := c.httpClient.Do(upload)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload document: %w", err)
+	}
+	defer uploadResp.Body.Close()
+
+	if uploadResp.StatusCode != http.StatusOK && uploadResp.StatusCode != http.StatusCreated {
+		uploadBody, _ := io.ReadAll(uploadResp.Body)
+		return "", fmt.Errorf("LinkedIn document upload failed (status %d): %s", uploadResp.StatusCode, string(uploadBody))
+	}
+
+	return initialized.Value.Document, nil
+}
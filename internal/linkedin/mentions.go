@@ -0,0 +1,79 @@
+package linkedin
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// Mention tags the person or organization URN on the text between byte
+// offsets Start and End of a post's commentary.
+type Mention struct {
+	Start, End int
+	URN        string
+}
+
+// TagMentions prepares content for publishing with mentions: each name or
+// alias found is replaced with the contact's name, which LinkedIn expects
+// the tagged text to match, and its span returned. Contacts without a URN
+// are left untagged.
+func TagMentions(content string, mentions []models.Mention) (string, []Mention) {
+	var b strings.Builder
+	var tagged []Mention
+	last := 0
+	for _, mention := range mentions {
+		if !mention.Contact.Taggable() {
+			continue
+		}
+		b.WriteString(content[last:mention.Start])
+		start := b.Len()
+		b.WriteString(mention.Contact.Name)
+		tagged = append(tagged, Mention{Start: start, End: b.Len(), URN: mention.Contact.LinkedInURN})
+		last = mention.End
+	}
+	b.WriteString(content[last:])
+
+	return b.String(), tagged
+}
+
+// ugcAttributes marks mentions in a ugcPosts share commentary, which counts
+// offsets in UTF-16 code units.
+func ugcAttributes(text string, mentions []Mention) []map[string]interface{} {
+	var attributes []map[string]interface{}
+	for _, mention := range mentions {
+		value := map[string]interface{}{
+			"com.linkedin.common.MemberAttributedEntity": map[string]string{"member": mention.URN},
+		}
+		if strings.HasPrefix(mention.URN, "urn:li:organization:") {
+			value = map[string]interface{}{
+				"com.linkedin.common.CompanyAttributedEntity": map[string]string{"company": mention.URN},
+			}
+		}
+
+		attributes = append(attributes, map[string]interface{}{
+			"start":  utf16Len(text[:mention.Start]),
+			"length": utf16Len(text[mention.Start:mention.End]),
+			"value":  value,
+		})
+	}
+	return attributes
+}
+
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+// littleText escapes commentary for the Posts API and writes mentions as
+// @[name](urn).
+func littleText(text string, mentions []Mention) string {
+	var b strings.Builder
+	last := 0
+	for _, mention := range mentions {
+		b.WriteString(escapeLittleText(text[last:mention.Start]))
+		b.WriteString("@[" + escapeLittleText(text[mention.Start:mention.End]) + "](" + mention.URN + ")")
+		last = mention.End
+	}
+	b.WriteString(escapeLittleText(text[last:]))
+	return b.String()
+}
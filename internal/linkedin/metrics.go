@@ -0,0 +1,224 @@
+package linkedin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+)
+
+const (
+	restBaseURL      = "https://api.linkedin.com/rest"
+	linkedInVersion  = "202506"
+	metricsWindow    = 30 * 24 * time.Hour
+	metricsRateDelay = 500 * time.Millisecond
+)
+
+// GetPostMetrics returns likes, comments, shares and views for a published
+// post. Likes and comments come from the social actions API; shares and
+// views need the r_member_postAnalytics scope and stay at zero without it.
+func (c *Client) GetPostMetrics(ctx context.Context, postURN string) (map[string]int, error) {
+	var social struct {
+		LikesSummary struct {
+			TotalLikes int `json:"totalLikes"`
+		} `json:"likesSummary"`
+		CommentsSummary struct {
+			AggregatedTotalComments int `json:"aggregatedTotalComments"`
+		} `json:"commentsSummary"`
+	}
+
+	if err := c.get(ctx, c.baseURL+"/socialActions/"+url.PathEscape(postURN), nil, &social); err != nil {
+		return nil, err
+	}
+
+	metrics := map[string]int{
+		"likes":    social.LikesSummary.TotalLikes,
+		"comments": social.CommentsSummary.AggregatedTotalComments,
+		"shares":   0,
+		"views":    0,
+	}
+
+	for key, queryType := range map[string]string{"views": "IMPRESSION", "shares": "RESHARE"} {
+		count, err := c.getPostAnalytics(ctx, postURN, queryType)
+		if err != nil {
+			slog.InfoContext(ctx, "linkedin analytics unavailable", "query_type", queryType, "post_urn", postURN, "error", err)
+			continue
+		}
+		metrics[key] = count
+	}
+
+	return metrics, nil
+}
+
+func (c *Client) getPostAnalytics(ctx context.Context, postURN, queryType string) (int, error) {
+	entityType := "ugc"
+	if strings.HasPrefix(postURN, "urn:li:share:") {
+		entityType = "share"
+	}
+	entity := "(" + entityType + ":" + url.QueryEscape(postURN) + ")"
+	endpoint := fmt.Sprintf("%s/memberCreatorPostAnalytics?q=entity&entity=%s&queryType=%s&aggregation=TOTAL",
+		restBaseURL, entity, queryType)
+
+	var result struct {
+		Elements []struct {
+			Count int `json:"count"`
+		} `json:"elements"`
+	}
+
+	headers := map[string]string{"LinkedIn-Version": linkedInVersion}
+	if err := c.get(ctx, endpoint, headers, &result); err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, element := range result.Elements {
+		total += element.Count
+	}
+
+	return total, nil
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, headers map[string]string, result any) error {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("X-Restli-Protocol-Version", "2.0.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call LinkedIn API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
+			return fmt.Errorf("LinkedIn API error (status %d): %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("LinkedIn API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// PerformanceScore weights engagement by effort: a comment or share says
+// more than a like, and views add a little so reach is not ignored.
+func PerformanceScore(metrics map[string]int) float64 {
+	return float64(metrics["likes"]) +
+		3*float64(metrics["comments"]) +
+		5*float64(metrics["shares"]) +
+		float64(metrics["views"])/100
+}
+
+type MetricsSyncer struct {
+	client      *Client
+	postRepo    *database.PostRepository
+	commentRepo *database.CommentRepository
+	interval    time.Duration
+}
+
+func NewMetricsSyncer(client *Client, postRepo *database.PostRepository, commentRepo *database.CommentRepository, interval time.Duration) *MetricsSyncer {
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+
+	return &MetricsSyncer{
+		client:      client,
+		postRepo:    postRepo,
+		commentRepo: commentRepo,
+		interval:    interval,
+	}
+}
+
+func (s *MetricsSyncer) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "linkedin metrics sync started", "interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.syncRecentPosts(ctx)
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "linkedin metrics sync stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncRecentPosts refreshes metrics and comments for posts published
+// within the last 30 days; older posts rarely change and are left alone.
+func (s *MetricsSyncer) syncRecentPosts(ctx context.Context) {
+	posts, err := s.postRepo.GetPublishedSince(ctx, time.Now().Add(-metricsWindow))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch published posts", "error", err)
+		return
+	}
+
+	synced := 0
+	for _, post := range posts {
+		if ctx.Err() != nil {
+			return
+		}
+		if post.LinkedInURN == "" {
+			continue
+		}
+
+		metrics, err := s.client.GetPostMetrics(ctx, post.LinkedInURN)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to fetch metrics for post", "post_id", post.ID, "error", err)
+			continue
+		}
+
+		if err := s.postRepo.UpdateMetrics(ctx, post.ID, metrics, PerformanceScore(metrics)); err != nil {
+			slog.ErrorContext(ctx, "failed to save metrics for post", "post_id", post.ID, "error", err)
+			continue
+		}
+		synced++
+
+		if metrics["comments"] > 0 {
+			if _, err := SyncComments(ctx, s.client, s.commentRepo, post); err != nil {
+				slog.ErrorContext(ctx, "failed to sync comments for post", "post_id", post.ID, "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(metricsRateDelay):
+		}
+	}
+
+	if synced > 0 {
+		slog.InfoContext(ctx, "synced linkedin metrics", "posts", synced)
+	}
+}
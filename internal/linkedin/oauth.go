@@ -0,0 +1,429 @@
+package linkedin
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/dedup"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	authorizeURL = "https://www.linkedin.com/oauth/v2/authorization"
+	tokenURL     = "https://www.linkedin.com/oauth/v2/accessToken"
+	userInfoURL  = "https://api.linkedin.com/v2/userinfo"
+	oauthScopes  = "openid profile w_member_social"
+
+	// organizationScopes let an administrator of a company page post and
+	// comment as the page.
+	organizationScopes = "w_organization_social"
+
+	// organizationState marks the state of a company page authorization.
+	organizationState = "organization:"
+
+	// stateTTL is how long an authorization link works.
+	stateTTL = 15 * time.Minute
+
+	// usedStateCacheSize caps how many used links each replica remembers
+	// locally, in front of the shared backend.
+	usedStateCacheSize = 1000
+)
+
+type TokenSource interface {
+	Token(ctx context.Context) (*models.LinkedInToken, error)
+}
+
+type staticTokenSource struct {
+	token *models.LinkedInToken
+}
+
+func NewStaticTokenSource(accessToken, authorURN string) TokenSource {
+	return &staticTokenSource{
+		token: &models.LinkedInToken{
+			AccessToken: accessToken,
+			AuthorURN:   authorURN,
+			ExpiresAt:   time.Now().AddDate(100, 0, 0),
+		},
+	}
+}
+
+func (s *staticTokenSource) Token(ctx context.Context) (*models.LinkedInToken, error) {
+	return s.token, nil
+}
+
+type OAuthHandler struct {
+	clientID        string
+	clientSecret    string
+	redirectURL     string
+	organizationURN string
+	authorID        string
+	tokenRepo       *database.LinkedInTokenRepository
+	usedStates      dedup.Store
+	httpClient      *http.Client
+
+	// refreshing serializes token refreshes, so concurrent publishes don't
+	// each spend the refresh token.
+	refreshing sync.Mutex
+}
+
+type tokenResponse struct {
+	AccessToken           string `json:"access_token"`
+	ExpiresIn             int    `json:"expires_in"`
+	RefreshToken          string `json:"refresh_token"`
+	RefreshTokenExpiresIn int    `json:"refresh_token_expires_in"`
+	Error                 string `json:"error"`
+	ErrorDescription      string `json:"error_description"`
+}
+
+// NewOAuthHandler connects members' profiles and, when organizationURN is
+// set, the company page it names. Posts are published to the profile the
+// Slack user authorID connected. Authorization links expire after
+// stateTTL and work once: used links are claimed in usedStates for as long
+// as they would still work, whatever TTL other deduplication uses.
+func NewOAuthHandler(clientID, clientSecret, redirectURL, organizationURN, authorID string, tokenRepo *database.LinkedInTokenRepository, usedStates dedup.Backend) *OAuthHandler {
+	return &OAuthHandler{
+		clientID:        clientID,
+		clientSecret:    clientSecret,
+		redirectURL:     redirectURL,
+		organizationURN: organizationURN,
+		authorID:        authorID,
+		tokenRepo:       tokenRepo,
+		usedStates:      dedup.NewPersistent(dedup.NewCache(usedStateCacheSize, stateTTL), usedStates, stateTTL),
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// AuthorID is the Slack user whose profile posts are published to.
+func (h *OAuthHandler) AuthorID() string {
+	return h.authorID
+}
+
+func (h *OAuthHandler) AuthURL(userID string) string {
+	return h.authURL(oauthScopes, userID)
+}
+
+// OrganizationAuthURL links to authorizing posts to the company page. The
+// user must be one of its administrators.
+func (h *OAuthHandler) OrganizationAuthURL(userID string) string {
+	return h.authURL(organizationScopes, organizationState+userID)
+}
+
+// HasOrganization reports whether a company page is configured.
+func (h *OAuthHandler) HasOrganization() bool {
+	return h.organizationURN != ""
+}
+
+func (h *OAuthHandler) authURL(scopes, state string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", h.clientID)
+	params.Set("redirect_uri", h.redirectURL)
+	params.Set("scope", scopes)
+	params.Set("state", h.signState(state))
+
+	return authorizeURL + "?" + params.Encode()
+}
+
+func (h *OAuthHandler) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if errCode := query.Get("error"); errCode != "" {
+		slog.Warn("linkedin authorization denied", "error_code", errCode, "description", query.Get("error_description"))
+		http.Error(w, "LinkedIn authorization was cancelled.", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := h.verifyState(query.Get("state"))
+	if !ok {
+		slog.Warn("invalid linkedin oauth state")
+		http.Error(w, "Invalid or expired authorization link.", http.StatusBadRequest)
+		return
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		http.Error(w, "Missing authorization code.", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	params := url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	params.Set("redirect_uri", h.redirectURL)
+
+	resp, err := h.requestToken(ctx, params)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to exchange linkedin code", "error", err)
+		http.Error(w, "Failed to connect LinkedIn account.", http.StatusBadGateway)
+		return
+	}
+
+	kind := models.TokenMember
+	authorURN := h.organizationURN
+	if id, ok := strings.CutPrefix(userID, organizationState); ok {
+		userID, kind = id, models.TokenOrganization
+	} else {
+		authorURN, err = h.fetchAuthorURN(ctx, resp.AccessToken)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to fetch linkedin profile", "error", err)
+			http.Error(w, "Failed to connect LinkedIn account.", http.StatusBadGateway)
+			return
+		}
+	}
+	if authorURN == "" {
+		slog.WarnContext(ctx, "linkedin company page authorized but none is configured", "user_id", userID)
+		http.Error(w, "No company page is configured.", http.StatusBadRequest)
+		return
+	}
+
+	token := &models.LinkedInToken{
+		UserID:    userID,
+		Kind:      kind,
+		AuthorURN: authorURN,
+	}
+	applyTokenResponse(token, resp)
+
+	if err := h.tokenRepo.Save(ctx, token); err != nil {
+		slog.ErrorContext(ctx, "failed to save linkedin token", "error", err)
+		http.Error(w, "Failed to save LinkedIn connection.", http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(ctx, "linkedin account connected", "user_id", userID, "kind", kind, "author_urn", authorURN)
+
+	message := "LinkedIn account connected! You can close this window and return to Slack."
+	if kind == models.TokenOrganization {
+		message = "LinkedIn company page connected! You can close this window and return to Slack."
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(message))
+}
+
+// Token returns the author's member token, refreshing it first when it is
+// about to expire.
+func (h *OAuthHandler) Token(ctx context.Context) (*models.LinkedInToken, error) {
+	return h.token(ctx, models.TokenMember)
+}
+
+// Organization returns the source of the company page's token.
+func (h *OAuthHandler) Organization() TokenSource {
+	return organizationTokens{h}
+}
+
+type organizationTokens struct {
+	h *OAuthHandler
+}
+
+func (s organizationTokens) Token(ctx context.Context) (*models.LinkedInToken, error) {
+	return s.h.token(ctx, models.TokenOrganization)
+}
+
+func (h *OAuthHandler) token(ctx context.Context, kind string) (*models.LinkedInToken, error) {
+	// The token is read under the lock too, so a publish waiting on
+	// another's refresh gets the refreshed token rather than spending the
+	// refresh token again.
+	h.refreshing.Lock()
+	defer h.refreshing.Unlock()
+
+	var token *models.LinkedInToken
+	var err error
+	if kind == models.TokenOrganization {
+		// Any administrator of the page may connect it.
+		token, err = h.tokenRepo.GetLatest(ctx, kind)
+		if err != nil {
+			return nil, fmt.Errorf("no linkedin company page connected: %w", err)
+		}
+	} else {
+		token, err = h.tokenRepo.GetByUserID(ctx, h.authorID, kind)
+		if err != nil {
+			return nil, fmt.Errorf("no linkedin account connected by the author (%s): %w", h.authorID, err)
+		}
+	}
+
+	if !token.Expired(5 * time.Minute) {
+		return token, nil
+	}
+
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("linkedin token expired and no refresh token is available, reconnect the account")
+	}
+
+	if token.RefreshTokenExpiresAt != nil && time.Now().After(*token.RefreshTokenExpiresAt) {
+		return nil, fmt.Errorf("linkedin refresh token expired, reconnect the account")
+	}
+
+	params := url.Values{}
+	params.Set("grant_type", "refresh_token")
+	params.Set("refresh_token", token.RefreshToken)
+
+	resp, err := h.requestToken(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh linkedin token: %w", err)
+	}
+
+	applyTokenResponse(token, resp)
+
+	if err := h.tokenRepo.Save(ctx, token); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "refreshed linkedin token", "user_id", token.UserID, "kind", kind)
+
+	return token, nil
+}
+
+func (h *OAuthHandler) requestToken(ctx context.Context, params url.Values) (*tokenResponse, error) {
+	params.Set("client_id", h.clientID)
+	params.Set("client_secret", h.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call LinkedIn token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var tokenResp tokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("LinkedIn token error (status %d): %s %s", resp.StatusCode, tokenResp.Error, tokenResp.ErrorDescription)
+	}
+
+	return &tokenResp, nil
+}
+
+func (h *OAuthHandler) fetchAuthorURN(ctx context.Context, accessToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", userInfoURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call LinkedIn userinfo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("LinkedIn userinfo error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var userInfo struct {
+		Sub string `json:"sub"`
+	}
+	if err := json.Unmarshal(body, &userInfo); err != nil {
+		return "", fmt.Errorf("failed to parse userinfo: %w", err)
+	}
+
+	if userInfo.Sub == "" {
+		return "", fmt.Errorf("userinfo response missing member id")
+	}
+
+	return "urn:li:person:" + userInfo.Sub, nil
+}
+
+// signState returns the state of an authorization link for userID:
+// "<userID>.<nonce>.<expiry>.<signature>", signed with the client secret.
+func (h *OAuthHandler) signState(userID string) string {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+
+	payload := fmt.Sprintf("%s.%s.%d", userID, hex.EncodeToString(nonce), time.Now().Add(stateTTL).Unix())
+	return payload + "." + h.stateMAC(payload)
+}
+
+func (h *OAuthHandler) stateMAC(payload string) string {
+	mac := hmac.New(sha256.New, []byte(h.clientSecret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyState returns the user a state was signed for, if it is signed,
+// hasn't expired and wasn't used before. A callback URL captured from a
+// browser's history can't be replayed.
+func (h *OAuthHandler) verifyState(state string) (string, bool) {
+	payload, signature, ok := cutLast(state, ".")
+	if !ok || !hmac.Equal([]byte(h.stateMAC(payload)), []byte(signature)) {
+		return "", false
+	}
+
+	rest, expiry, ok := cutLast(payload, ".")
+	if !ok {
+		return "", false
+	}
+	userID, nonce, ok := cutLast(rest, ".")
+	if !ok || userID == "" || nonce == "" {
+		return "", false
+	}
+
+	seconds, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || time.Now().After(time.Unix(seconds, 0)) {
+		return "", false
+	}
+	if h.usedStates.Seen("linkedin-oauth:" + nonce) {
+		return "", false
+	}
+
+	return userID, true
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+func applyTokenResponse(token *models.LinkedInToken, resp *tokenResponse) {
+	now := time.Now()
+
+	token.AccessToken = resp.AccessToken
+	token.ExpiresAt = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+
+	if resp.RefreshToken != "" {
+		token.RefreshToken = resp.RefreshToken
+	}
+
+	if resp.RefreshTokenExpiresIn > 0 {
+		refreshExpiresAt := now.Add(time.Duration(resp.RefreshTokenExpiresIn) * time.Second)
+		token.RefreshTokenExpiresAt = &refreshExpiresAt
+	}
+}
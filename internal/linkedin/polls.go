@@ -0,0 +1,35 @@
+package linkedin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// CreatePoll publishes poll with content as its commentary, tagging
+// mentions, and returns the post URN.
+func (c *Client) CreatePoll(ctx context.Context, content string, mentions []Mention, poll *models.Poll) (string, error) {
+	if poll == nil {
+		return "", fmt.Errorf("poll post has no poll")
+	}
+	if err := poll.Validate(); err != nil {
+		return "", err
+	}
+
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	restPoll := &restPoll{Question: poll.Question}
+	for _, option := range poll.Options {
+		restPoll.Options = append(restPoll.Options, restPollOption{Text: option})
+	}
+	restPoll.Settings.Duration = poll.Duration
+	if restPoll.Settings.Duration == "" {
+		restPoll.Settings.Duration = models.DefaultPollDuration
+	}
+
+	return c.createRestPost(ctx, token.AccessToken, token.AuthorURN, content, mentions, restPostContent{Poll: restPoll})
+}
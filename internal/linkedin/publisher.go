@@ -0,0 +1,659 @@
+package linkedin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/queue"
+)
+
+type Notifier interface {
+	SendMessage(channelID, message string) error
+}
+
+// CrossPoster copies a post to another network when it is published and
+// returns the ID it got there.
+type CrossPoster interface {
+	CrossPost(ctx context.Context, post *models.Post) (string, error)
+}
+
+// Backlinker tells the sources a published post was written from, such as
+// the issues it came from, where it was published.
+type Backlinker interface {
+	Backlink(ctx context.Context, post *models.Post) error
+}
+
+// DuplicateChecker finds a published post that a post about to go out
+// repeats, or returns nil.
+type DuplicateChecker interface {
+	FindDuplicate(ctx context.Context, post *models.Post) (*database.SimilarPost, error)
+}
+
+// DuplicatePrompter asks whether to publish, rewrite or cancel a post held
+// as a duplicate.
+type DuplicatePrompter interface {
+	PromptDuplicate(ctx context.Context, channelID string, post *models.Post, match *database.SimilarPost) error
+}
+
+// ContentPolicy returns an error explaining why post must not be
+// published, such as it mentioning confidential information, or nil.
+type ContentPolicy interface {
+	Check(post *models.Post) error
+}
+
+// PublishConfirmer shows a post about to go out in channelID, with the
+// choice to publish it now, delay it or cancel it.
+type PublishConfirmer interface {
+	ConfirmPublish(ctx context.Context, channelID string, post *models.Post) error
+}
+
+type Publisher struct {
+	client *Client
+
+	// company publishes as the company page and crossPoster copies posts to
+	// X. Without them, posts targeting the page or X only go to the profile.
+	company     *Client
+	crossPoster CrossPoster
+
+	// backlinker links each published post back to its sources.
+	backlinker Backlinker
+
+	// duplicates holds posts that repeat a published one, and prompter asks
+	// about them in notifyChannel. Without it repeats are published.
+	duplicates DuplicateChecker
+	prompter   DuplicatePrompter
+
+	// policy sends a post it refuses back to drafts, in case it was edited
+	// or the policy changed after it was approved.
+	policy ContentPolicy
+
+	// confirmer sends each post for a last look confirmBefore ahead of
+	// when it is due.
+	confirmer     PublishConfirmer
+	confirmBefore time.Duration
+
+	postRepo *database.PostRepository
+	// contactRepo holds the contacts tagged on LinkedIn when a post names
+	// them.
+	contactRepo *database.ContactRepository
+	// auditRepo records every publish attempt and its outcome.
+	auditRepo *database.AuditRepository
+	// blackoutRepo holds the pauses and blackouts during which nothing is
+	// published, by days in location.
+	blackoutRepo *database.BlackoutRepository
+	// jobs runs the publishing of due posts, so with several replicas each
+	// post goes out once.
+	jobs *queue.Queue
+
+	notifier      Notifier
+	notifyChannel string
+	location      *time.Location
+	interval      time.Duration
+
+	// paused is whether the last check found publishing paused, so only
+	// changes are logged.
+	paused bool
+}
+
+// NewPublisher creates a publisher that checks for due posts every interval
+// and publishes them. company, crossPoster, backlinker, duplicates, policy
+// and confirmer are optional and may be nil.
+func NewPublisher(client *Client, company *Client, crossPoster CrossPoster, backlinker Backlinker, duplicates DuplicateChecker, prompter DuplicatePrompter, policy ContentPolicy, confirmer PublishConfirmer, confirmBefore time.Duration, postRepo *database.PostRepository, contactRepo *database.ContactRepository, auditRepo *database.AuditRepository, blackoutRepo *database.BlackoutRepository, jobs *queue.Queue, notifier Notifier, notifyChannel string, location *time.Location, interval time.Duration) *Publisher {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &Publisher{
+		client:        client,
+		company:       company,
+		crossPoster:   crossPoster,
+		backlinker:    backlinker,
+		duplicates:    duplicates,
+		prompter:      prompter,
+		policy:        policy,
+		confirmer:     confirmer,
+		confirmBefore: confirmBefore,
+		postRepo:      postRepo,
+		contactRepo:   contactRepo,
+		auditRepo:     auditRepo,
+		blackoutRepo:  blackoutRepo,
+		jobs:          jobs,
+		notifier:      notifier,
+		notifyChannel: notifyChannel,
+		location:      location,
+		interval:      interval,
+	}
+}
+
+func (p *Publisher) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "linkedin publisher started", "interval", p.interval)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.publishDuePosts(ctx)
+		p.confirmUpcomingPosts(ctx)
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "linkedin publisher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishJob is a due post waiting in the job queue.
+type publishJob struct {
+	PostID string `json:"post_id"`
+}
+
+// publishDuePosts queues a job for every due post, unless publishing is
+// paused. The key keeps a post from being queued twice while its job waits
+// or runs.
+func (p *Publisher) publishDuePosts(ctx context.Context) {
+	blackout, err := p.blackout(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check blackouts", "error", err)
+		return
+	}
+	if paused := blackout != nil; paused != p.paused {
+		p.paused = paused
+		if paused {
+			slog.InfoContext(ctx, "publishing paused", "kind", blackout.Kind, "reason", blackout.Reason)
+		} else {
+			slog.InfoContext(ctx, "publishing resumed")
+		}
+	}
+	if blackout != nil {
+		return
+	}
+
+	posts, err := p.postRepo.GetScheduledPosts(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch scheduled posts", "error", err)
+		return
+	}
+
+	for _, post := range posts {
+		if _, err := p.jobs.Enqueue(ctx, models.JobPublish, "publish:"+post.ID, publishJob{PostID: post.ID}); err != nil {
+			slog.ErrorContext(ctx, "failed to queue post for publishing", "post_id", post.ID, "error", err)
+		}
+	}
+}
+
+// confirmUpcomingPosts sends the posts due within confirmBefore for a last
+// look, once for each time they are scheduled at, to whoever approved them
+// or else notifyChannel. They go out as planned unless someone acts on the
+// message. A post that couldn't be sent is tried again on the next tick.
+// Nothing is sent while publishing is paused.
+func (p *Publisher) confirmUpcomingPosts(ctx context.Context) {
+	if p.confirmer == nil || p.confirmBefore <= 0 || p.paused {
+		return
+	}
+
+	posts, err := p.postRepo.GetAwaitingConfirmation(ctx, time.Now().Add(p.confirmBefore))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch posts awaiting confirmation", "error", err)
+		return
+	}
+
+	for _, post := range posts {
+		channelID := p.notifyChannel
+		if post.ReviewedBy != nil && *post.ReviewedBy != "" {
+			// Posting to a user ID delivers the message in the bot's DM
+			// with them.
+			channelID = *post.ReviewedBy
+		}
+		if channelID == "" {
+			continue
+		}
+
+		claimed, err := p.postRepo.ClaimConfirmation(ctx, post.ID, *post.ScheduledAt)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to claim confirmation", "post_id", post.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := p.confirmer.ConfirmPublish(ctx, channelID, post); err != nil {
+			slog.ErrorContext(ctx, "failed to send post for confirmation", "post_id", post.ID, "error", err)
+			if err := p.postRepo.ReleaseConfirmation(ctx, post.ID, *post.ScheduledAt); err != nil {
+				slog.ErrorContext(ctx, "failed to release confirmation", "post_id", post.ID, "error", err)
+			}
+			continue
+		}
+		slog.InfoContext(ctx, "sent post for confirmation", "post_id", post.ID, "scheduled_at", post.ScheduledAt)
+	}
+}
+
+// RunJob publishes the post of a queued publish job, unless it was
+// published, unscheduled or moved meanwhile. Publishing isn't retried: a
+// failure marks the post failed.
+func (p *Publisher) RunJob(ctx context.Context, job *models.Job) error {
+	var payload publishJob
+	if err := queue.Decode(job, &payload); err != nil {
+		return err
+	}
+
+	post, err := p.postRepo.GetByID(ctx, payload.PostID)
+	if err != nil {
+		return fmt.Errorf("failed to load post %s: %w", payload.PostID, err)
+	}
+	if post.Status != "scheduled" || post.ScheduledAt == nil || post.ScheduledAt.After(time.Now()) {
+		slog.InfoContext(ctx, "post no longer due, skipping", "post_id", post.ID, "status", post.Status)
+		return nil
+	}
+
+	// The post stays scheduled and is queued again once the pause is over.
+	blackout, err := p.blackout(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check blackouts: %w", err)
+	}
+	if blackout != nil {
+		slog.InfoContext(ctx, "publishing paused, leaving post scheduled", "post_id", post.ID, "kind", blackout.Kind)
+		return nil
+	}
+
+	// Claiming the post first means it can't go out twice, even if its
+	// status can't be saved once it is out.
+	claimed, err := p.postRepo.TransitionStatus(ctx, post.ID, "scheduled", models.StatusPublishing)
+	if err != nil {
+		return fmt.Errorf("failed to claim post %s: %w", post.ID, err)
+	}
+	if !claimed {
+		slog.InfoContext(ctx, "post claimed meanwhile, skipping", "post_id", post.ID)
+		return nil
+	}
+	post.Status = models.StatusPublishing
+
+	// A publish that has started is allowed to finish during shutdown so a
+	// post is never left half-published with a stale status.
+	p.publish(context.WithoutCancel(ctx), post)
+	return nil
+}
+
+// blackout returns the pause or blackout covering today, or nil when
+// posts may go out.
+func (p *Publisher) blackout(ctx context.Context) (*models.Blackout, error) {
+	if p.blackoutRepo == nil {
+		return nil, nil
+	}
+
+	now := time.Now().In(p.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	blackouts, err := p.blackoutRepo.ListActive(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Covering(blackouts, today.Format(models.DateLayout)), nil
+}
+
+func (p *Publisher) publish(ctx context.Context, post *models.Post) {
+	toLinkedIn := post.HasTarget(models.TargetLinkedIn)
+	toCompany := post.HasTarget(models.TargetCompany) && p.company != nil
+	toX := post.HasTarget(models.TargetX) && p.crossPoster != nil
+
+	if !toLinkedIn && !toCompany && !toX {
+		p.fail(ctx, post, fmt.Errorf("neither the company page nor X cross-posting is configured"))
+		return
+	}
+
+	if p.refuse(ctx, post) || p.holdDuplicate(ctx, post) {
+		return
+	}
+
+	if toLinkedIn {
+		postURN, err := p.createPost(ctx, p.client, post)
+		if err != nil {
+			p.fail(ctx, post, fmt.Errorf("LinkedIn: %w", err))
+			return
+		}
+		post.LinkedInURN = postURN
+		post.LinkedInURL = models.LinkedInPostURL(postURN)
+	}
+
+	// Once the post is out somewhere, a failure elsewhere is reported
+	// rather than failing the publish.
+	var companyErr error
+	if toCompany {
+		post.CompanyPostURN, companyErr = p.createPost(ctx, p.company, post)
+		if companyErr != nil {
+			slog.ErrorContext(ctx, "failed to publish to company page", "post_id", post.ID, "error", companyErr)
+			if !toLinkedIn {
+				p.fail(ctx, post, fmt.Errorf("company page: %w", companyErr))
+				return
+			}
+		}
+	}
+
+	var crossPostErr error
+	if toX {
+		post.XPostID, crossPostErr = p.crossPoster.CrossPost(ctx, post)
+		if crossPostErr != nil {
+			slog.ErrorContext(ctx, "failed to cross-post to x", "post_id", post.ID, "error", crossPostErr)
+			if post.LinkedInURN == "" && post.CompanyPostURN == "" && post.XPostID == "" {
+				p.fail(ctx, post, fmt.Errorf("X: %w", crossPostErr))
+				return
+			}
+		}
+	}
+
+	now := time.Now()
+	post.Status = "published"
+	post.PublishedAt = &now
+
+	if err := p.savePublished(ctx, post); err != nil {
+		// The post stays publishing, so it isn't published again, but
+		// nothing links it to what went out until it's fixed by hand.
+		slog.ErrorContext(ctx, "post published but failed to update record", "post_id", post.ID, "post_urn", post.LinkedInURN, "company_post_urn", post.CompanyPostURN, "x_post_id", post.XPostID, "error", err)
+		p.notify(fmt.Sprintf("⚠️ A post was published but saving it failed, so it's stuck as `publishing`. Set it to published by hand (post %s, LinkedIn %s, company page %s, X %s): %v\n\n_%s_", post.ID, orNone(post.LinkedInURN), orNone(post.CompanyPostURN), orNone(post.XPostID), err, preview(post.Content)))
+	}
+
+	slog.InfoContext(ctx, "published post", "post_id", post.ID, "post_urn", post.LinkedInURN, "company_post_urn", post.CompanyPostURN, "x_post_id", post.XPostID)
+
+	// The post is already out, so a failed comment is reported rather than
+	// failing the publish. It goes under the profile's post, or the
+	// page's when only the page published it.
+	var commentErr error
+	if post.FirstComment != "" {
+		comment := format.ToUnicode(post.FirstComment)
+		switch {
+		case post.LinkedInURN != "":
+			commentErr = p.client.CreateComment(ctx, post.LinkedInURN, comment)
+		case post.CompanyPostURN != "":
+			commentErr = p.company.CreateComment(ctx, post.CompanyPostURN, comment)
+		}
+		if commentErr != nil {
+			slog.ErrorContext(ctx, "failed to post first comment", "post_id", post.ID, "post_urn", post.LinkedInURN, "error", commentErr)
+		}
+	}
+
+	var networks, outcomes []string
+	if toLinkedIn {
+		networks = append(networks, "LinkedIn")
+		outcomes = append(outcomes, "LinkedIn "+post.LinkedInURN)
+	}
+	if post.CompanyPostURN != "" {
+		networks = append(networks, "the company page")
+		outcomes = append(outcomes, "company page "+post.CompanyPostURN)
+	}
+	if companyErr != nil {
+		outcomes = append(outcomes, fmt.Sprintf("company page failed: %v", companyErr))
+	}
+	if post.XPostID != "" {
+		networks = append(networks, "X")
+		outcomes = append(outcomes, "X "+post.XPostID)
+	}
+	if crossPostErr != nil {
+		outcomes = append(outcomes, fmt.Sprintf("X failed: %v", crossPostErr))
+	}
+	if commentErr != nil {
+		outcomes = append(outcomes, fmt.Sprintf("first comment failed: %v", commentErr))
+	}
+
+	// Linking back is a courtesy to the team, so a failure is only logged
+	// and audited.
+	if p.backlinker != nil {
+		if err := p.backlinker.Backlink(ctx, post); err != nil {
+			slog.ErrorContext(ctx, "failed to link post back to its sources", "post_id", post.ID, "error", err)
+			outcomes = append(outcomes, fmt.Sprintf("back-link failed: %v", err))
+		}
+	}
+	p.audit(ctx, post, "published", strings.Join(outcomes, "; "))
+
+	message := fmt.Sprintf("Published to %s!", joinNetworks(networks))
+	if companyErr != nil {
+		message += fmt.Sprintf(" Publishing to the company page failed: %v", companyErr)
+	}
+	if crossPostErr != nil {
+		message += fmt.Sprintf(" Cross-posting to X failed: %v", crossPostErr)
+	}
+	if commentErr != nil {
+		message += fmt.Sprintf(" Posting the first comment failed, add it by hand: %v\n\n%s", commentErr, post.FirstComment)
+	}
+	message += fmt.Sprintf("\n\n_%s_", preview(post.Content))
+
+	// The first hour decides how far LinkedIn spreads a post, so the team
+	// gets the link right away.
+	var links []string
+	if post.LinkedInURL != "" {
+		links = append(links, fmt.Sprintf("<%s|View on LinkedIn>", post.LinkedInURL))
+	}
+	if post.CompanyPostURN != "" {
+		links = append(links, fmt.Sprintf("<%s|View on the company page>", models.LinkedInPostURL(post.CompanyPostURN)))
+	}
+	if len(links) > 0 {
+		message += "\n\n👉 " + strings.Join(links, " · ") + ". Early likes and comments help it reach more people."
+	}
+	p.notify(message)
+}
+
+// saveAttempts is how many times a published post's record is saved before
+// giving up, waiting saveBackoff, then twice as long, between attempts.
+const (
+	saveAttempts = 4
+	saveBackoff  = time.Second
+)
+
+// savePublished saves the URNs and published status of a post that is out.
+func (p *Publisher) savePublished(ctx context.Context, post *models.Post) error {
+	backoff := saveBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = p.postRepo.Update(ctx, post); err == nil || attempt == saveAttempts {
+			return err
+		}
+		slog.WarnContext(ctx, "failed to save published post, retrying", "post_id", post.ID, "attempt", attempt, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func orNone(id string) string {
+	if id == "" {
+		return "none"
+	}
+	return id
+}
+
+// refuse sends post back to drafts when the policy refuses it, to be
+// edited and approved again.
+func (p *Publisher) refuse(ctx context.Context, post *models.Post) bool {
+	if p.policy == nil {
+		return false
+	}
+	reason := p.policy.Check(post)
+	if reason == nil {
+		return false
+	}
+
+	slog.WarnContext(ctx, "post refused by content policy", "post_id", post.ID, "reason", reason)
+	if err := p.postRepo.UpdateStatus(ctx, post.ID, "draft"); err != nil {
+		// Left publishing, the post can't go out either way.
+		slog.ErrorContext(ctx, "failed to send refused post back to drafts", "post_id", post.ID, "error", err)
+	}
+	p.audit(ctx, post, "draft", reason.Error())
+	p.notify(fmt.Sprintf("🔒 A scheduled post was not published and is back in drafts: %v. Edit it and approve it again.\n\n_%s_", reason, preview(post.Content)))
+	return true
+}
+
+// holdDuplicate takes post off the schedule and asks about it when it
+// repeats a published post. A failed check doesn't stop the post going out.
+func (p *Publisher) holdDuplicate(ctx context.Context, post *models.Post) bool {
+	if p.duplicates == nil || post.AllowDuplicate {
+		return false
+	}
+
+	match, err := p.duplicates.FindDuplicate(ctx, post)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to check post for duplicates, publishing anyway", "post_id", post.ID, "error", err)
+		return false
+	}
+	if match == nil {
+		return false
+	}
+
+	if err := p.postRepo.UpdateStatus(ctx, post.ID, models.StatusOnHold); err != nil {
+		// Put back on the schedule, the post is checked again on the next
+		// tick.
+		slog.ErrorContext(ctx, "failed to hold duplicate post", "post_id", post.ID, "error", err)
+		if _, err := p.postRepo.TransitionStatus(ctx, post.ID, models.StatusPublishing, "scheduled"); err != nil {
+			slog.ErrorContext(ctx, "failed to put post back on the schedule", "post_id", post.ID, "error", err)
+		}
+		return true
+	}
+
+	slog.InfoContext(ctx, "held duplicate post", "post_id", post.ID, "duplicate_of", match.Post.ID, "similarity", match.Similarity)
+	p.audit(ctx, post, models.StatusOnHold, fmt.Sprintf("held as a duplicate of %s (%.0f%% similar)", match.Post.ID, match.Similarity*100))
+
+	if err := p.prompter.PromptDuplicate(ctx, p.notifyChannel, post, match); err != nil {
+		slog.ErrorContext(ctx, "failed to ask about duplicate post", "post_id", post.ID, "error", err)
+	}
+
+	return true
+}
+
+func (p *Publisher) fail(ctx context.Context, post *models.Post, err error) {
+	slog.ErrorContext(ctx, "failed to publish post", "post_id", post.ID, "error", err)
+	p.audit(ctx, post, "failed", err.Error())
+
+	if err := p.postRepo.UpdateStatus(ctx, post.ID, "failed"); err != nil {
+		slog.ErrorContext(ctx, "failed to mark post as failed", "post_id", post.ID, "error", err)
+	}
+
+	p.notify(fmt.Sprintf("Failed to publish a scheduled post: %v\n\n_%s_", err, preview(post.Content)))
+}
+
+// createPost publishes post through client, as the profile or the page
+// its token belongs to.
+func (p *Publisher) createPost(ctx context.Context, client *Client, post *models.Post) (string, error) {
+	content, mentions := p.mentions(ctx, post)
+
+	if post.PostType == models.PostTypePoll {
+		return client.CreatePoll(ctx, content, mentions, post.Poll)
+	}
+
+	media, err := p.media(ctx, post)
+	if err != nil {
+		return "", err
+	}
+
+	return client.CreatePost(ctx, content, mentions, media)
+}
+
+// joinNetworks lists networks as "A", "A and B" or "A, B and C".
+func joinNetworks(networks []string) string {
+	if len(networks) < 2 {
+		return strings.Join(networks, "")
+	}
+	return strings.Join(networks[:len(networks)-1], ", ") + " and " + networks[len(networks)-1]
+}
+
+// mentions returns the text of post as published, with its emphasis in
+// Unicode letters, and tags the contacts it names. If contacts can't be
+// loaded the post goes out untagged.
+func (p *Publisher) mentions(ctx context.Context, post *models.Post) (string, []Mention) {
+	text := format.ToUnicode(post.Content)
+	if p.contactRepo == nil {
+		return text, nil
+	}
+
+	contacts, err := p.contactRepo.List(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load contacts, publishing without mentions", "post_id", post.ID, "error", err)
+		return text, nil
+	}
+
+	content, mentions := TagMentions(text, models.FindMentions(text, contacts))
+	if len(mentions) > 0 {
+		slog.InfoContext(ctx, "tagging mentions", "post_id", post.ID, "mentions", len(mentions))
+	}
+	return content, mentions
+}
+
+// maxDocumentTitle is well under LinkedIn's limit for document titles.
+const maxDocumentTitle = 100
+
+// media loads the file attached to post. A missing image is logged and the
+// post goes out as text rather than failing. Carousels have no text-only
+// fallback, so a missing document fails the publish.
+func (p *Publisher) media(ctx context.Context, post *models.Post) (*Media, error) {
+	if post.PostType == models.PostTypeCarousel {
+		if post.DocumentPath == "" {
+			return nil, fmt.Errorf("carousel has no rendered document")
+		}
+
+		data, err := os.ReadFile(post.DocumentPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read carousel document: %w", err)
+		}
+
+		title := "Carousel"
+		if len(post.Slides) > 0 {
+			title = strings.Join(strings.Fields(post.Slides[0]), " ")
+		}
+		if runes := []rune(title); len(runes) > maxDocumentTitle {
+			title = string(runes[:maxDocumentTitle])
+		}
+
+		return &Media{Kind: MediaDocument, Data: data, Title: title}, nil
+	}
+
+	if post.ImagePath == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(post.ImagePath)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read post image, publishing text only", "post_id", post.ID, "path", post.ImagePath, "error", err)
+		return nil, nil
+	}
+
+	return &Media{Kind: MediaImage, Data: data, AltText: post.ImageAltText}, nil
+}
+
+func (p *Publisher) notify(message string) {
+	if p.notifier == nil || p.notifyChannel == "" {
+		return
+	}
+
+	if err := p.notifier.SendMessage(p.notifyChannel, message); err != nil {
+		slog.Error("failed to send publish notification", "error", err)
+	}
+}
+
+func preview(content string) string {
+	if runes := []rune(content); len(runes) > 100 {
+		return string(runes[:100]) + "..."
+	}
+	return content
+}
+
+// audit records a publish attempt on post and its outcome.
+func (p *Publisher) audit(ctx context.Context, post *models.Post, outcome, detail string) {
+	entry := &models.AuditEntry{
+		PostID:     post.ID,
+		Action:     models.AuditPublish,
+		FromStatus: "scheduled",
+		ToStatus:   outcome,
+		Detail:     detail,
+	}
+	if err := p.auditRepo.Record(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "failed to record publish attempt", "post_id", post.ID, "error", err)
+	}
+}
@@ -0,0 +1,133 @@
+package linkedin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Posts that ugcPosts can't express, documents and polls, go through the
+// versioned Posts API instead.
+
+type restPostRequest struct {
+	Author                    string           `json:"author"`
+	Commentary                string           `json:"commentary"`
+	Visibility                string           `json:"visibility"`
+	Distribution              restDistribution `json:"distribution"`
+	Content                   restPostContent  `json:"content"`
+	LifecycleState            string           `json:"lifecycleState"`
+	IsReshareDisabledByAuthor bool             `json:"isReshareDisabledByAuthor"`
+}
+
+type restDistribution struct {
+	FeedDistribution               string   `json:"feedDistribution"`
+	TargetEntities                 []string `json:"targetEntities"`
+	ThirdPartyDistributionChannels []string `json:"thirdPartyDistributionChannels"`
+}
+
+type restPostContent struct {
+	Media *restMedia `json:"media,omitempty"`
+	Poll  *restPoll  `json:"poll,omitempty"`
+}
+
+type restMedia struct {
+	Title string `json:"title"`
+	ID    string `json:"id"`
+}
+
+type restPoll struct {
+	Question string           `json:"question"`
+	Options  []restPollOption `json:"options"`
+	Settings struct {
+		Duration string `json:"duration"`
+	} `json:"settings"`
+}
+
+type restPollOption struct {
+	Text string `json:"text"`
+}
+
+// createRestPost publishes commentary, tagging mentions, with content as
+// the author and returns the new post's URN.
+func (c *Client) createRestPost(ctx context.Context, accessToken, authorURN, commentary string, mentions []Mention, content restPostContent) (string, error) {
+	reqBody := restPostRequest{
+		Author:     authorURN,
+		Commentary: littleText(commentary, mentions),
+		Visibility: "PUBLIC",
+		Distribution: restDistribution{
+			FeedDistribution:               "MAIN_FEED",
+			TargetEntities:                 []string{},
+			ThirdPartyDistributionChannels: []string{},
+		},
+		Content:        content,
+		LifecycleState: "PUBLISHED",
+	}
+
+	resp, body, err := c.restPost(ctx, accessToken, restBaseURL+"/posts", reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", restError(resp.StatusCode, body)
+	}
+
+	return resp.Header.Get("X-RestLi-Id"), nil
+}
+
+func (c *Client) restPost(ctx context.Context, accessToken, endpoint string, payload any) (*http.Response, []byte, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("X-Restli-Protocol-Version", "2.0.0")
+	req.Header.Set("LinkedIn-Version", linkedInVersion)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to call LinkedIn API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return resp, body, nil
+}
+
+func restError(status int, body []byte) error {
+	var apiErr apiError
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
+		return fmt.Errorf("LinkedIn API error (status %d): %s", status, apiErr.Message)
+	}
+	return fmt.Errorf("LinkedIn API error (status %d): %s", status, string(body))
+}
+
+// littleTextReserved are the characters the Posts API treats as markup in
+// commentary; unescaped, they truncate or mangle the post.
+const littleTextReserved = `\|{}@[]()<>#*_~`
+
+func escapeLittleText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(littleTextReserved, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
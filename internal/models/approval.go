@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Review roles and decisions recorded in approvals.
+const (
+	RoleAuthor   = "author"
+	RoleReviewer = "reviewer"
+
+	DecisionApproved = "approved"
+	DecisionRejected = "rejected"
+
+	// StatusInReview marks a post one side has approved while the other
+	// hasn't decided yet.
+	StatusInReview = "in_review"
+)
+
+type Approval struct {
+	PostID    string    `json:"post_id" bson:"post_id"`
+	UserID    string    `json:"user_id" bson:"user_id"`
+	Role      string    `json:"role" bson:"role"`
+	Decision  string    `json:"decision" bson:"decision"`
+	DecidedAt time.Time `json:"decided_at" bson:"decided_at"`
+}
+
+func NewApproval(postID, userID, role, decision string) *Approval {
+	return &Approval{
+		PostID:    postID,
+		UserID:    userID,
+		Role:      role,
+		Decision:  decision,
+		DecidedAt: time.Now(),
+	}
+}
+
+// ReviewStatus works out the status of a post that needs both its author
+// and the reviewer to sign off: any rejection rejects it, and it stays in
+// review until both roles have approved.
+func ReviewStatus(approvals []*Approval) string {
+	approved := make(map[string]bool)
+	for _, approval := range approvals {
+		if approval.Decision == DecisionRejected {
+			return DecisionRejected
+		}
+		approved[approval.Role] = true
+	}
+
+	if approved[RoleAuthor] && approved[RoleReviewer] {
+		return DecisionApproved
+	}
+	return StatusInReview
+}
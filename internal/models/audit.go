@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Audit actions recorded in the audit log.
+const (
+	AuditCommand    = "command"
+	AuditTransition = "transition"
+	AuditPublish    = "publish"
+)
+
+// AuditEntry records a command someone ran, a change of a post's status or
+// an attempt to publish a post. Actor is empty for the bot's own workers.
+type AuditEntry struct {
+	ID         string    `json:"id" bson:"_id"`
+	PostID     string    `json:"post_id,omitempty" bson:"post_id,omitempty"`
+	Actor      string    `json:"actor,omitempty" bson:"actor,omitempty"`
+	Action     string    `json:"action" bson:"action"`
+	FromStatus string    `json:"from_status,omitempty" bson:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status,omitempty" bson:"to_status,omitempty"`
+	Detail     string    `json:"detail,omitempty" bson:"detail,omitempty"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Blackout kinds. A blackout is a stretch of dates nothing is published
+// on, such as a vacation or a sensitive company event. A pause stops
+// publishing from the day it is set until a date, or until resumed.
+const (
+	BlackoutDates = "blackout"
+	BlackoutPause = "pause"
+)
+
+// DateLayout is how blackout dates are written and compared.
+const DateLayout = "2006-01-02"
+
+// Blackout keeps posts from being scheduled or published from StartDate
+// through EndDate. A nil EndDate lasts until publishing is resumed.
+type Blackout struct {
+	ID        string     `json:"id" bson:"_id"`
+	Kind      string     `json:"kind" bson:"kind"`
+	StartDate time.Time  `json:"start_date" bson:"start_date"`
+	EndDate   *time.Time `json:"end_date,omitempty" bson:"end_date,omitempty"`
+	Reason    string     `json:"reason,omitempty" bson:"reason,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// Covers reports whether day, a date in DateLayout, falls in the blackout.
+func (b *Blackout) Covers(day string) bool {
+	if day < b.StartDate.Format(DateLayout) {
+		return false
+	}
+	return b.EndDate == nil || day <= b.EndDate.Format(DateLayout)
+}
+
+// Covering returns the first of blackouts that covers day, or nil.
+func Covering(blackouts []*Blackout, day string) *Blackout {
+	for _, blackout := range blackouts {
+		if blackout.Covers(day) {
+			return blackout
+		}
+	}
+	return nil
+}
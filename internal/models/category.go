@@ -0,0 +1,40 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxCategoryName matches the size of thoughts.category.
+const MaxCategoryName = 100
+
+// Category is what thoughts are filed under. Rules are keywords, or
+// regular expressions written as /pattern/, that file a thought under the
+// category without asking the categorizer.
+type Category struct {
+	Name        string    `json:"name" bson:"name"`
+	Description string    `json:"description" bson:"description"`
+	Rules       []string  `json:"rules" bson:"rules"`
+	CreatedBy   string    `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+}
+
+func NewCategory(name, description, createdBy string) *Category {
+	return &Category{
+		Name:        NormalizeCategory(name),
+		Description: strings.TrimSpace(description),
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// NormalizeCategory lowercases name and joins its words with underscores,
+// dropping other punctuation, so "Product Update" and "product-update"
+// both become product_update.
+func NormalizeCategory(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "_")
+}
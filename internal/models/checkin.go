@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// CheckIn is someone's opt-in to a daily DM asking what they worked on.
+// Their replies become thoughts in ChannelID. PendingCategory is the
+// category they picked from the DM's quick replies, which their next reply
+// is filed under.
+type CheckIn struct {
+	UserID          string     `json:"user_id" bson:"user_id"`
+	ChannelID       string     `json:"channel_id" bson:"channel_id"`
+	Time            string     `json:"time" bson:"time"`
+	PendingCategory string     `json:"pending_category,omitempty" bson:"pending_category,omitempty"`
+	LastPromptedAt  *time.Time `json:"last_prompted_at,omitempty" bson:"last_prompted_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// NewCheckIn prompts userID every day at clock, an "HH:MM" time.
+func NewCheckIn(userID, channelID, clock string) *CheckIn {
+	return &CheckIn{
+		UserID:    userID,
+		ChannelID: channelID,
+		Time:      clock,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Comment is a comment someone left on a published post. ReplyDraft is the
+// reply suggested for it, and RepliedAt is set once it was answered.
+type Comment struct {
+	ID          string     `json:"id" bson:"_id"`
+	PostID      string     `json:"post_id" bson:"post_id"`
+	CommentURN  string     `json:"comment_urn" bson:"comment_urn"`
+	AuthorURN   string     `json:"author_urn,omitempty" bson:"author_urn,omitempty"`
+	Content     string     `json:"content" bson:"content"`
+	CommentedAt time.Time  `json:"commented_at" bson:"commented_at"`
+	ReplyDraft  string     `json:"reply_draft,omitempty" bson:"reply_draft,omitempty"`
+	RepliedAt   *time.Time `json:"replied_at,omitempty" bson:"replied_at,omitempty"`
+	RepliedBy   string     `json:"replied_by,omitempty" bson:"replied_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxContactName matches the size of contacts.name.
+const MaxContactName = 200
+
+// Contact is a person or company that posts can mention. LinkedInURN, a
+// urn:li:person or urn:li:organization, is what a mention links to; a
+// contact with only a ProfileURL can be suggested but not tagged.
+type Contact struct {
+	ID          string    `json:"id" bson:"_id"`
+	Name        string    `json:"name" bson:"name"`
+	Aliases     []string  `json:"aliases,omitempty" bson:"aliases,omitempty"`
+	LinkedInURN string    `json:"linkedin_urn,omitempty" bson:"linkedin_urn,omitempty"`
+	ProfileURL  string    `json:"profile_url,omitempty" bson:"profile_url,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+}
+
+func NewContact(name string, aliases []string, urn, profileURL, createdBy string) *Contact {
+	return &Contact{
+		Name:        strings.TrimSpace(name),
+		Aliases:     aliases,
+		LinkedInURN: urn,
+		ProfileURL:  profileURL,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Taggable reports whether posts can @-mention the contact.
+func (c *Contact) Taggable() bool {
+	return c.LinkedInURN != ""
+}
+
+// Mention is a contact named in a post. Start and End are the byte offsets
+// of the name, or the alias, as it appears in the post.
+type Mention struct {
+	Contact    *Contact
+	Start, End int
+}
+
+// FindMentions finds the first place each contact is named in content, by
+// name or alias, as a whole word and ignoring case. Mentions don't overlap
+// and are in the order they appear; a longer name wins over a shorter one
+// starting at the same place.
+func FindMentions(content string, contacts []*Contact) []Mention {
+	var mentions []Mention
+	for _, contact := range contacts {
+		names := append([]string{contact.Name}, contact.Aliases...)
+		var first []int
+		for _, name := range names {
+			if strings.TrimSpace(name) == "" {
+				continue
+			}
+			pattern := regexp.MustCompile(`(?i)(^|[^\pL\pN])(` + regexp.QuoteMeta(name) + `)($|[^\pL\pN])`)
+			if loc := pattern.FindStringSubmatchIndex(content); loc != nil {
+				if first == nil || loc[4] < first[0] || (loc[4] == first[0] && loc[5] > first[1]) {
+					first = []int{loc[4], loc[5]}
+				}
+			}
+		}
+		if first != nil {
+			mentions = append(mentions, Mention{Contact: contact, Start: first[0], End: first[1]})
+		}
+	}
+
+	slices.SortStableFunc(mentions, func(a, b Mention) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return (b.End - b.Start) - (a.End - a.Start)
+	})
+
+	var kept []Mention
+	for _, mention := range mentions {
+		if len(kept) > 0 && mention.Start < kept[len(kept)-1].End {
+			continue
+		}
+		kept = append(kept, mention)
+	}
+	return kept
+}
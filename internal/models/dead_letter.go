@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// DeadLetter is a webhook delivery that could not be processed, kept so it
+// can be looked into and replayed by hand.
+type DeadLetter struct {
+	ID         string    `json:"id" bson:"_id"`
+	Source     string    `json:"source" bson:"source"`
+	DeliveryID string    `json:"delivery_id,omitempty" bson:"delivery_id,omitempty"`
+	Payload    string    `json:"payload" bson:"payload"`
+	Error      string    `json:"error" bson:"error"`
+	Attempts   int       `json:"attempts" bson:"attempts"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
+
+func NewDeadLetter(source, deliveryID, payload, err string, attempts int) *DeadLetter {
+	return &DeadLetter{
+		Source:     source,
+		DeliveryID: deliveryID,
+		Payload:    payload,
+		Error:      err,
+		Attempts:   attempts,
+		CreatedAt:  time.Now(),
+	}
+}
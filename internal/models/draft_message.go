@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type DraftMessage struct {
+	MessageTS string    `json:"message_ts" bson:"_id"`
+	ChannelID string    `json:"channel_id" bson:"channel_id"`
+	PostIDs   []string  `json:"post_ids" bson:"post_ids"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+func NewDraftMessage(channelID, messageTS string, postIDs []string) *DraftMessage {
+	return &DraftMessage{
+		MessageTS: messageTS,
+		ChannelID: channelID,
+		PostIDs:   postIDs,
+		CreatedAt: time.Now(),
+	}
+}
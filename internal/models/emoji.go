@@ -0,0 +1,162 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Emoji policies say how many emojis a post may have.
+const (
+	EmojiNone       = "none"
+	EmojiMinimal    = "minimal"
+	EmojiExpressive = "expressive"
+)
+
+// EmojiPolicies lists the policies from fewest emojis to most.
+var EmojiPolicies = []string{EmojiNone, EmojiMinimal, EmojiExpressive}
+
+// Most emojis a post keeps under each policy.
+const (
+	MaxMinimalEmoji    = 2
+	MaxExpressiveEmoji = 10
+)
+
+// ParseEmojiPolicy returns the policy named s.
+func ParseEmojiPolicy(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, policy := range EmojiPolicies {
+		if s == policy {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown emoji policy %q, use one of %s", s, strings.Join(EmojiPolicies, ", "))
+}
+
+var (
+	repeatedSpaces  = regexp.MustCompile(`[ \t]{2,}`)
+	repeatedNewline = regexp.MustCompile(`\n{3,}`)
+)
+
+// ApplyEmojiPolicy strips the emojis content has beyond what policy allows:
+// all of them for none, all but the first two for minimal. Expressive keeps
+// up to ten, with repeats in a row such as "🔥🔥🔥" collapsed into one.
+func ApplyEmojiPolicy(content, policy string) string {
+	limit := MaxExpressiveEmoji
+	switch policy {
+	case EmojiNone:
+		limit = 0
+	case EmojiMinimal:
+		limit = MaxMinimalEmoji
+	case EmojiExpressive:
+	default:
+		return content
+	}
+
+	spans := emojiSpans(content)
+	var b strings.Builder
+	kept, last := 0, 0
+	changed := false
+	for i, span := range spans {
+		emoji := content[span[0]:span[1]]
+		b.WriteString(content[last:span[0]])
+		last = span[1]
+
+		repeat := policy == EmojiExpressive && i > 0 && spans[i-1][1] == span[0] &&
+			content[spans[i-1][0]:spans[i-1][1]] == emoji
+		if kept >= limit || repeat {
+			changed = true
+			continue
+		}
+		kept++
+		b.WriteString(emoji)
+	}
+	if !changed {
+		return content
+	}
+	b.WriteString(content[last:])
+
+	// Tidy the gaps the emojis leave, such as a line that started with one.
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(repeatedSpaces.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(repeatedNewline.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// emojiSpans returns the byte ranges of the emojis in content. An emoji
+// spans its modifiers, such as a skin tone, and the emojis joined to it,
+// so "👩🏽‍💻" is one emoji.
+func emojiSpans(content string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		end := i + size
+
+		switch {
+		case isKeycapBase(r):
+			// A keycap such as 1️⃣ is a digit, an optional variation
+			// selector and the enclosing keycap.
+			next := end
+			if r2, s := utf8.DecodeRuneInString(content[next:]); r2 == 0xFE0F {
+				next += s
+			}
+			if r2, s := utf8.DecodeRuneInString(content[next:]); r2 == 0x20E3 {
+				spans = append(spans, [2]int{i, next + s})
+				i = next + s
+				continue
+			}
+
+		case isRegionalIndicator(r):
+			// A flag is a pair of regional indicators.
+			if r2, s := utf8.DecodeRuneInString(content[end:]); isRegionalIndicator(r2) {
+				end += s
+			}
+			spans = append(spans, [2]int{i, end})
+			i = end
+			continue
+
+		case isEmoji(r):
+			for end < len(content) {
+				r2, s := utf8.DecodeRuneInString(content[end:])
+				if isEmojiModifier(r2) {
+					end += s
+					continue
+				}
+				if r2 == 0x200D {
+					if r3, s3 := utf8.DecodeRuneInString(content[end+s:]); isEmoji(r3) {
+						end += s + s3
+						continue
+					}
+				}
+				break
+			}
+			spans = append(spans, [2]int{i, end})
+		}
+
+		i = end
+	}
+	return spans
+}
+
+func isEmoji(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF && !isRegionalIndicator(r) && !isEmojiModifier(r)) ||
+		(r >= 0x2600 && r <= 0x27BF) ||
+		(r >= 0x2B00 && r <= 0x2BFF) ||
+		(r >= 0x231A && r <= 0x23FF)
+}
+
+// isEmojiModifier reports whether r changes the emoji before it: a
+// variation selector, a skin tone or a tag.
+func isEmojiModifier(r rune) bool {
+	return r == 0xFE0F || r == 0xFE0E || (r >= 0x1F3FB && r <= 0x1F3FF) || (r >= 0xE0020 && r <= 0xE007F)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isKeycapBase(r rune) bool {
+	return (r >= '0' && r <= '9') || r == '#' || r == '*'
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// SettleDays is how long after publishing a post's metrics are snapshotted
+// as settled, so posts of different ages can be compared at the same age.
+const SettleDays = 7
+
+// Experiment compares two variations of the same post, published a week
+// apart, by how they performed.
+type Experiment struct {
+	ID             string     `json:"id" bson:"_id"`
+	SlackChannelID string     `json:"slack_channel_id,omitempty" bson:"slack_channel_id,omitempty"`
+	CreatedBy      string     `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" bson:"created_at"`
+	WinnerPostID   *string    `json:"winner_post_id,omitempty" bson:"winner_post_id,omitempty"`
+	ConcludedAt    *time.Time `json:"concluded_at,omitempty" bson:"concluded_at,omitempty"`
+	// Posts are the variations in the order they are published.
+	Posts []*Post `json:"posts,omitempty" bson:"-"`
+}
+
+func NewExperiment(channelID, createdBy string) *Experiment {
+	return &Experiment{
+		SlackChannelID: channelID,
+		CreatedBy:      createdBy,
+		CreatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+const (
+	ClaimSupported    = "supported"
+	ClaimUnsupported  = "unsupported"
+	ClaimContradicted = "contradicted"
+)
+
+// FactCheck is what a verification pass found in a draft: how many factual
+// claims it checked against the thoughts the draft was written from, and
+// the ones they don't back up.
+type FactCheck struct {
+	Checked int         `json:"checked"`
+	Flags   []ClaimFlag `json:"flags,omitempty"`
+}
+
+// ClaimFlag is a claim in a draft that its source thoughts don't mention,
+// or say otherwise, so the model may have made it up.
+type ClaimFlag struct {
+	Claim   string `json:"claim"`
+	Verdict string `json:"verdict"`
+	Note    string `json:"note,omitempty"`
+}
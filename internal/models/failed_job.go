@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Failed job kinds.
+const (
+	// FailedCategorize is a thought saved as uncategorized because the
+	// categorizer failed.
+	FailedCategorize = "categorize"
+	// FailedGenerate is a generate command that didn't produce drafts.
+	FailedGenerate = "generate"
+)
+
+// FailedJob is an agent operation that failed and is retried later.
+type FailedJob struct {
+	ID        string `json:"id" bson:"_id"`
+	Kind      string `json:"kind" bson:"kind"`
+	ThoughtID string `json:"thought_id,omitempty" bson:"thought_id,omitempty"`
+	ChannelID string `json:"slack_channel_id,omitempty" bson:"slack_channel_id,omitempty"`
+	UserID    string `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	// Request is the command to run again, such as "generate poll hiring".
+	Request  string `json:"request,omitempty" bson:"request,omitempty"`
+	Error    string `json:"error" bson:"error"`
+	Attempts int    `json:"attempts" bson:"attempts"`
+	// NextAttemptAt is nil once the job ran out of attempts.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" bson:"next_attempt_at,omitempty"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// NewFailedJob records the first failed attempt of an operation, due to be
+// retried straight away.
+func NewFailedJob(kind, channelID, userID, request string, err error) *FailedJob {
+	now := time.Now()
+	return &FailedJob{
+		Kind:          kind,
+		ChannelID:     channelID,
+		UserID:        userID,
+		Request:       request,
+		Error:         err.Error(),
+		Attempts:      1,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+}
+
+// Describe names what the job does, for messages.
+func (j *FailedJob) Describe() string {
+	if j.Kind == FailedCategorize {
+		return "categorizing thought `" + j.ThoughtID + "`"
+	}
+	return "`" + j.Request + "`"
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Import job statuses.
+const (
+	ImportQueued    = "queued"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+	// ImportInterrupted marks a job the bot stopped before it finished. It
+	// picks up where it left off once a worker takes it again.
+	ImportInterrupted = "interrupted"
+)
+
+// ImportJob is a bulk import. ProgressTS is the Slack message in
+// SlackChannelID that shows its progress.
+type ImportJob struct {
+	ID             string     `json:"id" bson:"_id"`
+	Filename       string     `json:"filename" bson:"filename"`
+	SlackChannelID string     `json:"slack_channel_id,omitempty" bson:"slack_channel_id,omitempty"`
+	RequestedBy    string     `json:"requested_by,omitempty" bson:"requested_by,omitempty"`
+	ProgressTS     string     `json:"progress_ts,omitempty" bson:"progress_ts,omitempty"`
+	Status         string     `json:"status" bson:"status"`
+	Total          int        `json:"total" bson:"total"`
+	Imported       int        `json:"imported" bson:"imported"`
+	Skipped        int        `json:"skipped" bson:"skipped"`
+	Failed         int        `json:"failed" bson:"failed"`
+	Error          string     `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" bson:"created_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+func NewImportJob(filename, channelID, requestedBy string, total int) *ImportJob {
+	return &ImportJob{
+		Filename:       filename,
+		SlackChannelID: channelID,
+		RequestedBy:    requestedBy,
+		Status:         ImportQueued,
+		Total:          total,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// Processed counts the notes the job has finished with either way.
+func (j *ImportJob) Processed() int {
+	return j.Imported + j.Skipped + j.Failed
+}
+
+// Done reports whether the job has finished for good.
+func (j *ImportJob) Done() bool {
+	return j.Status == ImportCompleted || j.Status == ImportFailed
+}
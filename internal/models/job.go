@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job states.
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// Job kinds.
+const (
+	JobGenerate   = "generate"
+	JobBrainstorm = "brainstorm"
+	JobImport     = "import"
+	JobPublish    = "publish"
+)
+
+// Job is a unit of long-running work in the job queue.
+type Job struct {
+	ID   string `json:"id" bson:"_id"`
+	Kind string `json:"kind" bson:"kind"`
+	// Key keeps a second job for the same work from being queued while one
+	// is queued or running.
+	Key         string          `json:"key,omitempty" bson:"key,omitempty"`
+	Payload     json.RawMessage `json:"payload" bson:"payload"`
+	Actor       string          `json:"actor,omitempty" bson:"actor,omitempty"`
+	State       string          `json:"state" bson:"state"`
+	Attempts    int             `json:"attempts" bson:"attempts"`
+	MaxAttempts int             `json:"max_attempts" bson:"max_attempts"`
+	RunAt       time.Time       `json:"run_at" bson:"run_at"`
+	LockedBy    string          `json:"locked_by,omitempty" bson:"locked_by,omitempty"`
+	LeaseUntil  *time.Time      `json:"lease_until,omitempty" bson:"lease_until,omitempty"`
+	LastError   string          `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at" bson:"created_at"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+func NewJob(kind, key string, payload json.RawMessage, maxAttempts int) *Job {
+	now := time.Now()
+	return &Job{
+		Kind:        kind,
+		Key:         key,
+		Payload:     payload,
+		State:       JobQueued,
+		MaxAttempts: maxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the language posts are written in unless a user or
+// POST_LANGUAGE asks for another.
+const DefaultLanguage = "en"
+
+// Languages maps the codes of the languages posts can be written in to
+// their English names.
+var Languages = map[string]string{
+	"en": "English",
+	"de": "German",
+	"hi": "Hindi",
+	"es": "Spanish",
+	"fr": "French",
+	"pt": "Portuguese",
+	"it": "Italian",
+	"nl": "Dutch",
+}
+
+// ParseLanguage returns the code of a language given by code or name, as in
+// "de" or "German".
+func ParseLanguage(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if _, ok := Languages[s]; ok {
+		return s, nil
+	}
+	for code, name := range Languages {
+		if strings.ToLower(name) == s {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("unknown language %q, use one of %s", s, strings.Join(LanguageCodes(), ", "))
+}
+
+// LanguageName returns the name of the language with code, falling back to
+// the code itself.
+func LanguageName(code string) string {
+	if name, ok := Languages[code]; ok {
+		return name
+	}
+	return code
+}
+
+// LanguageCodes returns the supported language codes in order.
+func LanguageCodes() []string {
+	codes := make([]string, 0, len(Languages))
+	for code := range Languages {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
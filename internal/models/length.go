@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Post length presets.
+const (
+	LengthShort  = "short"
+	LengthMedium = "medium"
+	LengthLong   = "long"
+)
+
+// LengthPresets lists the presets from shortest to longest.
+var LengthPresets = []string{LengthShort, LengthMedium, LengthLong}
+
+// Bounds of an explicit word target. LinkedIn caps posts at 3000
+// characters, which the longest target stays well within.
+const (
+	MinWordTarget = 40
+	MaxWordTarget = 450
+)
+
+// PostLength is the range of words a post should have.
+type PostLength struct {
+	MinWords int
+	MaxWords int
+}
+
+var lengthPresets = map[string]PostLength{
+	LengthShort:  {MinWords: 60, MaxWords: 150},
+	LengthMedium: {MinWords: 150, MaxWords: 300},
+	LengthLong:   {MinWords: 300, MaxWords: MaxWordTarget},
+}
+
+// DefaultPostLength is the medium preset, the length posts have always
+// been written at.
+var DefaultPostLength = lengthPresets[LengthMedium]
+
+// ParsePostLength returns the length named by s: a preset, or a number of
+// words to aim for.
+func ParsePostLength(s string) (PostLength, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if length, ok := lengthPresets[s]; ok {
+		return length, nil
+	}
+
+	words, err := strconv.Atoi(s)
+	if err != nil {
+		return PostLength{}, fmt.Errorf("unknown post length %q, use one of %s or a number of words", s, strings.Join(LengthPresets, ", "))
+	}
+	return WordTarget(words)
+}
+
+// WordTarget returns the range around a target of words, give or take a
+// tenth.
+func WordTarget(words int) (PostLength, error) {
+	if words < MinWordTarget || words > MaxWordTarget {
+		return PostLength{}, fmt.Errorf("word target must be between %d and %d, got %d", MinWordTarget, MaxWordTarget, words)
+	}
+	margin := max(words/10, 5)
+	return PostLength{MinWords: words - margin, MaxWords: words + margin}, nil
+}
+
+// String describes the length as the prompts ask for it.
+func (l PostLength) String() string {
+	return fmt.Sprintf("%d-%d words", l.MinWords, l.MaxWords)
+}
+
+// Overshoots reports whether content is too long to pass for the length.
+// A few words over are let through, since a trim that close would do more
+// harm than good.
+func (l PostLength) Overshoots(content string) bool {
+	return CountWords(content) > l.MaxWords+max(l.MaxWords/10, 10)
+}
+
+// CountWords returns the number of words in content. Emojis, bullets and
+// other lone symbols aren't words.
+func CountWords(content string) int {
+	words := 0
+	for _, field := range strings.Fields(content) {
+		if strings.IndexFunc(field, isWordRune) >= 0 {
+			words++
+		}
+	}
+	return words
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Token kinds. A member token publishes as the person who connected it,
+// an organization token as the company page they administer.
+const (
+	TokenMember       = "member"
+	TokenOrganization = "organization"
+)
+
+type LinkedInToken struct {
+	ID                    string     `json:"id" bson:"_id"`
+	UserID                string     `json:"user_id" bson:"user_id"`
+	Kind                  string     `json:"kind" bson:"kind"`
+	AuthorURN             string     `json:"author_urn" bson:"author_urn"`
+	AccessToken           string     `json:"-" bson:"access_token"`
+	RefreshToken          string     `json:"-" bson:"refresh_token"`
+	ExpiresAt             time.Time  `json:"expires_at" bson:"expires_at"`
+	RefreshTokenExpiresAt *time.Time `json:"refresh_token_expires_at,omitempty" bson:"refresh_token_expires_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+func (t *LinkedInToken) Expired(margin time.Duration) bool {
+	return time.Now().Add(margin).After(t.ExpiresAt)
+}
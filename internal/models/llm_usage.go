@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// LLMUsage is one model call: the tokens it took and what they cost, in US
+// dollars, with the command and user it was made for when known.
+type LLMUsage struct {
+	ID           string    `json:"id" bson:"_id"`
+	Provider     string    `json:"provider" bson:"provider"`
+	Model        string    `json:"model" bson:"model"`
+	Role         string    `json:"role" bson:"role"`
+	Command      string    `json:"command,omitempty" bson:"command,omitempty"`
+	UserID       string    `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	InputTokens  int       `json:"input_tokens" bson:"input_tokens"`
+	OutputTokens int       `json:"output_tokens" bson:"output_tokens"`
+	Cost         float64   `json:"cost" bson:"cost"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+}
+
+// UsageTotal adds up the model calls made for one command, user or model.
+type UsageTotal struct {
+	Key          string
+	Calls        int
+	InputTokens  int
+	OutputTokens int
+	Cost         float64
+}
@@ -1,20 +1,132 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// PostTypeCarousel posts are published as a PDF document, one page per
+// slide, with Content as the commentary above it.
+const PostTypeCarousel = "carousel"
+
+// PostTypePoll posts are published as a LinkedIn poll, with Content as the
+// commentary above it.
+const PostTypePoll = "poll"
+
+// Limits LinkedIn enforces on polls.
+const (
+	PollMaxQuestionChars = 140
+	PollMaxOptionChars   = 30
+	PollMinOptions       = 2
+	PollMaxOptions       = 4
+)
+
+// DefaultPollDuration is how long a poll stays open; LinkedIn accepts
+// ONE_DAY, THREE_DAYS, SEVEN_DAYS and FOURTEEN_DAYS.
+const DefaultPollDuration = "THREE_DAYS"
+
+// StatusOnHold posts were due to be published but read like a post already
+// published, and wait for the author to publish, rewrite or cancel them.
+// Publishing one anyway sets AllowDuplicate so it isn't held again.
+const StatusOnHold = "on_hold"
+
+// StatusPublishing posts have been claimed by the publisher and are being
+// sent to LinkedIn. A post left publishing may already be out, so it is
+// never picked up again and needs checking by hand.
+const StatusPublishing = "publishing"
+
+// Networks a post can be published to. A post without targets goes to
+// LinkedIn only. TargetCompany is the LinkedIn company page.
+const (
+	TargetLinkedIn = "linkedin"
+	TargetCompany  = "company"
+	TargetX        = "x"
+)
+
+type Poll struct {
+	Question string   `json:"question" bson:"question"`
+	Options  []string `json:"options" bson:"options"`
+	Duration string   `json:"duration" bson:"duration"`
+}
+
+// Validate checks the poll against LinkedIn's limits.
+func (p *Poll) Validate() error {
+	if p.Question == "" {
+		return fmt.Errorf("poll has no question")
+	}
+	if utf8.RuneCountInString(p.Question) > PollMaxQuestionChars {
+		return fmt.Errorf("poll question is longer than %d characters", PollMaxQuestionChars)
+	}
+	if len(p.Options) < PollMinOptions || len(p.Options) > PollMaxOptions {
+		return fmt.Errorf("poll needs %d-%d options, got %d", PollMinOptions, PollMaxOptions, len(p.Options))
+	}
+	for _, option := range p.Options {
+		if option == "" || utf8.RuneCountInString(option) > PollMaxOptionChars {
+			return fmt.Errorf("poll option %q must be 1-%d characters", option, PollMaxOptionChars)
+		}
+	}
+
+	return nil
+}
 
 type Post struct {
-	ID                  string            `json:"id" bson:"_id"`
-	Content             string            `json:"content" bson:"content"`
-	Status              string            `json:"status" bson:"status"`
-	SourceThoughtIDs    []string          `json:"source_thought_ids" bson:"source_thought_ids"`
-	BrainstormSessionID *string           `json:"brainstorm_session_id,omitempty" bson:"brainstorm_session_id,omitempty"`
-	PostType            string            `json:"post_type" bson:"post_type"`
-	Tone                string            `json:"tone" bson:"tone"`
-	CreatedAt           time.Time         `json:"created_at" bson:"created_at"`
-	ScheduledAt         *time.Time        `json:"scheduled_at,omitempty" bson:"scheduled_at,omitempty"`
-	PublishedAt         *time.Time        `json:"published_at,omitempty" bson:"published_at,omitempty"`
-	Metrics             map[string]int    `json:"metrics" bson:"metrics"`
-	PerformanceScore    float64           `json:"performance_score" bson:"performance_score"`
+	ID                  string         `json:"id" bson:"_id"`
+	Content             string         `json:"content" bson:"content"`
+	Status              string         `json:"status" bson:"status"`
+	SourceThoughtIDs    []string       `json:"source_thought_ids" bson:"source_thought_ids"`
+	BrainstormSessionID *string        `json:"brainstorm_session_id,omitempty" bson:"brainstorm_session_id,omitempty"`
+	ParentPostID        *string        `json:"parent_post_id,omitempty" bson:"parent_post_id,omitempty"`
+	PostType            string         `json:"post_type" bson:"post_type"`
+	Tone                string         `json:"tone" bson:"tone"`
+	CreatedAt           time.Time      `json:"created_at" bson:"created_at"`
+	ScheduledAt         *time.Time     `json:"scheduled_at,omitempty" bson:"scheduled_at,omitempty"`
+	PublishedAt         *time.Time     `json:"published_at,omitempty" bson:"published_at,omitempty"`
+	Metrics             map[string]int `json:"metrics" bson:"metrics"`
+	PerformanceScore    float64        `json:"performance_score" bson:"performance_score"`
+	ReviewedBy          *string        `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt          *time.Time     `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+	LinkedInURN         string         `json:"linkedin_urn,omitempty" bson:"linkedin_urn,omitempty"`
+	LinkedInURL         string         `json:"linkedin_url,omitempty" bson:"linkedin_url,omitempty"`
+	MetricsSyncedAt     *time.Time     `json:"metrics_synced_at,omitempty" bson:"metrics_synced_at,omitempty"`
+	SettledMetrics      map[string]int `json:"settled_metrics,omitempty" bson:"settled_metrics,omitempty"`
+	SettledScore        *float64       `json:"settled_score,omitempty" bson:"settled_score,omitempty"`
+	ImageConcept        string         `json:"image_concept,omitempty" bson:"image_concept,omitempty"`
+	ImagePath           string         `json:"image_path,omitempty" bson:"image_path,omitempty"`
+	ImageAltText        string         `json:"image_alt_text,omitempty" bson:"image_alt_text,omitempty"`
+	Slides              []string       `json:"slides,omitempty" bson:"slides,omitempty"`
+	DocumentPath        string         `json:"document_path,omitempty" bson:"document_path,omitempty"`
+	Poll                *Poll          `json:"poll,omitempty" bson:"poll,omitempty"`
+	Targets             []string       `json:"targets,omitempty" bson:"targets,omitempty"`
+	XPostID             string         `json:"x_post_id,omitempty" bson:"x_post_id,omitempty"`
+	CompanyPostURN      string         `json:"company_post_urn,omitempty" bson:"company_post_urn,omitempty"`
+	AllowDuplicate      bool           `json:"allow_duplicate,omitempty" bson:"allow_duplicate,omitempty"`
+	Scores              *PostScores    `json:"scores,omitempty" bson:"scores,omitempty"`
+	ExperimentID        *string        `json:"experiment_id,omitempty" bson:"experiment_id,omitempty"`
+	FirstComment        string         `json:"first_comment,omitempty" bson:"first_comment,omitempty"`
+	Language            string         `json:"language,omitempty" bson:"language,omitempty"`
+	TranslationOf       *string        `json:"translation_of,omitempty" bson:"translation_of,omitempty"`
+	Provenance          []Provenance   `json:"provenance,omitempty" bson:"provenance,omitempty"`
+	FactCheck           *FactCheck     `json:"fact_check,omitempty" bson:"fact_check,omitempty"`
+	JobID               string         `json:"job_id,omitempty" bson:"job_id,omitempty"`
+}
+
+// LinkedInPostURL returns the canonical URL of the LinkedIn post with urn.
+func LinkedInPostURL(urn string) string {
+	return "https://www.linkedin.com/feed/update/" + urn + "/"
+}
+
+// HasTarget reports whether the post should be published to target.
+func (p *Post) HasTarget(target string) bool {
+	if len(p.Targets) == 0 {
+		return target == TargetLinkedIn
+	}
+	for _, t := range p.Targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
 }
 
 func NewPost(content string, thoughtIDs []string, postType, tone string) *Post {
@@ -32,5 +144,6 @@ func NewPost(content string, thoughtIDs []string, postType, tone string) *Post {
 			"views":    0,
 		},
 		PerformanceScore: 0.0,
+		Language:         DefaultLanguage,
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Revision sources record what produced a version of a post's content.
+const (
+	RevisionGeneration = "generation"
+	RevisionFeedback   = "revision"
+	RevisionEdit       = "edit"
+	RevisionRestore    = "restore"
+)
+
+type PostRevision struct {
+	ID        string    `json:"id" bson:"_id"`
+	PostID    string    `json:"post_id" bson:"post_id"`
+	Content   string    `json:"content" bson:"content"`
+	Source    string    `json:"source" bson:"source"`
+	Editor    string    `json:"editor,omitempty" bson:"editor,omitempty"`
+	Note      string    `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// PostScores rate how a post reads. They are stored with the post so they
+// can later be compared with how it performed.
+type PostScores struct {
+	// HookStrength rates the opening line, 0-100.
+	HookStrength int `json:"hook_strength" bson:"hook_strength"`
+	// ReadingGrade is the Flesch-Kincaid grade level; LinkedIn posts read
+	// best around 6-9.
+	ReadingGrade float64 `json:"reading_grade" bson:"reading_grade"`
+	// SentenceVariance is the variance of sentence lengths in words. Low
+	// values read as monotonous.
+	SentenceVariance float64 `json:"sentence_variance" bson:"sentence_variance"`
+	EmojiCount       int     `json:"emoji_count" bson:"emoji_count"`
+}
+
+var (
+	sentenceBreak = regexp.MustCompile(`[.!?]+["')\]]*(\s+|$)|\n+`)
+	vowelGroup    = regexp.MustCompile(`[aeiouy]+`)
+	// weakOpeners are ways to start a post that give the reader no reason
+	// to keep going.
+	weakOpeners = regexp.MustCompile(`(?i)^(i'?m (so )?(excited|thrilled|happy|humbled|proud)|i wanted to share|in today'?s|as (a|an) \w+,|hello|hi (everyone|all|linkedin)|happy (monday|friday))`)
+)
+
+// ScoreContent scores the text of a post.
+func ScoreContent(content string) *PostScores {
+	sentences := splitSentences(content)
+
+	var words, syllables int
+	lengths := make([]float64, 0, len(sentences))
+	for _, sentence := range sentences {
+		sentenceWords := wordsOf(sentence)
+		if len(sentenceWords) == 0 {
+			continue
+		}
+		lengths = append(lengths, float64(len(sentenceWords)))
+		words += len(sentenceWords)
+		for _, word := range sentenceWords {
+			syllables += countSyllables(word)
+		}
+	}
+
+	scores := &PostScores{
+		HookStrength: hookStrength(content),
+		EmojiCount:   countEmoji(content),
+	}
+	if words > 0 {
+		grade := 0.39*float64(words)/float64(len(lengths)) + 11.8*float64(syllables)/float64(words) - 15.59
+		scores.ReadingGrade = math.Round(math.Max(grade, 0)*10) / 10
+		scores.SentenceVariance = math.Round(variance(lengths)*10) / 10
+	}
+
+	return scores
+}
+
+// hookStrength rates the first line: short, concrete openers that stand on
+// their own above the fold score well, generic ones poorly.
+func hookStrength(content string) int {
+	content = strings.TrimSpace(content)
+	hook, rest, _ := strings.Cut(content, "\n")
+	hook = strings.TrimSpace(hook)
+	words := len(wordsOf(hook))
+	if words == 0 {
+		return 0
+	}
+
+	score := 50
+	switch {
+	case words <= 12:
+		score += 15
+	case words > 25:
+		score -= 20
+	}
+	if strings.IndexFunc(hook, unicode.IsDigit) >= 0 {
+		score += 10
+	}
+	if strings.HasSuffix(hook, "?") {
+		score += 10
+	}
+	if strings.HasPrefix(rest, "\n") {
+		// A blank line after the hook keeps it alone above the fold.
+		score += 10
+	}
+	if weakOpeners.MatchString(hook) {
+		score -= 30
+	}
+	if strings.Contains(hook, ". ") {
+		// More than one sentence in the opening line dilutes it.
+		score -= 5
+	}
+
+	return max(0, min(100, score))
+}
+
+func splitSentences(content string) []string {
+	var sentences []string
+	for _, sentence := range sentenceBreak.Split(content, -1) {
+		if sentence = strings.TrimSpace(sentence); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+	return sentences
+}
+
+// wordsOf returns the lowercased words of text, leaving out hashtags,
+// mentions and links.
+func wordsOf(text string) []string {
+	var words []string
+	for _, field := range strings.Fields(text) {
+		if strings.HasPrefix(field, "#") || strings.HasPrefix(field, "@") || strings.Contains(field, "://") {
+			continue
+		}
+		word := strings.ToLower(strings.TrimFunc(field, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }))
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// countSyllables estimates the syllables of an English word by its vowel
+// groups, which is close enough for a grade level.
+func countSyllables(word string) int {
+	count := len(vowelGroup.FindAllString(word, -1))
+	if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && count > 1 {
+		count--
+	}
+	return max(count, 1)
+}
+
+func countEmoji(content string) int {
+	return len(emojiSpans(content))
+}
+
+func variance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return squares / float64(len(values))
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxTemplateName matches the size of post_templates.name.
+const MaxTemplateName = 50
+
+// PostTemplate is a proven post structure, such as a contrarian take or a
+// failure story. Skeleton lays out, part by part, what the post says.
+type PostTemplate struct {
+	Name        string    `json:"name" bson:"name"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	Skeleton    string    `json:"skeleton" bson:"skeleton"`
+	CreatedBy   string    `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+func NewPostTemplate(name, description, skeleton, createdBy string) *PostTemplate {
+	now := time.Now()
+	return &PostTemplate{
+		Name:        NormalizeTemplateName(name),
+		Description: strings.TrimSpace(description),
+		Skeleton:    strings.TrimSpace(skeleton),
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// NormalizeTemplateName lowercases name and joins its words with dashes,
+// so "Failure Story" and "failure_story" both become failure-story.
+func NormalizeTemplateName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
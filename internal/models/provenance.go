@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	ProvenanceLinear = "linear"
+	ProvenanceGitHub = "github"
+	ProvenanceLink   = "link"
+)
+
+// maxProvenance caps how many sources are kept for one post.
+const maxProvenance = 20
+
+var (
+	// linearIssueURL matches links to Linear issues, such as
+	// https://linear.app/acme/issue/ENG-123/offline-mode.
+	linearIssueURL = regexp.MustCompile(`https://linear\.app/[\w-]+/issue/([A-Z][A-Z0-9]*-\d+)[^\s|>)\]]*`)
+	// gitHubURL matches links to GitHub pull requests, issues and commits.
+	gitHubURL = regexp.MustCompile(`https://github\.com/([\w.-]+/[\w.-]+)/(pull|issues|commit)/([0-9a-f]{7,40}|\d+)\b[^\s|>)\]]*`)
+)
+
+// Provenance is one source a post's claims can be checked against: the
+// Linear issue, GitHub pull request, issue or commit, or other page one of
+// the thoughts it was written from came from.
+type Provenance struct {
+	Kind string `json:"kind"`
+	// Ref names the source briefly, such as ENG-123, acme/app#45 or the
+	// host of a page.
+	Ref       string `json:"ref"`
+	Title     string `json:"title,omitempty"`
+	URL       string `json:"url,omitempty"`
+	ThoughtID string `json:"thought_id,omitempty"`
+}
+
+// Label is how a source is listed next to a draft: its ref, and title if
+// it has one.
+func (p Provenance) Label() string {
+	if p.Title == "" {
+		return p.Ref
+	}
+	return p.Ref + " " + p.Title
+}
+
+// ProvenanceOf lists where thoughts came from: the Linear issue a thought
+// was synced from, the page summarized into it, and the Linear issues and
+// GitHub pull requests, issues and commits linked in its text. Each source
+// is listed once, under the first thought that has it. A Linear thought is
+// only listed by its issue's link: its external ID is Linear's internal
+// one, or names a cycle or milestone, which means nothing to a reviewer.
+func ProvenanceOf(thoughts []*Thought) []Provenance {
+	var provenance []Provenance
+	seen := map[string]bool{}
+	add := func(p Provenance) {
+		// Issues and pull requests are linked under several URLs.
+		key := p.Kind + ":" + p.Ref
+		if p.Kind == ProvenanceLink {
+			key = p.URL
+		}
+		if p.Ref == "" || seen[key] || len(provenance) == maxProvenance {
+			return
+		}
+		seen[key] = true
+		provenance = append(provenance, p)
+	}
+
+	for _, thought := range thoughts {
+		if thought.SourceURL != "" {
+			p := sourceProvenance(thought.SourceURL)
+			p.ThoughtID = thought.ID
+			if thought.SourceTitle != "" {
+				p.Title = thought.SourceTitle
+			}
+			add(p)
+		}
+		for _, link := range linearIssueURL.FindAllString(thought.Content, -1) {
+			p := sourceProvenance(link)
+			p.ThoughtID = thought.ID
+			add(p)
+		}
+		for _, link := range gitHubURL.FindAllString(thought.Content, -1) {
+			p := sourceProvenance(link)
+			p.ThoughtID = thought.ID
+			add(p)
+		}
+	}
+
+	return provenance
+}
+
+// sourceProvenance names the page at link.
+func sourceProvenance(link string) Provenance {
+	if match := linearIssueURL.FindStringSubmatch(link); match != nil && match[0] == link {
+		return Provenance{Kind: ProvenanceLinear, Ref: match[1], URL: link}
+	}
+
+	if match := gitHubURL.FindStringSubmatch(link); match != nil && match[0] == link {
+		repo, kind, id := match[1], match[2], match[3]
+		ref := fmt.Sprintf("%s#%s", repo, id)
+		if kind == "commit" {
+			ref = fmt.Sprintf("%s@%.7s", repo, id)
+		}
+		return Provenance{Kind: ProvenanceGitHub, Ref: ref, URL: link}
+	}
+
+	ref := link
+	if parsed, err := url.Parse(link); err == nil && parsed.Host != "" {
+		ref = strings.TrimPrefix(parsed.Host, "www.")
+	}
+	return Provenance{Kind: ProvenanceLink, Ref: ref, URL: link}
+}
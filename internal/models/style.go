@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type StyleProfile struct {
+	ID                     string            `json:"id" bson:"_id"`
+	UserID                 string            `json:"user_id" bson:"user_id"`
+	StylePatterns          map[string]string `json:"style_patterns" bson:"style_patterns"`
+	TonePreferences        []string          `json:"tone_preferences" bson:"tone_preferences"`
+	HighPerformingElements []string          `json:"high_performing_elements" bson:"high_performing_elements"`
+	SamplePosts            []string          `json:"sample_posts" bson:"sample_posts"`
+	LastUpdated            time.Time         `json:"last_updated" bson:"last_updated"`
+}
+
+func NewStyleProfile(userID string) *StyleProfile {
+	return &StyleProfile{
+		UserID:                 userID,
+		StylePatterns:          map[string]string{},
+		TonePreferences:        []string{},
+		HighPerformingElements: []string{},
+		SamplePosts:            []string{},
+		LastUpdated:            time.Now(),
+	}
+}
@@ -11,6 +11,13 @@ type Thought struct {
 	Status          string    `json:"status" bson:"status"`
 	Timestamp       time.Time `json:"timestamp" bson:"timestamp"`
 	RelatedThoughts []string  `json:"related_thoughts" bson:"related_thoughts"`
+	SlackChannelID  string    `json:"slack_channel_id,omitempty" bson:"slack_channel_id,omitempty"`
+	SlackThreadTS   string    `json:"slack_thread_ts,omitempty" bson:"slack_thread_ts,omitempty"`
+	ExternalID      string    `json:"external_id,omitempty" bson:"external_id,omitempty"`
+	UsedByPostID    *string   `json:"used_by_post_id,omitempty" bson:"used_by_post_id,omitempty"`
+	SourceURL       string    `json:"source_url,omitempty" bson:"source_url,omitempty"`
+	SourceTitle     string    `json:"source_title,omitempty" bson:"source_title,omitempty"`
+	Embedding       []float32 `json:"-" bson:"-"`
 }
 
 func NewThought(content, source string) *Thought {
@@ -22,4 +29,4 @@ func NewThought(content, source string) *Thought {
 		TopicTags:       []string{},
 		RelatedThoughts: []string{},
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Access roles of users, from most to least trusted.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// User grants a Slack user a role. AddedBy is the owner who granted it, and
+// is empty for owners set in the config.
+type User struct {
+	UserID    string    `json:"user_id" bson:"_id"`
+	Role      string    `json:"role" bson:"role"`
+	AddedBy   string    `json:"added_by,omitempty" bson:"added_by,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+func NewUser(userID, role, addedBy string) *User {
+	return &User{
+		UserID:    userID,
+		Role:      role,
+		AddedBy:   addedBy,
+		CreatedAt: time.Now(),
+	}
+}
+
+// RoleAllows reports whether role may do what needs the role need. Unknown
+// roles count as viewers.
+func RoleAllows(role, need string) bool {
+	return roleRank(role) >= roleRank(need)
+}
+
+func ValidRole(role string) bool {
+	return role == RoleOwner || role == RoleEditor || role == RoleViewer
+}
+
+func roleRank(role string) int {
+	switch role {
+	case RoleOwner:
+		return 2
+	case RoleEditor:
+		return 1
+	}
+	return 0
+}
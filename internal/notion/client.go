@@ -0,0 +1,270 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const notionVersion = "2022-06-28"
+
+// maxRichText is Notion's limit on the content of one rich text object.
+const maxRichText = 2000
+
+type Client struct {
+	token      string
+	httpClient *http.Client
+	baseURL    string
+}
+
+// Database is the schema of a Notion database: property name to type.
+type Database struct {
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+}
+
+type Page struct {
+	ID             string                     `json:"id"`
+	CreatedTime    time.Time                  `json:"created_time"`
+	LastEditedTime time.Time                  `json:"last_edited_time"`
+	Properties     map[string]json.RawMessage `json:"properties"`
+}
+
+type queryResponse struct {
+	Results    []Page `json:"results"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor"`
+}
+
+type richText struct {
+	PlainText string `json:"plain_text"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewClient(token string) *Client {
+	if token == "" {
+		slog.Error("NOTION_TOKEN is required")
+		os.Exit(1)
+	}
+
+	return &Client{
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		baseURL:    "https://api.notion.com/v1",
+	}
+}
+
+func (c *Client) GetDatabase(ctx context.Context, databaseID string) (*Database, error) {
+	var database Database
+	if err := c.do(ctx, "GET", "/databases/"+databaseID, nil, &database); err != nil {
+		return nil, err
+	}
+	return &database, nil
+}
+
+// QueryDatabase returns every page matching filter, following pagination.
+func (c *Client) QueryDatabase(ctx context.Context, databaseID string, filter any) ([]Page, error) {
+	var pages []Page
+	cursor := ""
+
+	for {
+		body := map[string]any{"page_size": 100}
+		if filter != nil {
+			body["filter"] = filter
+		}
+		if cursor != "" {
+			body["start_cursor"] = cursor
+		}
+
+		var resp queryResponse
+		if err := c.do(ctx, "POST", "/databases/"+databaseID+"/query", body, &resp); err != nil {
+			return nil, err
+		}
+
+		pages = append(pages, resp.Results...)
+		if !resp.HasMore || resp.NextCursor == "" {
+			return pages, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+// GetPageText returns the plain text of a page's top-level blocks, one
+// block per line.
+func (c *Client) GetPageText(ctx context.Context, pageID string) (string, error) {
+	var resp struct {
+		Results []map[string]json.RawMessage `json:"results"`
+	}
+	if err := c.do(ctx, "GET", "/blocks/"+pageID+"/children?page_size=100", nil, &resp); err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, raw := range resp.Results {
+		var blockType string
+		if err := json.Unmarshal(raw["type"], &blockType); err != nil {
+			continue
+		}
+
+		var content struct {
+			RichText []richText `json:"rich_text"`
+		}
+		if err := json.Unmarshal(raw[blockType], &content); err != nil {
+			continue
+		}
+
+		if text := joinRichText(content.RichText); text != "" {
+			lines = append(lines, text)
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// CreatePage adds a page to databaseID with the given properties and a
+// body of paragraphs.
+func (c *Client) CreatePage(ctx context.Context, databaseID string, properties map[string]any, paragraphs []string) (string, error) {
+	body := map[string]any{
+		"parent":     map[string]string{"database_id": databaseID},
+		"properties": properties,
+		"children":   paragraphBlocks(paragraphs),
+	}
+
+	var page Page
+	if err := c.do(ctx, "POST", "/pages", body, &page); err != nil {
+		return "", err
+	}
+
+	return page.ID, nil
+}
+
+func (c *Client) UpdatePage(ctx context.Context, pageID string, properties map[string]any) error {
+	return c.do(ctx, "PATCH", "/pages/"+pageID, map[string]any{"properties": properties}, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, payload, result any) error {
+	var reqBody io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Notion-Version", notionVersion)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Notion API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
+			return fmt.Errorf("Notion API error (status %d, %s): %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("Notion API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+func joinRichText(parts []richText) string {
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteString(part.PlainText)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// textValue builds a rich text array, splitting text that is longer than
+// one rich text object allows.
+func textValue(text string) []map[string]any {
+	var parts []map[string]any
+	runes := []rune(text)
+	for len(runes) > 0 {
+		n := min(len(runes), maxRichText)
+		parts = append(parts, map[string]any{
+			"type": "text",
+			"text": map[string]string{"content": string(runes[:n])},
+		})
+		runes = runes[n:]
+	}
+	return parts
+}
+
+func paragraphBlocks(paragraphs []string) []map[string]any {
+	blocks := []map[string]any{}
+	for _, paragraph := range paragraphs {
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		blocks = append(blocks, map[string]any{
+			"object":    "block",
+			"type":      "paragraph",
+			"paragraph": map[string]any{"rich_text": textValue(paragraph)},
+		})
+	}
+	return blocks
+}
+
+// maxChildren is Notion's limit on the blocks appended in one request.
+const maxChildren = 100
+
+// AppendBullets adds items to the end of a page as bulleted list items.
+func (c *Client) AppendBullets(ctx context.Context, pageID string, items []string) error {
+	for len(items) > 0 {
+		n := min(len(items), maxChildren)
+		blocks := make([]map[string]any, 0, n)
+		for _, item := range items[:n] {
+			blocks = append(blocks, map[string]any{
+				"object":             "block",
+				"type":               "bulleted_list_item",
+				"bulleted_list_item": map[string]any{"rich_text": textValue(item)},
+			})
+		}
+		if err := c.do(ctx, "PATCH", "/blocks/"+pageID+"/children", map[string]any{"children": blocks}, nil); err != nil {
+			return err
+		}
+		items = items[n:]
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DailyNotes appends to the daily notes kept in a Notion database, one page
+// per day. A day's page is the one whose Date property, or title when the
+// database has no Date property, is that day; it is created when missing.
+type DailyNotes struct {
+	client     *Client
+	databaseID string
+
+	// The page of the last day appended to, so a run doesn't look it up
+	// for every batch.
+	day    string
+	pageID string
+}
+
+func NewDailyNotes(client *Client, databaseID string) *DailyNotes {
+	return &DailyNotes{
+		client:     client,
+		databaseID: databaseID,
+	}
+}
+
+func (d *DailyNotes) Name() string {
+	return "notion"
+}
+
+// Append adds entries, which are Markdown list items, to the day's page as
+// bulleted list items.
+func (d *DailyNotes) Append(ctx context.Context, day time.Time, entries []string) error {
+	date := day.Format("2006-01-02")
+	if d.day != date {
+		pageID, err := d.page(ctx, date)
+		if err != nil {
+			return err
+		}
+		d.day, d.pageID = date, pageID
+	}
+
+	items := make([]string, len(entries))
+	for i, entry := range entries {
+		items[i] = strings.TrimPrefix(entry, "- ")
+	}
+
+	if err := d.client.AppendBullets(ctx, d.pageID, items); err != nil {
+		// The page may have been deleted; look it up again next time.
+		d.day, d.pageID = "", ""
+		return fmt.Errorf("failed to append to notion daily note: %w", err)
+	}
+	return nil
+}
+
+// page returns the ID of the page for date, creating it when missing.
+func (d *DailyNotes) page(ctx context.Context, date string) (string, error) {
+	database, err := d.client.GetDatabase(ctx, d.databaseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load notion daily notes database: %w", err)
+	}
+
+	var title string
+	for name, property := range database.Properties {
+		if property.Type == "title" {
+			title = name
+		}
+	}
+	hasDate := database.Properties[dateProperty].Type == "date"
+
+	filter := map[string]any{"property": title, "title": map[string]string{"equals": date}}
+	if hasDate {
+		filter = map[string]any{"property": dateProperty, "date": map[string]string{"equals": date}}
+	}
+
+	pages, err := d.client.QueryDatabase(ctx, d.databaseID, filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to find notion daily note: %w", err)
+	}
+	if len(pages) > 0 {
+		return pages[0].ID, nil
+	}
+
+	properties := map[string]any{
+		title: map[string]any{"title": textValue(date)},
+	}
+	if hasDate {
+		properties[dateProperty] = map[string]any{"date": map[string]string{"start": date}}
+	}
+
+	pageID, err := d.client.CreatePage(ctx, d.databaseID, properties, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create notion daily note: %w", err)
+	}
+	return pageID, nil
+}
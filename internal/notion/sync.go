@@ -0,0 +1,374 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const thoughtSource = "notion"
+
+// Properties the syncer reads and writes. The database needs a title
+// property and a Status select or status property; the others are filled
+// in only when the database has them with the expected type.
+const (
+	statusProperty = "Status"
+	dateProperty   = "Date"
+	urlProperty    = "LinkedIn URL"
+	typeProperty   = "Type"
+)
+
+// postStatuses maps the post statuses that are written back to the Status
+// option used for them in Notion.
+var postStatuses = map[string]string{
+	"approved":  "Approved",
+	"scheduled": "Scheduled",
+	"published": "Published",
+}
+
+// publishedWindow limits which published posts are written back, so the
+// first sync doesn't copy the whole archive.
+const publishedWindow = 30 * 24 * time.Hour
+
+const maxTitleChars = 80
+
+type Syncer struct {
+	client      *Client
+	databaseID  string
+	ideaStatus  string
+	thoughtRepo *database.ThoughtRepository
+	postRepo    *database.PostRepository
+	pageRepo    *database.NotionPageRepository
+	categorizer *agents.CategorizerAgent
+	embeddings  *agents.EmbeddingAgent
+	interval    time.Duration
+
+	mu     sync.Mutex
+	schema *schema
+}
+
+type schema struct {
+	title      string
+	statusType string
+	types      map[string]string
+}
+
+type SyncResult struct {
+	Fetched  int
+	Created  int
+	Skipped  int
+	Failed   int
+	Exported int
+}
+
+func NewSyncer(client *Client, databaseID, ideaStatus string, thoughtRepo *database.ThoughtRepository, postRepo *database.PostRepository, pageRepo *database.NotionPageRepository, categorizer *agents.CategorizerAgent, embeddings *agents.EmbeddingAgent, interval time.Duration) *Syncer {
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	return &Syncer{
+		client:      client,
+		databaseID:  databaseID,
+		ideaStatus:  ideaStatus,
+		thoughtRepo: thoughtRepo,
+		postRepo:    postRepo,
+		pageRepo:    pageRepo,
+		categorizer: categorizer,
+		embeddings:  embeddings,
+		interval:    interval,
+	}
+}
+
+func (s *Syncer) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "notion sync started", "interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.Sync(ctx, "")
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "notion sync failed", "error", err)
+		} else if result != nil && (result.Created > 0 || result.Exported > 0) {
+			slog.InfoContext(ctx, "notion sync completed", "created", result.Created, "exported", result.Exported, "failed", result.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "notion sync stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync imports new ideas into the workspace of channelID, or the shared
+// pool when it is empty, and writes approved, scheduled and recently
+// published posts back to the database.
+func (s *Syncer) Sync(ctx context.Context, channelID string) (*SyncResult, error) {
+	// The background loop and the Slack command must not export the same
+	// post twice.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schema, err := s.loadSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.importIdeas(ctx, schema, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	exported, err := s.exportPosts(ctx, schema)
+	result.Exported = exported
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (s *Syncer) loadSchema(ctx context.Context) (*schema, error) {
+	if s.schema != nil {
+		return s.schema, nil
+	}
+
+	database, err := s.client.GetDatabase(ctx, s.databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notion database: %w", err)
+	}
+
+	loaded := &schema{types: map[string]string{}}
+	for name, property := range database.Properties {
+		loaded.types[name] = property.Type
+		if property.Type == "title" {
+			loaded.title = name
+		}
+	}
+
+	loaded.statusType = loaded.types[statusProperty]
+	if loaded.statusType != "select" && loaded.statusType != "status" {
+		return nil, fmt.Errorf("notion database needs a %q property of type select or status", statusProperty)
+	}
+
+	s.schema = loaded
+	return loaded, nil
+}
+
+func (s *Syncer) importIdeas(ctx context.Context, schema *schema, channelID string) (*SyncResult, error) {
+	filter := map[string]any{
+		"property":        statusProperty,
+		schema.statusType: map[string]string{"equals": s.ideaStatus},
+	}
+
+	pages, err := s.client.QueryDatabase(ctx, s.databaseID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notion ideas: %w", err)
+	}
+
+	result := &SyncResult{Fetched: len(pages)}
+
+	for _, page := range pages {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		created, err := s.ingestPage(ctx, schema, channelID, page)
+		switch {
+		case err != nil:
+			slog.ErrorContext(ctx, "failed to ingest notion page", "page_id", page.ID, "error", err)
+			result.Failed++
+		case created:
+			result.Created++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result, nil
+}
+
+// ingestPage creates a categorized thought from an idea row. It returns
+// false without an error when the page was already ingested.
+func (s *Syncer) ingestPage(ctx context.Context, schema *schema, channelID string, page Page) (bool, error) {
+	exists, err := s.thoughtRepo.ExistsByExternalID(ctx, thoughtSource, page.ID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	title := propertyText(page.Properties[schema.title])
+
+	body, err := s.client.GetPageText(ctx, page.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	content := strings.TrimSpace(title + "\n\n" + body)
+	if content == "" {
+		return false, nil
+	}
+
+	thought := models.NewThought(content, thoughtSource)
+	thought.ExternalID = page.ID
+	thought.SlackChannelID = channelID
+
+	if err := s.categorizer.CategorizeThought(ctx, thought); err != nil {
+		slog.ErrorContext(ctx, "failed to categorize thought", "error", err)
+		thought.Category = "uncategorized"
+		thought.TopicTags = []string{"general"}
+	}
+
+	if s.embeddings != nil {
+		if _, err := s.embeddings.Match(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "failed to match thought embeddings", "error", err)
+		}
+	}
+
+	if err := s.thoughtRepo.Create(ctx, thought); err != nil {
+		return false, fmt.Errorf("failed to save thought: %w", err)
+	}
+
+	if s.embeddings != nil {
+		if err := s.embeddings.Link(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "failed to store thought embedding", "error", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "created thought from notion page", "thought_id", thought.ID, "page_id", page.ID)
+
+	return true, nil
+}
+
+func (s *Syncer) exportPosts(ctx context.Context, schema *schema) (int, error) {
+	var posts []*models.Post
+	for _, status := range []string{"approved", "scheduled"} {
+		byStatus, err := s.postRepo.GetByStatus(ctx, status)
+		if err != nil {
+			return 0, err
+		}
+		posts = append(posts, byStatus...)
+	}
+
+	published, err := s.postRepo.GetPublishedSince(ctx, time.Now().Add(-publishedWindow))
+	if err != nil {
+		return 0, err
+	}
+	posts = append(posts, published...)
+
+	pages, err := s.pageRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	for _, post := range posts {
+		if ctx.Err() != nil {
+			return exported, ctx.Err()
+		}
+
+		state := postState(post)
+		page := pages[post.ID]
+		if page != nil && page.State == state {
+			continue
+		}
+
+		if err := s.exportPost(ctx, schema, post, page, state); err != nil {
+			slog.ErrorContext(ctx, "failed to write post to notion", "post_id", post.ID, "error", err)
+			continue
+		}
+		exported++
+	}
+
+	return exported, nil
+}
+
+// exportPost creates the page for a post or updates the page's properties.
+// The title and body are only written on creation so edits made in Notion
+// are kept.
+func (s *Syncer) exportPost(ctx context.Context, schema *schema, post *models.Post, page *database.NotionPage, state string) error {
+	properties := s.postProperties(schema, post)
+
+	if page == nil {
+		properties[schema.title] = map[string]any{"title": textValue(postTitle(post.Content))}
+		if schema.types[typeProperty] == "select" && post.PostType != "" {
+			properties[typeProperty] = map[string]any{"select": map[string]string{"name": post.PostType}}
+		}
+
+		pageID, err := s.client.CreatePage(ctx, s.databaseID, properties, strings.Split(post.Content, "\n\n"))
+		if err != nil {
+			return err
+		}
+		page = &database.NotionPage{PostID: post.ID, PageID: pageID}
+	} else if err := s.client.UpdatePage(ctx, page.PageID, properties); err != nil {
+		return err
+	}
+
+	page.State = state
+	return s.pageRepo.Save(ctx, page)
+}
+
+func (s *Syncer) postProperties(schema *schema, post *models.Post) map[string]any {
+	properties := map[string]any{
+		statusProperty: map[string]any{schema.statusType: map[string]string{"name": postStatuses[post.Status]}},
+	}
+
+	date := post.ScheduledAt
+	if post.PublishedAt != nil {
+		date = post.PublishedAt
+	}
+	if schema.types[dateProperty] == "date" && date != nil {
+		properties[dateProperty] = map[string]any{"date": map[string]string{"start": date.Format(time.RFC3339)}}
+	}
+
+	if schema.types[urlProperty] == "url" && post.LinkedInURL != "" {
+		properties[urlProperty] = map[string]any{"url": post.LinkedInURL}
+	}
+
+	return properties
+}
+
+// postState captures everything written to a page's properties, so a page
+// is only updated when one of them changed.
+func postState(post *models.Post) string {
+	state := post.Status + "|" + post.LinkedInURN
+	if post.ScheduledAt != nil {
+		state += "|" + post.ScheduledAt.UTC().Format(time.RFC3339)
+	}
+	if post.PublishedAt != nil {
+		state += "|" + post.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	return state
+}
+
+func postTitle(content string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	if runes := []rune(title); len(runes) > maxTitleChars {
+		title = string(runes[:maxTitleChars-3]) + "..."
+	}
+	return title
+}
+
+// propertyText returns the plain text of a title or rich text property.
+func propertyText(raw json.RawMessage) string {
+	var property struct {
+		Title    []richText `json:"title"`
+		RichText []richText `json:"rich_text"`
+	}
+	if err := json.Unmarshal(raw, &property); err != nil {
+		return ""
+	}
+	return joinRichText(append(property.Title, property.RichText...))
+}
@@ -0,0 +1,245 @@
+// Package outbound guards calls to third-party APIs. Each provider gets a
+// request budget, so a runaway loop can't burn through its quota, and a
+// circuit breaker that stops calling it for a while once it keeps failing.
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped with the provider name, for calls made
+// while a provider's circuit is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Guards hands out one Guard per provider, so every client talking to a
+// provider shares its budget and its breaker.
+type Guards struct {
+	budgets  map[string]int
+	failures int
+	cooldown time.Duration
+
+	mu     sync.Mutex
+	guards map[string]*Guard
+}
+
+// NewGuards sets up guards. budgets holds requests per minute by provider
+// name; providers without a positive budget are not rate limited. After
+// failures consecutive failed calls a provider's circuit opens for cooldown;
+// zero failures turns the breakers off.
+func NewGuards(budgets map[string]int, failures int, cooldown time.Duration) *Guards {
+	return &Guards{
+		budgets:  budgets,
+		failures: failures,
+		cooldown: cooldown,
+		guards:   make(map[string]*Guard),
+	}
+}
+
+// For returns the guard for a provider. A nil Guards returns a nil Guard,
+// which lets every call through.
+func (g *Guards) For(name string) *Guard {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if guard, ok := g.guards[name]; ok {
+		return guard
+	}
+
+	guard := &Guard{name: name}
+	if perMinute := g.budgets[name]; perMinute > 0 {
+		guard.limiter = newLimiter(perMinute)
+	}
+	if g.failures > 0 {
+		guard.breaker = &breaker{threshold: g.failures, cooldown: g.cooldown}
+	}
+	g.guards[name] = guard
+
+	return guard
+}
+
+// Guard rate limits and circuit-breaks the calls to one provider.
+type Guard struct {
+	name    string
+	limiter *limiter
+	breaker *breaker
+}
+
+// Transport wraps next so requests wait for the provider's budget and fail
+// fast while its circuit is open.
+func (g *Guard) Transport(next http.RoundTripper) http.RoundTripper {
+	if g == nil {
+		return next
+	}
+	return &transport{guard: g, next: next}
+}
+
+type transport struct {
+	guard *Guard
+	next  http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	g := t.guard
+
+	if g.breaker != nil && !g.breaker.allow() {
+		closeBody(req)
+		return nil, fmt.Errorf("%s: %w", g.name, ErrCircuitOpen)
+	}
+
+	if g.limiter != nil {
+		if err := g.limiter.wait(req.Context(), g.name); err != nil {
+			if g.breaker != nil {
+				g.breaker.release()
+			}
+			closeBody(req)
+			return nil, err
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+
+	if g.breaker != nil {
+		switch {
+		case err != nil && req.Context().Err() != nil:
+			// The caller gave up; that says nothing about the provider.
+			g.breaker.release()
+		case err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+			g.breaker.failure(req.Context(), g.name)
+		default:
+			g.breaker.success(req.Context(), g.name)
+		}
+	}
+
+	return resp, err
+}
+
+// closeBody honours the RoundTripper contract of closing the request body
+// even when the request is never sent.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// limiter is a token bucket holding up to a minute's budget, refilled
+// evenly over the minute.
+type limiter struct {
+	mu       sync.Mutex
+	capacity float64
+	perSec   float64
+	tokens   float64
+	last     time.Time
+}
+
+func newLimiter(perMinute int) *limiter {
+	return &limiter{
+		capacity: float64(perMinute),
+		perSec:   float64(perMinute) / 60,
+		tokens:   float64(perMinute),
+		last:     time.Now(),
+	}
+}
+
+// wait blocks until a request fits the budget or ctx is done.
+func (l *limiter) wait(ctx context.Context, name string) error {
+	logged := false
+
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = min(l.capacity, l.tokens+now.Sub(l.last).Seconds()*l.perSec)
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		delay := time.Duration((1 - l.tokens) / l.perSec * float64(time.Second))
+		l.mu.Unlock()
+
+		if !logged {
+			slog.WarnContext(ctx, "outbound rate limit reached, waiting", "provider", name, "delay_ms", delay.Milliseconds())
+			logged = true
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: waiting for rate limit: %w", name, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// breaker opens after threshold consecutive failures. Once cooldown has
+// passed it lets a single probe through: success closes it again, failure
+// keeps it open for another cooldown.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+// release gives up a probe slot without judging the provider.
+func (b *breaker) release() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *breaker) success(ctx context.Context, name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures >= b.threshold {
+		slog.InfoContext(ctx, "outbound circuit closed", "provider", name)
+	}
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *breaker) failure(ctx context.Context, name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		if b.failures == b.threshold {
+			slog.WarnContext(ctx, "outbound circuit opened", "provider", name, "failures", b.failures, "cooldown", b.cooldown)
+		}
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,240 @@
+// Package prompts holds the templates the agents build their LLM prompts
+// from. Each prompt has a built-in template, which a file in the prompts
+// directory or an override saved from Slack can replace.
+package prompts
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+)
+
+// Prompt names.
+const (
+	Generate        = "generate"
+	Brainstorm      = "brainstorm"
+	Categorize      = "categorize"
+	CategorizeBatch = "categorize-batch"
+)
+
+//go:embed templates/*.tmpl
+var builtinFiles embed.FS
+
+// GenerateData fills the generate prompt.
+type GenerateData struct {
+	// Input is the thoughts, or the brainstorm angle, to write from.
+	Input string
+	// Style is the author's learned writing style, if any.
+	Style string
+	// Examples lists the author's best performing posts, if any.
+	Examples string
+	// Count is how many variations to write, from 1 to 5.
+	Count int
+	// Angles lists the angle each variation takes, or the angles to pick
+	// from when Count is 1.
+	Angles string
+	// Emoji says how many emojis the post may use.
+	Emoji string
+	// Length is the range of words the post should have, such as
+	// "150-300 words".
+	Length string
+}
+
+// ThoughtData fills the brainstorm prompt.
+type ThoughtData struct {
+	Thought string
+}
+
+// CategorizeData fills the categorize prompt.
+type CategorizeData struct {
+	Thought string
+	// Categories lists the categories to choose from, comma separated.
+	Categories string
+}
+
+// CategorizeBatchData fills the categorize-batch prompt.
+type CategorizeBatchData struct {
+	// Thoughts lists the thoughts to categorize, numbered from 1.
+	Thoughts string
+	// Categories lists the categories to choose from, comma separated.
+	Categories string
+}
+
+type definition struct {
+	// sample checks that a template only uses fields the prompt is given.
+	sample    any
+	variables string
+}
+
+var definitions = map[string]definition{
+	Generate: {
+		sample:    GenerateData{Input: "input", Style: "style", Examples: "examples", Count: 3, Angles: "angles", Emoji: "emoji", Length: "length"},
+		variables: "`{{.Input}}` (thoughts or angle to write from), `{{.Style}}` (learned writing style, may be empty), `{{.Examples}}` (best performing posts, may be empty), `{{.Count}}` (number of variations), `{{.Angles}}` (angle of each variation), `{{.Emoji}}` (how many emojis to use), `{{.Length}}` (range of words, such as 150-300 words)",
+	},
+	Brainstorm: {
+		sample:    ThoughtData{Thought: "thought"},
+		variables: "`{{.Thought}}`",
+	},
+	Categorize: {
+		sample:    CategorizeData{Thought: "thought", Categories: "categories"},
+		variables: "`{{.Thought}}`, `{{.Categories}}` (the categories to choose from)",
+	},
+	CategorizeBatch: {
+		sample:    CategorizeBatchData{Thoughts: "thoughts", Categories: "categories"},
+		variables: "`{{.Thoughts}}` (numbered thoughts), `{{.Categories}}` (the categories to choose from)",
+	},
+}
+
+// Names lists the prompts in the order they are shown.
+func Names() []string {
+	return []string{Generate, Brainstorm, Categorize, CategorizeBatch}
+}
+
+// Variables describes the fields a prompt's template can use.
+func Variables(name string) string {
+	return definitions[name].variables
+}
+
+// Store renders prompts. Overrides are read on every render, so a change
+// made from Slack applies to every replica at once.
+type Store struct {
+	repo     *database.PromptRepository
+	defaults map[string]string
+}
+
+// NewStore loads the built-in templates, replacing any that have a
+// <name>.tmpl file in dir. An empty dir uses only the built-in ones, and a
+// nil repo disables overrides.
+func NewStore(repo *database.PromptRepository, dir string) (*Store, error) {
+	s := &Store{repo: repo, defaults: make(map[string]string)}
+
+	for _, name := range Names() {
+		text, err := builtinFiles.ReadFile("templates/" + name + ".tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read built-in %s prompt: %w", name, err)
+		}
+
+		if dir != "" {
+			custom, err := os.ReadFile(filepath.Join(dir, name+".tmpl"))
+			switch {
+			case err == nil:
+				if err := validate(name, string(custom)); err != nil {
+					return nil, fmt.Errorf("invalid %s prompt in %s: %w", name, dir, err)
+				}
+				slog.Info("Using prompt from file", "prompt", name, "dir", dir)
+				text = custom
+			case !os.IsNotExist(err):
+				return nil, fmt.Errorf("failed to read %s prompt: %w", name, err)
+			}
+		}
+
+		s.defaults[name] = string(text)
+	}
+
+	return s, nil
+}
+
+// Render fills the named prompt with data. An override that fails to
+// render is logged and the default used, so a bad edit can't stop
+// generation. A nil Store renders the built-in templates.
+func (s *Store) Render(ctx context.Context, name string, data any) (string, error) {
+	text, overridden, err := s.Get(ctx, name)
+	if err != nil {
+		if text == "" {
+			return "", err
+		}
+		// Get falls back to the default when the override can't be read.
+		slog.WarnContext(ctx, "Failed to load prompt override, using default", "prompt", name, "error", err)
+	}
+
+	prompt, err := render(name, text, data)
+	if err != nil && overridden {
+		slog.WarnContext(ctx, "Prompt override failed to render, using default", "prompt", name, "error", err)
+		prompt, err = render(name, s.defaults[name], data)
+	}
+
+	return prompt, err
+}
+
+// Get returns the template a prompt currently uses and whether it is an
+// override.
+func (s *Store) Get(ctx context.Context, name string) (string, bool, error) {
+	if s == nil {
+		text, err := builtinFiles.ReadFile("templates/" + name + ".tmpl")
+		if err != nil {
+			return "", false, fmt.Errorf("unknown prompt %q", name)
+		}
+		return string(text), false, nil
+	}
+
+	text, ok := s.defaults[name]
+	if !ok {
+		return "", false, fmt.Errorf("unknown prompt %q", name)
+	}
+	if s.repo == nil {
+		return text, false, nil
+	}
+
+	override, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return text, false, err
+	}
+	if override != "" {
+		return override, true, nil
+	}
+
+	return text, false, nil
+}
+
+// Set saves text as the override for a prompt once it parses and renders.
+func (s *Store) Set(ctx context.Context, name, text, userID string) error {
+	if _, ok := s.defaults[name]; !ok {
+		return fmt.Errorf("unknown prompt %q", name)
+	}
+
+	if err := validate(name, text); err != nil {
+		return err
+	}
+
+	return s.repo.Set(ctx, name, text, userID)
+}
+
+// Reset drops a prompt's override.
+func (s *Store) Reset(ctx context.Context, name string) error {
+	if _, ok := s.defaults[name]; !ok {
+		return fmt.Errorf("unknown prompt %q", name)
+	}
+
+	return s.repo.Delete(ctx, name)
+}
+
+func validate(name, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("prompt is empty")
+	}
+
+	_, err := render(name, text, definitions[name].sample)
+	return err
+}
+
+func render(name, text string, data any) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s prompt: %w", name, err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s prompt: %w", name, err)
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,336 @@
+// Package queue runs long work, such as generating drafts, imports and
+// publishing, from the jobs table rather than inline, so it survives a
+// restart and any replica of the bot can take it. A worker leases a job
+// while it runs it and keeps renewing the lease; when a worker dies its
+// lease runs out and another worker picks the job up again.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/logging"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// pollInterval is how often an idle worker looks for jobs queued by
+	// other replicas. Jobs queued by this one wake a worker straight away.
+	pollInterval = 2 * time.Second
+	// lease is how long a job stays with its worker without being renewed.
+	lease = time.Minute
+	// defaultMaxAttempts applies to kinds this replica doesn't run.
+	defaultMaxAttempts = 3
+	// retryBackoff is the wait before a failed job's first retry, doubled
+	// before each one after, up to maxBackoff.
+	retryBackoff = 30 * time.Second
+	maxBackoff   = 30 * time.Minute
+	// retention is how long finished jobs are kept.
+	retention     = 7 * 24 * time.Hour
+	pruneInterval = time.Hour
+)
+
+var tracer = otel.Tracer("github.com/shubh-37/linkedin-ghostwriter/internal/queue")
+
+// Handler runs a job. An error is retried until the job runs out of
+// attempts, unless it is Permanent. A handler stopped by ctx is put back in
+// the queue to run again.
+type Handler func(ctx context.Context, job *models.Job) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error that retrying won't fix.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+type kind struct {
+	handle      Handler
+	workers     int
+	maxAttempts int
+	wake        chan struct{}
+}
+
+type Queue struct {
+	repo   *database.JobRepository
+	worker string
+
+	mu    sync.RWMutex
+	kinds map[string]*kind
+}
+
+// NewQueue names this replica's workers after the host and process, so the
+// jobs table shows who holds a job.
+func NewQueue(repo *database.JobRepository) *Queue {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &Queue{
+		repo:   repo,
+		worker: fmt.Sprintf("%s:%d", host, os.Getpid()),
+		kinds:  make(map[string]*kind),
+	}
+}
+
+// Register runs jobs of a kind with handle, on workers goroutines in this
+// replica, trying each at most maxAttempts times. Register every kind
+// before Start.
+func (q *Queue) Register(name string, handle Handler, workers, maxAttempts int) {
+	if workers < 1 {
+		workers = 1
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.kinds[name] = &kind{
+		handle:      handle,
+		workers:     workers,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Enqueue queues a job of a kind with payload, encoded as JSON, on behalf
+// of the actor in ctx. A non-empty key makes it report false, without
+// queueing, while a job with the same key is queued or running.
+func (q *Queue) Enqueue(ctx context.Context, name, key string, payload any) (bool, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	q.mu.RLock()
+	k := q.kinds[name]
+	q.mu.RUnlock()
+
+	maxAttempts := defaultMaxAttempts
+	if k != nil {
+		maxAttempts = k.maxAttempts
+	}
+
+	job := models.NewJob(name, key, data, maxAttempts)
+	job.Actor = database.ActorFrom(ctx)
+
+	queued, err := q.repo.Enqueue(ctx, job)
+	if err != nil || !queued {
+		return queued, err
+	}
+
+	slog.DebugContext(ctx, "job queued", "job_id", job.ID, "kind", name, "key", key)
+
+	if k != nil {
+		select {
+		case k.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return true, nil
+}
+
+// Decode reads a job's payload into v. A payload that can't be read fails
+// the job for good.
+func Decode(job *models.Job, v any) error {
+	if err := json.Unmarshal(job.Payload, v); err != nil {
+		return Permanent(fmt.Errorf("invalid %s job payload: %w", job.Kind, err))
+	}
+	return nil
+}
+
+// Start runs the workers of every registered kind until ctx is cancelled,
+// then waits for them. Jobs running then are put back in the queue.
+func (q *Queue) Start(ctx context.Context) {
+	q.mu.RLock()
+	var wg sync.WaitGroup
+	for name, k := range q.kinds {
+		for range k.workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				q.work(ctx, name, k)
+			}()
+		}
+	}
+	slog.InfoContext(ctx, "job queue started", "worker", q.worker, "kinds", len(q.kinds))
+	q.mu.RUnlock()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			slog.InfoContext(ctx, "job queue stopped")
+			return
+		case <-ticker.C:
+			if count, err := q.repo.Prune(ctx, time.Now().Add(-retention)); err != nil {
+				slog.ErrorContext(ctx, "failed to prune jobs", "error", err)
+			} else if count > 0 {
+				slog.InfoContext(ctx, "pruned finished jobs", "count", count)
+			}
+		}
+	}
+}
+
+func (q *Queue) work(ctx context.Context, name string, k *kind) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := q.repo.Claim(ctx, name, q.worker, lease)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to claim job", "kind", name, "error", err)
+		}
+		if job != nil {
+			q.run(ctx, k, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-k.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Queue) run(ctx context.Context, k *kind, job *models.Job) {
+	ctx = logging.WithRequestID(ctx, job.ID)
+	if job.Actor != "" {
+		ctx = database.WithActor(ctx, job.Actor)
+	}
+	// The outcome is saved even when the bot is stopping.
+	saveCtx := context.WithoutCancel(ctx)
+
+	if job.Attempts > job.MaxAttempts {
+		q.fail(saveCtx, job, errors.New("worker stopped during the last attempt"))
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var leaseLost atomic.Bool
+	go q.renew(runCtx, job, cancel, &leaseLost)
+
+	runCtx, span := tracer.Start(runCtx, "job "+job.Kind)
+	defer span.End()
+
+	start := time.Now()
+	err := q.handle(runCtx, k, job)
+	cancel()
+
+	switch {
+	case err == nil:
+		if err := q.repo.Complete(saveCtx, job.ID, q.worker); err != nil {
+			slog.ErrorContext(ctx, "failed to complete job", "job_id", job.ID, "error", err)
+		}
+		slog.InfoContext(ctx, "job done", "job_id", job.ID, "kind", job.Kind, "attempt", job.Attempts, "duration_ms", time.Since(start).Milliseconds())
+		return
+
+	case leaseLost.Load():
+		slog.WarnContext(ctx, "job lease lost, leaving the job to its new worker", "job_id", job.ID, "kind", job.Kind)
+		return
+
+	case ctx.Err() != nil:
+		if err := q.repo.Release(saveCtx, job.ID, q.worker); err != nil {
+			slog.ErrorContext(ctx, "failed to release job", "job_id", job.ID, "error", err)
+		}
+		slog.InfoContext(ctx, "job put back in the queue", "job_id", job.ID, "kind", job.Kind)
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	var permanent *permanentError
+	if errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts {
+		q.fail(saveCtx, job, err)
+		return
+	}
+
+	runAt := time.Now().Add(backoff(job.Attempts))
+	if err := q.repo.Retry(saveCtx, job.ID, q.worker, err.Error(), runAt); err != nil {
+		slog.ErrorContext(ctx, "failed to requeue job", "job_id", job.ID, "error", err)
+	}
+	slog.WarnContext(ctx, "job failed, retrying", "job_id", job.ID, "kind", job.Kind, "attempt", job.Attempts, "run_at", runAt, "error", err)
+}
+
+// handle runs the handler, turning a panic into an error.
+func (q *Queue) handle(ctx context.Context, k *kind, job *models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return k.handle(ctx, job)
+}
+
+// renew extends the lease on job until ctx is done. When another worker
+// has taken the job meanwhile it cancels the run.
+func (q *Queue) renew(ctx context.Context, job *models.Job, cancel context.CancelFunc, lost *atomic.Bool) {
+	ticker := time.NewTicker(lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := q.repo.Extend(ctx, job.ID, q.worker, lease)
+			if errors.Is(err, database.ErrLeaseLost) {
+				lost.Store(true)
+				cancel()
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to renew job lease", "job_id", job.ID, "error", err)
+			}
+		}
+	}
+}
+
+func (q *Queue) fail(ctx context.Context, job *models.Job, err error) {
+	if saveErr := q.repo.Fail(ctx, job.ID, q.worker, err.Error()); saveErr != nil {
+		slog.ErrorContext(ctx, "failed to fail job", "job_id", job.ID, "error", saveErr)
+	}
+	slog.ErrorContext(ctx, "job failed", "job_id", job.ID, "kind", job.Kind, "attempts", job.Attempts, "error", err)
+}
+
+// backoff is the wait after a job's attempts-th failure.
+func backoff(attempts int) time.Duration {
+	wait := retryBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
@@ -0,0 +1,198 @@
+// Package recategorize re-runs the categorizer on thoughts that were filed
+// wrongly or not at all. A bulk run over every uncategorized thought goes
+// in the background, at a limited rate like imports.
+package recategorize
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// batchSize is how many thoughts are read at a time.
+	batchSize = 50
+	// progressEvery is how many thoughts pass between progress reports.
+	progressEvery = 25
+)
+
+var ErrBusy = errors.New("a recategorization is already running")
+
+// Job counts the progress of a bulk run.
+type Job struct {
+	Total   int
+	Checked int
+	Moved   int
+	Failed  int
+	Done    bool
+	// Interrupted is set when the bot stopped before the run finished.
+	Interrupted bool
+}
+
+// Reporter is told about a job when it starts, every few thoughts and when
+// it ends.
+type Reporter func(ctx context.Context, job *Job)
+
+type Recategorizer struct {
+	thoughtRepo *database.ThoughtRepository
+	categorizer *agents.CategorizerAgent
+	interval    time.Duration
+	queue       chan Reporter
+}
+
+// NewRecategorizer categorizes at most perMinute thoughts a minute in bulk
+// runs, or without a limit when perMinute is zero.
+func NewRecategorizer(thoughtRepo *database.ThoughtRepository, categorizer *agents.CategorizerAgent, perMinute int) *Recategorizer {
+	var interval time.Duration
+	if perMinute > 0 {
+		interval = time.Minute / time.Duration(perMinute)
+	}
+
+	return &Recategorizer{
+		thoughtRepo: thoughtRepo,
+		categorizer: categorizer,
+		interval:    interval,
+		queue:       make(chan Reporter, 1),
+	}
+}
+
+// Suggest asks the categorizer for a category and tags for thought without
+// changing it.
+func (r *Recategorizer) Suggest(ctx context.Context, thought *models.Thought) (string, []string, error) {
+	suggestion := *thought
+	if err := r.categorizer.CategorizeThought(ctx, &suggestion); err != nil {
+		return "", nil, err
+	}
+	return suggestion.Category, suggestion.TopicTags, nil
+}
+
+// SubmitUncategorized queues a run over every uncategorized thought. Only
+// one run is queued or running at a time. report may be nil.
+func (r *Recategorizer) SubmitUncategorized(report Reporter) error {
+	select {
+	case r.queue <- report:
+		return nil
+	default:
+		return ErrBusy
+	}
+}
+
+// Start runs queued jobs until ctx is cancelled.
+func (r *Recategorizer) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case report := <-r.queue:
+			r.run(ctx, report)
+		}
+	}
+}
+
+func (r *Recategorizer) run(ctx context.Context, report Reporter) {
+	job := &Job{}
+	notify := func() {
+		if report != nil {
+			report(context.WithoutCancel(ctx), job)
+		}
+	}
+
+	total, err := r.thoughtRepo.CountInCategory(ctx, "uncategorized")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count uncategorized thoughts", "error", err)
+		job.Done = true
+		notify()
+		return
+	}
+	job.Total = total
+
+	slog.InfoContext(ctx, "recategorization started", "thoughts", total)
+	notify()
+
+	var throttle <-chan time.Time
+	if r.interval > 0 {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	// Thoughts that move leave the category, so each batch starts after the
+	// ones that stayed. Thoughts added meanwhile are left for the next run.
+	stayed := 0
+	for job.Checked < job.Total && ctx.Err() == nil {
+		thoughts, err := r.thoughtRepo.GetByCategory(ctx, "uncategorized", batchSize, stayed)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to fetch uncategorized thoughts", "error", err)
+			break
+		}
+		if len(thoughts) == 0 {
+			break
+		}
+
+		for _, thought := range thoughts {
+			if job.Checked == job.Total {
+				break
+			}
+			if throttle != nil {
+				select {
+				case <-ctx.Done():
+				case <-throttle:
+				}
+			}
+			if ctx.Err() != nil {
+				break
+			}
+
+			moved, err := r.recategorize(ctx, thought)
+			switch {
+			case err != nil && ctx.Err() != nil:
+				// Stopped mid-thought: it is left for the next run.
+				continue
+			case err != nil:
+				slog.ErrorContext(ctx, "failed to recategorize thought", "thought_id", thought.ID, "error", err)
+				job.Failed++
+				stayed++
+			case moved:
+				job.Moved++
+			default:
+				stayed++
+			}
+			job.Checked++
+
+			if job.Checked%progressEvery == 0 && job.Checked < job.Total {
+				notify()
+			}
+		}
+	}
+
+	job.Done = true
+	job.Interrupted = ctx.Err() != nil
+	notify()
+
+	slog.InfoContext(ctx, "recategorization finished", "checked", job.Checked, "moved", job.Moved, "failed", job.Failed, "interrupted", job.Interrupted)
+}
+
+// recategorize files thought under the category the categorizer picks now,
+// if it picks one.
+func (r *Recategorizer) recategorize(ctx context.Context, thought *models.Thought) (bool, error) {
+	category, tags, err := r.Suggest(ctx, thought)
+	if err != nil {
+		return false, err
+	}
+	if category == "uncategorized" {
+		return false, nil
+	}
+
+	thought.Category = category
+	thought.TopicTags = tags
+	if err := r.thoughtRepo.Update(ctx, thought); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
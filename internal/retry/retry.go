@@ -0,0 +1,142 @@
+// Package retry runs failed categorizations and generations again, waiting
+// longer after each failure, until they succeed or run out of attempts.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// checkInterval is how often the worker looks for jobs that are due.
+	checkInterval = time.Minute
+	// batchSize caps how many jobs run in one check.
+	batchSize = 10
+	// claimLease keeps the jobs of a check from being run by another
+	// replica while this one works through them.
+	claimLease = 15 * time.Minute
+	// baseBackoff is the wait after the first failed retry, doubled after
+	// each one after.
+	baseBackoff = 2 * time.Minute
+	// maxBackoff caps the wait between retries.
+	maxBackoff = 2 * time.Hour
+)
+
+// Runner runs a failed job again.
+type Runner interface {
+	RunFailedJob(ctx context.Context, job *models.FailedJob) error
+}
+
+// Notifier tells the channel a job came from when it is given up on.
+type Notifier interface {
+	SendMessage(channelID, message string) error
+}
+
+type Worker struct {
+	repo        *database.FailedJobRepository
+	runner      Runner
+	notifier    Notifier
+	maxAttempts int
+}
+
+// NewWorker gives up on a job after maxAttempts failed attempts, counting
+// the one that recorded it.
+func NewWorker(repo *database.FailedJobRepository, runner Runner, notifier Notifier, maxAttempts int) *Worker {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &Worker{
+		repo:        repo,
+		runner:      runner,
+		notifier:    notifier,
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (w *Worker) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "failed job retrier started", "max_attempts", w.maxAttempts)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "failed job retrier stopped")
+			return
+		case <-ticker.C:
+			w.retryDue(ctx)
+		}
+	}
+}
+
+func (w *Worker) retryDue(ctx context.Context) {
+	jobs, err := w.repo.ClaimDue(ctx, claimLease, batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get due jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		w.retry(ctx, job)
+	}
+}
+
+func (w *Worker) retry(ctx context.Context, job *models.FailedJob) {
+	err := w.runner.RunFailedJob(ctx, job)
+	if err == nil {
+		if err := w.repo.Resolve(ctx, job.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to resolve job", "job_id", job.ID, "error", err)
+			return
+		}
+		slog.InfoContext(ctx, "failed job succeeded on retry", "job_id", job.ID, "kind", job.Kind, "attempts", job.Attempts+1)
+		return
+	}
+	if ctx.Err() != nil {
+		// Stopped mid-job: it runs again once its claim runs out.
+		return
+	}
+
+	attempts := job.Attempts + 1
+	var next *time.Time
+	if attempts < w.maxAttempts {
+		at := time.Now().Add(backoff(attempts))
+		next = &at
+	}
+
+	if err := w.repo.RecordAttempt(ctx, job.ID, err.Error(), next); err != nil {
+		slog.ErrorContext(ctx, "failed to record job attempt", "job_id", job.ID, "error", err)
+		return
+	}
+
+	if next != nil {
+		slog.WarnContext(ctx, "failed job failed again", "job_id", job.ID, "kind", job.Kind, "attempts", attempts, "next_attempt_at", next, "error", err)
+		return
+	}
+
+	slog.ErrorContext(ctx, "gave up on failed job", "job_id", job.ID, "kind", job.Kind, "attempts", attempts, "error", err)
+	if w.notifier != nil && job.ChannelID != "" {
+		message := fmt.Sprintf("⚠️ Gave up on %s after %d attempts: %s\nUse `@LinkedIn Ghostwriter retry failed` to try again.", job.Describe(), attempts, err)
+		if err := w.notifier.SendMessage(job.ChannelID, message); err != nil {
+			slog.ErrorContext(ctx, "failed to send give-up notice", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// backoff is the wait after a job's attempts-th failure.
+func backoff(attempts int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
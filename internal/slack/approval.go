@@ -2,33 +2,157 @@ package slack
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 )
 
+// errAlreadyDecided is returned when a review decision arrives for a post
+// that has already left review.
+var errAlreadyDecided = errors.New("post has already been decided on")
+
 type ApprovalHandler struct {
-	client     *Client
-	postRepo   *database.PostRepository
-	draftCache map[string][]string // messageTS -> []postIDs
+	client           *Client
+	postRepo         *database.PostRepository
+	thoughtRepo      *database.ThoughtRepository
+	draftMessageRepo *database.DraftMessageRepository
+	revisionRepo     *database.RevisionRepository
+	approvalRepo     *database.ApprovalRepository
+	contactRepo      *database.ContactRepository
+	auditRepo        *database.AuditRepository
+	publishTargets   []string
+	reviewerID       string
+	roles            *Roles
+	policy           *agents.PolicyAgent
 }
 
-func NewApprovalHandler(client *Client, postRepo *database.PostRepository) *ApprovalHandler {
+// NewApprovalHandler sets up draft reviews. A non-empty reviewerID turns on
+// review mode: drafts are also sent to the reviewer, and a post is only
+// approved once both its author and the reviewer have approved it. Drafts
+// that name someone in contactRepo get a reply listing who will be tagged.
+// Reactions and buttons are checked against roles first. `undo` finds the
+// last change to revert in auditRepo. Drafts that policy flags as
+// confidential can't be approved; policy may be nil.
+func NewApprovalHandler(client *Client, postRepo *database.PostRepository, thoughtRepo *database.ThoughtRepository, draftMessageRepo *database.DraftMessageRepository, revisionRepo *database.RevisionRepository, approvalRepo *database.ApprovalRepository, contactRepo *database.ContactRepository, auditRepo *database.AuditRepository, publishTargets []string, reviewerID string, roles *Roles, policy *agents.PolicyAgent) *ApprovalHandler {
 	return &ApprovalHandler{
-		client:     client,
-		postRepo:   postRepo,
-		draftCache: make(map[string][]string),
+		client:           client,
+		postRepo:         postRepo,
+		thoughtRepo:      thoughtRepo,
+		draftMessageRepo: draftMessageRepo,
+		revisionRepo:     revisionRepo,
+		approvalRepo:     approvalRepo,
+		contactRepo:      contactRepo,
+		auditRepo:        auditRepo,
+		publishTargets:   publishTargets,
+		reviewerID:       reviewerID,
+		roles:            roles,
+		policy:           policy,
 	}
 }
 
-func (h *ApprovalHandler) StoreDraftMessage(messageTS string, postIDs []string) {
-	h.draftCache[messageTS] = postIDs
+// ShareDrafts posts drafts for review in channelID, remembers the message
+// so reactions on it work, and in review mode sends them to the reviewer.
+// The progress message of the generation that wrote them, if ctx holds
+// one, is replaced by the drafts instead.
+func (h *ApprovalHandler) ShareDrafts(ctx context.Context, channelID string, blocks []slack.Block, postIDs []string) error {
+	var messageTS string
+	if progress := takeProgress(ctx, channelID); progress != nil {
+		messageTS = progress.replace(blocks)
+	}
+	if messageTS == "" {
+		var err error
+		if messageTS, err = h.client.SendBlocksAndGetTS(channelID, blocks); err != nil {
+			return err
+		}
+	}
+
+	if err := h.StoreDraftMessage(ctx, channelID, messageTS, postIDs); err != nil {
+		return err
+	}
+
+	h.suggestMentions(ctx, channelID, messageTS, postIDs)
+
+	if h.reviewerID != "" {
+		h.requestReview(ctx, channelID, postIDs)
+	}
+
+	return nil
+}
+
+// suggestMentions replies to a draft message with the contacts each draft
+// names.
+func (h *ApprovalHandler) suggestMentions(ctx context.Context, channelID, messageTS string, postIDs []string) {
+	if h.contactRepo == nil {
+		return
+	}
+
+	contacts, err := h.contactRepo.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load contacts", "error", err)
+		return
+	}
+	if len(contacts) == 0 {
+		return
+	}
+
+	var lines []string
+	for i, postID := range postIDs {
+		post, err := h.postRepo.GetByID(ctx, postID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load draft", "post_id", postID, "error", err)
+			continue
+		}
+
+		if text := formatMentions(post, contacts); text != "" {
+			label := "This draft"
+			if len(postIDs) > 1 {
+				label = fmt.Sprintf("Variation %d", i+1)
+			}
+			lines = append(lines, "• "+label+" "+text)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	if err := h.client.SendThreadMessage(channelID, messageTS, "👥 *Mentions*\n"+strings.Join(lines, "\n")); err != nil {
+		slog.ErrorContext(ctx, "Failed to suggest mentions", "error", err)
+	}
+}
+
+func (h *ApprovalHandler) StoreDraftMessage(ctx context.Context, channelID, messageTS string, postIDs []string) error {
+	return h.draftMessageRepo.Create(ctx, models.NewDraftMessage(channelID, messageTS, postIDs))
 }
 
 func (h *ApprovalHandler) HandleReaction(ctx context.Context, event *slackevents.ReactionAddedEvent) error {
-	postIDs, exists := h.draftCache[event.Item.Timestamp]
-	if !exists {
+	draftMessage, err := h.draftMessageRepo.GetByMessageTS(ctx, event.Item.Timestamp)
+	if err != nil {
+		return err
+	}
+	if draftMessage == nil {
+		return nil
+	}
+
+	postIDs := draftMessage.PostIDs
+	index, approvesOne := numberReactions[event.Reaction]
+
+	switch event.Reaction {
+	case "white_check_mark", "heavy_check_mark", "✅", "x", "❌", "calendar", "📅":
+	default:
+		if !approvesOne {
+			return nil
+		}
+	}
+	if !h.roles.authorize(ctx, event.Item.Channel, event.User, models.RoleEditor, "approve or reject drafts") {
 		return nil
 	}
 
@@ -37,69 +161,106 @@ func (h *ApprovalHandler) HandleReaction(ctx context.Context, event *slackevents
 		return h.approveDrafts(ctx, event, postIDs)
 	case "x", "❌":
 		return h.rejectDrafts(ctx, event, postIDs)
-	case "one", "1️⃣":
-		return h.approveSpecificDraft(ctx, event, postIDs, 0)
-	case "two", "2️⃣":
-		return h.approveSpecificDraft(ctx, event, postIDs, 1)
-	case "three", "3️⃣":
-		return h.approveSpecificDraft(ctx, event, postIDs, 2)
 	case "calendar", "📅":
 		return h.scheduleDrafts(ctx, event, postIDs)
 	}
 
-	return nil
+	return h.approveSpecificDraft(ctx, event, postIDs, index)
 }
 
+// numberReactions maps the number reactions that approve one variation to
+// its index.
+var numberReactions = map[string]int{
+	"one": 0, "1️⃣": 0,
+	"two": 1, "2️⃣": 1,
+	"three": 2, "3️⃣": 2,
+	"four": 3, "4️⃣": 3,
+	"five": 4, "5️⃣": 4,
+}
+
+// numberEmoji is the reaction that approves the variation at each index.
+var numberEmoji = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣"}
+
 func (h *ApprovalHandler) approveSpecificDraft(ctx context.Context, event *slackevents.ReactionAddedEvent, postIDs []string, index int) error {
 	if index >= len(postIDs) {
 		return h.client.SendMessage(event.Item.Channel, "Invalid variation number")
 	}
 
-	postID := postIDs[index]
-	post, err := h.postRepo.GetByID(ctx, postID)
+	post, err := h.decide(ctx, postIDs[index], event.User, models.DecisionApproved)
+	if errors.Is(err, errAlreadyDecided) {
+		return h.client.SendMessage(event.Item.Channel, fmt.Sprintf("Variation %d is already %s", index+1, post.Status))
+	}
+	if blocked := policyBlocked(err, fmt.Sprintf("Variation %d", index+1)); blocked != "" {
+		return h.client.SendMessage(event.Item.Channel, blocked)
+	}
 	if err != nil {
 		return err
 	}
 
-	post.Status = "approved"
-	if err := h.postRepo.Update(ctx, post); err != nil {
-		return err
-	}
+	h.rejectSiblings(ctx, postIDs, postIDs[index], event.User)
 
-	for i, otherID := range postIDs {
-		if i != index {
-			h.postRepo.UpdateStatus(ctx, otherID, "rejected")
-		}
+	if post.Status == models.StatusInReview {
+		return h.client.SendMessage(event.Item.Channel, fmt.Sprintf("Approved Variation %d! It can be scheduled once %s approves it too.", index+1, h.pendingOn(event.User)))
 	}
 
 	message := fmt.Sprintf("Approved Variation %d! Ready for scheduling.\n\nUse `@LinkedIn Ghostwriter schedule` to schedule it.", index+1)
 	return h.client.SendMessage(event.Item.Channel, message)
 }
 
+// rejectSiblings rejects the variations in postIDs other than approvedID,
+// since approving one variation picks it over the rest. It returns the ones
+// it rejected.
+func (h *ApprovalHandler) rejectSiblings(ctx context.Context, postIDs []string, approvedID, userID string) []string {
+	var rejected []string
+	for _, otherID := range postIDs {
+		if otherID == approvedID {
+			continue
+		}
+		if _, err := h.decide(ctx, otherID, userID, models.DecisionRejected); err != nil {
+			continue
+		}
+		rejected = append(rejected, otherID)
+	}
+	return rejected
+}
+
 func (h *ApprovalHandler) approveDrafts(ctx context.Context, event *slackevents.ReactionAddedEvent, postIDs []string) error {
-	var approvedCount int
-	for _, postID := range postIDs {
-		post, err := h.postRepo.GetByID(ctx, postID)
+	var approvedCount, inReviewCount int
+	var blocked []string
+	for i, postID := range postIDs {
+		post, err := h.decide(ctx, postID, event.User, models.DecisionApproved)
+		if explanation := policyBlocked(err, fmt.Sprintf("Variation %d", i+1)); explanation != "" {
+			blocked = append(blocked, explanation)
+			continue
+		}
 		if err != nil {
 			continue
 		}
-
-		post.Status = "approved"
-		if err := h.postRepo.Update(ctx, post); err != nil {
+		if post.Status == models.StatusInReview {
+			inReviewCount++
 			continue
 		}
 
 		approvedCount++
 	}
 
+	if inReviewCount > 0 {
+		message := fmt.Sprintf("Approved %d draft(s). They can be scheduled once %s approves them too.", approvedCount+inReviewCount, h.pendingOn(event.User))
+		return h.client.SendMessage(event.Item.Channel, withBlocked(message, blocked))
+	}
+
+	if approvedCount == 0 && len(blocked) > 0 {
+		return h.client.SendMessage(event.Item.Channel, strings.Join(blocked, "\n\n"))
+	}
+
 	message := fmt.Sprintf("Approved %d draft(s)! They're ready for scheduling.\n\nUse `@LinkedIn Ghostwriter schedule` to schedule them for posting.", approvedCount)
-	return h.client.SendMessage(event.Item.Channel, message)
+	return h.client.SendMessage(event.Item.Channel, withBlocked(message, blocked))
 }
 
 func (h *ApprovalHandler) rejectDrafts(ctx context.Context, event *slackevents.ReactionAddedEvent, postIDs []string) error {
 	var rejectedCount int
 	for _, postID := range postIDs {
-		if err := h.postRepo.UpdateStatus(ctx, postID, "rejected"); err != nil {
+		if _, err := h.decide(ctx, postID, event.User, models.DecisionRejected); err != nil {
 			continue
 		}
 		rejectedCount++
@@ -110,21 +271,311 @@ func (h *ApprovalHandler) rejectDrafts(ctx context.Context, event *slackevents.R
 }
 
 func (h *ApprovalHandler) scheduleDrafts(ctx context.Context, event *slackevents.ReactionAddedEvent, postIDs []string) error {
-	var scheduledCount int
-	for _, postID := range postIDs {
-		post, err := h.postRepo.GetByID(ctx, postID)
+	var scheduledCount, inReviewCount int
+	var blocked []string
+	for i, postID := range postIDs {
+		post, err := h.decide(ctx, postID, event.User, models.DecisionApproved)
+		if explanation := policyBlocked(err, fmt.Sprintf("Variation %d", i+1)); explanation != "" {
+			blocked = append(blocked, explanation)
+			continue
+		}
 		if err != nil {
 			continue
 		}
-
-		post.Status = "approved"
-		if err := h.postRepo.Update(ctx, post); err != nil {
+		if post.Status == models.StatusInReview {
+			inReviewCount++
 			continue
 		}
 
 		scheduledCount++
 	}
 
+	if inReviewCount > 0 {
+		message := fmt.Sprintf("Approved %d draft(s). They can be scheduled once %s approves them too.", scheduledCount+inReviewCount, h.pendingOn(event.User))
+		return h.client.SendMessage(event.Item.Channel, withBlocked(message, blocked))
+	}
+
+	if scheduledCount == 0 && len(blocked) > 0 {
+		return h.client.SendMessage(event.Item.Channel, strings.Join(blocked, "\n\n"))
+	}
+
 	message := fmt.Sprintf("Marked %d draft(s) for scheduling. Use `@LinkedIn Ghostwriter schedule` to set posting times.", scheduledCount)
-	return h.client.SendMessage(event.Item.Channel, message)
-}
\ No newline at end of file
+	return h.client.SendMessage(event.Item.Channel, withBlocked(message, blocked))
+}
+
+// AuthorizeAction reports whether the user who clicked a button or picked
+// a menu option may do so, and tells them privately when they may not.
+// actionID is the action's ID without any per-button suffix.
+func (h *ApprovalHandler) AuthorizeAction(ctx context.Context, callback *slack.InteractionCallback, actionID string, action *slack.BlockAction) bool {
+	return h.roles.authorize(ctx, callback.Channel.ID, callback.User.ID, actionRole(actionID, action), "do that")
+}
+
+func (h *ApprovalHandler) HandleBlockAction(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) error {
+	postID := action.Value
+	userID := callback.User.ID
+
+	switch action.ActionID {
+	case actionApproveDraft:
+		post, err := h.decide(ctx, postID, userID, models.DecisionApproved)
+		if errors.Is(err, errAlreadyDecided) {
+			return h.markDecision(callback, postID, fmt.Sprintf("Already %s", post.Status))
+		}
+		if blocked := policyBlocked(err, "this draft"); blocked != "" {
+			return h.client.SendEphemeral(callback.Channel.ID, userID, blocked)
+		}
+		if err != nil {
+			return err
+		}
+
+		blocks := callback.Message.Blocks.BlockSet
+		for _, siblingID := range h.rejectVariations(ctx, callback.Message.Timestamp, postID, userID) {
+			blocks = replaceDraftActions(blocks, siblingID, fmt.Sprintf("❌ Rejected, <@%s> picked another variation", userID))
+		}
+
+		text := fmt.Sprintf("✅ Approved by <@%s>. Use `@LinkedIn Ghostwriter schedule` to schedule it.", userID)
+		if post.Status == models.StatusInReview {
+			text = fmt.Sprintf("✅ Approved by <@%s>, waiting on %s", userID, h.pendingOn(userID))
+		} else if len(h.publishTargets) > 1 {
+			text += " Publishing to " + formatTargets(post) + "."
+		}
+		blocks = replaceDraftActions(blocks, postID, text)
+		return h.client.UpdateMessageBlocks(callback.Channel.ID, callback.Message.Timestamp, blocks)
+
+	case actionRejectDraft:
+		post, err := h.decide(ctx, postID, userID, models.DecisionRejected)
+		if errors.Is(err, errAlreadyDecided) {
+			return h.markDecision(callback, postID, fmt.Sprintf("Already %s", post.Status))
+		}
+		if err != nil {
+			return err
+		}
+		return h.markDecision(callback, postID, fmt.Sprintf("❌ Rejected by <@%s>", userID))
+
+	case actionKeepDraft:
+		if err := h.postRepo.UpdateReview(ctx, postID, "draft", userID); err != nil {
+			return err
+		}
+		return h.markDecision(callback, postID, fmt.Sprintf("📌 Kept by <@%s>, back in `drafts`", userID))
+
+	case actionDiscardDraft:
+		if err := h.postRepo.UpdateReview(ctx, postID, "rejected", userID); err != nil {
+			return err
+		}
+		return h.markDecision(callback, postID, fmt.Sprintf("🗑️ Discarded by <@%s>", userID))
+
+	case actionDraftTargets:
+		postID = strings.TrimPrefix(action.BlockID, draftActionsBlockID(""))
+		targets := []string{}
+		for _, option := range action.SelectedOptions {
+			targets = append(targets, option.Value)
+		}
+		return h.postRepo.SetTargets(ctx, postID, targets)
+
+	case actionEditDraft:
+		post, err := h.postRepo.GetByID(ctx, postID)
+		if err != nil {
+			return err
+		}
+
+		modal, err := buildEditDraftModal(post, editDraftMetadata{
+			PostID:    postID,
+			ChannelID: callback.Channel.ID,
+			MessageTS: callback.Message.Timestamp,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = h.client.GetAPI().OpenView(callback.TriggerID, modal)
+		return err
+	}
+
+	return nil
+}
+
+func (h *ApprovalHandler) HandleEditSubmission(ctx context.Context, callback *slack.InteractionCallback) error {
+	var metadata editDraftMetadata
+	if err := json.Unmarshal([]byte(callback.View.PrivateMetadata), &metadata); err != nil {
+		return fmt.Errorf("failed to parse modal metadata: %w", err)
+	}
+
+	// Submissions skip the check buttons go through, so the role is
+	// checked again here.
+	if !h.roles.authorize(ctx, metadata.ChannelID, callback.User.ID, models.RoleEditor, "edit drafts") {
+		return nil
+	}
+
+	content := format.FromSlack(callback.View.State.Values[editDraftBlockID][editDraftInputID].Value)
+	if content == "" {
+		return fmt.Errorf("edited draft is empty")
+	}
+
+	post, err := h.postRepo.GetByID(ctx, metadata.PostID)
+	if err != nil {
+		return err
+	}
+
+	post.Content = content
+	post.FirstComment = format.FromSlack(callback.View.State.Values[editCommentBlockID][editCommentInputID].Value)
+	post.Status = "draft"
+	// The flagged claims may have been fixed or be the author's own now.
+	post.FactCheck = nil
+	if err := h.postRepo.Update(ctx, post); err != nil {
+		return err
+	}
+
+	// The edited draft is a new version, so earlier decisions on it no
+	// longer count.
+	if err := h.approvalRepo.Reset(ctx, post.ID); err != nil {
+		slog.ErrorContext(ctx, "Failed to reset approvals of post", "post_id", post.ID, "error", err)
+	}
+
+	userID := callback.User.ID
+	if err := h.revisionRepo.Record(ctx, post.ID, post.Content, models.RevisionEdit, userID, ""); err != nil {
+		slog.ErrorContext(ctx, "Failed to record revision of post", "post_id", post.ID, "error", err)
+	}
+	history, err := h.client.GetMessage(metadata.ChannelID, metadata.MessageTS)
+	if err == nil {
+		blocks := replaceDraftActions(history.Blocks.BlockSet, post.ID, fmt.Sprintf("✏️ Edited by <@%s>, revised draft posted below", userID))
+		if err := h.client.UpdateMessageBlocks(metadata.ChannelID, metadata.MessageTS, blocks); err != nil {
+			slog.ErrorContext(ctx, "Failed to update original draft message", "error", err)
+		}
+	}
+
+	header := fmt.Sprintf("*Revised Draft*\n_Edited by <@%s>_", userID)
+	return h.ShareDrafts(ctx, metadata.ChannelID, buildDraftBlocks(header, []*models.Post{post}, h.publishTargets), []string{post.ID})
+}
+
+// PromptStaleDrafts posts the janitor's weekly keep-or-discard prompt.
+func (h *ApprovalHandler) PromptStaleDrafts(ctx context.Context, channelID string, drafts []*models.Post, staleDays int) error {
+	return h.client.SendMessageWithBlocks(channelID, buildStaleDraftBlocks(drafts, staleDays))
+}
+
+// decide records userID's decision on a post and returns the post with the
+// status it ended up in. In review mode the reviewer's decisions count for
+// the reviewer and everyone else's for the author, and only posts still
+// waiting on a decision can be decided, so a late click on a variation the
+// author already passed over can't bring it back. A post that mentions
+// confidential information can't be approved by anyone, and an
+// *agents.PolicyError says why.
+func (h *ApprovalHandler) decide(ctx context.Context, postID, userID, decision string) (*models.Post, error) {
+	post, err := h.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	role := models.RoleAuthor
+	if h.reviewerID != "" {
+		if userID == h.reviewerID {
+			role = models.RoleReviewer
+		}
+		if post.Status != "draft" && post.Status != "stale" && post.Status != models.StatusInReview {
+			return post, errAlreadyDecided
+		}
+	}
+
+	if decision == models.DecisionApproved {
+		if err := h.policy.Check(post); err != nil {
+			return post, err
+		}
+	}
+
+	status, err := h.approvalRepo.Decide(ctx, models.NewApproval(postID, userID, role, decision), h.reviewerID != "")
+	if err != nil {
+		return nil, err
+	}
+	post.Status = status
+
+	if status == models.DecisionApproved {
+		h.markThoughtsUsed(ctx, post)
+	}
+	if role == models.RoleReviewer {
+		h.replyToAuthor(ctx, post, decision)
+	}
+
+	return post, nil
+}
+
+// pendingOn names who still has to approve after userID did.
+func (h *ApprovalHandler) pendingOn(userID string) string {
+	if userID == h.reviewerID {
+		return "the author"
+	}
+	return fmt.Sprintf("<@%s>", h.reviewerID)
+}
+
+// requestReview sends the reviewer the drafts just shared in channelID.
+// A failure is only logged: the drafts are already up for the author.
+func (h *ApprovalHandler) requestReview(ctx context.Context, channelID string, postIDs []string) {
+	var posts []*models.Post
+	for _, postID := range postIDs {
+		post, err := h.postRepo.GetByID(ctx, postID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to get post for review", "post_id", postID, "error", err)
+			continue
+		}
+		posts = append(posts, post)
+	}
+	if len(posts) == 0 {
+		return
+	}
+
+	// Posting to a user ID delivers the message in the bot's DM with them.
+	if err := h.client.SendMessageWithBlocks(h.reviewerID, buildReviewBlocks(channelID, posts)); err != nil {
+		slog.ErrorContext(ctx, "Failed to send drafts to reviewer", "reviewer", h.reviewerID, "error", err)
+	}
+}
+
+// replyToAuthor lets the author know, in the thread of the draft message,
+// what the reviewer decided.
+func (h *ApprovalHandler) replyToAuthor(ctx context.Context, post *models.Post, decision string) {
+	message, err := h.draftMessageRepo.GetLatestByPostID(ctx, post.ID)
+	if err != nil || message == nil {
+		return
+	}
+
+	text := fmt.Sprintf("❌ <@%s> rejected this draft.", h.reviewerID)
+	switch {
+	case decision == models.DecisionApproved && post.Status == models.DecisionApproved:
+		text = fmt.Sprintf("✅ <@%s> approved this draft too. Use `@LinkedIn Ghostwriter schedule` to schedule it.", h.reviewerID)
+	case decision == models.DecisionApproved:
+		text = fmt.Sprintf("✅ <@%s> approved this draft. It can be scheduled once you approve it too.", h.reviewerID)
+	}
+
+	if err := h.client.SendThreadMessage(message.ChannelID, message.MessageTS, text); err != nil {
+		slog.ErrorContext(ctx, "Failed to tell author about review", "post_id", post.ID, "error", err)
+	}
+}
+
+// markThoughtsUsed retires the thoughts behind an approved post so later
+// generations pick fresh material.
+func (h *ApprovalHandler) markThoughtsUsed(ctx context.Context, post *models.Post) {
+	if len(post.SourceThoughtIDs) == 0 {
+		return
+	}
+
+	if err := h.thoughtRepo.MarkUsed(ctx, post.SourceThoughtIDs, post.ID); err != nil {
+		slog.ErrorContext(ctx, "Failed to mark thoughts used by post", "post_id", post.ID, "error", err)
+	}
+}
+
+// rejectVariations rejects the other variations shared in the message at
+// messageTS once postID is approved with its button, like approving one
+// with a number reaction does.
+func (h *ApprovalHandler) rejectVariations(ctx context.Context, messageTS, postID, userID string) []string {
+	draftMessage, err := h.draftMessageRepo.GetByMessageTS(ctx, messageTS)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load draft message", "message_ts", messageTS, "error", err)
+		return nil
+	}
+	if draftMessage == nil {
+		return nil
+	}
+
+	return h.rejectSiblings(ctx, draftMessage.PostIDs, postID, userID)
+}
+
+func (h *ApprovalHandler) markDecision(callback *slack.InteractionCallback, postID, text string) error {
+	blocks := replaceDraftActions(callback.Message.Blocks.BlockSet, postID, text)
+	return h.client.UpdateMessageBlocks(callback.Channel.ID, callback.Message.Timestamp, blocks)
+}
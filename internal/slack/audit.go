@@ -0,0 +1,185 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	auditUsage = "Usage: `@LinkedIn Ghostwriter audit [draft #]`, `@LinkedIn Ghostwriter audit scheduled [#]` or `@LinkedIn Ghostwriter audit [post ID]`"
+
+	// recentCommandsLimit is how many commands `audit` alone lists.
+	recentCommandsLimit = 15
+	// maxAuditCommand caps how much of a command the audit log keeps.
+	maxAuditCommand = 500
+)
+
+// recordCommand adds a command someone ran to the audit log.
+func (h *CommandHandler) recordCommand(ctx context.Context, text string) {
+	entry := &models.AuditEntry{
+		Action: models.AuditCommand,
+		Detail: truncate(text, maxAuditCommand),
+	}
+	if err := h.auditRepo.Record(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "Failed to record command", "error", err)
+	}
+}
+
+// HandleAudit shows the lifecycle of a post: who changed its status and
+// when, every version of its content and every attempt to publish it,
+// including the drafts it was revised from. Without arguments it lists the
+// latest commands.
+func (h *CommandHandler) HandleAudit(ctx context.Context, channelID string, args []string) error {
+	if len(args) == 0 {
+		return h.listRecentCommands(ctx, channelID)
+	}
+
+	var post *models.Post
+	var label string
+	switch {
+	case args[0] == "scheduled" && len(args) == 2:
+		var number int
+		var err error
+		post, number, err = h.scheduledPostByNumber(ctx, channelID, args[1])
+		if post == nil {
+			return err
+		}
+		label = fmt.Sprintf("Scheduled Post %d", number)
+
+	case len(args) != 1:
+		return h.client.SendMessage(channelID, auditUsage)
+
+	case uuid.Validate(args[0]) == nil:
+		var err error
+		post, err = h.postRepo.GetByID(ctx, args[0])
+		if errors.Is(err, pgx.ErrNoRows) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("Post %s not found.", args[0]))
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load post", "post_id", args[0], "error", err)
+			return h.client.SendMessage(channelID, "Failed to load post")
+		}
+		label = "Post " + post.ID
+
+	default:
+		index, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || index < 1 {
+			return h.client.SendMessage(channelID, auditUsage)
+		}
+
+		drafts, err := h.postRepo.GetByStatus(ctx, "draft")
+		if err != nil {
+			return h.client.SendMessage(channelID, "Failed to fetch drafts")
+		}
+		if index > len(drafts) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("Draft %d not found. Use `@LinkedIn Ghostwriter drafts` to see pending drafts.", index))
+		}
+		post = drafts[index-1]
+		label = fmt.Sprintf("Draft %d", index)
+	}
+
+	entries, err := h.auditRepo.GetLifecycle(ctx, post.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load audit log of post", "post_id", post.ID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to load the audit log")
+	}
+
+	revisions, err := h.revisionRepo.GetHistory(ctx, post.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load history of post", "post_id", post.ID, "error", err)
+	}
+
+	type event struct {
+		at   time.Time
+		line string
+	}
+	var events []event
+	for _, entry := range entries {
+		events = append(events, event{entry.CreatedAt, formatAuditEntry(entry)})
+	}
+	for _, revision := range revisions {
+		line := fmt.Sprintf("✏️ %s", revision.Source)
+		if revision.Editor != "" {
+			line += " by " + formatEditor(revision.Editor)
+		}
+		if revision.Note != "" {
+			line += fmt.Sprintf(": _%s_", truncate(revision.Note, 100))
+		}
+		events = append(events, event{revision.CreatedAt, line})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].at.Before(events[j].at)
+	})
+
+	if len(events) == 0 {
+		return h.client.SendMessage(channelID, fmt.Sprintf("Nothing recorded for %s yet.", label))
+	}
+
+	location := scheduleLocation()
+	message := fmt.Sprintf("*Lifecycle of %s* (now %s)\n_%s_\n\n", label, post.Status, truncate(post.Content, 100))
+	for _, e := range events {
+		message += fmt.Sprintf("`%s` %s\n", e.at.In(location).Format("Jan 2 15:04"), e.line)
+	}
+	message += fmt.Sprintf("\nPost ID: `%s`", post.ID)
+
+	return h.client.SendMessage(channelID, message)
+}
+
+func (h *CommandHandler) listRecentCommands(ctx context.Context, channelID string) error {
+	entries, err := h.auditRepo.GetRecent(ctx, models.AuditCommand, recentCommandsLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load recent commands", "error", err)
+		return h.client.SendMessage(channelID, "Failed to load the audit log")
+	}
+	if len(entries) == 0 {
+		return h.client.SendMessage(channelID, "No commands recorded yet.\n"+auditUsage)
+	}
+
+	location := scheduleLocation()
+	message := fmt.Sprintf("*Recent commands* (last %d)\n\n", len(entries))
+	for _, entry := range entries {
+		message += fmt.Sprintf("`%s` %s: %s\n", entry.CreatedAt.In(location).Format("Jan 2 15:04"),
+			formatActor(entry.Actor), truncate(entry.Detail, 100))
+	}
+	message += "\n" + auditUsage
+
+	return h.client.SendMessage(channelID, message)
+}
+
+func formatAuditEntry(entry *models.AuditEntry) string {
+	switch entry.Action {
+	case models.AuditTransition:
+		if entry.FromStatus == "" {
+			return fmt.Sprintf("🆕 created as *%s* by %s", entry.ToStatus, formatActor(entry.Actor))
+		}
+		return fmt.Sprintf("🔀 %s → *%s* by %s", entry.FromStatus, entry.ToStatus, formatActor(entry.Actor))
+
+	case models.AuditPublish:
+		line := "📤 publish attempt: *" + entry.ToStatus + "*"
+		if entry.Detail != "" {
+			line += " · " + truncate(entry.Detail, 200)
+		}
+		return line
+	}
+
+	return fmt.Sprintf("%s by %s: %s", entry.Action, formatActor(entry.Actor), truncate(entry.Detail, 200))
+}
+
+// formatActor names who acted; entries without an actor were made by the
+// bot itself, such as the publisher or the janitor.
+func formatActor(actor string) string {
+	if actor == "" {
+		return "system"
+	}
+	return formatEditor(actor)
+}
@@ -0,0 +1,265 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	pauseUsage    = "Usage: `@LinkedIn Ghostwriter pause [until date]`, e.g. `pause until 2026-03-14` or `pause until fri`, and `@LinkedIn Ghostwriter resume` to lift it"
+	blackoutUsage = "Usage: `@LinkedIn Ghostwriter blackout add [date] [to date][: reason]`, e.g. `blackout add dec 24 to dec 26: holidays`, or `@LinkedIn Ghostwriter blackout remove [#]`"
+)
+
+// HandlePause stops publishing from today until the date in args, or until
+// `resume` when there is none. Scheduled posts that come due meanwhile wait.
+func (h *CommandHandler) HandlePause(ctx context.Context, channelID, userID, args string) error {
+	if !h.roles.authorize(ctx, channelID, userID, models.RoleOwner, "pause publishing") {
+		return nil
+	}
+
+	today := scheduleToday()
+
+	pause := &models.Blackout{Kind: models.BlackoutPause, StartDate: today, CreatedBy: userID}
+	if args = strings.TrimSpace(args); args != "" {
+		dateText, ok := strings.CutPrefix(strings.ToLower(args), "until ")
+		if !ok {
+			return h.client.SendMessage(channelID, pauseUsage)
+		}
+		until, err := parseBlackoutDate(dateText, today)
+		if err != nil {
+			return h.client.SendMessage(channelID, fmt.Sprintf("%v. %s", err, pauseUsage))
+		}
+		if until.Before(today) {
+			return h.client.SendMessage(channelID, "That date has already passed.")
+		}
+		pause.EndDate = &until
+	}
+
+	if err := h.blackoutRepo.Create(ctx, pause); err != nil {
+		slog.ErrorContext(ctx, "Failed to pause publishing", "error", err)
+		return h.client.SendMessage(channelID, "Failed to pause publishing")
+	}
+
+	slog.InfoContext(ctx, "Paused publishing", "until", pause.EndDate, "user", userID)
+
+	message := fmt.Sprintf("⏸️ <@%s> paused publishing until `resume`.", userID)
+	if pause.EndDate != nil {
+		message = fmt.Sprintf("⏸️ <@%s> paused publishing through %s.", userID, pause.EndDate.Format("Mon, Jan 2"))
+	}
+	return h.client.SendMessage(channelID, message+h.heldPosts(ctx, pause))
+}
+
+// HandleResume lifts every pause. Posts that came due meanwhile go out on
+// the publisher's next check; blackout dates still apply.
+func (h *CommandHandler) HandleResume(ctx context.Context, channelID, userID string) error {
+	if !h.roles.authorize(ctx, channelID, userID, models.RoleOwner, "resume publishing") {
+		return nil
+	}
+
+	count, err := h.blackoutRepo.DeletePauses(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to resume publishing", "error", err)
+		return h.client.SendMessage(channelID, "Failed to resume publishing")
+	}
+
+	if count == 0 {
+		return h.client.SendMessage(channelID, "Publishing isn't paused.")
+	}
+
+	slog.InfoContext(ctx, "Resumed publishing", "user", userID)
+
+	message := fmt.Sprintf("▶️ <@%s> resumed publishing. Posts that came due while paused go out within a minute.", userID)
+	if blackout := h.blackoutToday(ctx); blackout != nil {
+		message += fmt.Sprintf(" Today is still blacked out (%s), so they wait until it's over.", describeBlackout(blackout))
+	}
+	return h.client.SendMessage(channelID, message)
+}
+
+// HandleBlackout lists the pauses and blackout dates still ahead, or adds
+// or removes a blackout.
+func (h *CommandHandler) HandleBlackout(ctx context.Context, channelID, userID, args string) error {
+	action, rest := cutWord(args)
+	today := scheduleToday()
+
+	switch strings.ToLower(action) {
+	case "", "list":
+		blackouts, err := h.blackoutRepo.ListActive(ctx, today)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to list blackouts", "error", err)
+			return h.client.SendMessage(channelID, "Failed to list blackouts")
+		}
+
+		var b strings.Builder
+		b.WriteString("*Blackouts*\nNothing is scheduled or published on these dates.\n")
+		if len(blackouts) == 0 {
+			b.WriteString("_None ahead._\n")
+		}
+		for i, blackout := range blackouts {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, describeBlackout(blackout))
+		}
+		b.WriteString("\n" + blackoutUsage)
+		return h.client.SendMessage(channelID, b.String())
+
+	case "add":
+		blackout, err := parseBlackout(rest, today)
+		if err != nil {
+			return h.client.SendMessage(channelID, fmt.Sprintf("%v. %s", err, blackoutUsage))
+		}
+		if blackout.EndDate.Before(today) {
+			return h.client.SendMessage(channelID, "Those dates have already passed.")
+		}
+		blackout.CreatedBy = userID
+
+		if err := h.blackoutRepo.Create(ctx, blackout); err != nil {
+			slog.ErrorContext(ctx, "Failed to add blackout", "error", err)
+			return h.client.SendMessage(channelID, "Failed to add the blackout")
+		}
+
+		slog.InfoContext(ctx, "Added blackout", "start", blackout.StartDate, "end", blackout.EndDate, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("🚫 Blacked out %s.%s", describeBlackout(blackout), h.heldPosts(ctx, blackout)))
+
+	case "remove":
+		number, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(rest), "#"))
+		if err != nil || number < 1 {
+			return h.client.SendMessage(channelID, blackoutUsage)
+		}
+
+		blackouts, err := h.blackoutRepo.ListActive(ctx, today)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to list blackouts", "error", err)
+			return h.client.SendMessage(channelID, "Failed to list blackouts")
+		}
+		if number > len(blackouts) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("Blackout %d not found. Use `@LinkedIn Ghostwriter blackout` to see them.", number))
+		}
+
+		blackout := blackouts[number-1]
+		err = h.blackoutRepo.Delete(ctx, blackout.ID)
+		if err != nil && !errors.Is(err, database.ErrBlackoutNotFound) {
+			slog.ErrorContext(ctx, "Failed to remove blackout", "blackout_id", blackout.ID, "error", err)
+			return h.client.SendMessage(channelID, "Failed to remove the blackout")
+		}
+
+		slog.InfoContext(ctx, "Removed blackout", "blackout_id", blackout.ID, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Removed the blackout of %s.", describeBlackout(blackout)))
+	}
+
+	return h.client.SendMessage(channelID, blackoutUsage)
+}
+
+// heldPosts tells how many scheduled posts blackout holds back, or "" when
+// none fall in it.
+func (h *CommandHandler) heldPosts(ctx context.Context, blackout *models.Blackout) string {
+	upcoming, err := h.scheduler.GetUpcoming(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to check scheduled posts against blackout", "error", err)
+		return ""
+	}
+
+	location := scheduleLocation()
+	held := 0
+	for _, post := range upcoming {
+		if blackout.Covers(post.ScheduledAt.In(location).Format(models.DateLayout)) {
+			held++
+		}
+	}
+
+	if held == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %d scheduled post(s) fall in it and wait until it's over, or move them with `reschedule`.", held)
+}
+
+// blackoutToday returns the blackout covering today, or nil.
+func (h *CommandHandler) blackoutToday(ctx context.Context) *models.Blackout {
+	today := scheduleToday()
+	blackouts, err := h.blackoutRepo.ListActive(ctx, today)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to list blackouts", "error", err)
+		return nil
+	}
+	return models.Covering(blackouts, today.Format(models.DateLayout))
+}
+
+// scheduleToday returns today in the schedule's timezone, as the UTC
+// midnight blackout dates are kept at.
+func scheduleToday() time.Time {
+	now := time.Now().In(scheduleLocation())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseBlackout reads `date [to date][: reason]`.
+func parseBlackout(text string, today time.Time) (*models.Blackout, error) {
+	dates, reason, _ := strings.Cut(text, ":")
+	dates = strings.ToLower(strings.TrimSpace(dates))
+	if dates == "" {
+		return nil, fmt.Errorf("I need a date")
+	}
+
+	startText, endText, isRange := strings.Cut(dates, " to ")
+	start, err := parseBlackoutDate(startText, today)
+	if err != nil {
+		return nil, err
+	}
+	end := start
+	if isRange {
+		if end, err = parseBlackoutDate(endText, today); err != nil {
+			return nil, err
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("I can't end a blackout before it starts")
+		}
+	}
+
+	return &models.Blackout{
+		Kind:      models.BlackoutDates,
+		StartDate: start,
+		EndDate:   &end,
+		Reason:    strings.TrimSpace(reason),
+	}, nil
+}
+
+// parseBlackoutDate reads a date the way `reschedule` does, as UTC
+// midnight. A date without a year that has passed is taken as next year's.
+func parseBlackoutDate(text string, today time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	date, err := parseScheduleDate(text, today)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if date.Before(today) && !strings.Contains(text, strconv.Itoa(date.Year())) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, nil
+}
+
+// describeBlackout reads like "Dec 24 - Dec 26 (holidays)".
+func describeBlackout(blackout *models.Blackout) string {
+	var dates string
+	switch {
+	case blackout.EndDate == nil:
+		dates = fmt.Sprintf("from %s until resumed", blackout.StartDate.Format("Mon, Jan 2"))
+	case blackout.EndDate.Equal(blackout.StartDate):
+		dates = blackout.StartDate.Format("Mon, Jan 2")
+	default:
+		dates = fmt.Sprintf("%s - %s", blackout.StartDate.Format("Mon, Jan 2"), blackout.EndDate.Format("Mon, Jan 2"))
+	}
+
+	if blackout.Kind == models.BlackoutPause {
+		dates = "⏸️ paused " + dates
+	}
+	if blackout.Reason != "" {
+		dates += fmt.Sprintf(" (%s)", blackout.Reason)
+	}
+	return dates
+}
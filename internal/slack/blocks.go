@@ -0,0 +1,352 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+const (
+	actionApproveDraft = "approve_draft"
+	actionRejectDraft  = "reject_draft"
+	actionEditDraft    = "edit_draft"
+	actionDraftTargets = "draft_targets"
+	actionFixDraft     = "fix_draft_style"
+	actionKeepDraft    = "keep_stale_draft"
+	actionDiscardDraft = "discard_stale_draft"
+
+	// maxStaleDrafts keeps a stale draft prompt under Slack's 50 block limit.
+	maxStaleDrafts = 20
+
+	// maxSectionText keeps a section under Slack's 3000 character limit.
+	maxSectionText = 2900
+
+	editDraftCallbackID   = "edit_draft_modal"
+	editDraftBlockID      = "draft_content"
+	editDraftInputID      = "content"
+	editDraftMaxInputSize = 3000
+
+	editCommentBlockID      = "draft_first_comment"
+	editCommentInputID      = "first_comment"
+	editCommentMaxInputSize = 1250
+)
+
+type editDraftMetadata struct {
+	PostID    string `json:"post_id"`
+	ChannelID string `json:"channel_id"`
+	MessageTS string `json:"message_ts"`
+}
+
+func draftActionsBlockID(postID string) string {
+	return "draft_actions:" + postID
+}
+
+// targetLabels names the networks offered in a draft's Targets menu.
+var targetLabels = map[string]string{
+	models.TargetLinkedIn: "LinkedIn",
+	models.TargetCompany:  "Company page",
+	models.TargetX:        "X",
+}
+
+// buildDraftBlocks lays out drafts for review. When targets lists more
+// than one network, each draft gets a menu to pick where it's published.
+// Drafts the style check flags list the issues and get a button to fix them.
+func buildDraftBlocks(header string, posts []*models.Post, targets []string) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, header, false, false), nil, nil),
+	}
+
+	for i, post := range posts {
+		content := format.ToSlack(post.Content)
+		text := fmt.Sprintf("*Variation %d:*\n\n%s", i+1, content)
+		switch {
+		case post.PostType == models.PostTypeCarousel:
+			text = fmt.Sprintf("*Carousel caption:*\n\n%s\n\n%s", content, formatSlides(post.Slides))
+		case post.PostType == models.PostTypePoll && post.Poll != nil:
+			text = fmt.Sprintf("*Poll caption:*\n\n%s\n\n%s", content, formatPoll(post.Poll))
+		}
+
+		issues := agents.Lint(post.Content)
+
+		blocks = append(blocks, slack.NewDividerBlock())
+		blocks = append(blocks, sectionBlocks(text)...)
+		if post.FirstComment != "" {
+			blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, "💬 *First comment:* "+format.ToSlack(post.FirstComment), false, false)))
+		}
+		if sources := formatProvenance(post); sources != "" {
+			blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, sources, false, false)))
+		}
+		if facts := formatFactCheck(post); facts != "" {
+			blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, facts, false, false)))
+		}
+		blocks = append(blocks,
+			slack.NewContextBlock("",
+				slack.NewTextBlockObject(slack.MarkdownType, previewContext(format.ToUnicode(post.Content)), false, false),
+				slack.NewTextBlockObject(slack.MarkdownType, scoresContext(post), false, false),
+			),
+		)
+		if len(issues) > 0 {
+			blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, formatLintIssues(issues), false, false)))
+		}
+		blocks = append(blocks, buildDraftActions(post, targets, len(issues) > 0))
+	}
+
+	footer := "Use the buttons to approve, reject or edit the draft. Reacting with ✅ or ❌ also works."
+	if len(posts) > 1 && len(posts) <= len(numberEmoji) {
+		footer = fmt.Sprintf("Use the buttons to approve, reject or edit each variation. Reacting with %s, ✅ or ❌ also works.",
+			strings.Join(numberEmoji[:len(posts)], " "))
+	}
+
+	blocks = append(blocks,
+		slack.NewDividerBlock(),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, footer, false, false)),
+	)
+
+	return blocks
+}
+
+// buildReviewBlocks lays out drafts shared in channelID for the reviewer,
+// with only the approve and reject buttons: edits happen in the channel.
+func buildReviewBlocks(channelID string, posts []*models.Post) []slack.Block {
+	header := fmt.Sprintf("📝 *Review requested* for %d draft(s) from <#%s>", len(posts), channelID)
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, header, false, false), nil, nil),
+	}
+
+	for _, post := range posts {
+		text := format.ToSlack(post.Content)
+		switch {
+		case post.PostType == models.PostTypeCarousel:
+			text = fmt.Sprintf("%s\n\n%s", text, formatSlides(post.Slides))
+		case post.PostType == models.PostTypePoll && post.Poll != nil:
+			text = fmt.Sprintf("%s\n\n%s", text, formatPoll(post.Poll))
+		}
+		if post.FirstComment != "" {
+			text += "\n\n💬 *First comment:* " + format.ToSlack(post.FirstComment)
+		}
+		if sources := formatProvenance(post); sources != "" {
+			text += "\n\n" + sources
+		}
+		if facts := formatFactCheck(post); facts != "" {
+			text += "\n\n" + facts
+		}
+
+		approve := slack.NewButtonBlockElement(actionApproveDraft, post.ID,
+			slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false))
+		approve.Style = slack.StylePrimary
+
+		reject := slack.NewButtonBlockElement(actionRejectDraft, post.ID,
+			slack.NewTextBlockObject(slack.PlainTextType, "Reject", false, false))
+		reject.Style = slack.StyleDanger
+
+		blocks = append(blocks, slack.NewDividerBlock())
+		blocks = append(blocks, sectionBlocks(text)...)
+		blocks = append(blocks, slack.NewActionBlock(draftActionsBlockID(post.ID), approve, reject))
+	}
+
+	footer := "A draft can only be scheduled once both you and its author approve it."
+	blocks = append(blocks,
+		slack.NewDividerBlock(),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, footer, false, false)),
+	)
+
+	return blocks
+}
+
+// sectionBlocks lays out text as markdown sections, as many as it takes to
+// stay under Slack's limit. Long text is split at a line break when there is
+// one, so a whole draft can be read before it's approved.
+func sectionBlocks(text string) []slack.Block {
+	var blocks []slack.Block
+	runes := []rune(text)
+	for len(runes) > maxSectionText {
+		cut := maxSectionText
+		for i := maxSectionText - 1; i > maxSectionText/2; i-- {
+			if runes[i] == '\n' {
+				cut = i
+				break
+			}
+		}
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, string(runes[:cut]), false, false), nil, nil))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), "\n"))
+	}
+	if len(runes) > 0 || len(blocks) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, string(runes), false, false), nil, nil))
+	}
+	return blocks
+}
+
+func formatSlides(slides []string) string {
+	var b strings.Builder
+	for i, slide := range slides {
+		fmt.Fprintf(&b, "*Slide %d:* %s\n", i+1, strings.ReplaceAll(slide, "\n", " — "))
+	}
+	return b.String()
+}
+
+func formatPoll(poll *models.Poll) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Poll:* %s\n", poll.Question)
+	for _, option := range poll.Options {
+		fmt.Fprintf(&b, "◦ %s\n", option)
+	}
+	return b.String()
+}
+
+func buildDraftActions(post *models.Post, targets []string, fixable bool) *slack.ActionBlock {
+	approve := slack.NewButtonBlockElement(actionApproveDraft, post.ID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false))
+	approve.Style = slack.StylePrimary
+
+	reject := slack.NewButtonBlockElement(actionRejectDraft, post.ID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Reject", false, false))
+	reject.Style = slack.StyleDanger
+
+	edit := slack.NewButtonBlockElement(actionEditDraft, post.ID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Edit", false, false))
+
+	elements := []slack.BlockElement{approve, reject, edit}
+	if fixable {
+		elements = append(elements, slack.NewButtonBlockElement(actionFixDraft, post.ID,
+			slack.NewTextBlockObject(slack.PlainTextType, "Fix style", false, false)))
+	}
+
+	// Carousels and polls only exist on LinkedIn.
+	if post.PostType == models.PostTypeCarousel || post.PostType == models.PostTypePoll {
+		var linkedInTargets []string
+		for _, target := range targets {
+			if target != models.TargetX {
+				linkedInTargets = append(linkedInTargets, target)
+			}
+		}
+		targets = linkedInTargets
+	}
+	if len(targets) < 2 {
+		return slack.NewActionBlock(draftActionsBlockID(post.ID), elements...)
+	}
+
+	var options, selected []*slack.OptionBlockObject
+	for _, target := range targets {
+		option := slack.NewOptionBlockObject(target,
+			slack.NewTextBlockObject(slack.PlainTextType, targetLabels[target], false, false), nil)
+		options = append(options, option)
+		if post.HasTarget(target) {
+			selected = append(selected, option)
+		}
+	}
+
+	menu := slack.NewOptionsMultiSelectBlockElement(slack.MultiOptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Targets", false, false), actionDraftTargets, options...).
+		WithInitialOptions(selected...)
+
+	return slack.NewActionBlock(draftActionsBlockID(post.ID), append(elements, menu)...)
+}
+
+// buildStaleDraftBlocks asks whether to keep or discard drafts nobody has
+// looked at in staleDays.
+func buildStaleDraftBlocks(drafts []*models.Post, staleDays int) []slack.Block {
+	header := fmt.Sprintf("🧹 *%d stale draft(s)* haven't been touched in %d days. Keep or discard them?", len(drafts), staleDays)
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, header, false, false), nil, nil),
+		slack.NewDividerBlock(),
+	}
+
+	for i, draft := range drafts {
+		if i == maxStaleDrafts {
+			more := fmt.Sprintf("_...and %d more, shown again next week._", len(drafts)-maxStaleDrafts)
+			blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, more, false, false)))
+			break
+		}
+
+		text := fmt.Sprintf("*%s* · _created %s_\n%s", draft.PostType, draft.CreatedAt.Format("Jan 02"), format.ToSlack(truncate(draft.Content, 200)))
+
+		keep := slack.NewButtonBlockElement(actionKeepDraft, draft.ID,
+			slack.NewTextBlockObject(slack.PlainTextType, "Keep", false, false))
+		keep.Style = slack.StylePrimary
+
+		discard := slack.NewButtonBlockElement(actionDiscardDraft, draft.ID,
+			slack.NewTextBlockObject(slack.PlainTextType, "Discard", false, false))
+		discard.Style = slack.StyleDanger
+
+		blocks = append(blocks,
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+			slack.NewActionBlock(draftActionsBlockID(draft.ID), keep, discard),
+		)
+	}
+
+	footer := "Kept drafts go back to `drafts`. Discarded ones are archived with the other rejected posts."
+	blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, footer, false, false)))
+
+	return blocks
+}
+
+func formatTargets(post *models.Post) string {
+	var names []string
+	for _, target := range []string{models.TargetLinkedIn, models.TargetCompany, models.TargetX} {
+		if post.HasTarget(target) {
+			names = append(names, targetLabels[target])
+		}
+	}
+	return strings.Join(names, " and ")
+}
+
+// replaceDraftActions swaps the buttons for a decided draft with a context line,
+// leaving every other block in the message untouched.
+func replaceDraftActions(blocks []slack.Block, postID, text string) []slack.Block {
+	blockID := draftActionsBlockID(postID)
+
+	updated := make([]slack.Block, 0, len(blocks))
+	for _, block := range blocks {
+		if actionBlock, ok := block.(*slack.ActionBlock); ok && actionBlock.BlockID == blockID {
+			block = slack.NewContextBlock(blockID, slack.NewTextBlockObject(slack.MarkdownType, text, false, false))
+		}
+		updated = append(updated, block)
+	}
+
+	return updated
+}
+
+func buildEditDraftModal(post *models.Post, metadata editDraftMetadata) (slack.ModalViewRequest, error) {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return slack.ModalViewRequest{}, fmt.Errorf("failed to marshal modal metadata: %w", err)
+	}
+
+	input := slack.NewPlainTextInputBlockElement(nil, editDraftInputID)
+	input.Multiline = true
+	input.InitialValue = format.ToSlackInput(post.Content)
+	input.MaxLength = editDraftMaxInputSize
+
+	comment := slack.NewPlainTextInputBlockElement(nil, editCommentInputID)
+	comment.Multiline = true
+	comment.InitialValue = format.ToSlackInput(post.FirstComment)
+	comment.MaxLength = editCommentMaxInputSize
+
+	commentBlock := slack.NewInputBlock(editCommentBlockID,
+		slack.NewTextBlockObject(slack.PlainTextType, "First comment", false, false),
+		slack.NewTextBlockObject(slack.PlainTextType, "Posted under the post right after it's published. Leave empty for none.", false, false),
+		comment)
+	commentBlock.Optional = true
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      editDraftCallbackID,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Edit Draft", false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		PrivateMetadata: string(metadataJSON),
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewInputBlock(editDraftBlockID,
+					slack.NewTextBlockObject(slack.PlainTextType, "Post content", false, false),
+					nil, input),
+				commentBlock,
+			},
+		},
+	}, nil
+}
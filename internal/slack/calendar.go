@@ -0,0 +1,145 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+const (
+	actionScheduleMenu = "schedule_post_menu"
+
+	scheduleMenuPreview    = "preview"
+	scheduleMenuReschedule = "reschedule"
+	scheduleMenuUnschedule = "unschedule"
+
+	rescheduleCallbackID = "reschedule_post_modal"
+	rescheduleBlockID    = "schedule_time"
+	rescheduleInputID    = "scheduled_at"
+
+	// Slack rejects messages with more than 50 blocks; leave room for the
+	// footer.
+	maxCalendarBlocks = 46
+)
+
+type rescheduleMetadata struct {
+	PostID    string `json:"post_id"`
+	ChannelID string `json:"channel_id"`
+	MessageTS string `json:"message_ts"`
+	Days      int    `json:"days"`
+}
+
+// scheduleMenuValue packs what an overflow option needs to act on a post and
+// refresh the calendar it was picked from.
+func scheduleMenuValue(action, postID string, days int) string {
+	return fmt.Sprintf("%s|%s|%d", action, postID, days)
+}
+
+func parseScheduleMenuValue(value string) (action, postID string, days int, err error) {
+	parts := strings.Split(value, "|")
+	if len(parts) != 3 {
+		return "", "", 0, fmt.Errorf("invalid schedule menu value: %q", value)
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "%d", &days); err != nil {
+		return "", "", 0, fmt.Errorf("invalid schedule menu value: %q", value)
+	}
+
+	return parts[0], parts[1], days, nil
+}
+
+// buildCalendarBlocks renders scheduled posts grouped by day. Posts must be
+// in posting order so their numbers match reschedule/unschedule commands.
+func buildCalendarBlocks(posts []*models.Post, days int, location *time.Location) []slack.Block {
+	header := fmt.Sprintf("*Content Calendar* (next %d days)", days)
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, header, false, false), nil, nil),
+	}
+
+	var currentDay string
+	shown := 0
+	for i, post := range posts {
+		if len(blocks)+4 > maxCalendarBlocks {
+			break
+		}
+		shown++
+
+		scheduledAt := post.ScheduledAt.In(location)
+
+		day := scheduledAt.Format("Monday, Jan 02")
+		if day != currentDay {
+			currentDay = day
+			blocks = append(blocks,
+				slack.NewDividerBlock(),
+				slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, day, false, false)),
+			)
+		}
+
+		text := fmt.Sprintf("*%d. %s*\n%s", i+1, scheduledAt.Format("3:04 PM"), format.ToSlack(truncate(post.Content, 150)))
+
+		menu := slack.NewOverflowBlockElement(actionScheduleMenu,
+			slack.NewOptionBlockObject(scheduleMenuValue(scheduleMenuPreview, post.ID, days),
+				slack.NewTextBlockObject(slack.PlainTextType, "Preview full text", false, false), nil),
+			slack.NewOptionBlockObject(scheduleMenuValue(scheduleMenuReschedule, post.ID, days),
+				slack.NewTextBlockObject(slack.PlainTextType, "Reschedule", false, false), nil),
+			slack.NewOptionBlockObject(scheduleMenuValue(scheduleMenuUnschedule, post.ID, days),
+				slack.NewTextBlockObject(slack.PlainTextType, "Unschedule", false, false), nil),
+		)
+
+		details := fmt.Sprintf("Type: %s · Tone: %s · From %d thought(s)", post.PostType, post.Tone, len(post.SourceThoughtIDs))
+
+		blocks = append(blocks,
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil,
+				slack.NewAccessory(menu)),
+			slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, details, false, false)),
+		)
+	}
+
+	footer := fmt.Sprintf("_Total: %d scheduled posts", len(posts))
+	if shown < len(posts) {
+		footer += fmt.Sprintf(", %d not shown - use a shorter range like `view schedule 3`", len(posts)-shown)
+	}
+	footer += ". Use the ⋯ menu, or `reschedule [post #] [date time]` / `unschedule [post #]`._"
+	blocks = append(blocks,
+		slack.NewDividerBlock(),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, footer, false, false)),
+	)
+
+	return blocks
+}
+
+func buildRescheduleModal(post *models.Post, metadata rescheduleMetadata) (slack.ModalViewRequest, error) {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return slack.ModalViewRequest{}, fmt.Errorf("failed to marshal modal metadata: %w", err)
+	}
+
+	picker := slack.NewDateTimePickerBlockElement(rescheduleInputID)
+	if post.ScheduledAt != nil {
+		picker.InitialDateTime = post.ScheduledAt.Unix()
+	}
+
+	preview := format.ToSlack(truncate(post.Content, 200))
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      rescheduleCallbackID,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Reschedule Post", false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Reschedule", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		PrivateMetadata: string(metadataJSON),
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, preview, false, false), nil, nil),
+				slack.NewInputBlock(rescheduleBlockID,
+					slack.NewTextBlockObject(slack.PlainTextType, "New date and time", false, false),
+					nil, picker),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,98 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+// Conversation is the chat a thought is captured from and answered in.
+// Slack channels are one; other messaging apps, such as Telegram, implement
+// it to capture thoughts through the same pipeline as Slack messages.
+type Conversation interface {
+	// Reply answers the captured message. text is Slack mrkdwn, which
+	// other apps convert with format.FromSlack.
+	Reply(ctx context.Context, text string) error
+}
+
+// SharedFile is a file shared with a captured message.
+type SharedFile struct {
+	Name     string
+	Title    string
+	Mimetype string
+	Size     int
+	// URL links to the file in the app it was shared in, and is kept with
+	// the summary. It is empty when the app has no link that is safe to
+	// keep.
+	URL      string
+	Download func(ctx context.Context) ([]byte, error)
+}
+
+// channel is a Slack channel or DM as a Conversation.
+type channel struct {
+	client *Client
+	id     string
+}
+
+func (c channel) Reply(_ context.Context, text string) error {
+	return c.client.SendMessage(c.id, text)
+}
+
+// sharedFiles returns the files of a Slack message that can be downloaded.
+func (h *MessageHandler) sharedFiles(files []slack.File) []SharedFile {
+	shared := make([]SharedFile, 0, len(files))
+	for _, file := range files {
+		if file.URLPrivateDownload == "" {
+			continue
+		}
+		downloadURL := file.URLPrivateDownload
+		shared = append(shared, SharedFile{
+			Name:     file.Name,
+			Title:    file.Title,
+			Mimetype: file.Mimetype,
+			Size:     file.Size,
+			URL:      file.Permalink,
+			Download: func(ctx context.Context) ([]byte, error) {
+				return h.client.DownloadFile(ctx, downloadURL)
+			},
+		})
+	}
+	return shared
+}
+
+// Capture runs a new message through the capture pipeline: the first file
+// or link shared with it is summarized into thought, which is categorized
+// and saved unless it duplicates an earlier thought, and conversation gets
+// the outcome. The caller sets the thought's source and where it came from.
+func (h *MessageHandler) Capture(ctx context.Context, conversation Conversation, thought *models.Thought, files []SharedFile) error {
+	h.enrichThought(ctx, thought.Content, files, thought)
+	if strings.TrimSpace(thought.Content) == "" {
+		return nil
+	}
+
+	duplicate, err := h.captureThought(ctx, thought)
+	if err != nil {
+		return err
+	}
+	if duplicate != nil {
+		return conversation.Reply(ctx, duplicateMessage(duplicate))
+	}
+
+	confirmationMsg := fmt.Sprintf("Got it! Categorized as: *%s* | Tags: %s",
+		thought.Category,
+		strings.Join(thought.TopicTags, ", "))
+	if thought.SourceTitle != "" || thought.SourceURL != "" {
+		confirmationMsg += fmt.Sprintf("\nSummarized: %s", sourceLabel(thought))
+	}
+	confirmationMsg += relatedSuffix(thought)
+
+	if err := conversation.Reply(ctx, confirmationMsg); err != nil {
+		slog.ErrorContext(ctx, "Failed to send confirmation", "error", err)
+	}
+
+	return nil
+}
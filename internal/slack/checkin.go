@@ -0,0 +1,204 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/checkin"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+const (
+	// actionCheckInCategory prefixes the action ID of each category quick
+	// reply, since buttons in one block need their own IDs.
+	actionCheckInCategory = "checkin_category:"
+	actionCheckInSkip     = "checkin_skip"
+
+	// quickReplyCategories is how many categories the check-in DM offers.
+	quickReplyCategories = 4
+	// quickReplyDays is how far back the most used categories are counted.
+	quickReplyDays = 30
+
+	checkInUsage = "Usage: `@LinkedIn Ghostwriter checkin [on [HH:MM]|off]`"
+)
+
+// HandleCheckIn shows whether userID gets the daily check-in DM, or turns
+// it on or off. Replies to the DM are saved as thoughts in channelID.
+func (h *CommandHandler) HandleCheckIn(ctx context.Context, channelID, userID, args string) error {
+	action, rest := cutWord(args)
+
+	switch strings.ToLower(action) {
+	case "":
+		checkIn, err := h.checkInRepo.GetByUser(ctx, userID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load check-in", "error", err)
+			return h.client.SendMessage(channelID, "Failed to load your check-in")
+		}
+		if checkIn == nil {
+			return h.client.SendMessage(channelID, fmt.Sprintf("You don't get a daily check-in. Use `@LinkedIn Ghostwriter checkin on` to get a DM at %s asking what you worked on.\n%s", h.checkInTime, checkInUsage))
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("You get a check-in DM every day at %s. Replies are saved as thoughts in <#%s>.\n%s", checkIn.Time, checkIn.ChannelID, checkInUsage))
+
+	case "on":
+		clock := h.checkInTime
+		if rest != "" {
+			var err error
+			if clock, err = checkin.ParseTime(rest); err != nil {
+				return h.client.SendMessage(channelID, checkInUsage)
+			}
+		}
+
+		if err := h.checkInRepo.Save(ctx, models.NewCheckIn(userID, channelID, clock)); err != nil {
+			slog.ErrorContext(ctx, "Failed to save check-in", "error", err)
+			return h.client.SendMessage(channelID, "Failed to turn on your check-in")
+		}
+
+		slog.InfoContext(ctx, "Check-in turned on", "user", userID, "time", clock)
+		return h.client.SendMessage(channelID, fmt.Sprintf("✅ I'll DM you every day at %s asking what you worked on. Reply there and I'll save it as a thought in this channel.", clock))
+
+	case "off":
+		err := h.checkInRepo.Delete(ctx, userID)
+		if errors.Is(err, database.ErrCheckInNotFound) {
+			return h.client.SendMessage(channelID, "Your daily check-in is already off.")
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to delete check-in", "error", err)
+			return h.client.SendMessage(channelID, "Failed to turn off your check-in")
+		}
+
+		slog.InfoContext(ctx, "Check-in turned off", "user", userID)
+		return h.client.SendMessage(channelID, "Your daily check-in is off.")
+	}
+
+	return h.client.SendMessage(channelID, checkInUsage)
+}
+
+// PromptCheckIn DMs the check-in question, with quick replies for the
+// categories most used in the channel the replies go to.
+func (h *CommandHandler) PromptCheckIn(ctx context.Context, checkIn *models.CheckIn) error {
+	categories, err := h.quickReplyCategories(ctx, checkIn.ChannelID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to pick check-in categories", "error", err)
+	}
+
+	// Posting to a user ID delivers the message in the bot's DM with them.
+	return h.client.SendMessageWithBlocks(checkIn.UserID, buildCheckInBlocks(categories))
+}
+
+// HandleCheckInAction acts on a check-in quick reply: a category files the
+// next reply under it, and "Nothing today" closes the prompt.
+func (h *CommandHandler) HandleCheckInAction(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) error {
+	userID := callback.User.ID
+
+	text := "👍 Nothing today, see you tomorrow."
+	category := ""
+	if action.ActionID != actionCheckInSkip {
+		category = action.Value
+		text = fmt.Sprintf("🗂️ Your next reply here is filed under *%s*. What did you work on?", category)
+	}
+
+	err := h.checkInRepo.SetPendingCategory(ctx, userID, category)
+	if errors.Is(err, database.ErrCheckInNotFound) {
+		text = "Your daily check-in is off. Use `@LinkedIn Ghostwriter checkin on` in a channel to turn it back on."
+	} else if err != nil {
+		return err
+	}
+
+	return h.client.UpdateMessageBlocks(callback.Channel.ID, callback.Message.Timestamp, []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+	})
+}
+
+// quickReplyCategories returns the categories with the most thoughts in
+// channelID lately, topped up from the category list.
+func (h *CommandHandler) quickReplyCategories(ctx context.Context, channelID string) ([]string, error) {
+	counts, err := h.thoughtRepo.CountByCategory(ctx, channelID, time.Now().AddDate(0, 0, -quickReplyDays), time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	all, err := h.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(all))
+	for _, category := range all {
+		names = append(names, category.Name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return counts[names[i]] > counts[names[j]]
+	})
+
+	if len(names) > quickReplyCategories {
+		names = names[:quickReplyCategories]
+	}
+	return names, nil
+}
+
+func buildCheckInBlocks(categories []string) []slack.Block {
+	question := "👋 *What did you work on today?*\nReply here and I'll save it as a thought. Pick a category first if you like."
+
+	var buttons []slack.BlockElement
+	for _, category := range categories {
+		buttons = append(buttons, slack.NewButtonBlockElement(actionCheckInCategory+category, category,
+			slack.NewTextBlockObject(slack.PlainTextType, category, false, false)))
+	}
+	buttons = append(buttons, slack.NewButtonBlockElement(actionCheckInSkip, "",
+		slack.NewTextBlockObject(slack.PlainTextType, "Nothing today", false, false)))
+
+	return []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, question, false, false), nil, nil),
+		slack.NewActionBlock("checkin_actions", buttons...),
+	}
+}
+
+// handleCheckInReply saves a DM from someone who opted in to the check-in
+// as a thought in their check-in channel. It returns false when userID
+// hasn't opted in, so the DM is handled like any other message.
+func (h *MessageHandler) handleCheckInReply(ctx context.Context, event *slackevents.MessageEvent) (bool, error) {
+	checkIn, err := h.checkInRepo.GetByUser(ctx, event.User)
+	if err != nil || checkIn == nil {
+		return false, err
+	}
+
+	thought := models.NewThought(event.Text, "slack")
+	thought.SlackChannelID = checkIn.ChannelID
+	thought.SlackThreadTS = event.TimeStamp
+	thought.Category = checkIn.PendingCategory
+
+	var files []slack.File
+	if event.Message != nil {
+		files = event.Message.Files
+	}
+
+	h.enrichThought(ctx, event.Text, h.sharedFiles(files), thought)
+	if strings.TrimSpace(thought.Content) == "" {
+		return true, nil
+	}
+
+	duplicate, err := h.captureThought(ctx, thought)
+	if err != nil {
+		return true, err
+	}
+	if duplicate != nil {
+		return true, h.client.SendMessage(event.Channel, duplicateMessage(duplicate))
+	}
+
+	if checkIn.PendingCategory != "" {
+		if err := h.checkInRepo.SetPendingCategory(ctx, checkIn.UserID, ""); err != nil {
+			slog.ErrorContext(ctx, "Failed to clear check-in category", "error", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "Check-in reply captured", "user", event.User, "thought_id", thought.ID, "category", thought.Category)
+	return true, h.client.SendMessage(event.Channel, fmt.Sprintf("Got it! Saved in <#%s> as *%s* | Tags: %s",
+		checkIn.ChannelID, thought.Category, strings.Join(thought.TopicTags, ", ")))
+}
@@ -1,24 +1,32 @@
 package slack
 
 import (
-	"log"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
 
+	"github.com/shubh-37/linkedin-ghostwriter/internal/outbound"
 	"github.com/slack-go/slack"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Client struct {
-	api    *slack.Client
-	botID  string
+	api   *slack.Client
+	botID string
 }
 
-func NewClient(token string) *Client {
-	api := slack.New(token)
-	
+func NewClient(token string, guard *outbound.Guard) *Client {
+	api := slack.New(token, slack.OptionHTTPClient(&http.Client{Transport: guard.Transport(otelhttp.NewTransport(http.DefaultTransport))}))
+
 	authTest, err := api.AuthTest()
 	if err != nil {
-		log.Fatalf("Failed to authenticate with Slack: %v", err)
+		slog.Error("Failed to authenticate with Slack", "error", err)
+		os.Exit(1)
 	}
-	
+
 	return &Client{
 		api:   api,
 		botID: authTest.UserID,
@@ -41,6 +49,24 @@ func (c *Client) SendMessage(channelID, message string) error {
 	return err
 }
 
+// SendEphemeral posts a message in channelID that only userID sees.
+func (c *Client) SendEphemeral(channelID, userID, message string) error {
+	_, err := c.api.PostEphemeral(
+		channelID,
+		userID,
+		slack.MsgOptionText(message, false),
+	)
+	return err
+}
+
+func (c *Client) SendMessageAndGetTS(channelID, message string) (string, error) {
+	_, timestamp, err := c.api.PostMessage(
+		channelID,
+		slack.MsgOptionText(message, false),
+	)
+	return timestamp, err
+}
+
 func (c *Client) SendMessageWithBlocks(channelID string, blocks []slack.Block) error {
 	_, _, err := c.api.PostMessage(
 		channelID,
@@ -54,11 +80,98 @@ func (c *Client) GetChannelHistory(channelID string, limit int) ([]slack.Message
 		ChannelID: channelID,
 		Limit:     limit,
 	}
-	
+
 	history, err := c.api.GetConversationHistory(params)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	return history.Messages, nil
-}
\ No newline at end of file
+}
+
+func (c *Client) SendThreadMessage(channelID, threadTS, message string) error {
+	_, _, err := c.api.PostMessage(
+		channelID,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionTS(threadTS),
+	)
+	return err
+}
+
+func (c *Client) SendThreadMessageAndGetTS(channelID, threadTS, message string) (string, error) {
+	_, timestamp, err := c.api.PostMessage(
+		channelID,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionTS(threadTS),
+	)
+	return timestamp, err
+}
+
+func (c *Client) UpdateMessage(channelID, timestamp, message string) error {
+	_, _, _, err := c.api.UpdateMessage(
+		channelID,
+		timestamp,
+		slack.MsgOptionText(message, false),
+	)
+	return err
+}
+
+func (c *Client) UpdateMessageBlocks(channelID, timestamp string, blocks []slack.Block) error {
+	_, _, _, err := c.api.UpdateMessage(
+		channelID,
+		timestamp,
+		slack.MsgOptionBlocks(blocks...),
+	)
+	return err
+}
+
+func (c *Client) SendBlocksAndGetTS(channelID string, blocks []slack.Block) (string, error) {
+	_, timestamp, err := c.api.PostMessage(
+		channelID,
+		slack.MsgOptionBlocks(blocks...),
+		slack.MsgOptionText("Generated LinkedIn post drafts", false),
+	)
+	return timestamp, err
+}
+
+// DownloadFile fetches a private file shared in Slack. It needs the
+// files:read scope.
+func (c *Client) DownloadFile(ctx context.Context, downloadURL string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.api.GetFileContext(ctx, downloadURL, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UploadFile shares content as a file in channelID. It needs the
+// files:write scope.
+func (c *Client) UploadFile(ctx context.Context, channelID, filename string, content []byte, comment string) error {
+	_, err := c.api.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+		Channel:        channelID,
+		Filename:       filename,
+		Title:          filename,
+		Reader:         bytes.NewReader(content),
+		FileSize:       len(content),
+		InitialComment: comment,
+	})
+	return err
+}
+
+func (c *Client) GetMessage(channelID, timestamp string) (*slack.Message, error) {
+	history, err := c.api.GetConversationHistory(&slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Latest:    timestamp,
+		Inclusive: true,
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history.Messages) == 0 {
+		return nil, fmt.Errorf("message not found")
+	}
+
+	return &history.Messages[0], nil
+}
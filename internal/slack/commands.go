@@ -2,13 +2,28 @@ package slack
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/images"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/importer"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/linear"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/linkedin"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/notion"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/prompts"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/recategorize"
+	"github.com/slack-go/slack"
 )
 
 type CommandHandler struct {
@@ -16,8 +31,42 @@ type CommandHandler struct {
 	thoughtRepo      *database.ThoughtRepository
 	postRepo         *database.PostRepository
 	brainstormRepo   *database.BrainstormRepository
+	styleRepo        *database.StyleRepository
+	workspaceRepo    *database.WorkspaceRepository
+	revisionRepo     *database.RevisionRepository
 	contentGenerator *agents.ContentGeneratorAgent
+	styleAnalyzer    *agents.StyleAnalyzerAgent
 	scheduler        *agents.SchedulerAgent
+	linkedinAuth     *linkedin.OAuthHandler
+	linearSyncer     *linear.Syncer
+	embeddings       *agents.EmbeddingAgent
+	carousels        *images.CarouselRenderer
+	notionSyncer     *notion.Syncer
+	publishTargets   []string
+	models           *agents.ModelRegistry
+	prompts          *prompts.Store
+	categoryRepo     *database.CategoryRepository
+	importer         *importer.Importer
+	linter           *agents.LinterAgent
+	factChecker      *agents.FactCheckerAgent
+	experimentRepo   *database.ExperimentRepository
+	contactRepo      *database.ContactRepository
+	recategorizer    *recategorize.Recategorizer
+	checkInRepo      *database.CheckInRepository
+	checkInTime      string
+	auditRepo        *database.AuditRepository
+	roles            *Roles
+	settings         *database.SettingsRepository
+	variations       int
+	language         string
+	emojiPolicy      string
+	postLength       models.PostLength
+	blackoutRepo     *database.BlackoutRepository
+	linkedinClient   *linkedin.Client
+	commentRepo      *database.CommentRepository
+	templateRepo     *database.TemplateRepository
+	usageRepo        *database.UsageRepository
+	usageTracker     *agents.UsageTracker
 }
 
 func NewCommandHandler(
@@ -25,20 +74,92 @@ func NewCommandHandler(
 	thoughtRepo *database.ThoughtRepository,
 	postRepo *database.PostRepository,
 	brainstormRepo *database.BrainstormRepository,
+	styleRepo *database.StyleRepository,
+	workspaceRepo *database.WorkspaceRepository,
+	revisionRepo *database.RevisionRepository,
 	contentGenerator *agents.ContentGeneratorAgent,
+	styleAnalyzer *agents.StyleAnalyzerAgent,
 	scheduler *agents.SchedulerAgent,
+	linkedinAuth *linkedin.OAuthHandler,
+	linearSyncer *linear.Syncer,
+	embeddings *agents.EmbeddingAgent,
+	carousels *images.CarouselRenderer,
+	notionSyncer *notion.Syncer,
+	publishTargets []string,
+	models *agents.ModelRegistry,
+	promptStore *prompts.Store,
+	categoryRepo *database.CategoryRepository,
+	thoughtImporter *importer.Importer,
+	linter *agents.LinterAgent,
+	factChecker *agents.FactCheckerAgent,
+	experimentRepo *database.ExperimentRepository,
+	contactRepo *database.ContactRepository,
+	recategorizer *recategorize.Recategorizer,
+	checkInRepo *database.CheckInRepository,
+	checkInTime string,
+	auditRepo *database.AuditRepository,
+	roles *Roles,
+	settings *database.SettingsRepository,
+	variations int,
+	language string,
+	emojiPolicy string,
+	postLength models.PostLength,
+	blackoutRepo *database.BlackoutRepository,
+	linkedinClient *linkedin.Client,
+	commentRepo *database.CommentRepository,
+	templateRepo *database.TemplateRepository,
+	usageRepo *database.UsageRepository,
+	usageTracker *agents.UsageTracker,
 ) *CommandHandler {
 	return &CommandHandler{
 		client:           client,
 		thoughtRepo:      thoughtRepo,
 		postRepo:         postRepo,
 		brainstormRepo:   brainstormRepo,
+		styleRepo:        styleRepo,
+		workspaceRepo:    workspaceRepo,
+		revisionRepo:     revisionRepo,
 		contentGenerator: contentGenerator,
+		styleAnalyzer:    styleAnalyzer,
 		scheduler:        scheduler,
+		linkedinAuth:     linkedinAuth,
+		linearSyncer:     linearSyncer,
+		embeddings:       embeddings,
+		carousels:        carousels,
+		notionSyncer:     notionSyncer,
+		publishTargets:   publishTargets,
+		models:           models,
+		prompts:          promptStore,
+		categoryRepo:     categoryRepo,
+		importer:         thoughtImporter,
+		linter:           linter,
+		factChecker:      factChecker,
+		experimentRepo:   experimentRepo,
+		contactRepo:      contactRepo,
+		recategorizer:    recategorizer,
+		checkInRepo:      checkInRepo,
+		checkInTime:      checkInTime,
+		auditRepo:        auditRepo,
+		roles:            roles,
+		settings:         settings,
+		variations:       variations,
+		language:         language,
+		emojiPolicy:      emojiPolicy,
+		postLength:       postLength,
+		blackoutRepo:     blackoutRepo,
+		linkedinClient:   linkedinClient,
+		commentRepo:      commentRepo,
+		templateRepo:     templateRepo,
+		usageRepo:        usageRepo,
+		usageTracker:     usageTracker,
 	}
 }
 
-func (h *CommandHandler) HandleSchedule(ctx context.Context, channelID string, args []string) error {
+func (h *CommandHandler) HandleSchedule(ctx context.Context, channelID, userID string, args []string) error {
+	if !h.roles.authorize(ctx, channelID, userID, models.RoleOwner, "schedule posts") {
+		return nil
+	}
+
 	postsPerDay := 2
 	if len(args) > 0 {
 		fmt.Sscanf(args[0], "%d", &postsPerDay)
@@ -52,14 +173,17 @@ func (h *CommandHandler) HandleSchedule(ctx context.Context, channelID string, a
 		PostsPerDay:    postsPerDay,
 		PreferredTimes: []string{},
 		StartDate:      time.Now().AddDate(0, 0, 1),
-		Timezone:       "Asia/Kolkata",
+		Timezone:       scheduleTimezone,
 	}
 
 	h.client.SendMessage(channelID, fmt.Sprintf("Scheduling approved posts... (%d posts per day)", postsPerDay))
 
 	scheduledCount, err := h.scheduler.ScheduleApprovedPosts(ctx, config)
 	if err != nil {
-		return h.client.SendMessage(channelID, "Failed to schedule posts. Please try again.")
+		slog.ErrorContext(ctx, "Failed to schedule posts", "error", err)
+		if scheduledCount == 0 {
+			return h.client.SendMessage(channelID, "Failed to schedule posts. Please try again.")
+		}
 	}
 
 	if scheduledCount == 0 {
@@ -68,11 +192,15 @@ func (h *CommandHandler) HandleSchedule(ctx context.Context, channelID string, a
 
 	schedule, err := h.scheduler.GetSchedule(ctx, 7)
 	if err != nil {
-		log.Printf("Failed to get schedule: %v", err)
+		slog.ErrorContext(ctx, "Failed to get schedule", "error", err)
 	}
 
 	message := fmt.Sprintf("*Scheduled %d posts!*\n\n", scheduledCount)
-	message += fmt.Sprintf("Posting %d times per day\n\n", postsPerDay)
+	if times, learned := h.scheduler.PostingTimes(ctx, postsPerDay, scheduleLocation()); learned {
+		message += fmt.Sprintf("Posting %d times per day at %s, when your posts have done best, on your best days each week\n\n", postsPerDay, strings.Join(times, ", "))
+	} else {
+		message += fmt.Sprintf("Posting %d times per day\n\n", postsPerDay)
+	}
 
 	if len(schedule) > 0 {
 		message += "*Upcoming Posts:*\n"
@@ -92,7 +220,19 @@ func (h *CommandHandler) HandleSchedule(ctx context.Context, channelID string, a
 				timeStr = post.ScheduledAt.Format("Jan 02 at 3:04 PM")
 			}
 
-			message += fmt.Sprintf("%d. %s\n   _%s_\n\n", i+1, timeStr, preview)
+			message += fmt.Sprintf("%d. %s\n   _%s_\n\n", i+1, timeStr, format.ToSlack(preview))
+		}
+	}
+
+	slots, err := h.scheduler.EventSlots(ctx, eventSlotDays, scheduleLocation())
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get event slots", "error", err)
+	}
+	if len(slots) > 0 {
+		message += "*Post after your events:*\n"
+		for _, slot := range slots {
+			message += fmt.Sprintf("• %s - post about it around %s: `reschedule [post #] %s`\n",
+				slot.Event.Summary, slot.At.Format("Mon Jan 02 at 3:04 PM"), slot.At.Format("2006-01-02 15:04"))
 		}
 	}
 
@@ -115,96 +255,576 @@ func (h *CommandHandler) HandleViewSchedule(ctx context.Context, channelID strin
 		return h.client.SendMessage(channelID, "No posts scheduled. Use `@LinkedIn Ghostwriter schedule` to schedule approved posts!")
 	}
 
-	message := fmt.Sprintf("*Posting Schedule* (Next %d days)\n\n", days)
+	return h.client.SendMessageWithBlocks(channelID, buildCalendarBlocks(schedule, days, scheduleLocation()))
+}
+
+// HandleScheduleMenu acts on an option picked from a calendar post's
+// overflow menu.
+func (h *CommandHandler) HandleScheduleMenu(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) error {
+	menuAction, postID, days, err := parseScheduleMenuValue(action.SelectedOption.Value)
+	if err != nil {
+		return err
+	}
+
+	channelID := callback.Channel.ID
+	messageTS := callback.Message.Timestamp
+
+	switch menuAction {
+	case scheduleMenuPreview:
+		post, err := h.postRepo.GetByID(ctx, postID)
+		if err != nil {
+			return err
+		}
+		return h.client.SendThreadMessage(channelID, messageTS, format.ToSlack(post.Content))
+
+	case scheduleMenuReschedule:
+		post, err := h.postRepo.GetByID(ctx, postID)
+		if err != nil {
+			return err
+		}
 
-	for i, post := range schedule {
-		preview := post.Content
-		if len(preview) > 80 {
-			preview = preview[:80] + "..."
+		modal, err := buildRescheduleModal(post, rescheduleMetadata{
+			PostID:    postID,
+			ChannelID: channelID,
+			MessageTS: messageTS,
+			Days:      days,
+		})
+		if err != nil {
+			return err
 		}
 
-		timeStr := "unknown"
-		if post.ScheduledAt != nil {
-			timeStr = post.ScheduledAt.Format("Jan 02 at 3:04 PM")
+		_, err = h.client.GetAPI().OpenView(callback.TriggerID, modal)
+		return err
+
+	case scheduleMenuUnschedule:
+		if err := h.scheduler.CancelSchedule(ctx, postID); err != nil {
+			return err
 		}
 
-		message += fmt.Sprintf("*%d. %s*\n%s\n\n", i+1, timeStr, preview)
+		h.refreshCalendar(ctx, channelID, messageTS, days)
+		return h.client.SendThreadMessage(channelID, messageTS,
+			fmt.Sprintf("<@%s> unscheduled a post. It's back in the approved queue.", callback.User.ID))
 	}
 
-	message += fmt.Sprintf("\n_Total: %d scheduled posts_", len(schedule))
+	return fmt.Errorf("unknown schedule menu action: %s", menuAction)
+}
 
-	return h.client.SendMessage(channelID, message)
+func (h *CommandHandler) HandleRescheduleSubmission(ctx context.Context, callback *slack.InteractionCallback) error {
+	var metadata rescheduleMetadata
+	if err := json.Unmarshal([]byte(callback.View.PrivateMetadata), &metadata); err != nil {
+		return fmt.Errorf("failed to parse modal metadata: %w", err)
+	}
+
+	// Submissions skip the check buttons go through, so the role is
+	// checked again here.
+	if !h.roles.authorize(ctx, metadata.ChannelID, callback.User.ID, models.RoleOwner, "reschedule posts") {
+		return nil
+	}
+
+	selected := callback.View.State.Values[rescheduleBlockID][rescheduleInputID].SelectedDateTime
+	if selected == 0 {
+		return fmt.Errorf("no date and time selected")
+	}
+
+	newTime := time.Unix(selected, 0).In(scheduleLocation())
+	if newTime.Before(time.Now()) {
+		return h.client.SendThreadMessage(metadata.ChannelID, metadata.MessageTS, "That time is in the past. Pick a future date and time.")
+	}
+
+	if err := h.scheduler.ReschedulePost(ctx, metadata.PostID, newTime); err != nil {
+		return err
+	}
+
+	h.refreshCalendar(ctx, metadata.ChannelID, metadata.MessageTS, metadata.Days)
+	return h.client.SendThreadMessage(metadata.ChannelID, metadata.MessageTS,
+		fmt.Sprintf("<@%s> moved a post to %s.", callback.User.ID, newTime.Format("Mon Jan 02 at 3:04 PM")))
 }
 
-func (h *CommandHandler) HandleGenerateDraft(ctx context.Context, channelID string, category string) (string, []string, error) {
-	var thoughts []*models.Thought
+func (h *CommandHandler) refreshCalendar(ctx context.Context, channelID, messageTS string, days int) {
+	schedule, err := h.scheduler.GetSchedule(ctx, days)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to refresh calendar", "error", err)
+		return
+	}
+
+	if err := h.client.UpdateMessageBlocks(channelID, messageTS, buildCalendarBlocks(schedule, days, scheduleLocation())); err != nil {
+		slog.ErrorContext(ctx, "Failed to refresh calendar", "error", err)
+	}
+}
+
+func scheduleLocation() *time.Location {
+	location, err := time.LoadLocation(scheduleTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+const scheduleTimezone = "Asia/Kolkata"
+
+// eventSlotDays is how far ahead `schedule` looks for talks and events on
+// the calendar worth posting about.
+const eventSlotDays = 14
+
+func (h *CommandHandler) HandleReschedule(ctx context.Context, channelID, userID string, args []string) error {
+	if !h.roles.authorize(ctx, channelID, userID, models.RoleOwner, "reschedule posts") {
+		return nil
+	}
+
+	usage := "Usage: `@LinkedIn Ghostwriter reschedule [post #] [date time]`, e.g. `reschedule 2 2026-03-14 09:30`, `reschedule 2 tomorrow 15:00` or `reschedule 2 fri 09:00`"
+
+	if len(args) < 2 {
+		return h.client.SendMessage(channelID, usage)
+	}
+
+	post, number, err := h.scheduledPostByNumber(ctx, channelID, args[0])
+	if err != nil || post == nil {
+		return err
+	}
+
+	location := scheduleLocation()
+
+	newTime, err := parseScheduleTime(strings.Join(args[1:], " "), time.Now().In(location), location)
+	if err != nil {
+		return h.client.SendMessage(channelID, fmt.Sprintf("%v\n\n%s", err, usage))
+	}
+
+	if newTime.Before(time.Now()) {
+		return h.client.SendMessage(channelID, "That time is in the past. Pick a future date and time.")
+	}
+
+	if err := h.scheduler.ReschedulePost(ctx, post.ID, newTime); err != nil {
+		slog.ErrorContext(ctx, "Failed to reschedule post", "post_id", post.ID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to reschedule post")
+	}
+
+	return h.client.SendMessage(channelID, fmt.Sprintf("Moved post %d to %s.", number, newTime.Format("Mon Jan 02 at 3:04 PM")))
+}
+
+func (h *CommandHandler) HandleUnschedule(ctx context.Context, channelID, userID string, args []string) error {
+	if !h.roles.authorize(ctx, channelID, userID, models.RoleOwner, "unschedule posts") {
+		return nil
+	}
+
+	if len(args) < 1 {
+		return h.client.SendMessage(channelID, "Usage: `@LinkedIn Ghostwriter unschedule [post #]`")
+	}
+
+	post, number, err := h.scheduledPostByNumber(ctx, channelID, args[0])
+	if err != nil || post == nil {
+		return err
+	}
+
+	if err := h.scheduler.CancelSchedule(ctx, post.ID); err != nil {
+		slog.ErrorContext(ctx, "Failed to unschedule post", "post_id", post.ID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to unschedule post")
+	}
+
+	return h.client.SendMessage(channelID, fmt.Sprintf("Unscheduled post %d. It's back in the approved queue for the next `schedule`.", number))
+}
+
+// scheduledPostByNumber resolves a post number from `view schedule`. It
+// returns a nil post after telling the user when the number is invalid.
+func (h *CommandHandler) scheduledPostByNumber(ctx context.Context, channelID, arg string) (*models.Post, int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || number < 1 {
+		return nil, 0, h.client.SendMessage(channelID, fmt.Sprintf("'%s' is not a post number. Use `@LinkedIn Ghostwriter view schedule` to see them.", arg))
+	}
+
+	upcoming, err := h.scheduler.GetUpcoming(ctx)
+	if err != nil {
+		return nil, 0, h.client.SendMessage(channelID, "Failed to fetch schedule")
+	}
+
+	if number > len(upcoming) {
+		return nil, 0, h.client.SendMessage(channelID, fmt.Sprintf("Post %d not found. Use `@LinkedIn Ghostwriter view schedule` to see scheduled posts.", number))
+	}
+
+	return upcoming[number-1], number, nil
+}
+
+var scheduleDateLayouts = []string{"2006-01-02", "01/02/2006", "Jan 2 2006", "Jan 2"}
+
+var scheduleClockLayouts = []string{"15:04", "3:04pm", "3pm"}
+
+// parseScheduleTime accepts "<date> <time>" where the date is an ISO or
+// short date, "today", "tomorrow" or a weekday name (the next one after
+// today), and the time is 24-hour or am/pm.
+func parseScheduleTime(input string, now time.Time, location *time.Location) (time.Time, error) {
+	fields := strings.Fields(strings.ToLower(input))
+	if len(fields) < 2 {
+		return time.Time{}, fmt.Errorf("I need both a date and a time")
+	}
+
+	clockText := fields[len(fields)-1]
+	dateText := strings.Join(fields[:len(fields)-1], " ")
+
+	var clock time.Time
 	var err error
+	for _, layout := range scheduleClockLayouts {
+		if clock, err = time.Parse(layout, clockText); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("I couldn't read the time '%s'", clockText)
+	}
 
-	if category != "" && category != "all" {
-		thoughts, err = h.thoughtRepo.GetByCategory(ctx, category)
-	} else {
-		thoughts, err = h.thoughtRepo.GetByStatus(ctx, "raw")
+	date, err := parseScheduleDate(dateText, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, location), nil
+}
+
+func parseScheduleDate(text string, now time.Time) (time.Time, error) {
+	switch text {
+	case "today":
+		return now, nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), nil
+	}
+
+	for day := 1; day <= 7; day++ {
+		candidate := now.AddDate(0, 0, day)
+		name := strings.ToLower(candidate.Weekday().String())
+		if text == name || text == name[:3] {
+			return candidate, nil
+		}
+	}
+
+	for _, layout := range scheduleDateLayouts {
+		date, err := time.Parse(layout, text)
+		if err != nil {
+			continue
+		}
+		if date.Year() == 0 {
+			date = date.AddDate(now.Year(), 0, 0)
+		}
+		return date, nil
+	}
+
+	return time.Time{}, fmt.Errorf("I couldn't read the date '%s'", text)
+}
+
+// generationCandidates caps how many thoughts are loaded when picking what
+// to generate from; only the first few are ever used.
+const generationCandidates = 20
+
+// fewShotExamples is how many top-performing posts are shown to the model
+// when generating.
+const fewShotExamples = 3
+
+// ErrNoThoughts is returned by the generate handlers after they have already
+// told the user there was nothing to generate from.
+var ErrNoThoughts = errors.New("no thoughts found")
+
+// ErrNoBrainstorm is returned by HandleDevelop after it has already told the
+// user why there was nothing to develop.
+var ErrNoBrainstorm = errors.New("no brainstorm angle to develop")
+
+func (h *CommandHandler) HandleGenerateDraft(ctx context.Context, channelID, userID, topic string) ([]slack.Block, []string, error) {
+	selectedThoughts, err := h.generationThoughts(ctx, channelID, topic)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	// A job that runs again keeps the drafts it already wrote and only
+	// writes the variations still missing.
+	posts, err := h.jobDrafts(ctx)
 	if err != nil {
-		h.client.SendMessage(channelID, "Failed to fetch thoughts")
-		return "", nil, err
+		return nil, nil, err
+	}
+	if missing := h.variationCount(ctx) - len(posts); missing > 0 {
+		written, err := h.writeDrafts(ctx, channelID, userID, selectedThoughts, missing)
+		if err != nil {
+			return nil, nil, err
+		}
+		posts = append(posts, written...)
 	}
 
-	if len(thoughts) == 0 {
-		h.client.SendMessage(channelID, "No thoughts found to generate posts from. Share some thoughts first!")
-		return "", nil, fmt.Errorf("no thoughts found")
+	postIDs := make([]string, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
 	}
 
-	selectedThoughts := thoughts
-	if len(thoughts) > 3 {
-		selectedThoughts = thoughts[:3]
+	header := fmt.Sprintf("*Generated LinkedIn Post Drafts*\n_Based on %d recent thought(s)_", len(selectedThoughts))
+
+	return buildDraftBlocks(header, posts, h.publishTargets), postIDs, nil
+}
+
+// writeDrafts writes and saves count variations from selectedThoughts.
+func (h *CommandHandler) writeDrafts(ctx context.Context, channelID, userID string, selectedThoughts []*models.Thought, count int) ([]*models.Post, error) {
+	ctx, progress := h.startProgress(ctx, channelID, "", "Generating LinkedIn post drafts... This may take a moment.")
+
+	ctx, language := h.withPostLanguage(h.withPostLength(h.withEmojiPolicy(ctx, userID)), userID)
+
+	profile, err := h.styleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load style profile", "error", err)
 	}
+	userStyle := agents.FormatStyleGuide(profile)
 
-	h.client.SendMessage(channelID, "Generating LinkedIn post drafts... This may take a moment.")
+	examples, err := h.postRepo.GetTopPerforming(ctx, fewShotExamples)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load top performing posts", "error", err)
+	}
 
-	variations, err := h.contentGenerator.GeneratePost(ctx, selectedThoughts, "")
+	variations, err := h.contentGenerator.GeneratePost(ctx, selectedThoughts, userStyle, examples, count)
 	if err != nil {
-		h.client.SendMessage(channelID, "Failed to generate post. Please try again.")
-		return "", nil, err
+		progress.finish(llmErrorMessage(err, "Failed to generate post. Please try again."))
+		return nil, err
 	}
 
-	var postIDs []string
+	var posts []*models.Post
 	for _, variation := range variations {
 		thoughtIDs := make([]string, len(selectedThoughts))
 		for j, t := range selectedThoughts {
 			thoughtIDs[j] = t.ID
 		}
 
-		post := models.NewPost(variation, thoughtIDs, "insight", "professional")
+		post := models.NewPost(variation.Content, thoughtIDs, "insight", "professional")
 		post.Status = "draft"
+		post.Language = language
+		post.Provenance = models.ProvenanceOf(selectedThoughts)
+		post.FirstComment = variation.FirstComment
+		post.JobID = generateJobID(ctx)
+		h.checkFacts(ctx, post, selectedThoughts)
 
 		if err := h.postRepo.Create(ctx, post); err != nil {
 			continue
 		}
+		h.recordRevision(ctx, post, models.RevisionGeneration, userID, "")
 
-		postIDs = append(postIDs, post.ID)
+		posts = append(posts, post)
+	}
+
+	if len(posts) == 0 {
+		progress.finish("Failed to save generated drafts. Please try again.")
+		return nil, fmt.Errorf("no drafts saved")
 	}
+	progress.done(fmt.Sprintf("Wrote %d draft(s).", len(posts)))
 
-	message := "*Generated LinkedIn Post Drafts*\n\n"
-	message += fmt.Sprintf("_Based on %d recent thought(s)_\n\n", len(selectedThoughts))
+	return posts, nil
+}
 
-	for i, variation := range variations {
-		message += "━━━━━━━━━━━━━━━━━━\n"
-		message += fmt.Sprintf("*Variation %d:*\n\n", i+1)
-		message += variation + "\n\n"
+// HandleGenerateCarousel writes a carousel draft from the same thoughts
+// generate would use and renders its slides to a PDF.
+func (h *CommandHandler) HandleGenerateCarousel(ctx context.Context, channelID, userID, topic string) ([]slack.Block, []string, error) {
+	selectedThoughts, err := h.generationThoughts(ctx, channelID, topic)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	message += "━━━━━━━━━━━━━━━━━━\n\n"
-	message += "*To approve a specific variation:*\n"
-	message += "React with:\n"
-	message += "• 1️⃣ to approve Variation 1\n"
-	message += "• 2️⃣ to approve Variation 2\n"
-	message += "• 3️⃣ to approve Variation 3\n"
-	message += "• ✅ to approve ALL variations\n"
-	message += "• ❌ to reject all\n"
+	existing, err := h.jobDrafts(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(existing) > 0 {
+		return h.carouselBlocks(ctx, channelID, existing[0], len(selectedThoughts))
+	}
+
+	h.client.SendMessage(channelID, "Generating a carousel draft... This may take a moment.")
+
+	ctx, language := h.withPostLanguage(h.withEmojiPolicy(ctx, userID), userID)
+
+	profile, err := h.styleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load style profile", "error", err)
+	}
+
+	carousel, err := h.contentGenerator.GenerateCarousel(ctx, selectedThoughts, agents.FormatStyleGuide(profile))
+	if err != nil {
+		h.client.SendMessage(channelID, llmErrorMessage(err, "Failed to generate carousel. Please try again."))
+		return nil, nil, err
+	}
+
+	thoughtIDs := make([]string, len(selectedThoughts))
+	for i, t := range selectedThoughts {
+		thoughtIDs[i] = t.ID
+	}
+
+	post := models.NewPost(carousel.Caption, thoughtIDs, models.PostTypeCarousel, "professional")
+	post.Slides = carousel.Slides
+	post.Language = language
+	post.Provenance = models.ProvenanceOf(selectedThoughts)
+	post.JobID = generateJobID(ctx)
+	h.checkFacts(ctx, post, selectedThoughts)
+
+	if err := h.postRepo.Create(ctx, post); err != nil {
+		h.client.SendMessage(channelID, "Failed to save carousel draft. Please try again.")
+		return nil, nil, err
+	}
+	h.recordRevision(ctx, post, models.RevisionGeneration, userID, "")
+
+	return h.carouselBlocks(ctx, channelID, post, len(selectedThoughts))
+}
+
+// carouselBlocks renders the slides of a carousel draft to a PDF, unless
+// they already were, and lays the draft out for review.
+func (h *CommandHandler) carouselBlocks(ctx context.Context, channelID string, post *models.Post, thoughtCount int) ([]slack.Block, []string, error) {
+	if post.DocumentPath == "" {
+		path, err := h.carousels.Render(post.ID, post.Slides)
+		if err != nil {
+			h.client.SendMessage(channelID, "Failed to render the carousel PDF. Please try again.")
+			return nil, nil, err
+		}
+		if err := h.postRepo.SetDocument(ctx, post.ID, path); err != nil {
+			h.client.SendMessage(channelID, "Failed to save the carousel PDF. Please try again.")
+			return nil, nil, err
+		}
+		post.DocumentPath = path
+	}
+
+	header := fmt.Sprintf("*Generated Carousel Draft*\n_%d slides, based on %d recent thought(s)_", len(post.Slides), thoughtCount)
+
+	return buildDraftBlocks(header, []*models.Post{post}, h.publishTargets), []string{post.ID}, nil
+}
+
+// HandleGeneratePoll writes a poll draft from the same thoughts generate
+// would use.
+func (h *CommandHandler) HandleGeneratePoll(ctx context.Context, channelID, userID, topic string) ([]slack.Block, []string, error) {
+	selectedThoughts, err := h.generationThoughts(ctx, channelID, topic)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := fmt.Sprintf("*Generated Poll Draft*\n_Based on %d recent thought(s)_", len(selectedThoughts))
+
+	existing, err := h.jobDrafts(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(existing) > 0 {
+		return buildDraftBlocks(header, existing[:1], h.publishTargets), []string{existing[0].ID}, nil
+	}
+
+	h.client.SendMessage(channelID, "Generating a poll draft... This may take a moment.")
+
+	ctx, language := h.withPostLanguage(h.withEmojiPolicy(ctx, userID), userID)
+
+	profile, err := h.styleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load style profile", "error", err)
+	}
+
+	caption, poll, err := h.contentGenerator.GeneratePoll(ctx, selectedThoughts, agents.FormatStyleGuide(profile))
+	if err != nil {
+		h.client.SendMessage(channelID, llmErrorMessage(err, "Failed to generate poll. Please try again."))
+		return nil, nil, err
+	}
+
+	thoughtIDs := make([]string, len(selectedThoughts))
+	for i, t := range selectedThoughts {
+		thoughtIDs[i] = t.ID
+	}
+
+	post := models.NewPost(caption, thoughtIDs, models.PostTypePoll, "professional")
+	post.Poll = poll
+	post.Language = language
+	post.Provenance = models.ProvenanceOf(selectedThoughts)
+	post.JobID = generateJobID(ctx)
+	h.checkFacts(ctx, post, selectedThoughts)
+
+	if err := h.postRepo.Create(ctx, post); err != nil {
+		h.client.SendMessage(channelID, "Failed to save poll draft. Please try again.")
+		return nil, nil, err
+	}
+	h.recordRevision(ctx, post, models.RevisionGeneration, userID, "")
+
+	return buildDraftBlocks(header, []*models.Post{post}, h.publishTargets), []string{post.ID}, nil
+}
+
+// generationThoughts picks the thoughts to write from, either about topic
+// or the newest unused ones. When there are none it tells the user and
+// returns ErrNoThoughts.
+func (h *CommandHandler) generationThoughts(ctx context.Context, channelID, topic string) ([]*models.Thought, error) {
+	var thoughts []*models.Thought
+	var err error
+
+	if topic != "" && topic != "all" {
+		thoughts, err = h.findTopicThoughts(ctx, channelID, topic)
+	} else {
+		thoughts, err = h.thoughtRepo.GetUnused(ctx, channelID, generationCandidates)
+	}
+
+	if err != nil {
+		h.client.SendMessage(channelID, "Failed to fetch thoughts")
+		return nil, err
+	}
 
-	return message, postIDs, nil
+	if len(thoughts) == 0 {
+		if topic != "" && topic != "all" {
+			offerMsg := fmt.Sprintf("I don't have any thoughts about '%s' yet.\n\n", topic)
+			offerMsg += "Would you like me to brainstorm ideas on this topic?\n\n"
+			offerMsg += fmt.Sprintf("Use: `@LinkedIn Ghostwriter brainstorm %s`", topic)
+			h.client.SendMessage(channelID, offerMsg)
+		} else {
+			h.client.SendMessage(channelID, "No thoughts found to generate posts from. Share some thoughts first!")
+		}
+		return nil, ErrNoThoughts
+	}
+
+	return h.selectThoughts(ctx, channelID, thoughts, 3), nil
+}
+
+// Semantic matches for a topic below this similarity are too loose to
+// generate from.
+const minTopicSimilarity = 0.5
+
+// findTopicThoughts matches a topic by category, tag or keyword and falls
+// back to semantic similarity when embeddings are enabled.
+func (h *CommandHandler) findTopicThoughts(ctx context.Context, channelID, topic string) ([]*models.Thought, error) {
+	thoughts, err := h.thoughtRepo.SearchByTagOrKeyword(ctx, channelID, topic, generationCandidates)
+	if err != nil || len(thoughts) > 0 || h.embeddings == nil {
+		return thoughts, err
+	}
+
+	similar, err := h.embeddings.SimilarTo(ctx, channelID, topic, 10)
+	if err != nil {
+		slog.ErrorContext(ctx, "Semantic topic match failed", "error", err)
+		return nil, nil
+	}
+
+	for _, match := range similar {
+		if match.Similarity >= minTopicSimilarity {
+			thoughts = append(thoughts, match.Thought)
+		}
+	}
+
+	return thoughts, nil
+}
+
+// selectThoughts starts from the newest thought and fills the rest with its
+// semantically related thoughts, falling back to the next newest ones.
+func (h *CommandHandler) selectThoughts(ctx context.Context, channelID string, thoughts []*models.Thought, limit int) []*models.Thought {
+	if len(thoughts) <= 1 {
+		return thoughts
+	}
+
+	selected := []*models.Thought{thoughts[0]}
+	seen := map[string]bool{thoughts[0].ID: true}
+
+	related, err := h.thoughtRepo.GetByIDs(ctx, channelID, thoughts[0].RelatedThoughts)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load related thoughts", "error", err)
+	}
+
+	for _, candidates := range [][]*models.Thought{related, thoughts[1:]} {
+		for _, t := range candidates {
+			if len(selected) >= limit {
+				return selected
+			}
+			if seen[t.ID] || t.Status != thoughts[0].Status {
+				continue
+			}
+			seen[t.ID] = true
+			selected = append(selected, t)
+		}
+	}
+
+	return selected
 }
 
 func (h *CommandHandler) HandleBrainstorm(ctx context.Context, channelID, topic string) error {
@@ -214,7 +834,7 @@ func (h *CommandHandler) HandleBrainstorm(ctx context.Context, channelID, topic
 
 	brainstormContent, angles, err := h.contentGenerator.GenerateBrainstorm(ctx, thought)
 	if err != nil {
-		return h.client.SendMessage(channelID, "Failed to generate brainstorm. Please try again.")
+		return h.client.SendMessage(channelID, llmErrorMessage(err, "Failed to generate brainstorm. Please try again."))
 	}
 
 	session := models.NewBrainstormSession(topic, []string{})
@@ -222,7 +842,7 @@ func (h *CommandHandler) HandleBrainstorm(ctx context.Context, channelID, topic
 	session.KeyAngles = angles
 
 	if err := h.brainstormRepo.Create(ctx, session); err != nil {
-		log.Printf("Failed to save brainstorm: %v", err)
+		slog.ErrorContext(ctx, "Failed to save brainstorm", "error", err)
 	}
 
 	message := "*Brainstorm Session*\n\n"
@@ -234,46 +854,1120 @@ func (h *CommandHandler) HandleBrainstorm(ctx context.Context, channelID, topic
 	for i, angle := range angles {
 		message += fmt.Sprintf("%d. %s\n", i+1, angle)
 	}
-	message += "\nAdd more context and use `@LinkedIn Ghostwriter generate` when ready!"
+	message += "\nReply in this thread with `@LinkedIn Ghostwriter develop [angle #]` to turn an angle into drafts."
 
-	return h.client.SendMessage(channelID, message)
+	messageTS, err := h.client.SendMessageAndGetTS(channelID, message)
+	if err != nil {
+		return err
+	}
+
+	if err := h.brainstormRepo.SetSlackMessage(ctx, session.ID, channelID, messageTS); err != nil {
+		slog.ErrorContext(ctx, "Failed to link brainstorm to its message", "session_id", session.ID, "error", err)
+	}
+
+	return nil
 }
 
-func (h *CommandHandler) HandleListDrafts(ctx context.Context, channelID string) error {
-	drafts, err := h.postRepo.GetByStatus(ctx, "draft")
+// HandleDevelop turns one of the angles of the brainstorm session posted as
+// threadTS into draft variations linked back to that session.
+func (h *CommandHandler) HandleDevelop(ctx context.Context, channelID, threadTS, userID, angleNumber string) ([]slack.Block, []string, error) {
+	usage := "Usage: reply in a brainstorm thread with `@LinkedIn Ghostwriter develop [angle #]`"
+
+	if threadTS == "" {
+		h.client.SendMessage(channelID, usage)
+		return nil, nil, ErrNoBrainstorm
+	}
+
+	session, err := h.brainstormRepo.GetBySlackThread(ctx, channelID, threadTS)
 	if err != nil {
-		return h.client.SendMessage(channelID, "Failed to fetch drafts")
+		h.client.SendThreadMessage(channelID, threadTS, "Failed to load the brainstorm session")
+		return nil, nil, err
+	}
+	if session == nil {
+		h.client.SendThreadMessage(channelID, threadTS, "This thread isn't a brainstorm session. Start one with `@LinkedIn Ghostwriter brainstorm [topic]`.")
+		return nil, nil, ErrNoBrainstorm
 	}
 
-	if len(drafts) == 0 {
-		return h.client.SendMessage(channelID, "No pending drafts. Use `@LinkedIn Ghostwriter generate` to create some!")
+	index, err := strconv.Atoi(angleNumber)
+	if err != nil || index < 1 || index > len(session.KeyAngles) {
+		h.client.SendThreadMessage(channelID, threadTS, fmt.Sprintf("Pick an angle between 1 and %d. %s", len(session.KeyAngles), usage))
+		return nil, nil, ErrNoBrainstorm
 	}
+	angle := session.KeyAngles[index-1]
 
-	message := fmt.Sprintf("*Pending Drafts* (%d)\n\n", len(drafts))
+	ctx, progress := h.startProgress(ctx, channelID, threadTS, fmt.Sprintf("Developing angle %d into drafts... This may take a moment.", index))
 
-	for i, draft := range drafts {
-		preview := draft.Content
-		if len(preview) > 100 {
-			preview = preview[:100] + "..."
-		}
+	ctx, language := h.withPostLanguage(h.withPostLength(h.withEmojiPolicy(ctx, userID)), userID)
 
-		message += fmt.Sprintf("*Draft %d:*\n%s\n\n", i+1, preview)
+	profile, err := h.styleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load style profile", "error", err)
+	}
 
-		if i >= 4 {
-			message += fmt.Sprintf("_...and %d more_\n", len(drafts)-5)
-			break
-		}
+	examples, err := h.postRepo.GetTopPerforming(ctx, fewShotExamples)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load top performing posts", "error", err)
 	}
 
-	return h.client.SendMessage(channelID, message)
-}
+	variations, err := h.contentGenerator.DevelopAngle(ctx, session.Topic, angle, session.BrainstormContent, agents.FormatStyleGuide(profile), examples, h.variationCount(ctx))
+	if err != nil {
+		progress.finish(llmErrorMessage(err, "Failed to develop the angle. Please try again."))
+		return nil, nil, err
+	}
 
-func (h *CommandHandler) HandleLinearSync(ctx context.Context, channelID string) error {
-	h.client.SendMessage(channelID, "Syncing with Linear...")
+	sourceThoughts, err := h.thoughtRepo.GetByIDs(ctx, channelID, session.ThoughtIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load brainstorm thoughts", "error", err)
+	}
 
-	message := "Linear sync completed!\n\n"
-	message += "Recent completed tasks have been captured as thoughts.\n"
-	message += "Use `@LinkedIn Ghostwriter generate` to create posts from them."
+	var posts []*models.Post
+	var postIDs []string
+	for _, variation := range variations {
+		post := models.NewPost(variation.Content, session.ThoughtIDs, "insight", "professional")
+		post.Status = "draft"
+		post.Language = language
+		post.Provenance = models.ProvenanceOf(sourceThoughts)
+		post.FirstComment = variation.FirstComment
+		h.checkFacts(ctx, post, sourceThoughts)
+		post.BrainstormSessionID = &session.ID
+
+		if err := h.postRepo.Create(ctx, post); err != nil {
+			slog.ErrorContext(ctx, "Failed to save developed draft", "error", err)
+			continue
+		}
+		h.recordRevision(ctx, post, models.RevisionGeneration, userID, "brainstorm angle "+angleNumber)
+
+		posts = append(posts, post)
+		postIDs = append(postIDs, post.ID)
+	}
+
+	if len(posts) == 0 {
+		progress.finish("Failed to save generated drafts. Please try again.")
+		return nil, nil, fmt.Errorf("no drafts saved")
+	}
+	progress.done(fmt.Sprintf("Developed angle %d into %d draft(s), shared in the channel.", index, len(posts)))
+
+	session.Status = "developed"
+	if err := h.brainstormRepo.Update(ctx, session); err != nil {
+		slog.ErrorContext(ctx, "Failed to update brainstorm session", "session_id", session.ID, "error", err)
+	}
+
+	header := fmt.Sprintf("*Drafts from Brainstorm: %s*\n_Angle %d: %s_", session.Topic, index, angle)
+
+	return buildDraftBlocks(header, posts, h.publishTargets), postIDs, nil
+}
+
+func (h *CommandHandler) HandleListDrafts(ctx context.Context, channelID string) error {
+	drafts, err := h.postRepo.GetByStatus(ctx, "draft")
+	if err != nil {
+		return h.client.SendMessage(channelID, "Failed to fetch drafts")
+	}
+
+	if len(drafts) == 0 {
+		return h.client.SendMessage(channelID, "No pending drafts. Use `@LinkedIn Ghostwriter generate` to create some!")
+	}
+
+	message := fmt.Sprintf("*Pending Drafts* (%d)\n\n", len(drafts))
+
+	for i, draft := range drafts {
+		preview := draft.Content
+		if len(preview) > 100 {
+			preview = preview[:100] + "..."
+		}
+
+		message += fmt.Sprintf("*Draft %d:*\n%s\n\n", i+1, format.ToSlack(preview))
+
+		if i >= 4 {
+			message += fmt.Sprintf("_...and %d more_\n", len(drafts)-5)
+			break
+		}
+	}
 
 	return h.client.SendMessage(channelID, message)
-}
\ No newline at end of file
+}
+
+func (h *CommandHandler) HandleLinearSync(ctx context.Context, channelID string, days int) error {
+	if h.linearSyncer == nil {
+		return h.client.SendMessage(channelID, "Linear is not configured. Add LINEAR_API_KEY to .env")
+	}
+
+	h.client.SendMessage(channelID, fmt.Sprintf("Syncing issues completed in Linear over the last %d days...", days))
+
+	result, err := h.linearSyncer.SyncCompleted(ctx, channelID, days)
+	if err != nil {
+		slog.ErrorContext(ctx, "Linear sync failed", "error", err)
+		return h.client.SendMessage(channelID, "Failed to sync with Linear")
+	}
+
+	filterNote := ""
+	if filter := h.linearSyncer.Filter(); !filter.IsZero() {
+		filterNote = fmt.Sprintf("\nOnly issues matching the filter (%s) were fetched.", filter)
+	}
+
+	if result.Created == 0 {
+		message := fmt.Sprintf("Linear sync completed - no new tasks found (%d already captured).", result.Skipped)
+		if result.Failed > 0 {
+			message += fmt.Sprintf(" %d issues failed to import.", result.Failed)
+		}
+		return h.client.SendMessage(channelID, message+filterNote)
+	}
+
+	message := "Linear sync completed!\n\n"
+	message += fmt.Sprintf("Captured %d new thoughts from %d completed issues", result.Created, result.Fetched)
+	if result.Skipped > 0 {
+		message += fmt.Sprintf(" (%d already captured)", result.Skipped)
+	}
+	message += ".\n"
+	if result.Failed > 0 {
+		message += fmt.Sprintf("%d issues failed to import.\n", result.Failed)
+	}
+	message += "Use `@LinkedIn Ghostwriter generate` to create posts from them."
+
+	return h.client.SendMessage(channelID, message+filterNote)
+}
+
+const linearFilterUsage = "Usage: `@LinkedIn Ghostwriter linear filter [teams|projects|labels] [name, name...]`, `linear filter estimate [points]`, or `linear filter reset`"
+
+// HandleLinearFilter shows which completed Linear issues become thoughts, or
+// changes one part of the filter. A part given without a value is cleared,
+// and "reset" goes back to the configured filter.
+func (h *CommandHandler) HandleLinearFilter(ctx context.Context, channelID, userID, args string) error {
+	if h.linearSyncer == nil {
+		return h.client.SendMessage(channelID, "Linear is not configured. Add LINEAR_API_KEY to .env")
+	}
+
+	field, value := cutWord(args)
+	switch strings.ToLower(field) {
+	case "":
+		return h.client.SendMessage(channelID, fmt.Sprintf("Linear issues captured: *%s*\n%s", h.linearSyncer.Filter(), linearFilterUsage))
+
+	case "reset":
+		if err := h.linearSyncer.ResetFilter(ctx); err != nil {
+			slog.ErrorContext(ctx, "Failed to reset Linear filter", "error", err)
+			return h.client.SendMessage(channelID, "Failed to reset the Linear filter")
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("The Linear filter is back to the configured one: *%s*", h.linearSyncer.Filter()))
+	}
+
+	filter := h.linearSyncer.Filter()
+	if err := filter.Set(field, value); err != nil {
+		return h.client.SendMessage(channelID, linearFilterUsage)
+	}
+
+	if err := h.linearSyncer.SetFilter(ctx, filter, userID); err != nil {
+		slog.ErrorContext(ctx, "Failed to save Linear filter", "error", err)
+		return h.client.SendMessage(channelID, "Failed to save the Linear filter")
+	}
+
+	slog.InfoContext(ctx, "Linear filter changed", "filter", filter.String(), "user", userID)
+	return h.client.SendMessage(channelID, fmt.Sprintf("<@%s> changed the Linear filter. Issues captured from now on: *%s*", userID, filter))
+}
+
+func (h *CommandHandler) HandleNotionSync(ctx context.Context, channelID string) error {
+	if h.notionSyncer == nil {
+		return h.client.SendMessage(channelID, "Notion is not configured. Add NOTION_TOKEN and NOTION_DATABASE_ID to .env")
+	}
+
+	h.client.SendMessage(channelID, "Syncing with Notion...")
+
+	result, err := h.notionSyncer.Sync(ctx, channelID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Notion sync failed", "error", err)
+		return h.client.SendMessage(channelID, "Failed to sync with Notion")
+	}
+
+	message := "Notion sync completed!\n\n"
+	message += fmt.Sprintf("Captured %d new thoughts from %d ideas", result.Created, result.Fetched)
+	if result.Skipped > 0 {
+		message += fmt.Sprintf(" (%d already captured)", result.Skipped)
+	}
+	message += ".\n"
+	if result.Failed > 0 {
+		message += fmt.Sprintf("%d ideas failed to import.\n", result.Failed)
+	}
+	message += fmt.Sprintf("Updated %d posts in the content calendar.", result.Exported)
+
+	return h.client.SendMessage(channelID, message)
+}
+
+// HandleWorkspace shows or switches whether a channel keeps its thoughts to
+// itself or pools them with the other shared channels.
+func (h *CommandHandler) HandleWorkspace(ctx context.Context, channelID, mode string) error {
+	switch mode {
+	case "":
+		shared, err := h.workspaceRepo.IsShared(ctx, channelID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load channel workspace", "error", err)
+			return h.client.SendMessage(channelID, "Failed to load workspace settings")
+		}
+		if shared {
+			return h.client.SendMessage(channelID, "This channel is *shared*: it uses thoughts from every shared channel and from Linear. Use `@LinkedIn Ghostwriter workspace isolated` to keep it to itself.")
+		}
+		return h.client.SendMessage(channelID, "This channel is *isolated*: only thoughts captured here are used. Use `@LinkedIn Ghostwriter workspace shared` to pool them with other shared channels.")
+	case "shared", "isolated":
+		if err := h.workspaceRepo.SetShared(ctx, channelID, mode == "shared"); err != nil {
+			slog.ErrorContext(ctx, "Failed to update channel workspace", "error", err)
+			return h.client.SendMessage(channelID, "Failed to update workspace settings")
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("This channel is now *%s*.", mode))
+	default:
+		return h.client.SendMessage(channelID, "Usage: `@LinkedIn Ghostwriter workspace [shared|isolated]`")
+	}
+}
+
+const modelUsage = "Usage: `@LinkedIn Ghostwriter model [generation|categorizer] [anthropic|openai|ollama] [model]`, or `model [generation|categorizer] reset`"
+
+// HandleModel shows the model of each LLM role, or switches one without a
+// restart. args is a role followed by a model, optionally preceded by a
+// provider, or by "reset" to go back to the configured model. Only owners
+// may switch models.
+func (h *CommandHandler) HandleModel(ctx context.Context, channelID, userID string, args []string) error {
+	roles := []string{agents.RoleGeneration, agents.RoleCategorizer}
+
+	if len(args) == 0 {
+		var b strings.Builder
+		b.WriteString("*Models*\n")
+		for _, role := range roles {
+			provider := h.models.Get(role)
+			fmt.Fprintf(&b, "• %s: `%s` on %s\n", role, provider.Model(), provider.Name())
+		}
+		b.WriteString("\n" + modelUsage)
+		return h.client.SendMessage(channelID, b.String())
+	}
+
+	role := strings.ToLower(args[0])
+	if h.models.Get(role) == nil || len(args) < 2 || len(args) > 3 {
+		return h.client.SendMessage(channelID, modelUsage)
+	}
+
+	if !h.roles.authorize(ctx, channelID, userID, models.RoleOwner, "switch models") {
+		return nil
+	}
+
+	if len(args) == 2 && strings.ToLower(args[1]) == "reset" {
+		if err := h.models.Reset(ctx, role); err != nil {
+			slog.ErrorContext(ctx, "Failed to reset model", "role", role, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Failed to reset the %s model: %v", role, err))
+		}
+		provider := h.models.Get(role)
+		return h.client.SendMessage(channelID, fmt.Sprintf("The %s model is back to `%s` on %s.", role, provider.Model(), provider.Name()))
+	}
+
+	providerName, model := "", args[len(args)-1]
+	if len(args) == 3 {
+		providerName = strings.ToLower(args[1])
+	}
+
+	if err := h.models.Switch(ctx, role, providerName, model, userID); err != nil {
+		slog.ErrorContext(ctx, "Failed to switch model", "role", role, "error", err)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Failed to switch the %s model: %v", role, err))
+	}
+
+	provider := h.models.Get(role)
+	slog.InfoContext(ctx, "Switched model", "role", role, "provider", provider.Name(), "model", provider.Model(), "user", userID)
+	return h.client.SendMessage(channelID, fmt.Sprintf("<@%s> switched the %s model to `%s` on %s.", userID, role, provider.Model(), provider.Name()))
+}
+
+const promptUsage = "Usage: `@LinkedIn Ghostwriter prompt [show|set|reset] [generate|brainstorm|categorize|categorize-batch]`, with the new template after `set`"
+
+// promptEscaper undoes the escaping Slack applies to message text, so
+// templates can be pasted as they are.
+var promptEscaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// HandlePrompt lists the prompt templates, shows one, or overrides or resets
+// one. args is everything after "prompt", newlines included, since a
+// template set from Slack is usually several lines long.
+func (h *CommandHandler) HandlePrompt(ctx context.Context, channelID, userID, args string) error {
+	action, rest := cutWord(args)
+	name, text := cutWord(rest)
+	action, name = strings.ToLower(action), strings.ToLower(name)
+
+	if action == "" || action == "list" {
+		var b strings.Builder
+		b.WriteString("*Prompts*\n")
+		for _, name := range prompts.Names() {
+			_, overridden, err := h.prompts.Get(ctx, name)
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to load prompt", "prompt", name, "error", err)
+			}
+			source := "built-in"
+			if overridden {
+				source = "customized"
+			}
+			fmt.Fprintf(&b, "• `%s` (%s): %s\n", name, source, prompts.Variables(name))
+		}
+		b.WriteString("\n" + promptUsage)
+		return h.client.SendMessage(channelID, b.String())
+	}
+
+	if prompts.Variables(name) == "" {
+		return h.client.SendMessage(channelID, promptUsage)
+	}
+
+	if (action == "set" || action == "reset") && !h.roles.authorize(ctx, channelID, userID, models.RoleOwner, "change prompts") {
+		return nil
+	}
+
+	switch action {
+	case "show":
+		template, overridden, err := h.prompts.Get(ctx, name)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load prompt", "prompt", name, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Failed to load the %s prompt: %v", name, err))
+		}
+		source := "built-in"
+		if overridden {
+			source = "customized"
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("*%s prompt* (%s)\n```\n%s\n```\nVariables: %s", name, source, template, prompts.Variables(name)))
+
+	case "set":
+		text = strings.TrimSpace(promptEscaper.Replace(text))
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "```"), "```"))
+		if text == "" {
+			return h.client.SendMessage(channelID, fmt.Sprintf("Paste the new template after `prompt set %s`. Variables: %s", name, prompts.Variables(name)))
+		}
+
+		if err := h.prompts.Set(ctx, name, text, userID); err != nil {
+			slog.ErrorContext(ctx, "Failed to set prompt", "prompt", name, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Couldn't save the %s prompt: %v", name, err))
+		}
+		slog.InfoContext(ctx, "Prompt overridden", "prompt", name, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("<@%s> updated the %s prompt. Use `prompt reset %s` to go back to the built-in one.", userID, name, name))
+
+	case "reset":
+		if err := h.prompts.Reset(ctx, name); err != nil {
+			slog.ErrorContext(ctx, "Failed to reset prompt", "prompt", name, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Failed to reset the %s prompt: %v", name, err))
+		}
+		slog.InfoContext(ctx, "Prompt reset", "prompt", name, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("The %s prompt is back to the built-in template.", name))
+	}
+
+	return h.client.SendMessage(channelID, promptUsage)
+}
+
+const categoriesUsage = "Usage: `@LinkedIn Ghostwriter categories [add name [description] | rename old new | remove name | rules name [keyword, /pattern/, ... | none]]`"
+
+// HandleCategories lists the categories thoughts are filed under, with how
+// many thoughts this channel sees in each, or adds, renames or removes one,
+// or sets its rules.
+func (h *CommandHandler) HandleCategories(ctx context.Context, channelID, userID, args string) error {
+	action, rest := cutWord(args)
+	name, rest := cutWord(rest)
+	name = models.NormalizeCategory(name)
+
+	switch strings.ToLower(action) {
+	case "", "list":
+		categories, err := h.categoryRepo.List(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to list categories", "error", err)
+			return h.client.SendMessage(channelID, "Failed to list categories")
+		}
+
+		counts, err := h.thoughtRepo.CountByCategory(ctx, channelID, time.Time{}, time.Time{})
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to count thoughts by category", "error", err)
+		}
+
+		var b strings.Builder
+		b.WriteString("*Categories*\n")
+		for _, category := range categories {
+			fmt.Fprintf(&b, "• `%s` (%d)", category.Name, counts[category.Name])
+			if category.Description != "" {
+				b.WriteString(": " + category.Description)
+			}
+			if len(category.Rules) > 0 {
+				b.WriteString(" _rules: " + formatRules(category.Rules) + "_")
+			}
+			b.WriteString("\n")
+		}
+		if counts["uncategorized"] > 0 {
+			fmt.Fprintf(&b, "• _uncategorized_ (%d)\n", counts["uncategorized"])
+		}
+		b.WriteString("\n" + categoriesUsage)
+		return h.client.SendMessage(channelID, b.String())
+
+	case "add":
+		if name == "" || name == "uncategorized" || len(name) > models.MaxCategoryName {
+			return h.client.SendMessage(channelID, categoriesUsage)
+		}
+
+		err := h.categoryRepo.Create(ctx, models.NewCategory(name, rest, userID))
+		if errors.Is(err, database.ErrCategoryExists) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("`%s` is already a category.", name))
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to add category", "category", name, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Failed to add the %s category", name))
+		}
+
+		slog.InfoContext(ctx, "Category added", "category", name, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Added the `%s` category. New thoughts can be filed under it from now on.", name))
+
+	case "rename":
+		newName := models.NormalizeCategory(rest)
+		if name == "" || newName == "" || newName == "uncategorized" || len(newName) > models.MaxCategoryName {
+			return h.client.SendMessage(channelID, categoriesUsage)
+		}
+
+		moved, err := h.categoryRepo.Rename(ctx, name, newName)
+		switch {
+		case errors.Is(err, database.ErrCategoryNotFound):
+			return h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` category.", name))
+		case errors.Is(err, database.ErrCategoryExists):
+			return h.client.SendMessage(channelID, fmt.Sprintf("`%s` is already a category.", newName))
+		case err != nil:
+			slog.ErrorContext(ctx, "Failed to rename category", "category", name, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Failed to rename the %s category", name))
+		}
+
+		slog.InfoContext(ctx, "Category renamed", "from", name, "to", newName, "thoughts", moved, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Renamed `%s` to `%s` and moved %d thought(s) with it.", name, newName, moved))
+
+	case "remove":
+		if name == "" {
+			return h.client.SendMessage(channelID, categoriesUsage)
+		}
+
+		moved, err := h.categoryRepo.Delete(ctx, name)
+		if errors.Is(err, database.ErrCategoryNotFound) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` category.", name))
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to remove category", "category", name, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Failed to remove the %s category", name))
+		}
+
+		slog.InfoContext(ctx, "Category removed", "category", name, "thoughts", moved, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Removed the `%s` category. Its %d thought(s) are now uncategorized.", name, moved))
+
+	case "rules":
+		if name == "" {
+			return h.client.SendMessage(channelID, categoriesUsage)
+		}
+		return h.setCategoryRules(ctx, channelID, userID, name, rest)
+	}
+
+	return h.client.SendMessage(channelID, categoriesUsage)
+}
+
+// setCategoryRules replaces the rules of a category with the comma
+// separated rules in text, or clears them when text is "none". Without
+// text, the current rules are shown.
+func (h *CommandHandler) setCategoryRules(ctx context.Context, channelID, userID, name, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		categories, err := h.categoryRepo.List(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to list categories", "error", err)
+			return h.client.SendMessage(channelID, "Failed to load the categories")
+		}
+		for _, category := range categories {
+			if category.Name != name {
+				continue
+			}
+			if len(category.Rules) == 0 {
+				return h.client.SendMessage(channelID, fmt.Sprintf("`%s` has no rules, so the categorizer decides. %s", name, categoriesUsage))
+			}
+			return h.client.SendMessage(channelID, fmt.Sprintf("Thoughts matching %s are filed under `%s`.", formatRules(category.Rules), name))
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` category.", name))
+	}
+
+	var rules []string
+	if !strings.EqualFold(text, "none") {
+		rules = splitRules(text)
+		for _, rule := range rules {
+			if _, err := agents.CompileRule(rule); err != nil {
+				return h.client.SendMessage(channelID, fmt.Sprintf("I can't use the rule `%s`: %v", rule, err))
+			}
+		}
+	}
+
+	err := h.categoryRepo.SetRules(ctx, name, rules)
+	if errors.Is(err, database.ErrCategoryNotFound) {
+		return h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` category.", name))
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to set category rules", "category", name, "error", err)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Failed to set the rules of the %s category", name))
+	}
+
+	slog.InfoContext(ctx, "Category rules set", "category", name, "rules", len(rules), "user", userID)
+	if len(rules) == 0 {
+		return h.client.SendMessage(channelID, fmt.Sprintf("Cleared the rules of `%s`.", name))
+	}
+	return h.client.SendMessage(channelID, fmt.Sprintf("New thoughts matching %s are filed under `%s` without asking the categorizer, unless another category's rules match them too.", formatRules(rules), name))
+}
+
+// splitRules splits comma separated rules, keeping commas inside a
+// /pattern/.
+func splitRules(text string) []string {
+	var rules []string
+	var current string
+	for _, part := range strings.Split(text, ",") {
+		if current != "" {
+			current += "," + part
+		} else {
+			current = strings.TrimSpace(part)
+		}
+
+		trimmed := strings.TrimSpace(current)
+		if strings.HasPrefix(trimmed, "/") && (len(trimmed) < 2 || !strings.HasSuffix(trimmed, "/")) {
+			continue
+		}
+		if trimmed != "" {
+			rules = append(rules, trimmed)
+		}
+		current = ""
+	}
+	if trimmed := strings.TrimSpace(current); trimmed != "" {
+		rules = append(rules, trimmed)
+	}
+
+	return rules
+}
+
+func formatRules(rules []string) string {
+	quoted := make([]string, len(rules))
+	for i, rule := range rules {
+		quoted[i] = "`" + rule + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// categoryNames lists the category names for the help message.
+func (h *CommandHandler) categoryNames(ctx context.Context) string {
+	categories, err := h.categoryRepo.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list categories", "error", err)
+		return "_unavailable_"
+	}
+
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = category.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// cutWord splits s into its first word and the rest, keeping the rest's
+// line breaks.
+func cutWord(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i:])
+	}
+	return s, ""
+}
+
+const (
+	searchResultLimit = 10
+	// Matches below this similarity are noise for short queries.
+	minSearchSimilarity = 0.3
+	// rrfK dampens how much the top rank of a single ranking dominates the
+	// fused score, as in standard reciprocal rank fusion.
+	rrfK = 60
+)
+
+func (h *CommandHandler) HandleSearch(ctx context.Context, channelID, query string) error {
+	textMatches, err := h.thoughtRepo.SearchText(ctx, channelID, query, searchResultLimit*2)
+	if err != nil {
+		slog.ErrorContext(ctx, "Thought search failed", "error", err)
+		return h.client.SendMessage(channelID, "Failed to search thoughts")
+	}
+
+	scores := make(map[string]float64)
+	thoughtsByID := make(map[string]*models.Thought)
+	for rank, t := range textMatches {
+		scores[t.ID] += 1.0 / float64(rrfK+rank+1)
+		thoughtsByID[t.ID] = t
+	}
+
+	if h.embeddings != nil {
+		similar, err := h.embeddings.SimilarTo(ctx, channelID, query, searchResultLimit*2)
+		if err != nil {
+			slog.ErrorContext(ctx, "Semantic search failed, using text matches only", "error", err)
+		}
+		rank := 0
+		for _, match := range similar {
+			if match.Similarity < minSearchSimilarity {
+				continue
+			}
+			scores[match.Thought.ID] += 1.0 / float64(rrfK+rank+1)
+			thoughtsByID[match.Thought.ID] = match.Thought
+			rank++
+		}
+	}
+
+	if len(thoughtsByID) == 0 {
+		return h.client.SendMessage(channelID, fmt.Sprintf("No thoughts found matching '%s'", query))
+	}
+
+	results := make([]*models.Thought, 0, len(thoughtsByID))
+	for _, t := range thoughtsByID {
+		results = append(results, t)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if scores[results[i].ID] != scores[results[j].ID] {
+			return scores[results[i].ID] > scores[results[j].ID]
+		}
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+	if len(results) > searchResultLimit {
+		results = results[:searchResultLimit]
+	}
+
+	message := fmt.Sprintf("*Thoughts matching '%s'*\n\n", query)
+	for i, t := range results {
+		message += fmt.Sprintf("%d. *%s* · %s · _%s_\n%s\n\n", i+1, t.Category, formatAge(t.Timestamp), t.Status, truncate(t.Content, 120))
+	}
+
+	return h.client.SendMessage(channelID, message)
+}
+
+func formatAge(t time.Time) string {
+	age := time.Since(t)
+	switch {
+	case age < time.Hour:
+		return "just now"
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	case age < 60*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	default:
+		return t.Format("Jan 2, 2006")
+	}
+}
+
+// statsWeeks is how many weeks the stats time series and category trends
+// cover; trends compare it with the same span before it.
+const statsWeeks = 4
+
+func (h *CommandHandler) HandleStats(ctx context.Context, channelID string) error {
+	now := time.Now()
+	periodStart := now.AddDate(0, 0, -7*statsWeeks)
+
+	total, err := h.thoughtRepo.CountByCategory(ctx, channelID, time.Time{}, time.Time{})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to count thoughts", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch stats")
+	}
+	current, err := h.thoughtRepo.CountByCategory(ctx, channelID, periodStart, time.Time{})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to count recent thoughts", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch stats")
+	}
+	previous, err := h.thoughtRepo.CountByCategory(ctx, channelID, periodStart.AddDate(0, 0, -7*statsWeeks), periodStart)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to count earlier thoughts", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch stats")
+	}
+
+	thoughtWeeks, err := h.thoughtRepo.CountByWeek(ctx, channelID, statsWeeks)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to count thoughts by week", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch stats")
+	}
+	postWeeks, err := h.postRepo.CountByWeek(ctx, statsWeeks)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to count posts by week", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch stats")
+	}
+
+	statuses, err := h.postRepo.CountByStatus(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to count posts by status", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch stats")
+	}
+	timeToPublish, err := h.postRepo.AverageTimeToPublish(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to compute time to publish", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch stats")
+	}
+
+	count := 0
+	for _, cnt := range total {
+		count += cnt
+	}
+
+	message := "*Thought Statistics*\n\n"
+	message += fmt.Sprintf("Total captured: *%d*\n\n", count)
+
+	message += fmt.Sprintf("*Last %d weeks:*\n", statsWeeks)
+	for i, week := range thoughtWeeks {
+		published := 0
+		if i < len(postWeeks) {
+			published = postWeeks[i].Count
+		}
+		message += fmt.Sprintf("• Week of %s: %d thought(s), %d published\n", week.Week.Format("Jan 02"), week.Count, published)
+	}
+
+	approved := statuses["approved"] + statuses["scheduled"] + statuses["published"]
+	if reviewed := approved + statuses["rejected"]; reviewed > 0 {
+		message += fmt.Sprintf("\nApproval rate: *%.0f%%* (%d of %d reviewed drafts)\n", float64(approved)*100/float64(reviewed), approved, reviewed)
+	} else {
+		message += "\nApproval rate: no drafts reviewed yet\n"
+	}
+	if timeToPublish > 0 {
+		message += fmt.Sprintf("Average thought to publish: *%s*\n", formatDuration(timeToPublish))
+	}
+
+	message += fmt.Sprintf("\n*By Category* (last %d weeks vs the %d before):\n", statsWeeks, statsWeeks)
+	categories := make([]string, 0, len(total))
+	for category := range total {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return total[categories[i]] > total[categories[j]]
+	})
+	for _, category := range categories {
+		message += fmt.Sprintf("• %s: %d%s\n", category, total[category], formatTrend(current[category], previous[category]))
+	}
+
+	thoughts, err := h.thoughtRepo.GetByWorkspace(ctx, channelID, 3, 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch recent thoughts", "error", err)
+	}
+	if len(thoughts) > 0 {
+		message += "\n*Recent Thoughts:*\n"
+		for i, thought := range thoughts {
+			message += fmt.Sprintf("%d. [%s] %s\n", i+1, thought.Category, truncate(thought.Content, 60))
+		}
+	}
+
+	return h.client.SendMessage(channelID, message)
+}
+
+func formatTrend(current, previous int) string {
+	switch {
+	case current > previous:
+		return fmt.Sprintf(" (%d recent, ↑%d)", current, current-previous)
+	case current < previous:
+		return fmt.Sprintf(" (%d recent, ↓%d)", current, previous-current)
+	case current > 0:
+		return fmt.Sprintf(" (%d recent, steady)", current)
+	default:
+		return ""
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%.1f hours", d.Hours())
+	}
+	return fmt.Sprintf("%.1f days", d.Hours()/24)
+}
+
+func (h *CommandHandler) HandlePerformance(ctx context.Context, channelID string) error {
+	posts, err := h.postRepo.GetPublishedSince(ctx, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		return h.client.SendMessage(channelID, "Failed to fetch published posts")
+	}
+
+	var tracked []*models.Post
+	for _, post := range posts {
+		if post.MetricsSyncedAt != nil {
+			tracked = append(tracked, post)
+		}
+	}
+
+	if len(tracked) == 0 {
+		return h.client.SendMessage(channelID, "No engagement data for posts published in the last 30 days yet. Metrics are pulled from LinkedIn a few times a day.")
+	}
+
+	const showCount = 3
+
+	message := fmt.Sprintf("*Post Performance* (last 30 days, %d posts)\n\n", len(tracked))
+	message += "*Top posts:*\n"
+	for i, post := range tracked {
+		if i >= showCount {
+			break
+		}
+		message += formatPerformanceLine(i+1, post)
+	}
+
+	if len(tracked) > showCount {
+		message += "\n*Lowest performing:*\n"
+		start := len(tracked) - showCount
+		if start < showCount {
+			start = showCount
+		}
+		for i := start; i < len(tracked); i++ {
+			message += formatPerformanceLine(i+1, tracked[i])
+		}
+	}
+
+	return h.client.SendMessage(channelID, message)
+}
+
+func formatPerformanceLine(rank int, post *models.Post) string {
+	published := ""
+	if post.PublishedAt != nil {
+		published = post.PublishedAt.Format("Jan 02")
+	}
+
+	return fmt.Sprintf("%d. *%.1f* · %s · 👍 %d · 💬 %d · 🔁 %d · 👀 %d\n_%s_\n",
+		rank,
+		post.PerformanceScore,
+		published,
+		post.Metrics["likes"],
+		post.Metrics["comments"],
+		post.Metrics["shares"],
+		post.Metrics["views"],
+		truncate(post.Content, 80))
+}
+
+// HandleConnectLinkedIn links to connecting the user's profile, or the
+// company page when page is set.
+func (h *CommandHandler) HandleConnectLinkedIn(ctx context.Context, channelID, userID string, page bool) error {
+	if h.linkedinAuth == nil {
+		return h.client.SendMessage(channelID, "LinkedIn OAuth is not configured. Add LINKEDIN_CLIENT_ID and LINKEDIN_CLIENT_SECRET to .env")
+	}
+
+	if page {
+		if !h.linkedinAuth.HasOrganization() {
+			return h.client.SendMessage(channelID, "No company page is configured. Add LINKEDIN_ORGANIZATION_URN to .env")
+		}
+
+		message := "*Connect the company page*\n\n"
+		message += fmt.Sprintf("<%s|Click here to authorize posting to the page>\n\n", h.linkedinAuth.OrganizationAuthURL(userID))
+		message += "You need to be an administrator of the page. Once connected, drafts can target *Company page* as well as your profile."
+		return h.client.SendMessage(channelID, message)
+	}
+
+	// Posts only go to the author's profile, so nobody else's is connected.
+	if author := h.linkedinAuth.AuthorID(); userID != author {
+		return h.client.SendMessage(channelID, fmt.Sprintf("Posts are published to <@%s>'s LinkedIn profile, so only they can connect it.", author))
+	}
+
+	message := "*Connect your LinkedIn account*\n\n"
+	message += fmt.Sprintf("<%s|Click here to authorize LinkedIn Ghostwriter>\n\n", h.linkedinAuth.AuthURL(userID))
+	message += "Once connected, scheduled posts will be published on your behalf."
+
+	return h.client.SendMessage(channelID, message)
+}
+
+func (h *CommandHandler) HandleLearnStyle(ctx context.Context, channelID, userID, text string) error {
+	profile, err := h.styleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return h.client.SendMessage(channelID, "Failed to load your style profile")
+	}
+
+	samples := splitSamplePosts(text)
+	if len(samples) == 0 {
+		if profile == nil {
+			return h.client.SendMessage(channelID, "Paste a few of your past LinkedIn posts after the command, separated by `---`:\n`@LinkedIn Ghostwriter learn-style [post 1] --- [post 2]`")
+		}
+		return h.client.SendMessage(channelID, "*Your Writing Style*\n\n"+agents.FormatStyleGuide(profile)+fmt.Sprintf("\n\n_Learned from %d post(s)_", len(profile.SamplePosts)))
+	}
+
+	if profile == nil {
+		profile = models.NewStyleProfile(userID)
+	}
+
+	profile.SamplePosts = append(profile.SamplePosts, samples...)
+	if len(profile.SamplePosts) > maxStyleSamples {
+		profile.SamplePosts = profile.SamplePosts[len(profile.SamplePosts)-maxStyleSamples:]
+	}
+
+	h.client.SendMessage(channelID, fmt.Sprintf("Analyzing %d post(s) to learn your style... This may take a moment.", len(profile.SamplePosts)))
+
+	if err := h.styleAnalyzer.AnalyzeStyle(ctx, profile); err != nil {
+		slog.ErrorContext(ctx, "Failed to analyze style", "error", err)
+		return h.client.SendMessage(channelID, llmErrorMessage(err, "Failed to analyze your writing style. Please try again."))
+	}
+
+	if err := h.styleRepo.Save(ctx, profile); err != nil {
+		slog.ErrorContext(ctx, "Failed to save style profile", "error", err)
+		return h.client.SendMessage(channelID, "Failed to save your style profile. Please try again.")
+	}
+
+	message := "*Style profile updated!*\n\n"
+	message += agents.FormatStyleGuide(profile)
+	message += "\n\nNew drafts from `@LinkedIn Ghostwriter generate` will follow this style."
+
+	return h.client.SendMessage(channelID, message)
+}
+
+const maxStyleSamples = 10
+
+func splitSamplePosts(text string) []string {
+	var samples []string
+	for _, part := range strings.Split(text, "---") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			samples = append(samples, part)
+		}
+	}
+	return samples
+}
+
+func (h *CommandHandler) HandleRevise(ctx context.Context, channelID, userID, draftNumber, feedback string) ([]slack.Block, []string, error) {
+	usage := "Usage: `@LinkedIn Ghostwriter revise [draft #] [feedback]`"
+
+	var index int
+	if _, err := fmt.Sscanf(draftNumber, "%d", &index); err != nil || index < 1 {
+		h.client.SendMessage(channelID, usage)
+		return nil, nil, fmt.Errorf("invalid draft number: %q", draftNumber)
+	}
+
+	if strings.TrimSpace(feedback) == "" {
+		h.client.SendMessage(channelID, usage)
+		return nil, nil, fmt.Errorf("no feedback provided")
+	}
+
+	drafts, err := h.postRepo.GetByStatus(ctx, "draft")
+	if err != nil {
+		h.client.SendMessage(channelID, "Failed to fetch drafts")
+		return nil, nil, err
+	}
+
+	if index > len(drafts) {
+		h.client.SendMessage(channelID, fmt.Sprintf("Draft %d not found. Use `@LinkedIn Ghostwriter drafts` to see pending drafts.", index))
+		return nil, nil, fmt.Errorf("draft %d not found", index)
+	}
+
+	original := drafts[index-1]
+
+	h.client.SendMessage(channelID, fmt.Sprintf("Revising Draft %d... This may take a moment.", index))
+
+	revised, err := h.contentGenerator.RevisePost(h.withEmojiPolicy(ctx, userID), original, feedback)
+	if err != nil {
+		h.client.SendMessage(channelID, llmErrorMessage(err, "Failed to revise draft. Please try again."))
+		return nil, nil, err
+	}
+
+	post := newRevision(original, revised)
+	if h.factChecker != nil {
+		// The feedback may add facts of its own, which are as good as the
+		// thoughts'.
+		sourceThoughts, err := h.thoughtRepo.GetByIDs(ctx, channelID, original.SourceThoughtIDs)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load draft thoughts", "error", err)
+		} else if len(sourceThoughts) > 0 {
+			h.checkFacts(ctx, post, append(sourceThoughts, models.NewThought(feedback, "feedback")))
+		}
+	}
+	if err := h.postRepo.Create(ctx, post); err != nil {
+		h.client.SendMessage(channelID, "Failed to save revised draft. Please try again.")
+		return nil, nil, err
+	}
+	h.recordRevision(ctx, post, models.RevisionFeedback, userID, feedback)
+
+	if err := h.postRepo.UpdateStatus(ctx, original.ID, "revised"); err != nil {
+		slog.ErrorContext(ctx, "Failed to mark draft as revised", "original_id", original.ID, "error", err)
+	}
+
+	header := fmt.Sprintf("*Revised Draft %d*\n_Feedback: %s_", index, feedback)
+
+	return buildDraftBlocks(header, []*models.Post{post}, h.publishTargets), []string{post.ID}, nil
+}
+
+// newRevision is a new draft of original with content, keeping its sources,
+// slides, poll, targets and language.
+func newRevision(original *models.Post, content string) *models.Post {
+	post := models.NewPost(content, original.SourceThoughtIDs, original.PostType, original.Tone)
+	post.ParentPostID = &original.ID
+	post.BrainstormSessionID = original.BrainstormSessionID
+	post.Slides = original.Slides
+	post.DocumentPath = original.DocumentPath
+	post.Poll = original.Poll
+	post.Targets = original.Targets
+	post.FirstComment = original.FirstComment
+	post.Language = original.Language
+	post.TranslationOf = original.TranslationOf
+	post.Provenance = original.Provenance
+	return post
+}
+
+func llmErrorMessage(err error, fallback string) string {
+	if errors.Is(err, agents.ErrLLMUnavailable) {
+		return "The AI provider is overloaded or unavailable right now and didn't recover after several retries. Please try again in a few minutes."
+	}
+	if errors.Is(err, agents.ErrBudgetExceeded) {
+		return "This month's LLM budget is spent, so nothing new can be generated until next month. Use `@LinkedIn Ghostwriter usage` to see where it went."
+	}
+	return fallback
+}
+
+func (h *CommandHandler) recordRevision(ctx context.Context, post *models.Post, source, editor, note string) {
+	if err := h.revisionRepo.Record(ctx, post.ID, post.Content, source, editor, note); err != nil {
+		slog.ErrorContext(ctx, "Failed to record revision of post", "post_id", post.ID, "error", err)
+	}
+}
+
+// HandleHistory lists every version of a pending draft, including the
+// drafts it was revised from, and with `restore [version #]` puts an
+// earlier version back.
+func (h *CommandHandler) HandleHistory(ctx context.Context, channelID, userID string, args []string) error {
+	usage := "Usage: `@LinkedIn Ghostwriter history [draft #]` or `@LinkedIn Ghostwriter history [draft #] restore [version #]`"
+
+	if len(args) != 1 && !(len(args) == 3 && args[1] == "restore") {
+		return h.client.SendMessage(channelID, usage)
+	}
+
+	index, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || index < 1 {
+		return h.client.SendMessage(channelID, usage)
+	}
+
+	drafts, err := h.postRepo.GetByStatus(ctx, "draft")
+	if err != nil {
+		return h.client.SendMessage(channelID, "Failed to fetch drafts")
+	}
+	if index > len(drafts) {
+		return h.client.SendMessage(channelID, fmt.Sprintf("Draft %d not found. Use `@LinkedIn Ghostwriter drafts` to see pending drafts.", index))
+	}
+	post := drafts[index-1]
+
+	revisions, err := h.revisionRepo.GetHistory(ctx, post.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load history of post", "post_id", post.ID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to load draft history")
+	}
+	if len(revisions) == 0 {
+		return h.client.SendMessage(channelID, fmt.Sprintf("No history recorded for Draft %d.", index))
+	}
+
+	if len(args) == 3 {
+		version, err := strconv.Atoi(strings.TrimPrefix(args[2], "v"))
+		if err != nil || version < 1 || version > len(revisions) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("Pick a version between 1 and %d.", len(revisions)))
+		}
+
+		post.Content = revisions[version-1].Content
+		if err := h.postRepo.Update(ctx, post); err != nil {
+			slog.ErrorContext(ctx, "Failed to restore post", "post_id", post.ID, "error", err)
+			return h.client.SendMessage(channelID, "Failed to restore draft")
+		}
+		h.recordRevision(ctx, post, models.RevisionRestore, userID, fmt.Sprintf("restored version %d", version))
+
+		return h.client.SendMessage(channelID, fmt.Sprintf("Restored version %d of Draft %d:\n\n%s", version, index, format.ToSlack(post.Content)))
+	}
+
+	message := fmt.Sprintf("*History of Draft %d* (%d versions)\n\n", index, len(revisions))
+	for i, revision := range revisions {
+		message += fmt.Sprintf("*v%d* · %s · %s", i+1, revision.Source, revision.CreatedAt.Format("Jan 2 15:04"))
+		if revision.Editor != "" {
+			message += " · " + formatEditor(revision.Editor)
+		}
+		if revision.Note != "" {
+			message += fmt.Sprintf("\n_%s_", truncate(revision.Note, 200))
+		}
+		message += fmt.Sprintf("\n%s\n\n", truncate(revision.Content, 200))
+	}
+	message += fmt.Sprintf("Restore one with `@LinkedIn Ghostwriter history %d restore [version #]`", index)
+
+	return h.client.SendMessage(channelID, message)
+}
+
+// formatEditor mentions Slack users; other editors such as "api" or "cli"
+// are lower-case and shown as is.
+func formatEditor(editor string) string {
+	if editor == strings.ToUpper(editor) {
+		return fmt.Sprintf("<@%s>", editor)
+	}
+	return editor
+}
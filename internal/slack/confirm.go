@@ -0,0 +1,126 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+const (
+	actionConfirmPublish = "confirm_publish"
+	actionConfirmDelay   = "confirm_delay"
+	actionConfirmCancel  = "confirm_cancel"
+)
+
+// confirmDelay is how far the Delay button moves a post.
+const confirmDelay = 24 * time.Hour
+
+// confirmValue packs a post and the time it was scheduled at when it was
+// sent for confirmation into a button value, so a button on an old
+// confirmation doesn't move a post rescheduled since.
+func confirmValue(post *models.Post) string {
+	return fmt.Sprintf("%s|%d", post.ID, post.ScheduledAt.UnixMicro())
+}
+
+func parseConfirmValue(value string) (string, time.Time, error) {
+	postID, micros, ok := strings.Cut(value, "|")
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("invalid confirmation value: %q", value)
+	}
+	at, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid confirmation value: %q", value)
+	}
+	return postID, time.UnixMicro(at), nil
+}
+
+// ConfirmPublish shows a post about to be published in channelID with
+// buttons to publish it now, delay it a day or cancel it.
+func (h *CommandHandler) ConfirmPublish(ctx context.Context, channelID string, post *models.Post) error {
+	return h.client.SendMessageWithBlocks(channelID, buildConfirmBlocks(post))
+}
+
+// HandleConfirmAction acts on a button of a publish confirmation. Posts
+// that were published, unscheduled or moved since are left alone.
+func (h *CommandHandler) HandleConfirmAction(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) error {
+	postID, scheduledAt, err := parseConfirmValue(action.Value)
+	if err != nil {
+		return err
+	}
+	userID := callback.User.ID
+
+	resolve := func(text string) error {
+		blocks := replaceDraftActions(callback.Message.Blocks.BlockSet, postID, text)
+		return h.client.UpdateMessageBlocks(callback.Channel.ID, callback.Message.Timestamp, blocks)
+	}
+
+	var moved bool
+	var text string
+	switch action.ActionID {
+	case actionConfirmPublish:
+		moved, err = h.postRepo.MoveSchedule(ctx, postID, scheduledAt, time.Now())
+		text = fmt.Sprintf("🚀 <@%s> chose to publish it now. It goes out within a minute.", userID)
+
+	case actionConfirmDelay:
+		delayed := scheduledAt.Add(confirmDelay)
+		moved, err = h.postRepo.MoveSchedule(ctx, postID, scheduledAt, delayed)
+		text = fmt.Sprintf("⏭️ <@%s> delayed it to %s. You'll be asked again before then.", userID, delayed.In(scheduleLocation()).Format("Mon, Jan 2 15:04"))
+
+	case actionConfirmCancel:
+		moved, err = h.postRepo.Unschedule(ctx, postID, scheduledAt)
+		text = fmt.Sprintf("🛑 Cancelled by <@%s>. It's back in the approved queue for the next `schedule`.", userID)
+
+	default:
+		return fmt.Errorf("unknown confirmation action: %s", action.ActionID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !moved {
+		return resolve("This post was already published, unscheduled or moved")
+	}
+	return resolve(text)
+}
+
+func buildConfirmBlocks(post *models.Post) []slack.Block {
+	due := post.ScheduledAt.In(scheduleLocation())
+	header := fmt.Sprintf("⏰ *Publishing at %s* to %s", due.Format("15:04"), formatTargets(post))
+	if minutes := int(time.Until(due).Round(time.Minute).Minutes()); minutes > 0 {
+		header += fmt.Sprintf(", in %d minutes", minutes)
+	}
+
+	content := format.ToSlack(post.Content)
+	switch {
+	case post.PostType == models.PostTypeCarousel:
+		content += fmt.Sprintf("\n\n_Carousel with %d slides_", len(post.Slides))
+	case post.Poll != nil:
+		content += fmt.Sprintf("\n\n_Poll: %s_", post.Poll.Question)
+	}
+
+	publish := slack.NewButtonBlockElement(actionConfirmPublish, confirmValue(post),
+		slack.NewTextBlockObject(slack.PlainTextType, "Publish now", false, false))
+	publish.Style = slack.StylePrimary
+
+	delay := slack.NewButtonBlockElement(actionConfirmDelay, confirmValue(post),
+		slack.NewTextBlockObject(slack.PlainTextType, "Delay 1 day", false, false))
+
+	cancel := slack.NewButtonBlockElement(actionConfirmCancel, confirmValue(post),
+		slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false))
+	cancel.Style = slack.StyleDanger
+
+	footer := "No need to reply: it goes out as planned. Cancelled posts go back to approved."
+
+	return []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, header, false, false), nil, nil),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, truncate(content, 2900), false, false), nil, nil),
+		slack.NewActionBlock(draftActionsBlockID(post.ID), publish, delay, cancel),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, footer, false, false)),
+	}
+}
@@ -0,0 +1,161 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const contactsUsage = "Usage: `@LinkedIn Ghostwriter contacts [add name[, alias...] [urn:li:person:... | urn:li:organization:...] [profile URL] | remove name]`"
+
+// HandleContacts lists the people and companies posts can mention, or adds,
+// updates or removes one.
+func (h *CommandHandler) HandleContacts(ctx context.Context, channelID, userID, args string) error {
+	action, rest := cutWord(args)
+
+	switch strings.ToLower(action) {
+	case "", "list":
+		contacts, err := h.contactRepo.List(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to list contacts", "error", err)
+			return h.client.SendMessage(channelID, "Failed to list contacts")
+		}
+
+		var b strings.Builder
+		b.WriteString("*Contacts*\n")
+		if len(contacts) == 0 {
+			b.WriteString("_None yet._\n")
+		}
+		for _, contact := range contacts {
+			b.WriteString("• " + describeContact(contact) + "\n")
+		}
+		b.WriteString("\n" + contactsUsage)
+		return h.client.SendMessage(channelID, b.String())
+
+	case "add":
+		contact := parseContact(rest, userID)
+		if contact == nil {
+			return h.client.SendMessage(channelID, contactsUsage)
+		}
+
+		if err := h.contactRepo.Save(ctx, contact); err != nil {
+			slog.ErrorContext(ctx, "Failed to save contact", "contact", contact.Name, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Failed to save %s", contact.Name))
+		}
+
+		slog.InfoContext(ctx, "Contact saved", "contact", contact.Name, "taggable", contact.Taggable(), "user", userID)
+		message := fmt.Sprintf("Saved %s. Posts that name them are tagged when published.", describeContact(contact))
+		if !contact.Taggable() {
+			message = fmt.Sprintf("Saved %s. Without a `urn:li:...` it can only be suggested, not tagged.", describeContact(contact))
+		}
+		return h.client.SendMessage(channelID, message)
+
+	case "remove":
+		name := strings.TrimSpace(rest)
+		if name == "" {
+			return h.client.SendMessage(channelID, contactsUsage)
+		}
+
+		err := h.contactRepo.Delete(ctx, name)
+		if errors.Is(err, database.ErrContactNotFound) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("There's no contact called %s.", name))
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to remove contact", "contact", name, "error", err)
+			return h.client.SendMessage(channelID, fmt.Sprintf("Failed to remove %s", name))
+		}
+
+		slog.InfoContext(ctx, "Contact removed", "contact", name, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Removed %s.", name))
+	}
+
+	return h.client.SendMessage(channelID, contactsUsage)
+}
+
+// parseContact reads `name[, alias...] [urn] [profile URL]`. The URN and URL
+// can come in any order after the names. It returns nil when there's no
+// name.
+func parseContact(args, userID string) *models.Contact {
+	var names []string
+	var urn, profileURL string
+	for _, field := range strings.Fields(args) {
+		// Slack sends links as <url> or <url|label>.
+		link, _, _ := strings.Cut(strings.Trim(field, "<>"), "|")
+		switch {
+		case strings.HasPrefix(link, "urn:li:"):
+			urn = link
+		case strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://"):
+			profileURL = link
+		default:
+			names = append(names, field)
+		}
+	}
+
+	var aliases []string
+	parts := strings.Split(strings.Join(names, " "), ",")
+	for _, alias := range parts[1:] {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	name := strings.TrimSpace(parts[0])
+	if name == "" || len(name) > models.MaxContactName {
+		return nil
+	}
+
+	return models.NewContact(name, aliases, urn, profileURL, userID)
+}
+
+func describeContact(contact *models.Contact) string {
+	text := "*" + contact.Name + "*"
+	if len(contact.Aliases) > 0 {
+		text += " (also " + strings.Join(contact.Aliases, ", ") + ")"
+	}
+	if contact.LinkedInURN != "" {
+		text += " · `" + contact.LinkedInURN + "`"
+	}
+	if contact.ProfileURL != "" {
+		text += " · " + contact.ProfileURL
+	}
+	return text
+}
+
+// formatMentions lists the contacts a draft names: the ones that will be
+// tagged when it's published, and the ones that can't be, with their
+// profile to link by hand. It returns "" when the draft names none.
+func formatMentions(post *models.Post, contacts []*models.Contact) string {
+	mentions := models.FindMentions(post.Content, contacts)
+	if len(mentions) == 0 {
+		return ""
+	}
+
+	var tagged, untagged []string
+	for _, mention := range mentions {
+		contact := mention.Contact
+		if contact.Taggable() {
+			tagged = append(tagged, "@"+contact.Name)
+			continue
+		}
+		if contact.ProfileURL != "" {
+			untagged = append(untagged, fmt.Sprintf("%s (%s)", contact.Name, contact.ProfileURL))
+		}
+	}
+
+	var parts []string
+	if len(tagged) > 0 {
+		parts = append(parts, "tags "+strings.Join(tagged, ", ")+" when published")
+	}
+	if len(untagged) > 0 {
+		parts = append(parts, "names "+strings.Join(untagged, ", ")+", add their profile link or a `urn:li:...` to tag them")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, "; ")
+}
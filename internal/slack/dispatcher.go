@@ -0,0 +1,144 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var tracer = otel.Tracer("github.com/shubh-37/linkedin-ghostwriter/internal/slack")
+
+type job struct {
+	ctx  context.Context
+	name string
+	run  func(ctx context.Context) error
+}
+
+type Dispatcher struct {
+	jobs    chan job
+	workers int
+	timeout time.Duration
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+
+	// abort cancels running jobs when shutdown runs out of time.
+	abort  context.Context
+	cancel context.CancelFunc
+}
+
+func NewDispatcher(workers, queueSize int, timeout time.Duration) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+
+	abort, cancel := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		jobs:    make(chan job, queueSize),
+		workers: workers,
+		timeout: timeout,
+		abort:   abort,
+		cancel:  cancel,
+	}
+}
+
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+
+	slog.Info("Event dispatcher started", "workers", d.workers)
+}
+
+// Submit queues a job without blocking. It returns false when the queue is
+// full or the dispatcher is shutting down, so the caller can ask Slack to retry.
+// The job runs with the values of ctx, such as its request ID, but not its
+// cancellation, since the request that queued it ends first.
+func (d *Dispatcher) Submit(ctx context.Context, name string, run func(ctx context.Context) error) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return false
+	}
+
+	select {
+	case d.jobs <- job{ctx: ctx, name: name, run: run}:
+		return true
+	default:
+		slog.WarnContext(ctx, "Event queue full, dropping job", "job", name)
+		return false
+	}
+}
+
+// Shutdown stops accepting jobs and waits for queued and running jobs to
+// finish or for ctx to expire. Jobs still running then are cancelled, which
+// aborts their in-flight LLM and API calls.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.jobs)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+
+	for j := range d.jobs {
+		d.run(j)
+	}
+}
+
+func (d *Dispatcher) run(j job) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(j.ctx), d.timeout)
+	defer cancel()
+	stop := context.AfterFunc(d.abort, cancel)
+	defer stop()
+
+	ctx, span := tracer.Start(ctx, j.name)
+	defer span.End()
+
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "Panic handling job", "job", j.name, "panic", r)
+			span.SetStatus(codes.Error, fmt.Sprint(r))
+		}
+	}()
+
+	start := time.Now()
+	if err := j.run(ctx); err != nil {
+		slog.ErrorContext(ctx, "Error handling job", "job", j.name, "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	if elapsed := time.Since(start); elapsed > d.timeout/2 {
+		slog.WarnContext(ctx, "Slow job", "job", j.name, "duration_ms", elapsed.Milliseconds())
+	}
+}
@@ -0,0 +1,166 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+const (
+	actionPublishDuplicate = "publish_duplicate"
+	actionRewriteDuplicate = "rewrite_duplicate"
+	actionCancelDuplicate  = "cancel_duplicate"
+)
+
+// duplicateValue packs the held post and the published post it repeats
+// into a button value.
+func duplicateValue(postID, matchID string) string {
+	return postID + "|" + matchID
+}
+
+// PromptDuplicate asks in channelID whether to publish, rewrite or cancel a
+// post the publisher held because it reads like match.
+func (h *CommandHandler) PromptDuplicate(ctx context.Context, channelID string, post *models.Post, match *database.SimilarPost) error {
+	return h.client.SendMessageWithBlocks(channelID, buildDuplicateBlocks(post, match))
+}
+
+// HandleDuplicateAction acts on a button of a duplicate prompt. A rewrite
+// returns the new draft for the caller to share.
+func (h *CommandHandler) HandleDuplicateAction(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) ([]slack.Block, []string, error) {
+	postID, matchID, _ := strings.Cut(action.Value, "|")
+	userID := callback.User.ID
+
+	resolve := func(text string) error {
+		blocks := replaceDraftActions(callback.Message.Blocks.BlockSet, postID, text)
+		return h.client.UpdateMessageBlocks(callback.Channel.ID, callback.Message.Timestamp, blocks)
+	}
+
+	switch action.ActionID {
+	case actionPublishDuplicate:
+		released, err := h.postRepo.ReleaseHold(ctx, postID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !released {
+			return nil, nil, resolve("This post is no longer on hold")
+		}
+		return nil, nil, resolve(fmt.Sprintf("🚀 <@%s> chose to publish it anyway. It goes out within a minute.", userID))
+
+	case actionCancelDuplicate:
+		cancelled, err := h.postRepo.TransitionStatus(ctx, postID, models.StatusOnHold, "rejected")
+		if err != nil {
+			return nil, nil, err
+		}
+		if !cancelled {
+			return nil, nil, resolve("This post is no longer on hold")
+		}
+		return nil, nil, resolve(fmt.Sprintf("🗑️ Cancelled by <@%s>", userID))
+
+	case actionRewriteDuplicate:
+		return h.rewriteDuplicate(ctx, postID, matchID, userID, resolve)
+	}
+
+	return nil, nil, fmt.Errorf("unknown duplicate action: %s", action.ActionID)
+}
+
+// rewriteDuplicate drafts a new version of a held post that doesn't repeat
+// the published one. The new draft goes through approval like any other.
+func (h *CommandHandler) rewriteDuplicate(ctx context.Context, postID, matchID, userID string, resolve func(string) error) ([]slack.Block, []string, error) {
+	// Claim the post first so a second click doesn't rewrite it twice.
+	claimed, err := h.postRepo.TransitionStatus(ctx, postID, models.StatusOnHold, "revised")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !claimed {
+		return nil, nil, resolve("This post is no longer on hold")
+	}
+
+	release := func(text string) {
+		if _, err := h.postRepo.TransitionStatus(ctx, postID, "revised", models.StatusOnHold); err != nil {
+			slog.ErrorContext(ctx, "Failed to put post back on hold", "post_id", postID, "error", err)
+		}
+		if err := resolve(text); err != nil {
+			slog.ErrorContext(ctx, "Failed to update duplicate prompt", "post_id", postID, "error", err)
+		}
+	}
+
+	original, err := h.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		release("Failed to load the post, it's still on hold")
+		return nil, nil, err
+	}
+
+	if err := resolve(fmt.Sprintf("✏️ <@%s> asked for a rewrite, working on it...", userID)); err != nil {
+		slog.ErrorContext(ctx, "Failed to update duplicate prompt", "post_id", postID, "error", err)
+	}
+
+	feedback := "This repeats a post that was already published. Keep the point, but find a new angle, example and opening so it doesn't read as the same post."
+	if match, err := h.postRepo.GetByID(ctx, matchID); err == nil {
+		feedback = fmt.Sprintf("This repeats a post already published on %s:\n\n%s\n\nKeep the point, but find a new angle, example and opening so it doesn't read as the same post.",
+			publishedDate(match), match.Content)
+	}
+
+	revised, err := h.contentGenerator.RevisePost(ctx, original, feedback)
+	if err != nil {
+		release(llmErrorMessage(err, "Failed to rewrite the post, it's still on hold. Try again."))
+		return nil, nil, err
+	}
+
+	post := newRevision(original, revised)
+	if err := h.postRepo.Create(ctx, post); err != nil {
+		release("Failed to save the rewrite, the post is still on hold. Try again.")
+		return nil, nil, err
+	}
+	h.recordRevision(ctx, post, models.RevisionFeedback, userID, "rewritten to avoid repeating a published post")
+
+	if err := resolve(fmt.Sprintf("✏️ <@%s> asked for a rewrite, new draft posted below", userID)); err != nil {
+		slog.ErrorContext(ctx, "Failed to update duplicate prompt", "post_id", postID, "error", err)
+	}
+
+	header := "*Rewritten Draft*\n_Reworked so it doesn't repeat a published post. Approve and schedule it like any other draft._"
+	return buildDraftBlocks(header, []*models.Post{post}, h.publishTargets), []string{post.ID}, nil
+}
+
+func buildDuplicateBlocks(post *models.Post, match *database.SimilarPost) []slack.Block {
+	header := fmt.Sprintf("⚠️ *A scheduled post is on hold.* It reads %.0f%% like a post published on %s.",
+		match.Similarity*100, publishedDate(match.Post))
+
+	scheduled := "*Held post*"
+	if post.ScheduledAt != nil {
+		scheduled = fmt.Sprintf("*Held post* · _was due %s_", post.ScheduledAt.In(scheduleLocation()).Format("Jan 02 15:04"))
+	}
+
+	publish := slack.NewButtonBlockElement(actionPublishDuplicate, duplicateValue(post.ID, match.Post.ID),
+		slack.NewTextBlockObject(slack.PlainTextType, "Publish anyway", false, false))
+	publish.Style = slack.StylePrimary
+
+	rewrite := slack.NewButtonBlockElement(actionRewriteDuplicate, duplicateValue(post.ID, match.Post.ID),
+		slack.NewTextBlockObject(slack.PlainTextType, "Rewrite", false, false))
+
+	cancel := slack.NewButtonBlockElement(actionCancelDuplicate, duplicateValue(post.ID, match.Post.ID),
+		slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false))
+	cancel.Style = slack.StyleDanger
+
+	footer := "A rewrite comes back as a new draft to approve and schedule. Cancelled posts are archived with the other rejected posts."
+
+	return []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, header, false, false), nil, nil),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, scheduled+"\n"+format.ToSlack(truncate(post.Content, 500)), false, false), nil, nil),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "*Published post*\n"+truncate(match.Post.Content, 500), false, false), nil, nil),
+		slack.NewActionBlock(draftActionsBlockID(post.ID), publish, rewrite, cancel),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, footer, false, false)),
+	}
+}
+
+func publishedDate(post *models.Post) string {
+	if post.PublishedAt == nil {
+		return "an earlier date"
+	}
+	return post.PublishedAt.In(scheduleLocation()).Format("Jan 02, 2006")
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// emojiSettingPrefix followed by a Slack user ID stores the emoji policy
+// of that user's posts.
+const emojiSettingPrefix = "emoji."
+
+var emojiUsage = fmt.Sprintf("Usage: `@LinkedIn Ghostwriter emoji [%s|reset]`", strings.Join(models.EmojiPolicies, "|"))
+
+// withEmojiPolicy returns ctx set up to write posts for userID with the
+// emojis they allow: the policy they set, or the configured one.
+func (h *CommandHandler) withEmojiPolicy(ctx context.Context, userID string) context.Context {
+	return agents.WithEmojiPolicy(ctx, h.userEmojiPolicy(ctx, userID))
+}
+
+func (h *CommandHandler) userEmojiPolicy(ctx context.Context, userID string) string {
+	value, err := h.settings.Get(ctx, emojiSettingPrefix+userID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load emoji setting", "user", userID, "error", err)
+	}
+	if policy, err := models.ParseEmojiPolicy(value); err == nil {
+		return policy
+	}
+	return h.emojiPolicy
+}
+
+// HandleEmoji shows how many emojis userID's posts use, or sets or resets
+// it.
+func (h *CommandHandler) HandleEmoji(ctx context.Context, channelID, userID string, args []string) error {
+	if len(args) == 0 {
+		return h.client.SendMessage(channelID, fmt.Sprintf("Your posts use *%s* emojis.\n%s", h.userEmojiPolicy(ctx, userID), emojiUsage))
+	}
+	if len(args) > 1 {
+		return h.client.SendMessage(channelID, emojiUsage)
+	}
+
+	key := emojiSettingPrefix + userID
+	if strings.EqualFold(args[0], "reset") {
+		if err := h.settings.Delete(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "Failed to reset emoji policy", "user", userID, "error", err)
+			return h.client.SendMessage(channelID, "Failed to reset your emoji policy")
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("Your posts are back to the configured *%s* emojis.", h.emojiPolicy))
+	}
+
+	policy, err := models.ParseEmojiPolicy(args[0])
+	if err != nil {
+		return h.client.SendMessage(channelID, emojiUsage)
+	}
+
+	if err := h.settings.Set(ctx, key, policy, userID); err != nil {
+		slog.ErrorContext(ctx, "Failed to save emoji policy", "user", userID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to save your emoji policy")
+	}
+
+	slog.InfoContext(ctx, "Set emoji policy", "policy", policy, "user", userID)
+	return h.client.SendMessage(channelID, fmt.Sprintf("<@%s>'s posts now use *%s* emojis.", userID, policy))
+}
@@ -0,0 +1,171 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/experiments"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	maxExperimentPairs   = 10
+	recentExperiments    = 5
+	experimentsUsage     = "Start one with `@LinkedIn Ghostwriter experiment [#]`."
+	noExperimentPairsMsg = "No approved variations to compare. Generate a few drafts from the same thought and approve two of them."
+)
+
+// experimentPair is two approved variations of the same thought that can
+// be run as an experiment.
+type experimentPair struct {
+	first, second *models.Post
+}
+
+// HandleExperiment lists the approved variations that can be compared and
+// recent experiments, or with a pair number, schedules that pair a week
+// apart as an experiment.
+func (h *CommandHandler) HandleExperiment(ctx context.Context, channelID, userID string, args []string) error {
+	pairs, err := h.experimentPairs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to find experiment candidates", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch approved posts")
+	}
+
+	if len(args) == 0 {
+		return h.listExperiments(ctx, channelID, pairs)
+	}
+
+	number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || number < 1 || number > len(pairs) {
+		return h.client.SendMessage(channelID, fmt.Sprintf("'%s' is not a pair number. Use `@LinkedIn Ghostwriter experiments` to see them.", args[0]))
+	}
+	pair := pairs[number-1]
+
+	experiment := models.NewExperiment(channelID, userID)
+	if err := h.experimentRepo.Create(ctx, experiment, []string{pair.first.ID, pair.second.ID}); err != nil {
+		if errors.Is(err, database.ErrExperimentPosts) {
+			return h.client.SendMessage(channelID, "One of those variations was changed in the meantime. Use `@LinkedIn Ghostwriter experiments` to see the current pairs.")
+		}
+		slog.ErrorContext(ctx, "Failed to create experiment", "error", err)
+		return h.client.SendMessage(channelID, "Failed to start the experiment")
+	}
+
+	config := agents.ScheduleConfig{
+		PostsPerDay:    2,
+		PreferredTimes: []string{},
+		StartDate:      time.Now().AddDate(0, 0, 1),
+		Timezone:       scheduleTimezone,
+	}
+	if err := h.scheduler.ScheduleExperiment(ctx, config, pair.first, pair.second); err != nil {
+		slog.ErrorContext(ctx, "Failed to schedule experiment", "experiment_id", experiment.ID, "error", err)
+		if err := h.experimentRepo.Conclude(ctx, experiment.ID, nil); err != nil {
+			slog.ErrorContext(ctx, "Failed to cancel experiment", "experiment_id", experiment.ID, "error", err)
+		}
+		return h.client.SendMessage(channelID, "Failed to schedule the experiment. Please try again.")
+	}
+
+	location := scheduleLocation()
+	return h.client.SendMessage(channelID, fmt.Sprintf("🧪 *Experiment started!*\n\n*A* goes out %s\n_%s_\n\n*B* goes out %s\n_%s_\n\nI'll report which did better here once both have had %d days to collect engagement.",
+		pair.first.ScheduledAt.In(location).Format("Mon Jan 02 at 3:04 PM"), truncate(pair.first.Content, 100),
+		pair.second.ScheduledAt.In(location).Format("Mon Jan 02 at 3:04 PM"), truncate(pair.second.Content, 100),
+		experiments.SettleDays))
+}
+
+func (h *CommandHandler) listExperiments(ctx context.Context, channelID string, pairs []experimentPair) error {
+	var b strings.Builder
+
+	if len(pairs) == 0 {
+		b.WriteString(noExperimentPairsMsg + "\n")
+	} else {
+		b.WriteString("*Variations you can compare*\n")
+		for i, pair := range pairs {
+			fmt.Fprintf(&b, "\n%d. *A:* %s\n    *B:* %s\n", i+1,
+				truncate(strings.ReplaceAll(pair.first.Content, "\n", " "), 80),
+				truncate(strings.ReplaceAll(pair.second.Content, "\n", " "), 80))
+		}
+		b.WriteString("\n" + experimentsUsage + " Both go out a week apart at the same time of day.\n")
+	}
+
+	recent, err := h.experimentRepo.List(ctx, recentExperiments)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list experiments", "error", err)
+	}
+	if len(recent) > 0 {
+		b.WriteString("\n*Recent experiments*\n")
+		for _, experiment := range recent {
+			b.WriteString("• " + experimentStatus(experiment) + "\n")
+		}
+	}
+
+	return h.client.SendMessage(channelID, b.String())
+}
+
+// experimentPairs returns pairs of approved posts, not already in an
+// experiment, that were generated from the same thought. The order is
+// stable so a pair number from the list can be used to start it.
+func (h *CommandHandler) experimentPairs(ctx context.Context) ([]experimentPair, error) {
+	approved, err := h.postRepo.GetByStatus(ctx, "approved")
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(approved)
+
+	var pairs []experimentPair
+	seen := make(map[string]bool)
+	for i, first := range approved {
+		if first.ExperimentID != nil {
+			continue
+		}
+		for _, second := range approved[i+1:] {
+			if second.ExperimentID != nil || seen[first.ID+second.ID] || !sharesThought(first, second) {
+				continue
+			}
+			seen[first.ID+second.ID] = true
+			pairs = append(pairs, experimentPair{first: first, second: second})
+			if len(pairs) == maxExperimentPairs {
+				return pairs, nil
+			}
+		}
+	}
+
+	return pairs, nil
+}
+
+func sharesThought(a, b *models.Post) bool {
+	for _, id := range a.SourceThoughtIDs {
+		if slices.Contains(b.SourceThoughtIDs, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func experimentStatus(experiment *models.Experiment) string {
+	started := experiment.CreatedAt.In(scheduleLocation()).Format("Jan 02")
+	hook := ""
+	if len(experiment.Posts) > 0 {
+		hook = ": _" + truncate(strings.ReplaceAll(experiment.Posts[0].Content, "\n", " "), 60) + "_"
+	}
+
+	switch {
+	case experiment.ConcludedAt == nil:
+		return fmt.Sprintf("Started %s, running%s", started, hook)
+	case experiment.WinnerPostID == nil:
+		return fmt.Sprintf("Started %s, no clear winner%s", started, hook)
+	}
+
+	for i, post := range experiment.Posts {
+		if post.ID == *experiment.WinnerPostID {
+			return fmt.Sprintf("Started %s, variation %s won%s", started, experiments.Label(i), hook)
+		}
+	}
+	return fmt.Sprintf("Started %s, finished%s", started, hook)
+}
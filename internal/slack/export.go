@@ -0,0 +1,76 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/export"
+)
+
+const exportUsage = "Usage: `@LinkedIn Ghostwriter export [posts|thoughts|all] [--format csv|json|markdown]`"
+
+// HandleExport uploads every post, and the thoughts this channel sees, as
+// files in the channel. args picks what to export (all by default) and the
+// format (CSV by default).
+func (h *CommandHandler) HandleExport(ctx context.Context, channelID string, args []string) error {
+	what, format := export.All, export.FormatCSV
+
+	for i := 0; i < len(args); i++ {
+		// Slack clients may turn "--" into an em dash.
+		arg := strings.TrimLeft(strings.ToLower(args[i]), "-—")
+		switch {
+		case arg == "format" && i+1 < len(args):
+			i++
+			format = strings.ToLower(args[i])
+		case strings.HasPrefix(arg, "format="):
+			format = strings.TrimPrefix(arg, "format=")
+		case arg == export.Posts || arg == export.Thoughts || arg == export.All:
+			what = arg
+		default:
+			return h.client.SendMessage(channelID, exportUsage)
+		}
+	}
+
+	if format == "md" {
+		format = export.FormatMarkdown
+	}
+	if format != export.FormatCSV && format != export.FormatJSON && format != export.FormatMarkdown {
+		return h.client.SendMessage(channelID, exportUsage)
+	}
+
+	data, err := export.Load(ctx, h.postRepo, h.thoughtRepo, channelID, what)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load export data", "error", err)
+		return h.client.SendMessage(channelID, "Failed to export, couldn't load posts and thoughts")
+	}
+
+	files, err := export.Render(data, format, what)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to render export", "format", format, "error", err)
+		return h.client.SendMessage(channelID, "Failed to export")
+	}
+
+	summary := fmt.Sprintf("📦 Export of %d post(s) and %d thought(s)", len(data.Posts), len(data.Thoughts))
+	switch what {
+	case export.Posts:
+		summary = fmt.Sprintf("📦 Export of %d post(s)", len(data.Posts))
+	case export.Thoughts:
+		summary = fmt.Sprintf("📦 Export of %d thought(s)", len(data.Thoughts))
+	}
+
+	for i, file := range files {
+		comment := ""
+		if i == 0 {
+			comment = summary
+		}
+		if err := h.client.UploadFile(ctx, channelID, file.Name, file.Content, comment); err != nil {
+			slog.ErrorContext(ctx, "Failed to upload export", "file", file.Name, "error", err)
+			return h.client.SendMessage(channelID, "Failed to upload the export. Does the app have the `files:write` scope?")
+		}
+	}
+
+	slog.InfoContext(ctx, "Exported posts and thoughts", "what", what, "format", format, "posts", len(data.Posts), "thoughts", len(data.Thoughts))
+	return nil
+}
@@ -0,0 +1,68 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// maxFactFlags caps the flagged claims listed under a draft.
+const maxFactFlags = 5
+
+// checkFacts runs the optional fact check on a generated draft before it's
+// saved, against the thoughts it was written from. A check that fails is
+// logged and the draft shared without one.
+func (h *CommandHandler) checkFacts(ctx context.Context, post *models.Post, thoughts []*models.Thought) {
+	if h.factChecker == nil || len(thoughts) == 0 {
+		return
+	}
+
+	text := post.Content
+	if len(post.Slides) > 0 {
+		text += "\n\n" + strings.Join(post.Slides, "\n\n")
+	}
+	if post.Poll != nil {
+		text += "\n\n" + post.Poll.Question + "\n" + strings.Join(post.Poll.Options, "\n")
+	}
+
+	check, err := h.factChecker.Check(ctx, text, thoughts)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fact-check draft", "error", err)
+		return
+	}
+	post.FactCheck = check
+}
+
+// formatFactCheck lists the claims of a draft its thoughts don't back up,
+// or says they all checked out. It returns "" for a draft that wasn't
+// checked.
+func formatFactCheck(post *models.Post) string {
+	check := post.FactCheck
+	if check == nil {
+		return ""
+	}
+	if len(check.Flags) == 0 {
+		if check.Checked == 0 {
+			return "🧐 *Fact check:* no factual claims"
+		}
+		return fmt.Sprintf("🧐 *Fact check:* all %d claim(s) found in the source thoughts", check.Checked)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "⚠️ *Fact check:* %d of %d claim(s) not found in the source thoughts, check them before approving", len(check.Flags), check.Checked)
+	for i, flag := range check.Flags {
+		if i == maxFactFlags {
+			fmt.Fprintf(&b, "\n_...and %d more_", len(check.Flags)-maxFactFlags)
+			break
+		}
+		fmt.Fprintf(&b, "\n• “%s” — %s", format.ToSlack(truncate(flag.Claim, 150)), flag.Verdict)
+		if flag.Note != "" {
+			fmt.Fprintf(&b, ": %s", format.ToSlack(truncate(flag.Note, 150)))
+		}
+	}
+	return b.String()
+}
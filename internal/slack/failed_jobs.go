@@ -0,0 +1,156 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const retryUsage = "Usage: `@LinkedIn Ghostwriter retry failed`"
+
+// generate runs a generate command, such as "generate poll hiring", and
+// shares the drafts it writes.
+func (h *MessageHandler) generate(ctx context.Context, channelID, userID, command string) error {
+	topic, count, err := cutVariationsFlag(strings.TrimSpace(strings.TrimPrefix(command, "generate")))
+	if err != nil {
+		return h.client.SendMessage(channelID, variationsUsage)
+	}
+	if count > 0 {
+		ctx = withVariations(ctx, count)
+	}
+	topic, templateName, hasTemplate, err := cutFlag(topic, "template")
+	if err != nil {
+		return h.client.SendMessage(channelID, templatesUsage)
+	}
+	ctx, topic, err = cutLanguageFlag(ctx, topic)
+	if err != nil {
+		return h.client.SendMessage(channelID, languageUsage)
+	}
+	ctx, topic, err = cutLengthFlag(ctx, topic)
+	if err != nil {
+		return h.client.SendMessage(channelID, lengthUsage)
+	}
+	_, hasLength := agents.PostLengthFrom(ctx)
+
+	generate := h.commandHandler.HandleGenerateDraft
+	postType, rest, _ := strings.Cut(topic, " ")
+	switch postType {
+	case models.PostTypeCarousel:
+		generate, topic = h.commandHandler.HandleGenerateCarousel, strings.TrimSpace(rest)
+	case models.PostTypePoll:
+		generate, topic = h.commandHandler.HandleGeneratePoll, strings.TrimSpace(rest)
+	}
+
+	if hasLength && (postType == models.PostTypeCarousel || postType == models.PostTypePoll) {
+		return h.client.SendMessage(channelID, "Length targets shape text posts, so `--length` and `--words` can't be used with carousels or polls.")
+	}
+
+	if hasTemplate {
+		if postType == models.PostTypeCarousel || postType == models.PostTypePoll {
+			return h.client.SendMessage(channelID, "Templates shape text posts, so `--template` can't be used with carousels or polls.")
+		}
+		template, err := h.commandHandler.postTemplate(ctx, channelID, templateName)
+		if errors.Is(err, database.ErrTemplateNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ctx = agents.WithTemplate(ctx, template)
+	}
+
+	if topic == "" {
+		topic = "all"
+	}
+
+	ctx = withProgress(ctx)
+	blocks, postIDs, err := generate(ctx, channelID, userID, topic)
+	if err != nil {
+		return err
+	}
+
+	return h.approvalHandler.ShareDrafts(ctx, channelID, blocks, postIDs)
+}
+
+func (h *MessageHandler) recordFailedJob(ctx context.Context, job *models.FailedJob) {
+	if err := h.failedJobRepo.Create(ctx, job); err != nil {
+		slog.ErrorContext(ctx, "Failed to record failed job", "kind", job.Kind, "error", err)
+	}
+}
+
+// RunFailedJob runs a failed categorization or generation again.
+func (h *MessageHandler) RunFailedJob(ctx context.Context, job *models.FailedJob) error {
+	switch job.Kind {
+	case models.FailedCategorize:
+		return h.recategorizeFailed(ctx, job.ThoughtID)
+
+	case models.FailedGenerate:
+		h.client.SendMessage(job.ChannelID, fmt.Sprintf("🔁 Retrying `%s` from earlier...", job.Request))
+		err := h.generate(agents.WithCommand(database.WithActor(ctx, job.UserID), "generate"), job.ChannelID, job.UserID, job.Request)
+		if errors.Is(err, ErrNoThoughts) || errors.Is(err, agents.ErrBudgetExceeded) {
+			// The user was told there's nothing to write from, or no
+			// budget left; retrying won't change that.
+			return nil
+		}
+		return err
+	}
+
+	return fmt.Errorf("unknown job kind %q", job.Kind)
+}
+
+// recategorizeFailed categorizes a thought saved as uncategorized when the
+// categorizer failed. A category someone has picked since is kept and only
+// the tags are replaced.
+func (h *MessageHandler) recategorizeFailed(ctx context.Context, thoughtID string) error {
+	thought, err := h.thoughtRepo.GetByID(ctx, thoughtID)
+	if err != nil {
+		// Deleted or archived since: there is nothing left to categorize.
+		slog.InfoContext(ctx, "Skipping categorization of missing thought", "thought_id", thoughtID, "error", err)
+		return nil
+	}
+
+	category := thought.Category
+	if err := h.categorizer.CategorizeThought(ctx, thought); err != nil {
+		return err
+	}
+	if category != "uncategorized" {
+		thought.Category = category
+	}
+
+	return h.thoughtRepo.Update(ctx, thought)
+}
+
+// HandleRetry makes every failed job due again, including those the retry
+// worker gave up on, and lists them.
+func (h *MessageHandler) HandleRetry(ctx context.Context, channelID string, args []string) error {
+	if len(args) != 1 || args[0] != "failed" {
+		return h.client.SendMessage(channelID, retryUsage)
+	}
+
+	jobs, err := h.failedJobRepo.GetOpen(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list failed jobs", "error", err)
+		return h.client.SendMessage(channelID, "Failed to list failed jobs")
+	}
+	if len(jobs) == 0 {
+		return h.client.SendMessage(channelID, "Nothing has failed. ✅")
+	}
+
+	if _, err := h.failedJobRepo.RetryAll(ctx); err != nil {
+		slog.ErrorContext(ctx, "Failed to retry failed jobs", "error", err)
+		return h.client.SendMessage(channelID, "Failed to retry failed jobs")
+	}
+
+	message := fmt.Sprintf("🔁 Retrying %d failed job(s) within a minute:\n", len(jobs))
+	for _, job := range jobs {
+		message += fmt.Sprintf("• %s · %d attempt(s) · _%s_\n", job.Describe(), job.Attempts, truncate(job.Error, 100))
+	}
+
+	return h.client.SendMessage(channelID, message)
+}
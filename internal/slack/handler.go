@@ -2,15 +2,21 @@ package slack
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 	"strings"
 
 	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
 	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/queue"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/sources"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type MessageHandler struct {
@@ -19,6 +25,13 @@ type MessageHandler struct {
 	categorizer     *agents.CategorizerAgent
 	commandHandler  *CommandHandler
 	approvalHandler *ApprovalHandler
+	embeddings      *agents.EmbeddingAgent
+	summarizer      *agents.SummarizerAgent
+	fetcher         *sources.Fetcher
+	checkInRepo     *database.CheckInRepository
+	failedJobRepo   *database.FailedJobRepository
+	jobs            *queue.Queue
+	roles           *Roles
 }
 
 func NewMessageHandler(
@@ -27,6 +40,13 @@ func NewMessageHandler(
 	categorizer *agents.CategorizerAgent,
 	commandHandler *CommandHandler,
 	approvalHandler *ApprovalHandler,
+	embeddings *agents.EmbeddingAgent,
+	summarizer *agents.SummarizerAgent,
+	fetcher *sources.Fetcher,
+	checkInRepo *database.CheckInRepository,
+	failedJobRepo *database.FailedJobRepository,
+	jobs *queue.Queue,
+	roles *Roles,
 ) *MessageHandler {
 	return &MessageHandler{
 		client:          client,
@@ -34,6 +54,13 @@ func NewMessageHandler(
 		categorizer:     categorizer,
 		commandHandler:  commandHandler,
 		approvalHandler: approvalHandler,
+		embeddings:      embeddings,
+		summarizer:      summarizer,
+		fetcher:         fetcher,
+		checkInRepo:     checkInRepo,
+		failedJobRepo:   failedJobRepo,
+		jobs:            jobs,
+		roles:           roles,
 	}
 }
 
@@ -46,7 +73,8 @@ func (h *MessageHandler) HandleMessage(ctx context.Context, event *slackevents.M
 		return nil
 	}
 
-	if event.SubType != "" {
+	// file_share is a normal message that also carries attachments.
+	if event.SubType != "" && event.SubType != "file_share" {
 		return nil
 	}
 
@@ -54,12 +82,12 @@ func (h *MessageHandler) HandleMessage(ctx context.Context, event *slackevents.M
 		return nil
 	}
 
-	if event.ThreadTimeStamp != "" && event.ThreadTimeStamp != event.TimeStamp {
+	if strings.HasPrefix(strings.TrimSpace(event.Text), "<@") {
 		return nil
 	}
 
-	if strings.HasPrefix(strings.TrimSpace(event.Text), "<@") {
-		return nil
+	if event.ThreadTimeStamp != "" && event.ThreadTimeStamp != event.TimeStamp {
+		return h.handleThreadReply(ctx, event)
 	}
 
 	text := strings.ToLower(strings.TrimSpace(event.Text))
@@ -70,213 +98,500 @@ func (h *MessageHandler) HandleMessage(ctx context.Context, event *slackevents.M
 		}
 	}
 
+	// Checked after commands, so a command sent while a check-in waits
+	// isn't taken as the answer.
+	if event.ChannelType == "im" {
+		if handled, err := h.handleCheckInReply(ctx, event); handled || err != nil {
+			return err
+		}
+	}
+
 	thought := models.NewThought(event.Text, "slack")
+	thought.SlackChannelID = event.Channel
+	thought.SlackThreadTS = event.TimeStamp
 
-	if err := h.categorizer.CategorizeThought(ctx, thought); err != nil {
+	var files []slack.File
+	if event.Message != nil {
+		files = event.Message.Files
+	}
+
+	return h.Capture(ctx, channel{client: h.client, id: event.Channel}, thought, h.sharedFiles(files))
+}
+
+// captureThought categorizes and saves a new thought. A category already set
+// on the thought is kept and only its tags are chosen. When embeddings are
+// enabled and the thought is a near-duplicate of an existing one, nothing is
+// saved and the existing thought is returned instead. A thought the
+// categorizer fails on is saved as uncategorized and categorized again
+// later.
+func (h *MessageHandler) captureThought(ctx context.Context, thought *models.Thought) (*database.SimilarThought, error) {
+	if h.embeddings != nil {
+		duplicate, err := h.embeddings.Match(ctx, thought)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to match thought embeddings", "error", err)
+		} else if duplicate != nil {
+			return duplicate, nil
+		}
+	}
+
+	category := thought.Category
+	categorizeErr := h.categorizer.CategorizeBatched(ctx, thought)
+	if categorizeErr != nil {
+		slog.ErrorContext(ctx, "Failed to categorize thought", "error", categorizeErr)
 		thought.Category = "uncategorized"
 		thought.TopicTags = []string{"general"}
 	}
+	if category != "" {
+		thought.Category = category
+	}
 
 	if err := h.thoughtRepo.Create(ctx, thought); err != nil {
-		log.Printf("Failed to save thought: %v", err)
-		return err
+		slog.ErrorContext(ctx, "Failed to save thought", "error", err)
+		return nil, err
 	}
 
-	confirmationMsg := fmt.Sprintf("Got it! Categorized as: *%s* | Tags: %s",
-		thought.Category,
-		strings.Join(thought.TopicTags, ", "))
+	if categorizeErr != nil {
+		job := models.NewFailedJob(models.FailedCategorize, thought.SlackChannelID, "", "", categorizeErr)
+		job.ThoughtID = thought.ID
+		h.recordFailedJob(ctx, job)
+	}
 
-	if err := h.client.SendMessage(event.Channel, confirmationMsg); err != nil {
-		log.Printf("Failed to send confirmation: %v", err)
+	if h.embeddings != nil {
+		if err := h.embeddings.Link(ctx, thought); err != nil {
+			slog.ErrorContext(ctx, "Failed to store thought embedding", "error", err)
+		}
 	}
 
-	return nil
+	return nil, nil
+}
+
+func duplicateMessage(duplicate *database.SimilarThought) string {
+	message := fmt.Sprintf("Looks like you already captured this on %s:\n> %s\n\n",
+		duplicate.Thought.Timestamp.Format("Jan 2"),
+		truncate(duplicate.Thought.Content, 200))
+	message += "I didn't save it again. Reply in that thought's thread to add more context."
+	return message
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
+}
+
+func relatedSuffix(thought *models.Thought) string {
+	if len(thought.RelatedThoughts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" | Related to %d earlier thought(s)", len(thought.RelatedThoughts))
+}
+
+func (h *MessageHandler) handleThreadReply(ctx context.Context, event *slackevents.MessageEvent) error {
+	thought, err := h.thoughtRepo.GetBySlackThread(ctx, event.Channel, event.ThreadTimeStamp)
+	if err != nil {
+		return err
+	}
+	if thought == nil {
+		return nil
+	}
+
+	if err := h.thoughtRepo.AppendContext(ctx, thought.ID, strings.TrimSpace(event.Text)); err != nil {
+		slog.ErrorContext(ctx, "Failed to append thread context", "error", err)
+		return err
+	}
+
+	return h.client.SendThreadMessage(event.Channel, event.ThreadTimeStamp, "Added this to the original thought.")
 }
 
 func (h *MessageHandler) HandleAppMention(ctx context.Context, event *slackevents.AppMentionEvent) error {
 	text := strings.TrimSpace(strings.Replace(event.Text, "<@"+h.client.GetBotID()+">", "", 1))
 
-	if strings.HasPrefix(text, "help") {
-		return h.sendHelpMessage(event.Channel)
+	if fields := strings.Fields(text); len(fields) > 0 {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("slack.command", strings.ToLower(fields[0])))
+		ctx = agents.WithCommand(ctx, strings.ToLower(fields[0]))
+		h.commandHandler.recordCommand(ctx, text)
 	}
 
-	if strings.HasPrefix(text, "stats") {
-		return h.sendStatsMessage(ctx, event.Channel)
+	// The role is checked and the handler picked from the same word, so a
+	// command can't pass as another one.
+	command, rest := cutWord(text)
+	command = strings.ToLower(command)
+	if !h.roles.authorize(ctx, event.Channel, event.User, commandRole(command, strings.Fields(strings.ToLower(rest))), "run `"+command+"`") {
+		return nil
 	}
 
-	if strings.HasPrefix(text, "generate") {
-		parts := strings.Fields(text)
+	if handled, err := h.runCommand(ctx, event, command, rest); handled || err != nil {
+		return err
+	}
 
-		if len(parts) == 1 {
-			message, postIDs, err := h.commandHandler.HandleGenerateDraft(ctx, event.Channel, "all")
-			if err != nil {
-				return err
-			}
+	if text != "" {
+		thought := models.NewThought(text, "slack")
+		thought.SlackChannelID = event.Channel
+		thought.SlackThreadTS = event.TimeStamp
 
-			messageTS, err := h.sendMessageAndGetTS(event.Channel, message)
-			if err != nil {
-				return err
-			}
+		h.enrichThought(ctx, text, nil, thought)
 
-			h.approvalHandler.StoreDraftMessage(messageTS, postIDs)
-			return nil
+		duplicate, err := h.captureThought(ctx, thought)
+		if err != nil {
+			return err
+		}
+		if duplicate != nil {
+			return h.client.SendMessage(event.Channel, duplicateMessage(duplicate))
 		}
 
-		topic := strings.Join(parts[1:], " ")
+		confirmationMsg := fmt.Sprintf("Captured! Category: *%s* | Tags: %s",
+			thought.Category,
+			strings.Join(thought.TopicTags, ", "))
+		if thought.SourceURL != "" {
+			confirmationMsg += fmt.Sprintf("\nSummarized: %s", sourceLabel(thought))
+		}
+		confirmationMsg += relatedSuffix(thought)
 
-		thoughts, err := h.thoughtRepo.GetByCategory(ctx, topic)
-		if err == nil && len(thoughts) > 0 {
-			message, postIDs, err := h.commandHandler.HandleGenerateDraft(ctx, event.Channel, topic)
-			if err != nil {
-				return err
-			}
+		return h.client.SendMessage(event.Channel, confirmationMsg)
+	}
 
-			messageTS, err := h.sendMessageAndGetTS(event.Channel, message)
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			h.approvalHandler.StoreDraftMessage(messageTS, postIDs)
-			return nil
+// runCommand runs the mention command named command, lowercased, with the
+// rest of the message as rest. It reports false when command isn't one, so
+// the message is captured as a thought.
+func (h *MessageHandler) runCommand(ctx context.Context, event *slackevents.AppMentionEvent, command, rest string) (bool, error) {
+	args := strings.Fields(rest)
+	sub, subRest := cutWord(rest)
+	sub = strings.ToLower(sub)
+
+	switch command {
+	case "help":
+		return true, h.sendHelpMessage(ctx, event.Channel)
+
+	case "stats":
+		return true, h.commandHandler.HandleStats(ctx, event.Channel)
+
+	case "usage":
+		return true, h.commandHandler.HandleUsage(ctx, event.Channel, args)
+
+	case "performance":
+		return true, h.commandHandler.HandlePerformance(ctx, event.Channel)
+
+	case "published":
+		return true, h.commandHandler.HandlePublished(ctx, event.Channel, args)
+
+	case "replies":
+		return true, h.commandHandler.HandleReplies(ctx, event.Channel, event.User, args)
+
+	case "generate":
+		return true, h.enqueue(ctx, event.Channel, models.JobGenerate, generateJob{
+			ChannelID: event.Channel,
+			UserID:    event.User,
+			Command:   strings.TrimSpace("generate " + rest),
+		})
+
+	case "develop":
+		threadTS := event.ThreadTimeStamp
+		if threadTS == event.TimeStamp {
+			threadTS = ""
 		}
 
-		offerMsg := fmt.Sprintf("I don't have any thoughts categorized as '%s' yet.\n\n", topic)
-		offerMsg += "Would you like me to brainstorm ideas on this topic?\n\n"
-		offerMsg += fmt.Sprintf("Use: `@LinkedIn Ghostwriter brainstorm %s`", topic)
+		angle, count, err := cutVariationsFlag(rest)
+		if err != nil {
+			return true, h.client.SendMessage(event.Channel, variationsUsage)
+		}
+		if count > 0 {
+			ctx = withVariations(ctx, count)
+		}
+		ctx, angle, err := cutLanguageFlag(ctx, angle)
+		if err != nil {
+			return true, h.client.SendMessage(event.Channel, languageUsage)
+		}
+		ctx, angle, err = cutLengthFlag(ctx, angle)
+		if err != nil {
+			return true, h.client.SendMessage(event.Channel, lengthUsage)
+		}
 
-		return h.client.SendMessage(event.Channel, offerMsg)
-	}
+		blocks, postIDs, err := h.commandHandler.HandleDevelop(ctx, event.Channel, threadTS, event.User, strings.TrimSpace(angle))
+		if errors.Is(err, ErrNoBrainstorm) {
+			return true, nil
+		}
+		if err != nil {
+			return true, err
+		}
 
-	if strings.HasPrefix(text, "drafts") {
-		return h.commandHandler.HandleListDrafts(ctx, event.Channel)
-	}
+		return true, h.approvalHandler.ShareDrafts(ctx, event.Channel, blocks, postIDs)
 
-	if strings.HasPrefix(text, "schedule") {
-		parts := strings.Fields(text)
-		args := []string{}
-		if len(parts) > 1 {
-			args = parts[1:]
+	case "revise":
+		number, feedback := cutWord(rest)
+		if feedback == "" {
+			return true, h.client.SendMessage(event.Channel, "Usage: `@LinkedIn Ghostwriter revise [draft #] [feedback]`")
 		}
-		return h.commandHandler.HandleSchedule(ctx, event.Channel, args)
-	}
 
-	if strings.HasPrefix(text, "view schedule") || strings.HasPrefix(text, "show schedule") {
+		blocks, postIDs, err := h.commandHandler.HandleRevise(ctx, event.Channel, event.User, number, feedback)
+		if err != nil {
+			return true, err
+		}
+
+		return true, h.approvalHandler.ShareDrafts(ctx, event.Channel, blocks, postIDs)
+
+	case "translate":
+		blocks, postIDs, err := h.commandHandler.HandleTranslate(ctx, event.Channel, event.User, args)
+		if err != nil || len(postIDs) == 0 {
+			return true, err
+		}
+
+		return true, h.approvalHandler.ShareDrafts(ctx, event.Channel, blocks, postIDs)
+
+	case "history":
+		return true, h.commandHandler.HandleHistory(ctx, event.Channel, event.User, args)
+
+	case "audit":
+		return true, h.commandHandler.HandleAudit(ctx, event.Channel, args)
+
+	case "users":
+		return true, h.commandHandler.HandleUsers(ctx, event.Channel, event.User, args)
+
+	case "drafts":
+		return true, h.commandHandler.HandleListDrafts(ctx, event.Channel)
+
+	case "trash":
+		return true, h.commandHandler.HandleTrash(ctx, event.Channel)
+
+	case "restore":
+		return true, h.commandHandler.HandleRestore(ctx, event.Channel, args)
+
+	case "schedule":
+		return true, h.commandHandler.HandleSchedule(ctx, event.Channel, event.User, args)
+
+	case "view", "show":
+		if sub != "schedule" {
+			break
+		}
 		days := 7
-		parts := strings.Fields(text)
-		if len(parts) > 2 {
-			fmt.Sscanf(parts[2], "%d", &days)
+		if dayArgs := strings.Fields(subRest); len(dayArgs) > 0 {
+			fmt.Sscanf(dayArgs[0], "%d", &days)
 		}
-		return h.commandHandler.HandleViewSchedule(ctx, event.Channel, days)
-	}
+		return true, h.commandHandler.HandleViewSchedule(ctx, event.Channel, days)
+
+	case "reschedule":
+		return true, h.commandHandler.HandleReschedule(ctx, event.Channel, event.User, args)
+
+	case "unschedule":
+		return true, h.commandHandler.HandleUnschedule(ctx, event.Channel, event.User, args)
+
+	case "undo":
+		return true, h.approvalHandler.HandleUndo(ctx, event.Channel, event.User)
+
+	case "pause":
+		return true, h.commandHandler.HandlePause(ctx, event.Channel, event.User, rest)
 
-	if strings.HasPrefix(text, "brainstorm") {
-		topic := strings.TrimPrefix(text, "brainstorm")
-		topic = strings.TrimSpace(topic)
-		if topic == "" {
-			return h.client.SendMessage(event.Channel, "Please provide a topic: `@LinkedIn Ghostwriter brainstorm [your topic]`")
+	case "resume":
+		return true, h.commandHandler.HandleResume(ctx, event.Channel, event.User)
+
+	case "blackout":
+		return true, h.commandHandler.HandleBlackout(ctx, event.Channel, event.User, rest)
+
+	case "brainstorm":
+		if rest == "" {
+			return true, h.client.SendMessage(event.Channel, "Please provide a topic: `@LinkedIn Ghostwriter brainstorm [your topic]`")
+		}
+		return true, h.enqueue(ctx, event.Channel, models.JobBrainstorm, brainstormJob{
+			ChannelID: event.Channel,
+			Topic:     rest,
+		})
+
+	case "sync":
+		switch sub {
+		case "linear":
+			return true, h.commandHandler.HandleLinearSync(ctx, event.Channel, linearSyncDays(subRest))
+		case "notion":
+			return true, h.commandHandler.HandleNotionSync(ctx, event.Channel)
 		}
-		return h.commandHandler.HandleBrainstorm(ctx, event.Channel, topic)
-	}
 
-	if strings.HasPrefix(text, "sync linear") || strings.HasPrefix(text, "linear sync") {
-		return h.commandHandler.HandleLinearSync(ctx, event.Channel)
-	}
+	case "linear":
+		switch sub {
+		case "sync":
+			return true, h.commandHandler.HandleLinearSync(ctx, event.Channel, linearSyncDays(subRest))
+		case "filter":
+			return true, h.commandHandler.HandleLinearFilter(ctx, event.Channel, event.User, subRest)
+		}
 
-	if text != "" {
-		thought := models.NewThought(text, "slack")
+	case "notion":
+		if sub == "sync" {
+			return true, h.commandHandler.HandleNotionSync(ctx, event.Channel)
+		}
 
-		if err := h.categorizer.CategorizeThought(ctx, thought); err != nil {
-			thought.Category = "uncategorized"
-			thought.TopicTags = []string{"general"}
+	case "recap":
+		recapArgs, count, err := cutVariationsFlag(rest)
+		if err != nil {
+			return true, h.client.SendMessage(event.Channel, variationsUsage)
+		}
+		ctx, recapArgs, err := cutLanguageFlag(withProgress(ctx), recapArgs)
+		if err != nil {
+			return true, h.client.SendMessage(event.Channel, languageUsage)
+		}
+		ctx, recapArgs, err = cutLengthFlag(ctx, recapArgs)
+		if err != nil {
+			return true, h.client.SendMessage(event.Channel, lengthUsage)
+		}
+		if count > 0 {
+			ctx = withVariations(ctx, count)
+		}
+		blocks, postIDs, err := h.commandHandler.HandleRecap(ctx, event.Channel, event.User, recapArgs)
+		if err != nil || len(postIDs) == 0 {
+			return true, err
 		}
 
-		if err := h.thoughtRepo.Create(ctx, thought); err != nil {
-			log.Printf("Failed to save thought: %v", err)
-			return err
+		return true, h.approvalHandler.ShareDrafts(ctx, event.Channel, blocks, postIDs)
+
+	case "search":
+		if rest == "" {
+			return true, h.client.SendMessage(event.Channel, "Please provide a query: `@LinkedIn Ghostwriter search [query]`")
 		}
+		return true, h.commandHandler.HandleSearch(ctx, event.Channel, rest)
 
-		confirmationMsg := fmt.Sprintf("Captured! Category: *%s* | Tags: %s",
-			thought.Category,
-			strings.Join(thought.TopicTags, ", "))
+	case "workspace":
+		return true, h.commandHandler.HandleWorkspace(ctx, event.Channel, rest)
 
-		return h.client.SendMessage(event.Channel, confirmationMsg)
+	case "language":
+		return true, h.commandHandler.HandleLanguage(ctx, event.Channel, event.User, args)
+
+	case "emoji":
+		return true, h.commandHandler.HandleEmoji(ctx, event.Channel, event.User, args)
+
+	case "variations":
+		return true, h.commandHandler.HandleVariations(ctx, event.Channel, event.User, args)
+
+	case "model":
+		return true, h.commandHandler.HandleModel(ctx, event.Channel, event.User, args)
+
+	case "experiment":
+		return true, h.commandHandler.HandleExperiment(ctx, event.Channel, event.User, args)
+
+	case "export":
+		return true, h.commandHandler.HandleExport(ctx, event.Channel, args)
+
+	case "import":
+		return true, h.commandHandler.HandleImport(ctx, event.Channel, event.User, event.TimeStamp)
+
+	case "templates":
+		return true, h.commandHandler.HandleTemplates(ctx, event.Channel, event.User, rest)
+
+	case "categories":
+		return true, h.commandHandler.HandleCategories(ctx, event.Channel, event.User, rest)
+
+	case "contacts":
+		return true, h.commandHandler.HandleContacts(ctx, event.Channel, event.User, rest)
+
+	case "checkin":
+		return true, h.commandHandler.HandleCheckIn(ctx, event.Channel, event.User, rest)
+
+	case "recategorize":
+		return true, h.commandHandler.HandleRecategorize(ctx, event.Channel, event.User, rest)
+
+	case "retry":
+		return true, h.HandleRetry(ctx, event.Channel, args)
+
+	case "retag":
+		return true, h.commandHandler.HandleRetag(ctx, event.Channel, event.User, rest)
+
+	case "prompt":
+		return true, h.commandHandler.HandlePrompt(ctx, event.Channel, event.User, rest)
+
+	case "learn-style":
+		return true, h.commandHandler.HandleLearnStyle(ctx, event.Channel, event.User, rest)
+
+	case "connect":
+		if sub == "linkedin" {
+			return true, h.commandHandler.HandleConnectLinkedIn(ctx, event.Channel, event.User, strings.EqualFold(subRest, "page"))
+		}
 	}
 
-	return nil
+	return false, nil
 }
 
-func (h *MessageHandler) sendMessageAndGetTS(channelID, message string) (string, error) {
-	_, timestamp, err := h.client.GetAPI().PostMessage(
-		channelID,
-		slack.MsgOptionText(message, false),
-	)
-	return timestamp, err
+// linearSyncDays reads how many days back `sync linear` looks, 7 unless
+// args starts with a number from 1 to 90.
+func linearSyncDays(args string) int {
+	if fields := strings.Fields(args); len(fields) > 0 {
+		if n, err := strconv.Atoi(fields[0]); err == nil && n > 0 && n <= 90 {
+			return n
+		}
+	}
+	return 7
 }
 
-func (h *MessageHandler) sendHelpMessage(channelID string) error {
+func (h *MessageHandler) sendHelpMessage(ctx context.Context, channelID string) error {
 	helpText := `*LinkedIn Ghostwriter Bot*
 
 I capture your thoughts and help generate LinkedIn posts!
 
 *Commands:*
 - \@LinkedIn Ghostwriter generate - Generate from recent thoughts
-- \@LinkedIn Ghostwriter generate [topic] - Generate from specific topic
+- \@LinkedIn Ghostwriter generate [topic] - Generate from thoughts about a category, tag or keyword (add --length short|medium|long or --words N to change the length)
+- \@LinkedIn Ghostwriter generate --template [name] [topic] - Generate drafts that follow a post template, such as contrarian or failure-story
+- \@LinkedIn Ghostwriter generate carousel [topic] - Generate a 6-8 slide carousel, published as a PDF document
+- \@LinkedIn Ghostwriter generate poll [topic] - Generate a LinkedIn poll with 2-4 options
 - \@LinkedIn Ghostwriter brainstorm [topic] - Brainstorm ideas
+- \@LinkedIn Ghostwriter develop [angle #] - In a brainstorm thread, turn an angle into drafts
+- \@LinkedIn Ghostwriter search [query] - Find past thoughts
 - \@LinkedIn Ghostwriter drafts - View pending drafts
+- \@LinkedIn Ghostwriter revise [draft #] [feedback] - Revise a draft with your feedback
+- \@LinkedIn Ghostwriter translate [draft #] [language] - Write a draft in another language, such as de or hi, as a new draft
+- \@LinkedIn Ghostwriter undo - Revert your last approval or rejection (removing the reaction works too, for 15 minutes)
+- \@LinkedIn Ghostwriter history [draft #] - See earlier versions of a draft and restore one
+- \@LinkedIn Ghostwriter audit [draft #|scheduled #|post ID] - See who changed a post and when, and every attempt to publish it
+- \@LinkedIn Ghostwriter trash - See deleted thoughts and posts
+- \@LinkedIn Ghostwriter restore [ID] - Bring a thought or post back from the trash
 - \@LinkedIn Ghostwriter schedule [1-4] - Schedule approved posts
 - \@LinkedIn Ghostwriter view schedule - See posting schedule
-- \@LinkedIn Ghostwriter stats - Show statistics
+- \@LinkedIn Ghostwriter reschedule [post #] [date time] - Move a scheduled post
+- \@LinkedIn Ghostwriter unschedule [post #] - Remove a post from the schedule
+- \@LinkedIn Ghostwriter pause [until date] - Stop publishing until a date, or until resumed
+- \@LinkedIn Ghostwriter resume - Publish again, including posts that came due while paused
+- \@LinkedIn Ghostwriter blackout [add date [to date][: reason]|remove #] - List or manage dates nothing is scheduled or published on
+- \@LinkedIn Ghostwriter experiments - List approved variations to compare and recent experiments
+- \@LinkedIn Ghostwriter experiment [#] - Publish two variations a week apart and report which did better
+- \@LinkedIn Ghostwriter stats - Show weekly stats, approval rate and trends
+- \@LinkedIn Ghostwriter usage [month] - Show LLM tokens and estimated cost by command, user and model
+- \@LinkedIn Ghostwriter performance - Best and worst posts of the last 30 days
+- \@LinkedIn Ghostwriter published [month] - Browse published posts with their metrics
+- \@LinkedIn Ghostwriter replies [post #] - Draft replies in your voice to new comments on a published post
+- \@LinkedIn Ghostwriter learn-style [posts] - Learn your style from past posts (separate with ---)
+- \@LinkedIn Ghostwriter sync linear [days] - Capture recently completed Linear issues
+- \@LinkedIn Ghostwriter recap cycle [team] - Draft a "what we shipped this sprint" post from the last completed Linear cycle
+- \@LinkedIn Ghostwriter linear filter [teams|projects|labels|estimate|reset] [value] - Choose which Linear issues become thoughts
+- \@LinkedIn Ghostwriter sync notion - Import Notion ideas and update the Notion content calendar
+- \@LinkedIn Ghostwriter workspace [shared|isolated] - Show or change whether this channel shares its thoughts
+- \@LinkedIn Ghostwriter model [generation|categorizer] [model] - Show or switch the LLM models
+- \@LinkedIn Ghostwriter variations [1-5|reset] - Show or set how many variations each generation writes
+- \@LinkedIn Ghostwriter language [code|reset] - Show or set the language your posts are written in
+- \@LinkedIn Ghostwriter emoji [none|minimal|expressive|reset] - Show or set how many emojis your posts use
+- \@LinkedIn Ghostwriter export [posts|thoughts|all] [--format csv|json|markdown] - Download posts and thoughts as a file
+- \@LinkedIn Ghostwriter import - Import the notes in an attached CSV or JSON file
+- \@LinkedIn Ghostwriter categories [add|rename|remove|rules] [name] - List or manage the categories thoughts are filed under
+- \@LinkedIn Ghostwriter templates [show|add|edit|remove] [name] - List or manage the post templates drafts can follow
+- \@LinkedIn Ghostwriter contacts [add|remove] [name] - List or manage the people and companies posts tag
+- \@LinkedIn Ghostwriter checkin [on [HH:MM]|off] - Get a daily DM asking what you worked on, with replies saved as thoughts here
+- \@LinkedIn Ghostwriter recategorize [#] [category] - Move a recent thought to another category, or let the categorizer pick again
+- \@LinkedIn Ghostwriter recategorize uncategorized - Re-run the categorizer on every uncategorized thought
+- \@LinkedIn Ghostwriter retag [#] [tag, tag...] - Replace a recent thought's tags, or let the categorizer pick them again
+- \@LinkedIn Ghostwriter retry failed - Retry failed categorizations and generations now
+- \@LinkedIn Ghostwriter prompt [show|set|reset] [generate|brainstorm|categorize|categorize-batch] - View or tune the prompt templates
+- \@LinkedIn Ghostwriter users [add|remove] [@user] [owner|editor|viewer] - List or manage who can write, approve and schedule posts
+- \@LinkedIn Ghostwriter connect linkedin - Connect your LinkedIn account
+- \@LinkedIn Ghostwriter connect linkedin page - Connect the company page you administer
 - \@LinkedIn Ghostwriter help - Show this help
 
 *Workflow:*
-1. Share thoughts naturally
+1. Share thoughts naturally (reply in a thought's thread to add more context)
 2. Generate posts: \@LinkedIn Ghostwriter generate
-3. React with 1️⃣ 2️⃣ 3️⃣ or ✅ to approve
+3. Click Approve, Reject or Edit on each variation
 4. Schedule: \@LinkedIn Ghostwriter schedule 2 (2 posts/day)
 5. Posts publish automatically!
 
 *Categories:*
-technical, business, learning, product_update, personal, industry_insight, milestone`
+` + h.commandHandler.categoryNames(ctx)
 
 	return h.client.SendMessage(channelID, helpText)
 }
-
-func (h *MessageHandler) sendStatsMessage(ctx context.Context, channelID string) error {
-	count, err := h.thoughtRepo.Count(ctx)
-	if err != nil {
-		return h.client.SendMessage(channelID, "Failed to fetch stats")
-	}
-
-	thoughts, err := h.thoughtRepo.GetAll(ctx)
-	if err != nil {
-		return h.client.SendMessage(channelID, "Failed to fetch thoughts")
-	}
-
-	categoryCount := make(map[string]int)
-	for _, thought := range thoughts {
-		categoryCount[thought.Category]++
-	}
-
-	statsText := "*Thought Statistics*\n\n"
-	statsText += fmt.Sprintf("Total captured: *%d*\n\n", count)
-	statsText += "*By Category:*\n"
-	for category, cnt := range categoryCount {
-		statsText += fmt.Sprintf("• %s: %d\n", category, cnt)
-	}
-
-	statsText += "\n*Recent Thoughts:*\n"
-	recentCount := 3
-	if len(thoughts) < recentCount {
-		recentCount = len(thoughts)
-	}
-
-	for i := 0; i < recentCount; i++ {
-		preview := thoughts[i].Content
-		if len(preview) > 60 {
-			preview = preview[:60] + "..."
-		}
-		statsText += fmt.Sprintf("%d. [%s] %s\n", i+1, thoughts[i].Category, preview)
-	}
-
-	return h.client.SendMessage(channelID, statsText)
-}
\ No newline at end of file
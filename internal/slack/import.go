@@ -0,0 +1,90 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/importer"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const importUsage = "Attach a CSV or JSON export of your notes to a message that says `@LinkedIn Ghostwriter import`. A CSV needs a header row with a `content`, `text` or `note` column, and can have `date`, `category` and `tags` columns too."
+
+// HandleImport imports the notes in the file attached to the mention at
+// messageTS. The file is parsed right away; categorizing happens in the
+// background, with a progress message in the channel kept up to date.
+func (h *CommandHandler) HandleImport(ctx context.Context, channelID, userID, messageTS string) error {
+	message, err := h.client.GetMessage(channelID, messageTS)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load import message", "error", err)
+		return h.client.SendMessage(channelID, "Couldn't read your message to find the file. Is it in a thread? Share it in the channel instead.")
+	}
+
+	if len(message.Files) == 0 {
+		return h.client.SendMessage(channelID, importUsage)
+	}
+
+	file := message.Files[0]
+	if file.Size > importer.MaxFileSize {
+		return h.client.SendMessage(channelID, fmt.Sprintf("%s is too big to import, the limit is %d MB.", file.Name, importer.MaxFileSize>>20))
+	}
+
+	data, err := h.client.DownloadFile(ctx, file.URLPrivateDownload)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to download import file", "file", file.Name, "error", err)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Failed to download %s", file.Name))
+	}
+
+	records, err := importer.Parse(data, file.Name)
+	if err != nil {
+		return h.client.SendMessage(channelID, fmt.Sprintf("Couldn't read %s: %v\n\n%s", file.Name, err, importUsage))
+	}
+
+	progressTS, err := h.client.SendMessageAndGetTS(channelID, fmt.Sprintf("📥 Queued %d note(s) from %s for import.", len(records), file.Name))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to send import progress", "error", err)
+	}
+
+	job := models.NewImportJob(file.Name, channelID, userID, len(records))
+	job.ProgressTS = progressTS
+	if err := h.importer.Submit(ctx, job, records); err != nil {
+		slog.ErrorContext(ctx, "Failed to queue import", "error", err)
+		if job.Status != models.ImportFailed {
+			job.Status, job.Error = models.ImportFailed, "couldn't save the job"
+		}
+		h.ReportImport(ctx, job)
+		return nil
+	}
+
+	slog.InfoContext(ctx, "Import queued", "job_id", job.ID, "file", file.Name, "notes", len(records), "user", userID)
+	return nil
+}
+
+// ReportImport shows an import's progress on the message it was queued
+// with.
+func (h *CommandHandler) ReportImport(ctx context.Context, job *models.ImportJob) {
+	if job.SlackChannelID == "" || job.ProgressTS == "" {
+		return
+	}
+	if err := h.client.UpdateMessage(job.SlackChannelID, job.ProgressTS, importProgress(job)); err != nil {
+		slog.ErrorContext(ctx, "Failed to update import progress", "job_id", job.ID, "error", err)
+	}
+}
+
+func importProgress(job *models.ImportJob) string {
+	counts := fmt.Sprintf("%d imported · %d already there · %d failed", job.Imported, job.Skipped, job.Failed)
+
+	switch job.Status {
+	case models.ImportQueued:
+		return fmt.Sprintf("📥 Queued %d note(s) from %s for import.", job.Total, job.Filename)
+	case models.ImportRunning:
+		return fmt.Sprintf("⏳ Importing %s: %d/%d note(s) done\n%s", job.Filename, job.Processed(), job.Total, counts)
+	case models.ImportCompleted:
+		return fmt.Sprintf("✅ Imported %s\n%s", job.Filename, counts)
+	case models.ImportInterrupted:
+		return fmt.Sprintf("⏸️ The import of %s paused at %d/%d note(s) while the bot restarts. It carries on from there once the bot is back.\n%s", job.Filename, job.Processed(), job.Total, counts)
+	default:
+		return fmt.Sprintf("❌ The import of %s failed: %s\n%s", job.Filename, job.Error, counts)
+	}
+}
@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/queue"
+)
+
+// generateJob is a generate command waiting in the job queue.
+type generateJob struct {
+	ChannelID string `json:"channel_id"`
+	UserID    string `json:"user_id"`
+	Command   string `json:"command"`
+}
+
+// brainstormJob is a brainstorm command waiting in the job queue.
+type brainstormJob struct {
+	ChannelID string `json:"channel_id"`
+	Topic     string `json:"topic"`
+}
+
+// enqueue hands a slow command to the job queue, so whichever replica is
+// free runs it and it survives a restart.
+func (h *MessageHandler) enqueue(ctx context.Context, channelID, kind string, payload any) error {
+	if _, err := h.jobs.Enqueue(ctx, kind, "", payload); err != nil {
+		slog.ErrorContext(ctx, "Failed to queue job", "kind", kind, "error", err)
+		return h.client.SendMessage(channelID, "Failed to queue your request. Please try again.")
+	}
+	return nil
+}
+
+type generateJobKey struct{}
+
+// withGenerateJob marks the drafts generated with ctx as written by the
+// queued job with jobID.
+func withGenerateJob(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, generateJobKey{}, jobID)
+}
+
+// generateJobID returns the queued job drafts generated with ctx belong to,
+// or "" outside a job.
+func generateJobID(ctx context.Context) string {
+	jobID, _ := ctx.Value(generateJobKey{}).(string)
+	return jobID
+}
+
+// RunGenerateJob runs a queued generate command. A generation that fails is
+// kept as a failed job for the retry worker rather than retried by the
+// queue, so the user hears about it once. Drafts are tagged with the job,
+// so when the job runs again, after a crash or restart, it keeps the drafts
+// it already wrote and only writes the variations still missing.
+func (h *MessageHandler) RunGenerateJob(ctx context.Context, job *models.Job) error {
+	var payload generateJob
+	if err := queue.Decode(job, &payload); err != nil {
+		return err
+	}
+
+	shared, err := h.jobShared(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	if shared {
+		slog.InfoContext(ctx, "Skipping generate job that already shared its drafts", "job_id", job.ID)
+		return nil
+	}
+
+	err = h.generate(agents.WithCommand(withGenerateJob(ctx, job.ID), "generate"), payload.ChannelID, payload.UserID, payload.Command)
+	// Nothing to write from, or no budget left, won't change on a retry.
+	if err == nil || errors.Is(err, ErrNoThoughts) || errors.Is(err, agents.ErrBudgetExceeded) {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+
+	slog.ErrorContext(ctx, "Failed to generate drafts", "command", payload.Command, "error", err)
+	h.recordFailedJob(ctx, models.NewFailedJob(models.FailedGenerate, payload.ChannelID, payload.UserID, payload.Command, err))
+	h.client.SendMessage(payload.ChannelID, "I saved the request and will retry it in a few minutes. Use `@LinkedIn Ghostwriter retry failed` to retry now.")
+	return nil
+}
+
+// jobShared reports whether the generate job with jobID already wrote and
+// shared its drafts.
+func (h *MessageHandler) jobShared(ctx context.Context, jobID string) (bool, error) {
+	posts, err := h.commandHandler.postRepo.GetByJobID(ctx, jobID)
+	if err != nil || len(posts) == 0 {
+		return false, err
+	}
+
+	shared, err := h.approvalHandler.draftMessageRepo.GetLatestByPostID(ctx, posts[0].ID)
+	if err != nil {
+		return false, err
+	}
+	return shared != nil, nil
+}
+
+// jobDrafts returns the drafts the generate job running with ctx already
+// wrote, if any.
+func (h *CommandHandler) jobDrafts(ctx context.Context) ([]*models.Post, error) {
+	jobID := generateJobID(ctx)
+	if jobID == "" {
+		return nil, nil
+	}
+	return h.postRepo.GetByJobID(ctx, jobID)
+}
+
+// RunBrainstormJob runs a queued brainstorm command.
+func (h *MessageHandler) RunBrainstormJob(ctx context.Context, job *models.Job) error {
+	var payload brainstormJob
+	if err := queue.Decode(job, &payload); err != nil {
+		return err
+	}
+
+	return h.commandHandler.HandleBrainstorm(agents.WithCommand(ctx, "brainstorm"), payload.ChannelID, payload.Topic)
+}
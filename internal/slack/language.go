@@ -0,0 +1,175 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+// languageSettingPrefix followed by a Slack user ID stores the language
+// that user's posts are written in.
+const languageSettingPrefix = "language."
+
+const (
+	languageUsage  = "Usage: `@LinkedIn Ghostwriter language [code|reset]`, or add `--language [code]` to `generate`, `develop` or `recap`"
+	translateUsage = "Usage: `@LinkedIn Ghostwriter translate [draft #] [language]`"
+)
+
+// cutLanguageFlag takes a "--language de" flag out of a command's
+// arguments and puts the language in ctx.
+func cutLanguageFlag(ctx context.Context, args string) (context.Context, string, error) {
+	rest, value, found, err := cutFlag(args, "language")
+	if err != nil || !found {
+		return ctx, args, err
+	}
+
+	code, err := models.ParseLanguage(value)
+	if err != nil {
+		return ctx, args, err
+	}
+
+	return agents.WithLanguage(ctx, code), rest, nil
+}
+
+// withPostLanguage returns ctx set up to write in the language posts for
+// userID are written in, and its code: the one ctx asks for, or the one
+// userID set, or the configured one.
+func (h *CommandHandler) withPostLanguage(ctx context.Context, userID string) (context.Context, string) {
+	if code := agents.LanguageFrom(ctx); code != "" {
+		return ctx, code
+	}
+
+	code := h.userLanguage(ctx, userID)
+	if code == "" {
+		code = h.language
+	}
+	return agents.WithLanguage(ctx, code), code
+}
+
+func (h *CommandHandler) userLanguage(ctx context.Context, userID string) string {
+	value, err := h.settings.Get(ctx, languageSettingPrefix+userID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load language setting", "user", userID, "error", err)
+		return ""
+	}
+	if _, ok := models.Languages[value]; !ok {
+		return ""
+	}
+	return value
+}
+
+// HandleLanguage shows the language userID's posts are written in, or sets
+// or resets it.
+func (h *CommandHandler) HandleLanguage(ctx context.Context, channelID, userID string, args []string) error {
+	if len(args) == 0 {
+		_, code := h.withPostLanguage(ctx, userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Your posts are written in *%s*. Languages: %s.\n%s",
+			models.LanguageName(code), strings.Join(models.LanguageCodes(), ", "), languageUsage))
+	}
+	if len(args) > 1 {
+		return h.client.SendMessage(channelID, languageUsage)
+	}
+
+	key := languageSettingPrefix + userID
+	if strings.EqualFold(args[0], "reset") {
+		if err := h.settings.Delete(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "Failed to reset language", "user", userID, "error", err)
+			return h.client.SendMessage(channelID, "Failed to reset your language")
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("Your posts are back to the configured *%s*.", models.LanguageName(h.language)))
+	}
+
+	code, err := models.ParseLanguage(args[0])
+	if err != nil {
+		return h.client.SendMessage(channelID, fmt.Sprintf("I don't write in %q. Languages: %s.", args[0], strings.Join(models.LanguageCodes(), ", ")))
+	}
+
+	if err := h.settings.Set(ctx, key, code, userID); err != nil {
+		slog.ErrorContext(ctx, "Failed to save language", "user", userID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to save your language")
+	}
+
+	slog.InfoContext(ctx, "Set post language", "language", code, "user", userID)
+	return h.client.SendMessage(channelID, fmt.Sprintf("<@%s>'s posts are now written in *%s*.", userID, models.LanguageName(code)))
+}
+
+// HandleTranslate writes a pending draft in another language as a new
+// draft linked to it. The original stays up for review, so both can be
+// published.
+func (h *CommandHandler) HandleTranslate(ctx context.Context, channelID, userID string, args []string) ([]slack.Block, []string, error) {
+	if len(args) != 2 {
+		return nil, nil, h.client.SendMessage(channelID, translateUsage)
+	}
+
+	var index int
+	if _, err := fmt.Sscanf(args[0], "%d", &index); err != nil || index < 1 {
+		return nil, nil, h.client.SendMessage(channelID, translateUsage)
+	}
+
+	code, err := models.ParseLanguage(args[1])
+	if err != nil {
+		return nil, nil, h.client.SendMessage(channelID, fmt.Sprintf("I don't write in %q. Languages: %s.", args[1], strings.Join(models.LanguageCodes(), ", ")))
+	}
+
+	drafts, err := h.postRepo.GetByStatus(ctx, "draft")
+	if err != nil {
+		h.client.SendMessage(channelID, "Failed to fetch drafts")
+		return nil, nil, err
+	}
+	if index > len(drafts) {
+		return nil, nil, h.client.SendMessage(channelID, fmt.Sprintf("Draft %d not found. Use `@LinkedIn Ghostwriter drafts` to see pending drafts.", index))
+	}
+
+	original := drafts[index-1]
+	if original.Language == code {
+		return nil, nil, h.client.SendMessage(channelID, fmt.Sprintf("Draft %d is already in %s.", index, models.LanguageName(code)))
+	}
+
+	h.client.SendMessage(channelID, fmt.Sprintf("Translating Draft %d into %s... This may take a moment.", index, models.LanguageName(code)))
+
+	translation, err := h.contentGenerator.TranslatePost(h.withEmojiPolicy(ctx, userID), original, code)
+	if err != nil {
+		h.client.SendMessage(channelID, llmErrorMessage(err, "Failed to translate the draft. Please try again."))
+		return nil, nil, err
+	}
+
+	post := newRevision(original, translation.Content)
+	post.ParentPostID = nil
+	post.TranslationOf = &original.ID
+	post.Language = code
+	post.FirstComment = translation.FirstComment
+	post.Slides = translation.Slides
+	post.DocumentPath = ""
+	post.Poll = translation.Poll
+
+	if err := h.postRepo.Create(ctx, post); err != nil {
+		h.client.SendMessage(channelID, "Failed to save the translated draft. Please try again.")
+		return nil, nil, err
+	}
+	h.recordRevision(ctx, post, models.RevisionGeneration, userID, "translation into "+code)
+
+	if post.PostType == models.PostTypeCarousel {
+		path, err := h.carousels.Render(post.ID, post.Slides)
+		if err != nil {
+			h.client.SendMessage(channelID, "Failed to render the carousel PDF. Please try again.")
+			return nil, nil, err
+		}
+		if err := h.postRepo.SetDocument(ctx, post.ID, path); err != nil {
+			h.client.SendMessage(channelID, "Failed to save the carousel PDF. Please try again.")
+			return nil, nil, err
+		}
+		post.DocumentPath = path
+	}
+
+	slog.InfoContext(ctx, "Translated draft", "original_id", original.ID, "post_id", post.ID, "language", code)
+
+	header := fmt.Sprintf("*Draft %d in %s*\n_Translated from the %s draft, which stays up for review so both can be published._",
+		index, models.LanguageName(code), models.LanguageName(original.Language))
+
+	return buildDraftBlocks(header, []*models.Post{post}, h.publishTargets), []string{post.ID}, nil
+}
@@ -0,0 +1,51 @@
+package slack
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const lengthUsage = "Usage: add `--length [short|medium|long]` or `--words [40-450]` to `generate`, `develop` or `recap`"
+
+// cutLengthFlag takes a "--length short" or "--words 200" flag out of a
+// command's arguments and puts the length in ctx.
+func cutLengthFlag(ctx context.Context, args string) (context.Context, string, error) {
+	rest, value, found, err := cutFlag(args, "length")
+	if err != nil {
+		return ctx, args, err
+	}
+	if found {
+		length, err := models.ParsePostLength(value)
+		if err != nil {
+			return ctx, args, err
+		}
+		ctx = agents.WithPostLength(ctx, length)
+	}
+
+	rest, value, found, err = cutFlag(rest, "words")
+	if err != nil || !found {
+		return ctx, rest, err
+	}
+
+	words, err := strconv.Atoi(value)
+	if err != nil {
+		return ctx, args, err
+	}
+	length, err := models.WordTarget(words)
+	if err != nil {
+		return ctx, args, err
+	}
+	return agents.WithPostLength(ctx, length), rest, nil
+}
+
+// withPostLength returns ctx set up to write posts at the length ctx asks
+// for, or the configured one.
+func (h *CommandHandler) withPostLength(ctx context.Context) context.Context {
+	if _, ok := agents.PostLengthFrom(ctx); ok {
+		return ctx
+	}
+	return agents.WithPostLength(ctx, h.postLength)
+}
@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+// maxLintLines keeps the style check under a draft short; the rest are
+// still fixed by the Fix style button.
+const maxLintLines = 5
+
+func formatLintIssues(issues []agents.LintIssue) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✍️ *Style check:* %d issue(s)", len(issues))
+
+	for i, issue := range issues {
+		if i == maxLintLines {
+			fmt.Fprintf(&b, "\n_...and %d more_", len(issues)-maxLintLines)
+			break
+		}
+		if issue.Sentence == "" {
+			fmt.Fprintf(&b, "\n• %s", issue.Message)
+			continue
+		}
+		fmt.Fprintf(&b, "\n• %s in _%s_", issue.Message, truncate(strings.ReplaceAll(issue.Sentence, "\n", " "), 80))
+	}
+
+	return b.String()
+}
+
+// HandleFixDraft rewrites the sentences the style check flagged in a draft
+// and returns the result as a revised draft for the caller to share.
+func (h *CommandHandler) HandleFixDraft(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) ([]slack.Block, []string, error) {
+	postID := action.Value
+	userID := callback.User.ID
+
+	resolve := func(text string) {
+		blocks := replaceDraftActions(callback.Message.Blocks.BlockSet, postID, text)
+		if err := h.client.UpdateMessageBlocks(callback.Channel.ID, callback.Message.Timestamp, blocks); err != nil {
+			slog.ErrorContext(ctx, "Failed to update draft message", "post_id", postID, "error", err)
+		}
+	}
+
+	original, err := h.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues := agents.Lint(original.Content)
+	if len(issues) == 0 {
+		return nil, nil, nil
+	}
+
+	// Claim the draft first so a second click doesn't fix it twice.
+	status := original.Status
+	if status != "draft" && status != "stale" {
+		resolve(fmt.Sprintf("Already %s", status))
+		return nil, nil, nil
+	}
+	claimed, err := h.postRepo.TransitionStatus(ctx, postID, status, "revised")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !claimed {
+		resolve("This draft was changed in the meantime")
+		return nil, nil, nil
+	}
+
+	revised, err := h.linter.Rewrite(ctx, original.Content, issues)
+	if err != nil {
+		if _, err := h.postRepo.TransitionStatus(ctx, postID, "revised", status); err != nil {
+			slog.ErrorContext(ctx, "Failed to restore draft status", "post_id", postID, "error", err)
+		}
+		h.client.SendThreadMessage(callback.Channel.ID, callback.Message.Timestamp, llmErrorMessage(err, "Failed to fix the draft's style. Please try again."))
+		return nil, nil, err
+	}
+
+	post := newRevision(original, revised)
+	if err := h.postRepo.Create(ctx, post); err != nil {
+		if _, err := h.postRepo.TransitionStatus(ctx, postID, "revised", status); err != nil {
+			slog.ErrorContext(ctx, "Failed to restore draft status", "post_id", postID, "error", err)
+		}
+		return nil, nil, err
+	}
+	h.recordRevision(ctx, post, models.RevisionFeedback, userID, "style fixes")
+
+	resolve(fmt.Sprintf("✍️ Style fixed for <@%s>, revised draft posted below", userID))
+
+	header := fmt.Sprintf("*Revised Draft*\n_Fixed %d style issue(s)_", len(issues))
+	return buildDraftBlocks(header, []*models.Post{post}, h.publishTargets), []string{post.ID}, nil
+}
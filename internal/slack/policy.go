@@ -0,0 +1,36 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+)
+
+// policyBlocked returns the explanation to show when err is a draft that
+// can't be approved because it mentions confidential information, and ""
+// for any other error.
+func policyBlocked(err error, draft string) string {
+	var policyErr *agents.PolicyError
+	if !errors.As(err, &policyErr) {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔒 Can't approve %s, it mentions confidential information:", draft)
+	for _, violation := range policyErr.Violations {
+		fmt.Fprintf(&b, "\n• %s *%s*: “%s”", violation.Kind, violation.Term, violation.Excerpt)
+	}
+	b.WriteString("\nEdit the draft to take it out, then approve it again.")
+	return b.String()
+}
+
+// withBlocked adds the explanations of drafts that weren't approved to
+// message.
+func withBlocked(message string, blocked []string) string {
+	if len(blocked) == 0 {
+		return message
+	}
+	return message + "\n\n" + strings.Join(blocked, "\n\n")
+}
@@ -0,0 +1,85 @@
+package slack
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// LinkedIn collapses a post behind "...see more" after roughly this many
+	// characters or three lines, whichever comes first.
+	linkedInFoldChars = 210
+	linkedInFoldLines = 3
+	linkedInMaxChars  = 3000
+)
+
+var hashtagPattern = regexp.MustCompile(`(?:^|\s)#[\p{L}\p{N}_]+`)
+
+type postPreview struct {
+	Chars     int
+	Hashtags  int
+	AboveFold string
+	Folded    bool
+}
+
+func previewPost(content string) postPreview {
+	runes := []rune(content)
+
+	fold := len(runes)
+	if fold > linkedInFoldChars {
+		fold = linkedInFoldChars
+	}
+
+	lines := 0
+	for i, r := range runes[:fold] {
+		if r == '\n' {
+			lines++
+			if lines == linkedInFoldLines {
+				fold = i
+				break
+			}
+		}
+	}
+
+	return postPreview{
+		Chars:     len(runes),
+		Hashtags:  len(hashtagPattern.FindAllString(content, -1)),
+		AboveFold: strings.TrimSpace(string(runes[:fold])),
+		Folded:    fold < len(runes),
+	}
+}
+
+// previewContext summarises how a draft will render on LinkedIn so the hook
+// can be judged before approving.
+func previewContext(content string) string {
+	preview := previewPost(content)
+
+	summary := fmt.Sprintf("%d chars", preview.Chars)
+	if preview.Chars > linkedInMaxChars {
+		summary += fmt.Sprintf(" ⚠️ over LinkedIn's %d limit", linkedInMaxChars)
+	}
+	summary += fmt.Sprintf(" · %d hashtag(s)", preview.Hashtags)
+
+	if !preview.Folded {
+		return summary + " · fits above the \"see more\" fold"
+	}
+
+	aboveFold := strings.ReplaceAll(preview.AboveFold, "\n", " ")
+	return summary + fmt.Sprintf(" · before \"see more\": _%s…_", aboveFold)
+}
+
+// scoresContext shows how a draft reads, to compare variations on more than
+// gut feel.
+func scoresContext(post *models.Post) string {
+	scores := post.Scores
+	if scores == nil {
+		scores = models.ScoreContent(post.Content)
+	}
+
+	return fmt.Sprintf("Hook %d/100 · reading grade %.1f · sentence length ±%.1f words · %d emoji",
+		scores.HookStrength, scores.ReadingGrade, math.Sqrt(scores.SentenceVariance), scores.EmojiCount)
+}
@@ -0,0 +1,166 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/slack-go/slack"
+)
+
+// progressInterval keeps edits of a progress message within Slack's rate
+// limit for chat.update.
+const progressInterval = 1500 * time.Millisecond
+
+// progressMessage is the "Generating..." message of a generation. It is
+// edited as the model writes each variation, and replaced by the drafts
+// when they are ready, so a generation ends in one message.
+type progressMessage struct {
+	client    *Client
+	channelID string
+	threadTS  string
+	title     string
+	// held is set when ShareDrafts will replace the message.
+	held bool
+
+	mu     sync.Mutex
+	ts     string
+	edited time.Time
+}
+
+type progressKey struct{}
+
+// progressSlot holds the progress message started while handling a
+// command, for ShareDrafts to replace.
+type progressSlot struct {
+	message *progressMessage
+}
+
+// withProgress returns ctx in which a generation's progress message is
+// replaced by the drafts it shares, rather than left above them.
+func withProgress(ctx context.Context) context.Context {
+	return context.WithValue(ctx, progressKey{}, &progressSlot{})
+}
+
+// startProgress posts title in channelID, or in the thread threadTS, and
+// returns ctx set up to stream the model's variations into it.
+func (h *CommandHandler) startProgress(ctx context.Context, channelID, threadTS, title string) (context.Context, *progressMessage) {
+	p := &progressMessage{client: h.client, channelID: channelID, threadTS: threadTS, title: title}
+
+	var err error
+	if threadTS != "" {
+		p.ts, err = h.client.SendThreadMessageAndGetTS(channelID, threadTS, title)
+	} else {
+		p.ts, err = h.client.SendMessageAndGetTS(channelID, title)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to post progress message", "error", err)
+		return ctx, p
+	}
+	p.edited = time.Now()
+
+	if slot, ok := ctx.Value(progressKey{}).(*progressSlot); ok && threadTS == "" {
+		slot.message = p
+		p.held = true
+	}
+
+	return agents.WithStream(ctx, p.stream), p
+}
+
+// stream renders the variations written so far, at most once every
+// progressInterval.
+func (p *progressMessage) stream(text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ts == "" || time.Since(p.edited) < progressInterval {
+		return
+	}
+	p.edited = time.Now()
+
+	if err := p.client.UpdateMessage(p.channelID, p.ts, p.render(text)); err != nil {
+		slog.Warn("Failed to update progress message", "error", err)
+	}
+}
+
+func (p *progressMessage) render(text string) string {
+	variations := agents.PartialVariations(text)
+
+	var b strings.Builder
+	b.WriteString(p.title)
+	for i, content := range variations {
+		fmt.Fprintf(&b, "\n\n*Variation %d*\n%s", i+1, quote(strings.TrimSpace(content)))
+	}
+	if len(variations) > 0 {
+		fmt.Fprintf(&b, "\n\n_Writing variation %d..._", len(variations))
+	}
+
+	return b.String()
+}
+
+// finish replaces the progress with text, such as an error. Without a
+// progress message, text is posted instead.
+func (p *progressMessage) finish(text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case p.ts == "" && p.threadTS != "":
+		p.client.SendThreadMessage(p.channelID, p.threadTS, text)
+	case p.ts == "":
+		p.client.SendMessage(p.channelID, text)
+	default:
+		if err := p.client.UpdateMessage(p.channelID, p.ts, text); err != nil {
+			slog.Warn("Failed to update progress message", "error", err)
+		}
+	}
+	p.ts = ""
+}
+
+// done ends the progress with text once the drafts are written, unless
+// ShareDrafts will replace it with them.
+func (p *progressMessage) done(text string) {
+	if !p.held {
+		p.finish(text)
+	}
+}
+
+// replace turns the progress message into blocks and returns its
+// timestamp, or "" when it couldn't, in which case nothing was changed.
+func (p *progressMessage) replace(blocks []slack.Block) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ts := p.ts
+	if ts == "" {
+		return ""
+	}
+	p.ts = ""
+
+	if err := p.client.UpdateMessageBlocks(p.channelID, ts, blocks); err != nil {
+		slog.Warn("Failed to replace progress message with drafts", "error", err)
+		return ""
+	}
+	return ts
+}
+
+// takeProgress returns the progress message started in channelID under
+// ctx, if any, and forgets it so it is replaced only once.
+func takeProgress(ctx context.Context, channelID string) *progressMessage {
+	slot, ok := ctx.Value(progressKey{}).(*progressSlot)
+	if !ok || slot.message == nil || slot.message.channelID != channelID {
+		return nil
+	}
+
+	message := slot.message
+	slot.message = nil
+	return message
+}
+
+func quote(text string) string {
+	return "> " + strings.ReplaceAll(text, "\n", "\n> ")
+}
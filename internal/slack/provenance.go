@@ -0,0 +1,38 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+// maxProvenanceLinks caps the sources listed under a draft.
+const maxProvenanceLinks = 8
+
+// formatProvenance lists the Linear issues, GitHub links and pages a
+// draft's thoughts came from, so its claims can be checked before it's
+// approved. It returns "" for a draft written from plain notes.
+func formatProvenance(post *models.Post) string {
+	if len(post.Provenance) == 0 {
+		return ""
+	}
+
+	links := make([]string, 0, min(len(post.Provenance), maxProvenanceLinks))
+	for i, source := range post.Provenance {
+		if i == maxProvenanceLinks {
+			links = append(links, fmt.Sprintf("_and %d more_", len(post.Provenance)-maxProvenanceLinks))
+			break
+		}
+
+		label := format.ToSlack(truncate(source.Label(), 60))
+		if source.URL == "" {
+			links = append(links, label)
+			continue
+		}
+		links = append(links, fmt.Sprintf("<%s|%s>", source.URL, label))
+	}
+
+	return "🔎 *Sources:* " + strings.Join(links, " · ")
+}
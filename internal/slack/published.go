@@ -0,0 +1,196 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+const (
+	actionPublishedPage = "published_page"
+
+	publishedPageSize = 5
+	publishedUsage    = "Usage: `@LinkedIn Ghostwriter published [month]`, with a month like `march`, `mar 2026` or `2026-03`"
+)
+
+// HandlePublished lists the posts published in the month named by args,
+// or every published post when args is empty, a page at a time.
+func (h *CommandHandler) HandlePublished(ctx context.Context, channelID string, args []string) error {
+	month := ""
+	if len(args) > 0 {
+		from, err := parseMonth(strings.Join(args, " "), time.Now().In(scheduleLocation()))
+		if err != nil {
+			return h.client.SendMessage(channelID, publishedUsage)
+		}
+		month = from.Format("2006-01")
+	}
+
+	blocks, err := h.publishedBlocks(ctx, month, 0)
+	if err != nil {
+		return h.client.SendMessage(channelID, "Failed to fetch published posts")
+	}
+
+	return h.client.SendMessageWithBlocks(channelID, blocks)
+}
+
+// HandlePublishedPage replaces a published list with the page its
+// previous or next button points to.
+func (h *CommandHandler) HandlePublishedPage(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) error {
+	month, page, err := parsePublishedPageValue(action.Value)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := h.publishedBlocks(ctx, month, page)
+	if err != nil {
+		return err
+	}
+
+	return h.client.UpdateMessageBlocks(callback.Channel.ID, callback.Message.Timestamp, blocks)
+}
+
+// publishedBlocks renders page of the posts published in month, a
+// "2006-01" key, or at any time when month is empty.
+func (h *CommandHandler) publishedBlocks(ctx context.Context, month string, page int) ([]slack.Block, error) {
+	location := scheduleLocation()
+
+	var from, to time.Time
+	title := "*Published posts*"
+	if month != "" {
+		start, err := time.ParseInLocation("2006-01", month, location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid month %q: %w", month, err)
+		}
+		from, to = start, start.AddDate(0, 1, 0)
+		title = fmt.Sprintf("*Published in %s*", start.Format("January 2006"))
+	}
+
+	// One extra post tells whether there is a next page.
+	posts, err := h.postRepo.GetPublishedBetween(ctx, from, to, publishedPageSize+1, page*publishedPageSize)
+	if err != nil {
+		return nil, err
+	}
+	hasNext := len(posts) > publishedPageSize
+	if hasNext {
+		posts = posts[:publishedPageSize]
+	}
+
+	if len(posts) == 0 && page == 0 {
+		return []slack.Block{
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, title+"\nNothing was published then.", false, false), nil, nil),
+		}, nil
+	}
+
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("%s · page %d", title, page+1), false, false), nil, nil),
+	}
+	for i, post := range posts {
+		blocks = append(blocks,
+			slack.NewDividerBlock(),
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType,
+				fmt.Sprintf("*%d.* %s", page*publishedPageSize+i+1, format.ToSlack(truncate(post.Content, 280))), false, false), nil, nil),
+			slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, publishedDetails(post, location), false, false)),
+		)
+	}
+
+	var buttons []slack.BlockElement
+	if page > 0 {
+		buttons = append(buttons, slack.NewButtonBlockElement(actionPublishedPage, publishedPageValue(month, page-1),
+			slack.NewTextBlockObject(slack.PlainTextType, "◀ Previous page", false, false)))
+	}
+	if hasNext {
+		buttons = append(buttons, slack.NewButtonBlockElement(actionPublishedPage, publishedPageValue(month, page+1),
+			slack.NewTextBlockObject(slack.PlainTextType, "Next page ▶", false, false)))
+	}
+	if len(buttons) > 0 {
+		blocks = append(blocks, slack.NewDividerBlock(), slack.NewActionBlock("published_pages", buttons...))
+	}
+
+	return blocks, nil
+}
+
+func publishedDetails(post *models.Post, location *time.Location) string {
+	published := "unknown date"
+	if post.PublishedAt != nil {
+		published = post.PublishedAt.In(location).Format("Mon, Jan 2 2006")
+	}
+
+	details := fmt.Sprintf("%s · %s", published, post.PostType)
+	if post.MetricsSyncedAt != nil {
+		details += fmt.Sprintf(" · *%.1f* · 👍 %d · 💬 %d · 🔁 %d · 👀 %d",
+			post.PerformanceScore,
+			post.Metrics["likes"],
+			post.Metrics["comments"],
+			post.Metrics["shares"],
+			post.Metrics["views"])
+	} else {
+		details += " · no metrics yet"
+	}
+	if post.LinkedInURL != "" {
+		details += fmt.Sprintf(" · <%s|View on LinkedIn>", post.LinkedInURL)
+	}
+	if post.CompanyPostURN != "" {
+		details += fmt.Sprintf(" · <%s|View on the company page>", models.LinkedInPostURL(post.CompanyPostURN))
+	}
+
+	return details
+}
+
+// publishedPageValue packs the month and page a page button shows.
+func publishedPageValue(month string, page int) string {
+	return fmt.Sprintf("%s|%d", month, page)
+}
+
+func parsePublishedPageValue(value string) (month string, page int, err error) {
+	month, pageText, ok := strings.Cut(value, "|")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid published page value: %q", value)
+	}
+	page, err = strconv.Atoi(pageText)
+	if err != nil || page < 0 {
+		return "", 0, fmt.Errorf("invalid published page value: %q", value)
+	}
+	return month, page, nil
+}
+
+// parseMonth reads a month as "2026-03", "march 2026" or "mar 2026", or a
+// month name alone for its latest occurrence up to now. It returns the
+// first moment of the month in now's location.
+func parseMonth(text string, now time.Time) (time.Time, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	if t, err := time.ParseInLocation("2006-01", text, now.Location()); err == nil {
+		return t, nil
+	}
+
+	name, yearText, hasYear := strings.Cut(text, " ")
+	var month time.Month
+	for m := time.January; m <= time.December; m++ {
+		full := strings.ToLower(m.String())
+		if name == full || name == full[:3] {
+			month = m
+		}
+	}
+	if month == 0 {
+		return time.Time{}, fmt.Errorf("unknown month %q", text)
+	}
+
+	year := now.Year()
+	if hasYear {
+		y, err := strconv.Atoi(strings.TrimSpace(yearText))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid year in %q", text)
+		}
+		year = y
+	} else if month > now.Month() {
+		year--
+	}
+
+	return time.Date(year, month, 1, 0, 0, 0, 0, now.Location()), nil
+}
@@ -0,0 +1,91 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+const recapUsage = "Usage: `@LinkedIn Ghostwriter recap cycle [team]`"
+
+// HandleRecap writes "what we shipped this sprint" drafts from the Linear
+// cycle that ended most recently. The cycle's recap is saved as a milestone
+// thought first, so the drafts trace back to it like any other.
+func (h *CommandHandler) HandleRecap(ctx context.Context, channelID, userID, args string) ([]slack.Block, []string, error) {
+	kind, team := cutWord(args)
+	if !strings.EqualFold(kind, "cycle") {
+		return nil, nil, h.client.SendMessage(channelID, recapUsage)
+	}
+	if h.linearSyncer == nil {
+		return nil, nil, h.client.SendMessage(channelID, "Linear is not configured. Add LINEAR_API_KEY to .env")
+	}
+
+	cycle, err := h.linearSyncer.LastCompletedCycle(strings.TrimSpace(team))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch Linear cycle", "error", err)
+		return nil, nil, h.client.SendMessage(channelID, "Failed to fetch the last cycle from Linear")
+	}
+	if cycle == nil {
+		return nil, nil, h.client.SendMessage(channelID, "No Linear cycle ended in the last 30 days.")
+	}
+
+	thought, err := h.linearSyncer.CaptureCycle(ctx, channelID, cycle)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to save cycle recap", "cycle_id", cycle.ID, "error", err)
+		return nil, nil, h.client.SendMessage(channelID, "Failed to save the cycle recap")
+	}
+
+	ctx, progress := h.startProgress(ctx, channelID, "", fmt.Sprintf("Writing a recap of *%s*... This may take a moment.", cycle.Title()))
+
+	ctx, language := h.withPostLanguage(h.withPostLength(h.withEmojiPolicy(ctx, userID)), userID)
+	profile, err := h.styleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load style profile", "error", err)
+	}
+
+	examples, err := h.postRepo.GetTopPerforming(ctx, fewShotExamples)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load top performing posts", "error", err)
+	}
+
+	variations, err := h.contentGenerator.GenerateRecap(ctx, cycle.Title(), thought.Content, agents.FormatStyleGuide(profile), examples, h.variationCount(ctx))
+	if err != nil {
+		progress.finish(llmErrorMessage(err, "Failed to generate the recap. Please try again."))
+		return nil, nil, err
+	}
+
+	var posts []*models.Post
+	var postIDs []string
+	for _, variation := range variations {
+		post := models.NewPost(variation.Content, []string{thought.ID}, "insight", "professional")
+		post.Status = "draft"
+		post.Language = language
+		post.Provenance = models.ProvenanceOf([]*models.Thought{thought})
+		post.FirstComment = variation.FirstComment
+		h.checkFacts(ctx, post, []*models.Thought{thought})
+
+		if err := h.postRepo.Create(ctx, post); err != nil {
+			slog.ErrorContext(ctx, "Failed to save recap draft", "error", err)
+			continue
+		}
+		h.recordRevision(ctx, post, models.RevisionGeneration, userID, "")
+
+		posts = append(posts, post)
+		postIDs = append(postIDs, post.ID)
+	}
+
+	if len(posts) == 0 {
+		progress.finish("Failed to save generated drafts. Please try again.")
+		return nil, nil, fmt.Errorf("no drafts saved")
+	}
+	progress.done(fmt.Sprintf("Wrote %d recap draft(s).", len(posts)))
+
+	header := fmt.Sprintf("*Sprint Recap Drafts*\n_%s_", strings.SplitN(thought.Content, "\n", 2)[0])
+
+	return buildDraftBlocks(header, posts, h.publishTargets), postIDs, nil
+}
@@ -0,0 +1,195 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/recategorize"
+)
+
+const (
+	// recentThoughtsLimit is how many thoughts recategorize and retag list
+	// and can pick from by number.
+	recentThoughtsLimit = 10
+
+	recategorizeUsage = "Usage: `@LinkedIn Ghostwriter recategorize [thought #] [category]` or `@LinkedIn Ghostwriter recategorize uncategorized`"
+	retagUsage        = "Usage: `@LinkedIn Ghostwriter retag [thought #] [tag, tag...]`"
+)
+
+// HandleRecategorize moves one of the channel's recent thoughts to another
+// category, or lets the categorizer pick again when no category is given.
+// `recategorize uncategorized` queues a run of the categorizer over every
+// uncategorized thought.
+func (h *CommandHandler) HandleRecategorize(ctx context.Context, channelID, userID, args string) error {
+	arg, rest := cutWord(args)
+	if arg == "" {
+		return h.listRecentThoughts(ctx, channelID, recategorizeUsage)
+	}
+	if strings.EqualFold(arg, "uncategorized") || (strings.EqualFold(arg, "all") && strings.EqualFold(rest, "uncategorized")) {
+		return h.recategorizeUncategorized(ctx, channelID, userID)
+	}
+
+	thought, number, err := h.recentThoughtByNumber(ctx, channelID, arg, recategorizeUsage)
+	if err != nil || thought == nil {
+		return err
+	}
+
+	category := models.NormalizeCategory(rest)
+	if category == "" {
+		suggested, _, err := h.recategorizer.Suggest(ctx, thought)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to recategorize thought", "thought_id", thought.ID, "error", err)
+			return h.client.SendMessage(channelID, llmErrorMessage(err, "Failed to recategorize the thought. Please try again."))
+		}
+		category = suggested
+	} else if category != "uncategorized" {
+		categories, err := h.categoryRepo.List(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to list categories", "error", err)
+			return h.client.SendMessage(channelID, "Failed to list categories")
+		}
+		if !slices.ContainsFunc(categories, func(c *models.Category) bool { return c.Name == category }) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` category. Add it with `@LinkedIn Ghostwriter categories add %s` first.", category, category))
+		}
+	}
+
+	if category == thought.Category {
+		return h.client.SendMessage(channelID, fmt.Sprintf("Thought %d is already filed under `%s`.", number, category))
+	}
+
+	previous := thought.Category
+	thought.Category = category
+	if err := h.thoughtRepo.Update(ctx, thought); err != nil {
+		slog.ErrorContext(ctx, "Failed to update thought", "thought_id", thought.ID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to save the thought")
+	}
+
+	slog.InfoContext(ctx, "Thought recategorized", "thought_id", thought.ID, "from", previous, "to", category, "user", userID)
+	return h.client.SendMessage(channelID, fmt.Sprintf("Moved thought %d from `%s` to `%s`.", number, previous, category))
+}
+
+// HandleRetag replaces the tags of one of the channel's recent thoughts, or
+// lets the categorizer pick them again when no tags are given.
+func (h *CommandHandler) HandleRetag(ctx context.Context, channelID, userID, args string) error {
+	arg, rest := cutWord(args)
+	if arg == "" {
+		return h.listRecentThoughts(ctx, channelID, retagUsage)
+	}
+
+	thought, number, err := h.recentThoughtByNumber(ctx, channelID, arg, retagUsage)
+	if err != nil || thought == nil {
+		return err
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(rest, ",") {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) == 0 {
+		_, tags, err = h.recategorizer.Suggest(ctx, thought)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to retag thought", "thought_id", thought.ID, "error", err)
+			return h.client.SendMessage(channelID, llmErrorMessage(err, "Failed to retag the thought. Please try again."))
+		}
+	}
+
+	thought.TopicTags = tags
+	if err := h.thoughtRepo.Update(ctx, thought); err != nil {
+		slog.ErrorContext(ctx, "Failed to update thought", "thought_id", thought.ID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to save the thought")
+	}
+
+	slog.InfoContext(ctx, "Thought retagged", "thought_id", thought.ID, "tags", tags, "user", userID)
+	return h.client.SendMessage(channelID, fmt.Sprintf("Thought %d is now tagged %s.", number, strings.Join(tags, ", ")))
+}
+
+func (h *CommandHandler) recategorizeUncategorized(ctx context.Context, channelID, userID string) error {
+	progressTS, err := h.client.SendMessageAndGetTS(channelID, "🗂️ Queued a recategorization of every uncategorized thought.")
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to send recategorization progress", "error", err)
+	}
+
+	report := func(ctx context.Context, job *recategorize.Job) {
+		if progressTS == "" {
+			return
+		}
+		if err := h.client.UpdateMessage(channelID, progressTS, recategorizeProgress(job)); err != nil {
+			slog.ErrorContext(ctx, "Failed to update recategorization progress", "error", err)
+		}
+	}
+
+	if err := h.recategorizer.SubmitUncategorized(report); errors.Is(err, recategorize.ErrBusy) {
+		if progressTS != "" {
+			return h.client.UpdateMessage(channelID, progressTS, "A recategorization is already running. Try again once it finishes.")
+		}
+		return nil
+	}
+
+	slog.InfoContext(ctx, "Recategorization queued", "user", userID)
+	return nil
+}
+
+func recategorizeProgress(job *recategorize.Job) string {
+	counts := fmt.Sprintf("%d moved · %d still uncategorized · %d failed", job.Moved, job.Checked-job.Moved-job.Failed, job.Failed)
+
+	switch {
+	case !job.Done:
+		return fmt.Sprintf("⏳ Recategorizing: %d/%d thought(s) checked\n%s", job.Checked, job.Total, counts)
+	case job.Interrupted:
+		return fmt.Sprintf("⏸️ Recategorizing stopped at %d/%d thought(s) when the bot restarted. Run it again to finish.\n%s", job.Checked, job.Total, counts)
+	case job.Total == 0:
+		return "✅ There are no uncategorized thoughts."
+	default:
+		return fmt.Sprintf("✅ Recategorized %d thought(s)\n%s", job.Checked, counts)
+	}
+}
+
+func (h *CommandHandler) listRecentThoughts(ctx context.Context, channelID, usage string) error {
+	thoughts, err := h.thoughtRepo.GetByWorkspace(ctx, channelID, recentThoughtsLimit, 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch recent thoughts", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch thoughts")
+	}
+	if len(thoughts) == 0 {
+		return h.client.SendMessage(channelID, "No thoughts captured yet.")
+	}
+
+	var b strings.Builder
+	b.WriteString("*Recent thoughts*\n\n")
+	for i, t := range thoughts {
+		fmt.Fprintf(&b, "%d. *%s* · %s · _%s_\n%s\n\n", i+1, t.Category, strings.Join(t.TopicTags, ", "), formatAge(t.Timestamp), truncate(t.Content, 120))
+	}
+	b.WriteString(usage)
+
+	return h.client.SendMessage(channelID, b.String())
+}
+
+// recentThoughtByNumber resolves a number from the recent thoughts list. It
+// returns a nil thought after telling the user when the number is invalid.
+func (h *CommandHandler) recentThoughtByNumber(ctx context.Context, channelID, arg, usage string) (*models.Thought, int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || number < 1 || number > recentThoughtsLimit {
+		return nil, 0, h.client.SendMessage(channelID, usage)
+	}
+
+	thoughts, err := h.thoughtRepo.GetByWorkspace(ctx, channelID, recentThoughtsLimit, 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch recent thoughts", "error", err)
+		return nil, 0, h.client.SendMessage(channelID, "Failed to fetch thoughts")
+	}
+
+	if number > len(thoughts) {
+		return nil, 0, h.client.SendMessage(channelID, fmt.Sprintf("Thought %d not found. Use `@LinkedIn Ghostwriter recategorize` to see recent thoughts.", number))
+	}
+
+	return thoughts[number-1], number, nil
+}
@@ -0,0 +1,264 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/linkedin"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+const (
+	actionPostReply = "reply_post"
+	actionReplyDone = "reply_done"
+
+	// maxReplies is how many comments one `replies` drafts for, keeping the
+	// message within Slack's block limit.
+	maxReplies = 10
+
+	repliesUsage = "Usage: `@LinkedIn Ghostwriter replies [post #]`, with the number from `@LinkedIn Ghostwriter published`"
+)
+
+// HandleReplies drafts replies in the author's voice to the comments on a
+// published post not answered yet. args holds the post's number in the
+// `published` list, newest first.
+func (h *CommandHandler) HandleReplies(ctx context.Context, channelID, userID string, args []string) error {
+	if len(args) != 1 {
+		return h.client.SendMessage(channelID, repliesUsage)
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || number < 1 {
+		return h.client.SendMessage(channelID, repliesUsage)
+	}
+
+	posts, err := h.postRepo.GetPublishedBetween(ctx, time.Time{}, time.Time{}, 1, number-1)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch published post", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch published posts")
+	}
+	if len(posts) == 0 {
+		return h.client.SendMessage(channelID, fmt.Sprintf("There's no published post #%d. Use `@LinkedIn Ghostwriter published` to find it.", number))
+	}
+	post := posts[0]
+
+	if h.linkedinClient != nil && post.LinkedInURN != "" {
+		if _, err := linkedin.SyncComments(ctx, h.linkedinClient, h.commentRepo, post); err != nil {
+			slog.ErrorContext(ctx, "Failed to sync comments", "post_id", post.ID, "error", err)
+		}
+	}
+
+	comments, err := h.commentRepo.ListUnanswered(ctx, post.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list comments", "post_id", post.ID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch comments")
+	}
+	if len(comments) == 0 {
+		return h.client.SendMessage(channelID, fmt.Sprintf("No new comments on _%s_.", truncate(strings.Join(strings.Fields(post.Content), " "), 80)))
+	}
+	more := 0
+	if len(comments) > maxReplies {
+		more = len(comments) - maxReplies
+		comments = comments[:maxReplies]
+	}
+
+	var pending []*models.Comment
+	for _, comment := range comments {
+		if comment.ReplyDraft == "" {
+			pending = append(pending, comment)
+		}
+	}
+
+	if len(pending) > 0 {
+		ts, err := h.client.SendMessageAndGetTS(channelID, fmt.Sprintf("💬 Drafting replies to %d comment(s)...", len(pending)))
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to post progress message", "error", err)
+		}
+		finish := func(blocks []slack.Block) error {
+			if ts == "" {
+				return h.client.SendMessageWithBlocks(channelID, blocks)
+			}
+			return h.client.UpdateMessageBlocks(channelID, ts, blocks)
+		}
+
+		if err := h.draftReplies(ctx, userID, post, pending); err != nil {
+			slog.ErrorContext(ctx, "Failed to draft replies", "post_id", post.ID, "error", err)
+			return finish([]slack.Block{slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType,
+				llmErrorMessage(err, "Failed to draft replies. Please try again."), false, false), nil, nil)})
+		}
+		return finish(h.buildRepliesBlocks(ctx, post, comments, more))
+	}
+
+	return h.client.SendMessageWithBlocks(channelID, h.buildRepliesBlocks(ctx, post, comments, more))
+}
+
+// draftReplies writes and saves a reply to each of comments.
+func (h *CommandHandler) draftReplies(ctx context.Context, userID string, post *models.Post, comments []*models.Comment) error {
+	profile, err := h.styleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load style profile", "error", err)
+	}
+
+	texts := make([]string, len(comments))
+	for i, comment := range comments {
+		texts[i] = comment.Content
+	}
+
+	replies, err := h.contentGenerator.DraftReplies(ctx, post.Content, texts, agents.FormatStyleGuide(profile))
+	if err != nil {
+		return err
+	}
+
+	for i, comment := range comments {
+		if replies[i] == "" {
+			continue
+		}
+		comment.ReplyDraft = replies[i]
+		if err := h.commentRepo.SaveDraft(ctx, comment.ID, replies[i]); err != nil {
+			slog.ErrorContext(ctx, "Failed to save reply draft", "comment_id", comment.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// HandleReplyAction posts a drafted reply to LinkedIn, or marks the
+// comment answered when the reply was copied and posted by hand.
+func (h *CommandHandler) HandleReplyAction(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) error {
+	commentID := action.Value
+	userID := callback.User.ID
+
+	resolve := func(text string) error {
+		blocks := replaceDraftActions(callback.Message.Blocks.BlockSet, commentID, text)
+		return h.client.UpdateMessageBlocks(callback.Channel.ID, callback.Message.Timestamp, blocks)
+	}
+
+	comment, err := h.commentRepo.GetByID(ctx, commentID)
+	if errors.Is(err, database.ErrCommentNotFound) {
+		return resolve("This comment is gone")
+	}
+	if err != nil {
+		return err
+	}
+
+	claimed, err := h.commentRepo.MarkReplied(ctx, comment.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return resolve("Already answered")
+	}
+
+	if action.ActionID == actionReplyDone {
+		return resolve(fmt.Sprintf("✔️ Marked as answered by <@%s>", userID))
+	}
+
+	if err := h.postReply(ctx, comment); err != nil {
+		slog.ErrorContext(ctx, "Failed to post reply", "comment_id", comment.ID, "error", err)
+		if err := h.commentRepo.ClearReplied(ctx, comment.ID); err != nil {
+			slog.ErrorContext(ctx, "Failed to clear reply", "comment_id", comment.ID, "error", err)
+		}
+		return h.client.SendEphemeral(callback.Channel.ID, userID, "Failed to post the reply on LinkedIn. Copy it and reply there instead.")
+	}
+
+	slog.InfoContext(ctx, "Posted reply to comment", "comment_id", comment.ID, "user", userID)
+	return resolve(fmt.Sprintf("✅ Replied on LinkedIn by <@%s>", userID))
+}
+
+func (h *CommandHandler) postReply(ctx context.Context, comment *models.Comment) error {
+	if h.linkedinClient == nil {
+		return fmt.Errorf("LinkedIn is not connected")
+	}
+	if comment.ReplyDraft == "" {
+		return fmt.Errorf("no reply drafted for comment %s", comment.ID)
+	}
+
+	post, err := h.postRepo.GetByID(ctx, comment.PostID)
+	if err != nil {
+		return err
+	}
+
+	return h.linkedinClient.ReplyToComment(ctx, post.LinkedInURN, comment.CommentURN, comment.ReplyDraft)
+}
+
+// buildRepliesBlocks shows each comment with its drafted reply in a code
+// block, which is one click to select and copy, and buttons to post it or
+// mark it answered.
+func (h *CommandHandler) buildRepliesBlocks(ctx context.Context, post *models.Post, comments []*models.Comment, more int) []slack.Block {
+	names := h.commenterNames(ctx)
+	location := scheduleLocation()
+
+	header := fmt.Sprintf("💬 *Replies to comments on* _%s_", truncate(strings.Join(strings.Fields(post.Content), " "), 80))
+	if post.LinkedInURL != "" {
+		header += fmt.Sprintf(" · <%s|View on LinkedIn>", post.LinkedInURL)
+	}
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, header, false, false), nil, nil),
+	}
+
+	for _, comment := range comments {
+		author := names[comment.AuthorURN]
+		if author == "" {
+			author = "Someone"
+		}
+		text := fmt.Sprintf("*%s* · %s\n%s", author, comment.CommentedAt.In(location).Format("Jan 2 15:04"), quote(truncate(comment.Content, 1200)))
+		if comment.ReplyDraft != "" {
+			text += "\n```" + truncate(comment.ReplyDraft, 1500) + "```"
+		} else {
+			text += "\n_No reply was drafted. Run the command again to retry._"
+		}
+
+		blocks = append(blocks,
+			slack.NewDividerBlock(),
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+		)
+
+		var buttons []slack.BlockElement
+		if h.linkedinClient != nil && comment.ReplyDraft != "" {
+			postButton := slack.NewButtonBlockElement(actionPostReply, comment.ID,
+				slack.NewTextBlockObject(slack.PlainTextType, "Post reply", false, false))
+			postButton.Style = slack.StylePrimary
+			buttons = append(buttons, postButton)
+		}
+		buttons = append(buttons, slack.NewButtonBlockElement(actionReplyDone, comment.ID,
+			slack.NewTextBlockObject(slack.PlainTextType, "Mark answered", false, false)))
+		blocks = append(blocks, slack.NewActionBlock(draftActionsBlockID(comment.ID), buttons...))
+	}
+
+	footer := "Copy a reply from its box and post it on LinkedIn, then mark it answered."
+	if h.linkedinClient != nil {
+		footer = "Post a reply as it is, or copy it from its box to edit it on LinkedIn and then mark it answered."
+	}
+	if more > 0 {
+		footer += fmt.Sprintf(" %d more comment(s) are waiting: run the command again once these are answered.", more)
+	}
+	blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, footer, false, false)))
+
+	return blocks
+}
+
+// commenterNames maps the LinkedIn URNs of contacts to their names, so
+// comments from people in the address book say who left them.
+func (h *CommandHandler) commenterNames(ctx context.Context) map[string]string {
+	names := make(map[string]string)
+
+	contacts, err := h.contactRepo.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list contacts", "error", err)
+		return names
+	}
+	for _, contact := range contacts {
+		if contact.LinkedInURN != "" {
+			names[contact.LinkedInURN] = contact.Name
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,288 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+)
+
+const usersUsage = "Usage: `@LinkedIn Ghostwriter users`, `@LinkedIn Ghostwriter users add @user [owner|editor|viewer]` or `@LinkedIn Ghostwriter users remove @user`"
+
+// Roles decides who may run which commands and actions. Until anyone has a
+// role everyone may do everything, so a new install works out of the box;
+// after that, users without a role are viewers. The reviewer may always
+// approve and reject drafts.
+type Roles struct {
+	client     *Client
+	repo       userStore
+	reviewerID string
+}
+
+// userStore is the part of database.UserRepository roles are kept in.
+type userStore interface {
+	List(ctx context.Context) ([]*models.User, error)
+	GetRole(ctx context.Context, userID string) (string, error)
+	Any(ctx context.Context) (bool, error)
+	Save(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, userID string) error
+}
+
+func NewRoles(client *Client, repo *database.UserRepository, reviewerID string) *Roles {
+	return &Roles{
+		client:     client,
+		repo:       repo,
+		reviewerID: reviewerID,
+	}
+}
+
+// Allows reports whether userID may do something that needs the role need.
+func (r *Roles) Allows(ctx context.Context, userID, need string) (bool, error) {
+	if need == models.RoleViewer {
+		return true, nil
+	}
+	if r.reviewerID != "" && userID == r.reviewerID && need == models.RoleEditor {
+		return true, nil
+	}
+
+	role, err := r.repo.GetRole(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if role != "" {
+		return models.RoleAllows(role, need), nil
+	}
+
+	restricted, err := r.repo.Any(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !restricted, nil
+}
+
+// authorize reports whether userID may do what, and if not tells them so
+// privately in channelID.
+func (r *Roles) authorize(ctx context.Context, channelID, userID, need, what string) bool {
+	allowed, err := r.Allows(ctx, userID, need)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to check user role", "user", userID, "error", err)
+		r.client.SendEphemeral(channelID, userID, "Failed to check your permissions. Please try again.")
+		return false
+	}
+	if allowed {
+		return true
+	}
+
+	slog.InfoContext(ctx, "Denied action", "user", userID, "action", what, "needs", need)
+	r.client.SendEphemeral(channelID, userID, fmt.Sprintf("🔒 You need the *%s* role to %s. Ask an owner to run `@LinkedIn Ghostwriter users add @you %s`.", need, what, need))
+	return false
+}
+
+// commandRole returns the role command needs with args, both lowercased.
+// Commands that only show something are open to viewers; writing and
+// approving drafts needs an editor, and publishing or changing how the bot
+// works needs an owner.
+func commandRole(command string, args []string) string {
+	switch command {
+	case "generate", "brainstorm", "develop", "revise", "learn-style", "import", "recap",
+		"recategorize", "retag", "retry", "sync", "restore", "undo", "replies", "translate":
+		return models.RoleEditor
+
+	case "schedule", "reschedule", "unschedule", "connect", "experiment", "pause", "resume":
+		return models.RoleOwner
+
+	case "history":
+		if len(args) > 1 && args[1] == "restore" {
+			return models.RoleEditor
+		}
+
+	case "contacts":
+		if len(args) > 0 {
+			return models.RoleEditor
+		}
+
+	case "linear":
+		if len(args) > 0 && args[0] == "sync" {
+			return models.RoleEditor
+		}
+		if len(args) > 1 && args[0] == "filter" {
+			return models.RoleOwner
+		}
+
+	case "notion":
+		return models.RoleEditor
+
+	case "language", "emoji":
+		if len(args) > 0 {
+			return models.RoleEditor
+		}
+
+	case "workspace", "model", "categories", "users", "variations", "blackout":
+		if len(args) > 0 {
+			return models.RoleOwner
+		}
+
+	case "prompt":
+		if len(args) > 0 && args[0] != "show" {
+			return models.RoleOwner
+		}
+
+	case "templates":
+		if len(args) > 0 && args[0] != "show" && args[0] != "list" {
+			return models.RoleOwner
+		}
+	}
+
+	return models.RoleViewer
+}
+
+// actionRole returns the role a button or menu needs.
+func actionRole(actionID string, action *slack.BlockAction) string {
+	switch actionID {
+	case actionCheckInCategory, actionCheckInSkip, actionPublishedPage:
+		return models.RoleViewer
+
+	case actionPublishDuplicate, actionCancelDuplicate, actionPostReply:
+		return models.RoleOwner
+
+	case actionScheduleMenu:
+		if menuAction, _, _, err := parseScheduleMenuValue(action.SelectedOption.Value); err == nil && menuAction == scheduleMenuPreview {
+			return models.RoleViewer
+		}
+		return models.RoleOwner
+	}
+
+	return models.RoleEditor
+}
+
+// HandleUsers lists who has which role, or gives or takes away one.
+func (h *CommandHandler) HandleUsers(ctx context.Context, channelID, userID string, args []string) error {
+	if len(args) == 0 {
+		return h.listUsers(ctx, channelID)
+	}
+
+	if !h.roles.authorize(ctx, channelID, userID, models.RoleOwner, "manage roles") {
+		return nil
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		if len(args) != 3 {
+			return h.client.SendMessage(channelID, usersUsage)
+		}
+		target, role := parseUserMention(args[1]), strings.ToLower(args[2])
+		if target == "" || !models.ValidRole(role) {
+			return h.client.SendMessage(channelID, usersUsage)
+		}
+		if role != models.RoleOwner {
+			restricted, err := h.roles.repo.Any(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to check users", "error", err)
+				return h.client.SendMessage(channelID, "Failed to list users")
+			}
+			if !restricted {
+				return h.client.SendMessage(channelID, "Add an owner first, such as `@LinkedIn Ghostwriter users add @you owner`, so someone can still manage roles.")
+			}
+			if last, err := h.isLastOwner(ctx, target); err != nil || last {
+				return h.lastOwnerMessage(ctx, channelID, err)
+			}
+		}
+
+		if err := h.roles.repo.Save(ctx, models.NewUser(target, role, userID)); err != nil {
+			slog.ErrorContext(ctx, "Failed to save user", "error", err)
+			return h.client.SendMessage(channelID, "Failed to save the role")
+		}
+
+		slog.InfoContext(ctx, "User role set", "user", target, "role", role, "by", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("<@%s>'s role is now *%s*.", target, role))
+
+	case "remove":
+		if len(args) != 2 {
+			return h.client.SendMessage(channelID, usersUsage)
+		}
+		target := parseUserMention(args[1])
+		if target == "" {
+			return h.client.SendMessage(channelID, usersUsage)
+		}
+		if last, err := h.isLastOwner(ctx, target); err != nil || last {
+			return h.lastOwnerMessage(ctx, channelID, err)
+		}
+
+		err := h.roles.repo.Delete(ctx, target)
+		if errors.Is(err, database.ErrUserNotFound) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("<@%s> has no role.", target))
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to delete user", "error", err)
+			return h.client.SendMessage(channelID, "Failed to remove the role")
+		}
+
+		slog.InfoContext(ctx, "User role removed", "user", target, "by", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("<@%s> no longer has a role and can only view.", target))
+	}
+
+	return h.client.SendMessage(channelID, usersUsage)
+}
+
+func (h *CommandHandler) listUsers(ctx context.Context, channelID string) error {
+	users, err := h.roles.repo.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list users", "error", err)
+		return h.client.SendMessage(channelID, "Failed to list users")
+	}
+	if len(users) == 0 {
+		return h.client.SendMessage(channelID, "Nobody has a role yet, so everyone can do everything. Add an owner to restrict who can approve and schedule posts.\n"+usersUsage)
+	}
+
+	message := "*Users*\n"
+	for _, user := range users {
+		message += fmt.Sprintf("• <@%s> · %s\n", user.UserID, user.Role)
+	}
+	message += "\nEveryone else can only view.\n" + usersUsage
+
+	return h.client.SendMessage(channelID, message)
+}
+
+// isLastOwner reports whether userID is the only owner, who can't be
+// demoted or removed without locking everyone out.
+func (h *CommandHandler) isLastOwner(ctx context.Context, userID string) (bool, error) {
+	users, err := h.roles.repo.List(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	owners, isOwner := 0, false
+	for _, user := range users {
+		if user.Role == models.RoleOwner {
+			owners++
+			isOwner = isOwner || user.UserID == userID
+		}
+	}
+	return isOwner && owners == 1, nil
+}
+
+func (h *CommandHandler) lastOwnerMessage(ctx context.Context, channelID string, err error) error {
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list users", "error", err)
+		return h.client.SendMessage(channelID, "Failed to list users")
+	}
+	return h.client.SendMessage(channelID, "That's the last owner. Make someone else an owner first.")
+}
+
+// parseUserMention returns the user ID in a mention such as <@U123> or
+// <@U123|name>, or a bare user ID.
+func parseUserMention(text string) string {
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "<@"), ">")
+	text, _, _ = strings.Cut(text, "|")
+	text = strings.ToUpper(text)
+
+	if len(text) < 2 || (text[0] != 'U' && text[0] != 'W') {
+		return ""
+	}
+	return text
+}
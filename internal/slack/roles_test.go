@@ -0,0 +1,125 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+type fakeUsers struct {
+	roles map[string]string
+	saved []*models.User
+}
+
+func (f *fakeUsers) List(ctx context.Context) ([]*models.User, error) {
+	var users []*models.User
+	for userID, role := range f.roles {
+		users = append(users, models.NewUser(userID, role, ""))
+	}
+	return users, nil
+}
+
+func (f *fakeUsers) GetRole(ctx context.Context, userID string) (string, error) {
+	return f.roles[userID], nil
+}
+
+func (f *fakeUsers) Any(ctx context.Context) (bool, error) {
+	return len(f.roles) > 0, nil
+}
+
+func (f *fakeUsers) Save(ctx context.Context, user *models.User) error {
+	f.saved = append(f.saved, user)
+	return nil
+}
+
+func (f *fakeUsers) Delete(ctx context.Context, userID string) error {
+	delete(f.roles, userID)
+	return nil
+}
+
+// fakeSlack records the Slack API methods called through the client it
+// returns.
+func fakeSlack(t *testing.T) (*Client, func() []string) {
+	var mu sync.Mutex
+	var calls []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, strings.TrimPrefix(r.URL.Path, "/"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := &Client{api: slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"))}
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+func TestViewerCantGrantOwnerWithMisspelledCommand(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeUsers{roles: map[string]string{"UOWNER": models.RoleOwner}}
+	client, calls := fakeSlack(t)
+	roles := &Roles{client: client, repo: users}
+	h := &MessageHandler{client: client, roles: roles, commandHandler: &CommandHandler{client: client, roles: roles}}
+
+	command, rest := cutWord("USERSx add <@UVIEWER> owner")
+	command = strings.ToLower(command)
+	if need := commandRole(command, strings.Fields(strings.ToLower(rest))); need != models.RoleViewer {
+		t.Fatalf("commandRole(%q) = %q, want viewer", command, need)
+	}
+
+	event := &slackevents.AppMentionEvent{Channel: "C1", User: "UVIEWER"}
+	handled, err := h.runCommand(ctx, event, command, rest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handled {
+		t.Fatalf("%q was run as a command", command)
+	}
+	if len(users.saved) > 0 {
+		t.Fatalf("role saved: %+v", users.saved[0])
+	}
+
+	// Reaching the handler some other way still needs an owner.
+	if err := h.commandHandler.HandleUsers(ctx, "C1", "UVIEWER", []string{"add", "<@UVIEWER>", "owner"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(users.saved) > 0 {
+		t.Fatalf("role saved: %+v", users.saved[0])
+	}
+	if got := calls(); len(got) != 1 || got[0] != "chat.postEphemeral" {
+		t.Fatalf("Slack calls = %v, want one chat.postEphemeral", got)
+	}
+}
+
+func TestOwnerCanGrantRoles(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeUsers{roles: map[string]string{"UOWNER": models.RoleOwner}}
+	client, _ := fakeSlack(t)
+	roles := &Roles{client: client, repo: users}
+	h := &MessageHandler{client: client, roles: roles, commandHandler: &CommandHandler{client: client, roles: roles}}
+
+	event := &slackevents.AppMentionEvent{Channel: "C1", User: "UOWNER"}
+	handled, err := h.runCommand(ctx, event, "users", "add <@UEDITOR> editor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !handled {
+		t.Fatal("users wasn't run as a command")
+	}
+	if len(users.saved) != 1 || users.saved[0].UserID != "UEDITOR" || users.saved[0].Role != models.RoleEditor {
+		t.Fatalf("saved = %+v, want UEDITOR as editor", users.saved)
+	}
+}
@@ -3,135 +3,276 @@ package slack
 import (
 	"context"
 	"encoding/json"
-	"io"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/logging"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/webhook"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Server struct {
 	client          *Client
 	messageHandler  *MessageHandler
 	approvalHandler *ApprovalHandler
+	commandHandler  *CommandHandler
+	dispatcher      *Dispatcher
 	signingSecret   string
-	processedEvents map[string]bool  // Add this for deduplication
+	mux             *http.ServeMux
+	httpServer      *http.Server
 }
 
-func NewServer(client *Client, messageHandler *MessageHandler, approvalHandler *ApprovalHandler, signingSecret string) *Server {
-	return &Server{
+func NewServer(client *Client, messageHandler *MessageHandler, approvalHandler *ApprovalHandler, commandHandler *CommandHandler, dispatcher *Dispatcher, signingSecret, port string) *Server {
+	s := &Server{
 		client:          client,
 		messageHandler:  messageHandler,
 		approvalHandler: approvalHandler,
+		commandHandler:  commandHandler,
+		dispatcher:      dispatcher,
 		signingSecret:   signingSecret,
-		processedEvents: make(map[string]bool),
+		mux:             http.NewServeMux(),
 	}
-}
 
-func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Printf("Error reading body: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
+	s.mux.HandleFunc("/health", s.healthCheck)
 
-	sv, err := slack.NewSecretsVerifier(r.Header, s.signingSecret)
-	if err != nil {
-		log.Printf("Error creating secrets verifier: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		return
+	// Built up front rather than in Start, so Shutdown can run alongside it.
+	s.httpServer = &http.Server{
+		Addr:              ":" + port,
+		Handler:           otelhttp.NewHandler(s.mux, "http", otelhttp.WithSpanNameFormatter(spanName)),
+		ReadHeaderTimeout: 10 * time.Second,
 	}
 
-	if _, err := sv.Write(body); err != nil {
-		log.Printf("Error writing to verifier: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	return s
+}
+
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) HandleFunc(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Webhooks returns the endpoints Slack delivers events and interactions
+// to, for a webhook.Router. Slack retries anything not acknowledged within
+// 3 seconds, so both only queue the work.
+func (s *Server) Webhooks() []webhook.Source {
+	verifier := webhook.Slack(s.signingSecret)
+
+	return []webhook.Source{
+		{
+			Name:       "slack",
+			Path:       "/slack/events",
+			Verifier:   verifier,
+			DeliveryID: slackEventID,
+			Handle:     s.handleEvent,
+		},
+		{
+			Name:     "slack",
+			Path:     "/slack/interactions",
+			Verifier: verifier,
+			Handle:   s.handleInteraction,
+		},
 	}
+}
 
-	if err := sv.Ensure(); err != nil {
-		log.Printf("Error verifying signature: %v", err)
-		w.WriteHeader(http.StatusUnauthorized)
-		return
+// slackEventID returns the event_id Slack keeps across retries of an event.
+func slackEventID(_ http.Header, body []byte) string {
+	var envelope struct {
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return ""
 	}
+	return envelope.EventID
+}
 
-	eventsAPIEvent, err := slackevents.ParseEvent(json.RawMessage(body), slackevents.OptionNoVerifyToken())
+// errQueueFull asks Slack to retry an event later.
+var errQueueFull = webhook.Fail(http.StatusServiceUnavailable, errors.New("event queue is full"))
+
+func (s *Server) handleEvent(ctx context.Context, delivery *webhook.Delivery) ([]byte, error) {
+	eventsAPIEvent, err := slackevents.ParseEvent(json.RawMessage(delivery.Body), slackevents.OptionNoVerifyToken())
 	if err != nil {
-		log.Printf("Error parsing event: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse event: %w", err))
 	}
 
 	if eventsAPIEvent.Type == slackevents.URLVerification {
-		var r *slackevents.ChallengeResponse
-		err := json.Unmarshal(body, &r)
-		if err != nil {
-			log.Printf("Error unmarshaling challenge: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+		var challenge slackevents.ChallengeResponse
+		if err := json.Unmarshal(delivery.Body, &challenge); err != nil {
+			return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse challenge: %w", err))
 		}
-		w.Header().Set("Content-Type", "text")
-		w.Write([]byte(r.Challenge))
-		return
+		return []byte(challenge.Challenge), nil
+	}
+
+	if eventsAPIEvent.Type != slackevents.CallbackEvent {
+		return nil, nil
 	}
 
-	if eventsAPIEvent.Type == slackevents.CallbackEvent {
-		var eventEnvelope struct {
-			EventID string `json:"event_id"`
+	innerEvent := eventsAPIEvent.InnerEvent
+	slog.DebugContext(ctx, "Slack event received", "event_type", innerEvent.Type, "retry", delivery.Header.Get("X-Slack-Retry-Num"))
+
+	// Changes made while handling the event are audited as the user's.
+	var run func(ctx context.Context) error
+	switch ev := innerEvent.Data.(type) {
+	case *slackevents.MessageEvent:
+		run = func(ctx context.Context) error {
+			return s.messageHandler.HandleMessage(database.WithActor(ctx, ev.User), ev)
 		}
-		var eventID string
-		if err := json.Unmarshal(body, &eventEnvelope); err == nil && eventEnvelope.EventID != "" {
-			eventID = eventEnvelope.EventID
-		} else {
-			eventID = eventsAPIEvent.TeamID + ":" + eventsAPIEvent.Type
+
+	case *slackevents.AppMentionEvent:
+		run = func(ctx context.Context) error {
+			return s.messageHandler.HandleAppMention(database.WithActor(ctx, ev.User), ev)
 		}
-		
-		if eventID != "" {
-			if s.processedEvents[eventID] {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-			s.processedEvents[eventID] = true
+
+	case *slackevents.ReactionAddedEvent:
+		run = func(ctx context.Context) error {
+			return s.approvalHandler.HandleReaction(database.WithActor(ctx, ev.User), ev)
 		}
 
-		innerEvent := eventsAPIEvent.InnerEvent
-		ctx := context.Background()
+	case *slackevents.ReactionRemovedEvent:
+		run = func(ctx context.Context) error {
+			return s.approvalHandler.HandleReactionRemoved(database.WithActor(ctx, ev.User), ev)
+		}
 
-		switch ev := innerEvent.Data.(type) {
-		case *slackevents.MessageEvent:
-			if err := s.messageHandler.HandleMessage(ctx, ev); err != nil {
-				log.Printf("Error handling message: %v", err)
-			}
+	default:
+		slog.WarnContext(ctx, "Unsupported event type", "event_type", innerEvent.Type)
+	}
 
-		case *slackevents.AppMentionEvent:
-			if err := s.messageHandler.HandleAppMention(ctx, ev); err != nil {
-				log.Printf("Error handling mention: %v", err)
-			}
+	if run != nil && !s.dispatcher.Submit(ctx, innerEvent.Type+" event", run) {
+		return nil, errQueueFull
+	}
+
+	return nil, nil
+}
+
+func (s *Server) handleInteraction(ctx context.Context, delivery *webhook.Delivery) ([]byte, error) {
+	form, err := url.ParseQuery(string(delivery.Body))
+	if err != nil {
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse interaction form: %w", err))
+	}
+
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(form.Get("payload")), &callback); err != nil {
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse interaction payload: %w", err))
+	}
+
+	if callback.TriggerID != "" {
+		ctx = logging.WithRequestID(ctx, callback.TriggerID)
+	}
+	ctx = database.WithActor(ctx, callback.User.ID)
+	slog.DebugContext(ctx, "Slack interaction received", "interaction_type", callback.Type)
 
-		case *slackevents.ReactionAddedEvent:
-			if err := s.approvalHandler.HandleReaction(ctx, ev); err != nil {
-				log.Printf("Error handling reaction: %v", err)
+	var run func(ctx context.Context) error
+	switch callback.Type {
+	case slack.InteractionTypeBlockActions:
+		run = func(ctx context.Context) error {
+			for _, action := range callback.ActionCallback.BlockActions {
+				var err error
+				actionID := action.ActionID
+				if strings.HasPrefix(actionID, actionCheckInCategory) {
+					actionID = actionCheckInCategory
+				}
+				ctx := agents.WithCommand(ctx, actionID)
+				if !s.approvalHandler.AuthorizeAction(ctx, &callback, actionID, action) {
+					continue
+				}
+				switch actionID {
+				case actionCheckInCategory, actionCheckInSkip:
+					err = s.commandHandler.HandleCheckInAction(ctx, &callback, action)
+				case actionScheduleMenu:
+					err = s.commandHandler.HandleScheduleMenu(ctx, &callback, action)
+				case actionPublishedPage:
+					err = s.commandHandler.HandlePublishedPage(ctx, &callback, action)
+				case actionConfirmPublish, actionConfirmDelay, actionConfirmCancel:
+					err = s.commandHandler.HandleConfirmAction(ctx, &callback, action)
+				case actionPostReply, actionReplyDone:
+					err = s.commandHandler.HandleReplyAction(ctx, &callback, action)
+				case actionFixDraft, actionPublishDuplicate, actionRewriteDuplicate, actionCancelDuplicate:
+					// These can come back with a revised draft to share.
+					handle := s.commandHandler.HandleDuplicateAction
+					if action.ActionID == actionFixDraft {
+						handle = s.commandHandler.HandleFixDraft
+					}
+					var blocks []slack.Block
+					var postIDs []string
+					blocks, postIDs, err = handle(ctx, &callback, action)
+					if err == nil && len(postIDs) > 0 {
+						err = s.approvalHandler.ShareDrafts(ctx, callback.Channel.ID, blocks, postIDs)
+					}
+				default:
+					err = s.approvalHandler.HandleBlockAction(ctx, &callback, action)
+				}
+				if err != nil {
+					slog.ErrorContext(ctx, "Error handling block action", "action_id", action.ActionID, "error", err)
+				}
 			}
+			return nil
+		}
 
-		default:
-			log.Printf("Unsupported event type: %v", innerEvent.Type)
+	case slack.InteractionTypeViewSubmission:
+		switch callback.View.CallbackID {
+		case editDraftCallbackID:
+			run = func(ctx context.Context) error {
+				return s.approvalHandler.HandleEditSubmission(ctx, &callback)
+			}
+		case rescheduleCallbackID:
+			run = func(ctx context.Context) error {
+				return s.commandHandler.HandleRescheduleSubmission(ctx, &callback)
+			}
 		}
+
+	default:
+		slog.WarnContext(ctx, "Unsupported interaction type", "interaction_type", callback.Type)
 	}
 
-	w.WriteHeader(http.StatusOK)
+	if run != nil && !s.dispatcher.Submit(ctx, string(callback.Type)+" interaction", run) {
+		return nil, errQueueFull
+	}
+
+	return nil, nil
+}
+
+// spanName keeps span names low-cardinality by grouping the admin API,
+// whose paths contain record IDs.
+func spanName(_ string, r *http.Request) string {
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		return r.Method + " /api/v1"
+	}
+	return r.Method + " " + r.URL.Path
 }
 
-func (s *Server) Start(port string) error {
-	http.HandleFunc("/slack/events", s.handleEvents)
-	http.HandleFunc("/health", s.healthCheck)
-	
-	log.Printf("Slack server starting on port %s", port)
-	
-	return http.ListenAndServe(":"+port, nil)
+func (s *Server) Start() error {
+	slog.Info("Slack server starting", "addr", s.httpServer.Addr)
+
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown stops accepting new requests, waits for in-flight handlers and
+// drains queued events, or gives up when ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	slog.InfoContext(ctx, "Slack server shutting down")
+
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	return s.dispatcher.Shutdown(ctx)
 }
 
 func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
-}
\ No newline at end of file
+}
@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/sources"
+)
+
+// enrichThought summarizes the first file or link shared in a message and
+// stores the summary, with the link, as the thought's content. The author's
+// own words are kept above the summary. On any failure the thought is left
+// as the raw message text.
+func (h *MessageHandler) enrichThought(ctx context.Context, text string, files []SharedFile, thought *models.Thought) {
+	if h.summarizer == nil {
+		return
+	}
+
+	doc, err := h.fetchSource(ctx, text, files)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to fetch shared content, saving message text only", "error", err)
+		return
+	}
+	if doc == nil {
+		return
+	}
+
+	summary, err := h.summarizer.Summarize(ctx, text, doc.Title, doc.Text)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to summarize shared content, saving message text only", "url", doc.URL, "error", err)
+		return
+	}
+
+	title := doc.Title
+	if title == "" {
+		title = "shared link"
+	}
+
+	content := strings.TrimSpace(text)
+	if content != "" {
+		content += "\n\n"
+	}
+	if doc.URL != "" {
+		title += " (" + doc.URL + ")"
+	}
+	content += fmt.Sprintf("Summary of %s:\n%s", title, summary)
+
+	thought.Content = content
+	thought.SourceURL = doc.URL
+	thought.SourceTitle = doc.Title
+}
+
+// fetchSource returns the readable content of the first supported file
+// attached to the message, or else of its first link. It returns nil when
+// there is nothing to fetch.
+func (h *MessageHandler) fetchSource(ctx context.Context, text string, files []SharedFile) (*sources.Document, error) {
+	for _, file := range files {
+		if file.Size > sources.MaxDownloadBytes {
+			continue
+		}
+
+		data, err := file.Download(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", file.Name, err)
+		}
+
+		doc, err := sources.Extract(data, file.Mimetype, file.Name)
+		if err != nil {
+			slog.InfoContext(ctx, "Skipping unreadable file", "file", file.Name, "error", err)
+			continue
+		}
+
+		doc.URL = file.URL
+		doc.Title = file.Title
+		if doc.Title == "" {
+			doc.Title = file.Name
+		}
+		return doc, nil
+	}
+
+	links := sources.Links(text)
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	return h.fetcher.FetchURL(ctx, links[0])
+}
+
+func sourceLabel(thought *models.Thought) string {
+	if thought.SourceTitle == "" || thought.SourceURL == "" {
+		return thought.SourceTitle + thought.SourceURL
+	}
+	return fmt.Sprintf("<%s|%s>", thought.SourceURL, thought.SourceTitle)
+}
@@ -0,0 +1,126 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const templatesUsage = "Usage: `@LinkedIn Ghostwriter templates [show name | add name: description | edit name: description | remove name]`, with the skeleton on the lines after `add` or `edit`. Use one with `@LinkedIn Ghostwriter generate --template name [topic]`"
+
+// HandleTemplates lists the post templates, shows one, or adds, edits or
+// removes one. A template's skeleton goes on the lines after the command.
+func (h *CommandHandler) HandleTemplates(ctx context.Context, channelID, userID, args string) error {
+	firstLine, skeleton, _ := strings.Cut(strings.TrimSpace(args), "\n")
+	action, rest := cutWord(firstLine)
+	rawName, description, _ := strings.Cut(rest, ":")
+	name := models.NormalizeTemplateName(rawName)
+
+	action = strings.ToLower(action)
+	switch action {
+	case "", "list":
+		templates, err := h.templateRepo.List(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to list post templates", "error", err)
+			return h.client.SendMessage(channelID, "Failed to list post templates")
+		}
+
+		var b strings.Builder
+		b.WriteString("*Post templates*\n")
+		for _, template := range templates {
+			fmt.Fprintf(&b, "• `%s`", template.Name)
+			if template.Description != "" {
+				b.WriteString(": " + template.Description)
+			}
+			b.WriteString("\n")
+		}
+		if len(templates) == 0 {
+			b.WriteString("_None yet._\n")
+		}
+		b.WriteString("\n" + templatesUsage)
+		return h.client.SendMessage(channelID, b.String())
+
+	case "show":
+		template, err := h.templateRepo.Get(ctx, name)
+		if errors.Is(err, database.ErrTemplateNotFound) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` template.", name))
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to get post template", "template", name, "error", err)
+			return h.client.SendMessage(channelID, "Failed to get the post template")
+		}
+
+		message := fmt.Sprintf("*%s*", template.Name)
+		if template.Description != "" {
+			message += " · " + template.Description
+		}
+		return h.client.SendMessage(channelID, message+"\n```"+template.Skeleton+"```")
+
+	case "add", "edit":
+		skeleton = strings.TrimSpace(skeleton)
+		if name == "" || len(name) > models.MaxTemplateName || skeleton == "" {
+			return h.client.SendMessage(channelID, templatesUsage)
+		}
+
+		_, err := h.templateRepo.Get(ctx, name)
+		exists := err == nil
+		if err != nil && !errors.Is(err, database.ErrTemplateNotFound) {
+			slog.ErrorContext(ctx, "Failed to get post template", "template", name, "error", err)
+			return h.client.SendMessage(channelID, "Failed to save the post template")
+		}
+		if action == "add" && exists {
+			return h.client.SendMessage(channelID, fmt.Sprintf("`%s` is already a template. Use `edit` to change it.", name))
+		}
+		if action == "edit" && !exists {
+			return h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` template. Use `add` to create it.", name))
+		}
+
+		if _, err := h.templateRepo.Save(ctx, models.NewPostTemplate(name, description, skeleton, userID)); err != nil {
+			slog.ErrorContext(ctx, "Failed to save post template", "template", name, "error", err)
+			return h.client.SendMessage(channelID, "Failed to save the post template")
+		}
+
+		slog.InfoContext(ctx, "Post template saved", "template", name, "user", userID, "new", !exists)
+		verb := "Added"
+		if exists {
+			verb = "Updated"
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("%s the `%s` template. Use it with `@LinkedIn Ghostwriter generate --template %s`.", verb, name, name))
+
+	case "remove":
+		if name == "" {
+			return h.client.SendMessage(channelID, templatesUsage)
+		}
+
+		err := h.templateRepo.Delete(ctx, name)
+		if errors.Is(err, database.ErrTemplateNotFound) {
+			return h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` template.", name))
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to remove post template", "template", name, "error", err)
+			return h.client.SendMessage(channelID, "Failed to remove the post template")
+		}
+
+		slog.InfoContext(ctx, "Post template removed", "template", name, "user", userID)
+		return h.client.SendMessage(channelID, fmt.Sprintf("Removed the `%s` template.", name))
+	}
+
+	return h.client.SendMessage(channelID, templatesUsage)
+}
+
+// postTemplate looks up the template a `--template` flag names, telling
+// channelID when there's no such template.
+func (h *CommandHandler) postTemplate(ctx context.Context, channelID, name string) (*models.PostTemplate, error) {
+	name = models.NormalizeTemplateName(name)
+
+	template, err := h.templateRepo.Get(ctx, name)
+	if errors.Is(err, database.ErrTemplateNotFound) {
+		h.client.SendMessage(channelID, fmt.Sprintf("There's no `%s` template. Use `@LinkedIn Ghostwriter templates` to see them.", name))
+	}
+	return template, err
+}
@@ -0,0 +1,115 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const restoreUsage = "Usage: `@LinkedIn Ghostwriter restore [ID]` with an ID from `@LinkedIn Ghostwriter trash`"
+
+// shortIDLength is how much of an ID the trash shows and restore accepts.
+const shortIDLength = 8
+
+// HandleTrash lists the deleted thoughts and posts that can still be
+// restored.
+func (h *CommandHandler) HandleTrash(ctx context.Context, channelID string) error {
+	thoughts, err := h.thoughtRepo.ListDeleted(ctx, channelID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list deleted thoughts", "error", err)
+		return h.client.SendMessage(channelID, "Failed to load the trash")
+	}
+	posts, err := h.postRepo.ListDeleted(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list deleted posts", "error", err)
+		return h.client.SendMessage(channelID, "Failed to load the trash")
+	}
+
+	if len(thoughts) == 0 && len(posts) == 0 {
+		return h.client.SendMessage(channelID, "The trash is empty. 🗑️")
+	}
+
+	message := "*Trash* 🗑️\n"
+	if len(thoughts) > 0 {
+		message += fmt.Sprintf("\n*Thoughts* (%d)\n", len(thoughts))
+		for _, deleted := range thoughts {
+			message += trashLine(deleted.Thought.ID, deleted.DeletedAt, deleted.Thought.Category, deleted.Thought.Content)
+		}
+	}
+	if len(posts) > 0 {
+		message += fmt.Sprintf("\n*Posts* (%d)\n", len(posts))
+		for _, deleted := range posts {
+			message += trashLine(deleted.Post.ID, deleted.DeletedAt, deleted.Post.Status, deleted.Post.Content)
+		}
+	}
+	message += "\nUse `@LinkedIn Ghostwriter restore [ID]` to bring one back."
+
+	return h.client.SendMessage(channelID, message)
+}
+
+func trashLine(id string, deletedAt time.Time, label, content string) string {
+	return fmt.Sprintf("• `%s` %s · _%s_ · deleted %s\n", shortID(id), truncate(strings.Join(strings.Fields(content), " "), 80), label, deletedAt.Format("Jan 2"))
+}
+
+func shortID(id string) string {
+	if len(id) > shortIDLength {
+		return id[:shortIDLength]
+	}
+	return id
+}
+
+// HandleRestore takes the thought or post whose ID starts with args[0] out
+// of the trash.
+func (h *CommandHandler) HandleRestore(ctx context.Context, channelID string, args []string) error {
+	if len(args) != 1 {
+		return h.client.SendMessage(channelID, restoreUsage)
+	}
+	prefix := strings.ToLower(args[0])
+
+	thoughts, err := h.thoughtRepo.ListDeleted(ctx, channelID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list deleted thoughts", "error", err)
+		return h.client.SendMessage(channelID, "Failed to load the trash")
+	}
+	posts, err := h.postRepo.ListDeleted(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list deleted posts", "error", err)
+		return h.client.SendMessage(channelID, "Failed to load the trash")
+	}
+
+	var restore func() error
+	var restored string
+	matches := 0
+	for _, deleted := range thoughts {
+		if strings.HasPrefix(deleted.Thought.ID, prefix) {
+			id := deleted.Thought.ID
+			restore = func() error { return h.thoughtRepo.Restore(ctx, id) }
+			restored = fmt.Sprintf("♻️ Restored thought `%s` to *%s*.", shortID(id), deleted.Thought.Category)
+			matches++
+		}
+	}
+	for _, deleted := range posts {
+		if strings.HasPrefix(deleted.Post.ID, prefix) {
+			id := deleted.Post.ID
+			restore = func() error { return h.postRepo.Restore(ctx, id) }
+			restored = fmt.Sprintf("♻️ Restored post `%s` as *%s*.", shortID(id), deleted.Post.Status)
+			matches++
+		}
+	}
+
+	switch {
+	case matches == 0:
+		return h.client.SendMessage(channelID, fmt.Sprintf("Nothing in the trash has the ID `%s`. Use `@LinkedIn Ghostwriter trash` to see what can be restored.", args[0]))
+	case matches > 1:
+		return h.client.SendMessage(channelID, fmt.Sprintf("More than one item in the trash starts with `%s`. Use more of the ID.", args[0]))
+	}
+
+	if err := restore(); err != nil {
+		slog.ErrorContext(ctx, "Failed to restore from trash", "id", args[0], "error", err)
+		return h.client.SendMessage(channelID, "Failed to restore it")
+	}
+
+	return h.client.SendMessage(channelID, restored)
+}
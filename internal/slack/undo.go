@@ -0,0 +1,229 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/slack-go/slack/slackevents"
+)
+
+const (
+	// reactionUndoWindow is how long after approving or rejecting drafts
+	// with a reaction removing it takes the decision back.
+	reactionUndoWindow = 15 * time.Minute
+
+	// undoBatchWindow groups the status changes one reaction or click made,
+	// such as approving a variation and passing over the others, so `undo`
+	// reverts them together.
+	undoBatchWindow = 5 * time.Second
+)
+
+// reactionDecisions returns the decision a reaction made on each of count
+// drafts, or nil when it isn't a review reaction.
+func reactionDecisions(reaction string, count int) []string {
+	decisions := make([]string, count)
+
+	switch reaction {
+	case "white_check_mark", "heavy_check_mark", "✅", "calendar", "📅":
+		for i := range decisions {
+			decisions[i] = models.DecisionApproved
+		}
+		return decisions
+	case "x", "❌":
+		for i := range decisions {
+			decisions[i] = models.DecisionRejected
+		}
+		return decisions
+	}
+
+	index, ok := numberReactions[reaction]
+	if !ok || index >= count {
+		return nil
+	}
+	for i := range decisions {
+		decisions[i] = models.DecisionRejected
+	}
+	decisions[index] = models.DecisionApproved
+	return decisions
+}
+
+// HandleReactionRemoved takes back the decisions a review reaction made
+// when it is removed within reactionUndoWindow, putting the drafts back up
+// for review. Drafts that have moved on, such as to the schedule, stay.
+func (h *ApprovalHandler) HandleReactionRemoved(ctx context.Context, event *slackevents.ReactionRemovedEvent) error {
+	draftMessage, err := h.draftMessageRepo.GetByMessageTS(ctx, event.Item.Timestamp)
+	if err != nil {
+		return err
+	}
+	if draftMessage == nil {
+		return nil
+	}
+
+	decisions := reactionDecisions(event.Reaction, len(draftMessage.PostIDs))
+	if decisions == nil {
+		return nil
+	}
+
+	// Whoever couldn't decide was already told so when they reacted.
+	allowed, err := h.roles.Allows(ctx, event.User, models.RoleEditor)
+	if err != nil || !allowed {
+		return err
+	}
+
+	since := time.Now().Add(-reactionUndoWindow)
+	var undone, tooOld, movedOn int
+	for i, postID := range draftMessage.PostIDs {
+		_, err := h.withdraw(ctx, postID, event.User, decisions[i], since, "draft")
+		switch {
+		case err == nil:
+			undone++
+		case errors.Is(err, database.ErrNoDecision):
+		case errors.Is(err, database.ErrDecisionTooOld):
+			tooOld++
+		case errors.Is(err, database.ErrDecisionFinal):
+			movedOn++
+		default:
+			slog.ErrorContext(ctx, "Failed to undo decision", "post_id", postID, "error", err)
+		}
+	}
+
+	var message string
+	switch {
+	case undone > 0:
+		message = fmt.Sprintf("↩️ Undid <@%s>'s decision on %d draft(s). They're back up for review.", event.User, undone)
+		if movedOn > 0 {
+			message += fmt.Sprintf(" %d already moved on, so they stay as they are.", movedOn)
+		}
+	case movedOn > 0:
+		message = "These drafts have moved on since, so removing the reaction didn't change them. Use `@LinkedIn Ghostwriter unschedule` to take a post off the schedule."
+	case tooOld > 0:
+		message = fmt.Sprintf("Removing a reaction only undoes it for %d minutes. Use `@LinkedIn Ghostwriter undo` to revert your last change.", int(reactionUndoWindow.Minutes()))
+	default:
+		return nil
+	}
+
+	slog.InfoContext(ctx, "Undid decision by reaction", "user", event.User, "reaction", event.Reaction, "undone", undone)
+	return h.client.SendMessage(event.Item.Channel, message)
+}
+
+// HandleUndo reverts the last status change userID made on drafts: an
+// approval or rejection, or keeping or discarding a stale draft. Changes to
+// posts that have moved on since are left alone.
+func (h *ApprovalHandler) HandleUndo(ctx context.Context, channelID, userID string) error {
+	entries, err := h.auditRepo.GetLatestTransitions(ctx, userID, undoBatchWindow)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load last change", "user", userID, "error", err)
+		return h.client.SendMessage(channelID, "Failed to find your last change")
+	}
+	if len(entries) == 0 {
+		return h.client.SendMessage(channelID, "You haven't changed any post yet.")
+	}
+
+	var lines []string
+	undone := 0
+	for _, entry := range entries {
+		post, err := h.postRepo.GetByID(ctx, entry.PostID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load post", "post_id", entry.PostID, "error", err)
+			continue
+		}
+		preview := truncate(strings.Join(strings.Fields(post.Content), " "), 80)
+
+		if !undoable(entry.FromStatus, entry.ToStatus) {
+			lines = append(lines, fmt.Sprintf("• _%s_ went from *%s* to *%s*, which `undo` can't revert", preview, entry.FromStatus, entry.ToStatus))
+			continue
+		}
+		if post.Status != entry.ToStatus {
+			lines = append(lines, fmt.Sprintf("• _%s_ is *%s* now, so it stays", preview, post.Status))
+			continue
+		}
+
+		status, err := h.revert(ctx, post.ID, userID, entry.FromStatus, entry.ToStatus)
+		if errors.Is(err, database.ErrDecisionFinal) {
+			lines = append(lines, fmt.Sprintf("• _%s_ has moved on, so it stays", preview))
+			continue
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to undo change", "post_id", post.ID, "error", err)
+			lines = append(lines, fmt.Sprintf("• _%s_ couldn't be reverted", preview))
+			continue
+		}
+
+		undone++
+		lines = append(lines, fmt.Sprintf("• _%s_ is back to *%s* (was %s)", preview, status, entry.ToStatus))
+	}
+
+	slog.InfoContext(ctx, "Undid last change", "user", userID, "undone", undone)
+
+	header := "↩️ *Undid your last change*"
+	if undone == 0 {
+		header = "*Nothing to undo*"
+	}
+	message := header + "\n" + strings.Join(lines, "\n")
+	if undone > 0 {
+		message += "\n\nUse `@LinkedIn Ghostwriter drafts` to see them."
+	}
+	return h.client.SendMessage(channelID, message)
+}
+
+// undoable reports whether `undo` can revert a change of status from one
+// to another: a review decision on a draft, or keeping a stale one.
+func undoable(from, to string) bool {
+	switch to {
+	case models.DecisionApproved, models.DecisionRejected, models.StatusInReview:
+		return from == "draft" || from == "stale" || from == models.StatusInReview
+	case "draft":
+		return from == "stale"
+	}
+	return false
+}
+
+// revert moves a post userID moved from one status to another back,
+// withdrawing their decision when the change was one.
+func (h *ApprovalHandler) revert(ctx context.Context, postID, userID, from, to string) (string, error) {
+	if to != "draft" {
+		decision := models.DecisionApproved
+		if to == models.DecisionRejected {
+			decision = models.DecisionRejected
+		}
+
+		status, err := h.withdraw(ctx, postID, userID, decision, time.Time{}, from)
+		if !errors.Is(err, database.ErrNoDecision) {
+			return status, err
+		}
+	}
+
+	// Keeping or discarding a stale draft isn't a decision.
+	if err := h.postRepo.UpdateReview(ctx, postID, from, userID); err != nil {
+		return "", err
+	}
+	return from, nil
+}
+
+// withdraw takes back userID's decision on a post, made since since, and
+// frees the thoughts an approval had used.
+func (h *ApprovalHandler) withdraw(ctx context.Context, postID, userID, decision string, since time.Time, previous string) (string, error) {
+	role := models.RoleAuthor
+	if h.reviewerID != "" && userID == h.reviewerID {
+		role = models.RoleReviewer
+	}
+
+	status, err := h.approvalRepo.Withdraw(ctx, models.NewApproval(postID, userID, role, decision), since, h.reviewerID != "", previous)
+	if err != nil {
+		return "", err
+	}
+
+	if decision == models.DecisionApproved && status != models.DecisionApproved {
+		if err := h.thoughtRepo.ReleaseUsed(ctx, postID); err != nil {
+			slog.ErrorContext(ctx, "Failed to release thoughts of post", "post_id", postID, "error", err)
+		}
+	}
+
+	return status, nil
+}
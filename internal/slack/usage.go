@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	usageUsage = "Usage: `@LinkedIn Ghostwriter usage [month]`, with a month like `march`, `mar 2026` or `2026-03`"
+
+	// usageBreakdownLimit is how many commands, users or models each
+	// breakdown lists.
+	usageBreakdownLimit = 8
+)
+
+// HandleUsage shows the tokens and estimated cost of the model calls made
+// in the month named by args, or this month, by command, user and model.
+func (h *CommandHandler) HandleUsage(ctx context.Context, channelID string, args []string) error {
+	now := time.Now().UTC()
+	from := agents.MonthStart(now)
+	if len(args) > 0 {
+		month, err := parseMonth(strings.Join(args, " "), now)
+		if err != nil {
+			return h.client.SendMessage(channelID, usageUsage)
+		}
+		from = month
+	}
+	to := from.AddDate(0, 1, 0)
+
+	total, err := h.usageRepo.Total(ctx, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to total LLM usage", "error", err)
+		return h.client.SendMessage(channelID, "Failed to fetch usage")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*LLM usage in %s*\n", from.Format("January 2006"))
+	if total.Calls == 0 {
+		b.WriteString("No model calls were made then.")
+		return h.client.SendMessage(channelID, b.String())
+	}
+
+	fmt.Fprintf(&b, "%d calls · %s tokens in, %s out · *$%.2f*", total.Calls,
+		formatTokens(total.InputTokens), formatTokens(total.OutputTokens), total.Cost)
+	if budget := h.usageTracker.Budget(); budget > 0 {
+		fmt.Fprintf(&b, " of the $%.2f monthly budget (%.0f%%)", budget, 100*total.Cost/budget)
+		if total.Cost >= budget && from.Equal(agents.MonthStart(now)) {
+			b.WriteString("\n🛑 The budget is spent: generating is blocked until next month.")
+		}
+	}
+	b.WriteString("\n")
+
+	breakdowns := []struct {
+		title string
+		by    string
+		label func(string) string
+	}{
+		{"By command", database.UsageByCommand, func(key string) string {
+			if key == "" {
+				return "_background work_"
+			}
+			return "`" + key + "`"
+		}},
+		{"By user", database.UsageByUser, func(key string) string {
+			switch key {
+			case "":
+				return "_the bot itself_"
+			case "api", "cli":
+				return "_" + key + "_"
+			}
+			return "<@" + key + ">"
+		}},
+		{"By model", database.UsageByModel, func(key string) string {
+			return "`" + key + "`"
+		}},
+	}
+
+	for _, breakdown := range breakdowns {
+		totals, err := h.usageRepo.TotalBy(ctx, breakdown.by, from, to)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to break down LLM usage", "by", breakdown.by, "error", err)
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n*%s*\n", breakdown.title)
+		for i, t := range totals {
+			if i == usageBreakdownLimit {
+				fmt.Fprintf(&b, "_and %d more_\n", len(totals)-i)
+				break
+			}
+			fmt.Fprintf(&b, "• %s: %s\n", breakdown.label(t.Key), formatUsage(t))
+		}
+	}
+
+	b.WriteString("\n_Costs are estimated from list prices; embeddings and images aren't counted._")
+	return h.client.SendMessage(channelID, b.String())
+}
+
+func formatUsage(total *models.UsageTotal) string {
+	return fmt.Sprintf("%d calls, %s tokens, $%.2f", total.Calls, formatTokens(total.InputTokens+total.OutputTokens), total.Cost)
+}
+
+// formatTokens abbreviates a token count, as 950, 12.3k or 1.2M.
+func formatTokens(tokens int) string {
+	switch {
+	case tokens >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
+	case tokens >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(tokens)/1_000)
+	}
+	return fmt.Sprintf("%d", tokens)
+}
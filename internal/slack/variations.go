@@ -0,0 +1,122 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/agents"
+)
+
+// variationsSetting stores the number of variations set from Slack.
+const variationsSetting = "generation.variations"
+
+var variationsUsage = fmt.Sprintf("Usage: `@LinkedIn Ghostwriter variations [%d-%d|reset]`, or add `--variations [#]` to `generate`, `develop` or `recap`",
+	agents.MinVariations, agents.MaxVariations)
+
+type variationsKey struct{}
+
+// withVariations asks the generation run with ctx for count variations.
+func withVariations(ctx context.Context, count int) context.Context {
+	return context.WithValue(ctx, variationsKey{}, count)
+}
+
+// cutVariationsFlag takes a "--variations 2" or "--variations=2" flag out
+// of a command's arguments, returning the rest and the count, or 0 when the
+// flag isn't there.
+func cutVariationsFlag(args string) (string, int, error) {
+	rest, value, found, err := cutFlag(args, "variations")
+	if err != nil || !found {
+		return args, 0, err
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < agents.MinVariations || count > agents.MaxVariations {
+		return args, 0, fmt.Errorf("invalid number of variations %q", value)
+	}
+
+	return rest, count, nil
+}
+
+// cutFlag takes a "--name value" or "--name=value" flag out of a command's
+// arguments, returning the rest and the value, and whether it was there.
+func cutFlag(args, name string) (string, string, bool, error) {
+	fields := strings.Fields(args)
+	for i, field := range fields {
+		// Slack clients may turn "--" into an em dash.
+		flag := strings.TrimLeft(field, "-—")
+		if flag == field {
+			continue
+		}
+		flagName, value, hasValue := strings.Cut(flag, "=")
+		if strings.ToLower(flagName) != name {
+			continue
+		}
+
+		end := i + 1
+		if !hasValue {
+			if end >= len(fields) {
+				return args, "", true, fmt.Errorf("missing value for --%s", name)
+			}
+			value = fields[end]
+			end++
+		}
+
+		rest := append(append([]string{}, fields[:i]...), fields[end:]...)
+		return strings.Join(rest, " "), value, true, nil
+	}
+
+	return args, "", false, nil
+}
+
+// variationCount returns how many variations a generation should write:
+// the count ctx asks for, or the one set from Slack, or the configured one.
+func (h *CommandHandler) variationCount(ctx context.Context) int {
+	if count, ok := ctx.Value(variationsKey{}).(int); ok && count > 0 {
+		return count
+	}
+
+	value, err := h.settings.Get(ctx, variationsSetting)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load variations setting", "error", err)
+	}
+	if count, err := strconv.Atoi(value); err == nil && count >= agents.MinVariations && count <= agents.MaxVariations {
+		return count
+	}
+
+	return h.variations
+}
+
+// HandleVariations shows how many variations a generation writes, or sets
+// or resets it.
+func (h *CommandHandler) HandleVariations(ctx context.Context, channelID, userID string, args []string) error {
+	if len(args) == 0 {
+		return h.client.SendMessage(channelID, fmt.Sprintf("Each generation writes *%d* variation(s).\n%s", h.variationCount(ctx), variationsUsage))
+	}
+	if len(args) > 1 {
+		return h.client.SendMessage(channelID, variationsUsage)
+	}
+
+	if strings.EqualFold(args[0], "reset") {
+		if err := h.settings.Delete(ctx, variationsSetting); err != nil {
+			slog.ErrorContext(ctx, "Failed to reset variations", "error", err)
+			return h.client.SendMessage(channelID, "Failed to reset the number of variations")
+		}
+		return h.client.SendMessage(channelID, fmt.Sprintf("Generations are back to the configured *%d* variation(s).", h.variations))
+	}
+
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < agents.MinVariations || count > agents.MaxVariations {
+		return h.client.SendMessage(channelID, variationsUsage)
+	}
+
+	if err := h.settings.Set(ctx, variationsSetting, strconv.Itoa(count), userID); err != nil {
+		slog.ErrorContext(ctx, "Failed to save variations", "error", err)
+		return h.client.SendMessage(channelID, "Failed to save the number of variations")
+	}
+
+	slog.InfoContext(ctx, "Set number of variations", "count", count, "user", userID)
+	return h.client.SendMessage(channelID, fmt.Sprintf("<@%s> set generations to write *%d* variation(s).", userID, count))
+}
@@ -0,0 +1,155 @@
+package sources
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/net/html"
+)
+
+// maxTextChars bounds the text handed to the summarizer; the start of a
+// page or document carries most of its point.
+const maxTextChars = 20000
+
+// Document is readable text pulled from a link or an uploaded file.
+type Document struct {
+	URL   string
+	Title string
+	Text  string
+}
+
+// Extract pulls plain text out of HTML, PDF and text content. name is used
+// to guess the format when contentType is missing or generic.
+func Extract(data []byte, contentType, name string) (*Document, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	var doc *Document
+	var err error
+
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		doc, err = extractHTML(data)
+	case mediaType == "application/pdf" || strings.EqualFold(path.Ext(name), ".pdf"):
+		doc, err = extractPDF(data)
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || isTextExt(name):
+		if !utf8.Valid(data) {
+			return nil, fmt.Errorf("content is not valid UTF-8 text")
+		}
+		doc = &Document{Text: string(data)}
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Text = truncate(collapseWhitespace(doc.Text), maxTextChars)
+	if doc.Text == "" {
+		return nil, fmt.Errorf("no readable text found")
+	}
+
+	return doc, nil
+}
+
+func isTextExt(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".txt", ".md", ".markdown", ".csv", ".json":
+		return true
+	}
+	return false
+}
+
+// skippedElements never contain readable page text.
+var skippedElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "svg": true,
+	"nav": true, "header": true, "footer": true, "form": true,
+}
+
+func extractHTML(data []byte) (*Document, error) {
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	doc := &Document{}
+	var text strings.Builder
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if n.Data == "title" && doc.Title == "" && n.FirstChild != nil {
+				doc.Title = strings.TrimSpace(n.FirstChild.Data)
+				return
+			}
+			if skippedElements[n.Data] {
+				return
+			}
+		}
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+			text.WriteByte(' ')
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if n.Type == html.ElementNode && (n.Data == "p" || n.Data == "br" || n.Data == "li" || strings.HasPrefix(n.Data, "h")) {
+			text.WriteByte('\n')
+		}
+	}
+	walk(root)
+
+	doc.Text = text.String()
+	return doc, nil
+}
+
+func extractPDF(data []byte) (*Document, error) {
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pdf: %w", err)
+	}
+
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pdf text: %w", err)
+	}
+
+	text, err := io.ReadAll(io.LimitReader(plain, maxTextChars*4))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pdf text: %w", err)
+	}
+
+	return &Document{Text: string(text)}, nil
+}
+
+// collapseWhitespace squeezes runs of spaces and keeps at most one blank
+// line between paragraphs.
+func collapseWhitespace(text string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank && len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func truncate(text string, limit int) string {
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit])
+	}
+	return text
+}
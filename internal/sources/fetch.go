@@ -0,0 +1,119 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// MaxDownloadBytes caps how much of a page or file is read.
+const MaxDownloadBytes = 5 << 20
+
+var errPrivateAddress = errors.New("refusing to fetch a private network address")
+
+// Fetcher downloads links shared in Slack. It only connects to public
+// addresses, so a pasted link can't be used to probe the bot's network.
+type Fetcher struct {
+	httpClient *http.Client
+}
+
+func NewFetcher() *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &Fetcher{
+		httpClient: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: otelhttp.NewTransport(transport),
+		},
+	}
+}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast())
+}
+
+// FetchURL downloads rawURL and extracts its readable text.
+func (f *Fetcher) FetchURL(ctx context.Context, rawURL string) (*Document, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("unsupported link: %s", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "linkedin-ghostwriter/1.0 (+link summary)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/pdf,text/plain;q=0.9,*/*;q=0.5")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch link: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxDownloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read link: %w", err)
+	}
+
+	doc, err := Extract(data, resp.Header.Get("Content-Type"), parsed.Path)
+	if err != nil {
+		return nil, err
+	}
+	doc.URL = rawURL
+
+	return doc, nil
+}
+
+// slackLink matches the <url> and <url|label> forms Slack uses for links
+// in message text.
+var slackLink = regexp.MustCompile(`<(https?://[^|>\s]+)(?:\|[^>]*)?>`)
+
+// Links returns the web links in a Slack message, in order and without
+// duplicates.
+func Links(text string) []string {
+	var links []string
+	seen := map[string]bool{}
+
+	for _, match := range slackLink.FindAllStringSubmatch(text, -1) {
+		link := strings.ReplaceAll(match[1], "&amp;", "&")
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
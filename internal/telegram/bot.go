@@ -0,0 +1,208 @@
+// Package telegram captures thoughts sent to a Telegram bot, so they can be
+// jotted down on a phone without Slack. Messages go through the same
+// capture pipeline as Slack messages.
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/database"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/slack"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/webhook"
+)
+
+const thoughtSource = "telegram"
+
+// webhookAttempts is how many times an update is processed before it
+// becomes a dead letter.
+const webhookAttempts = 3
+
+const helpMessage = `Send me a thought and I'll categorize and save it, ready for your next LinkedIn post.
+
+- A link or a document (PDF, text) is summarized with your note
+- Reply to a thought you sent to add more context to it
+
+Generating and reviewing posts happens in Slack.`
+
+type update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *message `json:"message"`
+}
+
+type message struct {
+	MessageID int    `json:"message_id"`
+	From      *user  `json:"from"`
+	Chat      chat   `json:"chat"`
+	Text      string `json:"text"`
+	Caption   string `json:"caption"`
+	Document  *struct {
+		FileID   string `json:"file_id"`
+		FileName string `json:"file_name"`
+		MimeType string `json:"mime_type"`
+		FileSize int    `json:"file_size"`
+	} `json:"document"`
+	ReplyTo *message `json:"reply_to_message"`
+}
+
+type user struct {
+	ID int64 `json:"id"`
+}
+
+type chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// Bot captures the messages that allowed users send the bot in private
+// chats.
+type Bot struct {
+	client      *Client
+	handler     *slack.MessageHandler
+	thoughtRepo *database.ThoughtRepository
+	secret      string
+	allowed     map[string]bool
+}
+
+// NewBot accepts messages from the Telegram user IDs in allowed only, and
+// deliveries carrying secret.
+func NewBot(client *Client, handler *slack.MessageHandler, thoughtRepo *database.ThoughtRepository, secret string, allowed []string) *Bot {
+	allowedIDs := make(map[string]bool, len(allowed))
+	for _, id := range allowed {
+		allowedIDs[strings.TrimSpace(id)] = true
+	}
+
+	return &Bot{
+		client:      client,
+		handler:     handler,
+		thoughtRepo: thoughtRepo,
+		secret:      secret,
+		allowed:     allowedIDs,
+	}
+}
+
+// Register points the bot's webhook at url, the public address of
+// /telegram/webhook.
+func (b *Bot) Register(ctx context.Context, url string) error {
+	if err := b.client.SetWebhook(ctx, url, b.secret); err != nil {
+		return fmt.Errorf("failed to set telegram webhook: %w", err)
+	}
+	return nil
+}
+
+func (b *Bot) Webhook() webhook.Source {
+	return webhook.Source{
+		Name:     "telegram",
+		Path:     "/telegram/webhook",
+		Verifier: webhook.Telegram(b.secret),
+		DeliveryID: func(_ http.Header, body []byte) string {
+			var delivery update
+			if err := json.Unmarshal(body, &delivery); err != nil || delivery.UpdateID == 0 {
+				return ""
+			}
+			return strconv.FormatInt(delivery.UpdateID, 10)
+		},
+		Attempts: webhookAttempts,
+		Handle:   b.handle,
+	}
+}
+
+func (b *Bot) handle(ctx context.Context, delivery *webhook.Delivery) ([]byte, error) {
+	var payload update
+	if err := json.Unmarshal(delivery.Body, &payload); err != nil {
+		return nil, webhook.Fail(http.StatusBadRequest, fmt.Errorf("failed to parse telegram update: %w", err))
+	}
+
+	msg := payload.Message
+	if msg == nil || msg.From == nil || msg.Chat.Type != "private" {
+		return nil, nil
+	}
+
+	conversation := &chatConversation{client: b.client, chatID: msg.Chat.ID, messageID: msg.MessageID}
+
+	userID := strconv.FormatInt(msg.From.ID, 10)
+	if !b.allowed[userID] {
+		slog.WarnContext(ctx, "ignoring telegram message from unknown user", "user_id", userID)
+		return nil, conversation.Reply(ctx, fmt.Sprintf("This bot only captures thoughts for its owner. Your Telegram user ID is %s.", userID))
+	}
+
+	text := strings.TrimSpace(msg.Text)
+	if text == "" {
+		text = strings.TrimSpace(msg.Caption)
+	}
+
+	if strings.HasPrefix(text, "/") {
+		return nil, conversation.Reply(ctx, helpMessage)
+	}
+
+	if msg.ReplyTo != nil && text != "" {
+		if handled, err := b.addContext(ctx, conversation, msg.ReplyTo, text); handled || err != nil {
+			return nil, err
+		}
+	}
+
+	thought := models.NewThought(text, thoughtSource)
+	thought.ExternalID = externalID(msg.Chat.ID, msg.MessageID)
+
+	var files []slack.SharedFile
+	if doc := msg.Document; doc != nil && doc.FileSize <= maxDownload {
+		files = append(files, slack.SharedFile{
+			Name:     doc.FileName,
+			Mimetype: doc.MimeType,
+			Size:     doc.FileSize,
+			// The download link carries the bot token, so none is kept.
+			Download: func(ctx context.Context) ([]byte, error) {
+				return b.client.DownloadFile(ctx, doc.FileID)
+			},
+		})
+	}
+
+	if err := b.handler.Capture(ctx, conversation, thought, files); err != nil {
+		return nil, fmt.Errorf("failed to capture telegram message: %w", err)
+	}
+	return nil, nil
+}
+
+// addContext appends text to the thought captured from the message it
+// replies to. It returns false when that message wasn't captured.
+func (b *Bot) addContext(ctx context.Context, conversation *chatConversation, original *message, text string) (bool, error) {
+	thought, err := b.thoughtRepo.GetByExternalID(ctx, thoughtSource, externalID(conversation.chatID, original.MessageID))
+	if err != nil {
+		return true, err
+	}
+	if thought == nil {
+		return false, nil
+	}
+
+	if err := b.thoughtRepo.AppendContext(ctx, thought.ID, text); err != nil {
+		return true, fmt.Errorf("failed to append telegram context: %w", err)
+	}
+	return true, conversation.Reply(ctx, "Added this to the original thought.")
+}
+
+// externalID identifies a message across chats, since message IDs are
+// only unique within one.
+func externalID(chatID int64, messageID int) string {
+	return fmt.Sprintf("%d:%d", chatID, messageID)
+}
+
+// chatConversation answers a message in its chat, as a reply to it.
+type chatConversation struct {
+	client    *Client
+	chatID    int64
+	messageID int
+}
+
+// Reply sends Slack mrkdwn as plain text, with bold spelled in Unicode
+// letters since Telegram's own markup would trip over tags like
+// product_update.
+func (c *chatConversation) Reply(ctx context.Context, text string) error {
+	return c.client.SendMessage(ctx, c.chatID, format.ToUnicode(format.FromSlack(text)), c.messageID)
+}
@@ -0,0 +1,152 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// maxDownload is the largest file the Bot API lets a bot download.
+const maxDownload = 20 << 20
+
+// Client calls the Telegram Bot API as one bot.
+type Client struct {
+	token      string
+	httpClient *http.Client
+	baseURL    string
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+func NewClient(token string) *Client {
+	return &Client{
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		baseURL:    "https://api.telegram.org",
+	}
+}
+
+// SendMessage sends text to chatID, as a reply to the message replyTo when
+// it isn't 0.
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string, replyTo int) error {
+	payload := map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}
+	if replyTo != 0 {
+		payload["reply_parameters"] = map[string]any{"message_id": replyTo, "allow_sending_without_reply": true}
+	}
+	return c.do(ctx, "sendMessage", payload, nil)
+}
+
+// SetWebhook has Telegram deliver the bot's messages to url, with secret in
+// the X-Telegram-Bot-Api-Secret-Token header.
+func (c *Client) SetWebhook(ctx context.Context, url, secret string) error {
+	return c.do(ctx, "setWebhook", map[string]any{
+		"url":             url,
+		"secret_token":    secret,
+		"allowed_updates": []string{"message"},
+	}, nil)
+}
+
+// DownloadFile returns the content of a file sent to the bot.
+func (c *Client) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	var file struct {
+		FilePath string `json:"file_path"`
+	}
+	if err := c.do(ctx, "getFile", map[string]any{"file_id": fileID}, &file); err != nil {
+		return nil, err
+	}
+	if file.FilePath == "" {
+		return nil, fmt.Errorf("file %s can't be downloaded", fileID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/file/bot"+c.token+"/"+file.FilePath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", c.redact(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", c.redact(err))
+	}
+	if len(data) > maxDownload {
+		return nil, fmt.Errorf("file %s is too large", fileID)
+	}
+	return data, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, payload, result any) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/bot"+c.token+"/"+method, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Telegram API: %w", c.redact(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var apiResp apiResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return fmt.Errorf("Telegram API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("Telegram API error (status %d): %s", apiResp.ErrorCode, apiResp.Description)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(apiResp.Result, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// redact keeps the bot token, which is part of every API URL, out of
+// errors that quote the URL.
+func (c *Client) redact(err error) error {
+	if c.token == "" {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), c.token, "<token>"))
+}
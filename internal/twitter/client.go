@@ -0,0 +1,148 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/format"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Client posts to X on behalf of one account, authenticating with the
+// OAuth 1.0a keys of an X developer app and the account's access token.
+type Client struct {
+	credentials credentials
+	httpClient  *http.Client
+	baseURL     string
+}
+
+type tweetRequest struct {
+	Text  string      `json:"text"`
+	Reply *tweetReply `json:"reply,omitempty"`
+}
+
+type tweetReply struct {
+	InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+}
+
+type tweetResponse struct {
+	Data struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+type apiError struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+func NewClient(apiKey, apiSecret, accessToken, accessSecret string) *Client {
+	if apiKey == "" || apiSecret == "" || accessToken == "" || accessSecret == "" {
+		slog.Error("X_API_KEY, X_API_SECRET, X_ACCESS_TOKEN and X_ACCESS_TOKEN_SECRET are required")
+		os.Exit(1)
+	}
+
+	return &Client{
+		credentials: credentials{
+			consumerKey:    apiKey,
+			consumerSecret: apiSecret,
+			token:          accessToken,
+			tokenSecret:    accessSecret,
+		},
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		baseURL:    "https://api.x.com/2",
+	}
+}
+
+// CrossPost publishes post as a single tweet, or as a thread when it
+// doesn't fit in one, and returns the ID of the first tweet.
+func (c *Client) CrossPost(ctx context.Context, post *models.Post) (string, error) {
+	return c.PostThread(ctx, Thread(format.ToUnicode(post.Content)))
+}
+
+// PostThread posts tweets as a thread, each replying to the one before.
+// When a later tweet fails, the ID of the first is returned along with the
+// error, since the thread is already partly live.
+func (c *Client) PostThread(ctx context.Context, tweets []string) (string, error) {
+	if len(tweets) == 0 {
+		return "", fmt.Errorf("nothing to post")
+	}
+
+	var firstID, previousID string
+	for i, text := range tweets {
+		id, err := c.CreateTweet(ctx, text, previousID)
+		if err != nil {
+			if firstID == "" {
+				return "", err
+			}
+			return firstID, fmt.Errorf("thread stopped after %d of %d tweets: %w", i, len(tweets), err)
+		}
+
+		if firstID == "" {
+			firstID = id
+		}
+		previousID = id
+	}
+
+	return firstID, nil
+}
+
+// CreateTweet posts text, as a reply to replyTo when it is set.
+func (c *Client) CreateTweet(ctx context.Context, text, replyTo string) (string, error) {
+	request := tweetRequest{Text: text}
+	if replyTo != "" {
+		request.Reply = &tweetReply{InReplyToTweetID: replyTo}
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/tweets"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", c.credentials.authorization("POST", endpoint))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call X API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		var apiErr apiError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Detail != "" {
+			return "", fmt.Errorf("X API error (status %d, %s): %s", resp.StatusCode, apiErr.Title, apiErr.Detail)
+		}
+		return "", fmt.Errorf("X API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result tweetResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if result.Data.ID == "" {
+		return "", fmt.Errorf("X API returned no tweet ID")
+	}
+
+	return result.Data.ID, nil
+}
@@ -0,0 +1,76 @@
+package twitter
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type credentials struct {
+	consumerKey    string
+	consumerSecret string
+	token          string
+	tokenSecret    string
+}
+
+// authorization builds an OAuth 1.0a header for a request without query
+// parameters. JSON bodies are not part of the signature.
+func (c credentials) authorization(method, endpoint string) string {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+
+	params := map[string]string{
+		"oauth_consumer_key":     c.consumerKey,
+		"oauth_nonce":            hex.EncodeToString(nonce),
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_token":            c.token,
+		"oauth_version":          "1.0",
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, percentEncode(key)+"="+percentEncode(params[key]))
+	}
+
+	base := strings.ToUpper(method) + "&" + percentEncode(endpoint) + "&" + percentEncode(strings.Join(pairs, "&"))
+	mac := hmac.New(sha1.New, []byte(percentEncode(c.consumerSecret)+"&"+percentEncode(c.tokenSecret)))
+	mac.Write([]byte(base))
+	params["oauth_signature"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	header := make([]string, 0, len(params))
+	for _, key := range append(keys, "oauth_signature") {
+		header = append(header, fmt.Sprintf(`%s="%s"`, key, percentEncode(params[key])))
+	}
+
+	return "OAuth " + strings.Join(header, ", ")
+}
+
+// percentEncode escapes everything but the RFC 3986 unreserved characters,
+// as OAuth 1.0a requires.
+func percentEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
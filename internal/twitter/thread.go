@@ -0,0 +1,181 @@
+package twitter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxTweetLength is X's limit, counted with Length.
+const MaxTweetLength = 280
+
+// maxThreadTweets keeps a long post from turning into a wall of tweets; the
+// rest is cut off.
+const maxThreadTweets = 8
+
+// numberingReserve leaves room for a " (8/8)" suffix on each tweet.
+const numberingReserve = 6
+
+// Length counts text the way X does: most Latin, punctuation and general
+// symbol characters weigh one, everything else (CJK, emoji) two. Links are
+// counted at their full length, which only overestimates.
+func Length(text string) int {
+	n := 0
+	for _, r := range text {
+		switch {
+		case r <= 0x10FF, r >= 0x2000 && r <= 0x200D, r >= 0x2010 && r <= 0x201F, r >= 0x2032 && r <= 0x2037:
+			n++
+		default:
+			n += 2
+		}
+	}
+	return n
+}
+
+// Thread fits a LinkedIn post into tweets. A post that is too long loses
+// its hashtag lines first, then is split on line, sentence and word
+// boundaries into a numbered thread.
+func Thread(content string) []string {
+	text := strings.TrimSpace(content)
+	if Length(text) <= MaxTweetLength {
+		return []string{text}
+	}
+
+	text = dropHashtagLines(text)
+	if Length(text) <= MaxTweetLength {
+		return []string{text}
+	}
+
+	limit := MaxTweetLength - numberingReserve
+	tweets := pack(pieces(text, limit), limit)
+
+	if len(tweets) > maxThreadTweets {
+		tweets = tweets[:maxThreadTweets]
+		last := []rune(tweets[maxThreadTweets-1])
+		for Length(string(last)+"…") > limit {
+			last = last[:len(last)-1]
+		}
+		tweets[maxThreadTweets-1] = strings.TrimSpace(string(last)) + "…"
+	}
+
+	for i := range tweets {
+		tweets[i] += fmt.Sprintf(" (%d/%d)", i+1, len(tweets))
+	}
+
+	return tweets
+}
+
+// dropHashtagLines removes lines made up only of hashtags, which LinkedIn
+// posts usually end with.
+func dropHashtagLines(text string) string {
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		words := strings.Fields(line)
+		hashtags := len(words) > 0
+		for _, word := range words {
+			if !strings.HasPrefix(word, "#") {
+				hashtags = false
+				break
+			}
+		}
+		if !hashtags {
+			kept = append(kept, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// piece is a unit of text that is never split further, along with the
+// separator that joins it to the text before it.
+type piece struct {
+	text string
+	sep  string
+}
+
+// pieces breaks text into lines, then breaks lines longer than limit into
+// sentences, sentences into words and words into runes, so every piece
+// fits in a tweet.
+func pieces(text string, limit int) []piece {
+	var out []piece
+	sep := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if sep != "" {
+				sep = "\n\n"
+			}
+			continue
+		}
+
+		parts := splitLine(line, limit)
+		parts[0].sep = sep
+		out = append(out, parts...)
+		sep = "\n"
+	}
+	return out
+}
+
+func splitLine(line string, limit int) []piece {
+	if Length(line) <= limit {
+		return []piece{{text: line}}
+	}
+
+	var out []piece
+	for _, sentence := range sentences(line) {
+		if Length(sentence) <= limit {
+			out = append(out, piece{text: sentence, sep: " "})
+			continue
+		}
+
+		for _, word := range strings.Fields(sentence) {
+			for Length(word) > limit {
+				runes := []rune(word)
+				n := len(runes)
+				for Length(string(runes[:n])) > limit {
+					n--
+				}
+				out = append(out, piece{text: string(runes[:n]), sep: " "})
+				word = string(runes[n:])
+			}
+			out = append(out, piece{text: word, sep: " "})
+		}
+	}
+	return out
+}
+
+func sentences(line string) []string {
+	var out []string
+	var current []string
+	for _, word := range strings.Fields(line) {
+		current = append(current, word)
+		if strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "?") {
+			out = append(out, strings.Join(current, " "))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		out = append(out, strings.Join(current, " "))
+	}
+	return out
+}
+
+// pack greedily joins pieces into tweets of at most limit.
+func pack(pieces []piece, limit int) []string {
+	var tweets []string
+	current := ""
+	for _, p := range pieces {
+		if current == "" {
+			current = p.text
+			continue
+		}
+		if joined := current + p.sep + p.text; Length(joined) <= limit {
+			current = joined
+			continue
+		}
+		tweets = append(tweets, current)
+		current = p.text
+	}
+	if current != "" {
+		tweets = append(tweets, current)
+	}
+	return tweets
+}
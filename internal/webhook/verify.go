@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Verifier checks that a delivery was signed by its source.
+type Verifier interface {
+	Verify(header http.Header, body []byte) error
+}
+
+// VerifierFunc lets a function be used as a Verifier.
+type VerifierFunc func(header http.Header, body []byte) error
+
+func (f VerifierFunc) Verify(header http.Header, body []byte) error {
+	return f(header, body)
+}
+
+const (
+	// maxSlackAge bounds how old a Slack request timestamp may be, as Slack
+	// recommends.
+	maxSlackAge = 5 * time.Minute
+	// maxLinearAge bounds how old a signed Linear delivery may be, so a
+	// captured request cannot be replayed later.
+	maxLinearAge = time.Minute
+	// maxMailgunAge bounds how old a Mailgun signature may be. The
+	// signature doesn't cover the body, so together with tokens being
+	// remembered as delivery IDs this keeps it from being reused.
+	maxMailgunAge = 5 * time.Minute
+	// maxFormMemory is how much of a posted form is parsed in memory;
+	// larger files spill to temporary files.
+	maxFormMemory = 32 << 20
+)
+
+var (
+	errMissingSignature = errors.New("missing signature")
+	errInvalidSignature = errors.New("invalid signature")
+	errStale            = errors.New("stale delivery")
+)
+
+// Slack checks the X-Slack-Signature header: "v0=" and a hex HMAC-SHA256 of
+// "v0:<timestamp>:<body>" keyed with the signing secret.
+func Slack(secret string) Verifier {
+	return VerifierFunc(func(header http.Header, body []byte) error {
+		timestamp := header.Get("X-Slack-Request-Timestamp")
+		signature, ok := strings.CutPrefix(header.Get("X-Slack-Signature"), "v0=")
+		if !ok || timestamp == "" {
+			return errMissingSignature
+		}
+
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil || !fresh(time.Unix(seconds, 0), maxSlackAge) {
+			return errStale
+		}
+
+		return checkHMAC(secret, signature, []byte("v0:"+timestamp+":"), body)
+	})
+}
+
+// Linear checks the Linear-Signature header, a hex HMAC-SHA256 of the body
+// keyed with the webhook signing secret, and that the webhookTimestamp in
+// the payload is recent.
+func Linear(secret string) Verifier {
+	return VerifierFunc(func(header http.Header, body []byte) error {
+		if err := checkHMAC(secret, header.Get("Linear-Signature"), nil, body); err != nil {
+			return err
+		}
+
+		var payload struct {
+			WebhookTimestamp int64 `json:"webhookTimestamp"`
+		}
+		if err := json.Unmarshal(body, &payload); err == nil && payload.WebhookTimestamp != 0 {
+			if !fresh(time.UnixMilli(payload.WebhookTimestamp), maxLinearAge) {
+				return errStale
+			}
+		}
+
+		return nil
+	})
+}
+
+// GitHub checks the X-Hub-Signature-256 header: "sha256=" and a hex
+// HMAC-SHA256 of the body keyed with the webhook secret.
+func GitHub(secret string) Verifier {
+	return VerifierFunc(func(header http.Header, body []byte) error {
+		signature, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		if !ok {
+			return errMissingSignature
+		}
+		return checkHMAC(secret, signature, nil, body)
+	})
+}
+
+// Mailgun checks the signature fields Mailgun adds to the form it posts:
+// signature, a hex HMAC-SHA256 of timestamp and token keyed with the
+// webhook signing key, and that timestamp is recent. Use MailgunToken as
+// the source's DeliveryID so each token is only accepted once.
+func Mailgun(signingKey string) Verifier {
+	return VerifierFunc(func(header http.Header, body []byte) error {
+		fields, err := mailgunSignature(header, body)
+		if err != nil {
+			return err
+		}
+		if fields.timestamp == "" || fields.token == "" {
+			return errMissingSignature
+		}
+
+		if err := checkHMAC(signingKey, fields.signature, []byte(fields.timestamp), []byte(fields.token)); err != nil {
+			return err
+		}
+
+		seconds, err := strconv.ParseInt(fields.timestamp, 10, 64)
+		if err != nil || !fresh(time.Unix(seconds, 0), maxMailgunAge) {
+			return errStale
+		}
+		return nil
+	})
+}
+
+// MailgunToken returns the token of a delivery Mailgun signed, which is
+// new for every request.
+func MailgunToken(header http.Header, body []byte) string {
+	fields, err := mailgunSignature(header, body)
+	if err != nil {
+		return ""
+	}
+	return fields.token
+}
+
+type mailgunFields struct {
+	timestamp, token, signature string
+}
+
+func mailgunSignature(header http.Header, body []byte) (mailgunFields, error) {
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return mailgunFields{}, err
+	}
+	req.Header = header
+	if err := req.ParseMultipartForm(maxFormMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return mailgunFields{}, errMissingSignature
+	}
+	if req.MultipartForm != nil {
+		defer req.MultipartForm.RemoveAll()
+	}
+
+	return mailgunFields{
+		timestamp: req.PostFormValue("timestamp"),
+		token:     req.PostFormValue("token"),
+		signature: req.PostFormValue("signature"),
+	}, nil
+}
+
+// Telegram checks the X-Telegram-Bot-Api-Secret-Token header, which
+// Telegram sets to the secret_token the webhook was registered with.
+// Without a secret every delivery is rejected.
+func Telegram(secret string) Verifier {
+	return VerifierFunc(func(header http.Header, _ []byte) error {
+		token := header.Get("X-Telegram-Bot-Api-Secret-Token")
+		if secret == "" || token == "" {
+			return errMissingSignature
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return errInvalidSignature
+		}
+		return nil
+	})
+}
+
+// checkHMAC compares a hex signature with the HMAC-SHA256 of prefix and
+// body. Without a secret every delivery is rejected.
+func checkHMAC(secret, signature string, prefix, body []byte) error {
+	if secret == "" || signature == "" {
+		return errMissingSignature
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return errInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(prefix)
+	mac.Write(body)
+
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+func fresh(sentAt time.Time, maxAge time.Duration) bool {
+	age := time.Since(sentAt)
+	return age <= maxAge && age >= -maxAge
+}
@@ -0,0 +1,227 @@
+// Package webhook receives deliveries from integrations such as Slack and
+// Linear. A Router gives every source the same treatment: a cap on the
+// payload size, signature verification, dropping redeliveries, retrying a
+// failed delivery and keeping the ones that still fail as dead letters.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shubh-37/linkedin-ghostwriter/internal/dedup"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/logging"
+	"github.com/shubh-37/linkedin-ghostwriter/internal/models"
+)
+
+const (
+	// DefaultMaxBody caps payloads of sources that don't set their own
+	// limit.
+	DefaultMaxBody = 1 << 20
+
+	// retryBackoff is the wait before the first retry of a failed delivery,
+	// doubled before each one after. Sources expect an answer within a few
+	// seconds, so retries stay short.
+	retryBackoff = 250 * time.Millisecond
+)
+
+// Delivery is one verified request from a source.
+type Delivery struct {
+	Source     string
+	ID         string
+	Header     http.Header
+	Body       []byte
+	ReceivedAt time.Time
+}
+
+// Handler processes a delivery. Whatever it returns is written as the
+// response body. An error is retried unless it is a StatusError.
+type Handler func(ctx context.Context, delivery *Delivery) ([]byte, error)
+
+// Source describes the webhook of one integration.
+type Source struct {
+	Name     string
+	Path     string
+	Verifier Verifier
+	// MaxBody caps the payload; DefaultMaxBody when zero.
+	MaxBody int64
+	// DeliveryID names a delivery for logs and to drop redeliveries of it.
+	// It may be nil, or return "" for deliveries without an ID.
+	DeliveryID func(header http.Header, body []byte) string
+	// Attempts is how many times a failing delivery is handled before it
+	// becomes a dead letter; once when zero.
+	Attempts int
+	Handle   Handler
+}
+
+// StatusError answers a delivery with Status straight away. Below 500 the
+// delivery can never succeed and becomes a dead letter; from 500 up it is
+// forgotten so the source's own retry is processed, as when the bot is
+// overloaded.
+type StatusError struct {
+	Status int
+	Err    error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s: %v", e.Status, http.StatusText(e.Status), e.Err)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+func Fail(status int, err error) error {
+	return &StatusError{Status: status, Err: err}
+}
+
+// DeadLetters keeps deliveries that failed for good.
+type DeadLetters interface {
+	Save(ctx context.Context, letter *models.DeadLetter) error
+}
+
+// Mux is where the router registers its routes, such as an http.ServeMux.
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+type Router struct {
+	mux         Mux
+	processed   dedup.Store
+	deadLetters DeadLetters
+}
+
+// NewRouter registers webhooks on mux. Delivery IDs are remembered in
+// processed, and failed deliveries saved to deadLetters, which may be nil
+// to only log them.
+func NewRouter(mux Mux, processed dedup.Store, deadLetters DeadLetters) *Router {
+	return &Router{
+		mux:         mux,
+		processed:   processed,
+		deadLetters: deadLetters,
+	}
+}
+
+func (r *Router) Register(sources ...Source) {
+	for _, source := range sources {
+		if source.MaxBody <= 0 {
+			source.MaxBody = DefaultMaxBody
+		}
+		if source.Attempts < 1 {
+			source.Attempts = 1
+		}
+
+		r.mux.Handle("POST "+source.Path, r.handler(source))
+		slog.Info("webhook registered", "source", source.Name, "path", source.Path)
+	}
+}
+
+func (r *Router) handler(source Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, source.MaxBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				slog.WarnContext(req.Context(), "webhook: payload too large", "source", source.Name, "limit", source.MaxBody)
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			slog.ErrorContext(req.Context(), "webhook: failed to read body", "source", source.Name, "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := source.Verifier.Verify(req.Header, body); err != nil {
+			slog.WarnContext(req.Context(), "webhook: rejected delivery", "source", source.Name, "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		delivery := &Delivery{
+			Source:     source.Name,
+			Header:     req.Header,
+			Body:       body,
+			ReceivedAt: time.Now(),
+		}
+		if source.DeliveryID != nil {
+			delivery.ID = source.DeliveryID(req.Header, body)
+		}
+
+		requestID := delivery.ID
+		if requestID == "" {
+			requestID = logging.NewRequestID()
+		}
+		// Handlers run to completion even if the source hangs up.
+		ctx := logging.WithRequestID(context.WithoutCancel(req.Context()), requestID)
+
+		key := source.Name + ":" + delivery.ID
+		if delivery.ID != "" && r.processed.Seen(key) {
+			slog.DebugContext(ctx, "webhook: skipping redelivery", "source", source.Name, "delivery_id", delivery.ID)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		response, attempts, err := r.handle(ctx, source, delivery)
+		if err == nil {
+			if response != nil {
+				w.Header().Set("Content-Type", "text/plain")
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(response)
+			return
+		}
+
+		status := http.StatusInternalServerError
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			status = statusErr.Status
+		}
+
+		if status >= http.StatusInternalServerError && delivery.ID != "" {
+			r.processed.Forget(key)
+		}
+		if statusErr == nil || status < http.StatusInternalServerError {
+			r.deadLetter(ctx, delivery, attempts, err)
+		}
+
+		w.WriteHeader(status)
+	})
+}
+
+// handle runs the handler until it succeeds, returns a StatusError or runs
+// out of attempts.
+func (r *Router) handle(ctx context.Context, source Source, delivery *Delivery) ([]byte, int, error) {
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		response, err := source.Handle(ctx, delivery)
+		if err == nil {
+			return response, attempt, nil
+		}
+
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) || attempt >= source.Attempts {
+			return nil, attempt, err
+		}
+
+		slog.WarnContext(ctx, "webhook: retrying delivery", "source", source.Name, "attempt", attempt, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (r *Router) deadLetter(ctx context.Context, delivery *Delivery, attempts int, err error) {
+	slog.ErrorContext(ctx, "webhook: delivery failed", "source", delivery.Source, "delivery_id", delivery.ID, "attempts", attempts, "error", err)
+
+	if r.deadLetters == nil {
+		return
+	}
+
+	letter := models.NewDeadLetter(delivery.Source, delivery.ID, string(delivery.Body), err.Error(), attempts)
+	if err := r.deadLetters.Save(ctx, letter); err != nil {
+		slog.ErrorContext(ctx, "webhook: failed to save dead letter", "source", delivery.Source, "error", err)
+	}
+}